@@ -4,3 +4,16 @@ type Person struct {
 	Name  string
 	Email string
 }
+
+type Item struct {
+	ID          string
+	Name        string
+	Description string
+	Price       string
+}
+
+var items = []Item{
+	{ID: "1", Name: "Tea & biscuits", Description: "A box of <b>assorted</b> biscuits.", Price: "£4.50"},
+	{ID: "2", Name: "Coffee", Description: "Whole beans, roasted in \"small\" batches.", Price: "£9.00"},
+	{ID: "3", Name: "Hot chocolate", Description: "Made with real cocoa.", Price: "£6.25"},
+}
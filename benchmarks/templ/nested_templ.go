@@ -0,0 +1,419 @@
+// Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 52dcb47206010ea3662501ae294930e744dbd09ea749df78c06fb53723e9ba77
+
+package testhtml
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import "context"
+import "io"
+import "bytes"
+
+//line nested.templ:3
+func layout(title string) templ.Component {
+//line nested_templ.go:17
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.layout"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_1 := templ.GetChildren(ctx)
+		if var_1 == nil {
+			var_1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<!doctype html><html lang=\"en\"><head><meta charset=\"utf-8\"><title>")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line nested.templ:8
+			var_2, err = templ.EscapeAny(title)
+//line nested_templ.go:43
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/nested.templ", 8, 13)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</title><link rel=\"stylesheet\" href=\"/assets/styles.css\"></head><body>")
+			if err != nil {
+				return err
+			}
+		}
+//line nested.templ:12
+		err = header().Render(ctx, templBuffer)
+//line nested_templ.go:58
+		if err != nil {
+			return templ.WrapError(err, "benchmarks/templ/nested.templ", 12, 5)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("<main>")
+			if err != nil {
+				return err
+			}
+		}
+		err = var_1.Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</main>")
+			if err != nil {
+				return err
+			}
+		}
+//line nested.templ:16
+		err = footer().Render(ctx, templBuffer)
+//line nested_templ.go:80
+		if err != nil {
+			return templ.WrapError(err, "benchmarks/templ/nested.templ", 16, 5)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line nested.templ:21
+func header() templ.Component {
+//line nested_templ.go:99
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.header"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_3 := templ.GetChildren(ctx)
+		if var_3 == nil {
+			var_3 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<header><nav><ul>")
+			if err != nil {
+				return err
+			}
+		}
+//line nested.templ:25
+		err = navItem("/", "Home").Render(ctx, templBuffer)
+//line nested_templ.go:125
+		if err != nil {
+			return templ.WrapError(err, "benchmarks/templ/nested.templ", 25, 6)
+		}
+//line nested.templ:26
+		err = navItem("/products", "Products").Render(ctx, templBuffer)
+//line nested_templ.go:131
+		if err != nil {
+			return templ.WrapError(err, "benchmarks/templ/nested.templ", 26, 6)
+		}
+//line nested.templ:27
+		err = navItem("/about", "About us").Render(ctx, templBuffer)
+//line nested_templ.go:137
+		if err != nil {
+			return templ.WrapError(err, "benchmarks/templ/nested.templ", 27, 6)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</ul></nav></header>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line nested.templ:33
+func navItem(href string, name string) templ.Component {
+//line nested_templ.go:156
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.navItem"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_4 := templ.GetChildren(ctx)
+		if var_4 == nil {
+			var_4 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<li><a href=\"")
+			if err != nil {
+				return err
+			}
+//line nested.templ:34
+			var var_5 templ.SafeURL = templ.URL(href)
+//line nested_templ.go:181
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_5)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_6 string
+//line nested.templ:34
+			var_6, err = templ.EscapeAny(name)
+//line nested_templ.go:193
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/nested.templ", 34, 36)
+			}
+			_, err = templBuffer.WriteString(var_6)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a></li>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line nested.templ:37
+func footer() templ.Component {
+//line nested_templ.go:215
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.footer"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_7 := templ.GetChildren(ctx)
+		if var_7 == nil {
+			var_7 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<footer><p>Prices include tax. <a href=\"/terms\">Terms and conditions</a> apply.</p></footer>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line nested.templ:43
+func productRow(item Item) templ.Component {
+//line nested_templ.go:248
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.productRow"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_8 := templ.GetChildren(ctx)
+		if var_8 == nil {
+			var_8 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<tr><td><a href=\"")
+			if err != nil {
+				return err
+			}
+//line nested.templ:45
+			var var_9 templ.SafeURL = templ.URL("/products/" + item.ID)
+//line nested_templ.go:273
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_9)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_10 string
+//line nested.templ:45
+			var_10, err = templ.EscapeAny(item.Name)
+//line nested_templ.go:285
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/nested.templ", 45, 55)
+			}
+			_, err = templBuffer.WriteString(var_10)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a></td><td>")
+			if err != nil {
+				return err
+			}
+			var var_11 string
+//line nested.templ:46
+			var_11, err = templ.EscapeAny(item.Description)
+//line nested_templ.go:300
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/nested.templ", 46, 9)
+			}
+			_, err = templBuffer.WriteString(var_11)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</td><td class=\"price\">")
+			if err != nil {
+				return err
+			}
+			var var_12 string
+//line nested.templ:47
+			var_12, err = templ.EscapeAny(item.Price)
+//line nested_templ.go:315
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/nested.templ", 47, 23)
+			}
+			_, err = templBuffer.WriteString(var_12)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</td></tr>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+// NestedPage renders the same HTML as Page, using nested components.
+//
+//line nested.templ:52
+func NestedPage(title string, items []Item) templ.Component {
+//line nested_templ.go:339
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.NestedPage"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_13 := templ.GetChildren(ctx)
+		if var_13 == nil {
+			var_13 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		var_14 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			templBuffer, templIsBuffer := w.(*bytes.Buffer)
+			if !templIsBuffer {
+				templBuffer = templ.GetBuffer()
+				defer templ.ReleaseBuffer(templBuffer)
+				ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+			}
+			templSkip := templ.IsOutputSkipped(ctx)
+			if !templSkip {
+				_, err = templBuffer.WriteString("<h1>")
+				if err != nil {
+					return err
+				}
+				var var_15 string
+//line nested.templ:54
+				var_15, err = templ.EscapeAny(title)
+//line nested_templ.go:373
+				if err != nil {
+					return templ.WrapError(err, "benchmarks/templ/nested.templ", 54, 9)
+				}
+				_, err = templBuffer.WriteString(var_15)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</h1><p>Everything we have in stock, updated daily.</p><table class=\"products\"><thead><tr><th>Name</th><th>Description</th><th>Price</th></tr></thead><tbody>")
+				if err != nil {
+					return err
+				}
+			}
+//line nested.templ:61
+			for _, item := range items {
+//line nested_templ.go:388
+//line nested.templ:62
+				err = productRow(item).Render(ctx, templBuffer)
+//line nested_templ.go:391
+				if err != nil {
+					return templ.WrapError(err, "benchmarks/templ/nested.templ", 62, 7)
+				}
+			}
+			if !templSkip {
+				_, err = templBuffer.WriteString("</tbody></table>")
+				if err != nil {
+					return err
+				}
+				if !templIsBuffer {
+					_, err = io.Copy(w, templBuffer)
+				}
+			}
+			return err
+		})
+//line nested.templ:53
+		err = layout(title).Render(templ.WithChildren(ctx, var_14), templBuffer)
+//line nested_templ.go:409
+		if err != nil {
+			return templ.WrapError(err, "benchmarks/templ/nested.templ", 53, 3)
+		}
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,147 @@
+// Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 243b46bd38307469be70f76e692ca6d743c3b89a2ee704f55f65c77febd06755
+
+package testhtml
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import "context"
+import "io"
+import "bytes"
+
+//line page.templ:3
+func Page(title string, items []Item) templ.Component {
+//line page_templ.go:17
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.Page"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_1 := templ.GetChildren(ctx)
+		if var_1 == nil {
+			var_1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<!doctype html><html lang=\"en\"><head><meta charset=\"utf-8\"><title>")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line page.templ:8
+			var_2, err = templ.EscapeAny(title)
+//line page_templ.go:43
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/page.templ", 8, 13)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</title><link rel=\"stylesheet\" href=\"/assets/styles.css\"></head><body><header><nav><ul><li><a href=\"/\">Home</a></li><li><a href=\"/products\">Products</a></li><li><a href=\"/about\">About us</a></li></ul></nav></header><main><h1>")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line page.templ:22
+			var_3, err = templ.EscapeAny(title)
+//line page_templ.go:58
+			if err != nil {
+				return templ.WrapError(err, "benchmarks/templ/page.templ", 22, 11)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1><p>Everything we have in stock, updated daily.</p><table class=\"products\"><thead><tr><th>Name</th><th>Description</th><th>Price</th></tr></thead><tbody>")
+			if err != nil {
+				return err
+			}
+		}
+//line page.templ:29
+		for _, item := range items {
+//line page_templ.go:73
+			if !templSkip {
+				_, err = templBuffer.WriteString("<tr><td><a href=\"")
+				if err != nil {
+					return err
+				}
+//line page.templ:31
+				var var_4 templ.SafeURL = templ.URL("/products/" + item.ID)
+//line page_templ.go:81
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_4)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\">")
+				if err != nil {
+					return err
+				}
+				var var_5 string
+//line page.templ:31
+				var_5, err = templ.EscapeAny(item.Name)
+//line page_templ.go:93
+				if err != nil {
+					return templ.WrapError(err, "benchmarks/templ/page.templ", 31, 61)
+				}
+				_, err = templBuffer.WriteString(var_5)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</a></td><td>")
+				if err != nil {
+					return err
+				}
+				var var_6 string
+//line page.templ:32
+				var_6, err = templ.EscapeAny(item.Description)
+//line page_templ.go:108
+				if err != nil {
+					return templ.WrapError(err, "benchmarks/templ/page.templ", 32, 15)
+				}
+				_, err = templBuffer.WriteString(var_6)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</td><td class=\"price\">")
+				if err != nil {
+					return err
+				}
+				var var_7 string
+//line page.templ:33
+				var_7, err = templ.EscapeAny(item.Price)
+//line page_templ.go:123
+				if err != nil {
+					return templ.WrapError(err, "benchmarks/templ/page.templ", 33, 29)
+				}
+				_, err = templBuffer.WriteString(var_7)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</td></tr>")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</tbody></table></main><footer><p>Prices include tax. <a href=\"/terms\">Terms and conditions</a> apply.</p></footer></body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
@@ -5,9 +5,24 @@ import (
 	"html/template"
 	"io"
 	"strings"
+	"sync"
 	"testing"
 )
 
+func TestTempl(t *testing.T) {
+	w := new(strings.Builder)
+	err := Render(Person{
+		Name:  "Luiz Bonfa",
+		Email: "luiz@example.com",
+	}).Render(context.Background(), w)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if w.String() != html {
+		t.Errorf("expected:\n%s\ngot:\n%s", html, w.String())
+	}
+}
+
 func BenchmarkTempl(b *testing.B) {
 	b.ReportAllocs()
 	t := Render(Person{
@@ -66,3 +81,108 @@ func BenchmarkIOWriteString(b *testing.B) {
 		w.Reset()
 	}
 }
+
+const pageHTML = `<!doctype html><html lang="en"><head><meta charset="utf-8"><title>Products</title><link rel="stylesheet" href="/assets/styles.css"></head><body><header><nav><ul><li><a href="/">Home</a></li><li><a href="/products">Products</a></li><li><a href="/about">About us</a></li></ul></nav></header><main><h1>Products</h1><p>Everything we have in stock, updated daily.</p><table class="products"><thead><tr><th>Name</th><th>Description</th><th>Price</th></tr></thead><tbody><tr><td><a href="/products/1">Tea &amp; biscuits</a></td><td>A box of &lt;b&gt;assorted&lt;/b&gt; biscuits.</td><td class="price">£4.50</td></tr><tr><td><a href="/products/2">Coffee</a></td><td>Whole beans, roasted in &#34;small&#34; batches.</td><td class="price">£9.00</td></tr><tr><td><a href="/products/3">Hot chocolate</a></td><td>Made with real cocoa.</td><td class="price">£6.25</td></tr></tbody></table></main><footer><p>Prices include tax. <a href="/terms">Terms and conditions</a> apply.</p></footer></body></html>`
+
+func TestTemplPage(t *testing.T) {
+	w := new(strings.Builder)
+	err := Page("Products", items).Render(context.Background(), w)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if w.String() != pageHTML {
+		t.Errorf("expected:\n%s\ngot:\n%s", pageHTML, w.String())
+	}
+}
+
+func BenchmarkTemplPage(b *testing.B) {
+	b.ReportAllocs()
+	t := Page("Products", items)
+
+	w := new(strings.Builder)
+	for i := 0; i < b.N; i++ {
+		err := t.Render(context.Background(), w)
+		if err != nil {
+			b.Errorf("failed to render: %v", err)
+		}
+		w.Reset()
+	}
+}
+
+func TestTemplNestedPage(t *testing.T) {
+	w := new(strings.Builder)
+	err := NestedPage("Products", items).Render(context.Background(), w)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if w.String() != pageHTML {
+		t.Errorf("expected:\n%s\ngot:\n%s", pageHTML, w.String())
+	}
+}
+
+func TestTemplConcurrentRender(t *testing.T) {
+	// Components share pooled buffers, so check that concurrent renders don't see each other's output.
+	// Run with go test -race.
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				w := new(strings.Builder)
+				if err := NestedPage("Products", items).Render(context.Background(), w); err != nil {
+					t.Errorf("failed to render: %v", err)
+					return
+				}
+				if w.String() != pageHTML {
+					t.Errorf("expected:\n%s\ngot:\n%s", pageHTML, w.String())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkTemplNestedPage(b *testing.B) {
+	b.ReportAllocs()
+	t := NestedPage("Products", items)
+
+	w := new(strings.Builder)
+	for i := 0; i < b.N; i++ {
+		err := t.Render(context.Background(), w)
+		if err != nil {
+			b.Errorf("failed to render: %v", err)
+		}
+		w.Reset()
+	}
+}
+
+func TestTemplStyledTable(t *testing.T) {
+	w := new(strings.Builder)
+	err := StyledTable(items).Render(context.Background(), w)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	// The CSS and script are shared by the rows, so they're only rendered once.
+	if n := strings.Count(w.String(), "<style"); n != 1 {
+		t.Errorf("expected the CSS to be rendered once, got %d times:\n%s", n, w.String())
+	}
+	if n := strings.Count(w.String(), "<script"); n != 1 {
+		t.Errorf("expected the script to be rendered once, got %d times:\n%s", n, w.String())
+	}
+}
+
+func BenchmarkTemplStyledTable(b *testing.B) {
+	b.ReportAllocs()
+	t := StyledTable(items)
+
+	w := new(strings.Builder)
+	for i := 0; i < b.N; i++ {
+		err := t.Render(context.Background(), w)
+		if err != nil {
+			b.Errorf("failed to render: %v", err)
+		}
+		w.Reset()
+	}
+}
@@ -0,0 +1,300 @@
+// Package config reads the templ.json file that configures templ for a project, so that the
+// command line tools, and the language server started by the editor, use the same settings.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ/cmd/templ/lint"
+)
+
+// FileName is the name of the file that configures templ for the directory that contains it,
+// and its subdirectories.
+const FileName = "templ.json"
+
+// DefaultSuffix is the suffix added to the name of each templ file, without its .templ
+// extension, to name the Go file generated from it.
+const DefaultSuffix = "_templ.go"
+
+// Config is the contents of a templ.json file. Command line flags override its settings.
+type Config struct {
+	// FileName is the templ.json file that the configuration was read from, or empty if there
+	// isn't one.
+	FileName string `json:"-"`
+	// Root is the directory that contains the templ.json file. If there isn't one, it's the
+	// directory that was searched.
+	Root string `json:"-"`
+	// Warnings are problems with the templ.json file that don't prevent it from being used,
+	// e.g. unknown keys.
+	Warnings []string `json:"-"`
+
+	Generate Generate `json:"generate"`
+	Fmt      Fmt      `json:"fmt"`
+	Lint     Lint     `json:"lint"`
+	LSP      LSP      `json:"lsp"`
+}
+
+// Generate configures templ generate. The language server also uses OutDir and Suffix to
+// find the generated code.
+type Generate struct {
+	// OutDir is the directory that the generated code is written to, mirroring the directory
+	// tree under Root. If it's empty, each Go file is written to the same directory as its
+	// templ file.
+	OutDir string `json:"outDir"`
+	// Suffix is added to the name of each templ file, without its .templ extension, to name
+	// the Go file generated from it.
+	Suffix string `json:"suffix"`
+	// IncludeLineDirectives writes //line directives to the generated code. If it's nil, they
+	// are written.
+	IncludeLineDirectives *bool `json:"includeLineDirectives"`
+	// Minify removes whitespace that browsers don't render from the generated code.
+	Minify bool `json:"minify"`
+	// SourceMap writes a visualisation of the source map alongside each generated file.
+	SourceMap bool `json:"sourcemap"`
+	// Workers is the number of files to generate in parallel. If it's zero, the number of CPUs
+	// is used.
+	Workers int `json:"workers"`
+	// BuildTags is a build constraint, e.g. "!dev", written in a //go:build line in each
+	// generated file.
+	BuildTags string `json:"buildTags"`
+	// TagSuffix is added to the names of the generated Go files, before .go, e.g. "_dev".
+	TagSuffix string `json:"tagSuffix"`
+}
+
+// Fmt configures templ fmt. It's reserved for future settings.
+type Fmt struct{}
+
+// Lint configures templ lint, and the warnings shown by the language server.
+type Lint struct {
+	// Enable lists the optional rules that are run, e.g. "inline-style".
+	Enable []string `json:"enable"`
+	// Disable lists the rules that aren't run, e.g. "no-alt".
+	Disable []string `json:"disable"`
+}
+
+// LSP configures templ lsp.
+type LSP struct {
+	// Log is the file to log templ LSP output to.
+	Log string `json:"log"`
+	// GoplsLog is the file to log gopls output to.
+	GoplsLog string `json:"goplsLog"`
+	// Timeouts override how long the language server waits for each kind of request, keyed
+	// by method, e.g. {"textDocument/completion": "5s"}. A timeout of "0s" waits forever.
+	Timeouts map[string]Duration `json:"timeouts"`
+	// SlowRequest is how long a request can take before it's logged as slow. If it's zero,
+	// the language server's default is used.
+	SlowRequest Duration `json:"slowRequest"`
+}
+
+// Duration is a time.Duration that's written in templ.json as a string, e.g. "1m30s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("durations must be strings, e.g. \"10s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if v < 0 {
+		return fmt.Errorf("duration %q is negative", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Default returns the configuration used when there isn't a templ.json file.
+func Default(root string) Config {
+	return Config{Root: root, Generate: Generate{Suffix: DefaultSuffix}}
+}
+
+// Output returns the naming of the generated files.
+func (c Config) Output() Output {
+	return Output{Root: c.Root, OutDir: c.Generate.OutDir, Suffix: c.Generate.Suffix}
+}
+
+// Load returns the configuration in the templ.json file within dir, or the closest of its
+// parent directories, stopping at the root of the Go module. If there isn't a templ.json file,
+// the default configuration for dir is returned.
+func Load(dir string) (c Config, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return c, err
+	}
+	for d := dir; ; {
+		found, ok, err := read(d)
+		if err != nil || ok {
+			return found, err
+		}
+		parent := filepath.Dir(d)
+		if parent == d || isFile(filepath.Join(d, "go.mod")) {
+			break
+		}
+		d = parent
+	}
+	return Default(dir), nil
+}
+
+func read(dir string) (c Config, ok bool, err error) {
+	fileName := filepath.Join(dir, FileName)
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, false, nil
+	}
+	if err != nil {
+		return c, false, err
+	}
+	if c, err = Parse(fileName, data); err != nil {
+		return c, false, err
+	}
+	return c, true, nil
+}
+
+// Parse reads the contents of the templ.json file called fileName. Line comments, starting
+// with //, are allowed. Relative paths are made relative to the directory of the file.
+func Parse(fileName string, data []byte) (c Config, err error) {
+	data = stripComments(data)
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", fileName, err)
+	}
+	c.FileName = fileName
+	c.Root = filepath.Dir(fileName)
+	for _, key := range unknownKeys("", data, reflect.TypeOf(c)) {
+		c.Warnings = append(c.Warnings, fmt.Sprintf("%s: unknown key %q", fileName, key))
+	}
+	for _, list := range []struct {
+		key   string
+		rules []string
+	}{{"lint.enable", c.Lint.Enable}, {"lint.disable", c.Lint.Disable}} {
+		for _, rule := range list.rules {
+			if !isRule(rule) {
+				c.Warnings = append(c.Warnings, fmt.Sprintf("%s: unknown rule %q in %s", fileName, rule, list.key))
+			}
+		}
+	}
+	if c.Generate.Suffix == "" {
+		c.Generate.Suffix = DefaultSuffix
+	}
+	c.Generate.OutDir = c.resolve(c.Generate.OutDir)
+	c.LSP.Log = c.resolve(c.LSP.Log)
+	c.LSP.GoplsLog = c.resolve(c.LSP.GoplsLog)
+	if err = c.Output().Validate(); err != nil {
+		return c, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return c, nil
+}
+
+// resolve returns the path relative to Root.
+func (c Config) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Root, path)
+}
+
+func isRule(name string) bool {
+	for _, rule := range lint.Names() {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stripComments replaces line comments outside of strings with spaces, so that the offsets in
+// JSON syntax errors are unchanged.
+func stripComments(data []byte) []byte {
+	output := make([]byte, len(data))
+	copy(output, data)
+	var inString, inComment bool
+	for i := 0; i < len(output); i++ {
+		switch {
+		case inComment:
+			if output[i] == '\n' {
+				inComment = false
+				continue
+			}
+			output[i] = ' '
+		case inString:
+			if output[i] == '\\' {
+				i++
+			} else if output[i] == '"' {
+				inString = false
+			}
+		case output[i] == '"':
+			inString = true
+		case output[i] == '/' && i+1 < len(output) && output[i+1] == '/':
+			inComment = true
+			output[i] = ' '
+		}
+	}
+	return output
+}
+
+// unknownKeys returns the keys of the JSON objects in data that don't match a field of the
+// struct type t, or of the structs within it, e.g. "generate.minfy".
+func unknownKeys(prefix string, data []byte, t reflect.Type) (keys []string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field, ok := fieldByJSONName(t, name)
+		if !ok {
+			keys = append(keys, prefix+name)
+			continue
+		}
+		keys = append(keys, unknownKeys(prefix+name+".", fields[name], field.Type)...)
+	}
+	return keys
+}
+
+// fieldByJSONName returns the field that encoding/json decodes the key into, which ignores
+// case.
+func fieldByJSONName(t reflect.Type, name string) (f reflect.StructField, ok bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f = t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return f, true
+		}
+	}
+	return f, false
+}
+
+func isFile(fileName string) bool {
+	info, err := os.Stat(fileName)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileNames(t *testing.T) {
+	root := filepath.FromSlash("/app")
+	tests := []struct {
+		name          string
+		output        Output
+		templFileName string
+		goFileName    string
+	}{
+		{
+			name:          "by default, code is generated alongside the templ file",
+			output:        Default(root).Output(),
+			templFileName: "/app/views/page.templ",
+			goFileName:    "/app/views/page_templ.go",
+		},
+		{
+			name:          "relative file names stay relative",
+			output:        Default(root).Output(),
+			templFileName: "views/page.templ",
+			goFileName:    "views/page_templ.go",
+		},
+		{
+			name:          "the suffix replaces _templ.go",
+			output:        Output{Root: root, Suffix: ".gen.go"},
+			templFileName: "/app/views/page.templ",
+			goFileName:    "/app/views/page.gen.go",
+		},
+		{
+			name:          "the directory tree is mirrored in the output directory",
+			output:        Output{Root: root, OutDir: "/app/gen", Suffix: DefaultSuffix},
+			templFileName: "/app/views/components/page.templ",
+			goFileName:    "/app/gen/views/components/page_templ.go",
+		},
+		{
+			name:          "the output directory can be outside of the root",
+			output:        Output{Root: root, OutDir: "/build/gen", Suffix: ".templ.go"},
+			templFileName: "/app/page.templ",
+			goFileName:    "/build/gen/page.templ.go",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			templFileName, goFileName := filepath.FromSlash(tt.templFileName), filepath.FromSlash(tt.goFileName)
+			actualGoFileName, err := tt.output.GoFileName(templFileName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(goFileName, actualGoFileName); diff != "" {
+				t.Errorf("unexpected Go file name:\n%s", diff)
+			}
+			actualTemplFileName, ok := tt.output.TemplFileName(goFileName)
+			if !ok {
+				t.Fatalf("expected %q to be a generated file", goFileName)
+			}
+			if diff := cmp.Diff(templFileName, actualTemplFileName); diff != "" {
+				t.Errorf("unexpected templ file name:\n%s", diff)
+			}
+		})
+	}
+	t.Run("templ files outside of the root can't be generated in the output directory", func(t *testing.T) {
+		c := Output{Root: root, OutDir: filepath.FromSlash("/app/gen")}
+		if _, err := c.GoFileName(filepath.FromSlash("/other/page.templ")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("Go files without the suffix aren't generated files", func(t *testing.T) {
+		c := Output{Root: root, OutDir: filepath.FromSlash("/app/gen"), Suffix: ".templ.go"}
+		for _, fileName := range []string{"/app/gen/views/page_templ.go", "/app/gen/.templ.go", "/app/views/page.templ.go"} {
+			if templFileName, ok := c.TemplFileName(filepath.FromSlash(fileName)); ok {
+				t.Errorf("%s: expected not to be a generated file, got %q", fileName, templFileName)
+			}
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	for _, suffix := range []string{"", "_templ.go", ".gen.go", ".go"} {
+		if err := (Output{Suffix: suffix}).Validate(); err != nil {
+			t.Errorf("%q: unexpected error: %v", suffix, err)
+		}
+	}
+	for _, suffix := range []string{"_templ.txt", "_templ_test.go", "/templ.go"} {
+		if err := (Output{Suffix: suffix}).Validate(); err == nil {
+			t.Errorf("%q: expected an error", suffix)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(t *testing.T, name, contents string) {
+		t.Helper()
+		fileName := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	writeFile(t, "templ.json", `{"generate": {"outDir": "gen", "suffix": ".templ.go"}}`)
+	writeFile(t, "app/go.mod", "module example.com/app\n")
+	writeFile(t, "app/views/page.templ", "")
+	writeFile(t, "other/views/page.templ", "")
+
+	t.Run("the templ.json file in a parent directory is used", func(t *testing.T) {
+		c, err := Load(filepath.Join(dir, "other", "views"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := Config{
+			FileName: filepath.Join(dir, FileName),
+			Root:     dir,
+			Generate: Generate{OutDir: filepath.Join(dir, "gen"), Suffix: ".templ.go"},
+		}
+		if diff := cmp.Diff(expected, c); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("templ.json files outside of the Go module aren't used", func(t *testing.T) {
+		appViews := filepath.Join(dir, "app", "views")
+		c, err := Load(appViews)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(Default(appViews), c); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the suffix defaults to _templ.go", func(t *testing.T) {
+		writeFile(t, "app/templ.json", `{"generate": {"outDir": "/gen"}}`)
+		c, err := Load(filepath.Join(dir, "app"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := Config{
+			FileName: filepath.Join(dir, "app", FileName),
+			Root:     filepath.Join(dir, "app"),
+			Generate: Generate{OutDir: "/gen", Suffix: DefaultSuffix},
+		}
+		if diff := cmp.Diff(expected, c); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("invalid templ.json files are an error", func(t *testing.T) {
+		writeFile(t, "app/templ.json", `{"generate": {"suffix": ".templ"}}`)
+		if _, err := Load(filepath.Join(dir, "app")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	root := filepath.FromSlash("/app")
+	fileName := filepath.Join(root, FileName)
+	t.Run("all sections can be set", func(t *testing.T) {
+		c, err := Parse(fileName, []byte(`{
+			// Comments are allowed, even with "quotes" in them.
+			"generate": {
+				"outDir": "gen",
+				"includeLineDirectives": false,
+				"minify": true,
+				"sourcemap": true,
+				"workers": 2 // Trailing comments too.
+			},
+			"fmt": {},
+			"lint": {"enable": ["inline-style"], "disable": ["no-alt", "duplicate-id"]},
+			"lsp": {
+				"log": "logs/templ.log",
+				"goplsLog": "/var/log/gopls.log",
+				"timeouts": {"textDocument/completion": "5s", "workspace/symbol": "0s"},
+				"slowRequest": "500ms"
+			}
+		}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		includeLineDirectives := false
+		expected := Config{
+			FileName: fileName,
+			Root:     root,
+			Generate: Generate{
+				OutDir:                filepath.Join(root, "gen"),
+				Suffix:                DefaultSuffix,
+				IncludeLineDirectives: &includeLineDirectives,
+				Minify:                true,
+				SourceMap:             true,
+				Workers:               2,
+			},
+			Lint: Lint{Enable: []string{"inline-style"}, Disable: []string{"no-alt", "duplicate-id"}},
+			LSP: LSP{
+				Log:      filepath.Join(root, "logs", "templ.log"),
+				GoplsLog: "/var/log/gopls.log",
+				Timeouts: map[string]Duration{
+					"textDocument/completion": Duration(5 * time.Second),
+					"workspace/symbol":        0,
+				},
+				SlowRequest: Duration(500 * time.Millisecond),
+			},
+		}
+		if diff := cmp.Diff(expected, c); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("// within strings isn't a comment", func(t *testing.T) {
+		c, err := Parse(fileName, []byte(`{"lsp": {"log": "//server/share/templ.log"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.LSP.Log != "//server/share/templ.log" {
+			t.Errorf("unexpected log: %q", c.LSP.Log)
+		}
+	})
+	t.Run("unknown keys and rules are warnings", func(t *testing.T) {
+		c, err := Parse(fileName, []byte(`{
+			"generate": {"minfy": true, "Workers": 1},
+			"lint": {"enable": ["img-alt"], "disable": ["no-alt", "no-title"]},
+			"format": {}
+		}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{
+			fileName + `: unknown key "format"`,
+			fileName + `: unknown key "generate.minfy"`,
+			fileName + `: unknown rule "img-alt" in lint.enable`,
+			fileName + `: unknown rule "no-title" in lint.disable`,
+		}
+		if diff := cmp.Diff(expected, c.Warnings); diff != "" {
+			t.Error(diff)
+		}
+		if c.Generate.Workers != 1 {
+			t.Errorf("expected keys to match without regard to case, got %d workers", c.Generate.Workers)
+		}
+	})
+	t.Run("invalid JSON is an error", func(t *testing.T) {
+		if _, err := Parse(fileName, []byte(`{"generate": {"workers": "two"}}`)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("invalid durations are errors", func(t *testing.T) {
+		for _, duration := range []string{`5`, `"5"`, `"-1s"`} {
+			if _, err := Parse(fileName, []byte(`{"lsp": {"slowRequest": `+duration+`}}`)); err == nil {
+				t.Errorf("expected an error for %s", duration)
+			}
+		}
+	})
+}
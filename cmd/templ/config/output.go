@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Output is the naming of the Go files generated from templ files.
+type Output struct {
+	// Root is the directory that contains the templ files. If OutDir is set, the directory
+	// tree under Root is mirrored under OutDir.
+	Root string
+	// OutDir is the directory that the generated code is written to. If it's empty, each Go
+	// file is written to the same directory as its templ file.
+	OutDir string
+	// Suffix is added to the name of each templ file, without its .templ extension, to name
+	// the Go file generated from it. If it's empty, DefaultSuffix is used.
+	Suffix string
+}
+
+// Validate returns an error if the suffix wouldn't name a Go file that's compiled into the
+// package.
+func (o Output) Validate() error {
+	suffix := o.suffix()
+	if !strings.HasSuffix(suffix, ".go") || strings.HasSuffix(suffix, "_test.go") {
+		return fmt.Errorf("invalid suffix %q: must end with .go, and not with _test.go", suffix)
+	}
+	if strings.ContainsAny(suffix, `/\`) {
+		return fmt.Errorf("invalid suffix %q: must not contain a path separator", suffix)
+	}
+	return nil
+}
+
+// OutputRoot returns the directory that contains the generated code.
+func (o Output) OutputRoot() string {
+	if o.OutDir != "" {
+		return o.OutDir
+	}
+	return o.Root
+}
+
+func (o Output) suffix() string {
+	if o.Suffix == "" {
+		return DefaultSuffix
+	}
+	return o.Suffix
+}
+
+// GoFileName returns the name of the Go file generated from the templ file. If OutDir is set,
+// the templ file must be within Root.
+func (o Output) GoFileName(templFileName string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(templFileName), ".templ") + o.suffix()
+	if o.OutDir == "" {
+		return filepath.Join(filepath.Dir(templFileName), name), nil
+	}
+	rel, err := relative(o.Root, templFileName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(o.OutDir, filepath.Dir(rel), name), nil
+}
+
+// TemplFileName returns the name of the templ file that the Go file would be generated from,
+// or false if the Go file isn't named as generated code.
+func (o Output) TemplFileName(goFileName string) (string, bool) {
+	base := filepath.Base(goFileName)
+	if !strings.HasSuffix(base, o.suffix()) || base == o.suffix() {
+		return "", false
+	}
+	name := strings.TrimSuffix(base, o.suffix()) + ".templ"
+	if o.OutDir == "" {
+		return filepath.Join(filepath.Dir(goFileName), name), true
+	}
+	rel, err := relative(o.OutDir, goFileName)
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(o.Root, filepath.Dir(rel), name), true
+}
+
+// relative returns the path of fileName relative to dir, or an error if it's not within dir.
+func relative(dir, fileName string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not within %s", fileName, dir)
+	}
+	return rel, nil
+}
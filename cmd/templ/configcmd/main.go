@@ -0,0 +1,81 @@
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/a-h/templ/cmd/templ/config"
+)
+
+type Arguments struct {
+	// Dir is the directory to write the templ.json file to.
+	Dir string
+}
+
+// defaultConfig is the contents of the templ.json file written by templ config init. The
+// values are the defaults, so that the file documents the settings without changing them.
+const defaultConfig = `{
+  // templ.json configures templ for this directory and its subdirectories, up to the root
+  // of the Go module. Command line flags override these settings.
+  "generate": {
+    // The directory to write the generated code to, mirroring the directory tree under this
+    // file. Leave empty to write each Go file alongside its templ file.
+    "outDir": "",
+    // The suffix of the generated Go files.
+    "suffix": "_templ.go",
+    // Set to false to omit the //line directives that make stack traces and compiler errors
+    // refer to the templ files.
+    "includeLineDirectives": true,
+    // Set to true to remove HTML comments and whitespace that isn't rendered from the
+    // generated code.
+    "minify": false,
+    // Set to true to write the source map of each generated file to <name>_templ.go.map.
+    "sourcemap": false,
+    // The number of files to generate in parallel, or 0 to use the number of CPUs.
+    "workers": 0,
+    // A build constraint, e.g. "!dev", to write in a //go:build line in each generated file.
+    "buildTags": "",
+    // Added to the names of the generated Go files, before .go, e.g. "_dev" names the code
+    // generated from home.templ home_templ_dev.go.
+    "tagSuffix": ""
+  },
+  "fmt": {},
+  "lint": {
+    // The optional rules that templ lint and the language server run, e.g. "inline-style".
+    "enable": [],
+    // The rules that templ lint and the language server don't run, e.g. "no-alt".
+    "disable": []
+  },
+  "lsp": {
+    // The files to log templ LSP and gopls output to, or leave empty to disable logging.
+    "log": "",
+    "goplsLog": ""
+  }
+}
+`
+
+// Init writes a templ.json file containing the default settings to the directory. An
+// existing templ.json file isn't overwritten.
+func Init(w io.Writer, args Arguments) (err error) {
+	fileName := filepath.Join(args.Dir, config.FileName)
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", fileName)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(f, defaultConfig); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Created %s\n", fileName)
+	return nil
+}
@@ -0,0 +1,49 @@
+package configcmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+	if err := Init(io.Discard, Arguments{Dir: dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Run("the default file loads without warnings", func(t *testing.T) {
+		c, err := config.Load(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		includeLineDirectives := true
+		expected := config.Default(dir)
+		expected.FileName = filepath.Join(dir, config.FileName)
+		expected.Generate.IncludeLineDirectives = &includeLineDirectives
+		expected.Lint.Enable = []string{}
+		expected.Lint.Disable = []string{}
+		if diff := cmp.Diff(expected, c); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("existing files aren't overwritten", func(t *testing.T) {
+		fileName := filepath.Join(dir, config.FileName)
+		if err := os.WriteFile(fileName, []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if err := Init(io.Discard, Arguments{Dir: dir}); err == nil {
+			t.Error("expected an error")
+		}
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if string(data) != "{}" {
+			t.Errorf("expected the file to be unchanged, got %q", data)
+		}
+	})
+}
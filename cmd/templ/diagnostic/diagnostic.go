@@ -0,0 +1,129 @@
+// Package diagnostic writes the problems found by templ generate, templ fmt and templ lint as
+// newline-delimited JSON, so that build systems and editor plugins don't need to parse the
+// human readable output.
+package diagnostic
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// The codes of diagnostics that aren't lint issues. Lint issues use the name of the rule that
+// found them, e.g. "no-alt".
+const (
+	// CodeParseError is used for templ files that can't be parsed.
+	CodeParseError = parser.CodeParseError
+	// CodeGenerateError is used for templ files that are parsed, but that code can't be
+	// generated for.
+	CodeGenerateError = "generate-error"
+	// CodeUnformatted is used by templ fmt -check for files that aren't formatted.
+	CodeUnformatted = "unformatted"
+	// CodeError is used for other errors, e.g. files that can't be read.
+	CodeError = "error"
+)
+
+// SeverityError is the severity of diagnostics that aren't lint issues.
+const SeverityError = "error"
+
+// Diagnostic is a problem found in a file. Lines and columns start at 1. They're 0 if the
+// problem doesn't have a position, e.g. if the file can't be read. File is empty if the
+// problem isn't in a file.
+type Diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+	EndLine  int    `json:"endLine"`
+	EndCol   int    `json:"endCol"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// Diagnoser is implemented by errors that describe themselves as diagnostics.
+type Diagnoser interface {
+	Diagnostics() []Diagnostic
+}
+
+// FromRange returns a diagnostic for the range of the file.
+func FromRange(fileName string, r parser.Range, severity, code, message string) Diagnostic {
+	return Diagnostic{
+		File:     fileName,
+		Line:     int(r.From.Line) + 1,
+		Col:      int(r.From.Col) + 1,
+		EndLine:  int(r.To.Line) + 1,
+		EndCol:   int(r.To.Col) + 1,
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+	}
+}
+
+// FromParser returns the diagnostic for a diagnostic found by the parser, e.g. by
+// parser.Diagnose.
+func FromParser(d parser.Diagnostic) Diagnostic {
+	return FromRange(d.FileName, d.Range, d.Severity.String(), d.Code, d.Message)
+}
+
+// FromIssue returns the diagnostic for a lint issue.
+func FromIssue(fileName string, issue parser.Issue) Diagnostic {
+	return FromParser(issue.Diagnostic(fileName))
+}
+
+// FromError returns the diagnostics for an error. Errors joined with errors.Join are
+// returned as separate diagnostics, as are the parse errors in a parser.FileError.
+func FromError(err error) (diagnostics []Diagnostic) {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			diagnostics = append(diagnostics, FromError(err)...)
+		}
+		return diagnostics
+	}
+	var d Diagnoser
+	if errors.As(err, &d) {
+		return d.Diagnostics()
+	}
+	var fe parser.FileError
+	if !errors.As(err, &fe) {
+		return []Diagnostic{{Severity: SeverityError, Code: CodeError, Message: err.Error()}}
+	}
+	if parsed, ok := parser.ParseErrorDiagnostics(fe.FileName, fe.Err); ok {
+		for _, d := range parsed {
+			diagnostics = append(diagnostics, FromParser(d))
+		}
+		return diagnostics
+	}
+	return []Diagnostic{{File: fe.FileName, Severity: SeverityError, Code: CodeError, Message: fe.Err.Error()}}
+}
+
+// Encoder writes diagnostics as newline-delimited JSON. It's safe for concurrent use.
+type Encoder struct {
+	m   sync.Mutex
+	enc *json.Encoder
+}
+
+// NewEncoder returns an Encoder that writes to w. Messages often contain HTML, so it isn't
+// escaped.
+func NewEncoder(w io.Writer) *Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &Encoder{enc: enc}
+}
+
+// Encode writes each diagnostic on its own line.
+func (e *Encoder) Encode(diagnostics ...Diagnostic) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	for _, d := range diagnostics {
+		if err := e.enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+type diagnoser struct{}
+
+func (diagnoser) Error() string { return "diagnoser" }
+
+func (diagnoser) Diagnostics() []Diagnostic {
+	return []Diagnostic{{File: "a.templ", Line: 1, Col: 2, EndLine: 1, EndCol: 2, Severity: SeverityError, Code: "custom", Message: "diagnoser"}}
+}
+
+func parseError(t *testing.T, fileName, src string) error {
+	t.Helper()
+	_, err := parser.ParseString(src)
+	if err == nil {
+		t.Fatal("expected a parse error")
+	}
+	return parser.FileError{FileName: fileName, Err: err}
+}
+
+func TestFromError(t *testing.T) {
+	parseErr := parseError(t, "a.templ", "package a\n\ntempl A() {\n\t<div>\n}\n")
+	tests := []struct {
+		name     string
+		err      error
+		expected []Diagnostic
+	}{
+		{
+			name: "nil errors have no diagnostics",
+		},
+		{
+			name: "parse errors have positions",
+			err:  parseErr,
+			expected: []Diagnostic{{
+				File:     "a.templ",
+				Line:     5,
+				Col:      1,
+				EndLine:  5,
+				EndCol:   1,
+				Severity: SeverityError,
+				Code:     CodeParseError,
+				Message:  "<div>: expected end tag not present or invalid tag contents",
+			}},
+		},
+		{
+			name:     "other file errors don't have positions",
+			err:      parser.FileError{FileName: "b.templ", Err: errors.New("read error")},
+			expected: []Diagnostic{{File: "b.templ", Severity: SeverityError, Code: CodeError, Message: "read error"}},
+		},
+		{
+			name:     "other errors aren't in a file",
+			err:      errors.New("failed"),
+			expected: []Diagnostic{{Severity: SeverityError, Code: CodeError, Message: "failed"}},
+		},
+		{
+			name:     "errors can describe themselves",
+			err:      diagnoser{},
+			expected: diagnoser{}.Diagnostics(),
+		},
+		{
+			name: "joined errors are separate diagnostics",
+			err:  errors.Join(diagnoser{}, errors.New("failed")),
+			expected: []Diagnostic{
+				diagnoser{}.Diagnostics()[0],
+				{Severity: SeverityError, Code: CodeError, Message: "failed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, FromError(tt.err)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFromIssue(t *testing.T) {
+	issue := parser.Issue{
+		Check:    parser.CheckNoAlt,
+		Severity: parser.SeverityWarning,
+		Message:  "<img>: missing alt attribute",
+		Range: parser.Range{
+			From: parser.Position{Line: 3, Col: 2},
+			To:   parser.Position{Line: 3, Col: 5},
+		},
+	}
+	expected := Diagnostic{
+		File:     "a.templ",
+		Line:     4,
+		Col:      3,
+		EndLine:  4,
+		EndCol:   6,
+		Severity: "warning",
+		Code:     parser.CheckNoAlt,
+		Message:  "<img>: missing alt attribute",
+	}
+	if diff := cmp.Diff(expected, FromIssue("a.templ", issue)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	var w bytes.Buffer
+	enc := NewEncoder(&w)
+	if err := enc.Encode(FromError(errors.Join(diagnoser{}, errors.New("<failed>")))...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", w.String())
+	}
+	if !strings.Contains(lines[1], `"message":"<failed>"`) {
+		t.Errorf("expected HTML not to be escaped, got %q", lines[1])
+	}
+	for _, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		for _, field := range []string{"file", "line", "col", "endLine", "endCol", "severity", "code", "message"} {
+			if _, ok := record[field]; !ok {
+				t.Errorf("expected field %q in %q", field, line)
+			}
+		}
+	}
+}
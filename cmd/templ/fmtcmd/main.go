@@ -1,82 +1,291 @@
 package fmtcmd
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/a-h/templ/cmd/templ/diagnostic"
 	"github.com/a-h/templ/cmd/templ/processor"
 	parser "github.com/a-h/templ/parser/v2"
 	"github.com/natefinch/atomic"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
 const workerCount = 4
 
-func Run(args []string) (err error) {
-	if len(args) > 0 {
-		return formatDir(args[0])
+// stdinFileName is the name used for templates read from stdin in diffs and errors.
+const stdinFileName = "<standard input>"
+
+// ErrUnformattedFiles is returned by Run in check mode when any of the templates aren't formatted.
+var ErrUnformattedFiles = errors.New("unformatted files found")
+
+type Arguments struct {
+	// Paths are the templ files, and the directories that are searched for templ files, to
+	// format. A path of "-", or no paths, reads a template from stdin and writes the formatted
+	// template to stdout.
+	Paths []string
+	// Check writes a unified diff of the changes needed to format each template to Log,
+	// instead of formatting it, and returns ErrUnformattedFiles if there are any changes.
+	Check bool
+	// Log is written to with progress messages and diffs. Defaults to stdout.
+	Log io.Writer
+	// Diagnostics, if it's set, is written to with the files that aren't formatted in check
+	// mode, and with the errors found in templates, instead of returning them.
+	Diagnostics *diagnostic.Encoder
+}
+
+// Run formats the templates in place. Templates that can't be parsed are left unchanged, and
+// their errors are returned.
+func Run(stdin io.Reader, stdout io.Writer, args Arguments) (err error) {
+	if args.Log == nil {
+		args.Log = stdout
+	}
+	if len(args.Paths) == 0 || (len(args.Paths) == 1 && args.Paths[0] == "-") {
+		err = formatStdin(stdin, stdout, args)
+	} else {
+		for _, path := range args.Paths {
+			if path == "-" {
+				return errors.New("stdin (-) can't be formatted with other paths")
+			}
+		}
+		err = formatPaths(args)
+	}
+	if args.Diagnostics != nil {
+		return report(args.Diagnostics, err)
+	}
+	return err
+}
+
+// report writes the errors as diagnostics. It returns an error for the exit code, which
+// doesn't repeat the errors.
+func report(d *diagnostic.Encoder, err error) error {
+	if err == nil || err == ErrUnformattedFiles {
+		return err
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var failed int
+	for _, e := range errs {
+		if e == ErrUnformattedFiles {
+			continue
+		}
+		failed++
+		if encErr := d.Encode(diagnostic.FromError(e)...); encErr != nil {
+			return encErr
+		}
 	}
-	return formatStdin()
+	return fmt.Errorf("failed to format templates, found %d errors", failed)
 }
 
-func formatStdin() (err error) {
-	var bytes []byte
-	bytes, err = io.ReadAll(os.Stdin)
+// Format returns the formatted template. It's the same formatting that's applied by the
+// language server.
+func Format(src string) (formatted string, err error) {
+	t, err := parser.ParseString(src)
 	if err != nil {
-		return
+		return "", err
+	}
+	w := new(strings.Builder)
+	if err = t.Write(w); err != nil {
+		return "", fmt.Errorf("formatting error: %w", err)
 	}
-	t, err := parser.ParseString(string(bytes))
+	return w.String(), nil
+}
+
+func formatStdin(stdin io.Reader, stdout io.Writer, args Arguments) (err error) {
+	src, err := io.ReadAll(stdin)
 	if err != nil {
-		return fmt.Errorf("parsing error: %w", err)
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+	formatted, err := Format(string(src))
+	if err != nil {
+		return parser.FileError{FileName: stdinFileName, Err: err}
+	}
+	if !args.Check {
+		_, err = io.WriteString(stdout, formatted)
+		return err
 	}
-	err = t.Write(os.Stdout)
+	if formatted == string(src) {
+		return nil
+	}
+	diff, err := unifiedDiff(stdinFileName, string(src), formatted)
 	if err != nil {
-		return fmt.Errorf("formatting error: %w", err)
+		return err
+	}
+	if _, err = io.WriteString(args.Log, diff); err != nil {
+		return err
+	}
+	if err = reportUnformatted(args.Diagnostics, stdinFileName, string(src), formatted); err != nil {
+		return err
 	}
-	return nil
+	return ErrUnformattedFiles
 }
 
-func formatDir(dir string) (err error) {
+func formatPaths(args Arguments) (err error) {
 	start := time.Now()
+	templates := make(chan string)
+	findErrs := make(chan error, 1)
+	go func() {
+		defer close(templates)
+		findErrs <- findTemplates(args.Paths, templates)
+	}()
+	var m sync.Mutex
+	fileToDiff := make(map[string]fileDiff)
+	format := func(fileName string) error {
+		diff, err := formatFile(fileName, args.Check)
+		if diff.diff != "" {
+			m.Lock()
+			defer m.Unlock()
+			fileToDiff[fileName] = diff
+		}
+		return err
+	}
 	results := make(chan processor.Result)
-	go processor.Process(dir, format, workerCount, results)
-	var successCount, errorCount int
+	go processor.ProcessChannel(templates, "", format, workerCount, results)
+	var errs []processor.Result
+	var count int
 	for r := range results {
+		count++
 		if r.Error != nil {
-			err = errors.Join(err, fmt.Errorf("%s: %w", r.FileName, r.Error))
-			errorCount++
+			errs = append(errs, r)
 			continue
 		}
-		fmt.Printf("%s complete in %v\n", r.FileName, r.Duration)
-		successCount++
+		if !args.Check {
+			fmt.Fprintf(args.Log, "%s complete in %v\n", r.FileName, r.Duration)
+		}
 	}
-	fmt.Printf("Formatted %d templates with %d errors in %s\n", successCount+errorCount, errorCount, time.Since(start))
-	return
+	err = <-findErrs
+
+	// Errors and diffs are written in the order of the file names, so that the output
+	// doesn't depend on which file was processed first.
+	sort.Slice(errs, func(i, j int) bool { return errs[i].FileName < errs[j].FileName })
+	for _, r := range errs {
+		err = errors.Join(err, r.Error)
+	}
+	if args.Check {
+		fileNames := make([]string, 0, len(fileToDiff))
+		for fileName := range fileToDiff {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			d := fileToDiff[fileName]
+			io.WriteString(args.Log, d.diff)
+			if diagErr := reportUnformatted(args.Diagnostics, fileName, d.src, d.formatted); diagErr != nil {
+				err = errors.Join(err, diagErr)
+			}
+		}
+		if len(fileNames) > 0 && err == nil {
+			return ErrUnformattedFiles
+		}
+		if len(fileNames) > 0 {
+			err = errors.Join(err, ErrUnformattedFiles)
+		}
+		return err
+	}
+	fmt.Fprintf(args.Log, "Formatted %d templates with %d errors in %s\n", count, len(errs), time.Since(start))
+	return err
 }
 
-func format(fileName string) (err error) {
-	contents, err := os.ReadFile(fileName)
-	if err != nil {
-		return fmt.Errorf("failed to read file %q: %w", fileName, err)
+// findTemplates sends the files in paths, and the templ files within the directories in
+// paths, to output.
+func findTemplates(paths []string, output chan<- string) (err error) {
+	for _, path := range paths {
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			err = errors.Join(err, statErr)
+			continue
+		}
+		if !info.IsDir() {
+			output <- path
+			continue
+		}
+		err = errors.Join(err, processor.FindTemplates(path, output))
 	}
-	t, err := parser.ParseString(string(contents))
+	return err
+}
+
+// fileDiff is the change needed to format a file.
+type fileDiff struct {
+	src, formatted string
+	// diff is a unified diff of the change.
+	diff string
+}
+
+// formatFile formats the file in place, or returns a diff of the changes if check is set.
+func formatFile(fileName string, check bool) (diff fileDiff, err error) {
+	src, err := os.ReadFile(fileName)
 	if err != nil {
-		return fmt.Errorf("%s parsing error: %w", fileName, err)
+		return diff, fmt.Errorf("failed to read file %q: %w", fileName, err)
 	}
-	w := new(bytes.Buffer)
-	err = t.Write(w)
+	formatted, err := Format(string(src))
 	if err != nil {
-		return fmt.Errorf("%s formatting error: %w", fileName, err)
+		return diff, parser.FileError{FileName: fileName, Err: err}
+	}
+	if formatted == string(src) {
+		return diff, nil
+	}
+	if check {
+		diff = fileDiff{src: string(src), formatted: formatted}
+		diff.diff, err = unifiedDiff(fileName, diff.src, formatted)
+		return diff, err
 	}
-	if string(contents) == w.String() {
+	if err = atomic.WriteFile(fileName, strings.NewReader(formatted)); err != nil {
+		return diff, fmt.Errorf("%s file write error: %w", fileName, err)
+	}
+	return diff, nil
+}
+
+// reportUnformatted writes a diagnostic for a file that isn't formatted, at the first line
+// that's changed by formatting, if diagnostics are enabled.
+func reportUnformatted(d *diagnostic.Encoder, fileName, src, formatted string) error {
+	if d == nil {
 		return nil
 	}
-	err = atomic.WriteFile(fileName, w)
+	srcLines, formattedLines := splitLines(src), splitLines(formatted)
+	line := 0
+	for line < len(srcLines) && line < len(formattedLines) && srcLines[line] == formattedLines[line] {
+		line++
+	}
+	return d.Encode(diagnostic.Diagnostic{
+		File:     fileName,
+		Line:     line + 1,
+		Col:      1,
+		EndLine:  line + 1,
+		EndCol:   1,
+		Severity: diagnostic.SeverityError,
+		Code:     diagnostic.CodeUnformatted,
+		Message:  "file is not formatted, run templ fmt to format it",
+	})
+}
+
+func unifiedDiff(fileName, src, formatted string) (diff string, err error) {
+	diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(src),
+		B:        splitLines(formatted),
+		FromFile: fileName + ".orig",
+		ToFile:   fileName,
+		Context:  3,
+	})
 	if err != nil {
-		return fmt.Errorf("%s file write error: %w", fileName, err)
+		return "", fmt.Errorf("%s diff error: %w", fileName, err)
+	}
+	return diff, nil
+}
+
+// splitLines splits s after each newline. Unlike difflib.SplitLines, it doesn't add an empty
+// line to the end of s, which would appear in the diff as a line that doesn't exist.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
 	}
-	return
+	return lines
 }
@@ -0,0 +1,246 @@
+package fmtcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/diagnostic"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	unformatted = "package a\n\ntempl A() {\n<div>x</div>\n}\n"
+	formatted   = "package a\n\ntempl A() {\n\t<div>x</div>\n}\n\n"
+	invalid     = "package a\n\ntempl B() {\n\t<div>\n}\n"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		fileName := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+func readFile(t *testing.T, fileName string) string {
+	t.Helper()
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	return string(contents)
+}
+
+func TestFormatStdin(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           Arguments
+		input          string
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			name:           "no paths formats stdin to stdout",
+			input:          unformatted,
+			expectedOutput: formatted,
+		},
+		{
+			name:           "a path of - formats stdin to stdout",
+			args:           Arguments{Paths: []string{"-"}},
+			input:          unformatted,
+			expectedOutput: formatted,
+		},
+		{
+			name:        "parse errors are reported with their position",
+			input:       invalid,
+			expectedErr: "<standard input>:5:1: <div>: expected end tag not present or invalid tag contents",
+		},
+		{
+			name:           "check prints a diff",
+			args:           Arguments{Check: true},
+			input:          unformatted,
+			expectedOutput: "--- <standard input>.orig\n+++ <standard input>\n@@ -1,5 +1,6 @@\n package a\n \n templ A() {\n-<div>x</div>\n+\t<div>x</div>\n }\n+\n",
+			expectedErr:    ErrUnformattedFiles.Error(),
+		},
+		{
+			name:  "check prints nothing if the template is formatted",
+			args:  Arguments{Check: true},
+			input: formatted,
+		},
+		{
+			name:        "stdin can't be combined with other paths",
+			args:        Arguments{Paths: []string{"-", "a.templ"}},
+			expectedErr: "stdin (-) can't be formatted with other paths",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := new(bytes.Buffer)
+			err := Run(strings.NewReader(tt.input), stdout, tt.args)
+			var actualErr string
+			if err != nil {
+				actualErr = err.Error()
+			}
+			if diff := cmp.Diff(tt.expectedErr, actualErr); diff != "" {
+				t.Errorf("unexpected error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.expectedOutput, stdout.String()); diff != "" {
+				t.Errorf("unexpected output:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatPaths(t *testing.T) {
+	t.Run("directories are formatted recursively", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"a.templ":          unformatted,
+			"sub/b.templ":      unformatted,
+			"sub/c.templ":      formatted,
+			"_skipped/d.templ": unformatted,
+		})
+		if err := Run(nil, new(bytes.Buffer), Arguments{Paths: []string{dir}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for name, expected := range map[string]string{
+			"a.templ":          formatted,
+			"sub/b.templ":      formatted,
+			"sub/c.templ":      formatted,
+			"_skipped/d.templ": unformatted,
+		} {
+			if diff := cmp.Diff(expected, readFile(t, filepath.Join(dir, name))); diff != "" {
+				t.Errorf("%s:\n%s", name, diff)
+			}
+		}
+	})
+	t.Run("files that can't be parsed are reported and left unchanged", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"a.templ": unformatted,
+			"b.templ": invalid,
+		})
+		err := Run(nil, new(bytes.Buffer), Arguments{Paths: []string{filepath.Join(dir, "b.templ"), filepath.Join(dir, "a.templ")}})
+		expectedErr := filepath.Join(dir, "b.templ") + ":5:1: <div>: expected end tag not present or invalid tag contents"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("expected error %q, got %v", expectedErr, err)
+		}
+		if diff := cmp.Diff(formatted, readFile(t, filepath.Join(dir, "a.templ"))); diff != "" {
+			t.Errorf("a.templ:\n%s", diff)
+		}
+		if diff := cmp.Diff(invalid, readFile(t, filepath.Join(dir, "b.templ"))); diff != "" {
+			t.Errorf("b.templ:\n%s", diff)
+		}
+	})
+	t.Run("check prints a diff of each unformatted file, and doesn't change them", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"a.templ": unformatted,
+			"b.templ": formatted,
+			"c.templ": unformatted,
+		})
+		stdout := new(bytes.Buffer)
+		err := Run(nil, stdout, Arguments{Paths: []string{dir}, Check: true})
+		if !errors.Is(err, ErrUnformattedFiles) {
+			t.Errorf("expected ErrUnformattedFiles, got %v", err)
+		}
+		var headers []string
+		for _, line := range strings.Split(stdout.String(), "\n") {
+			if strings.HasPrefix(line, "+++ ") {
+				headers = append(headers, line)
+			}
+		}
+		expectedHeaders := []string{"+++ " + filepath.Join(dir, "a.templ"), "+++ " + filepath.Join(dir, "c.templ")}
+		if diff := cmp.Diff(expectedHeaders, headers); diff != "" {
+			t.Errorf("unexpected diffs:\n%s\n%s", diff, stdout.String())
+		}
+		if diff := cmp.Diff(unformatted, readFile(t, filepath.Join(dir, "a.templ"))); diff != "" {
+			t.Errorf("a.templ:\n%s", diff)
+		}
+	})
+	t.Run("check returns no error if all files are formatted", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"a.templ": formatted})
+		stdout := new(bytes.Buffer)
+		if err := Run(nil, stdout, Arguments{Paths: []string{dir}, Check: true}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("expected no output, got %q", stdout.String())
+		}
+	})
+}
+
+func TestJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.templ": unformatted,
+		"b.templ": invalid,
+		"c.templ": formatted,
+	})
+	stdout, log := new(bytes.Buffer), new(bytes.Buffer)
+	err := Run(nil, stdout, Arguments{
+		Paths:       []string{dir},
+		Check:       true,
+		Log:         log,
+		Diagnostics: diagnostic.NewEncoder(stdout),
+	})
+	if err == nil || errors.Is(err, ErrUnformattedFiles) {
+		t.Errorf("expected an error for the file that can't be parsed, got %v", err)
+	}
+	var actual []diagnostic.Diagnostic
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		for _, field := range []string{"file", "line", "col", "endLine", "endCol", "severity", "code", "message"} {
+			if _, ok := fields[field]; !ok {
+				t.Errorf("expected field %q in %s", field, line)
+			}
+		}
+		var d diagnostic.Diagnostic
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		actual = append(actual, d)
+	}
+	expected := []diagnostic.Diagnostic{
+		{
+			File:     filepath.Join(dir, "a.templ"),
+			Line:     4,
+			Col:      1,
+			EndLine:  4,
+			EndCol:   1,
+			Severity: diagnostic.SeverityError,
+			Code:     diagnostic.CodeUnformatted,
+			Message:  "file is not formatted, run templ fmt to format it",
+		},
+		{
+			File:     filepath.Join(dir, "b.templ"),
+			Line:     5,
+			Col:      1,
+			EndLine:  5,
+			EndCol:   1,
+			Severity: diagnostic.SeverityError,
+			Code:     diagnostic.CodeParseError,
+			Message:  "<div>: expected end tag not present or invalid tag contents",
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	if !strings.Contains(log.String(), "+++ "+filepath.Join(dir, "a.templ")) {
+		t.Errorf("expected the diff to be written to the log, got %q", log.String())
+	}
+}
@@ -0,0 +1,91 @@
+package generatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// includedFile is a file included in a template with templ.Include.
+type includedFile struct {
+	// name is the name of the file, as written in the template.
+	name     string
+	contents []byte
+	// missing is true if the file can't be read.
+	missing bool
+}
+
+// readIncludes reads the files included by the templ file, which are relative to its
+// directory. Files that can't be read are returned without their contents, so that the
+// error is reported when the code is generated.
+func readIncludes(templFileName string, names []string) (files []includedFile) {
+	files = make([]includedFile, len(names))
+	for i, name := range names {
+		files[i].name = name
+		contents, err := os.ReadFile(includePath(templFileName, name))
+		files[i].contents, files[i].missing = contents, err != nil
+	}
+	return files
+}
+
+func includePath(templFileName, name string) string {
+	return filepath.Join(filepath.Dir(templFileName), filepath.FromSlash(name))
+}
+
+// includes maps the files included in templates to the templ files that include them, so
+// that the watcher generates the code for the templates again when the files change. The
+// methods of a nil *includes do nothing.
+type includes struct {
+	m sync.Mutex
+	// includedBy maps each included file to the templ files that include it.
+	includedBy map[string]map[string]struct{}
+	// files maps each templ file to the files that it includes.
+	files map[string][]string
+}
+
+func newIncludes() *includes {
+	return &includes{
+		includedBy: make(map[string]map[string]struct{}),
+		files:      make(map[string][]string),
+	}
+}
+
+// set records the files included by the templ file, replacing those recorded before.
+func (inc *includes) set(templFileName string, names []string) {
+	if inc == nil {
+		return
+	}
+	templFileName = filepath.Clean(templFileName)
+	inc.m.Lock()
+	defer inc.m.Unlock()
+	for _, fileName := range inc.files[templFileName] {
+		delete(inc.includedBy[fileName], templFileName)
+		if len(inc.includedBy[fileName]) == 0 {
+			delete(inc.includedBy, fileName)
+		}
+	}
+	delete(inc.files, templFileName)
+	for _, name := range names {
+		fileName := includePath(templFileName, name)
+		if inc.includedBy[fileName] == nil {
+			inc.includedBy[fileName] = make(map[string]struct{})
+		}
+		inc.includedBy[fileName][templFileName] = struct{}{}
+		inc.files[templFileName] = append(inc.files[templFileName], fileName)
+	}
+}
+
+// templFiles returns the templ files that include the file.
+func (inc *includes) templFiles(fileName string) (templFileNames []string) {
+	if inc == nil {
+		return nil
+	}
+	inc.m.Lock()
+	defer inc.m.Unlock()
+	for templFileName := range inc.includedBy[filepath.Clean(fileName)] {
+		templFileNames = append(templFileNames, templFileName)
+	}
+	sort.Strings(templFileNames)
+	return templFileNames
+}
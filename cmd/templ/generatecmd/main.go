@@ -5,22 +5,30 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"go/format"
+	"go/build/constraint"
+	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "net/http/pprof"
 
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/a-h/templ/cmd/templ/diagnostic"
 	"github.com/a-h/templ/cmd/templ/generatecmd/proxy"
 	"github.com/a-h/templ/cmd/templ/generatecmd/run"
 	"github.com/a-h/templ/cmd/templ/visualize"
@@ -31,21 +39,90 @@ import (
 )
 
 type Arguments struct {
-	FileName                        string
-	Path                            string
-	Watch                           bool
-	Command                         string
-	ProxyPort                       int
-	Proxy                           string
+	FileName  string
+	Path      string
+	Watch     bool
+	Command   string
+	ProxyPort int
+	Proxy     string
+	// OpenBrowser opens the proxy in the browser, once the app is ready.
+	OpenBrowser                     bool
 	WorkerCount                     int
 	GenerateSourceMapVisualisations bool
+	// GenerateSourceMaps writes the source map of each generated file to <name>_templ.go.map.
+	GenerateSourceMaps bool
+	// IncludeLineDirectives writes //line directives in the generated code, so that stack
+	// traces and the compiler report positions in the templ files.
+	IncludeLineDirectives bool
+	// Minify removes HTML comments and whitespace that isn't rendered from the constant
+	// HTML in the generated code.
+	Minify bool
+	// FailFast stops generating code for more files after the first error.
+	FailFast bool
+	// Force generates code for all files, even if the generated code is up to date.
+	Force bool
+	// Clean removes code that was generated from templ files that no longer exist.
+	Clean bool
+	// StrictVersion returns an error, instead of a warning, if code generated by an
+	// incompatible version of templ is found.
+	StrictVersion bool
+	// OutDir is the directory that the generated code is written to, mirroring the directory
+	// tree under Path. If it's empty, generate.outDir in templ.json is used, or the code is
+	// written alongside each templ file.
+	OutDir string
+	// Suffix is added to the name of each templ file, without its .templ extension, to name
+	// the generated Go file. If it's empty, generate.suffix in templ.json is used, or _templ.go.
+	Suffix string
+	// BuildTags is a build constraint, e.g. "dev" or "!dev", written in a //go:build line in
+	// each generated file.
+	BuildTags string
+	// TagSuffix is added to the names of the generated Go files, before .go, e.g. "_dev"
+	// names the code generated from home.templ home_templ_dev.go, so that a variant of the
+	// code generated with BuildTags can be written alongside the default code.
+	TagSuffix string
 	// PPROFPort is the port to run the pprof server on.
 	PPROFPort int
+	// Log is written to with progress messages, and with errors if Diagnostics isn't set.
+	// Defaults to os.Stdout.
+	Log io.Writer
+	// Diagnostics, if it's set, is written to with the errors found in templ files, instead
+	// of Log.
+	Diagnostics *diagnostic.Encoder
 }
 
-var defaultWorkerCount = runtime.NumCPU()
+var defaultWorkerCount = runtime.GOMAXPROCS(0)
+
+// compileOptions are the options that apply to the generation of each file.
+type compileOptions struct {
+	generateSourceMapVisualisations bool
+	generateSourceMaps              bool
+	includeLineDirectives           bool
+	minify                          bool
+	// buildTags is the build constraint written in the generated code, if it's set.
+	buildTags string
+	// force generates the code, even if it's up to date.
+	force bool
+	// output names the generated files.
+	output config.Output
+	// log is written to with progress messages. Defaults to os.Stdout.
+	log io.Writer
+	// includes records the files included by each template, so that they can be watched.
+	includes *includes
+}
+
+// logf writes a progress message to the log.
+func (opts compileOptions) logf(format string, a ...any) {
+	w := opts.log
+	if w == nil {
+		w = os.Stdout
+	}
+	fmt.Fprintf(w, format, a...)
+}
 
 func Run(args Arguments) (err error) {
+	if args.Log == nil {
+		args.Log = os.Stdout
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt)
@@ -61,7 +138,7 @@ func Run(args Arguments) (err error) {
 	go func() {
 		select {
 		case <-signalChan: // First signal, cancel context.
-			fmt.Println("\nCancelling...")
+			fmt.Fprintln(args.Log, "\nCancelling...")
 			cancel()
 		case <-ctx.Done():
 		}
@@ -77,11 +154,49 @@ func Run(args Arguments) (err error) {
 
 func runCmd(ctx context.Context, args Arguments) (err error) {
 	start := time.Now()
+	if args.Log == nil {
+		args.Log = os.Stdout
+	}
 	if args.Watch && args.FileName != "" {
 		return fmt.Errorf("cannot watch a single file, remove the -f or -watch flag")
 	}
+	if !path.IsAbs(args.Path) {
+		args.Path, err = filepath.Abs(args.Path)
+		if err != nil {
+			return
+		}
+	}
+	output, err := outputConfig(args)
+	if err != nil {
+		return err
+	}
+	buildTags, err := buildConstraint(args.BuildTags)
+	if err != nil {
+		return err
+	}
+	opts := compileOptions{
+		generateSourceMapVisualisations: args.GenerateSourceMapVisualisations,
+		generateSourceMaps:              args.GenerateSourceMaps,
+		includeLineDirectives:           args.IncludeLineDirectives,
+		minify:                          args.Minify,
+		buildTags:                       buildTags,
+		force:                           args.Force,
+		output:                          output,
+		log:                             args.Log,
+	}
+	if args.Watch {
+		opts.includes = newIncludes()
+	}
 	if args.FileName != "" {
-		return processSingleFile(ctx, args.FileName, args.GenerateSourceMapVisualisations)
+		generated, err := processSingleFile(ctx, args.FileName, opts)
+		if err != nil && args.Diagnostics != nil {
+			reportErrors(args, []error{err})
+			return fmt.Errorf("failed to generate code for %q", args.FileName)
+		}
+		if err == nil && !generated {
+			fmt.Fprintf(args.Log, "Generated code for %q is up to date\n", args.FileName)
+		}
+		return err
 	}
 	var target *url.URL
 	if args.Proxy != "" {
@@ -97,72 +212,279 @@ func runCmd(ctx context.Context, args Arguments) (err error) {
 	if args.WorkerCount == 0 {
 		args.WorkerCount = defaultWorkerCount
 	}
-	if !path.IsAbs(args.Path) {
-		args.Path, err = filepath.Abs(args.Path)
-		if err != nil {
-			return
-		}
-	}
 
 	var p *proxy.Handler
 	if args.Proxy != "" {
 		p = proxy.New(args.ProxyPort, target)
 	}
 
-	fmt.Println("Processing path:", args.Path)
-	bo := backoff.NewExponentialBackOff()
-	bo.InitialInterval = time.Millisecond * 500
-	bo.MaxInterval = time.Second * 3
-	var firstRunComplete bool
-	fileNameToLastModTime := make(map[string]time.Time)
-	for !firstRunComplete || args.Watch {
-		changesFound, errs := processChanges(ctx, fileNameToLastModTime, args.Path, args.GenerateSourceMapVisualisations, args.WorkerCount)
-		if len(errs) > 0 {
-			if errors.Is(errs[0], context.Canceled) {
-				return errs[0]
-			}
-			if !args.Watch {
-				return fmt.Errorf("failed to process path: %v", errors.Join(errs...))
-			}
-			fmt.Printf("Error processing path: %v\n", errors.Join(errs...))
+	fmt.Fprintln(args.Log, "Processing path:", args.Path)
+	changesFound, upToDate, errs := processChanges(ctx, args.Path, opts, args.WorkerCount, args.FailFast)
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	reportErrors(args, errs)
+	if err = processGeneratedFiles(ctx, args.Log, output, args.Clean, args.StrictVersion); err != nil {
+		return err
+	}
+	if len(errs) > 0 && !args.Watch {
+		return fmt.Errorf("failed to generate code for %d of %d templates", len(errs), changesFound)
+	}
+	templErrs := make(templErrors)
+	templErrs.update(nil, errs)
+	var w *watcher
+	if args.Watch {
+		// The watcher is started before the command, so that changes made while the app
+		// starts aren't missed.
+		if w, err = newWatcher(args.Path, defaultDebounce); err != nil {
+			return err
 		}
+		w.log = args.Log
+		w.includes = opts.includes
+	}
+	if changesFound > 0 || args.Watch {
 		if changesFound > 0 {
-			fmt.Printf("Generated code for %d templates with %d errors in %s\n", changesFound, len(errs), time.Since(start))
-			if args.Command != "" {
-				fmt.Printf("Executing command: %s\n", args.Command)
-				if _, err := run.Run(ctx, args.Path, args.Command); err != nil {
-					fmt.Printf("Error starting command: %v\n", err)
+			fmt.Fprintf(args.Log, "Generated code for %d templates with %d errors, %d up to date, in %s\n", changesFound-upToDate, len(errs), upToDate, time.Since(start))
+		}
+		if p != nil {
+			go func() {
+				fmt.Fprintf(args.Log, "Proxying from %s to target: %s\n", p.URL, p.Target.String())
+				if err := http.ListenAndServe(fmt.Sprintf("127.0.0.1:%d", args.ProxyPort), p); err != nil {
+					fmt.Fprintf(args.Log, "Error starting proxy: %v\n", err)
 				}
-				// Send server-sent event.
-				if p != nil {
-					p.SendSSE("message", "reload")
+			}()
+		}
+		// The app is started even if there are errors, because it's not running yet, and
+		// the code generated from the templates that can't be parsed may still work.
+		runCommand(ctx, args, p, templErrs.list())
+		if p != nil && args.OpenBrowser {
+			go func() {
+				fmt.Fprintf(args.Log, "Opening URL: %s\n", p.Target.String())
+				if err := openURL(args.Log, p.URL); err != nil {
+					fmt.Fprintf(args.Log, "Error opening URL: %v\n", err)
+				}
+			}()
+		}
+	}
+	if !args.Watch {
+		return nil
+	}
+
+	fmt.Fprintln(args.Log, "Watching for changes:", args.Path)
+	return w.Run(ctx, func(fileNames []string) {
+		start := time.Now()
+		changesFound, upToDate, errs := processFiles(ctx, fileNames, opts, args.WorkerCount, args.FailFast)
+		reportErrors(args, errs)
+		templErrs.update(fileNames, errs)
+		// Saving a file without changing it doesn't need the command to be run again.
+		if changesFound <= upToDate {
+			return
+		}
+		fmt.Fprintf(args.Log, "Processed %d changed templates with %d errors in %s\n", changesFound, len(errs), time.Since(start))
+		// The app isn't restarted until the errors are fixed, so that the browser shows the
+		// errors, rather than reloading into a broken app.
+		if errs := templErrs.list(); len(errs) > 0 {
+			if p != nil {
+				fmt.Fprintln(args.Log, "Showing errors in the browser")
+				p.ShowErrors(errs)
+			}
+			return
+		}
+		runCommand(ctx, args, p, nil)
+	})
+}
+
+// templErrors are the errors found in each templ file, so that the errors in all of the
+// files are shown in the browser, not only the errors in the files that changed last.
+type templErrors map[string][]proxy.Error
+
+// update replaces the errors of the files that were processed with the errors found.
+func (te templErrors) update(fileNames []string, errs []error) {
+	for _, fileName := range fileNames {
+		delete(te, fileName)
+	}
+	// Errors that aren't in a file, e.g. a cancelled context, only apply to the last update.
+	delete(te, "")
+	for _, err := range errs {
+		for _, d := range diagnostic.FromError(err) {
+			te[d.File] = append(te[d.File], proxy.Error{Diagnostic: d, Source: sourceLine(d.File, d.Line)})
+		}
+	}
+}
+
+// list returns the errors, ordered by file name.
+func (te templErrors) list() (errs []proxy.Error) {
+	fileNames := make([]string, 0, len(te))
+	for fileName := range te {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		errs = append(errs, te[fileName]...)
+	}
+	return errs
+}
+
+// sourceLine returns the line of the file, where the first line is 1, or an empty string
+// if it can't be read.
+func sourceLine(fileName string, line int) string {
+	if fileName == "" || line < 1 {
+		return ""
+	}
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(src), "\n")
+	if line > len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[line-1], "\r")
+}
+
+// outputConfig returns the naming of the generated files, from the templ.json file that
+// applies to the path, overridden by the arguments.
+func outputConfig(args Arguments) (o config.Output, err error) {
+	c, err := config.Load(args.Path)
+	if err != nil {
+		return o, err
+	}
+	o = c.Output()
+	if args.OutDir != "" {
+		if o.OutDir, err = filepath.Abs(args.OutDir); err != nil {
+			return o, err
+		}
+	}
+	if args.Suffix != "" {
+		o.Suffix = args.Suffix
+	}
+	if args.TagSuffix != "" {
+		o.Suffix = strings.TrimSuffix(o.Suffix, ".go") + args.TagSuffix + ".go"
+	}
+	return o, o.Validate()
+}
+
+// buildConstraint returns the build constraint in its canonical form, e.g. "dev && !prod",
+// or an error if it isn't a valid constraint.
+func buildConstraint(tags string) (string, error) {
+	if strings.TrimSpace(tags) == "" {
+		return "", nil
+	}
+	expr, err := constraint.Parse("//go:build " + tags)
+	if err != nil {
+		return "", fmt.Errorf("invalid build tags %q: %w", tags, err)
+	}
+	return expr.String(), nil
+}
+
+// processGeneratedFiles checks the generated code after generation. The code generated for
+// templ files that no longer exist is reported, or removed if clean is set. Code generated
+// by an incompatible version of templ, e.g. because its templ file couldn't be parsed, is
+// reported, and is an error if strictVersion is set.
+func processGeneratedFiles(ctx context.Context, log io.Writer, output config.Output, clean, strictVersion bool) error {
+	files, err := generatedFiles(ctx, output)
+	if err != nil {
+		return fmt.Errorf("failed to check generated files: %w", err)
+	}
+	version := generatorVersion()
+	var incompatible int
+	for _, f := range files {
+		if !fileExists(f.templFileName) {
+			if clean {
+				if err = removeGeneratedFiles(log, f.templFileName, output); err != nil {
+					fmt.Fprintln(log, err)
 				}
+				continue
 			}
-			if !firstRunComplete && p != nil {
-				go func() {
-					fmt.Printf("Proxying from %s to target: %s\n", p.URL, p.Target.String())
-					if err := http.ListenAndServe(fmt.Sprintf("127.0.0.1:%d", args.ProxyPort), p); err != nil {
-						fmt.Printf("Error starting proxy: %v\n", err)
-					}
-				}()
-				go func() {
-					fmt.Printf("Opening URL: %s\n", p.Target.String())
-					if err := openURL(p.URL); err != nil {
-						fmt.Printf("Error opening URL: %v\n", err)
-					}
-				}()
+			fmt.Fprintf(log, "%s: generated from %s, which doesn't exist, use -clean to remove it\n", f.fileName, f.templFileName)
+			continue
+		}
+		if !isCompatibleVersion(f.header.Version, version) {
+			generatedBy := "templ " + f.header.Version
+			if f.header.Version == "" {
+				generatedBy = "an early version of templ"
 			}
+			fmt.Fprintf(log, "%s: generated by %s, which isn't compatible with templ %s\n", f.fileName, generatedBy, version)
+			incompatible++
 		}
-		if firstRunComplete {
-			if changesFound > 0 {
-				bo.Reset()
+	}
+	if incompatible > 0 && strictVersion {
+		return fmt.Errorf("found %d files generated by an incompatible version of templ", incompatible)
+	}
+	return nil
+}
+
+// runCommand runs the command set with -cmd, if there is one. Once the app is ready, the
+// pages loaded through the proxy are reloaded, or, if there are errors in the templates,
+// the errors are shown in the pages instead.
+func runCommand(ctx context.Context, args Arguments, p *proxy.Handler, templErrs []proxy.Error) {
+	var cmd *exec.Cmd
+	var exited <-chan struct{}
+	if args.Command != "" {
+		fmt.Fprintf(args.Log, "Executing command: %s\n", args.Command)
+		var err error
+		if cmd, exited, err = run.Run(ctx, args.Path, args.Command); err != nil {
+			fmt.Fprintf(args.Log, "Error starting command: %v\n", err)
+			templErrs = append(templErrs, commandError(fmt.Errorf("failed to start %q: %w", args.Command, err)))
+		}
+	}
+	if p == nil {
+		return
+	}
+	if cmd != nil {
+		if err := waitForTarget(ctx, p.Target, cmd, exited); err != nil {
+			if ctx.Err() != nil {
+				return
 			}
-			time.Sleep(bo.NextBackOff())
+			fmt.Fprintln(args.Log, err)
+			templErrs = append(templErrs, commandError(err))
+		}
+	}
+	if len(templErrs) > 0 {
+		p.ShowErrors(templErrs)
+		return
+	}
+	p.Reload()
+}
+
+// commandError returns an error about the command to show in the browser.
+func commandError(err error) proxy.Error {
+	return proxy.Error{Diagnostic: diagnostic.Diagnostic{Severity: diagnostic.SeverityError, Code: diagnostic.CodeError, Message: err.Error()}}
+}
+
+// targetTimeout is how long to wait for the app to respond after it's started, before the
+// pages are reloaded anyway.
+const targetTimeout = 30 * time.Second
+
+// waitForTarget waits until the app started by the command responds to requests, so that
+// pages aren't reloaded before it's ready. It returns an error if the command fails before
+// then, e.g. because the app doesn't compile.
+func waitForTarget(ctx context.Context, target *url.URL, cmd *exec.Cmd, exited <-chan struct{}) error {
+	client := http.Client{Timeout: time.Second}
+	timeout := time.NewTimer(targetTimeout)
+	defer timeout.Stop()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+			return nil
+		}
+		select {
+		case <-exited:
+			if !cmd.ProcessState.Success() {
+				return fmt.Errorf("command %q failed: %v, see the terminal for its output", strings.Join(cmd.Args, " "), cmd.ProcessState)
+			}
+			return nil
+		case <-timeout.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
 		}
-		firstRunComplete = true
-		start = time.Now()
 	}
-	return err
 }
 
 func shouldSkipDir(dir string) bool {
@@ -180,10 +502,9 @@ func shouldSkipDir(dir string) bool {
 	return false
 }
 
-func processChanges(ctx context.Context, fileNameToLastModTime map[string]time.Time, path string, generateSourceMapVisualisations bool, maxWorkerCount int) (changesFound int, errs []error) {
-	sem := make(chan struct{}, maxWorkerCount)
-	var wg sync.WaitGroup
-
+// processChanges generates code for all of the templ files within path.
+func processChanges(ctx context.Context, path string, opts compileOptions, maxWorkerCount int, failFast bool) (changesFound, upToDate int, errs []error) {
+	var fileNames []string
 	err := filepath.WalkDir(path, func(path string, info os.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -194,43 +515,114 @@ func processChanges(ctx context.Context, fileNameToLastModTime map[string]time.T
 		if info.IsDir() && shouldSkipDir(path) {
 			return filepath.SkipDir
 		}
-		if info.IsDir() {
-			return nil
-		}
-		if strings.HasSuffix(path, ".templ") {
-			lastModTime := fileNameToLastModTime[path]
-			fileInfo, err := info.Info()
-			if err != nil {
-				return fmt.Errorf("failed to get file info: %w", err)
-			}
-			if fileInfo.ModTime().After(lastModTime) {
-				fileNameToLastModTime[path] = fileInfo.ModTime()
-				changesFound++
-
-				// Start a processor, but limit to maxWorkerCount.
-				sem <- struct{}{}
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-					if err := processSingleFile(ctx, path, generateSourceMapVisualisations); err != nil {
-						errs = append(errs, err)
-					}
-					<-sem
-				}()
-			}
+		if !info.IsDir() && strings.HasSuffix(path, ".templ") {
+			fileNames = append(fileNames, path)
 		}
 		return nil
 	})
 	if err != nil {
-		errs = append(errs, err)
+		return 0, 0, []error{err}
 	}
+	return processFiles(ctx, fileNames, opts, maxWorkerCount, failFast)
+}
 
+// processFiles regenerates the code for the templ files using maxWorkerCount workers. If a
+// templ file has been removed, the code that was generated from it is removed too. The
+// errors are returned in the same order as the file names, so that the output is the same
+// each time. If failFast is set, files aren't processed after the first error. Files whose
+// generated code is up to date are counted in upToDate.
+func processFiles(ctx context.Context, fileNames []string, opts compileOptions, maxWorkerCount int, failFast bool) (changesFound, upToDate int, errs []error) {
+	sem := make(chan struct{}, maxWorkerCount)
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	var skipped atomic.Int64
+	fileErrs := make([]error, len(fileNames))
+	for i, fileName := range fileNames {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		if failFast && failed.Load() {
+			<-sem
+			break
+		}
+		changesFound++
+		i, fileName := i, fileName
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			var err error
+			if _, statErr := os.Stat(fileName); errors.Is(statErr, fs.ErrNotExist) {
+				opts.includes.set(fileName, nil)
+				err = removeGeneratedFiles(opts.log, fileName, opts.output)
+			} else {
+				var generated bool
+				generated, err = processSingleFile(ctx, fileName, opts)
+				if err == nil && !generated {
+					skipped.Add(1)
+				}
+			}
+			if err != nil {
+				fileErrs[i] = err
+				failed.Store(true)
+			}
+		}()
+	}
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return changesFound, int(skipped.Load()), []error{err}
+	}
+	for _, err := range fileErrs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return changesFound, int(skipped.Load()), errs
+}
 
-	return changesFound, errs
+// removeGeneratedFiles removes the Go code and source map generated from a templ file that
+// has been deleted. Go files that weren't generated by templ aren't removed.
+func removeGeneratedFiles(log io.Writer, templFileName string, output config.Output) error {
+	targetFileName, err := output.GoFileName(templFileName)
+	if err != nil {
+		return err
+	}
+	generated, err := isGeneratedFile(targetFileName)
+	if err != nil {
+		return fmt.Errorf("%s read file error: %w", targetFileName, err)
+	}
+	if !generated {
+		return nil
+	}
+	for _, fileName := range []string{targetFileName, targetFileName + ".map"} {
+		if err = os.Remove(fileName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s remove file error: %w", fileName, err)
+		}
+	}
+	if log == nil {
+		log = os.Stdout
+	}
+	fmt.Fprintf(log, "Removed %q, because %q was deleted\n", targetFileName, templFileName)
+	return nil
 }
 
-func openURL(url string) error {
+// isGeneratedFile returns true if the Go file exists, and starts with the comment that
+// templ adds to generated code.
+func isGeneratedFile(fileName string) (ok bool, err error) {
+	f, err := os.Open(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	_, ok = generator.ReadHeader(f)
+	return ok, nil
+}
+
+func openURL(log io.Writer, url string) error {
 	backoff := backoff.NewExponentialBackOff()
 	backoff.InitialInterval = time.Second
 	var client http.Client
@@ -240,52 +632,198 @@ func openURL(url string) error {
 			break
 		}
 		d := backoff.NextBackOff()
-		fmt.Printf("Server not ready. Retrying in %v...\n", d)
+		fmt.Fprintf(log, "Server not ready. Retrying in %v...\n", d)
 		time.Sleep(d)
 	}
 	return browser.OpenURL(url)
 }
 
-func processSingleFile(ctx context.Context, fileName string, generateSourceMapVisualisations bool) error {
+// processSingleFile generates the code for the templ file, and returns false if it was
+// already up to date.
+func processSingleFile(ctx context.Context, fileName string, opts compileOptions) (generated bool, err error) {
 	start := time.Now()
-	err := compile(ctx, fileName, generateSourceMapVisualisations)
-	if err != nil {
-		return err
+	generated, err = compile(ctx, fileName, opts)
+	if err != nil || !generated {
+		return generated, err
 	}
-	fmt.Printf("Generated code for %q in %s\n", fileName, time.Since(start))
-	return err
+	opts.logf("Generated code for %q in %s\n", fileName, time.Since(start))
+	return true, nil
 }
 
-func compile(ctx context.Context, fileName string, generateSourceMapVisualisations bool) (err error) {
+func compile(ctx context.Context, fileName string, opts compileOptions) (generated bool, err error) {
 	if err = ctx.Err(); err != nil {
 		return
 	}
 
-	t, err := parser.Parse(fileName)
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return false, fmt.Errorf("%s read file error: %w", fileName, err)
+	}
+	targetFileName, err := opts.output.GoFileName(fileName)
+	if err != nil {
+		return false, err
+	}
+	errorFileName, err := errorFileName(fileName, targetFileName)
+	if err != nil {
+		return false, err
+	}
+	version := generatorVersion()
+	// The files included by the template are listed in the header of the generated code, so
+	// that changes to them are found without parsing the template.
+	existing, ok := readHeader(targetFileName)
+	if ok && !opts.force && !isDevelopmentVersion(version) {
+		hash := sourceHash(version, opts, errorFileName, src, readIncludes(fileName, existing.Includes))
+		if isUpToDate(targetFileName, existing, hash, opts) {
+			opts.includes.set(fileName, existing.Includes)
+			return false, nil
+		}
+	}
+	t, err := parser.ParseString(string(src))
 	if err != nil {
-		return fmt.Errorf("%s parsing error: %w", fileName, err)
+		// Each error in the file is reported as path:line:col: message.
+		return false, parser.FileError{FileName: fileName, Err: err}
 	}
-	targetFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	includes := parser.Includes(t)
+	opts.includes.set(fileName, includes)
+	hash := sourceHash(version, opts, errorFileName, src, readIncludes(fileName, includes))
 
+	generatorOpts := generator.GenerateOpts{
+		FileName:   errorFileName,
+		SourceHash: hash,
+		BuildTags:  opts.buildTags,
+		Minify:     opts.minify,
+		IncludeDir: filepath.Dir(fileName),
+	}
+	if opts.includeLineDirectives {
+		// The file name in a //line directive is relative to the directory of the Go file.
+		if generatorOpts.LineDirectiveFileName, err = relativeFileName(filepath.Dir(targetFileName), fileName); err != nil {
+			return false, err
+		}
+		generatorOpts.LineDirectiveGoFileName = filepath.Base(targetFileName)
+	}
 	var b bytes.Buffer
-	sourceMap, err := generator.Generate(t, &b)
+	result, err := generator.GenerateFile(generatorOpts, t, &b)
 	if err != nil {
-		return fmt.Errorf("%s generation error: %w", fileName, err)
+		return false, generateError{fileName: fileName, err: err}
+	}
+	if result.FormatError != nil {
+		return false, generateError{fileName: fileName, err: fmt.Errorf("source formatting error: %w", result.FormatError)}
+	}
+	data, sourceMap := b.Bytes(), result.SourceMap
+
+	if opts.output.OutDir != "" {
+		if err = os.MkdirAll(filepath.Dir(targetFileName), 0755); err != nil {
+			return false, fmt.Errorf("%s create directory error: %w", targetFileName, err)
+		}
+	}
+	if err = os.WriteFile(targetFileName, data, 0644); err != nil {
+		return false, fmt.Errorf("%s write file error: %w", targetFileName, err)
+	}
+
+	if opts.generateSourceMaps {
+		sourceMapFileName := targetFileName + ".map"
+		if err = writeSourceMap(sourceMapFileName, sourceMap); err != nil {
+			return false, fmt.Errorf("%s write file error: %w", sourceMapFileName, err)
+		}
+	}
+
+	if opts.generateSourceMapVisualisations {
+		if err = generateSourceMapVisualisation(ctx, fileName, targetFileName, sourceMap); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// generateError is an error found while generating the code for a templ file that was
+// parsed.
+type generateError struct {
+	fileName string
+	err      error
+}
+
+func (e generateError) Error() string {
+	var ge generator.Error
+	if errors.As(e.err, &ge) {
+		return fmt.Sprintf("%s:%d:%d: %v", e.fileName, ge.Range.From.Line+1, ge.Range.From.Col+1, ge.Err)
+	}
+	return fmt.Sprintf("%s generation error: %v", e.fileName, e.err)
+}
+
+func (e generateError) Unwrap() error {
+	return e.err
+}
+
+// Diagnostics returns the error as a diagnostic, at the position of the declaration that
+// caused it, if it's known.
+func (e generateError) Diagnostics() []diagnostic.Diagnostic {
+	var ge generator.Error
+	if errors.As(e.err, &ge) {
+		return []diagnostic.Diagnostic{diagnostic.FromRange(e.fileName, parser.Range{From: ge.Range.From, To: ge.Range.From}, diagnostic.SeverityError, diagnostic.CodeGenerateError, ge.Err.Error())}
 	}
+	return []diagnostic.Diagnostic{{File: e.fileName, Severity: diagnostic.SeverityError, Code: diagnostic.CodeGenerateError, Message: e.err.Error()}}
+}
 
-	data, err := format.Source(b.Bytes())
+// reportErrors writes the errors to the log, or as diagnostics if they're enabled. The
+// errors are in path order, and in the log they start with path:line:col, so that editors
+// can jump to them.
+func reportErrors(args Arguments, errs []error) {
+	for _, err := range errs {
+		if args.Diagnostics == nil {
+			fmt.Fprintln(args.Log, err)
+			continue
+		}
+		if encErr := args.Diagnostics.Encode(diagnostic.FromError(err)...); encErr != nil {
+			fmt.Fprintln(args.Log, encErr)
+		}
+	}
+}
+
+// relativeFileName returns the path of fileName relative to dir, using forward slashes.
+// errorFileName returns the templ file name that's written in the errors returned by the
+// generated code. It's relative to the root of the Go module, so that it identifies the file
+// within the module, whichever directory is passed to templ generate. Outside of a module, it's
+// relative to the directory of the Go file, like the file name in //line directives.
+func errorFileName(templFileName, goFileName string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(templFileName))
 	if err != nil {
-		return fmt.Errorf("%s source formatting error: %w", fileName, err)
+		return "", err
 	}
+	for d := dir; ; {
+		if _, err = os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return relativeFileName(d, templFileName)
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+	return relativeFileName(filepath.Dir(goFileName), templFileName)
+}
 
-	if err = os.WriteFile(targetFileName, data, 0644); err != nil {
-		return fmt.Errorf("%s write file error: %w", targetFileName, err)
+func relativeFileName(dir, fileName string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
 	}
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
 
-	if generateSourceMapVisualisations {
-		err = generateSourceMapVisualisation(ctx, fileName, targetFileName, sourceMap)
+func writeSourceMap(fileName string, sm *parser.SourceMap) (err error) {
+	data, err := json.Marshal(sm)
+	if err != nil {
+		return err
 	}
-	return
+	return os.WriteFile(fileName, data, 0644)
 }
 
 func generateSourceMapVisualisation(ctx context.Context, templFileName, goFileName string, sourceMap *parser.SourceMap) error {
@@ -312,7 +850,7 @@ func generateSourceMapVisualisation(ctx context.Context, templFileName, goFileNa
 		return templErr
 	}
 
-	targetFileName := strings.TrimSuffix(templFileName, ".templ") + "_templ_sourcemap.html"
+	targetFileName := strings.TrimSuffix(goFileName, ".go") + "_sourcemap.html"
 	w, err := os.Create(targetFileName)
 	if err != nil {
 		return fmt.Errorf("%s sourcemap visualisation error: %w", templFileName, err)
@@ -0,0 +1,346 @@
+package generatecmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ/cmd/templ/diagnostic"
+	"github.com/a-h/templ/cmd/templ/generatecmd/proxy"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	validTemplate   = "package %s\n\ntempl Template%d(name string) {\n\t<div class=\"a\">\n\t\t<p>Hello, { name }</p>\n\t</div>\n}\n"
+	invalidTemplate = "package %s\n\ntempl Template%d() {\n\t<div>\n}\n"
+)
+
+// writeTemplates writes count templ files to dir, in directories of 100 files. The files
+// with the indexes in invalid can't be parsed.
+func writeTemplates(t testing.TB, dir string, count int, invalid ...int) (fileNames []string) {
+	t.Helper()
+	isInvalid := make(map[int]bool)
+	for _, i := range invalid {
+		isInvalid[i] = true
+	}
+	for i := 0; i < count; i++ {
+		pkg := fmt.Sprintf("pkg%02d", i/100)
+		if err := os.MkdirAll(filepath.Join(dir, pkg), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		format := validTemplate
+		if isInvalid[i] {
+			format = invalidTemplate
+		}
+		fileName := filepath.Join(dir, pkg, fmt.Sprintf("template%04d.templ", i))
+		if err := os.WriteFile(fileName, []byte(fmt.Sprintf(format, pkg, i)), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	return fileNames
+}
+
+func TestProcessChanges(t *testing.T) {
+	t.Run("errors are returned in path order", func(t *testing.T) {
+		dir := t.TempDir()
+		fileNames := writeTemplates(t, dir, 50, 3, 17, 42)
+		changesFound, _, errs := processChanges(context.Background(), dir, compileOptions{}, 8, false)
+		if changesFound != 50 {
+			t.Errorf("expected 50 changes, got %d", changesFound)
+		}
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 errors, got %v", errs)
+		}
+		for i, index := range []int{3, 17, 42} {
+			expectedPrefix := fileNames[index] + ":5:1: "
+			if !strings.HasPrefix(errs[i].Error(), expectedPrefix) {
+				t.Errorf("expected error %d to start with %q, got %q", i, expectedPrefix, errs[i].Error())
+			}
+		}
+	})
+	t.Run("files after an error are not processed if failFast is set", func(t *testing.T) {
+		dir := t.TempDir()
+		fileNames := writeTemplates(t, dir, 10, 2, 5)
+		changesFound, _, errs := processChanges(context.Background(), dir, compileOptions{}, 1, true)
+		if changesFound != 3 {
+			t.Errorf("expected 3 changes, got %d", changesFound)
+		}
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %v", errs)
+		}
+		for i, fileName := range fileNames {
+			_, err := os.Stat(strings.TrimSuffix(fileName, ".templ") + "_templ.go")
+			if generated := err == nil; generated != (i < 2) {
+				t.Errorf("%s: expected generated=%v, got %v", fileName, i < 2, generated)
+			}
+		}
+	})
+}
+
+func BenchmarkProcessChanges(b *testing.B) {
+	dir := b.TempDir()
+	writeTemplates(b, dir, 1000)
+	discardStdout(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, errs := processChanges(context.Background(), dir, compileOptions{force: true}, defaultWorkerCount, false); len(errs) > 0 {
+			b.Fatalf("unexpected errors: %v", errs)
+		}
+	}
+}
+
+func BenchmarkProcessChangesUpToDate(b *testing.B) {
+	setGeneratorVersion(b, "v0.0.2")
+	dir := b.TempDir()
+	writeTemplates(b, dir, 1000)
+	discardStdout(b)
+	if _, _, errs := processChanges(context.Background(), dir, compileOptions{}, defaultWorkerCount, false); len(errs) > 0 {
+		b.Fatalf("unexpected errors: %v", errs)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		changesFound, upToDate, errs := processChanges(context.Background(), dir, compileOptions{}, defaultWorkerCount, false)
+		if len(errs) > 0 {
+			b.Fatalf("unexpected errors: %v", errs)
+		}
+		if upToDate != changesFound {
+			b.Fatalf("expected all %d files to be up to date, got %d", changesFound, upToDate)
+		}
+	}
+}
+
+// discardStdout discards the output printed for each generated file until the benchmark ends.
+func discardStdout(b *testing.B) {
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+func TestJSONDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/test\n\ngo 1.20\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	files := map[string]string{
+		"a.templ": "package main\n\ntempl A() {\n\t<div>\n}\n",
+		"b.templ": "package main\n\ntempl B(x int) {\n\tif x > {\n\t\t<p>x</p>\n\t}\n}\n",
+		"c.templ": "package main\n\ntempl C() {\n\t<p>C</p>\n}\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	var stdout, log bytes.Buffer
+	err := runCmd(context.Background(), Arguments{
+		Path:        dir,
+		Log:         &log,
+		Diagnostics: diagnostic.NewEncoder(&stdout),
+	})
+	if err == nil {
+		t.Error("expected an error")
+	}
+	var actual []diagnostic.Diagnostic
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		for _, field := range []string{"file", "line", "col", "endLine", "endCol", "severity", "code", "message"} {
+			if _, ok := fields[field]; !ok {
+				t.Errorf("expected field %q in %s", field, line)
+			}
+		}
+		var d diagnostic.Diagnostic
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		actual = append(actual, d)
+	}
+	if len(actual) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %#v", actual)
+	}
+	expected := diagnostic.Diagnostic{
+		File:     filepath.Join(dir, "a.templ"),
+		Line:     5,
+		Col:      1,
+		EndLine:  5,
+		EndCol:   1,
+		Severity: diagnostic.SeverityError,
+		Code:     diagnostic.CodeParseError,
+		Message:  "<div>: expected end tag not present or invalid tag contents",
+	}
+	if diff := cmp.Diff(expected, actual[0]); diff != "" {
+		t.Error(diff)
+	}
+	if actual[1].File != filepath.Join(dir, "b.templ") || actual[1].Code != diagnostic.CodeGenerateError {
+		t.Errorf("expected a generate error for b.templ, got %#v", actual[1])
+	}
+	if !strings.Contains(log.String(), "Processing path:") {
+		t.Errorf("expected progress to be written to the log, got %q", log.String())
+	}
+}
+
+func TestGenerateErrorDiagnostics(t *testing.T) {
+	err := generateError{
+		fileName: "a.templ",
+		err: generator.Error{
+			Err:   errors.New("invalid template"),
+			Range: parser.Range{From: parser.Position{Line: 2, Col: 6}, To: parser.Position{Line: 2, Col: 7}},
+		},
+	}
+	if err.Error() != "a.templ:3:7: invalid template" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	expected := []diagnostic.Diagnostic{{
+		File:     "a.templ",
+		Line:     3,
+		Col:      7,
+		EndLine:  3,
+		EndCol:   7,
+		Severity: diagnostic.SeverityError,
+		Code:     diagnostic.CodeGenerateError,
+		Message:  "invalid template",
+	}}
+	if diff := cmp.Diff(expected, diagnostic.FromError(err)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTemplErrors(t *testing.T) {
+	dir := t.TempDir()
+	fileNames := writeTemplates(t, dir, 3, 0, 2)
+	_, _, errs := processFiles(context.Background(), fileNames, compileOptions{log: io.Discard}, 1, false)
+	te := make(templErrors)
+	te.update(nil, errs)
+	actual := te.list()
+	if len(actual) != 2 || actual[0].File != fileNames[0] || actual[1].File != fileNames[2] {
+		t.Fatalf("expected errors in %v, got %#v", []string{fileNames[0], fileNames[2]}, actual)
+	}
+	if actual[0].Line != 5 || actual[0].Source != "}" {
+		t.Errorf("expected the error to be on line 5, with its source, got %#v", actual[0])
+	}
+
+	// Processing one of the files again only replaces its errors.
+	if err := os.WriteFile(fileNames[2], []byte(fmt.Sprintf(validTemplate, "pkg00", 2)), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	_, _, errs = processFiles(context.Background(), fileNames[2:], compileOptions{log: io.Discard}, 1, false)
+	te.update(fileNames[2:], errs)
+	if actual = te.list(); len(actual) != 1 || actual[0].File != fileNames[0] {
+		t.Errorf("expected the error in %s to be kept, got %#v", fileNames[0], actual)
+	}
+}
+
+func TestErrorFileName(t *testing.T) {
+	dir := t.TempDir()
+	templFileName := filepath.Join(dir, "views", "home.templ")
+	goFileName := filepath.Join(dir, "out", "views", "home_templ.go")
+
+	// Outside of a module, the file name is relative to the directory of the Go file.
+	actual, err := errorFileName(templFileName, goFileName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "../../views/home.templ"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	// Within a module, it's relative to the root of the module.
+	if err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	actual, err = errorFileName(templFileName, goFileName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "views/home.templ"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestWatchShowsErrorsInTheBrowser(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "a.templ")
+	writeTemplate := func(t *testing.T, contents string) {
+		t.Helper()
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	writeTemplate(t, "package a\n\ntempl A() {\n\t<p>A</p>\n}\n")
+
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html><body></body></html>")
+	}))
+	defer app.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- runCmd(ctx, Arguments{Path: dir, Watch: true, Proxy: app.URL, ProxyPort: port, Log: io.Discard})
+	}()
+	defer func() {
+		cancel()
+		<-runErr
+	}()
+
+	// waitForErrors waits until the proxy provides errors for which ok returns true.
+	waitForErrors := func(t *testing.T, ok func([]proxy.Error) bool) (errs []proxy.Error) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			if resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/_templ/reload/errors", port)); err == nil {
+				errs = nil
+				err = json.NewDecoder(resp.Body).Decode(&errs)
+				resp.Body.Close()
+				if err == nil && ok(errs) {
+					return errs
+				}
+			}
+			select {
+			case <-timeout:
+				t.Fatalf("timed out waiting for errors, got %#v", errs)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+	hasErrors := func(errs []proxy.Error) bool { return len(errs) > 0 }
+	noErrors := func(errs []proxy.Error) bool { return len(errs) == 0 }
+
+	waitForErrors(t, noErrors)
+	writeTemplate(t, "package a\n\ntempl A() {\n\t<p>A</div>\n}\n")
+	errs := waitForErrors(t, hasErrors)
+	if errs[0].File != fileName || errs[0].Line != 4 || errs[0].Source != "\t<p>A</div>" {
+		t.Errorf("expected an error on line 4 of %s, got %#v", fileName, errs[0])
+	}
+	writeTemplate(t, "package a\n\ntempl A() {\n\t<p>A</p>\n}\n")
+	waitForErrors(t, noErrors)
+}
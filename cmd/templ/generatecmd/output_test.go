@@ -0,0 +1,198 @@
+package generatecmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputLayouts(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+	templ, err := filepath.Abs("../../..")
+	if err != nil {
+		t.Fatalf("failed to get module root: %v", err)
+	}
+	tests := []struct {
+		name string
+		args Arguments
+		// templJSON is written to the root of the module, if it's set.
+		templJSON string
+		// goFileName is the generated file, relative to the module.
+		goFileName string
+		// pkg is the import path of the package that contains the generated code.
+		pkg string
+		// lineDirective is the path of the templ file in the //line directives.
+		lineDirective string
+	}{
+		{
+			name:          "code is generated alongside the templ files by default",
+			goFileName:    "views/page_templ.go",
+			pkg:           "example.com/app/views",
+			lineDirective: "page.templ",
+		},
+		{
+			name:          "code is generated in the out-dir, with the suffix, using arguments",
+			args:          Arguments{OutDir: "gen", Suffix: ".templ.go"},
+			goFileName:    "gen/views/page.templ.go",
+			pkg:           "example.com/app/gen/views",
+			lineDirective: "../../views/page.templ",
+		},
+		{
+			name:          "code is generated in the out-dir, with the suffix, using templ.json",
+			templJSON:     `{"generate": {"outDir": "gen", "suffix": ".templ.go"}}`,
+			goFileName:    "gen/views/page.templ.go",
+			pkg:           "example.com/app/gen/views",
+			lineDirective: "../../views/page.templ",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]string{
+				"go.mod":           "module example.com/app\n\ngo 1.20\n\nrequire github.com/a-h/templ v0.0.0\n\nreplace github.com/a-h/templ => " + templ + "\n",
+				"views/page.templ": "package views\n\ntempl Page(name string) {\n\t<p>Hello, { name }</p>\n}\n",
+				"main.go":          "package main\n\nimport (\n\t\"context\"\n\t\"os\"\n\n\t\"" + tt.pkg + "\"\n)\n\nfunc main() {\n\tviews.Page(\"World\").Render(context.Background(), os.Stdout)\n}\n",
+			}
+			if tt.templJSON != "" {
+				files["templ.json"] = tt.templJSON
+			}
+			for name, contents := range files {
+				fileName := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+					t.Fatalf("failed to write file: %v", err)
+				}
+			}
+
+			args := tt.args
+			args.Path = dir
+			if args.OutDir != "" {
+				args.OutDir = filepath.Join(dir, args.OutDir)
+			}
+			args.IncludeLineDirectives = true
+			if err := runCmd(context.Background(), args); err != nil {
+				t.Fatalf("failed to generate code: %v", err)
+			}
+			code, err := os.ReadFile(filepath.Join(dir, tt.goFileName))
+			if err != nil {
+				t.Fatalf("expected code to be generated: %v", err)
+			}
+			if !strings.Contains(string(code), "//line "+tt.lineDirective+":") {
+				t.Errorf("expected //line directives to refer to %q, got:\n%s", tt.lineDirective, code)
+			}
+
+			cmd := exec.Command("go", "run", ".")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("failed to run the generated code: %v\n%s", err, output)
+			}
+			if string(output) != "<p>Hello, World</p>" {
+				t.Errorf("unexpected output: %q", output)
+			}
+
+			// Removing the templ file removes the code generated from it.
+			if err := os.Remove(filepath.Join(dir, "views/page.templ")); err != nil {
+				t.Fatalf("failed to remove file: %v", err)
+			}
+			args.Clean = true
+			if err := runCmd(context.Background(), args); err != nil {
+				t.Fatalf("failed to generate code: %v", err)
+			}
+			if fileExists(filepath.Join(dir, tt.goFileName)) {
+				t.Errorf("expected %s to be removed", tt.goFileName)
+			}
+		})
+	}
+}
+
+func TestBuildTagVariants(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+	templ, err := filepath.Abs("../../..")
+	if err != nil {
+		t.Fatalf("failed to get module root: %v", err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.20\n\nrequire github.com/a-h/templ v0.0.0\n\nreplace github.com/a-h/templ => " + templ + "\n",
+		"views/page.templ": "package views\n\ntempl Page() {\n\t<p>Hello,   World</p>\n}\n",
+		"main.go":          "package main\n\nimport (\n\t\"context\"\n\t\"os\"\n\n\t\"example.com/app/views\"\n)\n\nfunc main() {\n\tviews.Page().Render(context.Background(), os.Stdout)\n}\n",
+	}
+	for name, contents := range files {
+		fileName := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	// The production code is minified, and the dev variant isn't.
+	production := Arguments{Path: dir, BuildTags: "!dev", Minify: true}
+	dev := Arguments{Path: dir, BuildTags: "dev", TagSuffix: "_dev", Clean: true}
+	for _, args := range []Arguments{production, dev} {
+		if err := runCmd(context.Background(), args); err != nil {
+			t.Fatalf("failed to generate code: %v", err)
+		}
+	}
+	for fileName, constraint := range map[string]string{"views/page_templ.go": "!dev", "views/page_templ_dev.go": "dev"} {
+		code, err := os.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			t.Fatalf("expected code to be generated: %v", err)
+		}
+		if !strings.Contains(string(code), "\n//go:build "+constraint+"\n") {
+			t.Errorf("%s: expected the build constraint %q, got:\n%s", fileName, constraint, code)
+		}
+	}
+
+	for _, tt := range []struct {
+		tags     string
+		expected string
+	}{
+		{tags: "", expected: "<p>Hello, World</p>"},
+		{tags: "dev", expected: "<p>Hello,   World</p>"},
+	} {
+		cmd := exec.Command("go", "run", "-tags="+tt.tags, ".")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("tags %q: failed to run the generated code: %v\n%s", tt.tags, err, output)
+		}
+		if string(output) != tt.expected {
+			t.Errorf("tags %q: expected %q, got %q", tt.tags, tt.expected, output)
+		}
+	}
+
+	t.Run("switching the build tags regenerates the code", func(t *testing.T) {
+		goFileName := filepath.Join(dir, "views/page_templ_dev.go")
+		dev.BuildTags = "dev && debug"
+		if err := runCmd(context.Background(), dev); err != nil {
+			t.Fatalf("failed to generate code: %v", err)
+		}
+		code, err := os.ReadFile(goFileName)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if !strings.Contains(string(code), "\n//go:build dev && debug\n") {
+			t.Errorf("expected the new build constraint, got:\n%s", code)
+		}
+	})
+	t.Run("invalid build tags are an error", func(t *testing.T) {
+		if err := runCmd(context.Background(), Arguments{Path: dir, BuildTags: "dev &&"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -1,16 +1,21 @@
 package proxy
 
 import (
+	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/a-h/templ/cmd/templ/diagnostic"
 	"github.com/a-h/templ/cmd/templ/generatecmd/sse"
 
 	_ "embed"
@@ -21,18 +26,36 @@ var script string
 
 const scriptTag = `<script src="/_templ/reload/script.js"></script>`
 
+// unavailablePage is shown when the target doesn't respond.
+const unavailablePage = `<!DOCTYPE html>
+<html><head><title>templ: waiting for the app</title></head>
+<body><p>Waiting for the app at %s to respond. The page will reload when it's ready.</p>` + scriptTag + `</body></html>`
+
+// Error is an error shown in the overlay in the browser.
+type Error struct {
+	diagnostic.Diagnostic
+	// Source is the line of the file that the error is on, if it's known.
+	Source string `json:"source,omitempty"`
+}
+
 type Handler struct {
 	URL    string
 	Target *url.URL
 	p      *httputil.ReverseProxy
 	sse    *sse.Handler
+
+	m sync.Mutex
+	// errors are shown in the overlay in pages loaded through the proxy, until the next
+	// reload.
+	errors []Error
 }
 
 func New(port int, target *url.URL) *Handler {
 	p := httputil.NewSingleHostReverseProxy(target)
 	p.ErrorLog = log.New(os.Stderr, "Proxy to target error: ", 0)
 	p.ModifyResponse = func(r *http.Response) error {
-		if contentType := r.Header.Get("Content-Type"); contentType != "text/html" {
+		// The Content-Type can include parameters, e.g. text/html; charset=utf-8.
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "text/html" {
 			return nil
 		}
 		body, err := io.ReadAll(r.Body)
@@ -45,6 +68,18 @@ func New(port int, target *url.URL) *Handler {
 		r.Header.Set("Content-Length", strconv.Itoa(len(updated)))
 		return nil
 	}
+	p.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		p.ErrorLog.Printf("%v", err)
+		// Pages requested while the app is starting, or after it has failed, include the
+		// script, so that they show the errors, and reload once the app is ready.
+		if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, unavailablePage, html.EscapeString(target.String()))
+	}
 	return &Handler{
 		URL:    fmt.Sprintf("http://127.0.0.1:%d", port),
 		Target: target,
@@ -68,9 +103,49 @@ func (p *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		p.sse.ServeHTTP(w, r)
 		return
 	}
+	if r.URL.Path == "/_templ/reload/errors" {
+		// Provides the errors to show when the page is loaded.
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("Cache-Control", "no-cache")
+		if err := json.NewEncoder(w).Encode(p.getErrors()); err != nil {
+			fmt.Printf("failed to write errors: %v\n", err)
+		}
+		return
+	}
 	p.p.ServeHTTP(w, r)
 }
 
 func (p *Handler) SendSSE(eventType string, data string) {
 	p.sse.Send(eventType, data)
 }
+
+// Reload clears the errors, and reloads the pages loaded through the proxy.
+func (p *Handler) Reload() {
+	p.m.Lock()
+	p.errors = nil
+	p.m.Unlock()
+	p.SendSSE("message", "reload")
+}
+
+// ShowErrors shows the errors in an overlay in the pages loaded through the proxy, and in
+// pages loaded before the next reload, instead of reloading them.
+func (p *Handler) ShowErrors(errs []Error) {
+	p.m.Lock()
+	p.errors = errs
+	p.m.Unlock()
+	data, err := json.Marshal(p.getErrors())
+	if err != nil {
+		fmt.Printf("failed to encode errors: %v\n", err)
+		return
+	}
+	p.SendSSE("errors", string(data))
+}
+
+func (p *Handler) getErrors() []Error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.errors == nil {
+		return []Error{}
+	}
+	return p.errors
+}
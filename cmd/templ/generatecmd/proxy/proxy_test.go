@@ -0,0 +1,160 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ/cmd/templ/diagnostic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func get(t *testing.T, url, accept string) (resp *http.Response, body string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", accept)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return resp, string(data)
+}
+
+func getErrors(t *testing.T, url string) (errs []Error) {
+	t.Helper()
+	_, body := get(t, url+"/_templ/reload/errors", "application/json")
+	if err := json.Unmarshal([]byte(body), &errs); err != nil {
+		t.Fatalf("failed to unmarshal %q: %v", body, err)
+	}
+	return errs
+}
+
+func TestHandler(t *testing.T) {
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "<html><body><p>Hello</p></body></html>")
+	}))
+	defer app.Close()
+	target, err := url.Parse(app.URL)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+	p := New(0, target)
+	p.p.ErrorLog.SetOutput(io.Discard)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	t.Run("the script is added to HTML pages", func(t *testing.T) {
+		_, body := get(t, server.URL, "text/html")
+		expected := "<html><body><p>Hello</p>" + scriptTag + "</body></html>"
+		if body != expected {
+			t.Errorf("expected %q, got %q", expected, body)
+		}
+	})
+	t.Run("there are no errors to begin with", func(t *testing.T) {
+		if errs := getErrors(t, server.URL); len(errs) != 0 {
+			t.Errorf("expected no errors, got %#v", errs)
+		}
+	})
+	t.Run("errors are sent to the browser, and kept until the next reload", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/_templ/reload/events", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		events := bufio.NewScanner(resp.Body)
+		// nextEvent returns the type and data of the next event that isn't a ping.
+		nextEvent := func() (eventType, data string) {
+			for events.Scan() {
+				line := events.Text()
+				switch {
+				case strings.HasPrefix(line, "event: "):
+					eventType = strings.TrimPrefix(line, "event: ")
+				case strings.HasPrefix(line, "data: "):
+					data = strings.TrimPrefix(line, "data: ")
+				case line == "" && data != "ping":
+					return eventType, data
+				}
+			}
+			t.Fatalf("expected an event: %v", events.Err())
+			return
+		}
+
+		errs := []Error{{
+			Diagnostic: diagnostic.Diagnostic{File: "a.templ", Line: 4, Col: 2, EndLine: 4, EndCol: 2, Severity: "error", Code: "parse-error", Message: "<div>: expected end tag"},
+			Source:     "\t<div>",
+		}}
+		// The client is connected once it has received the first ping.
+		for events.Scan() && events.Text() != "" {
+		}
+		p.ShowErrors(errs)
+		eventType, data := nextEvent()
+		if eventType != "errors" {
+			t.Errorf("expected an errors event, got %q", eventType)
+		}
+		var sent []Error
+		if err := json.Unmarshal([]byte(data), &sent); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", data, err)
+		}
+		if diff := cmp.Diff(errs, sent); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff(errs, getErrors(t, server.URL)); diff != "" {
+			t.Error(diff)
+		}
+
+		p.Reload()
+		if eventType, data := nextEvent(); eventType != "message" || data != "reload" {
+			t.Errorf("expected a reload message, got %q %q", eventType, data)
+		}
+		if errs := getErrors(t, server.URL); len(errs) != 0 {
+			t.Errorf("expected the errors to be cleared, got %#v", errs)
+		}
+	})
+}
+
+func TestHandlerTargetUnavailable(t *testing.T) {
+	app := httptest.NewServer(http.NotFoundHandler())
+	target, err := url.Parse(app.URL)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+	app.Close()
+	p := New(0, target)
+	p.p.ErrorLog.SetOutput(io.Discard)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	resp, body := get(t, server.URL, "text/html,application/xhtml+xml")
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected status %d, got %d", http.StatusBadGateway, resp.StatusCode)
+	}
+	if !strings.Contains(body, scriptTag) {
+		t.Errorf("expected the page to include the script, so that it reloads, got %q", body)
+	}
+	resp, body = get(t, server.URL+"/app.css", "text/css")
+	if resp.StatusCode != http.StatusBadGateway || body != "" {
+		t.Errorf("expected an empty %d response, got %d %q", http.StatusBadGateway, resp.StatusCode, body)
+	}
+}
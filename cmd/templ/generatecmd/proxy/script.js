@@ -1,6 +1,49 @@
-let src = new EventSource("/_templ/reload/events");
-src.onmessage = (event) => {
-	if (event && event.data === "reload") {
-		window.location.reload();
-	}
-};
+(() => {
+	const overlayId = "templ-error-overlay";
+	// showErrors shows the errors found while generating code in an overlay, or removes the
+	// overlay if there are none.
+	const showErrors = (errors) => {
+		document.getElementById(overlayId)?.remove();
+		if (!errors || errors.length === 0) {
+			return;
+		}
+		const overlay = document.createElement("div");
+		overlay.id = overlayId;
+		overlay.style.cssText = "position:fixed;inset:0;z-index:2147483647;overflow:auto;padding:2rem;background:rgba(20,20,20,0.95);color:#eee;font:14px/1.5 monospace;";
+		const title = document.createElement("h2");
+		title.style.cssText = "margin:0 0 1rem;color:#ff6b6b;font-size:18px;";
+		title.textContent = "templ: failed to generate code";
+		overlay.appendChild(title);
+		for (const e of errors) {
+			const item = document.createElement("div");
+			item.style.cssText = "margin-bottom:1.5rem;";
+			const position = document.createElement("div");
+			position.style.cssText = "color:#9cdcfe;";
+			position.textContent = e.file ? (e.line ? `${e.file}:${e.line}:${e.col}` : e.file) : "";
+			const message = document.createElement("div");
+			message.textContent = e.message;
+			item.append(position, message);
+			if (e.source) {
+				const source = document.createElement("pre");
+				source.style.cssText = "margin:0.5rem 0 0;padding:0.5rem;background:#000;white-space:pre-wrap;";
+				const marker = e.col > 0 ? "\n" + " ".repeat(e.col - 1) + "^" : "";
+				source.textContent = e.source.replace(/\t/g, " ") + marker;
+				item.appendChild(source);
+			}
+			overlay.appendChild(item);
+		}
+		const footer = document.createElement("div");
+		footer.style.cssText = "color:#888;";
+		footer.textContent = "The page will reload when the errors are fixed.";
+		overlay.appendChild(footer);
+		document.body.appendChild(overlay);
+	};
+	fetch("/_templ/reload/errors").then((r) => r.json()).then(showErrors).catch(() => {});
+	const src = new EventSource("/_templ/reload/events");
+	src.onmessage = (event) => {
+		if (event && event.data === "reload") {
+			window.location.reload();
+		}
+	};
+	src.addEventListener("errors", (event) => showErrors(JSON.parse(event.data)));
+})();
@@ -10,32 +10,53 @@ import (
 )
 
 var m = &sync.Mutex{}
-var running = map[string]*exec.Cmd{}
+var running = map[string]*process{}
 
-func Run(ctx context.Context, workingDir, input string) (cmd *exec.Cmd, err error) {
+// process is a command that has been started, and a channel that's closed when it exits.
+type process struct {
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+// Run starts the command in workingDir. If the same command is already running, it's
+// stopped first, along with the processes that it started, e.g. the program built by
+// go run. The exited channel is closed when the command exits.
+func Run(ctx context.Context, workingDir, input string) (cmd *exec.Cmd, exited <-chan struct{}, err error) {
 	m.Lock()
 	defer m.Unlock()
-	cmd, ok := running[input]
-	if ok {
-		if err = cmd.Process.Kill(); err != nil {
-			return nil, fmt.Errorf("failed to kill existing process: %w", err)
+	if p, ok := running[input]; ok {
+		select {
+		case <-p.exited:
+		default:
+			if err = kill(p.cmd); err != nil {
+				return nil, nil, fmt.Errorf("failed to kill existing process: %w", err)
+			}
+			// Wait for the process to exit, so that the new process can use its port.
+			<-p.exited
 		}
 		delete(running, input)
 	}
 
-	parts := strings.SplitN(input, " ", 2)
-	executable := parts[0]
-	args := []string{}
-	if len(parts) > 1 {
-		args = append(args, parts[1])
+	parts := strings.Fields(input)
+	if len(parts) == 0 {
+		return nil, nil, fmt.Errorf("empty command")
 	}
 
-	cmd = exec.CommandContext(ctx, executable, args...)
+	cmd = exec.CommandContext(ctx, parts[0], parts[1:]...)
 	cmd.Env = os.Environ()
 	cmd.Dir = workingDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	running[input] = cmd
-	err = cmd.Start()
-	return
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return kill(cmd) }
+	if err = cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	p := &process{cmd: cmd, exited: make(chan struct{})}
+	go func() {
+		_ = cmd.Wait()
+		close(p.exited)
+	}()
+	running[input] = p
+	return cmd, p.exited, nil
 }
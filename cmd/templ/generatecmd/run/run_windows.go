@@ -0,0 +1,17 @@
+//go:build windows
+
+package run
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// kill stops the command, and the processes that it started.
+func kill(cmd *exec.Cmd) error {
+	// taskkill fails if the process has already exited, which isn't an error here.
+	_ = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+	return nil
+}
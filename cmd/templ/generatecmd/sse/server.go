@@ -27,18 +27,16 @@ type event struct {
 	Data string
 }
 
-// Send an event to all connected clients.
+// Send an event to all connected clients. Events aren't sent to clients that haven't
+// received the previous events yet.
 func (s *Handler) Send(eventType string, data string) {
 	s.m.Lock()
 	defer s.m.Unlock()
 	for _, f := range s.requests {
-		f := f
-		go func(f chan event) {
-			f <- event{
-				Type: eventType,
-				Data: data,
-			}
-		}(f)
+		select {
+		case f <- event{Type: eventType, Data: data}:
+		default:
+		}
 	}
 }
 
@@ -50,13 +48,14 @@ func (s *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 
 	id := atomic.AddInt64(&s.counter, 1)
-	events := make(chan event)
+	events := make(chan event, 8)
+	s.m.Lock()
 	s.requests[id] = events
+	s.m.Unlock()
 	defer func() {
 		s.m.Lock()
 		defer s.m.Unlock()
 		delete(s.requests, id)
-		close(events)
 	}()
 
 	timer := time.NewTimer(0)
@@ -70,7 +69,6 @@ loop:
 			}
 			timer.Reset(time.Second * 5)
 		case e := <-events:
-			fmt.Println("Sending reload event...")
 			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, e.Data); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
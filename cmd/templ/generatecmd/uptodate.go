@@ -0,0 +1,141 @@
+package generatecmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/a-h/templ/generator"
+)
+
+// sourceHash returns a hash of everything that the generated code depends on: the version of
+// templ, the options that change the generated code, the file name written in its errors,
+// the templ source, and the files that it includes. The tag suffix isn't included, because it
+// changes the name of the generated file instead.
+func sourceHash(version string, opts compileOptions, errorFileName string, src []byte, includes []includedFile) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "templ %s\nline-directives=%v\nminify=%v\nbuild-tags=%s\nfile-name=%s\n", version, opts.includeLineDirectives, opts.minify, opts.buildTags, errorFileName)
+	h.Write(src)
+	for _, f := range includes {
+		if f.missing {
+			fmt.Fprintf(h, "\ninclude=%q missing\n", f.name)
+			continue
+		}
+		fmt.Fprintf(h, "\ninclude=%q size=%d\n", f.name, len(f.contents))
+		h.Write(f.contents)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// generatorVersion returns the version of templ used in the source hash. It's a variable so
+// that tests can set a release version.
+var generatorVersion = generator.Version
+
+// isDevelopmentVersion returns true for builds of templ that don't have a release version.
+// Every development build has the same version, so the source hash can't tell whether the
+// generator has changed, and code is always generated.
+func isDevelopmentVersion(version string) bool {
+	switch version {
+	case "", "(devel)", "unknown":
+		return true
+	}
+	return false
+}
+
+// readHeader returns the header of the generated code, if it exists.
+func readHeader(targetFileName string) (h generator.Header, ok bool) {
+	f, err := os.Open(targetFileName)
+	if err != nil {
+		return h, false
+	}
+	defer f.Close()
+	return generator.ReadHeader(f)
+}
+
+// isUpToDate returns true if the generated code, and the files that are written with it,
+// exist, and the code was generated with the hash.
+func isUpToDate(targetFileName string, existing generator.Header, hash string, opts compileOptions) bool {
+	if existing.SourceHash != hash {
+		return false
+	}
+	if opts.generateSourceMaps && !fileExists(targetFileName+".map") {
+		return false
+	}
+	if opts.generateSourceMapVisualisations && !fileExists(strings.TrimSuffix(targetFileName, ".go")+"_sourcemap.html") {
+		return false
+	}
+	return true
+}
+
+func fileExists(fileName string) bool {
+	_, err := os.Stat(fileName)
+	return err == nil
+}
+
+// generatedFile is code generated by templ that was found within a path.
+type generatedFile struct {
+	fileName      string
+	templFileName string
+	header        generator.Header
+}
+
+// generatedFiles returns the code generated by templ within the output directory.
+func generatedFiles(ctx context.Context, output config.Output) (files []generatedFile, err error) {
+	root := output.OutputRoot()
+	if !fileExists(root) {
+		return nil, nil
+	}
+	err = filepath.WalkDir(root, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if info.IsDir() && shouldSkipDir(path) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			return nil
+		}
+		templFileName, ok := output.TemplFileName(path)
+		if !ok {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h, ok := generator.ReadHeader(f)
+		if !ok {
+			return nil
+		}
+		files = append(files, generatedFile{
+			fileName:      path,
+			templFileName: templFileName,
+			header:        h,
+		})
+		return nil
+	})
+	return files, err
+}
+
+// isCompatibleVersion returns true if code generated by the generated version of templ can be
+// used with the current version. The generated code calls the runtime API of the version that
+// generated it, which may change in any release before v1, so code generated by any other
+// release is incompatible. Development builds can't be compared, and are assumed to be
+// compatible. Code generated by early versions of templ doesn't have a version, and is
+// always incompatible.
+func isCompatibleVersion(generated, current string) bool {
+	if generated == "" {
+		return isDevelopmentVersion(current)
+	}
+	return generated == current || isDevelopmentVersion(generated) || isDevelopmentVersion(current)
+}
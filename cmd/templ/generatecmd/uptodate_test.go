@@ -0,0 +1,297 @@
+package generatecmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setGeneratorVersion sets the version of templ used in the source hash until the test ends.
+func setGeneratorVersion(t testing.TB, version string) {
+	previous := generatorVersion
+	generatorVersion = func() string { return version }
+	t.Cleanup(func() { generatorVersion = previous })
+}
+
+func TestCompileSkipsUpToDateFiles(t *testing.T) {
+	setGeneratorVersion(t, "v0.0.2")
+	dir := t.TempDir()
+	templFileName := filepath.Join(dir, "a.templ")
+	goFileName := filepath.Join(dir, "a_templ.go")
+	writeTempl := func(t *testing.T, contents string) {
+		t.Helper()
+		if err := os.WriteFile(templFileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	expectGenerated := func(t *testing.T, opts compileOptions, expected bool) {
+		t.Helper()
+		generated, err := compile(context.Background(), templFileName, opts)
+		if err != nil {
+			t.Fatalf("failed to compile: %v", err)
+		}
+		if generated != expected {
+			t.Errorf("expected generated=%v, got %v", expected, generated)
+		}
+	}
+	writeTempl(t, "package a\n\ntempl A() {\n\t<div></div>\n}\n")
+	expectGenerated(t, compileOptions{}, true)
+
+	t.Run("up to date files are skipped", func(t *testing.T) {
+		expectGenerated(t, compileOptions{}, false)
+	})
+	t.Run("files are generated if the source changes", func(t *testing.T) {
+		writeTempl(t, "package a\n\ntempl A() {\n\t<div>Changed</div>\n}\n")
+		expectGenerated(t, compileOptions{}, true)
+		expectGenerated(t, compileOptions{}, false)
+	})
+	t.Run("files are generated if the options change", func(t *testing.T) {
+		expectGenerated(t, compileOptions{minify: true}, true)
+		expectGenerated(t, compileOptions{minify: true}, false)
+		expectGenerated(t, compileOptions{}, true)
+	})
+	t.Run("files are generated if the build tags change", func(t *testing.T) {
+		expectGenerated(t, compileOptions{buildTags: "dev"}, true)
+		expectGenerated(t, compileOptions{buildTags: "dev"}, false)
+		expectGenerated(t, compileOptions{buildTags: "!dev"}, true)
+		expectGenerated(t, compileOptions{}, true)
+	})
+	t.Run("files aren't generated if the project root changes", func(t *testing.T) {
+		// The file name in the errors returned by the generated code doesn't depend on the root.
+		expectGenerated(t, compileOptions{output: config.Output{Root: dir}}, false)
+		expectGenerated(t, compileOptions{}, false)
+	})
+	t.Run("files are generated if the version of templ changes", func(t *testing.T) {
+		src, err := os.ReadFile(templFileName)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		code, err := os.ReadFile(goFileName)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		errorFileName, err := errorFileName(templFileName, goFileName)
+		if err != nil {
+			t.Fatalf("failed to get the relative file name: %v", err)
+		}
+		current := sourceHash(generatorVersion(), compileOptions{}, errorFileName, src, nil)
+		previous := sourceHash("v0.0.1", compileOptions{}, errorFileName, src, nil)
+		if !strings.Contains(string(code), current) {
+			t.Fatalf("expected the generated code to contain the hash %s:\n%s", current, code)
+		}
+		code = []byte(strings.Replace(string(code), current, previous, 1))
+		if err = os.WriteFile(goFileName, code, 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		expectGenerated(t, compileOptions{}, true)
+		expectGenerated(t, compileOptions{}, false)
+	})
+	t.Run("files are generated if the output is missing", func(t *testing.T) {
+		if err := os.Remove(goFileName); err != nil {
+			t.Fatalf("failed to remove file: %v", err)
+		}
+		expectGenerated(t, compileOptions{}, true)
+	})
+	t.Run("files are generated if the output doesn't have a hash", func(t *testing.T) {
+		if err := os.WriteFile(goFileName, []byte("// Code generated by templ@v0.0.1 DO NOT EDIT.\n\npackage a\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		expectGenerated(t, compileOptions{}, true)
+	})
+	t.Run("files are generated if the source map is missing", func(t *testing.T) {
+		expectGenerated(t, compileOptions{generateSourceMaps: true}, true)
+		expectGenerated(t, compileOptions{generateSourceMaps: true}, false)
+		if err := os.Remove(goFileName + ".map"); err != nil {
+			t.Fatalf("failed to remove file: %v", err)
+		}
+		expectGenerated(t, compileOptions{generateSourceMaps: true}, true)
+	})
+	t.Run("files are generated if force is set", func(t *testing.T) {
+		expectGenerated(t, compileOptions{}, false)
+		expectGenerated(t, compileOptions{force: true}, true)
+	})
+	t.Run("files are always generated by development builds", func(t *testing.T) {
+		setGeneratorVersion(t, "(devel)")
+		expectGenerated(t, compileOptions{}, true)
+		expectGenerated(t, compileOptions{}, true)
+	})
+}
+
+func TestCompileGeneratesFilesWhenTheirIncludesChange(t *testing.T) {
+	setGeneratorVersion(t, "v0.0.2")
+	dir := t.TempDir()
+	templFileName := filepath.Join(dir, "a.templ")
+	goFileName := filepath.Join(dir, "a_templ.go")
+	svgFileName := filepath.Join(dir, "icons", "logo.svg")
+	writeFile := func(t *testing.T, fileName, contents string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	opts := compileOptions{includes: newIncludes()}
+	expectGenerated := func(t *testing.T, expected bool) {
+		t.Helper()
+		generated, err := compile(context.Background(), templFileName, opts)
+		if err != nil {
+			t.Fatalf("failed to compile: %v", err)
+		}
+		if generated != expected {
+			t.Errorf("expected generated=%v, got %v", expected, generated)
+		}
+	}
+	writeFile(t, templFileName, "package a\n\ntempl A() {\n\t@templ.Include(\"icons/logo.svg\")\n}\n")
+	writeFile(t, svgFileName, "<svg><circle/></svg>")
+	expectGenerated(t, true)
+
+	t.Run("up to date files are skipped", func(t *testing.T) {
+		expectGenerated(t, false)
+	})
+	t.Run("the templates that include a file are recorded", func(t *testing.T) {
+		if diff := cmp.Diff([]string{templFileName}, opts.includes.templFiles(svgFileName)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("files are generated if an included file changes", func(t *testing.T) {
+		writeFile(t, svgFileName, "<svg><rect/></svg>")
+		expectGenerated(t, true)
+		expectGenerated(t, false)
+		code, err := os.ReadFile(goFileName)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if !strings.Contains(string(code), "<svg><rect/></svg>") {
+			t.Errorf("expected the generated code to contain the included file:\n%s", code)
+		}
+	})
+	t.Run("missing included files are reported", func(t *testing.T) {
+		if err := os.Remove(svgFileName); err != nil {
+			t.Fatalf("failed to remove file: %v", err)
+		}
+		_, err := compile(context.Background(), templFileName, opts)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if expected := templFileName + ":4:3: templ.Include: "; !strings.HasPrefix(err.Error(), expected) {
+			t.Errorf("expected the error to start with %q, got %q", expected, err.Error())
+		}
+	})
+}
+
+func TestOrphanedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(t *testing.T, name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	writeFile(t, "a.templ", "package a\n\ntempl A() {\n\t<div></div>\n}\n")
+	writeFile(t, "b.templ", "package a\n\ntempl B() {\n\t<div></div>\n}\n")
+	// Handwritten files are never orphaned.
+	writeFile(t, "c_templ.go", "package a\n")
+	if _, _, errs := processChanges(context.Background(), dir, compileOptions{}, 1, false); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if err := os.Remove(filepath.Join(dir, "b.templ")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	files, err := generatedFiles(context.Background(), config.Default(dir).Output())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var orphans []string
+	for _, f := range files {
+		if !fileExists(f.templFileName) {
+			orphans = append(orphans, f.templFileName)
+		}
+	}
+	if len(orphans) != 1 || orphans[0] != filepath.Join(dir, "b.templ") {
+		t.Fatalf("expected b.templ to be orphaned, got %v", orphans)
+	}
+
+	if err = processGeneratedFiles(context.Background(), io.Discard, config.Default(dir).Output(), false, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fileExists(filepath.Join(dir, "b_templ.go")) {
+		t.Error("expected orphaned files to be reported, not removed, if clean isn't set")
+	}
+	if err = processGeneratedFiles(context.Background(), io.Discard, config.Default(dir).Output(), true, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, expected := range map[string]bool{"a_templ.go": true, "b_templ.go": false, "c_templ.go": true} {
+		if actual := fileExists(filepath.Join(dir, name)); actual != expected {
+			t.Errorf("%s: expected exists=%v, got %v", name, expected, actual)
+		}
+	}
+}
+
+func TestIncompatibleVersions(t *testing.T) {
+	setGeneratorVersion(t, "v0.0.2")
+	dir := t.TempDir()
+	writeFile := func(t *testing.T, name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	writeFile(t, "a.templ", "package a\n\ntempl A() {\n\t<div></div>\n}\n")
+	if _, _, errs := processChanges(context.Background(), dir, compileOptions{}, 1, false); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	t.Run("code generated by the current version is compatible", func(t *testing.T) {
+		if err := processGeneratedFiles(context.Background(), io.Discard, config.Default(dir).Output(), false, true); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("code generated by another version is an error if strict", func(t *testing.T) {
+		// The templ file can't be parsed, so the code generated by the previous version remains.
+		writeFile(t, "b.templ", "package a\n\ntempl B() {\n\t<div>\n}\n")
+		writeFile(t, "b_templ.go", "// Code generated by templ@v0.0.1 DO NOT EDIT.\n// templ: version: v0.0.1\n\npackage a\n")
+		if _, _, errs := processChanges(context.Background(), dir, compileOptions{}, 1, false); len(errs) != 1 {
+			t.Fatalf("expected an error for b.templ, got %v", errs)
+		}
+		if err := processGeneratedFiles(context.Background(), io.Discard, config.Default(dir).Output(), false, false); err != nil {
+			t.Errorf("expected a warning, not an error, got %v", err)
+		}
+		err := processGeneratedFiles(context.Background(), io.Discard, config.Default(dir).Output(), false, true)
+		if err == nil || err.Error() != "found 1 files generated by an incompatible version of templ" {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+	t.Run("code generated by early versions of templ is incompatible", func(t *testing.T) {
+		writeFile(t, "b_templ.go", "// Code generated by templ DO NOT EDIT.\n\npackage a\n")
+		if err := processGeneratedFiles(context.Background(), io.Discard, config.Default(dir).Output(), false, true); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestIsCompatibleVersion(t *testing.T) {
+	tests := []struct {
+		generated, current string
+		expected           bool
+	}{
+		{generated: "v0.2.1", current: "v0.2.1", expected: true},
+		{generated: "v0.2.1", current: "v0.2.2", expected: false},
+		{generated: "(devel)", current: "v0.2.2", expected: true},
+		{generated: "v0.2.1", current: "(devel)", expected: true},
+		{generated: "", current: "v0.2.2", expected: false},
+		{generated: "", current: "(devel)", expected: true},
+	}
+	for _, tt := range tests {
+		if actual := isCompatibleVersion(tt.generated, tt.current); actual != tt.expected {
+			t.Errorf("isCompatibleVersion(%q, %q): expected %v, got %v", tt.generated, tt.current, tt.expected, actual)
+		}
+	}
+}
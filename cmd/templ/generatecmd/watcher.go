@@ -0,0 +1,149 @@
+package generatecmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultDebounce is how long the watcher waits after the last change before regenerating
+// code, so that a burst of changes, e.g. an editor's atomic save, or a git checkout, is
+// processed once.
+const defaultDebounce = 100 * time.Millisecond
+
+// watcher watches the directories within a path for changes to templ files, and the files
+// that they include. Directories are watched instead of files, so that files replaced by a
+// rename, as editors do when saving, are still watched.
+type watcher struct {
+	fsw      *fsnotify.Watcher
+	root     string
+	debounce time.Duration
+	// log is written to with the errors found while watching.
+	log io.Writer
+	// includes are the files included by the templates. If it's nil, changes to included
+	// files are ignored.
+	includes *includes
+}
+
+func newWatcher(root string, debounce time.Duration) (*watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+	if err = watchDirs(fsw, root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return &watcher{fsw: fsw, root: root, debounce: debounce, log: os.Stdout}, nil
+}
+
+// Run calls onChange with the names of the templ files that have been created, written,
+// renamed or removed, or whose included files have, once no changes have been seen for the
+// debounce duration. It returns when the context is cancelled.
+func (w *watcher) Run(ctx context.Context, onChange func(fileNames []string)) error {
+	defer w.fsw.Close()
+	changed := make(map[string]struct{})
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Watch new directories, and process the templ files that were created
+					// within them before they were watched.
+					if err = watchDirs(w.fsw, event.Name); err != nil {
+						fmt.Fprintf(w.log, "Error watching %q: %v\n", event.Name, err)
+					}
+					for _, fileName := range templFiles(event.Name) {
+						changed[fileName] = struct{}{}
+					}
+					timer.Reset(w.debounce)
+					continue
+				}
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if !strings.HasSuffix(event.Name, ".templ") {
+				templFileNames := w.includes.templFiles(event.Name)
+				for _, fileName := range templFileNames {
+					changed[fileName] = struct{}{}
+				}
+				if len(templFileNames) > 0 {
+					timer.Reset(w.debounce)
+				}
+				continue
+			}
+			changed[event.Name] = struct{}{}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(w.log, "Error watching %q: %v\n", w.root, err)
+		case <-timer.C:
+			if len(changed) == 0 {
+				continue
+			}
+			fileNames := make([]string, 0, len(changed))
+			for fileName := range changed {
+				fileNames = append(fileNames, fileName)
+			}
+			sort.Strings(fileNames)
+			changed = make(map[string]struct{})
+			onChange(fileNames)
+		}
+	}
+}
+
+// watchDirs adds dir, and the directories within it, to the watcher, skipping the
+// directories that are ignored by the Go tool.
+func watchDirs(fsw *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && shouldSkipDir(path) {
+			return filepath.SkipDir
+		}
+		if err = fsw.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %q: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// templFiles returns the templ files within dir.
+func templFiles(dir string) (fileNames []string) {
+	_ = filepath.WalkDir(dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && path != dir && shouldSkipDir(path) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && strings.HasSuffix(path, ".templ") {
+			fileNames = append(fileNames, path)
+		}
+		return nil
+	})
+	return fileNames
+}
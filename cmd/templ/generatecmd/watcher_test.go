@@ -0,0 +1,150 @@
+package generatecmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newWatcher(dir, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	w.includes = newIncludes()
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan []string, 10)
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- w.Run(ctx, func(fileNames []string) {
+			changes <- fileNames
+		})
+	}()
+
+	expectChanges := func(t *testing.T, expected ...string) {
+		t.Helper()
+		select {
+		case actual := <-changes:
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for changes to %v", expected)
+		}
+	}
+	writeFile := func(t *testing.T, name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	templFileName := filepath.Join(dir, "a.templ")
+	t.Run("created files are processed", func(t *testing.T) {
+		writeFile(t, templFileName, "package a")
+		expectChanges(t, templFileName)
+	})
+	t.Run("a burst of writes is processed once", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			writeFile(t, templFileName, "package a\n")
+		}
+		writeFile(t, filepath.Join(dir, "a.go"), "package a")
+		expectChanges(t, templFileName)
+	})
+	t.Run("files replaced by a rename are processed", func(t *testing.T) {
+		// Editors write the new contents to a temporary file, and rename it over the original.
+		tmpFileName := filepath.Join(dir, "a.templ.tmp")
+		writeFile(t, tmpFileName, "package a\n\n")
+		if err := os.Rename(tmpFileName, templFileName); err != nil {
+			t.Fatalf("failed to rename file: %v", err)
+		}
+		expectChanges(t, templFileName)
+		// The file is still watched after it has been replaced.
+		writeFile(t, templFileName, "package a\n\n\n")
+		expectChanges(t, templFileName)
+	})
+	t.Run("files in new directories are processed", func(t *testing.T) {
+		subDir := filepath.Join(dir, "sub")
+		if err := os.Mkdir(subDir, 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		subFileName := filepath.Join(subDir, "b.templ")
+		writeFile(t, subFileName, "package sub")
+		expectChanges(t, subFileName)
+	})
+	t.Run("templates are processed when the files they include change", func(t *testing.T) {
+		w.includes.set(templFileName, []string{"logo.svg"})
+		writeFile(t, filepath.Join(dir, "logo.svg"), "<svg></svg>")
+		expectChanges(t, templFileName)
+		w.includes.set(templFileName, nil)
+	})
+	t.Run("removed files are processed", func(t *testing.T) {
+		if err := os.Remove(templFileName); err != nil {
+			t.Fatalf("failed to remove file: %v", err)
+		}
+		expectChanges(t, templFileName)
+	})
+	t.Run("cancelling the context stops the watcher", func(t *testing.T) {
+		cancel()
+		select {
+		case err := <-runErr:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the watcher to stop")
+		}
+	})
+}
+
+func TestProcessFiles(t *testing.T) {
+	dir := t.TempDir()
+	templFileName := filepath.Join(dir, "a.templ")
+	goFileName := filepath.Join(dir, "a_templ.go")
+	if err := os.WriteFile(templFileName, []byte("package a\n\ntempl A() {\n\t<div></div>\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	handwrittenFileName := filepath.Join(dir, "b_templ.go")
+	if err := os.WriteFile(handwrittenFileName, []byte("package a\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	invalidFileName := filepath.Join(dir, "c.templ")
+	if err := os.WriteFile(invalidFileName, []byte("package a\n\ntempl C() {\n\t<div>\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	exists := func(fileName string) bool {
+		_, err := os.Stat(fileName)
+		return err == nil
+	}
+
+	changesFound, _, errs := processFiles(context.Background(), []string{templFileName, invalidFileName}, compileOptions{}, 2, false)
+	if changesFound != 2 {
+		t.Errorf("expected 2 changes, got %d", changesFound)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected an error for the invalid file, got %v", errs)
+	}
+	if !exists(goFileName) {
+		t.Fatalf("expected %s to be generated", goFileName)
+	}
+
+	if err := os.Remove(templFileName); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	_, _, errs = processFiles(context.Background(), []string{templFileName, filepath.Join(dir, "b.templ")}, compileOptions{}, 2, false)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if exists(goFileName) {
+		t.Errorf("expected %s to be removed, because its templ file was removed", goFileName)
+	}
+	if !exists(handwrittenFileName) {
+		t.Errorf("expected %s not to be removed, because it wasn't generated by templ", handwrittenFileName)
+	}
+}
@@ -0,0 +1,293 @@
+// Package i18n finds the text in templ files that's translated, so that templ i18n extract
+// and the hardcoded-text lint rule find the same text.
+package i18n
+
+import (
+	"go/scanner"
+	"go/token"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Attributes are the attributes whose constant values are user-visible text.
+var Attributes = []string{"title", "alt", "placeholder", "aria-label"}
+
+// untranslated are the elements whose text isn't translated, because it's code or input,
+// e.g. <code>. Elements with a translate="no" attribute aren't translated either.
+var untranslated = map[string]bool{
+	"code": true,
+	"kbd":  true,
+	"samp": true,
+	"var":  true,
+}
+
+// Text is user-visible text written as-is in a template.
+type Text struct {
+	// Value of the text, with character references decoded, and each run of whitespace
+	// replaced with a single space.
+	Value string
+	// Element that contains the text, or is the element of the attribute, e.g. "img". It's
+	// empty for text that isn't within an element in the template.
+	Element string
+	// Attribute that the text is the value of, e.g. "alt", or empty for text content.
+	Attribute string
+	// Range of the element's name, or of the template's declaration if the text isn't within
+	// an element, since text doesn't have a position of its own.
+	Range parser.Range
+}
+
+// FindText returns the user-visible text in the templates of the file, in document order.
+// Text content is split by elements and expressions, e.g. <p>Hello, <b>{ name }</b>!</p>
+// contains "Hello,". Text that doesn't contain any letters, e.g. "|", isn't returned.
+func FindText(tf parser.TemplateFile) (texts []Text) {
+	for _, n := range tf.Nodes {
+		if t, ok := n.(parser.HTMLTemplate); ok {
+			f := &finder{rng: t.Expression.Range}
+			f.nodes(t.Children)
+			texts = append(texts, f.texts...)
+		}
+	}
+	return texts
+}
+
+type finder struct {
+	texts   []Text
+	element string
+	rng     parser.Range
+}
+
+func (f *finder) nodes(nodes []parser.Node) {
+	var run strings.Builder
+	flush := func() {
+		f.add(run.String(), "")
+		run.Reset()
+	}
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case parser.Text:
+			run.WriteString(n.Value)
+			continue
+		case parser.CharacterReference:
+			run.WriteString(n.Value)
+			continue
+		case parser.Whitespace:
+			run.WriteString(n.Value)
+			continue
+		}
+		flush()
+		switch n := n.(type) {
+		case parser.Element:
+			f.visitElement(n)
+		case parser.TemplElementExpression:
+			f.nodes(n.Children)
+		case parser.IfExpression:
+			f.nodes(n.Then)
+			for _, elseIf := range n.ElseIfs {
+				f.nodes(elseIf.Then)
+			}
+			f.nodes(n.Else)
+		case parser.SwitchExpression:
+			for _, c := range n.Cases {
+				f.nodes(c.Children)
+			}
+		case parser.ForExpression:
+			f.nodes(n.Children)
+		}
+	}
+	flush()
+}
+
+func (f *finder) visitElement(e parser.Element) {
+	name := strings.ToLower(e.Name)
+	if untranslated[name] || hasAttribute(e.Attributes, "translate", "no") {
+		return
+	}
+	child := &finder{element: e.Name, rng: e.NameRange}
+	child.attributes(e.Attributes)
+	child.nodes(e.Children)
+	f.texts = append(f.texts, child.texts...)
+}
+
+func (f *finder) attributes(attrs []parser.Attribute) {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case parser.ConstantAttribute:
+			for _, name := range Attributes {
+				if strings.EqualFold(attr.Name, name) {
+					f.add(attr.Value, attr.Name)
+				}
+			}
+		case parser.ConditionalAttribute:
+			f.attributes(attr.Then)
+			f.attributes(attr.Else)
+		}
+	}
+}
+
+func (f *finder) add(value, attribute string) {
+	value = strings.Join(strings.Fields(html.UnescapeString(value)), " ")
+	if strings.IndexFunc(value, unicode.IsLetter) < 0 {
+		return
+	}
+	f.texts = append(f.texts, Text{
+		Value:     value,
+		Element:   f.element,
+		Attribute: attribute,
+		Range:     f.rng,
+	})
+}
+
+func hasAttribute(attrs []parser.Attribute, name, value string) bool {
+	for _, attr := range attrs {
+		if ca, ok := attr.(parser.ConstantAttribute); ok && strings.EqualFold(ca.Name, name) && strings.EqualFold(strings.TrimSpace(ca.Value), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageID is the ID passed to a translation function, e.g. "home.title" in
+// { t("home.title") }.
+type MessageID struct {
+	ID string
+	// Position of the call.
+	Position parser.Position
+}
+
+// FindMessageIDs returns the message IDs passed as a string literal to the translation
+// function in the Go expressions of the file, in the order they're written. The function
+// may be qualified with a package name, e.g. "i18n.T".
+func FindMessageIDs(tf parser.TemplateFile, function string) (ids []MessageID) {
+	parts := strings.Split(function, ".")
+	find := func(e parser.Expression) {
+		ids = append(ids, findCalls(e, parts)...)
+	}
+	for _, n := range tf.Nodes {
+		switch n := n.(type) {
+		case parser.GoExpression:
+			find(n.Expression)
+		case parser.HTMLTemplate:
+			for _, c := range n.Children {
+				parser.Inspect(c, func(n parser.Node) bool {
+					expressions(n, find)
+					return true
+				})
+			}
+		}
+	}
+	return ids
+}
+
+// expressions calls f for the Go expressions of the node, but not of its children.
+func expressions(n parser.Node, f func(parser.Expression)) {
+	switch n := n.(type) {
+	case parser.Element:
+		attributeExpressions(n.Attributes, f)
+	case parser.StringExpression:
+		f(n.Expression)
+	case parser.TemplElementExpression:
+		f(n.Expression)
+	case parser.CallTemplateExpression:
+		f(n.Expression)
+	case parser.IfExpression:
+		f(n.Expression)
+		for _, elseIf := range n.ElseIfs {
+			f(elseIf.Expression)
+		}
+	case parser.SwitchExpression:
+		f(n.Expression)
+		for _, c := range n.Cases {
+			f(c.Expression)
+		}
+	case parser.ForExpression:
+		f(n.Expression)
+	}
+}
+
+func attributeExpressions(attrs []parser.Attribute, f func(parser.Expression)) {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case parser.ExpressionAttribute:
+			f(attr.Expression)
+		case parser.BoolExpressionAttribute:
+			f(attr.Expression)
+		case parser.SpreadAttributes:
+			f(attr.Expression)
+		case parser.ConditionalAttribute:
+			f(attr.Expression)
+			attributeExpressions(attr.Then, f)
+			attributeExpressions(attr.Else, f)
+		}
+	}
+}
+
+type goToken struct {
+	pos token.Pos
+	tok token.Token
+	lit string
+}
+
+// findCalls returns the calls to the function with a string literal as the first argument,
+// e.g. t("home.title"). The Go code doesn't need to be valid, since the expressions of if
+// and for statements aren't expressions.
+func findCalls(e parser.Expression, function []string) (ids []MessageID) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(e.Value))
+	var s scanner.Scanner
+	s.Init(file, []byte(e.Value), nil, 0)
+	var tokens []goToken
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		tokens = append(tokens, goToken{pos: pos, tok: tok, lit: lit})
+	}
+	// The function name is followed by a left parenthesis and a string literal.
+	n := 2*len(function) + 1
+	for i := 0; i+n <= len(tokens); i++ {
+		if i > 0 && tokens[i-1].tok == token.PERIOD {
+			continue
+		}
+		if !matchesCall(tokens[i:i+n], function) {
+			continue
+		}
+		id, err := strconv.Unquote(tokens[i+n-1].lit)
+		if err != nil {
+			continue
+		}
+		offset := file.Offset(tokens[i].pos)
+		ids = append(ids, MessageID{ID: id, Position: positionOf(e, offset)})
+	}
+	return ids
+}
+
+func matchesCall(tokens []goToken, function []string) bool {
+	for j, part := range function {
+		if j > 0 && tokens[2*j-1].tok != token.PERIOD {
+			return false
+		}
+		if t := tokens[2*j]; t.tok != token.IDENT || t.lit != part {
+			return false
+		}
+	}
+	return tokens[len(tokens)-2].tok == token.LPAREN && tokens[len(tokens)-1].tok == token.STRING
+}
+
+// positionOf returns the position of the offset within the expression.
+func positionOf(e parser.Expression, offset int) parser.Position {
+	p := e.Range.From
+	before := e.Value[:offset]
+	p.Index += int64(offset)
+	if i := strings.LastIndexByte(before, '\n'); i >= 0 {
+		p.Line += uint32(strings.Count(before, "\n"))
+		p.Col = uint32(len(before) - i - 1)
+	} else {
+		p.Col += uint32(offset)
+	}
+	return p
+}
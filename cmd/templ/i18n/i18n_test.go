@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func parse(t *testing.T, input string) parser.TemplateFile {
+	t.Helper()
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	return tf
+}
+
+func TestFindText(t *testing.T) {
+	tf := parse(t, `package main
+
+templ Page(name string, ok bool) {
+	<h1 title="Home &amp; away">Hello, { name }!</h1>
+	<p>
+		Welcome to
+		the <b>site</b>
+	</p>
+	<input placeholder="Search" if ok {
+		aria-label="Search the site"
+	} type="text"/>
+	if ok {
+		<p>Yes</p>
+	} else {
+		No
+	}
+	<code>go run .</code>
+	<p translate="no">templ</p>
+	<p>&nbsp;|&nbsp;</p>
+	<script>console.log("text")</script>
+}
+
+css red() {
+	color: red;
+}
+`)
+	type text struct {
+		Value, Element, Attribute string
+		Line                      uint32
+	}
+	var actual []text
+	for _, t := range FindText(tf) {
+		actual = append(actual, text{Value: t.Value, Element: t.Element, Attribute: t.Attribute, Line: t.Range.From.Line})
+	}
+	expected := []text{
+		{Value: "Home & away", Element: "h1", Attribute: "title", Line: 3},
+		{Value: "Hello,", Element: "h1", Line: 3},
+		{Value: "Welcome to the", Element: "p", Line: 4},
+		{Value: "site", Element: "b", Line: 6},
+		{Value: "Search", Element: "input", Attribute: "placeholder", Line: 8},
+		{Value: "Search the site", Element: "input", Attribute: "aria-label", Line: 8},
+		{Value: "Yes", Element: "p", Line: 12},
+		{Value: "No", Line: 2},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFindMessageIDs(t *testing.T) {
+	input := `package main
+
+var title = t("page.title")
+
+templ Page(items []string) {
+	<h1 title={ t("home.title") }>{ t("home.heading") }</h1>
+	for _, item := range items {
+		<p>{ t("home.item",
+			item) }</p>
+	}
+	@layout(t(name), i18n.T("home.qualified"), x.t("home.method"))
+}
+`
+	tf := parse(t, input)
+	tests := []struct {
+		function string
+		expected []string
+	}{
+		{
+			function: "t",
+			expected: []string{"page.title 2:12", "home.title 5:13", "home.heading 5:33", "home.item 7:7"},
+		},
+		{
+			function: "i18n.T",
+			expected: []string{"home.qualified 10:18"},
+		},
+	}
+	for _, tt := range tests {
+		var actual []string
+		for _, id := range FindMessageIDs(tf, tt.function) {
+			actual = append(actual, fmt.Sprintf("%s %d:%d", id.ID, id.Position.Line, id.Position.Col))
+			if !strings.HasPrefix(input[id.Position.Index:], tt.function+"(") {
+				t.Errorf("%s: expected the index of %q to be the call, got %q", tt.function, id.ID, input[id.Position.Index:])
+			}
+		}
+		if diff := cmp.Diff(tt.expected, actual); diff != "" {
+			t.Errorf("%s: %s", tt.function, diff)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package i18ncmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ/cmd/templ/i18n"
+	"github.com/a-h/templ/cmd/templ/processor"
+	parser "github.com/a-h/templ/parser/v2"
+)
+
+const workerCount = 4
+
+// The catalog formats.
+const (
+	FormatPOT  = "pot"
+	FormatJSON = "json"
+)
+
+type Arguments struct {
+	// Paths are the templ files, and the directories of templ files, to extract text from.
+	Paths []string
+	// Format of the catalog, FormatPOT or FormatJSON. Defaults to FormatPOT.
+	Format string
+	// Output is the file to write the catalog to. If it's empty, the catalog is written to w.
+	Output string
+	// Function is the translation function, whose string literal arguments are extracted as
+	// message IDs, e.g. "t" for { t("home.title") }. Defaults to "t".
+	Function string
+}
+
+// Message is an entry in the catalog.
+type Message struct {
+	// ID of the message, which is the text itself for text written in templates.
+	ID string `json:"id"`
+	// References are the positions the message is used, e.g. "views/home.templ:12".
+	References []string `json:"references"`
+}
+
+// Extract extracts the text written in the templates, and the message IDs passed to the
+// translation function, and writes them to a catalog. The messages are deduplicated, and
+// sorted by their first reference, so that the catalog only changes when the templates do.
+func Extract(w io.Writer, args Arguments) (err error) {
+	if args.Format == "" {
+		args.Format = FormatPOT
+	}
+	if args.Format != FormatPOT && args.Format != FormatJSON {
+		return fmt.Errorf("unknown format %q, expected %q or %q", args.Format, FormatPOT, FormatJSON)
+	}
+	if args.Function == "" {
+		args.Function = "t"
+	}
+
+	c := newCatalog()
+	extractFile := func(fileName string) error {
+		tf, err := parser.ParseFile(fileName)
+		if err != nil {
+			return err
+		}
+		ref := filepath.ToSlash(fileName)
+		for _, t := range i18n.FindText(tf) {
+			c.add(t.Value, ref, t.Range.From.Line+1)
+		}
+		for _, id := range i18n.FindMessageIDs(tf, args.Function) {
+			c.add(id.ID, ref, id.Position.Line+1)
+		}
+		return nil
+	}
+	var fileErrs []error
+	for _, path := range args.Paths {
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			fileErrs = append(fileErrs, statErr)
+			continue
+		}
+		if !info.IsDir() {
+			if extractErr := extractFile(path); extractErr != nil {
+				fileErrs = append(fileErrs, extractErr)
+			}
+			continue
+		}
+		results := make(chan processor.Result)
+		go processor.Process(path, extractFile, workerCount, results)
+		for r := range results {
+			if r.Error != nil {
+				fileErrs = append(fileErrs, r.Error)
+			}
+		}
+	}
+	// A catalog that's missing the text of a file would remove its translations, so
+	// nothing is written if a file can't be parsed.
+	if len(fileErrs) > 0 {
+		sort.Slice(fileErrs, func(i, j int) bool { return fileErrs[i].Error() < fileErrs[j].Error() })
+		return errors.Join(fileErrs...)
+	}
+
+	var sb strings.Builder
+	if args.Format == FormatJSON {
+		err = writeJSON(&sb, c.messages())
+	} else {
+		err = writePOT(&sb, c.messages())
+	}
+	if err != nil {
+		return err
+	}
+	if args.Output != "" {
+		return os.WriteFile(args.Output, []byte(sb.String()), 0644)
+	}
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+type reference struct {
+	fileName string
+	line     uint32
+}
+
+func (r reference) String() string {
+	return r.fileName + ":" + strconv.FormatUint(uint64(r.line), 10)
+}
+
+func (r reference) less(other reference) bool {
+	if r.fileName != other.fileName {
+		return r.fileName < other.fileName
+	}
+	return r.line < other.line
+}
+
+// catalog collects the references to each message. It's safe for concurrent use.
+type catalog struct {
+	m          sync.Mutex
+	references map[string]map[reference]struct{}
+}
+
+func newCatalog() *catalog {
+	return &catalog{references: make(map[string]map[reference]struct{})}
+}
+
+func (c *catalog) add(id, fileName string, line uint32) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	refs, ok := c.references[id]
+	if !ok {
+		refs = make(map[reference]struct{})
+		c.references[id] = refs
+	}
+	refs[reference{fileName: fileName, line: line}] = struct{}{}
+}
+
+// messages returns the messages, sorted by their first reference, then by ID.
+func (c *catalog) messages() []Message {
+	type entry struct {
+		id   string
+		refs []reference
+	}
+	entries := make([]entry, 0, len(c.references))
+	for id, refs := range c.references {
+		e := entry{id: id}
+		for r := range refs {
+			e.refs = append(e.refs, r)
+		}
+		sort.Slice(e.refs, func(i, j int) bool { return e.refs[i].less(e.refs[j]) })
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].refs[0], entries[j].refs[0]
+		if a != b {
+			return a.less(b)
+		}
+		return entries[i].id < entries[j].id
+	})
+	messages := make([]Message, len(entries))
+	for i, e := range entries {
+		messages[i].ID = e.id
+		for _, r := range e.refs {
+			messages[i].References = append(messages[i].References, r.String())
+		}
+	}
+	return messages
+}
+
+func writeJSON(w io.Writer, messages []Message) error {
+	if messages == nil {
+		messages = []Message{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(messages)
+}
+
+// writePOT writes the messages as a gettext template. It doesn't have a creation date, so
+// that it's the same each time it's extracted.
+func writePOT(w io.Writer, messages []Message) error {
+	var sb strings.Builder
+	sb.WriteString("msgid \"\"\nmsgstr \"\"\n\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	for _, m := range messages {
+		sb.WriteString("\n")
+		for _, r := range m.References {
+			sb.WriteString("#: " + r + "\n")
+		}
+		sb.WriteString("msgid " + quotePO(m.ID) + "\n")
+		sb.WriteString("msgstr \"\"\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// quotePO quotes the string as a PO string literal.
+func quotePO(s string) string {
+	return `"` + poEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,144 @@
+package i18ncmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+var files = map[string]string{
+	"views/home.templ": `package views
+
+templ Home(name string) {
+	<h1 title="Welcome">Welcome, { name }!</h1>
+	<p>{ t("home.intro") }</p>
+	<img src="logo.png" alt="Welcome"/>
+}
+`,
+	"views/about.templ": `package views
+
+templ About() {
+	<h1>About "us"</h1>
+	<p title={ t("home.intro") }>Welcome</p>
+}
+`,
+}
+
+func TestExtract(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	t.Run("pot", func(t *testing.T) {
+		var w bytes.Buffer
+		if err := Extract(&w, Arguments{Paths: []string{"."}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: views/about.templ:4
+msgid "About \"us\""
+msgstr ""
+
+#: views/about.templ:5
+#: views/home.templ:4
+#: views/home.templ:6
+msgid "Welcome"
+msgstr ""
+
+#: views/about.templ:5
+#: views/home.templ:5
+msgid "home.intro"
+msgstr ""
+
+#: views/home.templ:4
+msgid "Welcome,"
+msgstr ""
+`
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("json", func(t *testing.T) {
+		var w bytes.Buffer
+		if err := Extract(&w, Arguments{Paths: []string{"views/home.templ"}, Format: FormatJSON}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var actual []Message
+		if err := json.Unmarshal(w.Bytes(), &actual); err != nil {
+			t.Fatalf("failed to unmarshal catalog: %v\n%s", err, w.String())
+		}
+		expected := []Message{
+			{ID: "Welcome", References: []string{"views/home.templ:4", "views/home.templ:6"}},
+			{ID: "Welcome,", References: []string{"views/home.templ:4"}},
+			{ID: "home.intro", References: []string{"views/home.templ:5"}},
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the catalog is the same each time it's extracted", func(t *testing.T) {
+		var first string
+		for i := 0; i < 5; i++ {
+			output := filepath.Join(t.TempDir(), "messages.pot")
+			if err := Extract(nil, Arguments{Paths: []string{"."}, Output: output}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("failed to read catalog: %v", err)
+			}
+			if i == 0 {
+				first = string(data)
+				continue
+			}
+			if diff := cmp.Diff(first, string(data)); diff != "" {
+				t.Fatalf("run %d: %s", i, diff)
+			}
+		}
+	})
+	t.Run("nothing is written if a template can't be parsed", func(t *testing.T) {
+		invalid := t.TempDir()
+		writeFiles(t, invalid, map[string]string{"invalid.templ": "package views\n\ntempl A() {\n\t<div>\n}\n"})
+		output := filepath.Join(t.TempDir(), "messages.pot")
+		err := Extract(nil, Arguments{Paths: []string{".", invalid}, Output: output})
+		if err == nil || !strings.Contains(err.Error(), "invalid.templ") {
+			t.Errorf("expected a parse error, got %v", err)
+		}
+		if _, err := os.Stat(output); err == nil {
+			t.Error("expected the catalog not to be written")
+		}
+	})
+	t.Run("unknown formats are an error", func(t *testing.T) {
+		if err := Extract(nil, Arguments{Paths: []string{"."}, Format: "xliff"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,363 @@
+package lint
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// attribute is an attribute of an element. The value is only known for constant attributes.
+type attribute struct {
+	value    string
+	constant bool
+}
+
+// attributes returns the attributes of the element by lower case name. The attributes of
+// both branches of conditional attributes are included, because either may be rendered.
+// spread is true if the element has spread attributes, so the attributes that are rendered
+// aren't known.
+func attributes(e parser.Element) (attrs map[string]attribute, spread bool) {
+	attrs = make(map[string]attribute)
+	var add func(list []parser.Attribute)
+	add = func(list []parser.Attribute) {
+		for _, attr := range list {
+			switch attr := attr.(type) {
+			case parser.BoolConstantAttribute:
+				attrs[strings.ToLower(attr.Name)] = attribute{constant: true}
+			case parser.ConstantAttribute:
+				attrs[strings.ToLower(attr.Name)] = attribute{value: html.UnescapeString(attr.Value), constant: true}
+			case parser.BoolExpressionAttribute:
+				attrs[strings.ToLower(attr.Name)] = attribute{}
+			case parser.ExpressionAttribute:
+				attrs[strings.ToLower(attr.Name)] = attribute{}
+			case parser.SpreadAttributes:
+				spread = true
+			case parser.ConditionalAttribute:
+				add(attr.Then)
+				add(attr.Else)
+			}
+		}
+	}
+	add(e.Attributes)
+	return attrs, spread
+}
+
+// has returns true if the element has any of the attributes.
+func has(attrs map[string]attribute, names ...string) bool {
+	for _, name := range names {
+		if _, ok := attrs[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasName returns true if the attribute is set to a value that isn't empty, or to an
+// expression.
+func hasName(attrs map[string]attribute, name string) bool {
+	a, ok := attrs[name]
+	return ok && (!a.constant || strings.TrimSpace(a.value) != "")
+}
+
+// addAttributes is a fix that inserts the attributes after the name of the element.
+func addAttributes(e parser.Element, attrs string) *parser.Fix {
+	return &parser.Fix{
+		Title: "Add " + attrs,
+		Edits: []parser.Edit{{
+			Range:   parser.Range{From: e.NameRange.To, To: e.NameRange.To},
+			NewText: " " + attrs,
+		}},
+	}
+}
+
+// todoLabel is added by fixes for elements that need a label, which only the author can
+// write.
+const todoLabel = `aria-label="TODO"`
+
+// accessibleNameRule finds buttons and links that don't have an accessible name, so screen
+// readers can't describe them, e.g. a button that only contains an icon.
+type accessibleNameRule struct{}
+
+func (accessibleNameRule) Name() string { return CheckAccessibleName }
+
+func (accessibleNameRule) Check(f *File) (issues []parser.Issue) {
+	parser.InspectFile(f.Template, func(n parser.Node) bool {
+		e, ok := n.(parser.Element)
+		if !ok {
+			return true
+		}
+		attrs, spread := attributes(e)
+		if spread || !isNamedControl(strings.ToLower(e.Name), attrs) {
+			return true
+		}
+		if hasName(attrs, "aria-label") || has(attrs, "aria-labelledby") || hasName(attrs, "title") || hasContent(e.Children) {
+			return true
+		}
+		issues = append(issues, parser.Issue{
+			Check:    CheckAccessibleName,
+			Severity: parser.SeverityWarning,
+			Message:  fmt.Sprintf("<%s>: no accessible name, add text, or an aria-label attribute", e.Name),
+			Range:    e.NameRange,
+			Fix:      addAttributes(e, todoLabel),
+		})
+		return true
+	})
+	return issues
+}
+
+// isNamedControl returns true for the elements that are named by their content.
+func isNamedControl(name string, attrs map[string]attribute) bool {
+	switch attrs["role"].value {
+	case "button", "link", "menuitem", "tab":
+		return true
+	}
+	return name == "button" || (name == "a" && has(attrs, "href"))
+}
+
+// hasContent returns true if the nodes render text that names the element that contains
+// them. Expressions and components are assumed to render text.
+func hasContent(nodes []parser.Node) (found bool) {
+	for _, n := range nodes {
+		parser.Inspect(n, func(n parser.Node) bool {
+			if found {
+				return false
+			}
+			switch n := n.(type) {
+			case parser.Text:
+				found = strings.TrimSpace(html.UnescapeString(n.Value)) != ""
+			case parser.CharacterReference, parser.StringExpression, parser.TemplElementExpression, parser.CallTemplateExpression, parser.ChildrenExpression:
+				found = true
+			case parser.Element:
+				attrs, spread := attributes(n)
+				if spread || hasName(attrs, "aria-label") || has(attrs, "aria-labelledby") {
+					found = true
+					return false
+				}
+				if a := attrs["aria-hidden"]; has(attrs, "aria-hidden") && (!a.constant || a.value != "false") {
+					return false
+				}
+				if strings.EqualFold(n.Name, "img") {
+					found = hasName(attrs, "alt")
+					return false
+				}
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// anchorHrefRule finds <a> elements without an href, which can't be focused with the
+// keyboard.
+type anchorHrefRule struct{}
+
+func (anchorHrefRule) Name() string { return CheckAnchorHref }
+
+func (anchorHrefRule) Check(f *File) (issues []parser.Issue) {
+	parser.InspectFile(f.Template, func(n parser.Node) bool {
+		e, ok := n.(parser.Element)
+		if !ok || !strings.EqualFold(e.Name, "a") {
+			return true
+		}
+		attrs, spread := attributes(e)
+		if spread || has(attrs, "href") {
+			return true
+		}
+		issues = append(issues, parser.Issue{
+			Check:    CheckAnchorHref,
+			Severity: parser.SeverityWarning,
+			Message:  fmt.Sprintf("<%s>: missing href attribute, so it can't be focused, use a <button> for actions", e.Name),
+			Range:    e.NameRange,
+		})
+		return true
+	})
+	return issues
+}
+
+// clickHandlers are the attributes that handle clicks, including those of htmx and Alpine.js.
+var clickHandlers = []string{"onclick", "hx-on:click", "hx-on::click", "x-on:click", "@click"}
+
+// interactiveElements can be focused, and activated with the keyboard.
+var interactiveElements = map[string]bool{
+	"a": true, "button": true, "input": true, "select": true, "textarea": true,
+	"option": true, "summary": true, "details": true, "label": true,
+}
+
+// clickHandlerRoleRule finds click handlers on elements that can't be focused or activated
+// with the keyboard, e.g. <div onclick={ ... }>, unless they have a role and tabindex.
+type clickHandlerRoleRule struct{}
+
+func (clickHandlerRoleRule) Name() string { return CheckClickHandlerRole }
+
+func (clickHandlerRoleRule) Check(f *File) (issues []parser.Issue) {
+	parser.InspectFile(f.Template, func(n parser.Node) bool {
+		e, ok := n.(parser.Element)
+		if !ok || interactiveElements[strings.ToLower(e.Name)] {
+			return true
+		}
+		attrs, spread := attributes(e)
+		if spread || !has(attrs, clickHandlers...) {
+			return true
+		}
+		var missing []string
+		if !has(attrs, "role") {
+			missing = append(missing, `role="button"`)
+		}
+		if !has(attrs, "tabindex") {
+			missing = append(missing, `tabindex="0"`)
+		}
+		if len(missing) == 0 {
+			return true
+		}
+		add := strings.Join(missing, " ")
+		issues = append(issues, parser.Issue{
+			Check:    CheckClickHandlerRole,
+			Severity: parser.SeverityWarning,
+			Message:  fmt.Sprintf("<%s>: click handler on an element that can't be focused, add %s, or use a <button>", e.Name, add),
+			Range:    e.NameRange,
+			Fix:      addAttributes(e, add),
+		})
+		return true
+	})
+	return issues
+}
+
+// unlabelledInputTypes are the types of <input> that don't need a label, because they're
+// hidden, or named by their value.
+var unlabelledInputTypes = map[string]bool{
+	"hidden": true, "submit": true, "reset": true, "button": true, "image": true,
+}
+
+// inputLabelRule finds form controls that don't have a label.
+type inputLabelRule struct{}
+
+func (inputLabelRule) Name() string { return CheckInputLabel }
+
+func (inputLabelRule) Check(f *File) (issues []parser.Issue) {
+	// The ids of the controls that have a <label for="id"> in the file. If the for attribute
+	// of any label is an expression, the controls with ids are assumed to be labelled.
+	labelled := make(map[string]bool)
+	var anyLabelled bool
+	parser.InspectFile(f.Template, func(n parser.Node) bool {
+		if e, ok := n.(parser.Element); ok && strings.EqualFold(e.Name, "label") {
+			attrs, spread := attributes(e)
+			if a, ok := attrs["for"]; spread || (ok && !a.constant) {
+				anyLabelled = true
+			} else if ok {
+				labelled[a.value] = true
+			}
+		}
+		return true
+	})
+	v := &inputLabelVisitor{
+		issues:      &issues,
+		labelled:    labelled,
+		anyLabelled: anyLabelled,
+	}
+	parser.WalkFile(v, f.Template)
+	return issues
+}
+
+// inputLabelVisitor visits the nodes of a template, keeping track of whether they're within a
+// <label>, or within the children of a component, which may render the label.
+type inputLabelVisitor struct {
+	issues      *[]parser.Issue
+	labelled    map[string]bool
+	anyLabelled bool
+	// inLabel is true within a <label>, or the children of a component.
+	inLabel bool
+}
+
+func (v *inputLabelVisitor) Visit(node parser.Node) parser.Visitor {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case parser.TemplElementExpression:
+		return &inputLabelVisitor{issues: v.issues, labelled: v.labelled, anyLabelled: v.anyLabelled, inLabel: true}
+	case parser.Element:
+		name := strings.ToLower(n.Name)
+		if name == "label" {
+			return &inputLabelVisitor{issues: v.issues, labelled: v.labelled, anyLabelled: v.anyLabelled, inLabel: true}
+		}
+		if !v.inLabel && v.needsLabel(n, name) {
+			*v.issues = append(*v.issues, parser.Issue{
+				Check:    CheckInputLabel,
+				Severity: parser.SeverityWarning,
+				Message:  fmt.Sprintf("<%s>: no label, add a <label for=\"id\">, or an aria-label attribute", n.Name),
+				Range:    n.NameRange,
+				Fix:      addAttributes(n, todoLabel),
+			})
+		}
+	}
+	return v
+}
+
+func (v *inputLabelVisitor) needsLabel(e parser.Element, name string) bool {
+	if name != "input" && name != "select" && name != "textarea" {
+		return false
+	}
+	attrs, spread := attributes(e)
+	if spread || hasName(attrs, "aria-label") || has(attrs, "aria-labelledby") || hasName(attrs, "title") {
+		return false
+	}
+	if t, ok := attrs["type"]; name == "input" && ok && (!t.constant || unlabelledInputTypes[strings.ToLower(t.value)]) {
+		return false
+	}
+	id, ok := attrs["id"]
+	if !ok {
+		return true
+	}
+	if !id.constant {
+		return !v.anyLabelled && len(v.labelled) == 0
+	}
+	return !v.anyLabelled && !v.labelled[id.value]
+}
+
+// headingOrderRule finds headings that skip a level within a template, e.g. an <h4> after an
+// <h2>, which makes the outline of the page hard to follow with a screen reader. The first
+// heading of each template isn't checked, because the template may be rendered within
+// another.
+type headingOrderRule struct{}
+
+func (headingOrderRule) Name() string { return CheckHeadingOrder }
+
+func (headingOrderRule) Check(f *File) (issues []parser.Issue) {
+	for _, t := range templates(f.Template) {
+		var previous int
+		parser.Inspect(parser.Element{Children: t.Children}, func(n parser.Node) bool {
+			e, ok := n.(parser.Element)
+			if !ok {
+				return true
+			}
+			level := headingLevel(e.Name)
+			if level == 0 {
+				return true
+			}
+			if previous > 0 && level > previous+1 {
+				issues = append(issues, parser.Issue{
+					Check:    CheckHeadingOrder,
+					Severity: parser.SeverityWarning,
+					Message:  fmt.Sprintf("<%s>: heading level skipped after <h%d>, use <h%d>", e.Name, previous, previous+1),
+					Range:    e.NameRange,
+				})
+			}
+			previous = level
+			return true
+		})
+	}
+	return issues
+}
+
+// headingLevel returns the level of a heading element, e.g. 2 for <h2>, or 0 if the element
+// isn't a heading.
+func headingLevel(name string) int {
+	if len(name) != 2 || (name[0] != 'h' && name[0] != 'H') || name[1] < '1' || name[1] > '6' {
+		return 0
+	}
+	return int(name[1] - '0')
+}
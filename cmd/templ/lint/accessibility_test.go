@@ -0,0 +1,103 @@
+package lint
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/txtar"
+)
+
+// readFixture reads the files of a txtar archive.
+func readFixture(t *testing.T, fileName string) (sections map[string]string) {
+	t.Helper()
+	archive, err := txtar.ParseFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	sections = make(map[string]string, len(archive.Files))
+	for _, f := range archive.Files {
+		sections[f.Name] = string(f.Data)
+	}
+	return sections
+}
+
+// applyFixes makes the edits of the fixes of the issues to the source.
+func applyFixes(src string, issues []parser.Issue) string {
+	var edits []parser.Edit
+	for _, issue := range issues {
+		if issue.Fix != nil {
+			edits = append(edits, issue.Fix.Edits...)
+		}
+	}
+	// Edits are made from the end of the file, so that the positions of the others don't move.
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Range.From.Index > edits[j].Range.From.Index
+	})
+	for _, e := range edits {
+		src = src[:e.Range.From.Index] + e.NewText + src[e.Range.To.Index:]
+	}
+	return src
+}
+
+func TestAccessibilityRules(t *testing.T) {
+	fileNames, err := filepath.Glob("testdata/accessibility/*.txtar")
+	if err != nil {
+		t.Fatalf("failed to find fixtures: %v", err)
+	}
+	if len(fileNames) == 0 {
+		t.Fatal("expected to find fixtures")
+	}
+	rules := make(map[string]Rule)
+	for _, r := range Rules {
+		rules[r.Name()] = r
+	}
+	for _, fileName := range fileNames {
+		fileName := fileName
+		name := strings.TrimSuffix(filepath.Base(fileName), ".txtar")
+		t.Run(name, func(t *testing.T) {
+			r, ok := rules[name]
+			if !ok {
+				t.Fatalf("fixture %q is named after an unknown rule", fileName)
+			}
+			fixture := readFixture(t, fileName)
+			linter := New([]Rule{r})
+			tf, err := parser.ParseString(fixture["input.templ"])
+			if err != nil {
+				t.Fatalf("failed to parse input.templ: %v", err)
+			}
+			issues := linter.Lint("", tf)
+			var actual []string
+			for _, issue := range issues {
+				actual = append(actual, issue.String())
+			}
+			expected := strings.Split(strings.TrimSpace(fixture["issues"]), "\n")
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("unexpected issues:\n%s", diff)
+			}
+
+			expectedFixed, hasFixes := fixture["fixed.templ"]
+			if !hasFixes {
+				for _, issue := range issues {
+					if issue.Fix != nil {
+						t.Errorf("expected no fixes, got %q for %s", issue.Fix.Title, issue)
+					}
+				}
+				return
+			}
+			fixed := applyFixes(fixture["input.templ"], issues)
+			if diff := cmp.Diff(expectedFixed, fixed); diff != "" {
+				t.Errorf("unexpected fixed.templ:\n%s", diff)
+			}
+			if tf, err = parser.ParseString(fixed); err != nil {
+				t.Fatalf("failed to parse the fixed template: %v", err)
+			}
+			if remaining := linter.Lint("", tf); len(remaining) > 0 {
+				t.Errorf("expected the fixes to resolve the issues, got %v", remaining)
+			}
+		})
+	}
+}
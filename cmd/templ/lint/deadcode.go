@@ -0,0 +1,282 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// deadCodeRule finds the content of templates that's never rendered, because an if or
+// else if condition is always false, because an earlier condition in the chain is always
+// true, or because a for loop ranges over an empty literal.
+//
+// There's no type information, so only conditions that are made of the literals true and
+// false, e.g. !true or (x && false), and loops over literals such as []string{} and "",
+// are known. Anything else is assumed to be rendered, so that there are no false
+// positives.
+type deadCodeRule struct{}
+
+func (deadCodeRule) Name() string { return CheckDeadCode }
+
+func (deadCodeRule) Check(f *File) (issues []parser.Issue) {
+	for _, t := range templates(f.Template) {
+		fn, ok := signature(t)
+		if !ok {
+			continue
+		}
+		// Variables named true or false would change the meaning of the conditions.
+		vars := append(names(fn.Recv), names(fn.Type.Params)...)
+		parser.Inspect(parser.Element{Children: t.Children}, func(n parser.Node) bool {
+			if n, ok := n.(parser.ForExpression); ok {
+				vars = append(vars, forVariables(n.Expression.Value)...)
+			}
+			return true
+		})
+		if shadowsBoolLiteral(vars) {
+			continue
+		}
+		v := &deadCodeVisitor{}
+		for _, n := range t.Children {
+			parser.Walk(v, n)
+		}
+		issues = append(issues, v.issues...)
+	}
+	if len(issues) > 0 {
+		if p := f.Package(); p.Declares("true") || p.Declares("false") {
+			return nil
+		}
+	}
+	return issues
+}
+
+func isBoolLiteral(name string) bool {
+	return name == "true" || name == "false"
+}
+
+func shadowsBoolLiteral(vars []string) bool {
+	for _, name := range vars {
+		if isBoolLiteral(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// deadCodeVisitor visits the content of a template that's rendered, and records the
+// content that isn't. Content that isn't rendered isn't visited, so that it's only
+// reported once.
+type deadCodeVisitor struct {
+	issues []parser.Issue
+}
+
+func (v *deadCodeVisitor) Visit(node parser.Node) parser.Visitor {
+	switch n := node.(type) {
+	case parser.IfExpression:
+		v.visitIf(n)
+		return nil
+	case parser.ForExpression:
+		if reason, ok := emptyLoop(n.Expression.Value); ok {
+			v.add(n.ChildrenRange, n.Children, fmt.Sprintf("for %s: %s, so its content is never rendered", strings.TrimSpace(n.Expression.Value), reason))
+			return nil
+		}
+	}
+	return v
+}
+
+// branch is the condition and content of an if or else if.
+type branch struct {
+	keyword string
+	cond    string
+	nodes   []parser.Node
+	r       parser.Range
+}
+
+// visitIf checks each branch of the if expression in turn. The branches after one whose
+// condition is always true are never rendered.
+func (v *deadCodeVisitor) visitIf(n parser.IfExpression) {
+	branches := []branch{{"if", n.Expression.Value, n.Then, n.ThenRange}}
+	for _, elseIf := range n.ElseIfs {
+		branches = append(branches, branch{"else if", elseIf.Expression.Value, elseIf.Then, elseIf.ThenRange})
+	}
+	var always string
+	for _, b := range branches {
+		cond := strings.TrimSpace(b.cond)
+		if always != "" {
+			v.add(b.r, b.nodes, fmt.Sprintf("%s %s: never rendered, because %q is always true", b.keyword, cond, always))
+			continue
+		}
+		value, ok := condition(cond)
+		if ok && !value {
+			v.add(b.r, b.nodes, fmt.Sprintf("%s %s: condition is always false, so its content is never rendered", b.keyword, cond))
+			continue
+		}
+		v.walk(b.nodes)
+		if ok && value {
+			always = b.keyword + " " + cond
+		}
+	}
+	if always != "" {
+		v.add(n.ElseRange, n.Else, fmt.Sprintf("else: never rendered, because %q is always true", always))
+		return
+	}
+	v.walk(n.Else)
+}
+
+func (v *deadCodeVisitor) walk(nodes []parser.Node) {
+	for _, n := range nodes {
+		parser.Walk(v, n)
+	}
+}
+
+// add records the range as never rendered, unless it's empty.
+func (v *deadCodeVisitor) add(r parser.Range, nodes []parser.Node, msg string) {
+	if len(nodes) == 0 || r.To.Index <= r.From.Index {
+		return
+	}
+	v.issues = append(v.issues, parser.Issue{
+		Check:       CheckDeadCode,
+		Severity:    parser.SeverityHint,
+		Message:     msg,
+		Range:       r,
+		Unnecessary: true,
+	})
+}
+
+// statement parses a Go statement, e.g. "if x := f(); x {}".
+func statement(src string) (ast.Stmt, bool) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc _() {\n"+src+"\n}", goparser.SkipObjectResolution)
+	if err != nil || len(f.Decls) != 1 {
+		return nil, false
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok || len(fn.Body.List) != 1 {
+		return nil, false
+	}
+	return fn.Body.List[0], true
+}
+
+// condition returns the value of the condition of an if expression, e.g. "false", if it's
+// always the same. Conditions that have an init statement that declares true or false
+// aren't known.
+func condition(src string) (value, ok bool) {
+	stmt, ok := statement("if " + src + " {}")
+	if !ok {
+		return false, false
+	}
+	ifStmt, ok := stmt.(*ast.IfStmt)
+	if !ok || declaresBoolLiteral(ifStmt.Init) {
+		return false, false
+	}
+	return constant(ifStmt.Cond)
+}
+
+// constant returns the value of a boolean expression that's made of the literals true and
+// false, and so has the same value whatever the variables are, e.g. !false or x && false.
+func constant(e ast.Expr) (value, ok bool) {
+	switch e := e.(type) {
+	case *ast.Ident:
+		if isBoolLiteral(e.Name) {
+			return e.Name == "true", true
+		}
+	case *ast.ParenExpr:
+		return constant(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			value, ok = constant(e.X)
+			return !value, ok
+		}
+	case *ast.BinaryExpr:
+		x, xok := constant(e.X)
+		y, yok := constant(e.Y)
+		switch e.Op {
+		case token.LAND:
+			if (xok && !x) || (yok && !y) {
+				return false, true
+			}
+			return true, xok && yok
+		case token.LOR:
+			if (xok && x) || (yok && y) {
+				return true, true
+			}
+			return false, xok && yok
+		}
+	}
+	return false, false
+}
+
+// emptyLoop returns the reason that a for loop never runs its body, e.g. because it ranges
+// over "[]string{}", or "for false".
+func emptyLoop(src string) (reason string, ok bool) {
+	stmt, ok := statement("for " + src + " {}")
+	if !ok {
+		return "", false
+	}
+	switch stmt := stmt.(type) {
+	case *ast.ForStmt:
+		if stmt.Cond == nil || declaresBoolLiteral(stmt.Init) {
+			return "", false
+		}
+		if value, ok := constant(stmt.Cond); ok && !value {
+			return "condition is always false", true
+		}
+	case *ast.RangeStmt:
+		if isEmptyLiteral(stmt.X) {
+			return "ranges over an empty literal", true
+		}
+	}
+	return "", false
+}
+
+// isEmptyLiteral returns true if the expression is a slice, array or map literal without
+// elements, e.g. []string{}, or an empty string literal.
+func isEmptyLiteral(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return isEmptyLiteral(e.X)
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return err == nil && s == ""
+	case *ast.CompositeLit:
+		if len(e.Elts) > 0 {
+			return false
+		}
+		switch t := e.Type.(type) {
+		case *ast.MapType:
+			return true
+		case *ast.ArrayType:
+			// [3]int{} has 3 elements, but []int{}, [...]int{} and [0]int{} don't.
+			if t.Len == nil {
+				return true
+			}
+			if _, ok := t.Len.(*ast.Ellipsis); ok {
+				return true
+			}
+			lit, ok := t.Len.(*ast.BasicLit)
+			return ok && lit.Kind == token.INT && lit.Value == "0"
+		}
+	}
+	return false
+}
+
+// declaresBoolLiteral returns true if the statement declares a variable named true or
+// false, e.g. "false := x".
+func declaresBoolLiteral(stmt ast.Stmt) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE {
+		return false
+	}
+	for _, lhs := range assign.Lhs {
+		if id, ok := lhs.(*ast.Ident); ok && isBoolLiteral(id.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// ignoreSet is a set of check names. The empty name represents every check.
+type ignoreSet map[string]struct{}
+
+func newIgnoreSet(checks []string) ignoreSet {
+	s := make(ignoreSet)
+	for _, c := range checks {
+		s[c] = struct{}{}
+	}
+	if len(s) == 0 {
+		s[""] = struct{}{}
+	}
+	return s
+}
+
+func (s ignoreSet) has(check string) bool {
+	if _, ok := s[""]; ok {
+		return true
+	}
+	_, ok := s[check]
+	return ok
+}
+
+func (s ignoreSet) union(other ignoreSet) ignoreSet {
+	if len(other) == 0 {
+		return s
+	}
+	u := make(ignoreSet, len(s)+len(other))
+	for k := range s {
+		u[k] = struct{}{}
+	}
+	for k := range other {
+		u[k] = struct{}{}
+	}
+	return u
+}
+
+// suppress removes the issues that are ignored by //templ:ignore comments. As with
+// parser.Validate, a comment within a template applies to the next node and its children.
+// A comment on the line before a template applies to the whole template.
+func suppress(tf parser.TemplateFile, issues []parser.Issue) []parser.Issue {
+	if len(issues) == 0 {
+		return issues
+	}
+	ignored := make(map[parser.Range]ignoreSet)
+	var next ignoreSet
+	for _, n := range tf.Nodes {
+		switch n := n.(type) {
+		case parser.GoExpression:
+			next = templateDirective(n.Expression.Value)
+			continue
+		case parser.HTMLTemplate:
+			if n.Doc != "" {
+				next = templateDirective(n.Doc)
+			}
+			v := &ignorer{ranges: ignored, ignored: next}
+			if len(next) > 0 {
+				ignored[n.Expression.Range] = next
+			}
+			for _, child := range n.Children {
+				parser.Walk(v, child)
+			}
+		}
+		next = nil
+	}
+	kept := issues[:0]
+	for _, issue := range issues {
+		if !ignored[issue.Range].has(issue.Check) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// templateDirective returns the checks ignored by the last line of the Go code or doc
+// comments, if it's an ignore directive.
+func templateDirective(code string) ignoreSet {
+	lines := strings.Split(strings.TrimSpace(code), "\n")
+	comment, ok := strings.CutPrefix(strings.TrimSpace(lines[len(lines)-1]), "//")
+	if !ok {
+		return nil
+	}
+	checks, ok := parser.IgnoredChecks(comment)
+	if !ok {
+		return nil
+	}
+	return newIgnoreSet(checks)
+}
+
+// ignorer records the checks that are ignored for the ranges of the nodes it visits.
+type ignorer struct {
+	ranges  map[parser.Range]ignoreSet
+	ignored ignoreSet
+	next    ignoreSet
+}
+
+func (v *ignorer) Visit(node parser.Node) parser.Visitor {
+	switch n := node.(type) {
+	case nil, parser.Whitespace:
+		return nil
+	case parser.GoComment:
+		if checks, ok := parser.IgnoredChecks(n.Contents); ok {
+			v.next = newIgnoreSet(checks)
+			return nil
+		}
+	}
+	ignored := v.ignored.union(v.next)
+	v.next = nil
+	if len(ignored) > 0 {
+		switch n := node.(type) {
+		case parser.Element:
+			v.ranges[n.NameRange] = ignored
+		case parser.StringExpression:
+			v.ranges[n.Expression.Range] = ignored
+		case parser.IfExpression:
+			v.ranges[n.ThenRange] = ignored
+			for _, elseIf := range n.ElseIfs {
+				v.ranges[elseIf.ThenRange] = ignored
+			}
+			v.ranges[n.ElseRange] = ignored
+		case parser.ForExpression:
+			v.ranges[n.ChildrenRange] = ignored
+		}
+	}
+	return &ignorer{ranges: v.ranges, ignored: ignored}
+}
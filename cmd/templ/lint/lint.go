@@ -0,0 +1,202 @@
+// Package lint checks templ files for mistakes and style issues with a set of rules, so
+// that templ lint and the language server report the same issues.
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// The checks carried out by the rules in this package, in addition to the checks carried out
+// by parser.Validate.
+const (
+	CheckUnusedParameter      = "unused-parameter"
+	CheckUnusedComponent      = "unused-component"
+	CheckUnrenderedExpression = "unrendered-expression"
+	CheckInlineStyle          = "inline-style"
+	CheckHardcodedText        = "hardcoded-text"
+	CheckAccessibleName       = "accessible-name"
+	CheckAnchorHref           = "anchor-href"
+	CheckClickHandlerRole     = "click-handler-role"
+	CheckInputLabel           = "input-label"
+	CheckHeadingOrder         = "heading-order"
+	CheckDeadCode             = "dead-code"
+)
+
+// Rule checks templ files for one kind of issue.
+type Rule interface {
+	// Name of the rule, e.g. "no-alt". It's the Check of the issues that the rule finds, and
+	// is used to enable, disable and ignore the rule.
+	Name() string
+	// Check returns the issues found in the file.
+	Check(f *File) []parser.Issue
+}
+
+// Rules are the built-in rules.
+var Rules = func() (rules []Rule) {
+	for _, check := range parser.Checks {
+		rules = append(rules, validateRule(check))
+	}
+	return append(rules,
+		unusedParameterRule{},
+		unusedComponentRule{},
+		unrenderedExpressionRule{},
+		inlineStyleRule{},
+		hardcodedTextRule{},
+		accessibleNameRule{},
+		anchorHrefRule{},
+		clickHandlerRoleRule{},
+		inputLabelRule{},
+		headingOrderRule{},
+		deadCodeRule{},
+	)
+}()
+
+// optional rules aren't run unless they're enabled, because they're a matter of style, or only
+// apply to some projects, e.g. projects that are translated.
+var optional = map[string]bool{
+	CheckInlineStyle:   true,
+	CheckHardcodedText: true,
+}
+
+// Names returns the names of the built-in rules.
+func Names() (names []string) {
+	for _, r := range Rules {
+		names = append(names, r.Name())
+	}
+	return names
+}
+
+// Select returns the built-in rules that are run by default, with the enabled rules added,
+// and the disabled rules removed. Names that aren't built-in rules are listed in the error,
+// and are otherwise ignored.
+func Select(enable, disable []string) (rules []Rule, err error) {
+	known := make(map[string]bool)
+	for _, r := range Rules {
+		known[r.Name()] = true
+	}
+	var unknown []string
+	selected := make(map[string]bool)
+	for _, r := range Rules {
+		selected[r.Name()] = !optional[r.Name()]
+	}
+	for _, names := range []struct {
+		names   []string
+		enabled bool
+	}{{enable, true}, {disable, false}} {
+		for _, name := range names.names {
+			if !known[name] {
+				unknown = append(unknown, name)
+				continue
+			}
+			selected[name] = names.enabled
+		}
+	}
+	for _, r := range Rules {
+		if selected[r.Name()] {
+			rules = append(rules, r)
+		}
+	}
+	if len(unknown) > 0 {
+		err = fmt.Errorf("unknown lint rules: %s, expected one of: %s", strings.Join(unknown, ", "), strings.Join(Names(), ", "))
+	}
+	return rules, err
+}
+
+// Linter checks templ files with a set of rules. It caches the packages that the files are
+// in, so that rules can check references between the files of a package. It's safe for
+// concurrent use.
+type Linter struct {
+	rules []Rule
+
+	m        sync.Mutex
+	packages map[string]*Package
+}
+
+// New creates a Linter that checks files with the rules.
+func New(rules []Rule) *Linter {
+	return &Linter{
+		rules:    rules,
+		packages: make(map[string]*Package),
+	}
+}
+
+// Lint returns the issues found in the template by the rules, in the order of their
+// positions. Issues suppressed by ignore directives aren't returned. The fileName is used to
+// find the other files in the package. If it's empty, the template is checked on its own.
+func (l *Linter) Lint(fileName string, tf parser.TemplateFile) (issues []parser.Issue) {
+	f := &File{
+		FileName: fileName,
+		Template: tf,
+		linter:   l,
+	}
+	for _, r := range l.rules {
+		issues = append(issues, r.Check(f)...)
+	}
+	issues = suppress(tf, issues)
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Range.From.Index < issues[j].Range.From.Index
+	})
+	return issues
+}
+
+func (l *Linter) loadPackage(dir string) *Package {
+	l.m.Lock()
+	defer l.m.Unlock()
+	p, ok := l.packages[dir]
+	if !ok {
+		p = loadPackage(dir)
+		l.packages[dir] = p
+	}
+	return p
+}
+
+// File is a templ file being checked.
+type File struct {
+	// FileName of the templ file, which may be empty.
+	FileName string
+	// Template is the parsed templ file.
+	Template parser.TemplateFile
+
+	linter *Linter
+	// issues found by parser.Validate, which are shared by the rules that wrap its checks.
+	issues    []parser.Issue
+	validated bool
+}
+
+// Package returns the package that the file is in, read from the directory that contains
+// the file. The file's template replaces the version on disk, which may not be saved.
+func (f *File) Package() *Package {
+	p := &Package{Templates: make(map[string]parser.TemplateFile)}
+	if f.FileName != "" {
+		p = f.linter.loadPackage(filepath.Dir(f.FileName))
+	}
+	return p.with(f.FileName, f.Template)
+}
+
+func (f *File) validate() []parser.Issue {
+	if !f.validated {
+		f.issues = parser.Validate(f.Template)
+		f.validated = true
+	}
+	return f.issues
+}
+
+// validateRule is one of the checks carried out by parser.Validate.
+type validateRule string
+
+func (r validateRule) Name() string { return string(r) }
+
+func (r validateRule) Check(f *File) (issues []parser.Issue) {
+	for _, issue := range f.validate() {
+		if issue.Check == string(r) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,394 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func lint(t *testing.T, l *Linter, fileName, input string) (actual []string) {
+	t.Helper()
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	for _, issue := range l.Lint(fileName, tf) {
+		actual = append(actual, issue.String())
+	}
+	return actual
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		enable   []string
+		input    string
+		expected []string
+	}{
+		{
+			name: "templates that use their parameters have no issues",
+			input: `package main
+
+templ Page(title string, items []Item, show bool) {
+	<h1>{ title }</h1>
+	if show {
+		for _, item := range items {
+			@row(item)
+		}
+	}
+}
+
+templ row(item Item) {
+	<p class={ item.Class }>{ item.Name }</p>
+}
+`,
+		},
+		{
+			name: "unused parameters",
+			input: `package main
+
+templ Page(title string, _ int, count int) {
+	<h1>{ title }</h1>
+}
+`,
+			expected: []string{`3:7: warning: templ Page: parameter "count" is not used (unused-parameter)`},
+		},
+		{
+			name: "unused components",
+			input: `package main
+
+templ Page() {
+	@used()
+}
+
+templ used() {
+	<p>Used</p>
+}
+
+templ unused() {
+	<p>Unused</p>
+}
+`,
+			expected: []string{`11:7: warning: templ unused: component is not used in the package (unused-component)`},
+		},
+		{
+			name: "fields of parameters written as text",
+			input: `package main
+
+templ Page(item Item) {
+	<p>Hello, item.Name</p>
+	<p>{ item.Name } e.g. item.name</p>
+}
+`,
+			expected: []string{`4:3: warning: <p>: "item.Name" looks like a Go expression, use { item.Name } to render it (unrendered-expression)`},
+		},
+		{
+			name: "fields of loop variables written as text",
+			input: `package main
+
+templ Page(items []Item) {
+	for _, item := range items {
+		<p>item.Name</p>
+	}
+}
+`,
+			expected: []string{`5:4: warning: <p>: "item.Name" looks like a Go expression, use { item.Name } to render it (unrendered-expression)`},
+		},
+		{
+			name: "double braces",
+			input: `package main
+
+templ Page(name string) {
+	<p>{{ name }}</p>
+}
+`,
+			expected: []string{`4:6: warning: <p>: {{ name }} uses double braces, use { name } to render it (unrendered-expression)`},
+		},
+		{
+			name: "the checks of parser.Validate are included",
+			input: `package main
+
+templ Page() {
+	<img src="a.png"/>
+}
+`,
+			expected: []string{`4:3: warning: <img>: missing alt attribute, use alt="" for decorative images (no-alt)`},
+		},
+		{
+			name: "inline styles are only reported if enabled, and the package has css components",
+			input: `package main
+
+templ Page() {
+	<p style="color: red">Hello</p>
+}
+`,
+			enable: []string{CheckInlineStyle},
+		},
+		{
+			name: "inline styles",
+			input: `package main
+
+css red() {
+	color: red;
+}
+
+templ Page() {
+	<p style="color: red">Hello</p>
+}
+`,
+			enable:   []string{CheckInlineStyle},
+			expected: []string{`8:3: warning: <p>: inline style attribute, use a css component instead (inline-style)`},
+		},
+		{
+			name: "hard-coded text",
+			input: `package main
+
+templ Page(name string) {
+	<h1 title="Welcome">Welcome, { name }!</h1>
+	<img src="a.png" alt="Logo"/>
+	<p>{ t("home.intro") }</p>
+	<code>go run .</code>
+	<p translate="no">templ</p>
+	<p>|</p>
+	Footer
+}
+`,
+			enable: []string{CheckHardcodedText},
+			expected: []string{
+				`3:7: warning: templ Page: hard-coded text "Footer", use { t(...) } to translate it (hardcoded-text)`,
+				`4:3: warning: <h1>: hard-coded title attribute "Welcome", use title={ t(...) } to translate it (hardcoded-text)`,
+				`4:3: warning: <h1>: hard-coded text "Welcome,", use { t(...) } to translate it (hardcoded-text)`,
+				`5:3: warning: <img>: hard-coded alt attribute "Logo", use alt={ t(...) } to translate it (hardcoded-text)`,
+			},
+		},
+		{
+			name: "ignore directives within templates",
+			input: `package main
+
+templ Page(item Item) {
+	<h1>{ item.Title }</h1>
+	//templ:ignore unrendered-expression
+	<p>item.Name</p>
+	//templ:ignore no-alt
+	<div>
+		<img src="a.png"/>
+		<p>item.Name</p>
+	</div>
+}
+`,
+			expected: []string{`10:4: warning: <p>: "item.Name" looks like a Go expression, use { item.Name } to render it (unrendered-expression)`},
+		},
+		{
+			name: "content that is never rendered is dead code",
+			input: `package main
+
+templ Page(items []string, show bool) {
+	if false {
+		<p>Never</p>
+	}
+	if show && false {
+		<p>Never</p>
+	} else if show || true {
+		<p>Always</p>
+	} else if show {
+		<p>Never</p>
+	} else {
+		<p>Never</p>
+	}
+	for _, item := range []string{} {
+		<p>{ item }</p>
+	}
+	for _, item := range items {
+		<p>{ item }</p>
+	}
+}
+`,
+			expected: []string{
+				`5:1: hint: if false: condition is always false, so its content is never rendered (dead-code)`,
+				`8:1: hint: if show && false: condition is always false, so its content is never rendered (dead-code)`,
+				`12:1: hint: else if show: never rendered, because "else if show || true" is always true (dead-code)`,
+				`14:3: hint: else: never rendered, because "else if show || true" is always true (dead-code)`,
+				`17:1: hint: for _, item := range []string{}: ranges over an empty literal, so its content is never rendered (dead-code)`,
+			},
+		},
+		{
+			name: "conditions that can't be proved without type information aren't dead code",
+			input: `package main
+
+const enabled = false
+
+templ Page(items []string) {
+	if enabled {
+		<p>Enabled</p>
+	}
+	if x := len(items); x == 0 {
+		<p>Empty</p>
+	}
+	for _, item := range [2]string{} {
+		<p>{ item }</p>
+	}
+	for i := 0; i < 0; i++ {
+		<p>Never</p>
+	}
+}
+`,
+		},
+		{
+			name: "dead code can be ignored",
+			input: `package main
+
+templ Page() {
+	//templ:ignore dead-code
+	if false {
+		<p>Never</p>
+	} else {
+		<p>Always</p>
+	}
+}
+`,
+		},
+		{
+			name: "conditions aren't known if true or false is redeclared",
+			input: `package main
+
+const false = true
+
+templ Page() {
+	if false {
+		<p>Rendered</p>
+	}
+}
+
+templ Other(true bool) {
+	if true {
+		<p>Rendered</p>
+	} else {
+		<p>Rendered</p>
+	}
+}
+`,
+		},
+		{
+			name: "ignore directives before templates",
+			input: `package main
+
+//templ:ignore unused-parameter no-alt
+templ Page(title string) {
+	<img src="a.png"/>
+}
+
+//templ:ignore
+templ unused(title string) {
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Select(tt.enable, nil)
+			if err != nil {
+				t.Fatalf("failed to select rules: %v", err)
+			}
+			actual := lint(t, New(rules), "", tt.input)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Run("optional rules are not selected by default", func(t *testing.T) {
+		rules, err := Select(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, r := range rules {
+			if r.Name() == CheckInlineStyle {
+				t.Errorf("expected %q not to be selected", CheckInlineStyle)
+			}
+		}
+	})
+	t.Run("rules can be enabled and disabled", func(t *testing.T) {
+		rules, err := Select([]string{CheckInlineStyle}, []string{CheckUnusedParameter, parser.CheckNoAlt})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		selected := make(map[string]bool)
+		for _, r := range rules {
+			selected[r.Name()] = true
+		}
+		if !selected[CheckInlineStyle] || selected[CheckUnusedParameter] || selected[parser.CheckNoAlt] || !selected[CheckUnusedComponent] {
+			t.Errorf("unexpected rules: %v", selected)
+		}
+	})
+	t.Run("unknown rules are an error", func(t *testing.T) {
+		rules, err := Select(nil, []string{"no-such-rule"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if len(rules) == 0 {
+			t.Error("expected the known rules to be selected")
+		}
+	})
+}
+
+func TestPackage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.20\n",
+		"main.go": `package main
+
+func main() {
+	_ = usedFromGo()
+}
+`,
+		"other.templ": `package main
+
+templ usedFromTempl() {
+	<p>Hello</p>
+}
+
+css red() {
+	color: red;
+}
+`,
+		"other_templ.go": `// Code generated by templ - DO NOT EDIT.
+
+package main
+
+func usedFromGenerated() {}
+`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	input := `package main
+
+templ usedFromGo() {
+	@usedFromTempl()
+}
+
+templ usedFromGenerated() {
+}
+
+templ Page() {
+	<p style="color: red">Hello</p>
+}
+`
+	rules, _ := Select([]string{CheckInlineStyle}, nil)
+	actual := lint(t, New(rules), filepath.Join(dir, "index.templ"), input)
+	expected := []string{
+		`7:7: warning: templ usedFromGenerated: component is not used in the package (unused-component)`,
+		`11:3: warning: <p>: inline style attribute, use a css component instead (inline-style)`,
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,257 @@
+package lint
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Package is the templ and Go files in a directory.
+type Package struct {
+	// Templates are the templ files in the package, by file name. Files that can't be parsed
+	// aren't included.
+	Templates map[string]parser.TemplateFile
+	// goUses counts the identifiers in the Go files of the package that weren't generated by
+	// templ.
+	goUses map[string]int
+	// goDeclarations are the names declared at the top level of the Go files of the package
+	// that weren't generated by templ.
+	goDeclarations map[string]bool
+}
+
+// loadPackage reads the package in the directory. Files that can't be read or parsed are
+// skipped, because they're reported by the Go compiler and templ generate.
+func loadPackage(dir string) *Package {
+	p := &Package{
+		Templates:      make(map[string]parser.TemplateFile),
+		goUses:         make(map[string]int),
+		goDeclarations: make(map[string]bool),
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return p
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		fileName := filepath.Join(dir, entry.Name())
+		switch filepath.Ext(fileName) {
+		case ".templ":
+			if tf, err := parser.ParseFile(fileName); err == nil {
+				p.Templates[fileName] = tf
+			}
+		case ".go":
+			src, err := os.ReadFile(fileName)
+			if err != nil {
+				continue
+			}
+			if _, generated := generator.ReadHeader(strings.NewReader(string(src))); generated {
+				continue
+			}
+			f, err := goparser.ParseFile(fset, fileName, src, goparser.SkipObjectResolution)
+			if err != nil {
+				continue
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					p.goUses[id.Name]++
+				}
+				return true
+			})
+			for _, name := range declarations(f) {
+				p.goDeclarations[name] = true
+			}
+		}
+	}
+	return p
+}
+
+// with returns a copy of the package that contains the template.
+func (p *Package) with(fileName string, tf parser.TemplateFile) *Package {
+	c := &Package{
+		Templates:      make(map[string]parser.TemplateFile, len(p.Templates)+1),
+		goUses:         p.goUses,
+		goDeclarations: p.goDeclarations,
+	}
+	for k, v := range p.Templates {
+		c.Templates[k] = v
+	}
+	c.Templates[fileName] = tf
+	return c
+}
+
+// Uses returns the number of times that the name is used as an identifier in the Go code of
+// the package, and in the Go expressions of its templ files. The signatures of templates
+// aren't included, so that a template isn't used by its own declaration.
+func (p *Package) Uses(name string) (count int) {
+	count = p.goUses[name]
+	for _, tf := range p.Templates {
+		for _, n := range tf.Nodes {
+			switch n := n.(type) {
+			case parser.GoExpression:
+				count += countIdentifier(n.Expression.Value, name)
+			case parser.HTMLTemplate:
+				expressions(n.Children, func(e parser.Expression) {
+					count += countIdentifier(e.Value, name)
+				})
+			case parser.CSSTemplate:
+				for _, prop := range n.Properties {
+					if prop, ok := prop.(parser.ExpressionCSSProperty); ok {
+						count += countIdentifier(prop.Value.Expression.Value, name)
+					}
+				}
+			}
+		}
+	}
+	return count
+}
+
+// Declares returns true if the name is declared at the top level of the Go code of the
+// package, or of the Go code of its templ files, e.g. by a var, const or func declaration.
+func (p *Package) Declares(name string) bool {
+	if p.goDeclarations[name] {
+		return true
+	}
+	for _, tf := range p.Templates {
+		for _, n := range tf.Nodes {
+			e, ok := n.(parser.GoExpression)
+			if !ok {
+				continue
+			}
+			f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\n"+e.Expression.Value, goparser.SkipObjectResolution)
+			if err != nil {
+				continue
+			}
+			for _, declared := range declarations(f) {
+				if declared == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// declarations returns the names declared at the top level of the file. Methods aren't
+// included, because they're not in the scope of the package.
+func declarations(f *ast.File) (names []string) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				names = append(names, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// HasCSS returns true if the package contains css components.
+func (p *Package) HasCSS() bool {
+	for _, tf := range p.Templates {
+		for _, n := range tf.Nodes {
+			if _, ok := n.(parser.CSSTemplate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func countIdentifier(src, name string) (count int) {
+	identifiers(src, func(id string) {
+		if id == name {
+			count++
+		}
+	})
+	return count
+}
+
+// identifiers calls f for each identifier in the Go code, except for the names of fields
+// and methods that follow a period, e.g. Name in item.Name.
+func identifiers(src string, f func(name string)) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, 0)
+	previous := token.ILLEGAL
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return
+		}
+		if tok == token.IDENT && previous != token.PERIOD {
+			f(lit)
+		}
+		previous = tok
+	}
+}
+
+// expressions calls f for each Go expression within the nodes, including the expressions
+// in attributes.
+func expressions(nodes []parser.Node, f func(parser.Expression)) {
+	for _, n := range nodes {
+		parser.Inspect(n, func(n parser.Node) bool {
+			switch n := n.(type) {
+			case parser.Element:
+				attributeExpressions(n.Attributes, f)
+			case parser.StringExpression:
+				f(n.Expression)
+			case parser.TemplElementExpression:
+				f(n.Expression)
+			case parser.CallTemplateExpression:
+				f(n.Expression)
+			case parser.IfExpression:
+				f(n.Expression)
+				for _, elseIf := range n.ElseIfs {
+					f(elseIf.Expression)
+				}
+			case parser.SwitchExpression:
+				f(n.Expression)
+				for _, c := range n.Cases {
+					f(c.Expression)
+				}
+			case parser.ForExpression:
+				f(n.Expression)
+			}
+			return true
+		})
+	}
+}
+
+func attributeExpressions(attrs []parser.Attribute, f func(parser.Expression)) {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case parser.ExpressionAttribute:
+			f(attr.Expression)
+		case parser.BoolExpressionAttribute:
+			f(attr.Expression)
+		case parser.SpreadAttributes:
+			f(attr.Expression)
+		case parser.ConditionalAttribute:
+			f(attr.Expression)
+			attributeExpressions(attr.Then, f)
+			attributeExpressions(attr.Else, f)
+		}
+	}
+}
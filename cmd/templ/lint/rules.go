@@ -0,0 +1,288 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/i18n"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// signature parses the signature of the template, e.g. "(p Page) Title(name string)".
+func signature(t parser.HTMLTemplate) (fn *ast.FuncDecl, ok bool) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc "+t.Expression.Value+" {}", goparser.SkipObjectResolution)
+	if err != nil || len(f.Decls) != 1 {
+		return nil, false
+	}
+	fn, ok = f.Decls[0].(*ast.FuncDecl)
+	return fn, ok
+}
+
+func names(fields *ast.FieldList) (names []string) {
+	if fields == nil {
+		return nil
+	}
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+func templates(tf parser.TemplateFile) (templates []parser.HTMLTemplate) {
+	for _, n := range tf.Nodes {
+		if t, ok := n.(parser.HTMLTemplate); ok {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+// unusedParameterRule finds template parameters that aren't used by the template.
+type unusedParameterRule struct{}
+
+func (unusedParameterRule) Name() string { return CheckUnusedParameter }
+
+func (unusedParameterRule) Check(f *File) (issues []parser.Issue) {
+	for _, t := range templates(f.Template) {
+		fn, ok := signature(t)
+		if !ok {
+			continue
+		}
+		used := make(map[string]bool)
+		expressions(t.Children, func(e parser.Expression) {
+			identifiers(e.Value, func(name string) {
+				used[name] = true
+			})
+		})
+		for _, name := range names(fn.Type.Params) {
+			if name == "_" || used[name] {
+				continue
+			}
+			issues = append(issues, parser.Issue{
+				Check:    CheckUnusedParameter,
+				Severity: parser.SeverityWarning,
+				Message:  fmt.Sprintf("templ %s: parameter %q is not used", fn.Name.Name, name),
+				Range:    t.Expression.Range,
+			})
+		}
+	}
+	return issues
+}
+
+// unusedComponentRule finds unexported templates that aren't used by the package. Exported
+// templates may be used by other packages, so they aren't checked.
+type unusedComponentRule struct{}
+
+func (unusedComponentRule) Name() string { return CheckUnusedComponent }
+
+func (unusedComponentRule) Check(f *File) (issues []parser.Issue) {
+	var p *Package
+	for _, t := range templates(f.Template) {
+		fn, ok := signature(t)
+		if !ok || fn.Recv != nil || fn.Name.IsExported() || fn.Name.Name == "_" {
+			continue
+		}
+		if p == nil {
+			p = f.Package()
+		}
+		if p.Uses(fn.Name.Name) > 0 {
+			continue
+		}
+		issues = append(issues, parser.Issue{
+			Check:    CheckUnusedComponent,
+			Severity: parser.SeverityWarning,
+			Message:  fmt.Sprintf("templ %s: component is not used in the package", fn.Name.Name),
+			Range:    t.Expression.Range,
+		})
+	}
+	return issues
+}
+
+// unrenderedExpressionRule finds Go expressions that are written as text, and so are
+// rendered as they're written, instead of their value, e.g. "Hello, item.Name" instead of
+// "Hello, { item.Name }", and "{{ item.Name }}".
+type unrenderedExpressionRule struct{}
+
+func (unrenderedExpressionRule) Name() string { return CheckUnrenderedExpression }
+
+func (unrenderedExpressionRule) Check(f *File) (issues []parser.Issue) {
+	for _, t := range templates(f.Template) {
+		fn, ok := signature(t)
+		if !ok {
+			continue
+		}
+		vars := append(names(fn.Recv), names(fn.Type.Params)...)
+		parser.Inspect(parser.Element{Children: t.Children}, func(n parser.Node) bool {
+			if n, ok := n.(parser.ForExpression); ok {
+				vars = append(vars, forVariables(n.Expression.Value)...)
+			}
+			return true
+		})
+		v := &unrenderedVisitor{
+			issues:    &issues,
+			selectors: selectorPattern(vars),
+			rng:       t.Expression.Range,
+			name:      "templ " + fn.Name.Name,
+		}
+		for _, n := range t.Children {
+			parser.Walk(v, n)
+		}
+	}
+	return issues
+}
+
+// forVariables returns the variables declared by a for loop, e.g. "_, item := range items".
+func forVariables(expr string) (vars []string) {
+	lhs, _, ok := strings.Cut(expr, ":=")
+	if !ok {
+		return nil
+	}
+	for _, name := range strings.Split(lhs, ",") {
+		if name = strings.TrimSpace(name); name != "" && name != "_" {
+			vars = append(vars, name)
+		}
+	}
+	return vars
+}
+
+// selectorPattern matches the exported fields and methods of the variables, e.g. item.Name.
+func selectorPattern(vars []string) *regexp.Regexp {
+	var alternatives []string
+	for _, v := range vars {
+		if v != "_" && token.IsIdentifier(v) {
+			alternatives = append(alternatives, regexp.QuoteMeta(v))
+		}
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(alternatives, "|") + `)(?:\.[A-Z]\w*)+`)
+}
+
+// unrenderedVisitor visits the nodes of a template, keeping track of the nearest element,
+// because text doesn't have a position.
+type unrenderedVisitor struct {
+	issues    *[]parser.Issue
+	selectors *regexp.Regexp
+	rng       parser.Range
+	name      string
+}
+
+func (v *unrenderedVisitor) Visit(node parser.Node) parser.Visitor {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case parser.Element:
+		return &unrenderedVisitor{
+			issues:    v.issues,
+			selectors: v.selectors,
+			rng:       n.NameRange,
+			name:      "<" + n.Name + ">",
+		}
+	case parser.Text:
+		if v.selectors == nil {
+			return nil
+		}
+		for _, expr := range v.selectors.FindAllString(n.Value, -1) {
+			v.add(v.rng, fmt.Sprintf("%s: %q looks like a Go expression, use { %s } to render it", v.name, expr, expr))
+		}
+		return nil
+	case parser.StringExpression:
+		expr := strings.TrimSpace(n.Expression.Value)
+		if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+			inner := strings.TrimSpace(expr[1 : len(expr)-1])
+			v.add(n.Expression.Range, fmt.Sprintf("%s: {{ %s }} uses double braces, use { %s } to render it", v.name, inner, inner))
+		}
+		return nil
+	}
+	return v
+}
+
+func (v *unrenderedVisitor) add(r parser.Range, msg string) {
+	*v.issues = append(*v.issues, parser.Issue{
+		Check:    CheckUnrenderedExpression,
+		Severity: parser.SeverityWarning,
+		Message:  msg,
+		Range:    r,
+	})
+}
+
+// inlineStyleRule finds style attributes in packages that have css components, which
+// should be used instead. It's optional.
+type inlineStyleRule struct{}
+
+func (inlineStyleRule) Name() string { return CheckInlineStyle }
+
+func (inlineStyleRule) Check(f *File) (issues []parser.Issue) {
+	var elements []parser.Element
+	parser.InspectFile(f.Template, func(n parser.Node) bool {
+		e, ok := n.(parser.Element)
+		if !ok {
+			return true
+		}
+		for _, attr := range e.Attributes {
+			switch attr := attr.(type) {
+			case parser.ConstantAttribute:
+				if strings.EqualFold(attr.Name, "style") {
+					elements = append(elements, e)
+				}
+			case parser.ExpressionAttribute:
+				if strings.EqualFold(attr.Name, "style") {
+					elements = append(elements, e)
+				}
+			}
+		}
+		return true
+	})
+	if len(elements) == 0 || !f.Package().HasCSS() {
+		return nil
+	}
+	for _, e := range elements {
+		issues = append(issues, parser.Issue{
+			Check:    CheckInlineStyle,
+			Severity: parser.SeverityWarning,
+			Message:  fmt.Sprintf("<%s>: inline style attribute, use a css component instead", e.Name),
+			Range:    e.NameRange,
+		})
+	}
+	return issues
+}
+
+// hardcodedTextRule finds user-visible text that's written in templates, instead of being
+// translated with { t("message.id") }, for projects that are translated. It's optional.
+type hardcodedTextRule struct{}
+
+func (hardcodedTextRule) Name() string { return CheckHardcodedText }
+
+func (hardcodedTextRule) Check(f *File) (issues []parser.Issue) {
+	// Text that isn't within an element has the range of the template's declaration.
+	declarations := make(map[parser.Range]string)
+	for _, t := range templates(f.Template) {
+		if fn, ok := signature(t); ok {
+			declarations[t.Expression.Range] = "templ " + fn.Name.Name
+		}
+	}
+	for _, t := range i18n.FindText(f.Template) {
+		name := "<" + t.Element + ">"
+		if t.Element == "" {
+			name = declarations[t.Range]
+		}
+		msg := fmt.Sprintf("%s: hard-coded text %q, use { t(...) } to translate it", name, t.Value)
+		if t.Attribute != "" {
+			msg = fmt.Sprintf("%s: hard-coded %s attribute %q, use %s={ t(...) } to translate it", name, t.Attribute, t.Value, t.Attribute)
+		}
+		issues = append(issues, parser.Issue{
+			Check:    CheckHardcodedText,
+			Severity: parser.SeverityWarning,
+			Message:  msg,
+			Range:    t.Range,
+		})
+	}
+	return issues
+}
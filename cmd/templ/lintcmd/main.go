@@ -0,0 +1,141 @@
+package lintcmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/a-h/templ/cmd/templ/diagnostic"
+	"github.com/a-h/templ/cmd/templ/lint"
+	"github.com/a-h/templ/cmd/templ/processor"
+	parser "github.com/a-h/templ/parser/v2"
+)
+
+const workerCount = 4
+
+// ErrIssuesFound is returned when the templates contain issues.
+var ErrIssuesFound = errors.New("lint issues found")
+
+// The output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type Arguments struct {
+	// Paths are the templ files, and the directories of templ files, to lint.
+	Paths []string
+	// Enable lists the optional rules to run, e.g. "inline-style".
+	Enable []string
+	// Disable lists the rules that aren't run, e.g. "no-alt".
+	Disable []string
+	// Format of the output, FormatText or FormatJSON. Defaults to FormatText. FormatJSON
+	// writes the issues, and the errors found in templates, as newline-delimited JSON
+	// diagnostics.
+	Format string
+}
+
+// Run checks the templates with the lint rules, and writes the issues found to w.
+func Run(w io.Writer, args Arguments) (err error) {
+	if args.Format == "" {
+		args.Format = FormatText
+	}
+	if args.Format != FormatText && args.Format != FormatJSON {
+		return fmt.Errorf("unknown format %q, expected %q or %q", args.Format, FormatText, FormatJSON)
+	}
+	rules, err := lint.Select(args.Enable, args.Disable)
+	if err != nil {
+		return err
+	}
+	linter := lint.New(rules)
+
+	var m sync.Mutex
+	fileToIssues := make(map[string][]parser.Issue)
+	lintFile := func(fileName string) error {
+		tf, err := parser.ParseFile(fileName)
+		if err != nil {
+			return err
+		}
+		if issues := linter.Lint(fileName, tf); len(issues) > 0 {
+			m.Lock()
+			defer m.Unlock()
+			fileToIssues[fileName] = issues
+		}
+		return nil
+	}
+	var fileErrs []error
+	for _, path := range args.Paths {
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			fileErrs = append(fileErrs, statErr)
+			continue
+		}
+		if !info.IsDir() {
+			if lintErr := lintFile(path); lintErr != nil {
+				fileErrs = append(fileErrs, lintErr)
+			}
+			continue
+		}
+		results := make(chan processor.Result)
+		go processor.Process(path, lintFile, workerCount, results)
+		for r := range results {
+			if r.Error != nil {
+				fileErrs = append(fileErrs, r.Error)
+			}
+		}
+	}
+	// The errors are sorted, so that the output doesn't depend on which file was processed
+	// first.
+	sort.Slice(fileErrs, func(i, j int) bool { return fileErrs[i].Error() < fileErrs[j].Error() })
+
+	fileNames := make([]string, 0, len(fileToIssues))
+	for fileName := range fileToIssues {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	if args.Format == FormatJSON {
+		enc := diagnostic.NewEncoder(w)
+		for _, fileErr := range fileErrs {
+			if encErr := enc.Encode(diagnostic.FromError(fileErr)...); encErr != nil {
+				return encErr
+			}
+		}
+		for _, fileName := range fileNames {
+			for _, issue := range fileToIssues[fileName] {
+				if encErr := enc.Encode(diagnostic.FromIssue(fileName, issue)); encErr != nil {
+					return encErr
+				}
+			}
+		}
+		if len(fileErrs) > 0 {
+			err = fmt.Errorf("failed to lint templates, found %d errors", len(fileErrs))
+		}
+	} else {
+		err = errors.Join(fileErrs...)
+		for _, fileName := range fileNames {
+			for _, issue := range fileToIssues[fileName] {
+				fmt.Fprintf(w, "%s:%s\n", fileName, issue)
+			}
+		}
+	}
+	if hasProblems(fileToIssues) {
+		err = errors.Join(err, ErrIssuesFound)
+	}
+	return err
+}
+
+// hasProblems returns true if any of the issues are warnings or errors. Hints, e.g. content
+// that's never rendered, are reported, but aren't problems.
+func hasProblems(fileToIssues map[string][]parser.Issue) bool {
+	for _, issues := range fileToIssues {
+		for _, issue := range issues {
+			if issue.Severity != parser.SeverityHint {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package lintcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/diagnostic"
+	"github.com/google/go-cmp/cmp"
+)
+
+// decode unmarshals newline-delimited JSON diagnostics, checking that each has all of the
+// fields of the schema.
+func decode(t *testing.T, data []byte) (diagnostics []diagnostic.Diagnostic) {
+	t.Helper()
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(line, &fields); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		for _, field := range []string{"file", "line", "col", "endLine", "endCol", "severity", "code", "message"} {
+			if _, ok := fields[field]; !ok {
+				t.Errorf("expected field %q in %s", field, line)
+			}
+		}
+		var d diagnostic.Diagnostic
+		if err := json.Unmarshal(line, &d); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", line, err)
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "index.templ")
+	err := os.WriteFile(fileName, []byte(`package main
+
+templ Page(title string) {
+	<img src="a.png"/>
+}
+`), 0644)
+	if err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var w bytes.Buffer
+		err := Run(&w, Arguments{Paths: []string{dir}})
+		if !errors.Is(err, ErrIssuesFound) {
+			t.Errorf("expected ErrIssuesFound, got %v", err)
+		}
+		expected := fileName + `:3:7: warning: templ Page: parameter "title" is not used (unused-parameter)
+` + fileName + `:4:3: warning: <img>: missing alt attribute, use alt="" for decorative images (no-alt)
+`
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("json", func(t *testing.T) {
+		var w bytes.Buffer
+		err := Run(&w, Arguments{Paths: []string{fileName}, Disable: []string{"unused-parameter"}, Format: FormatJSON})
+		if !errors.Is(err, ErrIssuesFound) {
+			t.Errorf("expected ErrIssuesFound, got %v", err)
+		}
+		expected := []diagnostic.Diagnostic{{
+			File:     fileName,
+			Line:     4,
+			Col:      3,
+			EndLine:  4,
+			EndCol:   6,
+			Severity: "warning",
+			Code:     "no-alt",
+			Message:  `<img>: missing alt attribute, use alt="" for decorative images`,
+		}}
+		if diff := cmp.Diff(expected, decode(t, w.Bytes())); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("json parse errors", func(t *testing.T) {
+		invalidFileName := filepath.Join(t.TempDir(), "invalid.templ")
+		if err := os.WriteFile(invalidFileName, []byte("package main\n\ntempl A() {\n\t<div>\n}\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		var w bytes.Buffer
+		err := Run(&w, Arguments{Paths: []string{invalidFileName}, Format: FormatJSON})
+		if err == nil || errors.Is(err, ErrIssuesFound) {
+			t.Errorf("expected an error, got %v", err)
+		}
+		diagnostics := decode(t, w.Bytes())
+		if len(diagnostics) != 1 || diagnostics[0].Code != diagnostic.CodeParseError || diagnostics[0].Line != 5 {
+			t.Errorf("expected a parse error on line 5, got %#v", diagnostics)
+		}
+	})
+	t.Run("no issues", func(t *testing.T) {
+		var w bytes.Buffer
+		err := Run(&w, Arguments{Paths: []string{dir}, Disable: []string{"unused-parameter", "no-alt"}, Format: FormatJSON})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if w.String() != "" {
+			t.Errorf("expected no output, got %q", w.String())
+		}
+	})
+	t.Run("hints are reported, but aren't an error", func(t *testing.T) {
+		hintFileName := filepath.Join(t.TempDir(), "hint.templ")
+		if err := os.WriteFile(hintFileName, []byte("package main\n\ntempl Page() {\n\tif false {\n\t\t<p>Never</p>\n\t}\n}\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		var w bytes.Buffer
+		err := Run(&w, Arguments{Paths: []string{hintFileName}})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		expected := hintFileName + ":5:1: hint: if false: condition is always false, so its content is never rendered (dead-code)\n"
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("unknown rules are an error", func(t *testing.T) {
+		err := Run(&bytes.Buffer{}, Arguments{Paths: []string{dir}, Enable: []string{"no-such-rule"}})
+		if err == nil || errors.Is(err, ErrIssuesFound) {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+}
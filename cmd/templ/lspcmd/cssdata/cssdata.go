@@ -0,0 +1,54 @@
+// Package cssdata contains CSS property names, descriptions and enumerated values
+// used to provide completion and hover information within css templates.
+package cssdata
+
+//go:generate go run gen.go
+
+import "sort"
+
+// Property is a CSS property.
+type Property struct {
+	// Name of the property, e.g. "display".
+	Name string
+	// Description is a short summary of the property.
+	Description string
+	// Values are the enumerated keyword values of the property, if any.
+	Values []string
+}
+
+var globalValues = []string{"inherit", "initial", "revert", "unset"}
+
+var nameToProperty = func() map[string]Property {
+	m := make(map[string]Property, len(properties))
+	for _, p := range properties {
+		m[p.Name] = p
+	}
+	return m
+}()
+
+// Get a property by name.
+func Get(name string) (p Property, ok bool) {
+	p, ok = nameToProperty[name]
+	return
+}
+
+// Properties returns all known properties, sorted by name.
+func Properties() []Property {
+	op := make([]Property, len(properties))
+	copy(op, properties)
+	sort.Slice(op, func(i, j int) bool {
+		return op[i].Name < op[j].Name
+	})
+	return op
+}
+
+// Values returns the enumerated values of a property, including the
+// CSS-wide keywords that are valid for every property.
+func Values(name string) (values []string) {
+	p, ok := Get(name)
+	if !ok {
+		return nil
+	}
+	values = append(values, p.Values...)
+	return append(values, globalValues...)
+}
@@ -0,0 +1,69 @@
+//go:build ignore
+
+// gen writes properties.go from css-data.json, which uses the format of the
+// VS Code CSS custom data, derived from the MDN CSS reference.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+)
+
+type cssData struct {
+	Properties []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Values      []struct {
+			Name string `json:"name"`
+		} `json:"values"`
+	} `json:"properties"`
+}
+
+func main() {
+	f, err := os.Open("css-data.json")
+	if err != nil {
+		log.Fatalf("failed to open CSS data: %v", err)
+	}
+	defer f.Close()
+	var data cssData
+	if err = json.NewDecoder(f).Decode(&data); err != nil {
+		log.Fatalf("failed to decode CSS data: %v", err)
+	}
+	sort.SliceStable(data.Properties, func(i, j int) bool {
+		return data.Properties[i].Name < data.Properties[j].Name
+	})
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\n")
+	b.WriteString("package cssdata\n\n")
+	b.WriteString("// properties is generated from css-data.json, derived from the MDN CSS reference.\n")
+	b.WriteString("var properties = []Property{\n")
+	for _, p := range data.Properties {
+		fmt.Fprintf(&b, "{Name: %q, Description: %q", p.Name, p.Description)
+		if len(p.Values) > 0 {
+			b.WriteString(", Values: []string{")
+			for i, v := range p.Values {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(&b, "%q", v.Name)
+			}
+			b.WriteString("}")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format generated code: %v", err)
+	}
+	if err = os.WriteFile("properties.go", src, 0644); err != nil {
+		log.Fatalf("failed to write properties.go: %v", err)
+	}
+}
@@ -0,0 +1,448 @@
+package lspcmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/lspcmd/proxy"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+// The test harness connects an in-process fake editor to the templ proxy,
+// and the proxy to an in-process fake gopls, using in-memory pipes.
+//
+//	fakeClient <-> proxy.Server / proxy.Client <-> fakeGopls
+
+// fakeGopls replies to requests with canned responses, and records the
+// requests that it receives after they've been rewritten by the proxy.
+type fakeGopls struct {
+	protocol.Server
+	client protocol.Client
+
+	m sync.Mutex
+	// goSource is the Go code of each document, keyed by URI.
+	goSource map[protocol.DocumentURI]string
+	// completionRequests are the completion requests received.
+	completionRequests []protocol.CompletionParams
+	// diagnose is the Go code that gopls reports a diagnostic for when a document is opened.
+	diagnose string
+	// packageDirs are the directories that import paths resolve to, keyed by import path.
+	packageDirs map[string]protocol.DocumentURI
+	// initializationOptions are the options received in the initialize request.
+	initializationOptions interface{}
+	// settings are the settings received by didChangeConfiguration.
+	settings []interface{}
+}
+
+func newFakeGopls() *fakeGopls {
+	return &fakeGopls{
+		goSource:    make(map[protocol.DocumentURI]string),
+		packageDirs: make(map[string]protocol.DocumentURI),
+	}
+}
+
+func (g *fakeGopls) Initialize(ctx context.Context, params *protocol.InitializeParams) (*protocol.InitializeResult, error) {
+	g.m.Lock()
+	g.initializationOptions = params.InitializationOptions
+	g.m.Unlock()
+	return &protocol.InitializeResult{
+		Capabilities: protocol.ServerCapabilities{
+			CompletionProvider: &protocol.CompletionOptions{
+				TriggerCharacters: []string{"."},
+			},
+		},
+	}, nil
+}
+
+func (g *fakeGopls) Initialized(ctx context.Context, params *protocol.InitializedParams) error {
+	return nil
+}
+
+func (g *fakeGopls) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (g *fakeGopls) DidChangeConfiguration(ctx context.Context, params *protocol.DidChangeConfigurationParams) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.settings = append(g.settings, params.Settings)
+	return nil
+}
+
+func (g *fakeGopls) DidOpen(ctx context.Context, params *protocol.DidOpenTextDocumentParams) error {
+	g.m.Lock()
+	g.goSource[params.TextDocument.URI] = params.TextDocument.Text
+	diagnose := g.diagnose
+	g.m.Unlock()
+	if diagnose == "" {
+		return nil
+	}
+	r, ok := findRange(params.TextDocument.Text, diagnose)
+	if !ok {
+		return fmt.Errorf("fakeGopls: %q not found in Go source", diagnose)
+	}
+	return g.client.PublishDiagnostics(ctx, &protocol.PublishDiagnosticsParams{
+		URI: params.TextDocument.URI,
+		Diagnostics: []protocol.Diagnostic{
+			{
+				Range:    r,
+				Severity: protocol.DiagnosticSeverityError,
+				Source:   "compiler",
+				Message:  "fake gopls diagnostic",
+			},
+		},
+	})
+}
+
+func (g *fakeGopls) DidChange(ctx context.Context, params *protocol.DidChangeTextDocumentParams) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	for _, change := range params.ContentChanges {
+		g.goSource[params.TextDocument.URI] = change.Text
+	}
+	return nil
+}
+
+func (g *fakeGopls) DidClose(ctx context.Context, params *protocol.DidCloseTextDocumentParams) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	delete(g.goSource, params.TextDocument.URI)
+	return nil
+}
+
+// Completion returns a single item, with a text edit at the requested position.
+func (g *fakeGopls) Completion(ctx context.Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.completionRequests = append(g.completionRequests, *params)
+	return &protocol.CompletionList{
+		Items: []protocol.CompletionItem{
+			{
+				Label: "fakeCompletion",
+				TextEdit: &protocol.TextEdit{
+					Range: protocol.Range{
+						Start: params.Position,
+						End:   params.Position,
+					},
+					NewText: "fakeCompletion",
+				},
+			},
+		},
+	}, nil
+}
+
+// Definition returns the directory of the package, if the position is within an import path.
+func (g *fakeGopls) Definition(ctx context.Context, params *protocol.DefinitionParams) ([]protocol.Location, error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	path, _, ok := g.importPathAt(params.TextDocument.URI, params.Position)
+	if !ok {
+		return nil, nil
+	}
+	return []protocol.Location{{URI: g.packageDirs[path]}}, nil
+}
+
+// Hover describes the package, if the position is within an import path.
+func (g *fakeGopls) Hover(ctx context.Context, params *protocol.HoverParams) (*protocol.Hover, error) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	path, r, ok := g.importPathAt(params.TextDocument.URI, params.Position)
+	if !ok {
+		return nil, nil
+	}
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{Kind: protocol.Markdown, Value: "package " + path},
+		Range:    &r,
+	}, nil
+}
+
+// importPathAt returns the import path of a known package that contains the position, and
+// the range of the quoted path. It must be called with the lock held.
+func (g *fakeGopls) importPathAt(uri protocol.DocumentURI, pos protocol.Position) (path string, r protocol.Range, ok bool) {
+	lines := strings.Split(g.goSource[uri], "\n")
+	if int(pos.Line) >= len(lines) {
+		return
+	}
+	line := lines[pos.Line]
+	for path := range g.packageDirs {
+		quoted := `"` + path + `"`
+		col := strings.Index(line, quoted)
+		if col >= 0 && int(pos.Character) >= col && int(pos.Character) < col+len(quoted) {
+			r.Start = protocol.Position{Line: pos.Line, Character: uint32(col)}
+			r.End = protocol.Position{Line: pos.Line, Character: uint32(col + len(quoted))}
+			return path, r, true
+		}
+	}
+	return
+}
+
+func (g *fakeGopls) getGoSource(uri protocol.DocumentURI) (s string, ok bool) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	s, ok = g.goSource[uri]
+	return
+}
+
+func (g *fakeGopls) getInitializationOptions() interface{} {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return g.initializationOptions
+}
+
+func (g *fakeGopls) getSettings() []interface{} {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return append([]interface{}{}, g.settings...)
+}
+
+func (g *fakeGopls) getCompletionRequests() []protocol.CompletionParams {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return append([]protocol.CompletionParams{}, g.completionRequests...)
+}
+
+// fakeClient records the notifications sent to the editor.
+type fakeClient struct {
+	protocol.Client
+	diagnostics chan *protocol.PublishDiagnosticsParams
+	// configuration is returned in response to workspace/configuration requests, keyed by section.
+	configuration map[string]interface{}
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		diagnostics: make(chan *protocol.PublishDiagnosticsParams, 64),
+	}
+}
+
+func (c *fakeClient) PublishDiagnostics(ctx context.Context, params *protocol.PublishDiagnosticsParams) error {
+	c.diagnostics <- params
+	return nil
+}
+
+func (c *fakeClient) Configuration(ctx context.Context, params *protocol.ConfigurationParams) (result []interface{}, err error) {
+	for _, item := range params.Items {
+		result = append(result, c.configuration[item.Section])
+	}
+	return result, nil
+}
+
+func (c *fakeClient) LogMessage(ctx context.Context, params *protocol.LogMessageParams) error {
+	return nil
+}
+
+func (c *fakeClient) RegisterCapability(ctx context.Context, params *protocol.RegistrationParams) error {
+	return nil
+}
+
+type harness struct {
+	t      *testing.T
+	ctx    context.Context
+	server protocol.Server
+	gopls  *fakeGopls
+	client *fakeClient
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	return newHarnessWithOptions(t, nil)
+}
+
+// newHarnessWithOptions starts a harness where the editor sends the initializationOptions
+// in its initialize request.
+func newHarnessWithOptions(t *testing.T, initializationOptions interface{}) *harness {
+	t.Helper()
+	return startHarness(t, initializationOptions, proxy.NewURIMapper(nil))
+}
+
+// startHarness starts a harness where the proxy maps templ files to Go files with uris.
+func startHarness(t *testing.T, initializationOptions interface{}, uris *proxy.URIMapper) *harness {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	log := zap.NewNop()
+
+	goplsProxySide, goplsSide := net.Pipe()
+	editorProxySide, editorSide := net.Pipe()
+
+	// Start the proxy.
+	_, goplsConn, templConn := connect(log, uris, RequestOptions{}, goplsProxySide, editorProxySide)
+
+	// Start the fake gopls.
+	gopls := newFakeGopls()
+	_, fakeGoplsConn, goplsClient := protocol.NewServer(ctx, gopls, jsonrpc2.NewStream(goplsSide), log)
+	gopls.client = goplsClient
+
+	// Start the fake editor.
+	client := newFakeClient()
+	_, editorConn, server := protocol.NewClient(ctx, client, jsonrpc2.NewStream(editorSide), log)
+
+	t.Cleanup(func() {
+		cancel()
+		editorConn.Close()
+		templConn.Close()
+		goplsConn.Close()
+		fakeGoplsConn.Close()
+	})
+
+	h := &harness{
+		t:      t,
+		ctx:    ctx,
+		server: server,
+		gopls:  gopls,
+		client: client,
+	}
+	if _, err := server.Initialize(ctx, &protocol.InitializeParams{InitializationOptions: initializationOptions}); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if err := server.Initialized(ctx, &protocol.InitializedParams{}); err != nil {
+		t.Fatalf("initialized failed: %v", err)
+	}
+	return h
+}
+
+func (h *harness) DidOpen(uri protocol.DocumentURI, text string) {
+	h.t.Helper()
+	err := h.server.DidOpen(h.ctx, &protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{
+			URI:        uri,
+			LanguageID: "templ",
+			Version:    1,
+			Text:       text,
+		},
+	})
+	if err != nil {
+		h.t.Fatalf("didOpen failed: %v", err)
+	}
+}
+
+func (h *harness) DidChange(uri protocol.DocumentURI, version int32, text string) {
+	h.t.Helper()
+	err := h.server.DidChange(h.ctx, &protocol.DidChangeTextDocumentParams{
+		TextDocument: protocol.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: uri},
+			Version:                version,
+		},
+		ContentChanges: []protocol.TextDocumentContentChangeEvent{
+			{Text: text},
+		},
+	})
+	if err != nil {
+		h.t.Fatalf("didChange failed: %v", err)
+	}
+}
+
+func (h *harness) Completion(uri protocol.DocumentURI, pos protocol.Position) *protocol.CompletionList {
+	h.t.Helper()
+	result, err := h.server.Completion(h.ctx, &protocol.CompletionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     pos,
+		},
+	})
+	if err != nil {
+		h.t.Fatalf("completion failed: %v", err)
+	}
+	return result
+}
+
+func (h *harness) Definition(uri protocol.DocumentURI, pos protocol.Position) []protocol.Location {
+	h.t.Helper()
+	result, err := h.server.Definition(h.ctx, &protocol.DefinitionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     pos,
+		},
+	})
+	if err != nil {
+		h.t.Fatalf("definition failed: %v", err)
+	}
+	return result
+}
+
+func (h *harness) Hover(uri protocol.DocumentURI, pos protocol.Position) *protocol.Hover {
+	h.t.Helper()
+	result, err := h.server.Hover(h.ctx, &protocol.HoverParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     pos,
+		},
+	})
+	if err != nil {
+		h.t.Fatalf("hover failed: %v", err)
+	}
+	return result
+}
+
+func (h *harness) Formatting(uri protocol.DocumentURI) []protocol.TextEdit {
+	h.t.Helper()
+	result, err := h.server.Formatting(h.ctx, &protocol.DocumentFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+	})
+	if err != nil {
+		h.t.Fatalf("formatting failed: %v", err)
+	}
+	return result
+}
+
+// WaitForDiagnostics waits until diagnostics are published for the URI that
+// contain at least one item.
+func (h *harness) WaitForDiagnostics(uri protocol.DocumentURI) []protocol.Diagnostic {
+	h.t.Helper()
+	for {
+		select {
+		case d := <-h.client.diagnostics:
+			if d.URI == uri && len(d.Diagnostics) > 0 {
+				return d.Diagnostics
+			}
+		case <-h.ctx.Done():
+			h.t.Fatalf("timed out waiting for diagnostics for %q", uri)
+			return nil
+		}
+	}
+}
+
+// WaitForGoSource waits until gopls has received Go code for the URI that contains the value.
+func (h *harness) WaitForGoSource(uri protocol.DocumentURI, contains string) string {
+	h.t.Helper()
+	for {
+		if s, ok := h.gopls.getGoSource(uri); ok && strings.Contains(s, contains) {
+			return s
+		}
+		select {
+		case <-time.After(time.Millisecond * 10):
+		case <-h.ctx.Done():
+			s, _ := h.gopls.getGoSource(uri)
+			h.t.Fatalf("timed out waiting for gopls to receive %q in %q, got:\n%s", contains, uri, s)
+			return ""
+		}
+	}
+}
+
+// findRange returns the range of the first instance of s within text.
+func findRange(text, s string) (r protocol.Range, ok bool) {
+	for i, line := range strings.Split(text, "\n") {
+		if col := strings.Index(line, s); col >= 0 {
+			r.Start = protocol.Position{Line: uint32(i), Character: uint32(col)}
+			r.End = protocol.Position{Line: uint32(i), Character: uint32(col + len(s))}
+			return r, true
+		}
+	}
+	return
+}
+
+func parsePosition(s string) (pos protocol.Position, err error) {
+	_, err = fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &pos.Line, &pos.Character)
+	return
+}
+
+func parseRange(s string) (r protocol.Range, err error) {
+	_, err = fmt.Sscanf(strings.TrimSpace(s), "%d:%d-%d:%d", &r.Start.Line, &r.Start.Character, &r.End.Line, &r.End.Character)
+	return
+}
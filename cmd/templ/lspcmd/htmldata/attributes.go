@@ -0,0 +1,66 @@
+package htmldata
+
+var booleanValues = []string{"true", "false"}
+
+// attributes is derived from the MDN HTML attribute and ARIA references.
+var attributes = []Attribute{
+	{Name: "accesskey", Description: "Provides a hint for generating a keyboard shortcut for the current element."},
+	{Name: "action", Description: "The URL that processes the form submission."},
+	{Name: "alt", Description: "Alternative text in case an image can't be displayed."},
+	{Name: "aria-controls", Description: "Identifies the element (or elements) whose contents or presence are controlled by the element."},
+	{Name: "aria-describedby", Description: "Identifies the element (or elements) that describes the element."},
+	{Name: "aria-disabled", Description: "Indicates that the element is perceivable but disabled, so it is not editable or otherwise operable.", Values: booleanValues},
+	{Name: "aria-expanded", Description: "Indicates if a control is expanded or collapsed, and whether or not the controlled elements are displayed or hidden.", Values: []string{"true", "false", "undefined"}},
+	{Name: "aria-hidden", Description: "Indicates whether the element is exposed to an accessibility API.", Values: []string{"true", "false", "undefined"}},
+	{Name: "aria-label", Description: "Defines a string value that labels an interactive element."},
+	{Name: "aria-labelledby", Description: "Identifies the element (or elements) that labels the element it is applied to."},
+	{Name: "aria-live", Description: "Indicates that an element will be updated, and describes the types of updates the user agents, assistive technologies, and user can expect from the live region.", Values: []string{"assertive", "off", "polite"}},
+	{Name: "aria-pressed", Description: "Indicates the current \"pressed\" state of a toggle button.", Values: []string{"false", "mixed", "true", "undefined"}},
+	{Name: "aria-selected", Description: "Indicates the current \"selected\" state of various widgets.", Values: []string{"true", "false", "undefined"}},
+	{Name: "autocomplete", Description: "Indicates whether controls in this form can by default have their values automatically completed by the browser.", Values: []string{"on", "off"}},
+	{Name: "autofocus", Description: "Indicates that an element should be focused on page load, or when the dialog that it is part of is displayed."},
+	{Name: "checked", Description: "Indicates whether the element should be checked on page load."},
+	{Name: "class", Description: "A space-separated list of the classes of the element."},
+	{Name: "colspan", Description: "The number of columns a cell should span."},
+	{Name: "content", Description: "A value associated with http-equiv or name depending on the context."},
+	{Name: "contenteditable", Description: "Indicates whether the element's content is editable.", Values: []string{"true", "false", "plaintext-only"}},
+	{Name: "dir", Description: "Defines the text direction.", Values: []string{"ltr", "rtl", "auto"}},
+	{Name: "disabled", Description: "Indicates whether the user can interact with the element."},
+	{Name: "download", Description: "Indicates that the hyperlink is to be used for downloading a resource."},
+	{Name: "draggable", Description: "Defines whether the element can be dragged.", Values: booleanValues},
+	{Name: "enctype", Description: "Defines the content type of the form data when the method is POST.", Values: []string{"application/x-www-form-urlencoded", "multipart/form-data", "text/plain"}},
+	{Name: "for", Description: "Describes elements which belongs to this one."},
+	{Name: "form", Description: "Indicates the form that is the owner of the element."},
+	{Name: "height", Description: "Specifies the height of elements listed here. For all other elements, use the CSS height property."},
+	{Name: "hidden", Description: "Prevents rendering of given element, while keeping child elements, e.g. script elements, active."},
+	{Name: "href", Description: "The URL of a linked resource."},
+	{Name: "id", Description: "Often used with CSS to style a specific element. The value of this attribute must be unique."},
+	{Name: "inputmode", Description: "Provides a hint as to the type of data that might be entered by the user while editing the element or its contents.", Values: []string{"none", "text", "decimal", "numeric", "tel", "search", "email", "url"}},
+	{Name: "lang", Description: "Defines the language used in the element."},
+	{Name: "loading", Description: "Indicates if the element should be loaded lazily or loaded immediately.", Values: []string{"eager", "lazy"}},
+	{Name: "max", Description: "Indicates the maximum value allowed."},
+	{Name: "maxlength", Description: "Defines the maximum number of characters allowed in the element."},
+	{Name: "method", Description: "Defines which HTTP method to use when submitting the form.", Values: []string{"get", "post", "dialog"}},
+	{Name: "min", Description: "Indicates the minimum value allowed."},
+	{Name: "multiple", Description: "Indicates whether multiple values can be entered in an input of the type email or file."},
+	{Name: "name", Description: "Name of the element. For example used by the server to identify the fields in form submits."},
+	{Name: "open", Description: "Indicates whether the contents are currently visible (in the case of a details element) or whether the dialog is active and can be interacted with (in the case of a dialog element)."},
+	{Name: "pattern", Description: "Defines a regular expression which the element's value will be validated against."},
+	{Name: "placeholder", Description: "Provides a hint to the user of what can be entered in the field."},
+	{Name: "readonly", Description: "Indicates whether the element can be edited."},
+	{Name: "rel", Description: "Specifies the relationship of the target object to the link object."},
+	{Name: "required", Description: "Indicates whether this element is required to fill out or not."},
+	{Name: "role", Description: "Defines an explicit role for an element for use by assistive technologies."},
+	{Name: "rowspan", Description: "Defines the number of rows a table cell should span over."},
+	{Name: "selected", Description: "Defines a value which will be selected on page load."},
+	{Name: "spellcheck", Description: "Indicates whether spell checking is allowed for the element.", Values: booleanValues},
+	{Name: "src", Description: "The URL of the embeddable content."},
+	{Name: "style", Description: "Defines CSS styles which will override styles previously set."},
+	{Name: "tabindex", Description: "Overrides the browser's default tab order and follows the one specified instead."},
+	{Name: "target", Description: "Specifies where to open the linked document (in the case of an a element) or where to display the response received (in the case of a form element).", Values: []string{"_self", "_blank", "_parent", "_top"}},
+	{Name: "title", Description: "Text to be displayed in a tooltip when hovering over the element."},
+	{Name: "translate", Description: "Specify whether an element's attribute values and the values of its text node children are to be translated when the page is localized.", Values: []string{"yes", "no"}},
+	{Name: "type", Description: "Defines the type of the element."},
+	{Name: "value", Description: "Defines a default value which will be displayed in the element on page load."},
+	{Name: "width", Description: "For the elements listed here, this establishes the element's width."},
+}
@@ -0,0 +1,85 @@
+package htmldata
+
+// elements is derived from the MDN HTML element reference.
+var elements = []Element{
+	{Name: "a", Description: "Creates a hyperlink to web pages, files, email addresses, locations in the same page, or anything else a URL can address."},
+	{Name: "abbr", Description: "Represents an abbreviation or acronym."},
+	{Name: "address", Description: "Indicates that the enclosed HTML provides contact information for a person or people, or for an organization."},
+	{Name: "article", Description: "Represents a self-contained composition in a document, page, application, or site, which is intended to be independently distributable or reusable."},
+	{Name: "aside", Description: "Represents a portion of a document whose content is only indirectly related to the document's main content."},
+	{Name: "audio", Description: "Used to embed sound content in documents."},
+	{Name: "b", Description: "Used to draw the reader's attention to the element's contents, which are not otherwise granted special importance."},
+	{Name: "blockquote", Description: "Indicates that the enclosed text is an extended quotation."},
+	{Name: "body", Description: "Represents the content of an HTML document. There can be only one such element in a document."},
+	{Name: "br", Description: "Produces a line break in text (carriage-return)."},
+	{Name: "button", Description: "An interactive element activated by a user with a mouse, keyboard, finger, voice command, or other assistive technology."},
+	{Name: "canvas", Description: "Container element to use with either the canvas scripting API or the WebGL API to draw graphics and animations."},
+	{Name: "caption", Description: "Specifies the caption (or title) of a table."},
+	{Name: "code", Description: "Displays its contents styled in a fashion intended to indicate that the text is a short fragment of computer code."},
+	{Name: "datalist", Description: "Contains a set of option elements that represent the permissible or recommended options available to choose from within other controls."},
+	{Name: "dd", Description: "Provides the description, definition, or value for the preceding term (dt) in a description list (dl)."},
+	{Name: "details", Description: "Creates a disclosure widget in which information is visible only when the widget is toggled into an \"open\" state."},
+	{Name: "dialog", Description: "Represents a dialog box or other interactive component, such as a dismissible alert, inspector, or subwindow."},
+	{Name: "div", Description: "The generic container for flow content. It has no effect on the content or layout until styled in some way using CSS."},
+	{Name: "dl", Description: "Represents a description list. The element encloses a list of groups of terms (dt) and descriptions (dd)."},
+	{Name: "dt", Description: "Specifies a term in a description or definition list, and as such must be used inside a dl element."},
+	{Name: "em", Description: "Marks text that has stress emphasis."},
+	{Name: "fieldset", Description: "Used to group several controls as well as labels (label) within a web form."},
+	{Name: "figcaption", Description: "Represents a caption or legend describing the rest of the contents of its parent figure element."},
+	{Name: "figure", Description: "Represents self-contained content, potentially with an optional caption, which is specified using the figcaption element."},
+	{Name: "footer", Description: "Represents a footer for its nearest ancestor sectioning content or sectioning root element."},
+	{Name: "form", Description: "Represents a document section containing interactive controls for submitting information."},
+	{Name: "h1", Description: "Represents a level 1 section heading. h1 is the highest section level."},
+	{Name: "h2", Description: "Represents a level 2 section heading."},
+	{Name: "h3", Description: "Represents a level 3 section heading."},
+	{Name: "h4", Description: "Represents a level 4 section heading."},
+	{Name: "h5", Description: "Represents a level 5 section heading."},
+	{Name: "h6", Description: "Represents a level 6 section heading. h6 is the lowest section level."},
+	{Name: "head", Description: "Contains machine-readable information (metadata) about the document, like its title, scripts, and style sheets."},
+	{Name: "header", Description: "Represents introductory content, typically a group of introductory or navigational aids."},
+	{Name: "hr", Description: "Represents a thematic break between paragraph-level elements."},
+	{Name: "html", Description: "Represents the root (top-level element) of an HTML document, so it is also referred to as the root element."},
+	{Name: "i", Description: "Represents a range of text that is set off from the normal text for some reason, such as idiomatic text, technical terms, and taxonomical designations."},
+	{Name: "iframe", Description: "Represents a nested browsing context, embedding another HTML page into the current one."},
+	{Name: "img", Description: "Embeds an image into the document."},
+	{Name: "input", Description: "Used to create interactive controls for web-based forms to accept data from the user."},
+	{Name: "label", Description: "Represents a caption for an item in a user interface."},
+	{Name: "legend", Description: "Represents a caption for the content of its parent fieldset."},
+	{Name: "li", Description: "Represents an item in a list."},
+	{Name: "link", Description: "Specifies relationships between the current document and an external resource."},
+	{Name: "main", Description: "Represents the dominant content of the body of a document."},
+	{Name: "meta", Description: "Represents metadata that cannot be represented by other HTML meta-related elements."},
+	{Name: "nav", Description: "Represents a section of a page whose purpose is to provide navigation links."},
+	{Name: "noscript", Description: "Defines a section of HTML to be inserted if a script type on the page is unsupported or if scripting is currently turned off in the browser."},
+	{Name: "ol", Description: "Represents an ordered list of items, typically rendered as a numbered list."},
+	{Name: "optgroup", Description: "Creates a grouping of options within a select element."},
+	{Name: "option", Description: "Used to define an item contained in a select, an optgroup, or a datalist element."},
+	{Name: "p", Description: "Represents a paragraph."},
+	{Name: "picture", Description: "Contains zero or more source elements and one img element to offer alternative versions of an image for different display/device scenarios."},
+	{Name: "pre", Description: "Represents preformatted text which is to be presented exactly as written in the HTML file."},
+	{Name: "progress", Description: "Displays an indicator showing the completion progress of a task, typically displayed as a progress bar."},
+	{Name: "script", Description: "Used to embed executable code or data."},
+	{Name: "section", Description: "Represents a generic standalone section of a document, which doesn't have a more specific semantic element to represent it."},
+	{Name: "select", Description: "Represents a control that provides a menu of options."},
+	{Name: "slot", Description: "Part of the Web Components technology suite, this element is a placeholder inside a web component."},
+	{Name: "small", Description: "Represents side-comments and small print, like copyright and legal text."},
+	{Name: "source", Description: "Specifies multiple media resources for the picture, the audio element, or the video element."},
+	{Name: "span", Description: "A generic inline container for phrasing content, which does not inherently represent anything."},
+	{Name: "strong", Description: "Indicates that its contents have strong importance, seriousness, or urgency."},
+	{Name: "style", Description: "Contains style information for a document or part of a document."},
+	{Name: "summary", Description: "Specifies a summary, caption, or legend for a details element's disclosure box."},
+	{Name: "svg", Description: "Container defining a new coordinate system and viewport for SVG content."},
+	{Name: "table", Description: "Represents tabular data, that is, information presented in a two-dimensional table comprised of rows and columns of cells containing data."},
+	{Name: "tbody", Description: "Encapsulates a set of table rows (tr elements), indicating that they comprise the body of the table (table)."},
+	{Name: "td", Description: "Defines a cell of a table that contains data."},
+	{Name: "template", Description: "A mechanism for holding HTML that is not to be rendered immediately when a page is loaded but may be instantiated subsequently during runtime using JavaScript."},
+	{Name: "textarea", Description: "Represents a multi-line plain-text editing control."},
+	{Name: "tfoot", Description: "Defines a set of rows summarizing the columns of the table."},
+	{Name: "th", Description: "Defines a cell as the header of a group of table cells."},
+	{Name: "thead", Description: "Defines a set of rows defining the head of the columns of the table."},
+	{Name: "time", Description: "Represents a specific period in time."},
+	{Name: "title", Description: "Defines the document's title that is shown in a browser's title bar or a page's tab."},
+	{Name: "tr", Description: "Defines a row of cells in a table."},
+	{Name: "ul", Description: "Represents an unordered list of items, typically rendered as a bulleted list."},
+	{Name: "video", Description: "Embeds a media player which supports video playback into the document."},
+}
@@ -0,0 +1,49 @@
+// Package htmldata contains HTML element and attribute names, descriptions and
+// enumerated values used to provide hover information within templ templates.
+package htmldata
+
+// Element is an HTML element.
+type Element struct {
+	// Name of the element, e.g. "details".
+	Name string
+	// Description is a short summary of the element.
+	Description string
+}
+
+// Attribute is an HTML attribute.
+type Attribute struct {
+	// Name of the attribute, e.g. "aria-expanded".
+	Name string
+	// Description is a short summary of the attribute.
+	Description string
+	// Values are the enumerated values of the attribute, if any.
+	Values []string
+}
+
+var nameToElement = func() map[string]Element {
+	m := make(map[string]Element, len(elements))
+	for _, e := range elements {
+		m[e.Name] = e
+	}
+	return m
+}()
+
+var nameToAttribute = func() map[string]Attribute {
+	m := make(map[string]Attribute, len(attributes))
+	for _, a := range attributes {
+		m[a.Name] = a
+	}
+	return m
+}()
+
+// GetElement gets an element by name.
+func GetElement(name string) (e Element, ok bool) {
+	e, ok = nameToElement[name]
+	return
+}
+
+// GetAttribute gets an attribute by name.
+func GetAttribute(name string) (a Attribute, ok bool) {
+	a, ok = nameToAttribute[name]
+	return
+}
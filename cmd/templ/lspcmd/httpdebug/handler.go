@@ -33,11 +33,11 @@ func NewHandler(l *zap.Logger, s *proxy.Server) http.Handler {
 			Error(w, "uri not found", http.StatusNotFound)
 			return
 		}
-		JSON(w, sm.SourceLinesToTarget)
+		JSON(w, sm.Mappings())
 	})
 	m.HandleFunc("/go", func(w http.ResponseWriter, r *http.Request) {
 		uri := r.URL.Query().Get("uri")
-		c, ok := s.GoSource[uri]
+		c, ok := s.GetGoSource(uri)
 		if !ok {
 			Error(w, "uri not found", http.StatusNotFound)
 			return
@@ -61,7 +61,7 @@ func NewHandler(l *zap.Logger, s *proxy.Server) http.Handler {
 				return
 			}
 		}
-		goSource, ok := s.GoSource[uri]
+		goSource, ok := s.GetGoSource(uri)
 		if !ok {
 			if !ok {
 				Error(w, "uri not found in document contents", http.StatusNotFound)
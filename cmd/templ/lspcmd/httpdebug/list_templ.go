@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: a191c80bc14901791a72e765781668533d1bad82bd629adec33d16b3949626d8
 
 package httpdebug
 
@@ -9,126 +11,110 @@ import "context"
 import "io"
 import "bytes"
 
+//line list.templ:3
 func list(uris []string) templ.Component {
+//line list_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "httpdebug.list"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<table><tr><th>")
-		if err != nil {
-			return err
-		}
-		var_2 := `File`
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</th><th></th><th></th><th></th><th></th></tr>")
-		if err != nil {
-			return err
-		}
-		for _, uri := range uris {
-			_, err = templBuffer.WriteString("<tr><td>")
-			if err != nil {
-				return err
-			}
-			var var_3 string = uri
-			_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("</td><td><a href=\"")
-			if err != nil {
-				return err
-			}
-			var var_4 templ.SafeURL = getMapURL(uri)
-			_, err = templBuffer.WriteString(templ.EscapeString(string(var_4)))
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("\">")
-			if err != nil {
-				return err
-			}
-			var_5 := `Mapping`
-			_, err = templBuffer.WriteString(var_5)
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("</a></td><td><a href=\"")
-			if err != nil {
-				return err
-			}
-			var var_6 templ.SafeURL = getSourceMapURL(uri)
-			_, err = templBuffer.WriteString(templ.EscapeString(string(var_6)))
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("\">")
-			if err != nil {
-				return err
-			}
-			var_7 := `Source Map`
-			_, err = templBuffer.WriteString(var_7)
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("</a></td><td><a href=\"")
-			if err != nil {
-				return err
-			}
-			var var_8 templ.SafeURL = getTemplURL(uri)
-			_, err = templBuffer.WriteString(templ.EscapeString(string(var_8)))
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("\">")
-			if err != nil {
-				return err
-			}
-			var_9 := `Templ`
-			_, err = templBuffer.WriteString(var_9)
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("</a></td><td><a href=\"")
-			if err != nil {
-				return err
-			}
-			var var_10 templ.SafeURL = getGoURL(uri)
-			_, err = templBuffer.WriteString(templ.EscapeString(string(var_10)))
+		if !templSkip {
+			_, err = templBuffer.WriteString("<table><tr><th>File</th><th></th><th></th><th></th><th></th></tr>")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("\">")
-			if err != nil {
-				return err
+		}
+//line list.templ:12
+		for _, uri := range uris {
+//line list_templ.go:43
+			if !templSkip {
+				_, err = templBuffer.WriteString("<tr><td>")
+				if err != nil {
+					return err
+				}
+				var var_2 string
+//line list.templ:14
+				var_2, err = templ.EscapeAny(uri)
+//line list_templ.go:52
+				if err != nil {
+					return templ.WrapError(err, "cmd/templ/lspcmd/httpdebug/list.templ", 14, 11)
+				}
+				_, err = templBuffer.WriteString(var_2)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</td><td><a href=\"")
+				if err != nil {
+					return err
+				}
+//line list.templ:15
+				var var_3 templ.SafeURL = getMapURL(uri)
+//line list_templ.go:66
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_3)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\">Mapping</a></td><td><a href=\"")
+				if err != nil {
+					return err
+				}
+//line list.templ:16
+				var var_4 templ.SafeURL = getSourceMapURL(uri)
+//line list_templ.go:77
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_4)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\">Source Map</a></td><td><a href=\"")
+				if err != nil {
+					return err
+				}
+//line list.templ:17
+				var var_5 templ.SafeURL = getTemplURL(uri)
+//line list_templ.go:88
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_5)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\">Templ</a></td><td><a href=\"")
+				if err != nil {
+					return err
+				}
+//line list.templ:18
+				var var_6 templ.SafeURL = getGoURL(uri)
+//line list_templ.go:99
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_6)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\">Go</a></td></tr>")
+				if err != nil {
+					return err
+				}
 			}
-			var_11 := `Go`
-			_, err = templBuffer.WriteString(var_11)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</table>")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("</a></td></tr>")
-			if err != nil {
-				return err
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
 			}
 		}
-		_, err = templBuffer.WriteString("</table>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
-		}
 		return err
 	})
 }
@@ -0,0 +1,292 @@
+package lspcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/fmtcmd"
+	"github.com/a-h/templ/cmd/templ/lspcmd/proxy"
+	"github.com/google/go-cmp/cmp"
+	"go.lsp.dev/uri"
+	"golang.org/x/tools/txtar"
+)
+
+func loadFixture(t *testing.T, name string) map[string]string {
+	t.Helper()
+	archive, err := txtar.ParseFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	files := make(map[string]string, len(archive.Files))
+	for _, f := range archive.Files {
+		files[f.Name] = string(f.Data)
+	}
+	return files
+}
+
+func templURI(t *testing.T) protocol.DocumentURI {
+	return uri.File(filepath.Join(t.TempDir(), "input.templ"))
+}
+
+func goURI(templURI protocol.DocumentURI) protocol.DocumentURI {
+	return protocol.DocumentURI(strings.TrimSuffix(string(templURI), ".templ") + "_templ.go")
+}
+
+func TestLSPCompletion(t *testing.T) {
+	fixtures := []string{
+		"completion.txtar",
+		"completion-for.txtar",
+		"completion-if.txtar",
+		"completion-switch.txtar",
+		"completion-attribute.txtar",
+		"completion-ctx.txtar",
+	}
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(strings.TrimSuffix(fixture, ".txtar"), func(t *testing.T) {
+			testCompletion(t, loadFixture(t, fixture))
+		})
+	}
+}
+
+// testCompletion requests completion at the position in the input, and checks that gopls
+// receives the request on the line of Go code that contains go-contains, after the Go
+// code in go-before, if it's set.
+func testCompletion(t *testing.T, files map[string]string) {
+	pos, err := parsePosition(files["position"])
+	if err != nil {
+		t.Fatalf("invalid position: %v", err)
+	}
+	expectedRange, err := parseRange(files["expected-range"])
+	if err != nil {
+		t.Fatalf("invalid range: %v", err)
+	}
+
+	h := newHarness(t)
+	uri := templURI(t)
+	h.DidOpen(uri, files["input.templ"])
+	goSource := h.WaitForGoSource(goURI(uri), strings.TrimSpace(files["go-contains"]))
+
+	result := h.Completion(uri, pos)
+
+	// gopls should receive a request for the Go file, at the position of the expression.
+	requests := h.gopls.getCompletionRequests()
+	if len(requests) != 1 {
+		t.Fatalf("expected gopls to receive 1 completion request, got %d", len(requests))
+	}
+	if requests[0].TextDocument.URI != goURI(uri) {
+		t.Errorf("expected gopls to receive a request for %q, got %q", goURI(uri), requests[0].TextDocument.URI)
+	}
+	goLines := strings.Split(goSource, "\n")
+	goLine := goLines[requests[0].Position.Line]
+	if !strings.Contains(goLine, strings.TrimSpace(files["go-contains"])) {
+		t.Errorf("expected gopls position to be on a line containing %q, got %q", files["go-contains"], goLine)
+	}
+	if before := strings.TrimSpace(files["go-before"]); before != "" {
+		if col := int(requests[0].Position.Character); col > len(goLine) || !strings.HasSuffix(goLine[:col], before) {
+			t.Errorf("expected gopls position to be after %q, got column %d of %q", before, col, goLine)
+		}
+	}
+
+	// The editor should receive templ positions.
+	if result == nil || len(result.Items) != 1 {
+		t.Fatalf("expected 1 completion item, got %#v", result)
+	}
+	if diff := cmp.Diff(expectedRange, result.Items[0].TextEdit.Range); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLSPDiagnostics(t *testing.T) {
+	fixtures := []string{
+		"diagnostics.txtar",
+		"diagnostics-import.txtar",
+	}
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(strings.TrimSuffix(fixture, ".txtar"), func(t *testing.T) {
+			testDiagnostics(t, loadFixture(t, fixture))
+		})
+	}
+}
+
+// testDiagnostics checks that a diagnostic that gopls publishes for the Go code in diagnose
+// is mapped back to the expected range of the templ file.
+func testDiagnostics(t *testing.T, files map[string]string) {
+	expectedRange, err := parseRange(files["expected-range"])
+	if err != nil {
+		t.Fatalf("invalid range: %v", err)
+	}
+
+	h := newHarness(t)
+	h.gopls.diagnose = strings.TrimSpace(files["diagnose"])
+	uri := templURI(t)
+	h.DidOpen(uri, files["input.templ"])
+
+	diagnostics := h.WaitForDiagnostics(uri)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diagnostics))
+	}
+	if diff := cmp.Diff(expectedRange, diagnostics[0].Range); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLSPImports(t *testing.T) {
+	files := loadFixture(t, "imports.txtar")
+	pos, err := parsePosition(files["position"])
+	if err != nil {
+		t.Fatalf("invalid position: %v", err)
+	}
+	expectedRange, err := parseRange(files["expected-range"])
+	if err != nil {
+		t.Fatalf("invalid range: %v", err)
+	}
+	path := strings.TrimSpace(files["import-path"])
+	packageDir := uri.File(filepath.Join(t.TempDir(), path))
+
+	h := newHarness(t)
+	h.gopls.m.Lock()
+	h.gopls.packageDirs[path] = packageDir
+	h.gopls.m.Unlock()
+	uri := templURI(t)
+	h.DidOpen(uri, files["input.templ"])
+	h.WaitForGoSource(goURI(uri), path)
+
+	t.Run("definition returns the package directory", func(t *testing.T) {
+		expected := []protocol.Location{{URI: packageDir}}
+		if diff := cmp.Diff(expected, h.Definition(uri, pos)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("hover describes the package", func(t *testing.T) {
+		expected := &protocol.Hover{
+			Contents: protocol.MarkupContent{Kind: protocol.Markdown, Value: "package " + path},
+			Range:    &expectedRange,
+		}
+		if diff := cmp.Diff(expected, h.Hover(uri, pos)); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestLSPOutDir(t *testing.T) {
+	files := loadFixture(t, "diagnostics.txtar")
+	expectedRange, err := parseRange(files["expected-range"])
+	if err != nil {
+		t.Fatalf("invalid range: %v", err)
+	}
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "templ.json"), []byte(`{"generate": {"outDir": "gen", "suffix": ".templ.go"}}`), 0644); err != nil {
+		t.Fatalf("failed to write templ.json: %v", err)
+	}
+	templURI := uri.File(filepath.Join(dir, "views", "input.templ"))
+	goURI := uri.File(filepath.Join(dir, "gen", "views", "input.templ.go"))
+
+	h := startHarness(t, nil, proxy.NewURIMapper(nil))
+	h.gopls.diagnose = strings.TrimSpace(files["diagnose"])
+	h.DidOpen(templURI, files["input.templ"])
+
+	// gopls should receive the Go code at the path that templ generate writes it to, and
+	// its diagnostics should be mapped back to the templ file.
+	h.WaitForGoSource(goURI, strings.TrimSpace(files["diagnose"]))
+	diagnostics := h.WaitForDiagnostics(templURI)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diagnostics))
+	}
+	if diff := cmp.Diff(expectedRange, diagnostics[0].Range); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLSPDidChange(t *testing.T) {
+	files := loadFixture(t, "didchange.txtar")
+
+	h := newHarness(t)
+	uri := templURI(t)
+	h.DidOpen(uri, files["input.templ"])
+	h.DidChange(uri, 2, files["changed.templ"])
+
+	h.WaitForGoSource(goURI(uri), strings.TrimSpace(files["go-contains"]))
+}
+
+func TestLSPFormatting(t *testing.T) {
+	files := loadFixture(t, "formatting.txtar")
+
+	h := newHarness(t)
+	uri := templURI(t)
+	h.DidOpen(uri, files["input.templ"])
+
+	edits := h.Formatting(uri)
+	if len(edits) != 1 {
+		t.Fatalf("expected 1 edit, got %d", len(edits))
+	}
+	if diff := cmp.Diff(files["formatted.templ"], edits[0].NewText); diff != "" {
+		t.Error(diff)
+	}
+	// templ fmt must format templates in the same way as the language server.
+	formatted, err := fmtcmd.Format(files["input.templ"])
+	if err != nil {
+		t.Fatalf("templ fmt failed: %v", err)
+	}
+	if diff := cmp.Diff(edits[0].NewText, formatted); diff != "" {
+		t.Errorf("templ fmt output differs from the language server:\n%s", diff)
+	}
+}
+
+func TestLSPGoplsConfiguration(t *testing.T) {
+	options := map[string]interface{}{
+		"buildFlags": []interface{}{"-tags=dev"},
+	}
+	h := newHarnessWithOptions(t, options)
+	h.client.configuration = map[string]interface{}{
+		"gopls":          map[string]interface{}{"env": map[string]interface{}{"GOFLAGS": "-mod=mod"}},
+		"gopls.analyses": map[string]interface{}{"unusedparams": true},
+	}
+
+	t.Run("initialization options are forwarded to gopls", func(t *testing.T) {
+		if diff := cmp.Diff(options, h.gopls.getInitializationOptions()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("configuration requests from gopls are relayed to the editor", func(t *testing.T) {
+		result, err := h.gopls.client.Configuration(h.ctx, &protocol.ConfigurationParams{
+			Items: []protocol.ConfigurationItem{
+				{Section: "gopls.analyses"},
+				{Section: "gopls"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("configuration request failed: %v", err)
+		}
+		expected := []interface{}{
+			h.client.configuration["gopls.analyses"],
+			h.client.configuration["gopls"],
+		}
+		if diff := cmp.Diff(expected, result); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("configuration changes are forwarded to gopls", func(t *testing.T) {
+		settings := map[string]interface{}{"gopls": map[string]interface{}{"staticcheck": true}}
+		err := h.server.DidChangeConfiguration(h.ctx, &protocol.DidChangeConfigurationParams{Settings: settings})
+		if err != nil {
+			t.Fatalf("didChangeConfiguration failed: %v", err)
+		}
+		// Notifications are asynchronous, so wait for gopls to receive it.
+		for i := 0; i < 100; i++ {
+			if received := h.gopls.getSettings(); len(received) > 0 {
+				if diff := cmp.Diff(settings, received[0]); diff != "" {
+					t.Error(diff)
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Error("gopls did not receive the configuration change")
+	})
+}
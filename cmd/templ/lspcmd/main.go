@@ -3,11 +3,13 @@ package lspcmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 
-	"github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/config"
 	"github.com/a-h/templ/cmd/templ/lspcmd/httpdebug"
 	"github.com/a-h/templ/cmd/templ/lspcmd/pls"
 	"github.com/a-h/templ/cmd/templ/lspcmd/proxy"
@@ -26,6 +28,16 @@ type Arguments struct {
 	PPROF bool
 	// HTTPDebug sets the HTTP endpoint to listen on. Leave empty for no web debug.
 	HTTPDebug string
+	// Debug enables debug requests, such as templ/sourceMap.
+	Debug bool
+	// OutDir and Suffix are the -out-dir and -suffix arguments of templ generate, relative to
+	// the working directory. If neither is set, the templ.json file that applies to each
+	// templ file is used.
+	OutDir string
+	Suffix string
+	// Requests configures the timeouts of requests from the editor, and which requests are
+	// logged as slow.
+	Requests RequestOptions
 }
 
 func Run(args Arguments) error {
@@ -78,6 +90,15 @@ func run(ctx context.Context, args Arguments) (err error) {
 		}
 	}()
 
+	log.Info("lsp: checking gopls...")
+	goplsVersion, err := pls.Version(ctx)
+	if err != nil {
+		log.Error("gopls check failed", zap.String("version", goplsVersion), zap.Error(err))
+		serveStartupError(ctx, log, stdrwc{log: log}, err)
+		return nil
+	}
+	log.Info("lsp: found gopls", zap.String("version", goplsVersion))
+
 	log.Info("lsp: starting gopls...")
 	rwc, err := pls.NewGopls(ctx, log, pls.Options{
 		Log:      args.GoplsLog,
@@ -88,27 +109,16 @@ func run(ctx context.Context, args Arguments) (err error) {
 		os.Exit(1)
 	}
 
-	cache := proxy.NewSourceMapCache()
-
-	log.Info("creating client")
-	clientProxy, clientInit := proxy.NewClient(log, cache)
-	_, goplsConn, goplsServer := protocol.NewClient(context.Background(), clientProxy, jsonrpc2.NewStream(rwc), log)
+	uris, err := uriMapper(args)
+	if err != nil {
+		return err
+	}
+	templStream := stdrwc{log: log}
+	serverProxy, goplsConn, templConn := connect(log, uris, args.Requests, rwc, templStream)
+	serverProxy.DebugRequests = args.Debug
 	defer goplsConn.Close()
-
-	log.Info("creating proxy")
-	// Create the proxy to sit between.
-	serverProxy, serverInit := proxy.NewServer(log, goplsServer, cache)
-
-	// Create templ server.
-	log.Info("creating templ server")
-	templStream := jsonrpc2.NewStream(stdrwc{log: log})
-	_, templConn, templClient := protocol.NewServer(context.Background(), serverProxy, templStream, log)
 	defer templConn.Close()
 
-	// Allow both the server and the client to initiate outbound requests.
-	clientInit(templClient)
-	serverInit(templClient)
-
 	// Start the web server if required.
 	if args.HTTPDebug != "" {
 		log.Info("starting debug http server", zap.String("addr", args.HTTPDebug))
@@ -133,3 +143,55 @@ func run(ctx context.Context, args Arguments) (err error) {
 	log.Info("shutdown complete")
 	return
 }
+
+// uriMapper returns the mapping of templ files to the Go files generated from them, which
+// uses the arguments if they're set.
+func uriMapper(args Arguments) (*proxy.URIMapper, error) {
+	if args.OutDir == "" && args.Suffix == "" {
+		return proxy.NewURIMapper(nil), nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	o := config.Default(wd).Output()
+	if args.OutDir != "" {
+		o.OutDir = filepath.Join(wd, args.OutDir)
+		if filepath.IsAbs(args.OutDir) {
+			o.OutDir = args.OutDir
+		}
+	}
+	if args.Suffix != "" {
+		o.Suffix = args.Suffix
+	}
+	if err = o.Validate(); err != nil {
+		return nil, err
+	}
+	return proxy.NewURIMapper(&o), nil
+}
+
+// connect creates the templ proxy, and connects it to gopls and the editor.
+func connect(log *zap.Logger, uris *proxy.URIMapper, opts RequestOptions, gopls, editor io.ReadWriteCloser) (serverProxy *proxy.Server, goplsConn, templConn jsonrpc2.Conn) {
+	cache := proxy.NewSourceMapCache()
+
+	log.Info("creating client")
+	clientProxy, clientInit := proxy.NewClient(log, cache)
+	clientProxy.URIs = uris
+	goplsConn, goplsServer := newClientConn(context.Background(), log, clientProxy, jsonrpc2.NewStream(gopls))
+
+	log.Info("creating proxy")
+	// Create the proxy to sit between.
+	serverProxy, serverInit := proxy.NewServer(log, goplsServer, cache)
+	serverProxy.URIs = uris
+
+	// Create templ server.
+	log.Info("creating templ server")
+	templConn, templClient := newServerConn(context.Background(), log, opts, serverProxy, jsonrpc2.NewStream(editor))
+
+	// Allow both the server and the client to initiate outbound requests.
+	editorClient := proxy.NewDiagnosticsClient(log, templClient, proxy.DefaultDiagnosticsWindow)
+	clientInit(editorClient)
+	serverInit(editorClient)
+
+	return serverProxy, goplsConn, templConn
+}
@@ -33,7 +33,7 @@ func (opts Options) AsArguments() []string {
 func NewGopls(ctx context.Context, log *zap.Logger, opts Options) (rwc io.ReadWriteCloser, err error) {
 	_, err = exec.LookPath("gopls")
 	if errors.Is(err, exec.ErrNotFound) {
-		err = fmt.Errorf("cannot find gopls on the path (%q), you can install it with `go install golang.org/x/tools/gopls@latest`", os.Getenv("PATH"))
+		err = fmt.Errorf("cannot find gopls on the path (%q), %s", os.Getenv("PATH"), installInstructions)
 		return
 	}
 	if err != nil {
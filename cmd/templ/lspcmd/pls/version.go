@@ -0,0 +1,55 @@
+package pls
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// MinimumVersion is the oldest version of gopls that templ is tested against.
+const MinimumVersion = "v0.10.0"
+
+const installInstructions = "you can install it with `go install golang.org/x/tools/gopls@latest`"
+
+// Version runs `gopls version`, and checks that the installed gopls is supported.
+func Version(ctx context.Context) (version string, err error) {
+	if _, err = exec.LookPath("gopls"); err != nil {
+		return "", fmt.Errorf("cannot find gopls on the path (%q), %s", os.Getenv("PATH"), installInstructions)
+	}
+	output, err := exec.CommandContext(ctx, "gopls", "version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("gopls failed to start (%v): %s, %s", err, strings.TrimSpace(string(output)), installInstructions)
+	}
+	return parseVersion(string(output))
+}
+
+// parseVersion reads the version from the output of `gopls version`, e.g.:
+//
+//	golang.org/x/tools/gopls v0.11.0
+//	    golang.org/x/tools/gopls@v0.11.0 h1:/nvKHdTtePZE3ZD4eHBxOIgKvGi5/VoFwxJhBCIKR/0=
+func parseVersion(output string) (version string, err error) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "golang.org/x/tools/gopls" {
+			version = fields[1]
+			break
+		}
+	}
+	if version == "" {
+		return "", fmt.Errorf("could not find the gopls version in the output of `gopls version`: %q", output)
+	}
+	// Development builds report "(devel)", and are assumed to be recent.
+	if !semver.IsValid(version) {
+		return version, nil
+	}
+	if semver.Compare(version, MinimumVersion) < 0 {
+		return version, fmt.Errorf("gopls %s is too old, templ requires %s or later, %s", version, MinimumVersion, installInstructions)
+	}
+	return version, nil
+}
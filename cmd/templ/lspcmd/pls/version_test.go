@@ -0,0 +1,52 @@
+package pls
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		expectedVersion string
+		expectedError   string
+	}{
+		{
+			name:            "supported versions are accepted",
+			output:          "golang.org/x/tools/gopls v0.11.0\n    golang.org/x/tools/gopls@v0.11.0 h1:abc=\n",
+			expectedVersion: "v0.11.0",
+		},
+		{
+			name:            "development builds are accepted",
+			output:          "golang.org/x/tools/gopls (devel)\n",
+			expectedVersion: "(devel)",
+		},
+		{
+			name:            "old versions are rejected",
+			output:          "golang.org/x/tools/gopls v0.7.5\n",
+			expectedVersion: "v0.7.5",
+			expectedError:   "too old",
+		},
+		{
+			name:          "unexpected output is rejected",
+			output:        "command not found\n",
+			expectedError: "could not find the gopls version",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := parseVersion(tt.output)
+			if version != tt.expectedVersion {
+				t.Errorf("expected version %q, got %q", tt.expectedVersion, version)
+			}
+			if tt.expectedError == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+				t.Errorf("expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"os"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"go.uber.org/zap"
+)
+
+// convertGoCallHierarchyItemToTempl maps call hierarchy items within generated *_templ.go files
+// back to their templ declarations. Items that are part of the generated code, rather than
+// a templ or Go code written by the user, are excluded by returning ok=false.
+func (p *Server) convertGoCallHierarchyItemToTempl(item lsp.CallHierarchyItem) (output lsp.CallHierarchyItem, ok bool) {
+	isTemplGoFile, templURI := p.URIs.TemplGoToTempl(item.URI)
+	if !isTemplGoFile {
+		return item, true
+	}
+	output = item
+	output.URI = templURI
+	// Go code written within the templ file is in the source map.
+	if sourceMap, ok := p.getSourceMap(templURI); ok {
+		if start, ok := sourceMap.SourcePositionFromTargetUTF16(item.SelectionRange.Start.Line, item.SelectionRange.Start.Character); ok {
+			output.SelectionRange = convertGoRangeToTemplRange(sourceMap, item.SelectionRange)
+			output.SelectionRange.Start = lsp.Position{Line: start.Line, Character: start.Col}
+			output.Range = output.SelectionRange
+			return output, true
+		}
+	}
+	// Calls made within the function literals of a templ are attributed to the literal,
+	// e.g. "Page$1", so the templ is found by name.
+	name, _, _ := strings.Cut(item.Name, "$")
+	name, _, _ = strings.Cut(name, ".")
+	for _, s := range p.templSymbols(templURI) {
+		if unqualifiedName(s.Name) == name {
+			output.Name = s.Name
+			output.Range = s.SelectionRange
+			output.SelectionRange = s.SelectionRange
+			return output, true
+		}
+	}
+	return output, false
+}
+
+// convertTemplCallHierarchyItemToGo maps a call hierarchy item that was returned to the client
+// back to the generated Go code, so that gopls can find it.
+func (p *Server) convertTemplCallHierarchyItemToGo(item lsp.CallHierarchyItem) lsp.CallHierarchyItem {
+	templURI := item.URI
+	isTemplFile, goURI := p.URIs.TemplToGo(templURI)
+	if !isTemplFile {
+		return item
+	}
+	item.URI = goURI
+	item.Range = p.convertTemplRangeToGoRange(templURI, item.Range)
+	item.SelectionRange = p.convertTemplRangeToGoRange(templURI, item.SelectionRange)
+	return item
+}
+
+// convertGoCallRangesToTempl maps the ranges of calls within a generated *_templ.go file to
+// the templ file. Ranges within generated code are removed.
+func (p *Server) convertGoCallRangesToTempl(templURI lsp.DocumentURI, ranges []lsp.Range) (output []lsp.Range) {
+	sourceMap, ok := p.getSourceMap(templURI)
+	if !ok {
+		return nil
+	}
+	for _, r := range ranges {
+		if _, ok := sourceMap.SourcePositionFromTargetUTF16(r.Start.Line, r.Start.Character); !ok {
+			continue
+		}
+		output = append(output, convertGoRangeToTemplRange(sourceMap, r))
+	}
+	return output
+}
+
+// templSymbols returns the declarations within a templ file, reading it from disk if it isn't open.
+func (p *Server) templSymbols(templURI lsp.DocumentURI) (symbols []lsp.DocumentSymbol) {
+	var lines []string
+	if doc, ok := p.TemplSource.Get(string(templURI)); ok {
+		lines = doc.Lines
+	} else {
+		data, err := os.ReadFile(templURI.Filename())
+		if err != nil {
+			p.Log.Info("templSymbols: failed to read file", zap.String("uri", string(templURI)), zap.Error(err))
+			return nil
+		}
+		lines = strings.Split(string(data), "\n")
+	}
+	symbols, err := documentSymbols(lines)
+	if err != nil {
+		p.Log.Info("templSymbols: failed to parse template", zap.String("uri", string(templURI)), zap.Error(err))
+		return nil
+	}
+	return symbols
+}
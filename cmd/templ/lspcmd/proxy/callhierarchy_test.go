@@ -0,0 +1,178 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+const callHierarchyTestTemplate = `package main
+
+templ Child() {
+	<div></div>
+}
+
+templ Parent() {
+	@Child()
+}
+`
+
+type callHierarchyTarget struct {
+	testTarget
+	prepare  func(params *lsp.CallHierarchyPrepareParams) []lsp.CallHierarchyItem
+	incoming func(params *lsp.CallHierarchyIncomingCallsParams) []lsp.CallHierarchyIncomingCall
+	outgoing func(params *lsp.CallHierarchyOutgoingCallsParams) []lsp.CallHierarchyOutgoingCall
+}
+
+func (t callHierarchyTarget) PrepareCallHierarchy(ctx context.Context, params *lsp.CallHierarchyPrepareParams) ([]lsp.CallHierarchyItem, error) {
+	return t.prepare(params), nil
+}
+
+func (t callHierarchyTarget) IncomingCalls(ctx context.Context, params *lsp.CallHierarchyIncomingCallsParams) ([]lsp.CallHierarchyIncomingCall, error) {
+	return t.incoming(params), nil
+}
+
+func (t callHierarchyTarget) OutgoingCalls(ctx context.Context, params *lsp.CallHierarchyOutgoingCallsParams) ([]lsp.CallHierarchyOutgoingCall, error) {
+	return t.outgoing(params), nil
+}
+
+// goRangeOf returns the range of the first instance of s in a line that has the prefix.
+func goRangeOf(t *testing.T, goCode, linePrefix, s string) lsp.Range {
+	t.Helper()
+	for i, line := range strings.Split(goCode, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), linePrefix) {
+			continue
+		}
+		if col := strings.Index(line, s); col >= 0 {
+			return lsp.Range{
+				Start: lsp.Position{Line: uint32(i), Character: uint32(col)},
+				End:   lsp.Position{Line: uint32(i), Character: uint32(col + len(s))},
+			}
+		}
+	}
+	t.Fatalf("%q not found in generated code", s)
+	return lsp.Range{}
+}
+
+func TestCallHierarchy(t *testing.T) {
+	tf, err := parser.ParseString(callHierarchyTestTemplate)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var sb strings.Builder
+	result, err := generator.GenerateFile(generator.GenerateOpts{}, tf, &sb)
+	if err != nil {
+		t.Fatalf("failed to generate template: %v", err)
+	}
+	sm := result.SourceMap
+	goCode := sb.String()
+
+	templURI := lsp.DocumentURI("file:///components.templ")
+	goURI := lsp.DocumentURI("file:///components_templ.go")
+	childDeclaration := goRangeOf(t, goCode, "func Child(", "Child")
+	childCall := goRangeOf(t, goCode, "err = Child()", "Child")
+	boilerplateCall := goRangeOf(t, goCode, "templBuffer, templIsBuffer :=", "templBuffer")
+	parentLiteral := goRangeOf(t, goCode, "return templ.ComponentFunc", "func")
+
+	childItem := lsp.CallHierarchyItem{Name: "Child", Kind: lsp.SymbolKindFunction, URI: goURI, Range: childDeclaration, SelectionRange: childDeclaration}
+	templChildName := lsp.Range{Start: lsp.Position{Line: 2, Character: 6}, End: lsp.Position{Line: 2, Character: 11}}
+	templParentName := lsp.Range{Start: lsp.Position{Line: 6, Character: 6}, End: lsp.Position{Line: 6, Character: 12}}
+	templChildCall := lsp.Range{Start: lsp.Position{Line: 7, Character: 2}, End: lsp.Position{Line: 7, Character: 7}}
+
+	target := callHierarchyTarget{
+		prepare: func(params *lsp.CallHierarchyPrepareParams) []lsp.CallHierarchyItem {
+			if params.TextDocument.URI != goURI || params.Position.Line != childDeclaration.Start.Line {
+				t.Errorf("unexpected prepare request: %v %v", params.TextDocument.URI, params.Position)
+			}
+			return []lsp.CallHierarchyItem{childItem}
+		},
+		incoming: func(params *lsp.CallHierarchyIncomingCallsParams) []lsp.CallHierarchyIncomingCall {
+			if diff := cmp.Diff(childItem.SelectionRange, params.Item.SelectionRange); diff != "" || params.Item.URI != goURI {
+				t.Errorf("unexpected incoming calls item: %v\n%s", params.Item.URI, diff)
+			}
+			return []lsp.CallHierarchyIncomingCall{
+				{
+					From:       lsp.CallHierarchyItem{Name: "Parent$1", URI: goURI, Range: parentLiteral, SelectionRange: parentLiteral},
+					FromRanges: []lsp.Range{childCall},
+				},
+				{
+					From:       lsp.CallHierarchyItem{Name: "Child$1", URI: goURI, Range: parentLiteral, SelectionRange: parentLiteral},
+					FromRanges: []lsp.Range{boilerplateCall},
+				},
+			}
+		},
+		outgoing: func(params *lsp.CallHierarchyOutgoingCallsParams) []lsp.CallHierarchyOutgoingCall {
+			return []lsp.CallHierarchyOutgoingCall{
+				{
+					To:         childItem,
+					FromRanges: []lsp.Range{childCall},
+				},
+				{
+					To:         lsp.CallHierarchyItem{Name: "GetBuffer", URI: "file:///templ/runtime.go"},
+					FromRanges: []lsp.Range{boilerplateCall},
+				},
+			}
+		},
+	}
+	s, _ := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	s.SourceMapCache.Set(string(templURI), sm)
+	s.TemplSource.Set(string(templURI), 1, NewDocument(zap.NewNop(), callHierarchyTestTemplate))
+
+	items, err := s.PrepareCallHierarchy(context.Background(), &lsp.CallHierarchyPrepareParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+			Position:     lsp.Position{Line: 2, Character: 7},
+		},
+	})
+	if err != nil {
+		t.Fatalf("prepare failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if items[0].URI != templURI {
+		t.Errorf("expected item URI %q, got %q", templURI, items[0].URI)
+	}
+	if diff := cmp.Diff(templChildName, items[0].SelectionRange); diff != "" {
+		t.Errorf("unexpected prepared item range:\n%s", diff)
+	}
+
+	incoming, err := s.IncomingCalls(context.Background(), &lsp.CallHierarchyIncomingCallsParams{Item: items[0]})
+	if err != nil {
+		t.Fatalf("incoming calls failed: %v", err)
+	}
+	if len(incoming) != 1 {
+		t.Fatalf("expected 1 incoming call, got %d", len(incoming))
+	}
+	if incoming[0].From.Name != "Parent" || incoming[0].From.URI != templURI {
+		t.Errorf("expected a call from Parent, got %q in %q", incoming[0].From.Name, incoming[0].From.URI)
+	}
+	if diff := cmp.Diff(templParentName, incoming[0].From.SelectionRange); diff != "" {
+		t.Errorf("unexpected caller range:\n%s", diff)
+	}
+	if diff := cmp.Diff([]lsp.Range{templChildCall}, incoming[0].FromRanges); diff != "" {
+		t.Errorf("unexpected call ranges:\n%s", diff)
+	}
+
+	outgoing, err := s.OutgoingCalls(context.Background(), &lsp.CallHierarchyOutgoingCallsParams{
+		Item: lsp.CallHierarchyItem{Name: "Parent", URI: templURI, Range: templParentName, SelectionRange: templParentName},
+	})
+	if err != nil {
+		t.Fatalf("outgoing calls failed: %v", err)
+	}
+	if len(outgoing) != 1 {
+		t.Fatalf("expected 1 outgoing call, got %d", len(outgoing))
+	}
+	if outgoing[0].To.URI != templURI || outgoing[0].To.Name != "Child" {
+		t.Errorf("expected a call to Child, got %q in %q", outgoing[0].To.Name, outgoing[0].To.URI)
+	}
+	if diff := cmp.Diff([]lsp.Range{templChildCall}, outgoing[0].FromRanges); diff != "" {
+		t.Errorf("unexpected call ranges:\n%s", diff)
+	}
+}
@@ -20,12 +20,15 @@ type Client struct {
 	Log            *zap.Logger
 	Target         lsp.Client
 	SourceMapCache *SourceMapCache
+	// URIs maps the Go files generated from templ files back to the templ files.
+	URIs *URIMapper
 }
 
 func NewClient(log *zap.Logger, cache *SourceMapCache) (c *Client, init func(lsp.Client)) {
 	c = &Client{
 		Log:            log,
 		SourceMapCache: cache,
+		URIs:           NewURIMapper(nil),
 	}
 	return c, func(target lsp.Client) {
 		c.Target = target
@@ -52,7 +55,11 @@ func (p Client) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagn
 		p.Log.Info(fmt.Sprintf("client <- server: PublishDiagnostics: [%d]", i), zap.Any("diagnostic", diagnostic))
 	}
 	// Get the sourcemap from the cache.
-	uri := strings.TrimSuffix(string(params.URI), "_templ.go") + ".templ"
+	isTemplGoFile, templURI := p.URIs.TemplGoToTempl(params.URI)
+	if !isTemplGoFile {
+		return fmt.Errorf("unable to publish diagnostics for %q, because it isn't generated from a templ file", params.URI)
+	}
+	uri := string(templURI)
 	sourceMap, ok := p.SourceMapCache.Get(uri)
 	if !ok {
 		return fmt.Errorf("unable to complete because the sourcemap for %q doesn't exist in the cache, has the didOpen notification been sent yet?", uri)
@@ -61,7 +68,7 @@ func (p Client) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagn
 	// Rewrite the positions.
 	for i := 0; i < len(params.Diagnostics); i++ {
 		item := params.Diagnostics[i]
-		start, ok := sourceMap.SourcePositionFromTarget(item.Range.Start.Line, item.Range.Start.Character)
+		start, ok := sourceMap.SourcePositionFromTargetUTF16(item.Range.Start.Line, item.Range.Start.Character)
 		if !ok {
 			continue
 		}
@@ -75,7 +82,7 @@ func (p Client) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagn
 			p.Log.Info(fmt.Sprintf("diagnostic [%d] rewritten", i), zap.Any("diagnostic", item))
 			continue
 		}
-		end, ok := sourceMap.SourcePositionFromTarget(item.Range.End.Line, item.Range.End.Character)
+		end, ok := sourceMap.SourcePositionFromTargetUTF16(item.Range.End.Line, item.Range.End.Character)
 		if !ok {
 			continue
 		}
@@ -123,8 +130,21 @@ func (p Client) ApplyEdit(ctx context.Context, params *lsp.ApplyWorkspaceEditPar
 }
 
 func (p Client) Configuration(ctx context.Context, params *lsp.ConfigurationParams) (result []interface{}, err error) {
-	p.Log.Info("client <- server: Configuration")
-	return p.Target.Configuration(ctx, params)
+	sections := make([]string, len(params.Items))
+	for i, item := range params.Items {
+		sections[i] = item.Section
+	}
+	p.Log.Info("client <- server: Configuration", zap.Strings("sections", sections))
+	result, err = p.Target.Configuration(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// gopls matches results to items by index, so there must be a result, even if it's
+	// null, for each item.
+	for len(result) < len(params.Items) {
+		result = append(result, nil)
+	}
+	return result, nil
 }
 
 func (p Client) WorkspaceFolders(ctx context.Context) (result []lsp.WorkspaceFolder, err error) {
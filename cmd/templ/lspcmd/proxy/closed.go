@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	lsp "github.com/a-h/protocol"
+	"go.uber.org/zap"
+)
+
+// closedDocuments are templ files that have been closed in the editor. Their
+// diagnostics are kept up-to-date from the file on disk, without caching the
+// document contents.
+type closedDocuments struct {
+	m    sync.Mutex
+	uris map[lsp.DocumentURI]struct{}
+}
+
+func (cd *closedDocuments) Add(uri lsp.DocumentURI) {
+	cd.m.Lock()
+	defer cd.m.Unlock()
+	if cd.uris == nil {
+		cd.uris = make(map[lsp.DocumentURI]struct{})
+	}
+	cd.uris[uri] = struct{}{}
+}
+
+func (cd *closedDocuments) Remove(uri lsp.DocumentURI) {
+	cd.m.Lock()
+	defer cd.m.Unlock()
+	delete(cd.uris, uri)
+}
+
+func (cd *closedDocuments) Contains(uri lsp.DocumentURI) bool {
+	cd.m.Lock()
+	defer cd.m.Unlock()
+	_, ok := cd.uris[uri]
+	return ok
+}
+
+// publishFromDisk regenerates a closed templ file from disk, so that its diagnostics
+// reflect the state of the file, and gopls diagnostics for the generated Go can still
+// be mapped back. If the file has been deleted, its diagnostics are cleared.
+func (p *Server) publishFromDisk(ctx context.Context, templURI lsp.DocumentURI) (err error) {
+	contents, err := os.ReadFile(templURI.Filename())
+	if errors.Is(err, fs.ErrNotExist) {
+		p.Log.Info("closed document has been deleted, clearing diagnostics", zap.String("uri", string(templURI)))
+		p.closedDocuments.Remove(templURI)
+		p.SourceMapCache.Delete(string(templURI))
+		if ls, ok := p.Client.(lintDiagnosticsSetter); ok {
+			ls.SetLintDiagnostics(templURI, nil)
+		}
+		return p.Client.PublishDiagnostics(ctx, &lsp.PublishDiagnosticsParams{
+			URI:         templURI,
+			Diagnostics: []lsp.Diagnostic{},
+		})
+	}
+	if err != nil {
+		return err
+	}
+	p.closedDocuments.Add(templURI)
+	template, ok, err := p.parseTemplate(ctx, templURI, string(contents))
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		p.SourceMapCache.Delete(string(templURI))
+		return nil
+	}
+	w := new(strings.Builder)
+	sm, err := generate(templURI, template, w)
+	if err != nil {
+		p.SourceMapCache.Delete(string(templURI))
+		return p.publishGeneratorError(ctx, templURI, err)
+	}
+	sm.SetText(string(contents), w.String())
+	p.SourceMapCache.Set(string(templURI), sm)
+	return nil
+}
@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// endTagCompletion returns the end tags of the elements that are open at the position, the
+// nearest first, if the text before the position is the start of an end tag, e.g. "</" or
+// "</di". Each item replaces the start of the end tag with the whole end tag.
+func endTagCompletion(lines []string, pos lsp.Position) (items []lsp.CompletionItem, ok bool) {
+	if int(pos.Line) >= len(lines) || int(pos.Character) > len(lines[pos.Line]) {
+		return
+	}
+	line := lines[pos.Line]
+	start := strings.LastIndex(line[:pos.Character], "</")
+	if start < 0 || strings.IndexFunc(line[start+2:pos.Character], isNotElementNameChar) >= 0 {
+		return
+	}
+	// The document is usually invalid while the end tag is being typed, so the open elements
+	// are found by parsing as much of the document as possible.
+	index := indexOfPosition(lines, lsp.Position{Line: pos.Line, Character: uint32(start)})
+	names := parser.OpenElements(strings.Join(lines, "\n"), index)
+	// Replace the ">" too, if the editor has already inserted it.
+	end := pos.Character
+	if int(end) < len(line) && line[end] == '>' {
+		end++
+	}
+	r := lsp.Range{
+		Start: lsp.Position{Line: pos.Line, Character: uint32(start)},
+		End:   lsp.Position{Line: pos.Line, Character: end},
+	}
+	seen := make(map[string]bool)
+	for i := len(names) - 1; i >= 0; i-- {
+		if seen[names[i]] {
+			continue
+		}
+		seen[names[i]] = true
+		tag := "</" + names[i] + ">"
+		items = append(items, lsp.CompletionItem{
+			Label:     tag,
+			Kind:      lsp.CompletionItemKind(lsp.CompletionItemKindProperty),
+			SortText:  fmt.Sprintf("%04d", len(items)),
+			Preselect: len(items) == 0,
+			TextEdit: &lsp.TextEdit{
+				Range:   r,
+				NewText: tag,
+			},
+		})
+	}
+	return items, len(items) > 0
+}
+
+func isNotElementNameChar(r rune) bool {
+	return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-._", r)
+}
@@ -0,0 +1,136 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+// splitAtCursor removes the "|" that marks the cursor from the template, and returns its lines
+// and the position of the cursor.
+func splitAtCursor(template string) (lines []string, pos lsp.Position) {
+	index := strings.Index(template, "|")
+	before := template[:index]
+	pos.Line = uint32(strings.Count(before, "\n"))
+	pos.Character = uint32(index - (strings.LastIndex(before, "\n") + 1))
+	return strings.Split(before+template[index+1:], "\n"), pos
+}
+
+func TestEndTagCompletion(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      string
+		expected      []string
+		expectedRange lsp.Range
+	}{
+		{
+			name: "the nearest unclosed element is first",
+			template: `templ page() {
+	<main>
+		<section>
+			<p>
+				text
+			</|`,
+			expected: []string{"</p>", "</section>", "</main>"},
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 5, Character: 3},
+				End:   lsp.Position{Line: 5, Character: 5},
+			},
+		},
+		{
+			name: "the cursor is inside an if block",
+			template: `templ list(items []string, show bool) {
+	<ul>
+		if show {
+			for _, item := range items {
+				<li>
+					<span>{ item }</span>
+				</|
+			}
+		}
+	</ul>
+}`,
+			expected: []string{"</li>", "</ul>"},
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 6, Character: 4},
+				End:   lsp.Position{Line: 6, Character: 6},
+			},
+		},
+		{
+			name: "the start of the name and a closing bracket are replaced",
+			template: `templ page() {
+	<div>
+		<div>
+		</di|>`,
+			expected: []string{"</div>"},
+			expectedRange: lsp.Range{
+				Start: lsp.Position{Line: 3, Character: 2},
+				End:   lsp.Position{Line: 3, Character: 7},
+			},
+		},
+		{
+			name: "end tags aren't offered if all elements are closed",
+			template: `templ page() {
+	<div></div>
+	</|`,
+			expected: nil,
+		},
+		{
+			name: "end tags aren't offered outside an end tag",
+			template: `templ page() {
+	<div>
+		<p>a</p> |`,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			items, ok := endTagCompletion(splitAtCursor(tt.template))
+			if ok != (len(tt.expected) > 0) {
+				t.Fatalf("expected ok to be %v, got %v", len(tt.expected) > 0, ok)
+			}
+			var labels []string
+			for i, item := range items {
+				labels = append(labels, item.Label)
+				if diff := cmp.Diff(tt.expectedRange, item.TextEdit.Range); diff != "" {
+					t.Errorf("item %d range:\n%s", i, diff)
+				}
+				if item.TextEdit.NewText != item.Label {
+					t.Errorf("expected item %d to insert %q, got %q", i, item.Label, item.TextEdit.NewText)
+				}
+				if item.Preselect != (i == 0) {
+					t.Errorf("expected only the first item to be preselected")
+				}
+			}
+			if diff := cmp.Diff(tt.expected, labels); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestEndTagsAreCompletedWithoutCallingGopls(t *testing.T) {
+	// The target panics if it's called.
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(&testClient{})
+	lines, pos := splitAtCursor("package main\n\ntempl page() {\n\t<div>\n\t\t<p>\n\t\t</|")
+	s.TemplSource.Set("file:///a.templ", 1, NewDocument(zap.NewNop(), strings.Join(lines, "\n")))
+	result, err := s.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: "file:///a.templ"},
+			Position:     pos,
+		},
+		Context: &lsp.CompletionContext{TriggerKind: lsp.CompletionTriggerKindTriggerCharacter, TriggerCharacter: "/"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil || len(result.Items) != 2 || result.Items[0].Label != "</p>" {
+		t.Errorf("expected </p> to be the first of 2 items, got %#v", result)
+	}
+}
@@ -0,0 +1,210 @@
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+)
+
+// documentColors returns the colors within the values of the constant properties of css
+// templates, and of constant style attributes. The values of Go expressions aren't known
+// until the template is rendered, so they're skipped.
+func documentColors(lines []string) (result []lsp.ColorInformation, err error) {
+	text := strings.Join(lines, "\n")
+	tf, err := parseString(text)
+	if err != nil {
+		return nil, err
+	}
+	sc := &spanCollector{text: text}
+	sc.templateFile(tf)
+	result = []lsp.ColorInformation{}
+	for _, v := range sc.cssValues {
+		for _, c := range findColors(text[v.from:v.to]) {
+			result = append(result, lsp.ColorInformation{
+				Range: lsp.Range{
+					Start: positionOfIndex(text, v.from+c.from),
+					End:   positionOfIndex(text, v.from+c.to),
+				},
+				Color: c.color,
+			})
+		}
+	}
+	return result, nil
+}
+
+var (
+	hexColor      = regexp.MustCompile(`#[0-9a-fA-F]+\b`)
+	functionColor = regexp.MustCompile(`(?i)\b(rgba?|hsla?)\(([^()]*)\)`)
+)
+
+type foundColor struct {
+	span
+	color lsp.Color
+}
+
+// findColors returns the hex, rgb() and hsl() colors within a CSS value.
+func findColors(value string) (colors []foundColor) {
+	for _, m := range hexColor.FindAllStringIndex(value, -1) {
+		if c, ok := parseHexColor(value[m[0]+1 : m[1]]); ok {
+			colors = append(colors, foundColor{span: span{from: m[0], to: m[1]}, color: c})
+		}
+	}
+	for _, m := range functionColor.FindAllStringSubmatchIndex(value, -1) {
+		name := strings.ToLower(value[m[2]:m[3]])
+		if c, ok := parseFunctionColor(name, value[m[4]:m[5]]); ok {
+			colors = append(colors, foundColor{span: span{from: m[0], to: m[1]}, color: c})
+		}
+	}
+	return colors
+}
+
+// parseHexColor parses the digits of a #rgb, #rgba, #rrggbb or #rrggbbaa color.
+func parseHexColor(digits string) (c lsp.Color, ok bool) {
+	if len(digits) == 3 || len(digits) == 4 {
+		var expanded strings.Builder
+		for _, d := range digits {
+			expanded.WriteRune(d)
+			expanded.WriteRune(d)
+		}
+		digits = expanded.String()
+	}
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	if len(digits) != 8 {
+		return c, false
+	}
+	v, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return c, false
+	}
+	return lsp.Color{
+		Red:   float64(v>>24&0xff) / 255,
+		Green: float64(v>>16&0xff) / 255,
+		Blue:  float64(v>>8&0xff) / 255,
+		Alpha: float64(v&0xff) / 255,
+	}, true
+}
+
+// parseFunctionColor parses the arguments of an rgb(), rgba(), hsl() or hsla() color, in
+// either the comma separated, or the space separated syntax, e.g. rgb(255, 0, 0) or
+// rgb(255 0 0 / 50%).
+func parseFunctionColor(name, args string) (c lsp.Color, ok bool) {
+	fields := strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == '/' || r == ' ' || r == '\t' })
+	if len(fields) != 3 && len(fields) != 4 {
+		return c, false
+	}
+	c.Alpha = 1
+	if len(fields) == 4 {
+		if c.Alpha, ok = parseColorComponent(fields[3], 1); !ok {
+			return c, false
+		}
+	}
+	if strings.HasPrefix(name, "rgb") {
+		if c.Red, ok = parseColorComponent(fields[0], 255); !ok {
+			return c, false
+		}
+		if c.Green, ok = parseColorComponent(fields[1], 255); !ok {
+			return c, false
+		}
+		if c.Blue, ok = parseColorComponent(fields[2], 255); !ok {
+			return c, false
+		}
+		return c, true
+	}
+	hue, err := strconv.ParseFloat(strings.TrimSuffix(fields[0], "deg"), 64)
+	if err != nil {
+		return c, false
+	}
+	if !strings.HasSuffix(fields[1], "%") || !strings.HasSuffix(fields[2], "%") {
+		return c, false
+	}
+	saturation, ok := parseColorComponent(fields[1], 1)
+	if !ok {
+		return c, false
+	}
+	lightness, ok := parseColorComponent(fields[2], 1)
+	if !ok {
+		return c, false
+	}
+	c.Red, c.Green, c.Blue = hslToRGB(hue, saturation, lightness)
+	return c, true
+}
+
+// parseColorComponent parses a number between 0 and max, or a percentage, and returns it in
+// the range [0-1].
+func parseColorComponent(s string, max float64) (v float64, ok bool) {
+	if strings.HasSuffix(s, "%") {
+		s, max = strings.TrimSuffix(s, "%"), 100
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Min(math.Max(v/max, 0), 1), true
+}
+
+func hslToRGB(hue, saturation, lightness float64) (r, g, b float64) {
+	hue = math.Mod(math.Mod(hue, 360)+360, 360)
+	f := func(n float64) float64 {
+		k := math.Mod(n+hue/30, 12)
+		a := saturation * math.Min(lightness, 1-lightness)
+		return lightness - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return f(0), f(8), f(4)
+}
+
+func rgbToHSL(r, g, b float64) (hue, saturation, lightness float64) {
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	lightness = (max + min) / 2
+	d := max - min
+	if d == 0 {
+		return 0, 0, lightness
+	}
+	saturation = d / (1 - math.Abs(2*lightness-1))
+	switch max {
+	case r:
+		hue = math.Mod((g-b)/d+6, 6)
+	case g:
+		hue = (b-r)/d + 2
+	default:
+		hue = (r-g)/d + 4
+	}
+	return hue * 60, saturation, lightness
+}
+
+// colorPresentations returns the color written as hex, rgb() and hsl(), each replacing the
+// range.
+func colorPresentations(c lsp.Color, r lsp.Range) (result []lsp.ColorPresentation) {
+	red, green, blue, alpha := colorByte(c.Red), colorByte(c.Green), colorByte(c.Blue), colorByte(c.Alpha)
+	hue, saturation, lightness := rgbToHSL(c.Red, c.Green, c.Blue)
+	h, s, l := math.Round(hue), math.Round(saturation*100), math.Round(lightness*100)
+	labels := []string{
+		fmt.Sprintf("#%02x%02x%02x", red, green, blue),
+		fmt.Sprintf("rgb(%d, %d, %d)", red, green, blue),
+		fmt.Sprintf("hsl(%g, %g%%, %g%%)", h, s, l),
+	}
+	if alpha < 255 {
+		a := strconv.FormatFloat(math.Round(c.Alpha*100)/100, 'f', -1, 64)
+		labels = []string{
+			fmt.Sprintf("#%02x%02x%02x%02x", red, green, blue, alpha),
+			fmt.Sprintf("rgba(%d, %d, %d, %s)", red, green, blue, a),
+			fmt.Sprintf("hsla(%g, %g%%, %g%%, %s)", h, s, l, a),
+		}
+	}
+	for _, label := range labels {
+		result = append(result, lsp.ColorPresentation{
+			Label:    label,
+			TextEdit: &lsp.TextEdit{Range: r, NewText: label},
+		})
+	}
+	return result
+}
+
+func colorByte(v float64) uint8 {
+	return uint8(math.Round(math.Min(math.Max(v, 0), 1) * 255))
+}
@@ -0,0 +1,177 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestDocumentColors(t *testing.T) {
+	type color struct {
+		Text  string
+		Range lsp.Range
+		Color lsp.Color
+	}
+	tests := []struct {
+		name     string
+		template string
+		expected []color
+	}{
+		{
+			name: "css templates",
+			template: `package main
+
+css warning() {
+	color: #ff0044;
+	background-color: rgb(1, 2, 3);
+	border-color: { red };
+}`,
+			expected: []color{
+				{
+					Text: "#ff0044",
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 3, Character: 8},
+						End:   lsp.Position{Line: 3, Character: 15},
+					},
+					Color: lsp.Color{Red: 1, Green: 0, Blue: 68.0 / 255, Alpha: 1},
+				},
+				{
+					Text: "rgb(1, 2, 3)",
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 4, Character: 19},
+						End:   lsp.Position{Line: 4, Character: 31},
+					},
+					Color: lsp.Color{Red: 1.0 / 255, Green: 2.0 / 255, Blue: 3.0 / 255, Alpha: 1},
+				},
+			},
+		},
+		{
+			name: "style attributes",
+			template: `package main
+
+templ page(color string) {
+	<div style="border: 1px solid #0f0; color: hsl(240, 100%, 50%)"></div>
+	<div style={ color }></div>
+	<div title="#fff"></div>
+}`,
+			expected: []color{
+				{
+					Text: "#0f0",
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 3, Character: 31},
+						End:   lsp.Position{Line: 3, Character: 35},
+					},
+					Color: lsp.Color{Green: 1, Alpha: 1},
+				},
+				{
+					Text: "hsl(240, 100%, 50%)",
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 3, Character: 44},
+						End:   lsp.Position{Line: 3, Character: 63},
+					},
+					Color: lsp.Color{Blue: 1, Alpha: 1},
+				},
+			},
+		},
+		{
+			name: "alpha",
+			template: `package main
+
+css faded() {
+	color: #ff000080;
+	background: rgba(0 0 255 / 50%);
+}`,
+			expected: []color{
+				{
+					Text: "#ff000080",
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 3, Character: 8},
+						End:   lsp.Position{Line: 3, Character: 17},
+					},
+					Color: lsp.Color{Red: 1, Alpha: 128.0 / 255},
+				},
+				{
+					Text: "rgba(0 0 255 / 50%)",
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 4, Character: 13},
+						End:   lsp.Position{Line: 4, Character: 32},
+					},
+					Color: lsp.Color{Blue: 1, Alpha: 0.5},
+				},
+			},
+		},
+		{
+			name: "values that aren't colors are ignored",
+			template: `package main
+
+css invalid() {
+	color: #ff00f;
+	background: url(#abcde);
+	border-color: rgb(1, 2);
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(tt.template, "\n")
+			result, err := documentColors(lines)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := strings.Join(lines, "\n")
+			var actual []color
+			for _, c := range result {
+				from, to := indexOfPosition(lines, c.Range.Start), indexOfPosition(lines, c.Range.End)
+				actual = append(actual, color{Text: text[from:to], Range: c.Range, Color: c.Color})
+			}
+			if diff := cmp.Diff(tt.expected, actual, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestColorPresentations(t *testing.T) {
+	r := lsp.Range{
+		Start: lsp.Position{Line: 3, Character: 8},
+		End:   lsp.Position{Line: 3, Character: 15},
+	}
+	tests := []struct {
+		name     string
+		color    lsp.Color
+		expected []string
+	}{
+		{
+			name:     "opaque colors",
+			color:    lsp.Color{Red: 1, Green: 0, Blue: 68.0 / 255, Alpha: 1},
+			expected: []string{"#ff0044", "rgb(255, 0, 68)", "hsl(344, 100%, 50%)"},
+		},
+		{
+			name:     "transparent colors",
+			color:    lsp.Color{Red: 0, Green: 0, Blue: 1, Alpha: 0.5},
+			expected: []string{"#0000ff80", "rgba(0, 0, 255, 0.5)", "hsla(240, 100%, 50%, 0.5)"},
+		},
+		{
+			name:     "greys have no hue",
+			color:    lsp.Color{Red: 0.5, Green: 0.5, Blue: 0.5, Alpha: 1},
+			expected: []string{"#808080", "rgb(128, 128, 128)", "hsl(0, 0%, 50%)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual []string
+			for _, p := range colorPresentations(tt.color, r) {
+				if p.TextEdit == nil || p.TextEdit.Range != r || p.TextEdit.NewText != p.Label {
+					t.Errorf("expected %q to replace the range, got %#v", p.Label, p.TextEdit)
+				}
+				actual = append(actual, p.Label)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package proxy
+
+import (
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+)
+
+// templCommandPrefix namespaces the gopls commands that are offered by templ. Editors
+// register the commands of each language server globally, so if the gopls commands were
+// offered as-is, they'd clash with the commands of the gopls instance that the editor runs
+// for Go files.
+const templCommandPrefix = "templ."
+
+func templCommands(goplsCommands []string) (commands []string) {
+	commands = make([]string, len(goplsCommands))
+	for i, c := range goplsCommands {
+		commands[i] = templCommandPrefix + c
+	}
+	return commands
+}
+
+// namespaceCommand rewrites a command returned by gopls, e.g. within a code lens, so that
+// the editor executes it via templ.
+func namespaceCommand(c *lsp.Command) {
+	if c == nil || strings.HasPrefix(c.Command, templCommandPrefix) {
+		return
+	}
+	c.Command = templCommandPrefix + c.Command
+}
+
+// goplsCommand returns the name of the gopls command that a templ command wraps.
+func goplsCommand(command string) string {
+	return strings.TrimPrefix(command, templCommandPrefix)
+}
+
+// enableProvider enables a capability of the server, while keeping any options set by
+// gopls.
+func enableProvider(goplsProvider interface{}) interface{} {
+	if goplsProvider == nil || goplsProvider == false {
+		return true
+	}
+	return goplsProvider
+}
@@ -0,0 +1,91 @@
+package proxy
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/a-h/templ/cmd/templ/lint"
+	"github.com/a-h/templ/parser/v2"
+	"go.uber.org/zap"
+)
+
+// configWatcher is the glob pattern of the templ.json files that the client is asked to
+// watch, so that changes to them are picked up without restarting the server.
+var configWatcher = lsp.FileSystemWatcher{GlobPattern: "**/" + config.FileName}
+
+// watchedFilesDynamicRegistration returns true if the client supports dynamic registration
+// of file watchers.
+func (p *Server) watchedFilesDynamicRegistration() bool {
+	ws := p.ClientCapabilities.Workspace
+	if ws == nil || ws.DidChangeWatchedFiles == nil {
+		return false
+	}
+	return ws.DidChangeWatchedFiles.DynamicRegistration
+}
+
+// isConfigFile returns true if the URI is a templ.json file.
+func isConfigFile(u lsp.DocumentURI) bool {
+	return path.Base(string(u)) == config.FileName
+}
+
+// lintConfig returns the lint settings in the templ.json file that applies to the templ file.
+func (p *Server) lintConfig(templURI lsp.DocumentURI) config.Lint {
+	fileName, ok := fileNameOf(templURI)
+	if !ok {
+		return config.Lint{}
+	}
+	c, _ := p.URIs.Config(filepath.Dir(fileName))
+	return c.Lint
+}
+
+// checkConfig shows the problems with the templ.json file that applies to the directory.
+func (p *Server) checkConfig(ctx context.Context, dir string) {
+	c, err := p.URIs.Config(dir)
+	messages := c.Warnings
+	if err != nil {
+		messages = append(messages, err.Error())
+	}
+	for _, msg := range messages {
+		p.Log.Warn("templ.json", zap.String("message", msg))
+		err = p.Client.ShowMessage(ctx, &lsp.ShowMessageParams{
+			Type:    lsp.MessageTypeWarning,
+			Message: msg,
+		})
+		if err != nil {
+			p.Log.Error("failed to show templ.json warning", zap.Error(err))
+		}
+	}
+}
+
+// reloadConfig picks up the changes to a templ.json file, and updates the lint warnings of
+// the open templ files. Changes to the output settings apply to templ files opened after
+// the change.
+func (p *Server) reloadConfig(ctx context.Context, configURI lsp.DocumentURI) {
+	p.Log.Info("reloading templ.json", zap.String("uri", string(configURI)))
+	p.URIs.Reload()
+	if fileName, ok := fileNameOf(configURI); ok {
+		p.checkConfig(ctx, filepath.Dir(fileName))
+	}
+	for _, templURI := range p.TemplSource.URIs() {
+		d, ok := p.TemplSource.Get(templURI)
+		if !ok {
+			continue
+		}
+		if _, _, err := p.parseTemplate(ctx, lsp.DocumentURI(templURI), d.String()); err != nil {
+			p.Log.Error("failed to update diagnostics after templ.json change", zap.Error(err))
+		}
+	}
+}
+
+// validate returns the issues found in the template by the lint rules selected in templ.json.
+// Unknown rules are reported by checkConfig, so they're ignored here. A new linter is used
+// each time, because the other files of the package may have changed.
+func (p *Server) validate(templURI lsp.DocumentURI, template parser.TemplateFile) []parser.Issue {
+	c := p.lintConfig(templURI)
+	rules, _ := lint.Select(c.Enable, c.Disable)
+	fileName, _ := fileNameOf(templURI)
+	return lint.New(rules).Lint(fileName, template)
+}
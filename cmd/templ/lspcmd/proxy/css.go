@@ -0,0 +1,156 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/lspcmd/cssdata"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// isWithinCSSTemplate returns true if the line is within the body of a css template.
+//
+// While the user is typing, the document often fails to parse, so if it can't be
+// parsed, the line being edited is blanked out before trying again.
+func isWithinCSSTemplate(lines []string, line uint32) bool {
+	if int(line) >= len(lines) {
+		return false
+	}
+	tf, err := parseString(strings.Join(lines, "\n"))
+	if err != nil {
+		edited := make([]string, len(lines))
+		copy(edited, lines)
+		edited[line] = ""
+		if tf, err = parseString(strings.Join(edited, "\n")); err != nil {
+			return false
+		}
+	}
+	for i, n := range tf.Nodes {
+		css, isCSS := n.(parser.CSSTemplate)
+		if !isCSS {
+			continue
+		}
+		start := css.Name.Range.From.Line
+		if line <= start {
+			continue
+		}
+		// The template ends at the closing brace before the next node.
+		end := uint32(len(lines))
+		if i+1 < len(tf.Nodes) {
+			end = templateFileNodeStartLine(tf.Nodes[i+1])
+		}
+		for end > start && strings.TrimSpace(lines[end-1]) != "}" {
+			end--
+		}
+		if end > start && line < end-1 {
+			return true
+		}
+	}
+	return false
+}
+
+func templateFileNodeStartLine(n parser.TemplateFileNode) uint32 {
+	switch n := n.(type) {
+	case parser.HTMLTemplate:
+		return n.Expression.Range.From.Line
+	case parser.CSSTemplate:
+		return n.Name.Range.From.Line
+	case parser.ScriptTemplate:
+		return n.Name.Range.From.Line
+	case parser.GoExpression:
+		return n.Expression.Range.From.Line
+	}
+	return 0
+}
+
+func isCSSPropertyNameChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '-'
+}
+
+// cssCompletion returns property name or value completions for the position. If the
+// position is outside of a css template, or within a Go expression, ok is false.
+func cssCompletion(lines []string, pos lsp.Position) (items []lsp.CompletionItem, ok bool) {
+	if !isWithinCSSTemplate(lines, pos.Line) {
+		return nil, false
+	}
+	line := lines[pos.Line]
+	if int(pos.Character) > len(line) {
+		pos.Character = uint32(len(line))
+	}
+	prefix := line[:pos.Character]
+	// Go expressions are completed by gopls.
+	if strings.Count(prefix, "{") > strings.Count(prefix, "}") {
+		return nil, false
+	}
+	declaration := strings.TrimLeft(prefix, " \t")
+	name, value, isValue := strings.Cut(declaration, ":")
+	if isValue {
+		value = strings.TrimLeft(value, " \t")
+		if strings.IndexFunc(value, func(r rune) bool { return !isCSSPropertyNameChar(r) && (r < '0' || r > '9') }) >= 0 {
+			return []lsp.CompletionItem{}, true
+		}
+		editRange := lsp.Range{
+			Start: lsp.Position{Line: pos.Line, Character: pos.Character - uint32(len(value))},
+			End:   pos,
+		}
+		for _, v := range cssdata.Values(strings.TrimSpace(name)) {
+			if !strings.HasPrefix(v, value) {
+				continue
+			}
+			items = append(items, lsp.CompletionItem{
+				Label:    v,
+				Kind:     lsp.CompletionItemKindValue,
+				TextEdit: &lsp.TextEdit{Range: editRange, NewText: v},
+			})
+		}
+		return items, true
+	}
+	if strings.IndexFunc(name, func(r rune) bool { return !isCSSPropertyNameChar(r) }) >= 0 {
+		return []lsp.CompletionItem{}, true
+	}
+	editRange := lsp.Range{
+		Start: lsp.Position{Line: pos.Line, Character: pos.Character - uint32(len(name))},
+		End:   pos,
+	}
+	for _, p := range cssdata.Properties() {
+		if !strings.HasPrefix(p.Name, name) {
+			continue
+		}
+		items = append(items, lsp.CompletionItem{
+			Label:         p.Name,
+			Kind:          lsp.CompletionItemKindProperty,
+			Documentation: p.Description,
+			TextEdit:      &lsp.TextEdit{Range: editRange, NewText: p.Name + ": "},
+		})
+	}
+	return items, true
+}
+
+// cssHover returns the description of the CSS property name at the position.
+func cssHover(lines []string, pos lsp.Position) (result *lsp.Hover, ok bool) {
+	if !isWithinCSSTemplate(lines, pos.Line) {
+		return nil, false
+	}
+	line := lines[pos.Line]
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	name, _, found := strings.Cut(line[indent:], ":")
+	name = strings.TrimRight(name, " \t")
+	if !found || int(pos.Character) < indent || int(pos.Character) > indent+len(name) {
+		return nil, false
+	}
+	p, ok := cssdata.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return &lsp.Hover{
+		Contents: lsp.MarkupContent{
+			Kind:  lsp.Markdown,
+			Value: fmt.Sprintf("**%s**\n\n%s", p.Name, p.Description),
+		},
+		Range: &lsp.Range{
+			Start: lsp.Position{Line: pos.Line, Character: uint32(indent)},
+			End:   lsp.Position{Line: pos.Line, Character: uint32(indent + len(name))},
+		},
+	}, true
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+)
+
+const cssTestTemplate = `package main
+
+css red() {
+	color: red;
+	display: { value };
+}
+
+templ page() {
+	<div>disp</div>
+}
+`
+
+const cssTestTemplateWithPartialProperty = `package main
+
+css red() {
+	color: red;
+	disp
+}
+`
+
+func TestCSSCompletion(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      string
+		pos           lsp.Position
+		expectedOK    bool
+		expectedLabel string
+	}{
+		{
+			name:          "property names are completed",
+			template:      cssTestTemplateWithPartialProperty,
+			pos:           lsp.Position{Line: 4, Character: 5},
+			expectedOK:    true,
+			expectedLabel: "display",
+		},
+		{
+			name:          "property values are completed",
+			template:      cssTestTemplate,
+			pos:           lsp.Position{Line: 3, Character: 10},
+			expectedOK:    true,
+			expectedLabel: "revert",
+		},
+		{
+			name:       "Go expressions are not completed",
+			template:   cssTestTemplate,
+			pos:        lsp.Position{Line: 4, Character: 14},
+			expectedOK: false,
+		},
+		{
+			name:       "the css template declaration is not completed",
+			template:   cssTestTemplate,
+			pos:        lsp.Position{Line: 2, Character: 2},
+			expectedOK: false,
+		},
+		{
+			name:       "templ templates are not completed",
+			template:   cssTestTemplate,
+			pos:        lsp.Position{Line: 8, Character: 10},
+			expectedOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			items, ok := cssCompletion(strings.Split(tt.template, "\n"), tt.pos)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if tt.expectedLabel == "" {
+				return
+			}
+			for _, item := range items {
+				if item.Label == tt.expectedLabel {
+					return
+				}
+			}
+			t.Errorf("expected %q to be in the completion list, got %v", tt.expectedLabel, items)
+		})
+	}
+}
+
+func TestCSSHover(t *testing.T) {
+	lines := strings.Split(cssTestTemplate, "\n")
+	result, ok := cssHover(lines, lsp.Position{Line: 3, Character: 3})
+	if !ok {
+		t.Fatal("expected hover information for the color property")
+	}
+	if !strings.Contains(result.Contents.Value, "**color**") {
+		t.Errorf("unexpected hover contents: %q", result.Contents.Value)
+	}
+	if _, ok = cssHover(lines, lsp.Position{Line: 3, Character: 10}); ok {
+		t.Error("expected no hover information for a property value")
+	}
+}
@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/diagnostic"
+	"github.com/a-h/templ/cmd/templ/lintcmd"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.lsp.dev/uri"
+	"go.uber.org/zap"
+	"golang.org/x/tools/txtar"
+)
+
+// TestDiagnosticsAreTheSameInEachConsumer checks that parser.Diagnose, templ lint -format json
+// and the language server report the same ranges, severities, codes and messages for each of
+// the templates in testdata/diagnostics.txtar.
+func TestDiagnosticsAreTheSameInEachConsumer(t *testing.T) {
+	archive, err := txtar.ParseFile("testdata/diagnostics.txtar")
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	if len(archive.Files) == 0 {
+		t.Fatal("expected to find fixtures")
+	}
+	for _, f := range archive.Files {
+		name, src := f.Name, string(f.Data)
+		t.Run(name, func(t *testing.T) {
+			// Some of the templates can't be parsed, so they're written to a temporary directory,
+			// rather than kept in the repository, where templ generate would fail on them.
+			fileName := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(fileName, []byte(src), 0644); err != nil {
+				t.Fatalf("failed to write fixture: %v", err)
+			}
+
+			var expected []diagnostic.Diagnostic
+			for _, d := range parser.Diagnose(src, fileName, parser.WithValidation()) {
+				expected = append(expected, diagnostic.FromParser(d))
+			}
+			if len(expected) < 2 {
+				t.Fatalf("expected the fixture to have several diagnostics, got %v", expected)
+			}
+			sortDiagnostics(expected)
+
+			t.Run("templ lint -format json", func(t *testing.T) {
+				actual := lintJSONDiagnostics(t, fileName)
+				if diff := cmp.Diff(expected, actual); diff != "" {
+					t.Error(diff)
+				}
+			})
+			t.Run("language server", func(t *testing.T) {
+				actual := languageServerDiagnostics(t, fileName, src)
+				if diff := cmp.Diff(expected, actual); diff != "" {
+					t.Error(diff)
+				}
+			})
+		})
+	}
+}
+
+func lintJSONDiagnostics(t *testing.T, fileName string) (diagnostics []diagnostic.Diagnostic) {
+	t.Helper()
+	var w bytes.Buffer
+	err := lintcmd.Run(&w, lintcmd.Arguments{Paths: []string{fileName}, Format: lintcmd.FormatJSON})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	dec := json.NewDecoder(&w)
+	for {
+		var d diagnostic.Diagnostic
+		if err := dec.Decode(&d); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("failed to decode output: %v", err)
+			}
+			break
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+func languageServerDiagnostics(t *testing.T, fileName, src string) (diagnostics []diagnostic.Diagnostic) {
+	t.Helper()
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(client)
+	_, _, _ = s.parseTemplate(context.Background(), uri.File(fileName), src)
+	if len(client.diagnostics) != 1 {
+		t.Fatalf("expected diagnostics to be published once, got %d", len(client.diagnostics))
+	}
+	severities := map[lsp.DiagnosticSeverity]parser.Severity{
+		lsp.DiagnosticSeverityError:   parser.SeverityError,
+		lsp.DiagnosticSeverityWarning: parser.SeverityWarning,
+		lsp.DiagnosticSeverityHint:    parser.SeverityHint,
+	}
+	lines := strings.Split(src, "\n")
+	for _, d := range client.diagnostics[0].Diagnostics {
+		code, _ := d.Code.(string)
+		r := parser.Range{
+			From: parser.Position{Line: d.Range.Start.Line, Col: parser.ByteColFromUTF16(lines[d.Range.Start.Line], d.Range.Start.Character)},
+			To:   parser.Position{Line: d.Range.End.Line, Col: parser.ByteColFromUTF16(lines[d.Range.End.Line], d.Range.End.Character)},
+		}
+		diagnostics = append(diagnostics, diagnostic.FromRange(fileName, r, severities[d.Severity].String(), code, d.Message))
+	}
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+func sortDiagnostics(diagnostics []diagnostic.Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		if diagnostics[i].Col != diagnostics[j].Col {
+			return diagnostics[i].Col < diagnostics[j].Col
+		}
+		return diagnostics[i].Code < diagnostics[j].Code
+	})
+}
@@ -0,0 +1,110 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lsp "github.com/a-h/protocol"
+	"go.uber.org/zap"
+)
+
+// DefaultDiagnosticsWindow is the time that diagnostics for a document are held for,
+// so that rapid updates are coalesced.
+const DefaultDiagnosticsWindow = time.Millisecond * 75
+
+// DiagnosticsClient sends messages to the editor, but coalesces the diagnostics for each
+// document that are published within a time window, so that only the final state is sent.
+//
+// Without it, a burst of edits results in the editor receiving a stream of alternating
+// error and clear notifications, and the diagnostics flicker.
+type DiagnosticsClient struct {
+	lsp.Client
+	Log    *zap.Logger
+	Window time.Duration
+
+	m       sync.Mutex
+	pending map[lsp.DocumentURI]*lsp.PublishDiagnosticsParams
+	timers  map[lsp.DocumentURI]*time.Timer
+	// lint diagnostics are added to every publish for the document, because gopls
+	// replaces the diagnostics of the document each time it publishes.
+	lint map[lsp.DocumentURI][]lsp.Diagnostic
+	// sendMutex ensures that diagnostics for a document are sent in order.
+	sendMutex sync.Mutex
+}
+
+// NewDiagnosticsClient creates a client that coalesces the diagnostics sent to the target.
+func NewDiagnosticsClient(log *zap.Logger, target lsp.Client, window time.Duration) *DiagnosticsClient {
+	return &DiagnosticsClient{
+		Client:  target,
+		Log:     log,
+		Window:  window,
+		pending: make(map[lsp.DocumentURI]*lsp.PublishDiagnosticsParams),
+		timers:  make(map[lsp.DocumentURI]*time.Timer),
+		lint:    make(map[lsp.DocumentURI][]lsp.Diagnostic),
+	}
+}
+
+// PublishDiagnostics schedules the diagnostics to be sent at the end of the window,
+// replacing any that are already waiting to be sent for the document.
+func (dc *DiagnosticsClient) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagnosticsParams) (err error) {
+	dc.m.Lock()
+	defer dc.m.Unlock()
+	dc.pending[params.URI] = params
+	if _, scheduled := dc.timers[params.URI]; !scheduled {
+		uri := params.URI
+		dc.timers[uri] = time.AfterFunc(dc.Window, func() {
+			if err := dc.FlushDiagnostics(context.Background(), uri); err != nil {
+				dc.Log.Error("failed to publish diagnostics", zap.String("uri", string(uri)), zap.Error(err))
+			}
+		})
+	}
+	return nil
+}
+
+// FlushDiagnostics sends any diagnostics waiting to be sent for the document immediately.
+func (dc *DiagnosticsClient) FlushDiagnostics(ctx context.Context, uri lsp.DocumentURI) (err error) {
+	dc.sendMutex.Lock()
+	defer dc.sendMutex.Unlock()
+	dc.m.Lock()
+	params, ok := dc.pending[uri]
+	delete(dc.pending, uri)
+	if t, scheduled := dc.timers[uri]; scheduled {
+		t.Stop()
+		delete(dc.timers, uri)
+	}
+	lint := dc.lint[uri]
+	dc.m.Unlock()
+	if !ok {
+		return nil
+	}
+	if len(lint) > 0 {
+		merged := *params
+		merged.Diagnostics = append(append([]lsp.Diagnostic{}, params.Diagnostics...), lint...)
+		params = &merged
+	}
+	return dc.Client.PublishDiagnostics(ctx, params)
+}
+
+// SetLintDiagnostics sets the lint diagnostics for the document, which are sent with the
+// next, and all subsequent, diagnostics published for it.
+func (dc *DiagnosticsClient) SetLintDiagnostics(uri lsp.DocumentURI, diagnostics []lsp.Diagnostic) {
+	dc.m.Lock()
+	defer dc.m.Unlock()
+	if len(diagnostics) == 0 {
+		delete(dc.lint, uri)
+		return
+	}
+	dc.lint[uri] = diagnostics
+}
+
+// diagnosticsFlusher is implemented by clients that delay sending diagnostics.
+type diagnosticsFlusher interface {
+	FlushDiagnostics(ctx context.Context, uri lsp.DocumentURI) error
+}
+
+// lintDiagnosticsSetter is implemented by clients that merge lint diagnostics with the
+// diagnostics published by gopls.
+type lintDiagnosticsSetter interface {
+	SetLintDiagnostics(uri lsp.DocumentURI, diagnostics []lsp.Diagnostic)
+}
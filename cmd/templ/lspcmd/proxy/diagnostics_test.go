@@ -0,0 +1,157 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+type recordingClient struct {
+	lsp.Client
+	m         sync.Mutex
+	published []*lsp.PublishDiagnosticsParams
+}
+
+func (c *recordingClient) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagnosticsParams) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.published = append(c.published, params)
+	return nil
+}
+
+func (c *recordingClient) get() []*lsp.PublishDiagnosticsParams {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]*lsp.PublishDiagnosticsParams{}, c.published...)
+}
+
+func TestDiagnosticsAreCoalesced(t *testing.T) {
+	target := &recordingClient{}
+	dc := NewDiagnosticsClient(zap.NewNop(), target, time.Millisecond*50)
+	uri := lsp.DocumentURI("file:///a.templ")
+	for i := 0; i < 50; i++ {
+		params := &lsp.PublishDiagnosticsParams{URI: uri, Diagnostics: []lsp.Diagnostic{}}
+		if i%2 == 0 {
+			params.Diagnostics = append(params.Diagnostics, lsp.Diagnostic{Message: "error"})
+		}
+		if err := dc.PublishDiagnostics(context.Background(), params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The final update clears the diagnostics.
+	deadline := time.Now().Add(time.Second * 5)
+	for len(target.get()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+	time.Sleep(time.Millisecond * 100)
+	published := target.get()
+	if len(published) == 0 || len(published) > 5 {
+		t.Fatalf("expected between 1 and 5 notifications, got %d", len(published))
+	}
+	if last := published[len(published)-1]; len(last.Diagnostics) != 0 {
+		t.Errorf("expected the final state to have no diagnostics, got %d", len(last.Diagnostics))
+	}
+}
+
+func TestDiagnosticsAreFlushedOnSave(t *testing.T) {
+	target := &recordingClient{}
+	dc := NewDiagnosticsClient(zap.NewNop(), target, time.Hour)
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(dc)
+	uri := lsp.DocumentURI("file:///a.templ")
+	err := dc.PublishDiagnostics(context.Background(), &lsp.PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: []lsp.Diagnostic{{Message: "error"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(target.get()) != 0 {
+		t.Fatal("expected diagnostics to be delayed")
+	}
+	if err = s.DidSave(context.Background(), &lsp.DidSaveTextDocumentParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published := target.get(); len(published) != 1 {
+		t.Errorf("expected diagnostics to be sent on save, got %d notifications", len(published))
+	}
+}
+
+func TestLintDiagnosticsAreKeptWhenGoplsPublishes(t *testing.T) {
+	target := &recordingClient{}
+	dc := NewDiagnosticsClient(zap.NewNop(), target, time.Hour)
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(dc)
+	ctx := context.Background()
+	uri := lsp.DocumentURI("file:///a.templ")
+	if _, ok, err := s.parseTemplate(ctx, uri, "package main\n\ntempl A() {\n\t<img src=\"a.png\"/>\n}\n"); !ok || err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	// gopls replaces the diagnostics of the document when it publishes.
+	err := dc.PublishDiagnostics(ctx, &lsp.PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: []lsp.Diagnostic{{Source: "compiler", Message: "undefined: x"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = dc.FlushDiagnostics(ctx, uri); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	published := target.get()
+	if len(published) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(published))
+	}
+	var sources []string
+	for _, d := range published[0].Diagnostics {
+		sources = append(sources, d.Source)
+	}
+	if diff := cmp.Diff([]string{"compiler", "templ-lint"}, sources); diff != "" {
+		t.Error(diff)
+	}
+
+	// A parse error replaces the lint diagnostics, because the template can't be checked.
+	if _, ok, _ := s.parseTemplate(ctx, uri, "package main\n\ntempl A() {\n\t<a></b>\n}\n"); ok {
+		t.Fatal("expected a parse error")
+	}
+	if err = dc.FlushDiagnostics(ctx, uri); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	published = target.get()
+	last := published[len(published)-1]
+	if len(last.Diagnostics) != 1 || last.Diagnostics[0].Source != "templ" {
+		t.Errorf("expected only the parse error, got %v", last.Diagnostics)
+	}
+}
+
+func TestDeadCodeIsAFadedOutHint(t *testing.T) {
+	s, _ := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	templateText := "package main\n\ntempl A() {\n\tif false {\n\t\t<p>Never</p>\n\t}\n}\n"
+	tf, err := parser.ParseString(templateText)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	diagnostics := lintDiagnostics(templateText, s.validate(lsp.DocumentURI("file:///a.templ"), tf))
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diagnostics)
+	}
+	d := diagnostics[0]
+	if d.Code != "dead-code" || d.Severity != lsp.DiagnosticSeverityHint {
+		t.Errorf("expected a dead-code hint, got %v", d)
+	}
+	if diff := cmp.Diff([]lsp.DiagnosticTag{lsp.DiagnosticTagUnnecessary}, d.Tags); diff != "" {
+		t.Error(diff)
+	}
+	expectedRange := lsp.Range{Start: lsp.Position{Line: 4}, End: lsp.Position{Line: 5, Character: 1}}
+	if diff := cmp.Diff(expectedRange, d.Range); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -14,28 +14,79 @@ func newDocumentContents(log *zap.Logger) *DocumentContents {
 	return &DocumentContents{
 		m:             new(sync.Mutex),
 		uriToContents: make(map[string]*Document),
+		uriToVersion:  make(map[string]int32),
+		uriToUnsaved:  make(map[string]bool),
 		log:           log,
 	}
 }
 
+// DocumentContents is the contents of the documents that are open in the editor. It's safe
+// for concurrent use, because the documents that it returns are copies.
 type DocumentContents struct {
 	m             *sync.Mutex
 	uriToContents map[string]*Document
-	log           *zap.Logger
+	uriToVersion  map[string]int32
+	// uriToUnsaved is true when changes have been applied to the document since it was
+	// opened or saved, so the editor has edits that aren't in the file on disk.
+	uriToUnsaved map[string]bool
+	log          *zap.Logger
 }
 
-// Set the contents of a document.
-func (dc *DocumentContents) Set(uri string, d *Document) {
+// VersionError is returned by Apply when the changes from the client are for a version of
+// the document that isn't later than the cached version, so they can't be applied to it.
+type VersionError struct {
+	// Current is the version of the cached document.
+	Current int32
+	// Received is the version that the changes are for.
+	Received int32
+}
+
+func (e VersionError) Error() string {
+	return fmt.Sprintf("changes for version %d can't be applied to version %d of the document", e.Received, e.Current)
+}
+
+// Set the contents of a document, and the version of the document in the client.
+func (dc *DocumentContents) Set(uri string, version int32, d *Document) {
 	dc.m.Lock()
 	defer dc.m.Unlock()
 	dc.uriToContents[uri] = d
+	dc.uriToVersion[uri] = version
+	dc.uriToUnsaved[uri] = false
+}
+
+// Saved records that the document has been saved, so it's the same as the file on disk.
+func (dc *DocumentContents) Saved(uri string) {
+	dc.m.Lock()
+	defer dc.m.Unlock()
+	if _, ok := dc.uriToContents[uri]; ok {
+		dc.uriToUnsaved[uri] = false
+	}
+}
+
+// Unsaved returns true if changes have been applied to the document since it was opened
+// or saved.
+func (dc *DocumentContents) Unsaved(uri string) bool {
+	dc.m.Lock()
+	defer dc.m.Unlock()
+	return dc.uriToUnsaved[uri]
 }
 
-// Get the contents of a document.
+// Get a copy of the contents of a document.
 func (dc *DocumentContents) Get(uri string) (d *Document, ok bool) {
 	dc.m.Lock()
 	defer dc.m.Unlock()
 	d, ok = dc.uriToContents[uri]
+	if ok {
+		d = d.copy()
+	}
+	return
+}
+
+// Version returns the version of a document.
+func (dc *DocumentContents) Version(uri string) (version int32, ok bool) {
+	dc.m.Lock()
+	defer dc.m.Unlock()
+	version, ok = dc.uriToVersion[uri]
 	return
 }
 
@@ -44,6 +95,8 @@ func (dc *DocumentContents) Delete(uri string) {
 	dc.m.Lock()
 	defer dc.m.Unlock()
 	delete(dc.uriToContents, uri)
+	delete(dc.uriToVersion, uri)
+	delete(dc.uriToUnsaved, uri)
 }
 
 func (dc *DocumentContents) URIs() (uris []string) {
@@ -58,8 +111,14 @@ func (dc *DocumentContents) URIs() (uris []string) {
 	return uris
 }
 
-// Apply changes to the document from the client, and return a list of change requests to send back to the client.
-func (dc *DocumentContents) Apply(uri string, changes []lsp.TextDocumentContentChangeEvent) (d *Document, err error) {
+// Apply changes to the document from the client, and return a copy of the updated document.
+//
+// Changes to a range of the document are only applied if the version is later than the
+// version of the document, since changes that are repeated or out of order would leave the
+// document different to the one in the editor. A VersionError is returned instead. Versions
+// may skip numbers, e.g. Neovim uses the changedtick of the buffer. Changes that replace the
+// whole document are always applied.
+func (dc *DocumentContents) Apply(uri string, version int32, changes []lsp.TextDocumentContentChangeEvent) (d *Document, err error) {
 	dc.m.Lock()
 	defer dc.m.Unlock()
 	var ok bool
@@ -68,10 +127,16 @@ func (dc *DocumentContents) Apply(uri string, changes []lsp.TextDocumentContentC
 		err = fmt.Errorf("document not found")
 		return
 	}
+	current := dc.uriToVersion[uri]
+	if version <= current && !(len(changes) > 0 && changes[0].Range == nil) {
+		return nil, VersionError{Current: current, Received: version}
+	}
 	for _, change := range changes {
 		d.Apply(change.Range, change.Text)
 	}
-	return
+	dc.uriToVersion[uri] = version
+	dc.uriToUnsaved[uri] = true
+	return d.copy(), nil
 }
 
 func NewDocument(log *zap.Logger, s string) *Document {
@@ -86,6 +151,15 @@ type Document struct {
 	Lines []string
 }
 
+func (d *Document) copy() *Document {
+	lines := make([]string, len(d.Lines))
+	copy(lines, d.Lines)
+	return &Document{
+		Log:   d.Log,
+		Lines: lines,
+	}
+}
+
 func (d *Document) LineLengths() (lens []int) {
 	lens = make([]int, len(d.Lines))
 	for i, l := range d.Lines {
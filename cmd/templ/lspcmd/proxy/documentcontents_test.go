@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"errors"
 	"testing"
 
 	lsp "github.com/a-h/protocol"
@@ -568,3 +569,117 @@ d`,
 		})
 	}
 }
+
+func TestDocumentContentsVersions(t *testing.T) {
+	insert := func(text string) lsp.TextDocumentContentChangeEvent {
+		return lsp.TextDocumentContentChangeEvent{
+			Range: &lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 0},
+				End:   lsp.Position{Line: 0, Character: 0},
+			},
+			Text: text,
+		}
+	}
+	tests := []struct {
+		name            string
+		version         int32
+		change          lsp.TextDocumentContentChangeEvent
+		expected        string
+		expectedVersion int32
+		expectedErr     error
+	}{
+		{
+			name:            "changes for the next version are applied",
+			version:         3,
+			change:          insert("a"),
+			expected:        "ab",
+			expectedVersion: 3,
+		},
+		{
+			name:            "repeated changes are rejected",
+			version:         2,
+			change:          insert("a"),
+			expected:        "b",
+			expectedVersion: 2,
+			expectedErr:     VersionError{Current: 2, Received: 2},
+		},
+		{
+			name:            "changes for an earlier version are rejected",
+			version:         1,
+			change:          insert("a"),
+			expected:        "b",
+			expectedVersion: 2,
+			expectedErr:     VersionError{Current: 2, Received: 1},
+		},
+		{
+			name:            "changes that skip a version are applied",
+			version:         4,
+			change:          insert("a"),
+			expected:        "ab",
+			expectedVersion: 4,
+		},
+		{
+			name:            "changes to the whole document are applied to any version",
+			version:         1,
+			change:          lsp.TextDocumentContentChangeEvent{Text: "resynchronized"},
+			expected:        "resynchronized",
+			expectedVersion: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dc := newDocumentContents(zap.NewNop())
+			dc.Set("file:///a.templ", 2, NewDocument(zap.NewNop(), "b"))
+			_, err := dc.Apply("file:///a.templ", tt.version, []lsp.TextDocumentContentChangeEvent{tt.change})
+			var ve VersionError
+			if tt.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectedErr != nil && (!errors.As(err, &ve) || ve != tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			d, _ := dc.Get("file:///a.templ")
+			if diff := cmp.Diff(tt.expected, d.String()); diff != "" {
+				t.Error(diff)
+			}
+			if version, _ := dc.Version("file:///a.templ"); version != tt.expectedVersion {
+				t.Errorf("expected version %d, got %d", tt.expectedVersion, version)
+			}
+		})
+	}
+}
+
+func TestDocumentContentsUnsaved(t *testing.T) {
+	dc := newDocumentContents(zap.NewNop())
+	dc.Set("file:///a.templ", 1, NewDocument(zap.NewNop(), "b"))
+	if dc.Unsaved("file:///a.templ") {
+		t.Error("expected an opened document to have no unsaved edits")
+	}
+	if _, err := dc.Apply("file:///a.templ", 2, []lsp.TextDocumentContentChangeEvent{{Range: &lsp.Range{}, Text: "a"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !dc.Unsaved("file:///a.templ") {
+		t.Error("expected a changed document to have unsaved edits")
+	}
+	dc.Saved("file:///a.templ")
+	if dc.Unsaved("file:///a.templ") {
+		t.Error("expected a saved document to have no unsaved edits")
+	}
+}
+
+func TestDocumentContentsGetReturnsACopy(t *testing.T) {
+	dc := newDocumentContents(zap.NewNop())
+	dc.Set("file:///a.templ", 1, NewDocument(zap.NewNop(), "b"))
+	d, _ := dc.Get("file:///a.templ")
+	_, err := dc.Apply("file:///a.templ", 2, []lsp.TextDocumentContentChangeEvent{{
+		Range: &lsp.Range{},
+		Text:  "a",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.String() != "b" {
+		t.Errorf("expected the document to be unchanged by later changes, got %q", d.String())
+	}
+}
@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/lspcmd/htmldata"
+)
+
+// htmlHover returns documentation for the element or attribute name at the position.
+// If the position isn't on a known element or attribute name, ok is false.
+func htmlHover(lines []string, pos lsp.Position) (result *lsp.Hover, ok bool) {
+	text := strings.Join(lines, "\n")
+	tf, err := parseString(text)
+	if err != nil {
+		return nil, false
+	}
+	sc := &spanCollector{text: text}
+	sc.templateFile(tf)
+	index := indexOfPosition(lines, pos)
+	for _, n := range sc.names {
+		if !n.contains(index) {
+			continue
+		}
+		var value string
+		if n.isAttribute {
+			value, ok = attributeDocumentation(n.name)
+		} else {
+			value, ok = elementDocumentation(n.name)
+		}
+		if !ok {
+			return nil, false
+		}
+		return &lsp.Hover{
+			Contents: lsp.MarkupContent{
+				Kind:  lsp.Markdown,
+				Value: value,
+			},
+			Range: &lsp.Range{
+				Start: positionOfIndex(text, n.from),
+				End:   positionOfIndex(text, n.to),
+			},
+		}, true
+	}
+	return nil, false
+}
+
+func elementDocumentation(name string) (value string, ok bool) {
+	e, ok := htmldata.GetElement(strings.ToLower(name))
+	if !ok {
+		return
+	}
+	return fmt.Sprintf("**<%s>**\n\n%s", e.Name, e.Description), true
+}
+
+func attributeDocumentation(name string) (value string, ok bool) {
+	a, ok := htmldata.GetAttribute(strings.ToLower(name))
+	if !ok {
+		return
+	}
+	value = fmt.Sprintf("**%s**\n\n%s", a.Name, a.Description)
+	if len(a.Values) > 0 {
+		value += fmt.Sprintf("\n\nValues: `%s`", strings.Join(a.Values, "`, `"))
+	}
+	return value, true
+}
+
+// linkedEditingRanges returns the ranges of the opening and closing tag names of the
+// element at the position, so that editors can keep them in sync. Void and self-closing
+// elements have no closing tag, so ok is false.
+func linkedEditingRanges(lines []string, pos lsp.Position) (result *lsp.LinkedEditingRanges, ok bool) {
+	text := strings.Join(lines, "\n")
+	tf, err := parseString(text)
+	if err != nil {
+		return nil, false
+	}
+	sc := &spanCollector{text: text}
+	sc.templateFile(tf)
+	index := indexOfPosition(lines, pos)
+	for _, t := range sc.tags {
+		if !t.open.contains(index) && !t.close.contains(index) {
+			continue
+		}
+		return &lsp.LinkedEditingRanges{
+			Ranges: []lsp.Range{
+				{Start: positionOfIndex(text, t.open.from), End: positionOfIndex(text, t.open.to)},
+				{Start: positionOfIndex(text, t.close.from), End: positionOfIndex(text, t.close.to)},
+			},
+		}, true
+	}
+	return nil, false
+}
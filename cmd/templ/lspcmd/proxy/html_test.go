@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+)
+
+const htmlHoverTestTemplate = `package main
+
+templ Disclosure(open bool) {
+	<details open?={ open } aria-expanded="false">
+		<summary>{ "More" }</summary>
+	</details>
+}
+`
+
+func TestHTMLHover(t *testing.T) {
+	tests := []struct {
+		name             string
+		position         lsp.Position
+		expectedOK       bool
+		expectedContents string
+		expectedRange    lsp.Range
+	}{
+		{
+			name:             "element name",
+			position:         lsp.Position{Line: 3, Character: 3},
+			expectedOK:       true,
+			expectedContents: "**<details>**",
+			expectedRange:    lsp.Range{Start: lsp.Position{Line: 3, Character: 2}, End: lsp.Position{Line: 3, Character: 9}},
+		},
+		{
+			name:             "closing element name",
+			position:         lsp.Position{Line: 5, Character: 5},
+			expectedOK:       true,
+			expectedContents: "**<details>**",
+			expectedRange:    lsp.Range{Start: lsp.Position{Line: 5, Character: 3}, End: lsp.Position{Line: 5, Character: 10}},
+		},
+		{
+			name:             "attribute name with values",
+			position:         lsp.Position{Line: 3, Character: 28},
+			expectedOK:       true,
+			expectedContents: "Values: `true`, `false`, `undefined`",
+			expectedRange:    lsp.Range{Start: lsp.Position{Line: 3, Character: 25}, End: lsp.Position{Line: 3, Character: 38}},
+		},
+		{
+			name:       "Go expression",
+			position:   lsp.Position{Line: 3, Character: 19},
+			expectedOK: false,
+		},
+		{
+			name:       "text",
+			position:   lsp.Position{Line: 4, Character: 14},
+			expectedOK: false,
+		},
+	}
+	lines := strings.Split(htmlHoverTestTemplate, "\n")
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := htmlHover(lines, tt.position)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if !strings.Contains(result.Contents.Value, tt.expectedContents) {
+				t.Errorf("expected contents to contain %q, got %q", tt.expectedContents, result.Contents.Value)
+			}
+			if *result.Range != tt.expectedRange {
+				t.Errorf("expected range %v, got %v", tt.expectedRange, *result.Range)
+			}
+		})
+	}
+}
+
+func TestLinkedEditingRanges(t *testing.T) {
+	template := `package main
+
+templ Form() {
+	<form>
+		<input type="text"/>
+		<br/>
+		<img src="a.png"/>
+	</form>
+}
+`
+	lines := strings.Split(template, "\n")
+	expected := []lsp.Range{
+		{Start: lsp.Position{Line: 3, Character: 2}, End: lsp.Position{Line: 3, Character: 6}},
+		{Start: lsp.Position{Line: 7, Character: 3}, End: lsp.Position{Line: 7, Character: 7}},
+	}
+	tests := []struct {
+		name       string
+		position   lsp.Position
+		expectedOK bool
+	}{
+		{name: "opening tag", position: lsp.Position{Line: 3, Character: 4}, expectedOK: true},
+		{name: "closing tag", position: lsp.Position{Line: 7, Character: 7}, expectedOK: true},
+		{name: "self-closing element", position: lsp.Position{Line: 4, Character: 4}, expectedOK: false},
+		{name: "void element", position: lsp.Position{Line: 5, Character: 3}, expectedOK: false},
+		{name: "attribute", position: lsp.Position{Line: 6, Character: 7}, expectedOK: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := linkedEditingRanges(lines, tt.position)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(expected, result.Ranges); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -90,6 +90,27 @@ import (
 	"fmt"
 )
 
+templ example() {
+}
+`,
+		},
+		{
+			name: "aliased and blank single-line imports are recognised",
+			templContents: `package main
+
+import h "github.com/foo/html"
+import _ "embed"
+
+templ example() {
+}
+`,
+			packageName: "fmt",
+			expected: `package main
+
+import h "github.com/foo/html"
+import _ "embed"
+import "fmt"
+
 templ example() {
 }
 `,
@@ -0,0 +1,218 @@
+package proxy
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"golang.org/x/mod/modfile"
+)
+
+// importPath is an import path literal in the imports of a templ file that's being completed.
+type importPath struct {
+	// Prefix is the text of the path before the cursor, e.g. "github.com/a-h/" in
+	// "github.com/a-h/|templ".
+	Prefix string
+	// Range is the range of the prefix, which is replaced by the completion.
+	Range lsp.Range
+}
+
+// importPathAt returns the import path literal that contains the position, if the position is
+// within one of the import declarations that follow the package declaration. The literal may
+// not be terminated yet.
+func importPathAt(lines []string, pos lsp.Position) (ip importPath, ok bool) {
+	if int(pos.Line) >= len(lines) {
+		return ip, false
+	}
+	src := []byte(strings.Join(lines, "\n"))
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	// Unterminated literals are reported as errors, but are still scanned.
+	s.Init(file, src, func(token.Position, string) {}, 0)
+	offset := lineOffset(lines, pos.Line) + int(parser.ByteColFromUTF16(lines[pos.Line], pos.Character))
+
+	// The package clause.
+	for _, expected := range []token.Token{token.PACKAGE, token.IDENT, token.SEMICOLON} {
+		if _, tok, _ := s.Scan(); tok != expected {
+			return ip, false
+		}
+	}
+	// The import declarations, which end at the first token that isn't part of one.
+	var grouped bool
+	for {
+		p, tok, lit := s.Scan()
+		switch {
+		case tok == token.IMPORT && !grouped:
+			continue
+		case tok == token.LPAREN && !grouped:
+			grouped = true
+			continue
+		case tok == token.RPAREN && grouped:
+			grouped = false
+			continue
+		case tok == token.IDENT || tok == token.PERIOD || tok == token.SEMICOLON:
+			// Import names, e.g. the "." of a dot import, and the ends of the specs.
+			continue
+		case tok != token.STRING:
+			return ip, false
+		}
+		start := file.Offset(p)
+		if offset <= start {
+			return ip, false
+		}
+		end := start + len(lit)
+		if terminated := len(lit) > 1 && lit[len(lit)-1] == lit[0]; terminated {
+			end--
+		}
+		if offset > end {
+			continue
+		}
+		startPos := file.Position(p)
+		ip.Prefix = string(src[start+1 : offset])
+		ip.Range = lsp.Range{
+			Start: lsp.Position{Line: pos.Line, Character: parser.UTF16Col(lines[pos.Line], uint32(startPos.Column))},
+			End:   pos,
+		}
+		return ip, startPos.Line-1 == int(pos.Line)
+	}
+}
+
+// lineOffset returns the byte offset of the start of the line.
+func lineOffset(lines []string, line uint32) (offset int) {
+	for _, l := range lines[:line] {
+		offset += len(l) + 1
+	}
+	return offset
+}
+
+// modulePackages returns the import paths of the packages in the Go module that contains the
+// directory, which start with the prefix. The package in the directory isn't included,
+// because it can't import itself, and nor are internal packages that it can't import.
+// Nested modules, and testdata, vendor and hidden directories are skipped.
+func modulePackages(dir, prefix string) (paths []string, err error) {
+	root, modulePath, err := findModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if p != root {
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || p == dir || !isPackageDir(p) {
+			return nil
+		}
+		importPath := path.Join(modulePath, filepath.ToSlash(rel))
+		if !strings.HasPrefix(importPath, prefix) || !canImportInternal(root, dir, rel) {
+			return nil
+		}
+		paths = append(paths, importPath)
+		return nil
+	})
+	return paths, err
+}
+
+// findModule returns the directory that contains the go.mod file of the module that contains
+// the directory, and the path of the module.
+func findModule(dir string) (root, modulePath string, err error) {
+	for d := dir; ; {
+		data, err := os.ReadFile(filepath.Join(d, "go.mod"))
+		if err == nil {
+			if modulePath = modfile.ModulePath(data); modulePath == "" {
+				return "", "", fmt.Errorf("%s: missing module path", filepath.Join(d, "go.mod"))
+			}
+			return d, modulePath, nil
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return "", "", fmt.Errorf("no go.mod file found in %s or its parents", dir)
+		}
+		d = parent
+	}
+}
+
+// isPackageDir returns true if the directory contains Go or templ files, other than tests.
+func isPackageDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ext := filepath.Ext(name); ext == ".go" || ext == ".templ" {
+			return true
+		}
+	}
+	return false
+}
+
+// canImportInternal returns true if the package at rel, relative to the module root, isn't
+// internal, or if dir is within the parent of its internal directory.
+func canImportInternal(root, dir, rel string) bool {
+	elems := strings.Split(filepath.ToSlash(rel), "/")
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i] != "internal" {
+			continue
+		}
+		parent := filepath.Join(root, filepath.FromSlash(strings.Join(elems[:i], "/")))
+		return dir == parent || strings.HasPrefix(dir, parent+string(filepath.Separator))
+	}
+	return true
+}
+
+// withModulePackages adds the packages of the module to the completion items returned by gopls,
+// so that they're listed first. gopls items for the same packages are removed.
+func withModulePackages(result *lsp.CompletionList, ip importPath, packages []string) *lsp.CompletionList {
+	if len(packages) == 0 {
+		return result
+	}
+	if result == nil {
+		result = &lsp.CompletionList{}
+	}
+	items := make([]lsp.CompletionItem, 0, len(packages)+len(result.Items))
+	isModulePackage := make(map[string]bool, len(packages))
+	for i, p := range packages {
+		isModulePackage[p] = true
+		items = append(items, lsp.CompletionItem{
+			Label:      p,
+			Kind:       lsp.CompletionItemKindModule,
+			Detail:     "package in this module",
+			SortText:   fmt.Sprintf("0%05d", i),
+			FilterText: p,
+			TextEdit:   &lsp.TextEdit{Range: ip.Range, NewText: p},
+		})
+	}
+	for _, item := range result.Items {
+		if isModulePackage[item.Label] {
+			continue
+		}
+		sortText := item.SortText
+		if sortText == "" {
+			sortText = item.Label
+		}
+		item.SortText = "1" + sortText
+		items = append(items, item)
+	}
+	result.Items = items
+	return result
+}
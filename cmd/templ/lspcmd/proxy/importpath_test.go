@@ -0,0 +1,214 @@
+package proxy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+	"go.lsp.dev/uri"
+	"go.uber.org/zap"
+)
+
+func TestImportPathAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected importPath
+		ok       bool
+	}{
+		{
+			name:     "single imports",
+			template: "package main\n\nimport \"fm|\"\n",
+			expected: importPath{
+				Prefix: "fm",
+				Range:  lsp.Range{Start: lsp.Position{Line: 2, Character: 8}, End: lsp.Position{Line: 2, Character: 10}},
+			},
+			ok: true,
+		},
+		{
+			name:     "grouped imports with names",
+			template: "package main\n\nimport (\n\t\"fmt\"\n\tx \"github.com/|a-h/templ\"\n)\n",
+			expected: importPath{
+				Prefix: "github.com/",
+				Range:  lsp.Range{Start: lsp.Position{Line: 4, Character: 4}, End: lsp.Position{Line: 4, Character: 15}},
+			},
+			ok: true,
+		},
+		{
+			name:     "unterminated paths",
+			template: "package main\n\nimport (\n\t\"github.com/a-|\n)\n\ntempl page() {\n}\n",
+			expected: importPath{
+				Prefix: "github.com/a-",
+				Range:  lsp.Range{Start: lsp.Position{Line: 3, Character: 2}, End: lsp.Position{Line: 3, Character: 15}},
+			},
+			ok: true,
+		},
+		{
+			name:     "empty paths",
+			template: "package main\n\nimport \"|\"\n",
+			expected: importPath{
+				Range: lsp.Range{Start: lsp.Position{Line: 2, Character: 8}, End: lsp.Position{Line: 2, Character: 8}},
+			},
+			ok: true,
+		},
+		{
+			name:     "before the path",
+			template: "package main\n\nimport |\"fmt\"\n",
+		},
+		{
+			name:     "after the path",
+			template: "package main\n\nimport \"fmt\"|\n",
+		},
+		{
+			name:     "strings in Go code after the imports",
+			template: "package main\n\nimport \"fmt\"\n\nvar x = \"a|\"\n",
+		},
+		{
+			name:     "strings in templates",
+			template: "package main\n\ntempl page() {\n\t<a href=\"/|\"></a>\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			lines, pos := splitAtCursor(tt.template)
+			actual, ok := importPathAt(lines, pos)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// writeModule writes the files, which are relative to the returned directory.
+func writeModule(t *testing.T, files map[string]string) (dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	for name, contents := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	return dir
+}
+
+var testModule = map[string]string{
+	"go.mod":                    "module example.com/app\n\ngo 1.20\n",
+	"main.go":                   "package main\n",
+	"components/button.templ":   "package components\n",
+	"internal/db/db.go":         "package db\n",
+	"pages/page.templ":          "package pages\n\nimport \"example.com/app/\"\n",
+	"pages/internal/nav/nav.go": "package nav\n",
+	"testdata/fixture/x.go":     "package fixture\n",
+	".git/hooks/x.go":           "package hooks\n",
+	"tools/tools_test.go":       "package tools\n",
+	"nested/go.mod":             "module example.com/nested\n",
+	"nested/x.go":               "package nested\n",
+}
+
+func TestModulePackages(t *testing.T) {
+	root := writeModule(t, testModule)
+	tests := []struct {
+		dir      string
+		prefix   string
+		expected []string
+	}{
+		{
+			dir:      "pages",
+			prefix:   "example.com/app",
+			expected: []string{"example.com/app", "example.com/app/components", "example.com/app/internal/db", "example.com/app/pages/internal/nav"},
+		},
+		{
+			dir:      "components",
+			prefix:   "example.com/app/",
+			expected: []string{"example.com/app/internal/db", "example.com/app/pages"},
+		},
+		{
+			dir:    "pages",
+			prefix: "github.com/",
+		},
+	}
+	for _, tt := range tests {
+		actual, err := modulePackages(filepath.Join(root, tt.dir), tt.prefix)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(tt.expected, actual); diff != "" {
+			t.Errorf("%s, %q:\n%s", tt.dir, tt.prefix, diff)
+		}
+	}
+	if _, err := modulePackages(t.TempDir(), ""); err == nil {
+		t.Error("expected an error outside of a module")
+	}
+}
+
+func TestImportPathCompletionListsModulePackagesFirst(t *testing.T) {
+	root := writeModule(t, testModule)
+	target := testTarget{
+		completion: func(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error) {
+			// gopls replaces the text of the path before the cursor.
+			r := lsp.Range{
+				Start: lsp.Position{Line: params.Position.Line, Character: params.Position.Character - uint32(len("example.com/app/"))},
+				End:   params.Position,
+			}
+			return &lsp.CompletionList{Items: []lsp.CompletionItem{
+				{Label: "example.com/app/components", SortText: "00000", TextEdit: &lsp.TextEdit{Range: r, NewText: "example.com/app/components"}},
+				{Label: "example.com/app/other", SortText: "00001", TextEdit: &lsp.TextEdit{Range: r, NewText: "example.com/app/other"}},
+			}}, nil
+		},
+	}
+	s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	init(&testClient{})
+	templURI := lsp.DocumentURI(uri.File(filepath.Join(root, "pages", "page.templ")))
+	ctx := context.Background()
+	err := s.DidOpen(ctx, &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: templURI, Version: 1, Text: testModule["pages/page.templ"]},
+	})
+	if err != nil {
+		t.Fatalf("failed to open document: %v", err)
+	}
+	result, err := s.Completion(ctx, &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+			Position:     lsp.Position{Line: 2, Character: 24},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var labels, sortTexts []string
+	for _, item := range result.Items {
+		labels = append(labels, item.Label)
+		sortTexts = append(sortTexts, item.SortText)
+		expectedRange := lsp.Range{Start: lsp.Position{Line: 2, Character: 8}, End: lsp.Position{Line: 2, Character: 24}}
+		if diff := cmp.Diff(expectedRange, item.TextEdit.Range); diff != "" {
+			t.Errorf("unexpected range for %s:\n%s", item.Label, diff)
+		}
+	}
+	expectedLabels := []string{
+		"example.com/app/components",
+		"example.com/app/internal/db",
+		"example.com/app/pages/internal/nav",
+		"example.com/app/other",
+	}
+	if diff := cmp.Diff(expectedLabels, labels); diff != "" {
+		t.Error(diff)
+	}
+	if !strings.HasPrefix(sortTexts[0], "0") || !strings.HasPrefix(sortTexts[3], "1") {
+		t.Errorf("expected the packages of the module to be sorted first, got %v", sortTexts)
+	}
+}
@@ -0,0 +1,70 @@
+package proxy
+
+import (
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+)
+
+// lintFixes returns quick fixes for the lint issues within the range of the request that can
+// be fixed mechanically, e.g. by adding a missing attribute.
+func (p *Server) lintFixes(templURI lsp.DocumentURI, params *lsp.CodeActionParams) (actions []lsp.CodeAction) {
+	if !codeActionKindRequested(params.Context.Only, lsp.QuickFix) {
+		return nil
+	}
+	doc, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return nil
+	}
+	template, err := parseString(doc.String())
+	if err != nil {
+		return nil
+	}
+	for _, issue := range p.validate(templURI, template) {
+		if issue.Fix == nil {
+			continue
+		}
+		diagnostic := lintDiagnostic(doc.Lines, issue)
+		if !rangesOverlap(diagnostic.Range, params.Range) {
+			continue
+		}
+		edits := make([]lsp.TextEdit, len(issue.Fix.Edits))
+		for i, e := range issue.Fix.Edits {
+			edits[i] = lsp.TextEdit{Range: lspRange(doc.Lines, e.Range), NewText: e.NewText}
+		}
+		actions = append(actions, lsp.CodeAction{
+			Title:       issue.Fix.Title,
+			Kind:        lsp.QuickFix,
+			Diagnostics: []lsp.Diagnostic{diagnostic},
+			IsPreferred: true,
+			Edit: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{templURI: edits},
+			},
+		})
+	}
+	return actions
+}
+
+// codeActionKindRequested returns true if the kind of code action, or the kind that it's a
+// sub-kind of, was requested. If no kinds were requested, all kinds are.
+func codeActionKindRequested(only []lsp.CodeActionKind, kind lsp.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, k := range only {
+		if k == "" || k == kind || strings.HasPrefix(string(kind), string(k)+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// rangesOverlap returns true if the ranges overlap, or touch, so that a fix is offered when
+// the cursor is at either end of the diagnostic.
+func rangesOverlap(a, b lsp.Range) bool {
+	return !positionBefore(a.End, b.Start) && !positionBefore(b.End, a.Start)
+}
+
+func positionBefore(a, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
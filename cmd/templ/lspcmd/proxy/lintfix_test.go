@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestCodeActionsIncludeLintFixes(t *testing.T) {
+	template := `package main
+
+templ menu(toggle templ.ComponentScript) {
+	<p>Menu</p>
+	<span>🍔</span><div onclick={ toggle }>Open</div>
+}
+`
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	generated, err := generator.GenerateFile(generator.GenerateOpts{}, tf, io.Discard)
+	if err != nil {
+		t.Fatalf("failed to generate template: %v", err)
+	}
+	templURI := lsp.DocumentURI("file:///menu.templ")
+	target := testTarget{
+		codeAction: func(ctx context.Context, params *lsp.CodeActionParams) ([]lsp.CodeAction, error) {
+			return nil, nil
+		},
+	}
+	s, _ := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	s.SourceMapCache.Set(string(templURI), generated.SourceMap)
+	s.TemplSource.Set(string(templURI), 1, NewDocument(zap.NewNop(), template))
+
+	// The range of the div's name, in UTF-16 code units, in which the emoji is 2 long.
+	divRange := lsp.Range{
+		Start: lsp.Position{Line: 4, Character: 17},
+		End:   lsp.Position{Line: 4, Character: 20},
+	}
+	codeActions := func(t *testing.T, r lsp.Range, only ...lsp.CodeActionKind) []lsp.CodeAction {
+		t.Helper()
+		result, err := s.CodeAction(context.Background(), &lsp.CodeActionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+			Range:        r,
+			Context:      lsp.CodeActionContext{Only: only},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	t.Run("issues within the range are fixed", func(t *testing.T) {
+		cursor := lsp.Range{Start: divRange.End, End: divRange.End}
+		expected := []lsp.CodeAction{
+			{
+				Title: `Add role="button" tabindex="0"`,
+				Kind:  lsp.QuickFix,
+				Diagnostics: []lsp.Diagnostic{
+					{
+						Range:    divRange,
+						Severity: lsp.DiagnosticSeverityWarning,
+						Code:     "click-handler-role",
+						Source:   "templ-lint",
+						Message:  `<div>: click handler on an element that can't be focused, add role="button" tabindex="0", or use a <button>`,
+					},
+				},
+				IsPreferred: true,
+				Edit: &lsp.WorkspaceEdit{
+					Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+						templURI: {{
+							Range:   lsp.Range{Start: divRange.End, End: divRange.End},
+							NewText: ` role="button" tabindex="0"`,
+						}},
+					},
+				},
+			},
+		}
+		if diff := cmp.Diff(expected, codeActions(t, cursor)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("issues outside the range aren't fixed", func(t *testing.T) {
+		r := lsp.Range{Start: lsp.Position{Line: 3, Character: 0}, End: lsp.Position{Line: 3, Character: 4}}
+		if actions := codeActions(t, r); len(actions) != 0 {
+			t.Errorf("expected no code actions, got %v", actions)
+		}
+	})
+	t.Run("fixes aren't returned unless quick fixes are requested", func(t *testing.T) {
+		if actions := codeActions(t, divRange, lsp.SourceOrganizeImports); len(actions) != 0 {
+			t.Errorf("expected no code actions, got %v", actions)
+		}
+		if actions := codeActions(t, divRange, lsp.QuickFix); len(actions) != 1 {
+			t.Errorf("expected the fix, got %v", actions)
+		}
+	})
+}
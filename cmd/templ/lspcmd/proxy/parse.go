@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// parseTimeout is how long the server waits for a templ file to be parsed. Even large files
+// parse in milliseconds, so a parse that takes longer is assumed to be stuck on input that the
+// parser doesn't handle, and is reported as a diagnostic, instead of freezing the editor.
+var parseTimeout = 10 * time.Second
+
+// parseTemplateFile is replaced in tests.
+var parseTemplateFile = parser.ParseString
+
+// parseString parses the templ file, like parser.ParseString, but returns an error if it takes
+// longer than parseTimeout, or panics. A stuck parse can't be stopped, so it's left running in
+// the background.
+func parseString(text string) (parser.TemplateFile, error) {
+	type result struct {
+		tf  parser.TemplateFile
+		err error
+	}
+	done := make(chan result, 1)
+	parse := parseTemplateFile
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("templ: the parser failed, please report this as a bug with the contents of the file: %v", r)}
+			}
+		}()
+		tf, err := parse(text)
+		done <- result{tf: tf, err: err}
+	}()
+	timer := time.NewTimer(parseTimeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.tf, r.err
+	case <-timer.C:
+		return parser.TemplateFile{}, fmt.Errorf("templ: the file took longer than %v to parse, please report this as a bug with the contents of the file", parseTimeout)
+	}
+}
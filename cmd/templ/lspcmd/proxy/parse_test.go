@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"go.uber.org/zap"
+)
+
+// setParser replaces the parser used by the server until the test ends.
+func setParser(t *testing.T, timeout time.Duration, parse func(string) (parser.TemplateFile, error)) {
+	previousTimeout, previousParse := parseTimeout, parseTemplateFile
+	parseTimeout, parseTemplateFile = timeout, parse
+	t.Cleanup(func() {
+		parseTimeout, parseTemplateFile = previousTimeout, previousParse
+	})
+}
+
+func TestStuckParsesArePublishedAsDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(string) (parser.TemplateFile, error)
+		expected string
+	}{
+		{
+			name: "parses that don't finish",
+			parse: func(string) (parser.TemplateFile, error) {
+				select {}
+			},
+			expected: "templ: the file took longer than 10ms to parse",
+		},
+		{
+			name: "parses that panic",
+			parse: func(string) (parser.TemplateFile, error) {
+				panic("index out of range")
+			},
+			expected: "templ: the parser failed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			setParser(t, 10*time.Millisecond, tt.parse)
+			client := &testClient{}
+			s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+			init(client)
+			_, ok, err := s.parseTemplate(context.Background(), lsp.DocumentURI("file:///a.templ"), "package main\n")
+			if ok || err != nil {
+				t.Fatalf("expected the template not to be parsed, without an error, got ok=%v, err=%v", ok, err)
+			}
+			if len(client.diagnostics) != 1 || len(client.diagnostics[0].Diagnostics) != 1 {
+				t.Fatalf("expected a diagnostic, got %v", client.diagnostics)
+			}
+			d := client.diagnostics[0].Diagnostics[0]
+			if d.Severity != lsp.DiagnosticSeverityError || !strings.HasPrefix(d.Message, tt.expected) {
+				t.Errorf("expected an error starting with %q, got %v", tt.expected, d)
+			}
+		})
+	}
+}
+
+func TestFilesWithoutDeclarationsAreParsed(t *testing.T) {
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(client)
+	ctx := context.Background()
+	uri := lsp.DocumentURI("file:///a.templ")
+	for _, input := range []string{"package main", "package main\n\nimport \"fmt\"\n\nvar x = fmt.Sprint(1)\n"} {
+		if _, ok, err := s.parseTemplate(ctx, uri, input); !ok || err != nil {
+			t.Fatalf("expected %q to be parsed, got ok=%v, err=%v", input, ok, err)
+		}
+		last := client.diagnostics[len(client.diagnostics)-1]
+		if len(last.Diagnostics) != 0 {
+			t.Errorf("expected no diagnostics for %q, got %v", input, last.Diagnostics)
+		}
+	}
+
+	// A missing package is reported at the start of the file.
+	if _, ok, _ := s.parseTemplate(ctx, uri, "templ A() {\n}\n"); ok {
+		t.Fatal("expected a parse error")
+	}
+	last := client.diagnostics[len(client.diagnostics)-1]
+	if len(last.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", last.Diagnostics)
+	}
+	if d := last.Diagnostics[0]; d.Range != (lsp.Range{}) || !strings.HasPrefix(d.Message, "missing package declaration") {
+		t.Errorf("expected a missing package error on the first line, got %v", d)
+	}
+}
@@ -2,23 +2,139 @@ package proxy
 
 import (
 	"path"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/cmd/templ/config"
+	"go.lsp.dev/uri"
 )
 
-func convertTemplToGoURI(templURI lsp.DocumentURI) (isTemplFile bool, goURI lsp.DocumentURI) {
+// URIMapper maps the URIs of templ files to the URIs of the Go files that templ generate
+// writes for them, and back, so that gopls sees the Go code in the same package as the
+// generated code on disk. It caches the templ.json file that applies to each directory.
+type URIMapper struct {
+	// output is used for every file if it's set. Otherwise, the templ.json file that applies
+	// to each file is used.
+	output *config.Output
+
+	m           sync.Mutex
+	dirToConfig map[string]loadedConfig
+	// goToTempl records the templ file of each Go URI that has been returned, because the
+	// templ.json file may not be found from the Go file's directory.
+	goToTempl map[lsp.DocumentURI]lsp.DocumentURI
+}
+
+type loadedConfig struct {
+	config config.Config
+	err    error
+}
+
+// NewURIMapper creates a URIMapper that names the generated files with output. If output is
+// nil, the templ.json file that applies to each file is used.
+func NewURIMapper(output *config.Output) *URIMapper {
+	if output != nil && output.Suffix == "" {
+		withSuffix := *output
+		withSuffix.Suffix = config.DefaultSuffix
+		output = &withSuffix
+	}
+	return &URIMapper{
+		output:      output,
+		dirToConfig: make(map[string]loadedConfig),
+		goToTempl:   make(map[lsp.DocumentURI]lsp.DocumentURI),
+	}
+}
+
+// Config returns the configuration in the templ.json file that applies to the directory. If
+// the templ.json file is invalid, the default configuration is returned with the error.
+func (m *URIMapper) Config(dir string) (config.Config, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	lc, ok := m.dirToConfig[dir]
+	if !ok {
+		if lc.config, lc.err = config.Load(dir); lc.err != nil {
+			lc.config = config.Default(dir)
+		}
+		m.dirToConfig[dir] = lc
+	}
+	return lc.config, lc.err
+}
+
+// Reload discards the cached templ.json files, so that changes to them are picked up.
+func (m *URIMapper) Reload() {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.dirToConfig = make(map[string]loadedConfig)
+	m.goToTempl = make(map[lsp.DocumentURI]lsp.DocumentURI)
+}
+
+func (m *URIMapper) outputFor(dir string) config.Output {
+	if m.output != nil {
+		return *m.output
+	}
+	// An invalid templ.json file is reported when it's loaded, so the default is used.
+	c, _ := m.Config(dir)
+	return c.Output()
+}
+
+// TemplToGo returns the URI of the Go file generated from the templ file, or false if the URI
+// isn't a templ file.
+func (m *URIMapper) TemplToGo(templURI lsp.DocumentURI) (isTemplFile bool, goURI lsp.DocumentURI) {
 	base, fileName := path.Split(string(templURI))
 	if !strings.HasSuffix(fileName, ".templ") {
 		return
 	}
-	return true, lsp.DocumentURI(base + (strings.TrimSuffix(fileName, ".templ") + "_templ.go"))
+	c := config.Default("").Output()
+	templFileName, isFile := fileNameOf(templURI)
+	if isFile {
+		c = m.outputFor(filepath.Dir(templFileName))
+	}
+	goURI = lsp.DocumentURI(base + strings.TrimSuffix(fileName, ".templ") + c.Suffix)
+	if c.OutDir != "" {
+		// Files outside of the root keep the Go file alongside the templ file.
+		if goFileName, err := c.GoFileName(templFileName); err == nil {
+			goURI = lsp.DocumentURI(uri.File(goFileName))
+		}
+		m.m.Lock()
+		m.goToTempl[goURI] = templURI
+		m.m.Unlock()
+	}
+	return true, goURI
 }
 
-func convertTemplGoToTemplURI(goURI lsp.DocumentURI) (isTemplGoFile bool, templURI lsp.DocumentURI) {
+// TemplGoToTempl returns the URI of the templ file that the Go file was generated from, or
+// false if the URI isn't a generated Go file.
+func (m *URIMapper) TemplGoToTempl(goURI lsp.DocumentURI) (isTemplGoFile bool, templURI lsp.DocumentURI) {
+	m.m.Lock()
+	templURI, ok := m.goToTempl[goURI]
+	m.m.Unlock()
+	if ok {
+		return true, templURI
+	}
+	c := config.Default("").Output()
+	goFileName, isFile := fileNameOf(goURI)
+	if isFile {
+		c = m.outputFor(filepath.Dir(goFileName))
+	}
+	if c.OutDir != "" {
+		templFileName, ok := c.TemplFileName(goFileName)
+		if !ok {
+			return false, ""
+		}
+		return true, lsp.DocumentURI(uri.File(templFileName))
+	}
 	base, fileName := path.Split(string(goURI))
-	if !strings.HasSuffix(fileName, "_templ.go") {
+	if !strings.HasSuffix(fileName, c.Suffix) || fileName == c.Suffix {
 		return
 	}
-	return true, lsp.DocumentURI(base + (strings.TrimSuffix(fileName, "_templ.go") + ".templ"))
+	return true, lsp.DocumentURI(base + strings.TrimSuffix(fileName, c.Suffix) + ".templ")
+}
+
+// fileNameOf returns the file name of a file:// URI.
+func fileNameOf(u lsp.DocumentURI) (fileName string, ok bool) {
+	if !strings.HasPrefix(string(u), uri.FileScheme+"://") {
+		return "", false
+	}
+	return u.Filename(), true
 }
@@ -0,0 +1,518 @@
+package proxy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// span is a range of text within a document, as byte offsets.
+type span struct {
+	from, to int
+}
+
+func (s span) contains(index int) bool {
+	return s.from <= index && index <= s.to
+}
+
+// selectionRanges returns the chain of selection ranges for each position, from the
+// identifier under the cursor, outwards to the enclosing templ declaration.
+func selectionRanges(lines []string, positions []lsp.Position) (result []lsp.SelectionRange, err error) {
+	text := strings.Join(lines, "\n")
+	tf, err := parseString(text)
+	if err != nil {
+		return nil, err
+	}
+	sc := &spanCollector{text: text}
+	sc.templateFile(tf)
+	result = make([]lsp.SelectionRange, len(positions))
+	for i, pos := range positions {
+		index := indexOfPosition(lines, pos)
+		var containing []span
+		if word, ok := wordAt(text, index); ok {
+			containing = append(containing, word)
+		}
+		for _, s := range sc.spans {
+			if s.contains(index) {
+				containing = append(containing, s)
+			}
+		}
+		sort.SliceStable(containing, func(i, j int) bool {
+			return containing[i].to-containing[i].from < containing[j].to-containing[j].from
+		})
+		// Build the chain from the outside in.
+		var parent *lsp.SelectionRange
+		for j := len(containing) - 1; j >= 0; j-- {
+			r := lsp.Range{
+				Start: positionOfIndex(text, containing[j].from),
+				End:   positionOfIndex(text, containing[j].to),
+			}
+			if parent != nil && parent.Range == r {
+				continue
+			}
+			parent = &lsp.SelectionRange{Range: r, Parent: parent}
+		}
+		if parent == nil {
+			parent = &lsp.SelectionRange{Range: lsp.Range{Start: pos, End: pos}}
+		}
+		result[i] = *parent
+	}
+	return result, nil
+}
+
+// indexOfPosition returns the byte index within the text of the position, whose column is
+// in UTF-16 code units.
+func indexOfPosition(lines []string, pos lsp.Position) (index int) {
+	for i := 0; i < int(pos.Line) && i < len(lines); i++ {
+		index += len(lines[i]) + 1
+	}
+	if int(pos.Line) < len(lines) {
+		line := lines[pos.Line]
+		if c := int(parser.ByteColFromUTF16(line, pos.Character)); c < len(line) {
+			return index + c
+		}
+		return index + len(line)
+	}
+	return index
+}
+
+// positionOfIndex returns the position of the byte index within the text, with the column
+// in UTF-16 code units.
+func positionOfIndex(text string, index int) (pos lsp.Position) {
+	if index > len(text) {
+		index = len(text)
+	}
+	before := text[:index]
+	lineStart := strings.LastIndex(before, "\n") + 1
+	line := text[lineStart:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	pos.Line = uint32(strings.Count(before, "\n"))
+	pos.Character = parser.UTF16Col(line, uint32(index-lineStart))
+	return pos
+}
+
+func isIdentifierChar(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordAt returns the span of the identifier at the index. Identifiers may contain multi-byte
+// characters, so the text is scanned a rune at a time.
+func wordAt(text string, index int) (s span, ok bool) {
+	s.from, s.to = index, index
+	for s.from > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:s.from])
+		if !isIdentifierChar(r) {
+			break
+		}
+		s.from -= size
+	}
+	for s.to < len(text) {
+		r, size := utf8.DecodeRuneInString(text[s.to:])
+		if !isIdentifierChar(r) {
+			break
+		}
+		s.to += size
+	}
+	return s, s.to > s.from
+}
+
+// spanCollector walks the AST, and finds the location of each node in the text.
+//
+// Only expressions have positions within the AST, so the collector keeps a cursor
+// that moves forward through the text as each node is found. If a node can't be
+// found, the rest of its siblings are skipped.
+type spanCollector struct {
+	text   string
+	cursor int
+	spans  []span
+	// names are the element and attribute names that were found.
+	names []nameSpan
+	// tags are the names within the opening and closing tags of elements that have both.
+	tags []tagNameSpans
+	// declarations are the templ, css and script declarations, keyed by their index
+	// within the template file's nodes.
+	declarations map[int]span
+	// cssValues are the values of the constant properties of css templates, and of constant
+	// style attributes.
+	cssValues []span
+}
+
+// tagNameSpans are the locations of an element's name within its opening and closing tags.
+type tagNameSpans struct {
+	open, close span
+}
+
+// nameSpan is the location of an element or attribute name.
+type nameSpan struct {
+	span
+	name        string
+	isAttribute bool
+}
+
+func (sc *spanCollector) addName(from int, name string, isAttribute bool) {
+	sc.names = append(sc.names, nameSpan{
+		span:        span{from: from, to: from + len(name)},
+		name:        name,
+		isAttribute: isAttribute,
+	})
+}
+
+func (sc *spanCollector) add(from, to int) {
+	if from < 0 || to > len(sc.text) || from >= to {
+		return
+	}
+	sc.spans = append(sc.spans, span{from: from, to: to})
+}
+
+// find moves the cursor past the next instance of s, and returns its start.
+func (sc *spanCollector) find(s string) (index int, ok bool) {
+	if sc.cursor > len(sc.text) {
+		return 0, false
+	}
+	i := strings.Index(sc.text[sc.cursor:], s)
+	if i < 0 {
+		return 0, false
+	}
+	index = sc.cursor + i
+	sc.cursor = index + len(s)
+	return index, true
+}
+
+// expression adds the span of the Go expression, and moves the cursor past it.
+func (sc *spanCollector) expression(e parser.Expression) (from, to int) {
+	from, to = int(e.Range.From.Index), int(e.Range.To.Index)
+	sc.add(from, to)
+	if to > sc.cursor {
+		sc.cursor = to
+	}
+	return from, to
+}
+
+// braced adds the span of an expression within braces, e.g. { name }.
+func (sc *spanCollector) braced(e parser.Expression) (from, to int, ok bool) {
+	from, _ = sc.expression(e)
+	from = strings.LastIndex(sc.text[:from], "{")
+	if from < 0 {
+		return 0, 0, false
+	}
+	closing, ok := sc.find("}")
+	if !ok {
+		return 0, 0, false
+	}
+	to = closing + 1
+	sc.add(from, to)
+	return from, to, true
+}
+
+// keyword returns the start of the keyword that precedes the expression, e.g. "if".
+func (sc *spanCollector) keyword(e parser.Expression, keyword string) int {
+	from := int(e.Range.From.Index)
+	if i := strings.LastIndex(sc.text[:from], keyword); i >= 0 {
+		return i
+	}
+	return from
+}
+
+// block adds the span of a control-flow block, from the keyword to the closing brace.
+func (sc *spanCollector) block(from int, children func() bool) bool {
+	if _, ok := sc.find("{"); !ok {
+		return false
+	}
+	if !children() {
+		return false
+	}
+	closing, ok := sc.find("}")
+	if !ok {
+		return false
+	}
+	sc.add(from, closing+1)
+	return true
+}
+
+func (sc *spanCollector) templateFile(tf parser.TemplateFile) {
+	sc.declarations = make(map[int]span)
+	for i, n := range tf.Nodes {
+		var ok bool
+		switch n := n.(type) {
+		case parser.HTMLTemplate:
+			sc.cursor = int(n.Expression.Range.To.Index)
+			from := sc.keyword(n.Expression, "templ")
+			ok = sc.block(from, func() bool { return sc.nodes(n.Children) })
+		case parser.CSSTemplate:
+			sc.cursor = int(n.Name.Range.To.Index)
+			from := sc.keyword(n.Name, "css")
+			ok = sc.block(from, func() bool {
+				for _, p := range n.Properties {
+					switch p := p.(type) {
+					case parser.ConstantCSSProperty:
+						if _, ok := sc.find(p.Name); !ok {
+							return false
+						}
+						if _, ok := sc.find(":"); !ok {
+							return false
+						}
+						from, ok := sc.find(p.Value)
+						if !ok {
+							return false
+						}
+						sc.cssValues = append(sc.cssValues, span{from: from, to: sc.cursor})
+					case parser.ExpressionCSSProperty:
+						if _, _, ok := sc.braced(p.Value.Expression); !ok {
+							return false
+						}
+					}
+				}
+				return true
+			})
+		case parser.ScriptTemplate:
+			sc.cursor = int(n.Parameters.Range.To.Index)
+			from := sc.keyword(n.Name, "script")
+			ok = sc.block(from, func() bool {
+				_, ok := sc.find(n.Value)
+				return ok
+			})
+		case parser.GoExpression:
+			sc.cursor = int(n.Expression.Range.From.Index)
+			sc.expression(n.Expression)
+		}
+		if ok {
+			// The span of the block is the last one to be added.
+			sc.declarations[i] = sc.spans[len(sc.spans)-1]
+		}
+	}
+}
+
+func (sc *spanCollector) nodes(nodes []parser.Node) bool {
+	for _, n := range nodes {
+		if !sc.node(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (sc *spanCollector) node(n parser.Node) bool {
+	switch n := n.(type) {
+	case parser.Element:
+		return sc.element(n.Name, n.Attributes, func() bool {
+			return sc.nodes(n.Children)
+		}, n.IsVoidElement())
+	case parser.RawElement:
+		return sc.element(n.Name, n.Attributes, func() bool {
+			_, ok := sc.find(n.Contents)
+			return ok
+		}, false)
+	case parser.Text:
+		from, ok := sc.find(n.Value)
+		sc.add(from, sc.cursor)
+		return ok
+	case parser.CharacterReference:
+		from, ok := sc.find(n.Value)
+		sc.add(from, sc.cursor)
+		return ok
+	case parser.HTMLComment:
+		from, ok := sc.find("<!--" + n.Contents + "-->")
+		sc.add(from, sc.cursor)
+		return ok
+	case parser.GoComment:
+		return sc.comment(n)
+	case parser.DocType:
+		from, ok := sc.find("<!")
+		if !ok {
+			return false
+		}
+		to, ok := sc.find(">")
+		sc.add(from, to+1)
+		return ok
+	case parser.StringExpression:
+		_, _, ok := sc.braced(n.Expression)
+		return ok
+	case parser.CallTemplateExpression:
+		_, _, ok := sc.braced(n.Expression)
+		return ok
+	case parser.ChildrenExpression:
+		from, ok := sc.find("{")
+		if !ok {
+			return false
+		}
+		to, ok := sc.find("}")
+		sc.add(from, to+1)
+		return ok
+	case parser.TemplElementExpression:
+		from := sc.keyword(n.Expression, "@")
+		_, to := sc.expression(n.Expression)
+		if len(n.Children) == 0 {
+			sc.add(from, to)
+			return true
+		}
+		return sc.block(from, func() bool { return sc.nodes(n.Children) })
+	case parser.IfExpression:
+		from := sc.keyword(n.Expression, "if")
+		sc.expression(n.Expression)
+		return sc.block(from, func() bool {
+			if !sc.nodes(n.Then) {
+				return false
+			}
+			for _, elseIf := range n.ElseIfs {
+				sc.expression(elseIf.Expression)
+				if !sc.nodes(elseIf.Then) {
+					return false
+				}
+			}
+			if len(n.Else) > 0 {
+				if _, ok := sc.find("else"); !ok {
+					return false
+				}
+			}
+			return sc.nodes(n.Else)
+		})
+	case parser.SwitchExpression:
+		from := sc.keyword(n.Expression, "switch")
+		sc.expression(n.Expression)
+		return sc.block(from, func() bool {
+			for _, c := range n.Cases {
+				caseFrom, _ := sc.expression(c.Expression)
+				if !sc.nodes(c.Children) {
+					return false
+				}
+				sc.add(caseFrom, sc.cursor)
+			}
+			return true
+		})
+	case parser.ForExpression:
+		from := sc.keyword(n.Expression, "for")
+		sc.expression(n.Expression)
+		return sc.block(from, func() bool { return sc.nodes(n.Children) })
+	}
+	// Whitespace, and any nodes without text of their own.
+	return true
+}
+
+func (sc *spanCollector) comment(c parser.GoComment) bool {
+	text := "//" + c.Contents
+	if c.Multiline {
+		text = "/*" + c.Contents + "*/"
+	}
+	from, ok := sc.find(text)
+	sc.add(from, sc.cursor)
+	return ok
+}
+
+func (sc *spanCollector) element(name string, attributes []parser.Attribute, children func() bool, isVoid bool) bool {
+	from, ok := sc.find("<" + name)
+	if !ok {
+		return false
+	}
+	sc.addName(from+1, name, false)
+	openName := span{from: from + 1, to: from + 1 + len(name)}
+	if !sc.attributes(attributes) {
+		return false
+	}
+	openTagEnd, ok := sc.find(">")
+	if !ok {
+		return false
+	}
+	if isVoid || sc.text[openTagEnd-1] == '/' {
+		sc.add(from, openTagEnd+1)
+		return true
+	}
+	if !children() {
+		return false
+	}
+	closeTagStart, ok := sc.find("</" + name)
+	if !ok {
+		return false
+	}
+	sc.addName(closeTagStart+2, name, false)
+	sc.tags = append(sc.tags, tagNameSpans{
+		open:  openName,
+		close: span{from: closeTagStart + 2, to: closeTagStart + 2 + len(name)},
+	})
+	closeTagEnd, ok := sc.find(">")
+	if !ok {
+		return false
+	}
+	sc.add(from, closeTagEnd+1)
+	return true
+}
+
+func (sc *spanCollector) attributes(attributes []parser.Attribute) bool {
+	for _, a := range attributes {
+		switch a := a.(type) {
+		case parser.BoolConstantAttribute:
+			from, ok := sc.find(a.Name)
+			if !ok {
+				return false
+			}
+			sc.addName(from, a.Name, true)
+			sc.add(from, sc.cursor)
+		case parser.ConstantAttribute:
+			from, ok := sc.find(a.Name)
+			if !ok {
+				return false
+			}
+			sc.addName(from, a.Name, true)
+			if _, ok = sc.find(`"`); !ok {
+				return false
+			}
+			valueFrom := sc.cursor
+			to, ok := sc.find(`"`)
+			if !ok {
+				return false
+			}
+			sc.add(valueFrom, to)
+			sc.add(from, to+1)
+			if strings.EqualFold(a.Name, "style") {
+				sc.cssValues = append(sc.cssValues, span{from: valueFrom, to: to})
+			}
+		case parser.ExpressionAttribute:
+			from := sc.keyword(a.Expression, a.Name)
+			sc.addName(from, a.Name, true)
+			_, to, ok := sc.braced(a.Expression)
+			if !ok {
+				return false
+			}
+			sc.add(from, to)
+		case parser.BoolExpressionAttribute:
+			from := sc.keyword(a.Expression, a.Name)
+			sc.addName(from, a.Name, true)
+			_, to, ok := sc.braced(a.Expression)
+			if !ok {
+				return false
+			}
+			sc.add(from, to)
+		case parser.SpreadAttributes:
+			if _, _, ok := sc.braced(a.Expression); !ok {
+				return false
+			}
+		case parser.CommentAttribute:
+			if !sc.comment(a.Comment) {
+				return false
+			}
+		case parser.ConditionalAttribute:
+			from := sc.keyword(a.Expression, "if")
+			sc.expression(a.Expression)
+			ok := sc.block(from, func() bool {
+				if !sc.attributes(a.Then) {
+					return false
+				}
+				if len(a.Else) > 0 {
+					if _, ok := sc.find("else"); !ok {
+						return false
+					}
+				}
+				return sc.attributes(a.Else)
+			})
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
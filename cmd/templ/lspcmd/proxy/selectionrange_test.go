@@ -0,0 +1,176 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+)
+
+const selectionRangeTestTemplate = `package main
+
+templ List(items []string) {
+	<ul class="list" id={ "x" }>
+		for _, item := range items {
+			if item != "" {
+				<li>{ item }</li>
+			} else {
+				<br/>
+			}
+		}
+	</ul>
+}
+`
+
+func TestSelectionRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		position lsp.Position
+		expected []string
+	}{
+		{
+			name:     "expression inside an element inside control flow",
+			position: lsp.Position{Line: 6, Character: 12},
+			expected: []string{
+				"6:10-6:14", // item
+				"6:8-6:16",  // { item }
+				"6:4-6:21",  // <li>...</li>
+				"5:3-9:4",   // if
+				"4:2-10:3",  // for
+				"3:1-11:6",  // <ul>...</ul>
+				"2:0-12:1",  // templ
+			},
+		},
+		{
+			name:     "attribute expression",
+			position: lsp.Position{Line: 3, Character: 24},
+			expected: []string{
+				"3:24-3:25", // x
+				"3:23-3:26", // "x"
+				"3:21-3:28", // { "x" }
+				"3:18-3:28", // id={ "x" }
+				"3:1-11:6",  // <ul>...</ul>
+				"2:0-12:1",  // templ
+			},
+		},
+		{
+			name:     "constant attribute value",
+			position: lsp.Position{Line: 3, Character: 13},
+			expected: []string{
+				"3:12-3:16", // list
+				"3:5-3:17",  // class="list"
+				"3:1-11:6",  // <ul>...</ul>
+				"2:0-12:1",  // templ
+			},
+		},
+	}
+	lines := strings.Split(selectionRangeTestTemplate, "\n")
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := selectionRanges(lines, []lsp.Position{tt.position})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(result))
+			}
+			if diff := cmp.Diff(tt.expected, formatSelectionRange(result[0])); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSelectionRangesOfNonASCIIIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		position lsp.Position
+		expected string
+	}{
+		{
+			name:     "multi-byte characters at the end",
+			template: "package main\n\ntempl A() {\n\t<p class=\"café\"></p>\n}\n",
+			position: lsp.Position{Line: 3, Character: 12},
+			expected: "3:11-3:15",
+		},
+		{
+			name:     "multi-byte characters at the start",
+			template: "package main\n\ntempl A() {\n\t<p class=\"été\"></p>\n}\n",
+			position: lsp.Position{Line: 3, Character: 12},
+			expected: "3:11-3:14",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := selectionRanges(strings.Split(tt.template, "\n"), []lsp.Position{tt.position})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(result))
+			}
+			if actual := formatSelectionRange(result[0])[0]; actual != tt.expected {
+				t.Errorf("expected the identifier to be selected as %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSelectionRangesReturnsAChainForEachPosition(t *testing.T) {
+	lines := strings.Split(selectionRangeTestTemplate, "\n")
+	positions := []lsp.Position{
+		{Line: 6, Character: 12},
+		{Line: 8, Character: 5},
+	}
+	result, err := selectionRanges(lines, positions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != len(positions) {
+		t.Fatalf("expected %d results, got %d", len(positions), len(result))
+	}
+	expected := [][]string{
+		{"6:10-6:14", "6:8-6:16", "6:4-6:21", "5:3-9:4", "4:2-10:3", "3:1-11:6", "2:0-12:1"},
+		{"8:5-8:7", "8:4-8:9", "5:3-9:4", "4:2-10:3", "3:1-11:6", "2:0-12:1"},
+	}
+	for i := range result {
+		if diff := cmp.Diff(expected[i], formatSelectionRange(result[i])); diff != "" {
+			t.Errorf("position %d: %s", i, diff)
+		}
+	}
+}
+
+func TestSelectionRangesSkipComments(t *testing.T) {
+	template := `package main
+
+templ List() {
+	// The <li> is below.
+	<!-- <li> -->
+	<li>x</li>
+}
+`
+	result, err := selectionRanges(strings.Split(template, "\n"), []lsp.Position{{Line: 5, Character: 5}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"5:5-5:6",  // x
+		"5:1-5:11", // <li>x</li>
+		"2:0-6:1",  // templ
+	}
+	if diff := cmp.Diff(expected, formatSelectionRange(result[0])); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func formatSelectionRange(sr lsp.SelectionRange) (ranges []string) {
+	for p := &sr; p != nil; p = p.Parent {
+		ranges = append(ranges, fmt.Sprintf("%d:%d-%d:%d", p.Range.Start.Line, p.Range.Start.Character, p.Range.End.Line, p.Range.End.Character))
+	}
+	return ranges
+}
@@ -2,14 +2,20 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
-	"github.com/a-h/parse"
 	lsp "github.com/a-h/protocol"
 	"github.com/a-h/templ/generator"
 	"github.com/a-h/templ/parser/v2"
+	"go.lsp.dev/jsonrpc2"
 	"go.lsp.dev/uri"
 	"go.uber.org/zap"
 )
@@ -34,7 +40,25 @@ type Server struct {
 	Target         lsp.Server
 	SourceMapCache *SourceMapCache
 	TemplSource    *DocumentContents
-	GoSource       map[string]string
+	// GoSource is the Go code generated from each open templ file. Use GetGoSource to read it,
+	// since requests are handled concurrently.
+	GoSource     map[string]string
+	goSourceLock sync.RWMutex
+	// ClientCapabilities are the capabilities sent by the client during Initialize.
+	ClientCapabilities lsp.ClientCapabilities
+	// workspaceFolders are used to avoid sending documents to gopls that it will reject.
+	workspaceFolders workspaceFolders
+	// DebugRequests enables the non-standard templ/sourceMap request.
+	DebugRequests bool
+	// closedDocuments have diagnostics maintained from the file on disk.
+	closedDocuments closedDocuments
+	// diskSourceMaps are the source maps of templ files that aren't open.
+	diskSourceMaps diskSourceMaps
+	// URIs maps templ files to the Go files generated from them.
+	URIs *URIMapper
+	// goCompletion is gopls's completion provider, which is registered for Go files in
+	// Initialized if the client supports dynamic registration.
+	goCompletion lsp.CompletionOptions
 }
 
 func NewServer(log *zap.Logger, target lsp.Server, cache *SourceMapCache) (s *Server, init func(lsp.Client)) {
@@ -44,17 +68,38 @@ func NewServer(log *zap.Logger, target lsp.Server, cache *SourceMapCache) (s *Se
 		SourceMapCache: cache,
 		TemplSource:    newDocumentContents(log),
 		GoSource:       make(map[string]string),
+		URIs:           NewURIMapper(nil),
 	}
 	return s, func(client lsp.Client) {
 		s.Client = client
 	}
 }
 
+// GetGoSource returns the Go code generated from an open templ file.
+func (p *Server) GetGoSource(templURI string) (goSource string, ok bool) {
+	p.goSourceLock.RLock()
+	defer p.goSourceLock.RUnlock()
+	goSource, ok = p.GoSource[templURI]
+	return
+}
+
+func (p *Server) setGoSource(templURI, goSource string) {
+	p.goSourceLock.Lock()
+	defer p.goSourceLock.Unlock()
+	p.GoSource[templURI] = goSource
+}
+
+func (p *Server) deleteGoSource(templURI string) {
+	p.goSourceLock.Lock()
+	defer p.goSourceLock.Unlock()
+	delete(p.GoSource, templURI)
+}
+
 // updatePosition maps positions and filenames from source templ files into the target *.go files.
 func (p *Server) updatePosition(templURI lsp.DocumentURI, current lsp.Position) (ok bool, goURI lsp.DocumentURI, updated lsp.Position) {
 	log := p.Log.With(zap.String("uri", string(templURI)))
 	var isTemplFile bool
-	if isTemplFile, goURI = convertTemplToGoURI(templURI); !isTemplFile {
+	if isTemplFile, goURI = p.URIs.TemplToGo(templURI); !isTemplFile {
 		return false, templURI, current
 	}
 	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
@@ -63,7 +108,7 @@ func (p *Server) updatePosition(templURI lsp.DocumentURI, current lsp.Position)
 		return
 	}
 	// Map from the source position to target Go position.
-	to, ok := sourceMap.TargetPositionFromSource(current.Line, current.Character)
+	to, ok := sourceMap.TargetPositionFromSourceUTF16(current.Line, current.Character)
 	if !ok {
 		log.Info("updatePosition: not found", zap.String("from", fmt.Sprintf("%d:%d", current.Line, current.Character)))
 		return false, templURI, current
@@ -77,17 +122,17 @@ func (p *Server) updatePosition(templURI lsp.DocumentURI, current lsp.Position)
 
 func (p *Server) convertTemplRangeToGoRange(templURI lsp.DocumentURI, input lsp.Range) (output lsp.Range) {
 	output = input
-	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	sourceMap, ok := p.getSourceMap(templURI)
 	if !ok {
 		return
 	}
 	// Map from the source position to target Go position.
-	start, ok := sourceMap.TargetPositionFromSource(input.Start.Line, input.Start.Character)
+	start, ok := sourceMap.TargetPositionFromSourceUTF16(input.Start.Line, input.Start.Character)
 	if ok {
 		output.Start.Line = start.Line
 		output.Start.Character = start.Col
 	}
-	end, ok := sourceMap.TargetPositionFromSource(input.End.Line, input.End.Character)
+	end, ok := sourceMap.TargetPositionFromSourceUTF16(input.End.Line, input.End.Character)
 	if ok {
 		output.End.Line = end.Line
 		output.End.Character = end.Col
@@ -96,18 +141,22 @@ func (p *Server) convertTemplRangeToGoRange(templURI lsp.DocumentURI, input lsp.
 }
 
 func (p *Server) convertGoRangeToTemplRange(templURI lsp.DocumentURI, input lsp.Range) (output lsp.Range) {
-	output = input
-	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	sourceMap, ok := p.getSourceMap(templURI)
 	if !ok {
-		return
+		return input
 	}
-	// Map from the source position to target Go position.
-	start, ok := sourceMap.SourcePositionFromTarget(input.Start.Line, input.Start.Character)
+	return convertGoRangeToTemplRange(sourceMap, input)
+}
+
+func convertGoRangeToTemplRange(sourceMap *parser.SourceMap, input lsp.Range) (output lsp.Range) {
+	output = input
+	// Map from the target Go position to the source position.
+	start, ok := sourceMap.SourcePositionFromTargetUTF16(input.Start.Line, input.Start.Character)
 	if ok {
 		output.Start.Line = start.Line
 		output.Start.Character = start.Col
 	}
-	end, ok := sourceMap.SourcePositionFromTarget(input.End.Line, input.End.Character)
+	end, ok := sourceMap.SourcePositionFromTargetUTF16(input.End.Line, input.End.Character)
 	if ok {
 		output.End.Line = end.Line
 		output.End.Character = end.Col
@@ -115,32 +164,190 @@ func (p *Server) convertGoRangeToTemplRange(templURI lsp.DocumentURI, input lsp.
 	return
 }
 
+// convertGoLocationToTemplLocation maps locations within generated *_templ.go files back to
+// their templ source, e.g. a definition found in another file or package.
+func (p *Server) convertGoLocationToTemplLocation(l lsp.Location) lsp.Location {
+	isTemplGoFile, templURI := p.URIs.TemplGoToTempl(l.URI)
+	if !isTemplGoFile {
+		return l
+	}
+	sourceMap, ok := p.getSourceMap(templURI)
+	if !ok {
+		return l
+	}
+	return lsp.Location{
+		URI:   templURI,
+		Range: convertGoRangeToTemplRange(sourceMap, l.Range),
+	}
+}
+
+// getSourceMap returns the source map of a templ file. If the file isn't open in the editor,
+// it's read from disk and generated, and the result is cached until the file changes.
+func (p *Server) getSourceMap(templURI lsp.DocumentURI) (sourceMap *parser.SourceMap, ok bool) {
+	if sourceMap, ok = p.SourceMapCache.Get(string(templURI)); ok {
+		return
+	}
+	if p.SourceMapCache.Outdated(string(templURI)) {
+		p.Log.Info("getSourceMap: the source map is outdated, because the template couldn't be generated", zap.String("uri", string(templURI)))
+		return nil, false
+	}
+	// The file on disk doesn't match the document in the editor, so it's only used for
+	// templates that aren't open.
+	if _, isOpen := p.TemplSource.Version(string(templURI)); isOpen {
+		return nil, false
+	}
+	info, err := os.Stat(templURI.Filename())
+	if err != nil {
+		p.Log.Info("getSourceMap: failed to stat template", zap.String("uri", string(templURI)), zap.Error(err))
+		return nil, false
+	}
+	if sourceMap, cached := p.diskSourceMaps.Get(string(templURI), info); cached {
+		return sourceMap, sourceMap != nil
+	}
+	sourceMap = p.generateFromDisk(templURI)
+	p.diskSourceMaps.Set(string(templURI), info, sourceMap)
+	return sourceMap, sourceMap != nil
+}
+
+// generateFromDisk returns the source map of the templ file on disk, or nil if it can't be
+// generated.
+func (p *Server) generateFromDisk(templURI lsp.DocumentURI) *parser.SourceMap {
+	contents, err := os.ReadFile(templURI.Filename())
+	if err != nil {
+		p.Log.Info("getSourceMap: failed to read template", zap.String("uri", string(templURI)), zap.Error(err))
+		return nil
+	}
+	template, err := parseString(string(contents))
+	if err != nil {
+		p.Log.Info("getSourceMap: failed to parse template", zap.String("uri", string(templURI)), zap.Error(err))
+		return nil
+	}
+	w := new(strings.Builder)
+	sourceMap, err := generate(templURI, template, w)
+	if err != nil {
+		p.Log.Info("getSourceMap: failed to generate template", zap.String("uri", string(templURI)), zap.Error(err))
+		return nil
+	}
+	sourceMap.SetText(string(contents), w.String())
+	return sourceMap
+}
+
+// generate writes the Go code for a templ file. Files included in the template are read
+// relative to the templ file.
+func generate(templURI lsp.DocumentURI, template parser.TemplateFile, w io.Writer) (*parser.SourceMap, error) {
+	result, err := generator.GenerateFile(generator.GenerateOpts{IncludeDir: filepath.Dir(templURI.Filename())}, template, w)
+	return result.SourceMap, err
+}
+
+// checkOutsideWorkspace reports generator errors for templates that are outside of the
+// workspace. gopls would reject their Go code, so it isn't sent.
+func (p *Server) checkOutsideWorkspace(ctx context.Context, uri uri.URI, template parser.TemplateFile) error {
+	p.Log.Info("document is outside of the workspace, not sending to gopls", zap.String("uri", string(uri)))
+	if _, err := generate(uri, template, io.Discard); err != nil {
+		return p.publishGeneratorError(ctx, uri, err)
+	}
+	return nil
+}
+
+// publishGeneratorError notifies the end user that the Go code couldn't be generated.
+func (p *Server) publishGeneratorError(ctx context.Context, uri uri.URI, err error) error {
+	d := lsp.Diagnostic{
+		Severity: lsp.DiagnosticSeverityError,
+		Source:   "templ-generator",
+		Message:  err.Error(),
+	}
+	var ge generator.Error
+	if errors.As(err, &ge) {
+		d.Message = ge.Err.Error()
+		d.Range = lsp.Range{
+			Start: lsp.Position{Line: ge.Range.From.Line, Character: ge.Range.From.Col},
+			End:   lsp.Position{Line: ge.Range.To.Line, Character: ge.Range.To.Col},
+		}
+	}
+	return p.Client.PublishDiagnostics(ctx, &lsp.PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: []lsp.Diagnostic{d},
+	})
+}
+
+// parseErrorDiagnostics converts a parser error into diagnostics, with one diagnostic for
+// each error found in the file.
+func parseErrorDiagnostics(lines []string, err error) (diagnostics []lsp.Diagnostic) {
+	parsed, ok := parser.ParseErrorDiagnostics("", err)
+	if !ok {
+		return []lsp.Diagnostic{{
+			Severity: lsp.DiagnosticSeverityError,
+			Source:   "templ",
+			Message:  err.Error(),
+		}}
+	}
+	for _, d := range parsed {
+		diagnostics = append(diagnostics, lspDiagnostic(lines, d, "templ"))
+	}
+	return diagnostics
+}
+
+// lintDiagnostics converts the issues found by the parser's validation pass into diagnostics.
+func lintDiagnostics(templateText string, issues []parser.Issue) (diagnostics []lsp.Diagnostic) {
+	lines := strings.Split(templateText, "\n")
+	for _, issue := range issues {
+		diagnostics = append(diagnostics, lintDiagnostic(lines, issue))
+	}
+	return diagnostics
+}
+
+func lintDiagnostic(lines []string, issue parser.Issue) lsp.Diagnostic {
+	d := lspDiagnostic(lines, issue.Diagnostic(""), "templ-lint")
+	// Editors fade out content that's never rendered.
+	if issue.Unnecessary {
+		d.Tags = []lsp.DiagnosticTag{lsp.DiagnosticTagUnnecessary}
+	}
+	return d
+}
+
+// lspDiagnostic converts a diagnostic found by the parser, so that the editor shows the same
+// range, code and message as templ generate and templ lint -json.
+func lspDiagnostic(lines []string, d parser.Diagnostic, source string) lsp.Diagnostic {
+	severity := lsp.DiagnosticSeverityWarning
+	switch d.Severity {
+	case parser.SeverityError:
+		severity = lsp.DiagnosticSeverityError
+	case parser.SeverityHint:
+		severity = lsp.DiagnosticSeverityHint
+	}
+	return lsp.Diagnostic{
+		Severity: severity,
+		Source:   source,
+		Code:     d.Code,
+		Message:  d.Message,
+		Range:    lspRange(lines, d.Range),
+	}
+}
+
+// lspRange converts a range of the templ file, whose columns are in bytes, to a range whose
+// columns are in UTF-16 code units.
+func lspRange(lines []string, r parser.Range) lsp.Range {
+	position := func(pos parser.Position) lsp.Position {
+		var line string
+		if int(pos.Line) < len(lines) {
+			line = lines[pos.Line]
+		}
+		return lsp.Position{Line: pos.Line, Character: parser.UTF16Col(line, pos.Col)}
+	}
+	return lsp.Range{Start: position(r.From), End: position(r.To)}
+}
+
 // parseTemplate parses the templ file content, and notifies the end user via the LSP about how it went.
 func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText string) (template parser.TemplateFile, ok bool, err error) {
-	template, err = parser.ParseString(templateText)
+	lintSetter, mergesLint := p.Client.(lintDiagnosticsSetter)
+	template, err = parseString(templateText)
 	if err != nil {
-		msg := &lsp.PublishDiagnosticsParams{
-			URI: uri,
-			Diagnostics: []lsp.Diagnostic{
-				{
-					Severity: lsp.DiagnosticSeverityError,
-					Code:     "",
-					Source:   "templ",
-					Message:  err.Error(),
-				},
-			},
+		if mergesLint {
+			lintSetter.SetLintDiagnostics(uri, nil)
 		}
-		if pe, isParserError := err.(parse.ParseError); isParserError {
-			msg.Diagnostics[0].Range = lsp.Range{
-				Start: lsp.Position{
-					Line:      uint32(pe.Pos.Line),
-					Character: uint32(pe.Pos.Col),
-				},
-				End: lsp.Position{
-					Line:      uint32(pe.Pos.Line),
-					Character: uint32(pe.Pos.Col),
-				},
-			}
+		msg := &lsp.PublishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: parseErrorDiagnostics(strings.Split(templateText, "\n"), err),
 		}
 		err = p.Client.PublishDiagnostics(ctx, msg)
 		if err != nil {
@@ -149,10 +356,18 @@ func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText st
 		return
 	}
 	ok = true
-	// Clear diagnostics.
+	// Clear diagnostics, leaving only the lint warnings.
+	diagnostics := lintDiagnostics(templateText, p.validate(uri, template))
+	if mergesLint {
+		lintSetter.SetLintDiagnostics(uri, diagnostics)
+		diagnostics = nil
+	}
+	if diagnostics == nil {
+		diagnostics = []lsp.Diagnostic{}
+	}
 	err = p.Client.PublishDiagnostics(ctx, &lsp.PublishDiagnosticsParams{
 		URI:         uri,
-		Diagnostics: []lsp.Diagnostic{},
+		Diagnostics: diagnostics,
 	})
 	if err != nil {
 		p.Log.Error("failed to publish diagnostics", zap.Error(err))
@@ -164,31 +379,135 @@ func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText st
 func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (result *lsp.InitializeResult, err error) {
 	p.Log.Info("client -> server: Initialize")
 	defer p.Log.Info("client -> server: Initialize end")
+	p.ClientCapabilities = params.Capabilities
+	p.workspaceFolders.Add(string(params.RootURI))
+	p.workspaceFolders.Add(workspaceFolderURIs(params.WorkspaceFolders)...)
+	// The client's capabilities are passed to gopls untouched, so that it can register
+	// file watchers, and send code lenses etc.
 	result, err = p.Target.Initialize(ctx, params)
 	if err != nil {
 		p.Log.Error("Initialize failed", zap.Error(err))
+		return nil, err
 	}
-	// Add the '<' and '{' trigger so that we can do snippets for tags.
 	if result.Capabilities.CompletionProvider == nil {
 		result.Capabilities.CompletionProvider = &lsp.CompletionOptions{}
 	}
-	result.Capabilities.CompletionProvider.TriggerCharacters = append(result.Capabilities.CompletionProvider.TriggerCharacters, "{", "<")
-	// Remove all the gopls commands.
-	if result.Capabilities.ExecuteCommandProvider == nil {
-		result.Capabilities.ExecuteCommandProvider = &lsp.ExecuteCommandOptions{}
-	}
-	result.Capabilities.ExecuteCommandProvider.Commands = []string{}
-	result.Capabilities.DocumentFormattingProvider = true
+	// Add the '<' and '{' trigger so that we can do snippets for tags.
+	// If the client supports it, completion is registered in Initialized instead, once for
+	// templ files with their triggers, and once for Go files with gopls's triggers. Clients
+	// would otherwise have two completion providers for templ files, and send two requests
+	// each time completion is triggered.
+	if p.completionDynamicRegistration() {
+		p.goCompletion = *result.Capabilities.CompletionProvider
+		result.Capabilities.CompletionProvider = nil
+	} else {
+		result.Capabilities.CompletionProvider.TriggerCharacters = append(result.Capabilities.CompletionProvider.TriggerCharacters, templTriggerCharacters...)
+	}
+	if result.Capabilities.ExecuteCommandProvider != nil {
+		result.Capabilities.ExecuteCommandProvider.Commands = templCommands(result.Capabilities.ExecuteCommandProvider.Commands)
+	}
+	// templ provides these for templ files, and passes other files through to gopls.
+	result.Capabilities.DocumentFormattingProvider = enableProvider(result.Capabilities.DocumentFormattingProvider)
+	result.Capabilities.SelectionRangeProvider = enableProvider(result.Capabilities.SelectionRangeProvider)
+	result.Capabilities.LinkedEditingRangeProvider = enableProvider(result.Capabilities.LinkedEditingRangeProvider)
+	result.Capabilities.DocumentSymbolProvider = enableProvider(result.Capabilities.DocumentSymbolProvider)
+	result.Capabilities.ColorProvider = enableProvider(result.Capabilities.ColorProvider)
+	// The semantic tokens of the generated Go code can't be mapped to templ files.
 	result.Capabilities.SemanticTokensProvider = nil
+	// Editor plugins show the version of templ, rather than gopls.
+	result.ServerInfo = &lsp.ServerInfo{
+		Name:    "templ",
+		Version: generator.Version(),
+	}
 	return result, err
 }
 
 func (p *Server) Initialized(ctx context.Context, params *lsp.InitializedParams) (err error) {
 	p.Log.Info("client -> server: Initialized")
 	defer p.Log.Info("client -> server: Initialized end")
+	if p.completionDynamicRegistration() {
+		err = p.Client.RegisterCapability(ctx, &lsp.RegistrationParams{
+			Registrations: completionRegistrations(p.goCompletion),
+		})
+		if err != nil {
+			p.Log.Error("failed to register completion providers", zap.Error(err))
+		}
+	}
+	if p.watchedFilesDynamicRegistration() {
+		err = p.Client.RegisterCapability(ctx, &lsp.RegistrationParams{
+			Registrations: []lsp.Registration{
+				{
+					ID:     "templ-config",
+					Method: "workspace/didChangeWatchedFiles",
+					RegisterOptions: lsp.DidChangeWatchedFilesRegistrationOptions{
+						Watchers: []lsp.FileSystemWatcher{configWatcher},
+					},
+				},
+			},
+		})
+		if err != nil {
+			p.Log.Error("failed to register templ.json watcher", zap.Error(err))
+		}
+	}
+	for _, dir := range p.workspaceFolders.Paths() {
+		p.checkConfig(ctx, dir)
+	}
 	return p.Target.Initialized(ctx, params)
 }
 
+// templTriggerCharacters are the completion trigger characters used within templ files.
+// "/" completes end tags, after "</" has been typed.
+var templTriggerCharacters = []string{"{", "<", "/"}
+
+// completionRegistrations returns the completion providers of templ and Go files. Expressions
+// in templ files are completed by gopls, so templ files have gopls's triggers too.
+func completionRegistrations(goCompletion lsp.CompletionOptions) []lsp.Registration {
+	templTriggers := append([]string{}, goCompletion.TriggerCharacters...)
+	seen := make(map[string]bool, len(templTriggers))
+	for _, c := range templTriggers {
+		seen[c] = true
+	}
+	for _, c := range templTriggerCharacters {
+		if !seen[c] {
+			templTriggers = append(templTriggers, c)
+		}
+	}
+	return []lsp.Registration{
+		{
+			ID:     "templ-completion",
+			Method: "textDocument/completion",
+			RegisterOptions: lsp.CompletionRegistrationOptions{
+				TextDocumentRegistrationOptions: lsp.TextDocumentRegistrationOptions{
+					DocumentSelector: lsp.DocumentSelector{{Pattern: "**/*.templ"}},
+				},
+				TriggerCharacters: templTriggers,
+				ResolveProvider:   goCompletion.ResolveProvider,
+			},
+		},
+		{
+			ID:     "go-completion",
+			Method: "textDocument/completion",
+			RegisterOptions: lsp.CompletionRegistrationOptions{
+				TextDocumentRegistrationOptions: lsp.TextDocumentRegistrationOptions{
+					DocumentSelector: lsp.DocumentSelector{{Pattern: "**/*.go"}},
+				},
+				TriggerCharacters: goCompletion.TriggerCharacters,
+				ResolveProvider:   goCompletion.ResolveProvider,
+			},
+		},
+	}
+}
+
+// completionDynamicRegistration returns true if the client supports dynamic registration
+// of completion providers, which allows the trigger characters to be scoped to templ files.
+func (p *Server) completionDynamicRegistration() bool {
+	td := p.ClientCapabilities.TextDocument
+	if td == nil || td.Completion == nil {
+		return false
+	}
+	return td.Completion.DynamicRegistration
+}
+
 func (p *Server) Shutdown(ctx context.Context) (err error) {
 	p.Log.Info("client -> server: Shutdown")
 	defer p.Log.Info("client -> server: Shutdown end")
@@ -222,41 +541,73 @@ func (p *Server) SetTrace(ctx context.Context, params *lsp.SetTraceParams) (err
 func (p *Server) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (result []lsp.CodeAction, err error) {
 	p.Log.Info("client -> server: CodeAction")
 	defer p.Log.Info("client -> server: CodeAction end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
-		return p.Target.CodeAction(ctx, params)
+		result, err = p.Target.CodeAction(ctx, params)
+		for i := 0; i < len(result); i++ {
+			namespaceCommand(result[i].Command)
+		}
+		return
 	}
 	templURI := params.TextDocument.URI
+	fixes := p.lintFixes(templURI, params)
 	params.TextDocument.URI = goURI
+	// Rewrite the request range and the diagnostics in the context, so that gopls can
+	// match them against its own diagnostics.
+	params.Range = p.convertTemplRangeToGoRange(templURI, params.Range)
+	for di := 0; di < len(params.Context.Diagnostics); di++ {
+		params.Context.Diagnostics[di].Range = p.convertTemplRangeToGoRange(templURI, params.Context.Diagnostics[di].Range)
+	}
 	result, err = p.Target.CodeAction(ctx, params)
 	if err != nil {
 		return
 	}
 	for i := 0; i < len(result); i++ {
 		r := result[i]
+		namespaceCommand(r.Command)
 		// Rewrite the Diagnostics range field.
 		for di := 0; di < len(r.Diagnostics); di++ {
 			r.Diagnostics[di].Range = p.convertGoRangeToTemplRange(templURI, r.Diagnostics[di].Range)
 		}
+		if r.Edit == nil {
+			continue
+		}
+		// Rewrite the Changes.
+		if edits, ok := r.Edit.Changes[goURI]; ok {
+			for ei := 0; ei < len(edits); ei++ {
+				edits[ei].Range = p.convertGoRangeToTemplRange(templURI, edits[ei].Range)
+			}
+			delete(r.Edit.Changes, goURI)
+			r.Edit.Changes[templURI] = edits
+		}
 		// Rewrite the DocumentChanges.
 		for dci := 0; dci < len(r.Edit.DocumentChanges); dci++ {
-			dc := r.Edit.DocumentChanges[0]
+			dc := r.Edit.DocumentChanges[dci]
+			if dc.TextDocument.URI != goURI {
+				continue
+			}
 			for ei := 0; ei < len(dc.Edits); ei++ {
 				dc.Edits[ei].Range = p.convertGoRangeToTemplRange(templURI, dc.Edits[ei].Range)
 			}
 			dc.TextDocument.URI = templURI
+			r.Edit.DocumentChanges[dci] = dc
 		}
 		result[i] = r
 	}
+	result = append(result, fixes...)
 	return
 }
 
 func (p *Server) CodeLens(ctx context.Context, params *lsp.CodeLensParams) (result []lsp.CodeLens, err error) {
 	p.Log.Info("client -> server: CodeLens")
 	defer p.Log.Info("client -> server: CodeLens end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
-		return p.Target.CodeLens(ctx, params)
+		result, err = p.Target.CodeLens(ctx, params)
+		for i := 0; i < len(result); i++ {
+			namespaceCommand(result[i].Command)
+		}
+		return
 	}
 	templURI := params.TextDocument.URI
 	params.TextDocument.URI = goURI
@@ -270,6 +621,7 @@ func (p *Server) CodeLens(ctx context.Context, params *lsp.CodeLensParams) (resu
 	for i := 0; i < len(result); i++ {
 		cl := result[i]
 		cl.Range = p.convertGoRangeToTemplRange(templURI, cl.Range)
+		namespaceCommand(cl.Command)
 		result[i] = cl
 	}
 	return
@@ -278,50 +630,78 @@ func (p *Server) CodeLens(ctx context.Context, params *lsp.CodeLensParams) (resu
 func (p *Server) CodeLensResolve(ctx context.Context, params *lsp.CodeLens) (result *lsp.CodeLens, err error) {
 	p.Log.Info("client -> server: CodeLensResolve")
 	defer p.Log.Info("client -> server: CodeLensResolve end")
-	return p.Target.CodeLensResolve(ctx, params)
+	if params.Command != nil {
+		params.Command.Command = goplsCommand(params.Command.Command)
+	}
+	result, err = p.Target.CodeLensResolve(ctx, params)
+	if result != nil {
+		namespaceCommand(result.Command)
+	}
+	return
 }
 
 func (p *Server) ColorPresentation(ctx context.Context, params *lsp.ColorPresentationParams) (result []lsp.ColorPresentation, err error) {
 	p.Log.Info("client -> server: ColorPresentation ColorPresentation")
 	defer p.Log.Info("client -> server: ColorPresentation end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
-	if !isTemplFile {
+	if isTemplFile, _ := p.URIs.TemplToGo(params.TextDocument.URI); !isTemplFile {
 		return p.Target.ColorPresentation(ctx, params)
 	}
-	templURI := params.TextDocument.URI
-	params.TextDocument.URI = goURI
-	result, err = p.Target.ColorPresentation(ctx, params)
-	if err != nil {
-		return
-	}
-	if result == nil {
-		return
-	}
-	for i := 0; i < len(result); i++ {
-		r := result[i]
-		if r.TextEdit != nil {
-			r.TextEdit.Range = p.convertGoRangeToTemplRange(templURI, r.TextEdit.Range)
-		}
-		result[i] = r
-	}
-	return
+	// The colors of templ files are within CSS, which gopls knows nothing about.
+	return colorPresentations(params.Color, params.Range), nil
 }
 
 func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (result *lsp.CompletionList, err error) {
 	p.Log.Info("client -> server: Completion")
 	defer p.Log.Info("client -> server: Completion end")
+	// Requests for other files are passed through untouched.
+	if isTemplFile, _ := p.URIs.TemplToGo(params.TextDocument.URI); !isTemplFile {
+		return p.Target.Completion(ctx, params)
+	}
 	if params.Context != nil && params.Context.TriggerCharacter == "<" {
 		result = &lsp.CompletionList{
 			Items: htmlSnippets,
 		}
+		if !p.snippetSupport() {
+			result.Items = snippetsAsPlainText(result.Items)
+		}
 		return
 	}
-	// Get the sourcemap from the cache.
 	templURI := params.TextDocument.URI
+	var ip importPath
+	var packages []string
+	// CSS templates and end tags are completed without calling gopls.
+	if doc, ok := p.TemplSource.Get(string(templURI)); ok {
+		if items, ok := cssCompletion(doc.Lines, params.Position); ok {
+			return &lsp.CompletionList{Items: items}, nil
+		}
+		if items, ok := endTagCompletion(doc.Lines, params.Position); ok {
+			return &lsp.CompletionList{Items: items}, nil
+		}
+		// Import paths are completed by gopls, but the packages of the module are listed first.
+		var isImportPath bool
+		if ip, isImportPath = importPathAt(doc.Lines, params.Position); isImportPath {
+			var listErr error
+			if packages, listErr = modulePackages(filepath.Dir(templURI.Filename()), ip.Prefix); listErr != nil {
+				p.Log.Warn("completion: failed to list the packages of the module", zap.Error(listErr))
+			}
+		}
+	}
+	// Get the sourcemap from the cache.
+	templPosition := params.TextDocumentPositionParams.Position
 	var ok bool
 	ok, params.TextDocument.URI, params.TextDocumentPositionParams.Position = p.updatePosition(templURI, params.TextDocumentPositionParams.Position)
 	if !ok {
-		return nil, nil
+		// Positions that aren't in Go code may be the start of a templ statement.
+		if doc, ok := p.TemplSource.Get(string(templURI)); ok {
+			if items, ok := statementCompletion(doc.Lines, templPosition); ok {
+				result = &lsp.CompletionList{Items: items}
+				if !p.snippetSupport() {
+					result.Items = snippetsAsPlainText(result.Items)
+				}
+				return result, nil
+			}
+		}
+		return withModulePackages(nil, ip, packages), nil
 	}
 	// Call the target.
 	result, err = p.Target.Completion(ctx, params)
@@ -330,7 +710,7 @@ func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (
 		return
 	}
 	if result == nil {
-		return
+		return withModulePackages(nil, ip, packages), nil
 	}
 	// Rewrite the result positions.
 	p.Log.Info("completion: received items", zap.Int("count", len(result.Items)))
@@ -358,7 +738,17 @@ func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (
 		}
 		result.Items[i] = item
 	}
-	return
+	return withModulePackages(result, ip, packages), nil
+}
+
+// snippetSupport returns true if the client declared that it can expand snippet
+// placeholders in completion items.
+func (p *Server) snippetSupport() bool {
+	td := p.ClientCapabilities.TextDocument
+	if td == nil || td.Completion == nil || td.Completion.CompletionItem == nil {
+		return false
+	}
+	return td.Completion.CompletionItem.SnippetSupport
 }
 
 var completionWithImport = regexp.MustCompile(`^.*\(from\s(".+")\)$`)
@@ -377,6 +767,10 @@ type importInsert struct {
 
 var nonImportKeywordRegexp = regexp.MustCompile(`^(?:templ|func|css|script|var|const|type)\s`)
 
+// singleLineImportRegexp matches single-line imports, including aliased and blank imports,
+// e.g. import h "github.com/foo/html" or import _ "embed".
+var singleLineImportRegexp = regexp.MustCompile(`^import\s+(?:[\w.]+\s+)?"`)
+
 func addImport(lines []string, pkg string) (result importInsert) {
 	var isInMultiLineImport bool
 	lastSingleLineImportIndex := -1
@@ -385,7 +779,7 @@ func addImport(lines []string, pkg string) (result importInsert) {
 			isInMultiLineImport = true
 			continue
 		}
-		if strings.HasPrefix(line, "import \"") {
+		if singleLineImportRegexp.MatchString(line) {
 			lastSingleLineImportIndex = lineIndex
 			continue
 		}
@@ -436,10 +830,7 @@ func (p *Server) Declaration(ctx context.Context, params *lsp.DeclarationParams)
 		return
 	}
 	for i := 0; i < len(result); i++ {
-		if isTemplGoFile, templURI := convertTemplGoToTemplURI(result[i].URI); isTemplGoFile {
-			result[i].URI = templURI
-			result[i].Range = p.convertGoRangeToTemplRange(templURI, result[i].Range)
-		}
+		result[i] = p.convertGoLocationToTemplLocation(result[i])
 	}
 	return
 }
@@ -463,10 +854,7 @@ func (p *Server) Definition(ctx context.Context, params *lsp.DefinitionParams) (
 		return
 	}
 	for i := 0; i < len(result); i++ {
-		if isTemplGoFile, templURI := convertTemplGoToTemplURI(result[i].URI); isTemplGoFile {
-			result[i].URI = templURI
-			result[i].Range = p.convertGoRangeToTemplRange(templURI, result[i].Range)
-		}
+		result[i] = p.convertGoLocationToTemplLocation(result[i])
 	}
 	return
 }
@@ -474,13 +862,30 @@ func (p *Server) Definition(ctx context.Context, params *lsp.DefinitionParams) (
 func (p *Server) DidChange(ctx context.Context, params *lsp.DidChangeTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidChange", zap.Any("params", params))
 	defer p.Log.Info("client -> server: DidChange end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		p.Log.Error("not a templ file")
 		return
 	}
 	// Apply content changes to the cached template.
-	d, err := p.TemplSource.Apply(string(params.TextDocument.URI), params.ContentChanges)
+	d, err := p.TemplSource.Apply(string(params.TextDocument.URI), params.TextDocument.Version, params.ContentChanges)
+	var ve VersionError
+	if errors.As(err, &ve) {
+		// Replacing the document with the file on disk would lose the edits in the editor
+		// that haven't been saved, so the changes are ignored instead.
+		if p.TemplSource.Unsaved(string(params.TextDocument.URI)) {
+			p.Log.Warn("document version is out of sequence, ignoring the changes",
+				zap.String("uri", string(params.TextDocument.URI)),
+				zap.Int32("currentVersion", ve.Current),
+				zap.Int32("receivedVersion", ve.Received))
+			return nil
+		}
+		p.Log.Warn("document version is out of sequence, reloading the document from disk",
+			zap.String("uri", string(params.TextDocument.URI)),
+			zap.Int32("currentVersion", ve.Current),
+			zap.Int32("receivedVersion", ve.Received))
+		d, err = p.reloadDocument(params.TextDocument.URI, params.TextDocument.Version)
+	}
 	if err != nil {
 		p.Log.Error("error applying changes", zap.Error(err))
 		return
@@ -494,16 +899,23 @@ func (p *Server) DidChange(ctx context.Context, params *lsp.DidChangeTextDocumen
 	if !ok {
 		return
 	}
+	if !p.workspaceFolders.Contains(params.TextDocument.URI) {
+		return p.checkOutsideWorkspace(ctx, params.TextDocument.URI, template)
+	}
 	w := new(strings.Builder)
-	sm, err := generator.Generate(template, w)
+	sm, err := generate(params.TextDocument.URI, template, w)
 	if err != nil {
 		p.Log.Error("generate failure", zap.Error(err))
-		return
+		// gopls still has the previous Go code, which no longer matches the template, so
+		// the source map can't be used to rewrite positions until generation succeeds.
+		p.SourceMapCache.SetOutdated(string(params.TextDocument.URI))
+		return p.publishGeneratorError(ctx, params.TextDocument.URI, err)
 	}
+	sm.SetText(d.String(), w.String())
 	// Cache the sourcemap.
 	p.Log.Info("setting cache", zap.String("uri", string(params.TextDocument.URI)))
 	p.SourceMapCache.Set(string(params.TextDocument.URI), sm)
-	p.GoSource[string(params.TextDocument.URI)] = w.String()
+	p.setGoSource(string(params.TextDocument.URI), w.String())
 	// Change the path.
 	params.TextDocument.URI = goURI
 	params.TextDocument.TextDocumentIdentifier.URI = goURI
@@ -514,6 +926,20 @@ func (p *Server) DidChange(ctx context.Context, params *lsp.DidChangeTextDocumen
 	return p.Target.DidChange(ctx, params)
 }
 
+// reloadDocument replaces the cached template with the file on disk, when the changes from the
+// client can't be applied to it, and the document has no unsaved edits. There's no way to
+// request the contents of a document from the client, so the file on disk is the best guess at
+// what the editor has, e.g. after the buffer has been reloaded.
+func (p *Server) reloadDocument(templURI lsp.DocumentURI, version int32) (d *Document, err error) {
+	contents, err := os.ReadFile(templURI.Filename())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload the document from disk: %w", err)
+	}
+	d = NewDocument(p.Log, string(contents))
+	p.TemplSource.Set(string(templURI), version, d)
+	return d.copy(), nil
+}
+
 func (p *Server) DidChangeConfiguration(ctx context.Context, params *lsp.DidChangeConfigurationParams) (err error) {
 	p.Log.Info("client -> server: DidChangeConfiguration")
 	defer p.Log.Info("client -> server: DidChangeConfiguration end")
@@ -523,25 +949,45 @@ func (p *Server) DidChangeConfiguration(ctx context.Context, params *lsp.DidChan
 func (p *Server) DidChangeWatchedFiles(ctx context.Context, params *lsp.DidChangeWatchedFilesParams) (err error) {
 	p.Log.Info("client -> server: DidChangeWatchedFiles")
 	defer p.Log.Info("client -> server: DidChangeWatchedFiles end")
+	for _, change := range params.Changes {
+		if isConfigFile(change.URI) {
+			p.reloadConfig(ctx, change.URI)
+			continue
+		}
+		if !p.closedDocuments.Contains(change.URI) {
+			continue
+		}
+		if err = p.publishFromDisk(ctx, change.URI); err != nil {
+			p.Log.Error("failed to publish diagnostics from disk", zap.Error(err))
+		}
+	}
 	return p.Target.DidChangeWatchedFiles(ctx, params)
 }
 
 func (p *Server) DidChangeWorkspaceFolders(ctx context.Context, params *lsp.DidChangeWorkspaceFoldersParams) (err error) {
 	p.Log.Info("client -> server: DidChangeWorkspaceFolders")
 	defer p.Log.Info("client -> server: DidChangeWorkspaceFolders end")
+	p.workspaceFolders.Remove(workspaceFolderURIs(params.Event.Removed)...)
+	p.workspaceFolders.Add(workspaceFolderURIs(params.Event.Added)...)
 	return p.Target.DidChangeWorkspaceFolders(ctx, params)
 }
 
 func (p *Server) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidClose")
 	defer p.Log.Info("client -> server: DidClose end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.DidClose(ctx, params)
 	}
-	// Delete the template and sourcemaps from caches.
+	// Delete the template from the cache, and keep the diagnostics from the file on disk.
 	p.TemplSource.Delete(string(params.TextDocument.URI))
-	p.SourceMapCache.Delete(string(params.TextDocument.URI))
+	p.deleteGoSource(string(params.TextDocument.URI))
+	if err = p.publishFromDisk(ctx, params.TextDocument.URI); err != nil {
+		p.Log.Error("failed to publish diagnostics from disk", zap.Error(err))
+	}
+	if !p.workspaceFolders.Contains(params.TextDocument.URI) {
+		return nil
+	}
 	// Get gopls to delete the Go file from its cache.
 	params.TextDocument.URI = goURI
 	return p.Target.DidClose(ctx, params)
@@ -550,12 +996,13 @@ func (p *Server) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentP
 func (p *Server) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidOpen", zap.String("uri", string(params.TextDocument.URI)))
 	defer p.Log.Info("client -> server: DidOpen end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.DidOpen(ctx, params)
 	}
+	p.closedDocuments.Remove(params.TextDocument.URI)
 	// Cache the template doc.
-	p.TemplSource.Set(string(params.TextDocument.URI), NewDocument(p.Log, params.TextDocument.Text))
+	p.TemplSource.Set(string(params.TextDocument.URI), params.TextDocument.Version, NewDocument(p.Log, params.TextDocument.Text))
 	// Parse the template.
 	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, params.TextDocument.Text)
 	if err != nil {
@@ -565,18 +1012,24 @@ func (p *Server) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentPar
 		p.Log.Info("parsing template did not succeed", zap.String("uri", string(params.TextDocument.URI)))
 		return nil
 	}
+	if !p.workspaceFolders.Contains(params.TextDocument.URI) {
+		return p.checkOutsideWorkspace(ctx, params.TextDocument.URI, template)
+	}
 	// Generate the output code and cache the source map and Go contents to use during completion
 	// requests.
 	w := new(strings.Builder)
-	sm, err := generator.Generate(template, w)
+	sm, err := generate(params.TextDocument.URI, template, w)
 	if err != nil {
-		return
+		p.Log.Error("generate failure", zap.Error(err))
+		p.SourceMapCache.SetOutdated(string(params.TextDocument.URI))
+		return p.publishGeneratorError(ctx, params.TextDocument.URI, err)
 	}
+	sm.SetText(params.TextDocument.Text, w.String())
 	p.Log.Info("setting source map cache contents", zap.String("uri", string(params.TextDocument.URI)))
 	p.SourceMapCache.Set(string(params.TextDocument.URI), sm)
 	// Set the Go contents.
 	params.TextDocument.Text = w.String()
-	p.GoSource[string(params.TextDocument.URI)] = params.TextDocument.Text
+	p.setGoSource(string(params.TextDocument.URI), params.TextDocument.Text)
 	// Change the path.
 	params.TextDocument.URI = goURI
 	return p.Target.DidOpen(ctx, params)
@@ -585,7 +1038,17 @@ func (p *Server) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentPar
 func (p *Server) DidSave(ctx context.Context, params *lsp.DidSaveTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidSave")
 	defer p.Log.Info("client -> server: DidSave end")
-	if isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI); isTemplFile {
+	if isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI); isTemplFile {
+		p.TemplSource.Saved(string(params.TextDocument.URI))
+		// Saving is a natural point to show the latest diagnostics without delay.
+		if f, ok := p.Client.(diagnosticsFlusher); ok {
+			if err = f.FlushDiagnostics(ctx, params.TextDocument.URI); err != nil {
+				p.Log.Error("failed to flush diagnostics", zap.Error(err))
+			}
+		}
+		if !p.workspaceFolders.Contains(params.TextDocument.URI) {
+			return nil
+		}
 		params.TextDocument.URI = goURI
 	}
 	return p.Target.DidSave(ctx, params)
@@ -594,29 +1057,25 @@ func (p *Server) DidSave(ctx context.Context, params *lsp.DidSaveTextDocumentPar
 func (p *Server) DocumentColor(ctx context.Context, params *lsp.DocumentColorParams) (result []lsp.ColorInformation, err error) {
 	p.Log.Info("client -> server: DocumentColor")
 	defer p.Log.Info("client -> server: DocumentColor end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
-	if !isTemplFile {
+	if isTemplFile, _ := p.URIs.TemplToGo(params.TextDocument.URI); !isTemplFile {
 		return p.Target.DocumentColor(ctx, params)
 	}
-	templURI := params.TextDocument.URI
-	params.TextDocument.URI = goURI
-	result, err = p.Target.DocumentColor(ctx, params)
-	if err != nil {
-		return
-	}
-	if result == nil {
-		return
+	doc, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return nil, fmt.Errorf("document not found: %s", params.TextDocument.URI)
 	}
-	for i := 0; i < len(result); i++ {
-		result[i].Range = p.convertGoRangeToTemplRange(templURI, result[i].Range)
+	result, err = documentColors(doc.Lines)
+	if err != nil {
+		p.Log.Info("documentColor: failed to parse template", zap.Error(err))
+		return []lsp.ColorInformation{}, nil
 	}
-	return
+	return result, nil
 }
 
 func (p *Server) DocumentHighlight(ctx context.Context, params *lsp.DocumentHighlightParams) (result []lsp.DocumentHighlight, err error) {
 	p.Log.Info("client -> server: DocumentHighlight")
 	defer p.Log.Info("client -> server: DocumentHighlight end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.DocumentHighlight(ctx, params)
 	}
@@ -644,7 +1103,7 @@ func (p *Server) DocumentLink(ctx context.Context, params *lsp.DocumentLinkParam
 func (p *Server) DocumentLinkResolve(ctx context.Context, params *lsp.DocumentLink) (result *lsp.DocumentLink, err error) {
 	p.Log.Info("client -> server: DocumentLinkResolve")
 	defer p.Log.Info("client -> server: DocumentLinkResolve end")
-	isTemplFile, goURI := convertTemplToGoURI(params.Target)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.Target)
 	if !isTemplFile {
 		return p.Target.DocumentLinkResolve(ctx, params)
 	}
@@ -667,14 +1126,29 @@ func (p *Server) DocumentLinkResolve(ctx context.Context, params *lsp.DocumentLi
 func (p *Server) DocumentSymbol(ctx context.Context, params *lsp.DocumentSymbolParams) (result []interface{} /* []SymbolInformation | []DocumentSymbol */, err error) {
 	p.Log.Info("client -> server: DocumentSymbol")
 	defer p.Log.Info("client -> server: DocumentSymbol end")
-	//TODO: Rewrite the request and response, but for now, ignore it.
-	//return p.Target.DocumentSymbol(ctx params)
+	if isTemplFile, _ := p.URIs.TemplToGo(params.TextDocument.URI); !isTemplFile {
+		return p.Target.DocumentSymbol(ctx, params)
+	}
+	// The symbols of templ files are read from the template, without calling gopls.
+	doc, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return
+	}
+	symbols, err := documentSymbols(doc.Lines)
+	if err != nil {
+		p.Log.Info("documentSymbol: failed to parse template", zap.Error(err))
+		return nil, nil
+	}
+	for _, s := range symbols {
+		result = append(result, s)
+	}
 	return
 }
 
 func (p *Server) ExecuteCommand(ctx context.Context, params *lsp.ExecuteCommandParams) (result interface{}, err error) {
 	p.Log.Info("client -> server: ExecuteCommand")
 	defer p.Log.Info("client -> server: ExecuteCommand end")
+	params.Command = goplsCommand(params.Command)
 	return p.Target.ExecuteCommand(ctx, params)
 }
 
@@ -719,8 +1193,18 @@ func (p *Server) Formatting(ctx context.Context, params *lsp.DocumentFormattingP
 func (p *Server) Hover(ctx context.Context, params *lsp.HoverParams) (result *lsp.Hover, err error) {
 	p.Log.Info("client -> server: Hover")
 	defer p.Log.Info("client -> server: Hover end")
-	// Rewrite the request.
 	templURI := params.TextDocument.URI
+	// CSS property names are described without calling gopls.
+	if doc, ok := p.TemplSource.Get(string(templURI)); ok {
+		if result, ok := cssHover(doc.Lines, params.Position); ok {
+			return result, nil
+		}
+		// HTML element and attribute names are also described without calling gopls.
+		if result, ok := htmlHover(doc.Lines, params.Position); ok {
+			return result, nil
+		}
+	}
+	// Rewrite the request.
 	var ok bool
 	ok, params.TextDocument.URI, params.Position = p.updatePosition(params.TextDocument.URI, params.Position)
 	if !ok {
@@ -823,7 +1307,7 @@ func (p *Server) RangeFormatting(ctx context.Context, params *lsp.DocumentRangeF
 	templURI := params.TextDocument.URI
 	// Rewrite the request.
 	var isTemplURI bool
-	isTemplURI, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
+	isTemplURI, params.TextDocument.URI = p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplURI {
 		err = fmt.Errorf("not a templ file")
 		return
@@ -848,7 +1332,7 @@ func (p *Server) References(ctx context.Context, params *lsp.ReferenceParams) (r
 	templURI := params.TextDocument.URI
 	// Rewrite the request.
 	var isTemplURI bool
-	isTemplURI, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
+	isTemplURI, params.TextDocument.URI = p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplURI {
 		err = fmt.Errorf("not a templ file")
 		return
@@ -906,7 +1390,7 @@ func (p *Server) WillSave(ctx context.Context, params *lsp.WillSaveTextDocumentP
 	p.Log.Info("client -> server: WillSave")
 	defer p.Log.Info("client -> server: WillSave end")
 	var ok bool
-	ok, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
+	ok, params.TextDocument.URI = p.URIs.TemplToGo(params.TextDocument.URI)
 	if !ok {
 		p.Log.Error("not a templ file")
 		return nil
@@ -971,25 +1455,79 @@ func (p *Server) CodeLensRefresh(ctx context.Context) (err error) {
 func (p *Server) PrepareCallHierarchy(ctx context.Context, params *lsp.CallHierarchyPrepareParams) (result []lsp.CallHierarchyItem, err error) {
 	p.Log.Info("client -> server: PrepareCallHierarchy")
 	defer p.Log.Info("client -> server: PrepareCallHierarchy end")
-	return p.Target.PrepareCallHierarchy(ctx, params)
+	// Rewrite the request.
+	var ok bool
+	ok, params.TextDocument.URI, params.Position = p.updatePosition(params.TextDocument.URI, params.Position)
+	if !ok {
+		return nil, nil
+	}
+	items, err := p.Target.PrepareCallHierarchy(ctx, params)
+	if err != nil {
+		return
+	}
+	// Rewrite the response.
+	for _, item := range items {
+		if item, ok := p.convertGoCallHierarchyItemToTempl(item); ok {
+			result = append(result, item)
+		}
+	}
+	return
 }
 
 func (p *Server) IncomingCalls(ctx context.Context, params *lsp.CallHierarchyIncomingCallsParams) (result []lsp.CallHierarchyIncomingCall, err error) {
 	p.Log.Info("client -> server: IncomingCalls")
 	defer p.Log.Info("client -> server: IncomingCalls end")
-	return p.Target.IncomingCalls(ctx, params)
+	params.Item = p.convertTemplCallHierarchyItemToGo(params.Item)
+	calls, err := p.Target.IncomingCalls(ctx, params)
+	if err != nil {
+		return
+	}
+	for _, call := range calls {
+		// The ranges are within the calling function's file.
+		if isTemplGoFile, templURI := p.URIs.TemplGoToTempl(call.From.URI); isTemplGoFile {
+			if call.FromRanges = p.convertGoCallRangesToTempl(templURI, call.FromRanges); len(call.FromRanges) == 0 {
+				continue
+			}
+		}
+		var ok bool
+		if call.From, ok = p.convertGoCallHierarchyItemToTempl(call.From); !ok {
+			continue
+		}
+		result = append(result, call)
+	}
+	return
 }
 
 func (p *Server) OutgoingCalls(ctx context.Context, params *lsp.CallHierarchyOutgoingCallsParams) (result []lsp.CallHierarchyOutgoingCall, err error) {
 	p.Log.Info("client -> server: OutgoingCalls")
 	defer p.Log.Info("client -> server: OutgoingCalls end")
-	return p.Target.OutgoingCalls(ctx, params)
+	params.Item = p.convertTemplCallHierarchyItemToGo(params.Item)
+	calls, err := p.Target.OutgoingCalls(ctx, params)
+	if err != nil {
+		return
+	}
+	isTemplGoFile, templURI := p.URIs.TemplGoToTempl(params.Item.URI)
+	for _, call := range calls {
+		// The ranges are within the file of the item that was requested. Calls made by the
+		// generated code, e.g. to write to the output, are removed.
+		if isTemplGoFile {
+			if call.FromRanges = p.convertGoCallRangesToTempl(templURI, call.FromRanges); len(call.FromRanges) == 0 {
+				continue
+			}
+		}
+		var ok bool
+		if call.To, ok = p.convertGoCallHierarchyItemToTempl(call.To); !ok {
+			continue
+		}
+		result = append(result, call)
+	}
+	return
 }
 
 func (p *Server) SemanticTokensFull(ctx context.Context, params *lsp.SemanticTokensParams) (result *lsp.SemanticTokens, err error) {
 	p.Log.Info("client -> server: SemanticTokensFull")
 	defer p.Log.Info("client -> server: SemanticTokensFull end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		return nil, nil
 	}
@@ -1000,7 +1538,7 @@ func (p *Server) SemanticTokensFull(ctx context.Context, params *lsp.SemanticTok
 func (p *Server) SemanticTokensFullDelta(ctx context.Context, params *lsp.SemanticTokensDeltaParams) (result interface{} /* SemanticTokens | SemanticTokensDelta */, err error) {
 	p.Log.Info("client -> server: SemanticTokensFullDelta")
 	defer p.Log.Info("client -> server: SemanticTokensFullDelta end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		return nil, nil
 	}
@@ -1011,7 +1549,7 @@ func (p *Server) SemanticTokensFullDelta(ctx context.Context, params *lsp.Semant
 func (p *Server) SemanticTokensRange(ctx context.Context, params *lsp.SemanticTokensRangeParams) (result *lsp.SemanticTokens, err error) {
 	p.Log.Info("client -> server: SemanticTokensRange")
 	defer p.Log.Info("client -> server: SemanticTokensRange end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.URIs.TemplToGo(params.TextDocument.URI)
 	if !isTemplFile {
 		return nil, nil
 	}
@@ -1028,7 +1566,16 @@ func (p *Server) SemanticTokensRefresh(ctx context.Context) (err error) {
 func (p *Server) LinkedEditingRange(ctx context.Context, params *lsp.LinkedEditingRangeParams) (result *lsp.LinkedEditingRanges, err error) {
 	p.Log.Info("client -> server: LinkedEditingRange")
 	defer p.Log.Info("client -> server: LinkedEditingRange end")
-	return p.Target.LinkedEditingRange(ctx, params)
+	if isTemplFile, _ := p.URIs.TemplToGo(params.TextDocument.URI); !isTemplFile {
+		return p.Target.LinkedEditingRange(ctx, params)
+	}
+	// Tag names are linked without calling gopls.
+	doc, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return nil, nil
+	}
+	result, _ = linkedEditingRanges(doc.Lines, params.Position)
+	return result, nil
 }
 
 func (p *Server) Moniker(ctx context.Context, params *lsp.MonikerParams) (result []lsp.Moniker, err error) {
@@ -1044,7 +1591,51 @@ func (p *Server) Moniker(ctx context.Context, params *lsp.MonikerParams) (result
 }
 
 func (p *Server) Request(ctx context.Context, method string, params interface{}) (result interface{}, err error) {
-	p.Log.Info("client -> server: Request")
+	p.Log.Info("client -> server: Request", zap.String("method", method))
 	defer p.Log.Info("client -> server: Request end")
+	// The protocol package doesn't have a method for selection ranges, so they arrive here.
+	if method == "textDocument/selectionRange" {
+		return p.SelectionRange(ctx, params)
+	}
+	if method == "templ/sourceMap" {
+		if !p.DebugRequests {
+			return nil, jsonrpc2.Errorf(jsonrpc2.MethodNotFound, "method not found: %s, start the LSP with --debug to enable it", method)
+		}
+		return p.SourceMap(ctx, params)
+	}
 	return p.Target.Request(ctx, method, params)
 }
+
+// SelectionRange returns the ranges to use when expanding the selection, computed from
+// the syntax of the templ file.
+func (p *Server) SelectionRange(ctx context.Context, params interface{}) (result []lsp.SelectionRange, err error) {
+	var srp lsp.SelectionRangeParams
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &srp); err != nil {
+		return nil, err
+	}
+	if isTemplFile, _ := p.URIs.TemplToGo(srp.TextDocument.URI); !isTemplFile {
+		var r interface{}
+		if r, err = p.Target.Request(ctx, "textDocument/selectionRange", params); err != nil {
+			return nil, err
+		}
+		if b, err = json.Marshal(r); err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(b, &result)
+		return result, err
+	}
+	doc, ok := p.TemplSource.Get(string(srp.TextDocument.URI))
+	if !ok {
+		return nil, fmt.Errorf("document not found: %s", srp.TextDocument.URI)
+	}
+	result, err = selectionRanges(doc.Lines, srp.Positions)
+	if err != nil {
+		p.Log.Info("selectionRange: failed to parse template", zap.Error(err))
+		return nil, nil
+	}
+	return result, nil
+}
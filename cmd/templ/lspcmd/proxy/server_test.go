@@ -0,0 +1,1132 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.lsp.dev/uri"
+	"go.uber.org/zap"
+)
+
+// testTarget is a gopls stand-in. Methods that aren't overridden will panic if called.
+type testTarget struct {
+	lsp.Server
+	completion func(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error)
+	definition func(ctx context.Context, params *lsp.DefinitionParams) ([]lsp.Location, error)
+	codeAction func(ctx context.Context, params *lsp.CodeActionParams) ([]lsp.CodeAction, error)
+	didOpen    func(ctx context.Context, params *lsp.DidOpenTextDocumentParams) error
+	didChange  func(ctx context.Context, params *lsp.DidChangeTextDocumentParams) error
+	initialize func(ctx context.Context, params *lsp.InitializeParams) (*lsp.InitializeResult, error)
+	codeLens   func(ctx context.Context, params *lsp.CodeLensParams) ([]lsp.CodeLens, error)
+	execute    func(ctx context.Context, params *lsp.ExecuteCommandParams) (interface{}, error)
+}
+
+func (t testTarget) CodeLens(ctx context.Context, params *lsp.CodeLensParams) ([]lsp.CodeLens, error) {
+	return t.codeLens(ctx, params)
+}
+
+func (t testTarget) ExecuteCommand(ctx context.Context, params *lsp.ExecuteCommandParams) (interface{}, error) {
+	return t.execute(ctx, params)
+}
+
+func (t testTarget) CodeAction(ctx context.Context, params *lsp.CodeActionParams) ([]lsp.CodeAction, error) {
+	return t.codeAction(ctx, params)
+}
+
+func (t testTarget) Definition(ctx context.Context, params *lsp.DefinitionParams) ([]lsp.Location, error) {
+	return t.definition(ctx, params)
+}
+
+func (t testTarget) Completion(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error) {
+	return t.completion(ctx, params)
+}
+
+func (t testTarget) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentParams) error {
+	if t.didOpen != nil {
+		return t.didOpen(ctx, params)
+	}
+	return nil
+}
+
+func (t testTarget) DidSave(ctx context.Context, params *lsp.DidSaveTextDocumentParams) error {
+	return nil
+}
+
+func (t testTarget) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentParams) error {
+	return nil
+}
+
+func (t testTarget) DidChangeWatchedFiles(ctx context.Context, params *lsp.DidChangeWatchedFilesParams) error {
+	return nil
+}
+
+func (t testTarget) DidChange(ctx context.Context, params *lsp.DidChangeTextDocumentParams) error {
+	if t.didChange != nil {
+		return t.didChange(ctx, params)
+	}
+	return nil
+}
+
+func (t testTarget) Initialize(ctx context.Context, params *lsp.InitializeParams) (*lsp.InitializeResult, error) {
+	if t.initialize != nil {
+		return t.initialize(ctx, params)
+	}
+	return &lsp.InitializeResult{ServerInfo: &lsp.ServerInfo{Name: "gopls"}}, nil
+}
+
+func (t testTarget) Initialized(ctx context.Context, params *lsp.InitializedParams) error {
+	return nil
+}
+
+// testClient is an editor stand-in. Methods that aren't overridden will panic if called.
+type testClient struct {
+	lsp.Client
+	registrations []lsp.Registration
+	diagnostics   []*lsp.PublishDiagnosticsParams
+	messages      []string
+}
+
+func (c *testClient) ShowMessage(ctx context.Context, params *lsp.ShowMessageParams) error {
+	c.messages = append(c.messages, params.Message)
+	return nil
+}
+
+func (c *testClient) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagnosticsParams) error {
+	c.diagnostics = append(c.diagnostics, params)
+	return nil
+}
+
+func (c *testClient) RegisterCapability(ctx context.Context, params *lsp.RegistrationParams) error {
+	c.registrations = append(c.registrations, params.Registrations...)
+	return nil
+}
+
+func TestCompletionOfNonTemplFilesIsPassedThrough(t *testing.T) {
+	var targetCalled bool
+	target := testTarget{
+		completion: func(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error) {
+			targetCalled = true
+			if params.TextDocument.URI != "file:///main.go" {
+				t.Errorf("expected the URI to be unchanged, got %q", params.TextDocument.URI)
+			}
+			return &lsp.CompletionList{}, nil
+		},
+	}
+	s, _ := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	result, err := s.Completion(context.Background(), &lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: "file:///main.go"},
+		},
+		Context: &lsp.CompletionContext{
+			TriggerCharacter: "<",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !targetCalled {
+		t.Error("expected gopls to be called")
+	}
+	if len(result.Items) != 0 {
+		t.Errorf("expected no snippets for a Go file, got %d items", len(result.Items))
+	}
+}
+
+func TestCompletionTriggerCharacterRegistration(t *testing.T) {
+	tests := []struct {
+		name                string
+		dynamicRegistration bool
+		// expectedRequests is the number of completion requests sent by the client for each
+		// file, when completion is triggered by each character, or invoked, which is "".
+		expectedRequests map[string]map[string]int
+	}{
+		{
+			name:                "without dynamic registration support, triggers are added to the static capabilities",
+			dynamicRegistration: false,
+			expectedRequests: map[string]map[string]int{
+				"page.templ": {"": 1, ".": 1, "<": 1, "{": 1, "/": 1},
+				"main.go":    {"": 1, ".": 1, "<": 1, "{": 1, "/": 1},
+			},
+		},
+		{
+			name:                "with dynamic registration support, templ triggers are registered for templ files only",
+			dynamicRegistration: true,
+			expectedRequests: map[string]map[string]int{
+				"page.templ": {"": 1, ".": 1, "<": 1, "{": 1, "/": 1},
+				"main.go":    {"": 1, ".": 1, "<": 0, "{": 0, "/": 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			client := &testClient{}
+			target := testTarget{
+				initialize: func(ctx context.Context, params *lsp.InitializeParams) (*lsp.InitializeResult, error) {
+					return &lsp.InitializeResult{
+						Capabilities: lsp.ServerCapabilities{
+							CompletionProvider: &lsp.CompletionOptions{TriggerCharacters: []string{"."}},
+						},
+					}, nil
+				},
+			}
+			s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+			init(client)
+			result, err := s.Initialize(context.Background(), &lsp.InitializeParams{
+				Capabilities: lsp.ClientCapabilities{
+					TextDocument: &lsp.TextDocumentClientCapabilities{
+						Completion: &lsp.CompletionTextDocumentClientCapabilities{
+							DynamicRegistration: tt.dynamicRegistration,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected initialize error: %v", err)
+			}
+			if err = s.Initialized(context.Background(), &lsp.InitializedParams{}); err != nil {
+				t.Fatalf("unexpected initialized error: %v", err)
+			}
+			for fileName, triggers := range tt.expectedRequests {
+				for trigger, expected := range triggers {
+					if actual := completionRequests(t, result, client.registrations, fileName, trigger); actual != expected {
+						t.Errorf("%s, %q: expected %d completion requests, got %d", fileName, trigger, expected, actual)
+					}
+				}
+			}
+			expectedServerInfo := &lsp.ServerInfo{Name: "templ", Version: generator.Version()}
+			if diff := cmp.Diff(expectedServerInfo, result.ServerInfo); diff != "" {
+				t.Errorf("unexpected server info:\n%s", diff)
+			}
+		})
+	}
+}
+
+// completionRequests returns the number of completion requests that a client, such as
+// vscode-languageclient, sends for the file when completion is triggered by the character, or
+// invoked if the character is empty. The client sends a request to each provider whose
+// document selector matches the file, which for the static provider is every file.
+func completionRequests(t *testing.T, result *lsp.InitializeResult, registrations []lsp.Registration, fileName, trigger string) (n int) {
+	t.Helper()
+	triggers := func(characters []string) bool {
+		if trigger == "" {
+			return true
+		}
+		for _, c := range characters {
+			if c == trigger {
+				return true
+			}
+		}
+		return false
+	}
+	if p := result.Capabilities.CompletionProvider; p != nil && triggers(p.TriggerCharacters) {
+		n++
+	}
+	for _, r := range registrations {
+		if r.Method != "textDocument/completion" {
+			continue
+		}
+		options, ok := r.RegisterOptions.(lsp.CompletionRegistrationOptions)
+		if !ok {
+			t.Fatalf("unexpected options of %s: %#v", r.ID, r.RegisterOptions)
+		}
+		for _, filter := range options.DocumentSelector {
+			matched, err := filepath.Match(strings.TrimPrefix(filter.Pattern, "**/"), fileName)
+			if err != nil {
+				t.Fatalf("invalid pattern %q: %v", filter.Pattern, err)
+			}
+			if matched && triggers(options.TriggerCharacters) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+var update = flag.Bool("update", false, "Update the expected results in testdata.")
+
+// TestInitializeResult checks the result of initializing the server with the payload sent
+// by VS Code, and the capabilities returned by gopls, against
+// testdata/initialize/expected-result.json. Run go test -update to rewrite the expected
+// result.
+func TestInitializeResult(t *testing.T) {
+	const dir = "testdata/initialize"
+	var params, forwarded lsp.InitializeParams
+	readJSON(t, filepath.Join(dir, "vscode-params.json"), &params)
+	var goplsResult lsp.InitializeResult
+	readJSON(t, filepath.Join(dir, "gopls-result.json"), &goplsResult)
+
+	target := testTarget{
+		initialize: func(ctx context.Context, params *lsp.InitializeParams) (*lsp.InitializeResult, error) {
+			forwarded = *params
+			result := goplsResult
+			return &result, nil
+		},
+	}
+	s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	init(&testClient{})
+	sent := params
+	result, err := s.Initialize(context.Background(), &sent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(params, forwarded); diff != "" {
+		t.Errorf("expected the client's params to be forwarded to gopls untouched:\n%s", diff)
+	}
+
+	// The version depends on how the test binary was built.
+	result.ServerInfo.Version = "(version)"
+	actual, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	actual = append(actual, '\n')
+	expectedFileName := filepath.Join(dir, "expected-result.json")
+	if *update {
+		if err = os.WriteFile(expectedFileName, actual, 0644); err != nil {
+			t.Fatalf("failed to write expected result: %v", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(expectedFileName)
+	if err != nil {
+		t.Fatalf("failed to read expected result: %v", err)
+	}
+	if diff := cmp.Diff(string(expected), string(actual)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func readJSON(t *testing.T, fileName string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", fileName, err)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", fileName, err)
+	}
+}
+
+func TestGoplsCommandsAreNamespaced(t *testing.T) {
+	var executed string
+	target := testTarget{
+		codeLens: func(ctx context.Context, params *lsp.CodeLensParams) ([]lsp.CodeLens, error) {
+			return []lsp.CodeLens{
+				{Command: &lsp.Command{Title: "run test", Command: "gopls.test"}},
+				{Command: &lsp.Command{Title: "run go generate", Command: "gopls.generate"}},
+			}, nil
+		},
+		execute: func(ctx context.Context, params *lsp.ExecuteCommandParams) (interface{}, error) {
+			executed = params.Command
+			return nil, nil
+		},
+	}
+	s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	init(&testClient{})
+	lenses, err := s.CodeLens(context.Background(), &lsp.CodeLensParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: "file:///project/main_test.go"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var commands []string
+	for _, l := range lenses {
+		commands = append(commands, l.Command.Command)
+	}
+	if diff := cmp.Diff([]string{"templ.gopls.test", "templ.gopls.generate"}, commands); diff != "" {
+		t.Error(diff)
+	}
+	if _, err = s.ExecuteCommand(context.Background(), &lsp.ExecuteCommandParams{Command: "templ.gopls.test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executed != "gopls.test" {
+		t.Errorf("expected gopls to execute %q, got %q", "gopls.test", executed)
+	}
+}
+
+func TestConfigIsReloadedWhenItChanges(t *testing.T) {
+	dir := t.TempDir()
+	configFileName := filepath.Join(dir, "templ.json")
+	writeConfig := func(t *testing.T, contents string) {
+		t.Helper()
+		if err := os.WriteFile(configFileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write templ.json: %v", err)
+		}
+	}
+	writeConfig(t, `{"lint": {"disable": ["no-alt"]}, "fmt": {"tabs": true}}`)
+
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(client)
+	_, err := s.Initialize(context.Background(), &lsp.InitializeParams{
+		Capabilities: lsp.ClientCapabilities{
+			Workspace: &lsp.WorkspaceClientCapabilities{
+				DidChangeWatchedFiles: &lsp.DidChangeWatchedFilesWorkspaceClientCapabilities{
+					DynamicRegistration: true,
+				},
+			},
+		},
+		WorkspaceFolders: []lsp.WorkspaceFolder{{URI: string(uri.File(dir)), Name: "app"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected initialize error: %v", err)
+	}
+	if err = s.Initialized(context.Background(), &lsp.InitializedParams{}); err != nil {
+		t.Fatalf("unexpected initialized error: %v", err)
+	}
+	if len(client.registrations) != 1 || client.registrations[0].Method != "workspace/didChangeWatchedFiles" {
+		t.Errorf("expected templ.json to be watched, got %#v", client.registrations)
+	}
+	expectedMessages := []string{configFileName + `: unknown key "fmt.tabs"`}
+	if diff := cmp.Diff(expectedMessages, client.messages); diff != "" {
+		t.Errorf("unexpected messages:\n%s", diff)
+	}
+
+	templURI := uri.File(filepath.Join(dir, "logo.templ"))
+	err = s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{
+			URI:  templURI,
+			Text: "package main\n\ntempl Logo() {\n\t<img src=\"/logo.png\"/>\n}\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected didOpen error: %v", err)
+	}
+	lastDiagnostics := func() []lsp.Diagnostic {
+		return client.diagnostics[len(client.diagnostics)-1].Diagnostics
+	}
+	if diagnostics := lastDiagnostics(); len(diagnostics) != 0 {
+		t.Errorf("expected the no-alt check to be disabled, got %#v", diagnostics)
+	}
+
+	writeConfig(t, `{}`)
+	err = s.DidChangeWatchedFiles(context.Background(), &lsp.DidChangeWatchedFilesParams{
+		Changes: []*lsp.FileEvent{{URI: uri.File(configFileName), Type: lsp.FileChangeTypeChanged}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected didChangeWatchedFiles error: %v", err)
+	}
+	if diagnostics := lastDiagnostics(); len(diagnostics) != 1 || diagnostics[0].Code != parser.CheckNoAlt {
+		t.Errorf("expected the no-alt check to be enabled after the change, got %#v", diagnostics)
+	}
+}
+
+func TestDefinitionInUnopenedTemplFileIsMappedToTemplDeclaration(t *testing.T) {
+	dir := t.TempDir()
+	layoutTemplate := `package layout
+
+templ Base(title string) {
+	<title>{ title }</title>
+}
+`
+	layoutFileName := filepath.Join(dir, "layout", "base.templ")
+	if err := os.MkdirAll(filepath.Dir(layoutFileName), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(layoutFileName, []byte(layoutTemplate), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	// Find where gopls would locate the Base function in the generated code.
+	tf, err := parser.ParseString(layoutTemplate)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var sb strings.Builder
+	if _, err = generator.GenerateFile(generator.GenerateOpts{}, tf, &sb); err != nil {
+		t.Fatalf("failed to generate template: %v", err)
+	}
+	var goPosition lsp.Position
+	for i, line := range strings.Split(sb.String(), "\n") {
+		if strings.HasPrefix(line, "func Base(") {
+			goPosition = lsp.Position{Line: uint32(i), Character: uint32(len("func "))}
+		}
+	}
+
+	target := testTarget{
+		definition: func(ctx context.Context, params *lsp.DefinitionParams) ([]lsp.Location, error) {
+			return []lsp.Location{
+				{
+					URI:   uri.File(filepath.Join(dir, "layout", "base_templ.go")),
+					Range: lsp.Range{Start: goPosition, End: goPosition},
+				},
+			}, nil
+		},
+	}
+	s, _ := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	pageURI := uri.File(filepath.Join(dir, "page.templ"))
+	// Map the start of the page to the start of its Go file.
+	pageSourceMap := parser.NewSourceMap()
+	pageSourceMap.Add(parser.Expression{Value: "layout.Base"}, parser.Range{})
+	s.SourceMapCache.Set(string(pageURI), pageSourceMap)
+
+	result, err := s.Definition(context.Background(), &lsp.DefinitionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: pageURI},
+			Position:     lsp.Position{Line: 0, Character: 0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 location, got %d", len(result))
+	}
+	if expected := uri.File(layoutFileName); result[0].URI != expected {
+		t.Errorf("expected URI %q, got %q", expected, result[0].URI)
+	}
+	expected := lsp.Position{Line: 2, Character: uint32(len("templ "))}
+	if result[0].Range.Start != expected {
+		t.Errorf("expected position %v, got %v", expected, result[0].Range.Start)
+	}
+}
+
+func TestCodeActionRangesAreMapped(t *testing.T) {
+	template := `package main
+
+templ Hello() {
+	<div>{ undefinedVariable }</div>
+}
+`
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	generated, err := generator.GenerateFile(generator.GenerateOpts{}, tf, io.Discard)
+	if err != nil {
+		t.Fatalf("failed to generate template: %v", err)
+	}
+	sm := generated.SourceMap
+	templRange := lsp.Range{
+		Start: lsp.Position{Line: 3, Character: 8},
+		End:   lsp.Position{Line: 3, Character: 25},
+	}
+	start, _ := sm.TargetPositionFromSource(templRange.Start.Line, templRange.Start.Character)
+	end, _ := sm.TargetPositionFromSource(templRange.End.Line, templRange.End.Character)
+	goRange := lsp.Range{
+		Start: lsp.Position{Line: start.Line, Character: start.Col},
+		End:   lsp.Position{Line: end.Line, Character: end.Col},
+	}
+
+	templURI := lsp.DocumentURI("file:///hello.templ")
+	goURI := lsp.DocumentURI("file:///hello_templ.go")
+	target := testTarget{
+		codeAction: func(ctx context.Context, params *lsp.CodeActionParams) ([]lsp.CodeAction, error) {
+			if params.TextDocument.URI != goURI {
+				t.Errorf("expected gopls to receive %q, got %q", goURI, params.TextDocument.URI)
+			}
+			if diff := cmp.Diff(goRange, params.Range); diff != "" {
+				t.Errorf("unexpected request range:\n%s", diff)
+			}
+			if diff := cmp.Diff(goRange, params.Context.Diagnostics[0].Range); diff != "" {
+				t.Errorf("unexpected context diagnostic range:\n%s", diff)
+			}
+			return []lsp.CodeAction{
+				{
+					Title:       "Declare undefinedVariable",
+					Diagnostics: params.Context.Diagnostics,
+					Edit: &lsp.WorkspaceEdit{
+						Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+							goURI: {{Range: goRange, NewText: "x"}},
+						},
+						DocumentChanges: []lsp.TextDocumentEdit{
+							{
+								TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+									TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: goURI},
+								},
+								Edits: []lsp.TextEdit{{Range: goRange, NewText: "x"}},
+							},
+						},
+					},
+				},
+				{
+					Title: "No edit",
+				},
+			}, nil
+		},
+	}
+	s, _ := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	s.SourceMapCache.Set(string(templURI), sm)
+
+	result, err := s.CodeAction(context.Background(), &lsp.CodeActionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+		Range:        templRange,
+		Context: lsp.CodeActionContext{
+			Diagnostics: []lsp.Diagnostic{
+				{Range: templRange, Message: "undefined: undefinedVariable"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 code actions, got %d", len(result))
+	}
+	expectedEdit := &lsp.WorkspaceEdit{
+		Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+			templURI: {{Range: templRange, NewText: "x"}},
+		},
+		DocumentChanges: []lsp.TextDocumentEdit{
+			{
+				TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: templURI},
+				},
+				Edits: []lsp.TextEdit{{Range: templRange, NewText: "x"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdit, result[0].Edit); diff != "" {
+		t.Errorf("unexpected edit:\n%s", diff)
+	}
+	if diff := cmp.Diff(templRange, result[0].Diagnostics[0].Range); diff != "" {
+		t.Errorf("unexpected diagnostic range:\n%s", diff)
+	}
+}
+
+func TestGeneratorErrorsArePublishedAsDiagnostics(t *testing.T) {
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(client)
+	templURI := lsp.DocumentURI("file:///a.templ")
+	ge := generator.Error{
+		Err: errors.New("void element \"br\" must not have child elements"),
+		Range: parser.Range{
+			From: parser.NewPosition(20, 2, 6),
+			To:   parser.NewPosition(23, 2, 9),
+		},
+	}
+	if err := s.publishGeneratorError(context.Background(), templURI, fmt.Errorf("generate: %w", ge)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := client.diagnostics[len(client.diagnostics)-1]
+	if len(last.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(last.Diagnostics))
+	}
+	d := last.Diagnostics[0]
+	if d.Source != "templ-generator" {
+		t.Errorf("expected source %q, got %q", "templ-generator", d.Source)
+	}
+	if d.Message != ge.Err.Error() {
+		t.Errorf("expected message %q, got %q", ge.Err.Error(), d.Message)
+	}
+	expected := lsp.Range{
+		Start: lsp.Position{Line: 2, Character: 6},
+		End:   lsp.Position{Line: 2, Character: 9},
+	}
+	if diff := cmp.Diff(expected, d.Range); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTemplFilesOutsideTheWorkspaceAreNotSentToGopls(t *testing.T) {
+	target := testTarget{
+		didOpen: func(ctx context.Context, params *lsp.DidOpenTextDocumentParams) error {
+			t.Errorf("unexpected didOpen sent to gopls for %q", params.TextDocument.URI)
+			return nil
+		},
+	}
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	init(client)
+	_, err := s.Initialize(context.Background(), &lsp.InitializeParams{
+		WorkspaceFolders: []lsp.WorkspaceFolder{
+			{URI: string(uri.File(filepath.Join(string(filepath.Separator), "workspace"))), Name: "workspace"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected initialize error: %v", err)
+	}
+
+	templURI := uri.File(filepath.Join(t.TempDir(), "scratch.templ"))
+	err = s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{
+			URI:  templURI,
+			Text: "package main\n\ntempl Scratch() {\n<div></div>\n}\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected didOpen error: %v", err)
+	}
+	if len(client.diagnostics) == 0 {
+		t.Error("expected parse diagnostics to be published")
+	}
+
+	t.Run("formatting", func(t *testing.T) {
+		edits, err := s.Formatting(context.Background(), &lsp.DocumentFormattingParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(edits) != 1 || !strings.Contains(edits[0].NewText, "\t<div></div>") {
+			t.Errorf("expected the template to be formatted, got %#v", edits)
+		}
+	})
+	t.Run("document symbols", func(t *testing.T) {
+		symbols, err := s.DocumentSymbol(context.Background(), &lsp.DocumentSymbolParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(symbols) != 1 || symbols[0].(lsp.DocumentSymbol).Name != "Scratch" {
+			t.Errorf("expected the Scratch symbol, got %#v", symbols)
+		}
+	})
+	t.Run("snippet completion", func(t *testing.T) {
+		result, err := s.Completion(context.Background(), &lsp.CompletionParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+				Position:     lsp.Position{Line: 3, Character: 1},
+			},
+			Context: &lsp.CompletionContext{TriggerCharacter: "<"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Items) == 0 {
+			t.Error("expected snippets")
+		}
+	})
+	t.Run("Go completion is not sent to gopls", func(t *testing.T) {
+		result, err := s.Completion(context.Background(), &lsp.CompletionParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+				Position:     lsp.Position{Line: 2, Character: 8},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != nil {
+			t.Errorf("expected no completion, got %#v", result)
+		}
+	})
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	template := `package main
+
+templ Hello(name string) {
+	<div>{ name }</div>
+}
+
+css red() {
+	color: red;
+}
+
+templ (p *Person) Card() {
+	<div>{ p.Name }</div>
+}
+
+templ List[T fmt.Stringer](items []T) {
+}
+`
+	symbols, err := documentSymbols(strings.Split(template, "\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []lsp.DocumentSymbol{
+		{
+			Name:           "Hello",
+			Detail:         "templ",
+			Kind:           lsp.SymbolKindFunction,
+			Range:          lsp.Range{Start: lsp.Position{Line: 2, Character: 0}, End: lsp.Position{Line: 4, Character: 1}},
+			SelectionRange: lsp.Range{Start: lsp.Position{Line: 2, Character: 6}, End: lsp.Position{Line: 2, Character: 11}},
+		},
+		{
+			Name:           "red",
+			Detail:         "css",
+			Kind:           lsp.SymbolKindFunction,
+			Range:          lsp.Range{Start: lsp.Position{Line: 6, Character: 0}, End: lsp.Position{Line: 8, Character: 1}},
+			SelectionRange: lsp.Range{Start: lsp.Position{Line: 6, Character: 4}, End: lsp.Position{Line: 6, Character: 7}},
+		},
+		{
+			Name:           "(*Person).Card",
+			Detail:         "templ",
+			Kind:           lsp.SymbolKindFunction,
+			Range:          lsp.Range{Start: lsp.Position{Line: 10, Character: 0}, End: lsp.Position{Line: 12, Character: 1}},
+			SelectionRange: lsp.Range{Start: lsp.Position{Line: 10, Character: 18}, End: lsp.Position{Line: 10, Character: 22}},
+		},
+		{
+			Name:           "List",
+			Detail:         "templ",
+			Kind:           lsp.SymbolKindFunction,
+			Range:          lsp.Range{Start: lsp.Position{Line: 14, Character: 0}, End: lsp.Position{Line: 15, Character: 1}},
+			SelectionRange: lsp.Range{Start: lsp.Position{Line: 14, Character: 6}, End: lsp.Position{Line: 14, Character: 10}},
+		},
+	}
+	if diff := cmp.Diff(expected, symbols); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClosedDocumentDiagnosticsAreKeptFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "a.templ")
+	broken := "package main\n\ntempl A() {\n\t<br>x</br>\n}\n"
+	if err := os.WriteFile(fileName, []byte(broken), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	templURI := uri.File(fileName)
+
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(client)
+	err := s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: templURI, Text: broken},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.DidClose(context.Background(), &lsp.DidCloseTextDocumentParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := s.TemplSource.Get(string(templURI)); ok {
+		t.Error("expected the document contents to be removed from the cache")
+	}
+	last := client.diagnostics[len(client.diagnostics)-1]
+	if len(last.Diagnostics) != 1 || last.Diagnostics[0].Source != "templ" {
+		t.Fatalf("expected the parse error to be republished after close, got %v", last.Diagnostics)
+	}
+
+	// Fixing the file on disk updates the diagnostics.
+	if err = os.WriteFile(fileName, []byte("package main\n\ntempl A() {\n\t<br/>\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	err = s.DidChangeWatchedFiles(context.Background(), &lsp.DidChangeWatchedFilesParams{
+		Changes: []*lsp.FileEvent{{URI: templURI, Type: lsp.FileChangeTypeChanged}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last = client.diagnostics[len(client.diagnostics)-1]; len(last.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared, got %v", last.Diagnostics)
+	}
+	if _, ok := s.SourceMapCache.Get(string(templURI)); !ok {
+		t.Error("expected the source map of the closed document to be cached, so that gopls diagnostics can be mapped")
+	}
+
+	// Deleting the file clears the diagnostics.
+	if err = os.Remove(fileName); err != nil {
+		t.Fatalf("failed to delete template: %v", err)
+	}
+	client.diagnostics = nil
+	err = s.DidChangeWatchedFiles(context.Background(), &lsp.DidChangeWatchedFilesParams{
+		Changes: []*lsp.FileEvent{{URI: templURI, Type: lsp.FileChangeTypeDeleted}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.diagnostics) != 1 || len(client.diagnostics[0].Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared, got %v", client.diagnostics)
+	}
+	if _, ok := s.SourceMapCache.Get(string(templURI)); ok {
+		t.Error("expected the source map of the deleted document to be removed")
+	}
+}
+
+func TestOutOfSequenceChangesReloadTheDocumentFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "a.templ")
+	onDisk := "package main\n\ntempl FromDisk() {\n}\n"
+	if err := os.WriteFile(fileName, []byte(onDisk), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	templURI := uri.File(fileName)
+
+	var goSource string
+	target := testTarget{
+		didChange: func(ctx context.Context, params *lsp.DidChangeTextDocumentParams) error {
+			goSource = params.ContentChanges[0].Text
+			return nil
+		},
+	}
+	s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	init(&testClient{})
+	err := s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: templURI, Version: 5, Text: "package main\n\ntempl Open() {\n}\n"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The editor has reloaded the buffer, and starts again from an earlier version.
+	err = s.DidChange(context.Background(), &lsp.DidChangeTextDocumentParams{
+		TextDocument: lsp.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: templURI},
+			Version:                2,
+		},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{
+			Range: &lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 6},
+				End:   lsp.Position{Line: 2, Character: 6},
+			},
+			Text: "X",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, _ := s.TemplSource.Get(string(templURI))
+	if diff := cmp.Diff(onDisk, d.String()); diff != "" {
+		t.Errorf("expected the document to be reloaded from disk:\n%s", diff)
+	}
+	if version, _ := s.TemplSource.Version(string(templURI)); version != 2 {
+		t.Errorf("expected the document to be at version 2, got %d", version)
+	}
+	if !strings.Contains(goSource, "func FromDisk()") {
+		t.Errorf("expected gopls to receive the Go code of the reloaded document, got:\n%s", goSource)
+	}
+}
+
+func TestOutOfSequenceChangesDontReplaceUnsavedEdits(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "a.templ")
+	if err := os.WriteFile(fileName, []byte("package main\n\ntempl FromDisk() {\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	templURI := uri.File(fileName)
+
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(&testClient{})
+	err := s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: templURI, Version: 5, Text: "package main\n\ntempl Open() {\n}\n"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	insert := func(version int32, text string) {
+		t.Helper()
+		err := s.DidChange(context.Background(), &lsp.DidChangeTextDocumentParams{
+			TextDocument: lsp.VersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: templURI},
+				Version:                version,
+			},
+			ContentChanges: []lsp.TextDocumentContentChangeEvent{{
+				Range: &lsp.Range{
+					Start: lsp.Position{Line: 2, Character: 6},
+					End:   lsp.Position{Line: 2, Character: 6},
+				},
+				Text: text,
+			}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The version skips numbers, like Neovim's changedtick, but follows the current version.
+	insert(9, "Edited")
+	// The changes for an earlier version can't be applied, but the edit isn't saved.
+	insert(7, "X")
+	d, _ := s.TemplSource.Get(string(templURI))
+	if diff := cmp.Diff("package main\n\ntempl EditedOpen() {\n}\n", d.String()); diff != "" {
+		t.Errorf("expected the unsaved edits to be kept:\n%s", diff)
+	}
+	if version, _ := s.TemplSource.Version(string(templURI)); version != 9 {
+		t.Errorf("expected the document to be at version 9, got %d", version)
+	}
+}
+
+func TestRangesOfOpenDocumentsThatCantBeGeneratedAreNotMappedFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "a.templ")
+	if err := os.WriteFile(fileName, []byte("package main\n\ntempl A() {\n\t<p>On disk</p>\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	templURI := uri.File(fileName)
+
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(&testClient{})
+	err := s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: templURI, Version: 1, Text: "package main\n\ntempl A() {\n\t<p>Open</p>\n}\n"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := s.getSourceMap(templURI); !ok {
+		t.Fatal("expected the source map of the open document")
+	}
+	// The included file doesn't exist, so the Go code can't be generated.
+	err = s.DidChange(context.Background(), &lsp.DidChangeTextDocumentParams{
+		TextDocument: lsp.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: templURI},
+			Version:                2,
+		},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{
+			Text: "package main\n\ntempl A() {\n\t@templ.Include(\"missing.svg\")\n}\n",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.SourceMapCache.Outdated(string(templURI)) {
+		t.Error("expected the source map to be outdated")
+	}
+	if _, ok := s.getSourceMap(templURI); ok {
+		t.Error("expected no source map, not one generated from the file on disk")
+	}
+}
+
+func TestSourceMapsOfUnopenedDocumentsAreCachedUntilTheFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "a.templ")
+	if err := os.WriteFile(fileName, []byte("package main\n\ntempl A() {\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	templURI := uri.File(fileName)
+	s, _ := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+
+	first, ok := s.getSourceMap(templURI)
+	if !ok {
+		t.Fatal("expected a source map")
+	}
+	if second, _ := s.getSourceMap(templURI); second != first {
+		t.Error("expected the cached source map to be used")
+	}
+
+	if err := os.WriteFile(fileName, []byte("package main\n\ntempl Changed() {\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(fileName, later, later); err != nil {
+		t.Fatalf("failed to set the modification time: %v", err)
+	}
+	if changed, _ := s.getSourceMap(templURI); changed == first {
+		t.Error("expected the source map to be generated again after the file changed")
+	}
+}
+
+func TestParseErrorsArePublishedAsDiagnostics(t *testing.T) {
+	src := "package main\n\ntempl A() {\n\t<a></b>\n}\n\ntempl B() {\n\t<a></b>\n}\n"
+	_, err := parser.ParseString(src)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	diagnostics := parseErrorDiagnostics(strings.Split(src, "\n"), err)
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected a diagnostic for each error, got %v", diagnostics)
+	}
+	for i, line := range []uint32{3, 7} {
+		expected := lsp.Range{
+			Start: lsp.Position{Line: line, Character: 4},
+			End:   lsp.Position{Line: line, Character: 4},
+		}
+		if diff := cmp.Diff(expected, diagnostics[i].Range); diff != "" {
+			t.Errorf("unexpected range for diagnostic %d:\n%v", i, diff)
+		}
+	}
+}
+
+func TestParseErrorDiagnosticsCoverInvalidNames(t *testing.T) {
+	src := "package main\n\ntempl A() {\n\t<div data-x!=\"1\"></div>\n}\n"
+	_, err := parser.ParseString(src)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	diagnostics := parseErrorDiagnostics(strings.Split(src, "\n"), err)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diagnostics)
+	}
+	expected := lsp.Range{
+		Start: lsp.Position{Line: 3, Character: 6},
+		End:   lsp.Position{Line: 3, Character: 13},
+	}
+	if diff := cmp.Diff(expected, diagnostics[0].Range); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestPositionsInMultiByteLinesAreMappedAsUTF16(t *testing.T) {
+	templURI := uri.File(filepath.Join(t.TempDir(), "greet.templ"))
+	templ := "package main\n\ntempl greet(name string) {\n\t<p>😀 你好 { name }</p>\n}\n"
+	// LSP clients count UTF-16 code units, so the emoji is 2 columns, and each CJK character is 1.
+	namePosition := lsp.Position{Line: 3, Character: uint32(len(utf16.Encode([]rune("\t<p>😀 你好 { "))))}
+	var s *Server
+	var goPosition lsp.Position
+	target := testTarget{
+		definition: func(ctx context.Context, params *lsp.DefinitionParams) ([]lsp.Location, error) {
+			goPosition = params.Position
+			goSource, _ := s.GetGoSource(string(templURI))
+			goLine := strings.Split(goSource, "\n")[goPosition.Line]
+			if got := goLine[parser.ByteColFromUTF16(goLine, goPosition.Character):]; !strings.HasPrefix(got, "name") {
+				t.Errorf("expected gopls to be sent the position of name, got %q", got)
+			}
+			return []lsp.Location{
+				{
+					URI:   params.TextDocument.URI,
+					Range: lsp.Range{Start: goPosition, End: goPosition},
+				},
+			}, nil
+		},
+	}
+	s, init := NewServer(zap.NewNop(), target, NewSourceMapCache())
+	init(&testClient{})
+	err := s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: templURI, Text: templ},
+	})
+	if err != nil {
+		t.Fatalf("unexpected didOpen error: %v", err)
+	}
+	result, err := s.Definition(context.Background(), &lsp.DefinitionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: templURI},
+			Position:     namePosition,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 location, got %d", len(result))
+	}
+	if result[0].Range.Start != namePosition {
+		t.Errorf("expected the definition to map back to %v, got %v", namePosition, result[0].Range.Start)
+	}
+}
+
+func TestLintIssuesArePublishedAsWarnings(t *testing.T) {
+	client := &testClient{}
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(client)
+	templURI := lsp.DocumentURI("file:///a.templ")
+	templ := "package main\n\ntempl A() {\n\t<p>😀</p><img src=\"a.png\"/>\n\t//templ:ignore no-alt\n\t<img src=\"b.png\"/>\n}\n"
+	if _, ok, err := s.parseTemplate(context.Background(), templURI, templ); !ok || err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	last := client.diagnostics[len(client.diagnostics)-1]
+	expected := []lsp.Diagnostic{{
+		Severity: lsp.DiagnosticSeverityWarning,
+		Source:   "templ-lint",
+		Code:     parser.CheckNoAlt,
+		Message:  `<img>: missing alt attribute, use alt="" for decorative images`,
+		// The emoji is 4 bytes, but 2 UTF-16 code units.
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 3, Character: 11},
+			End:   lsp.Position{Line: 3, Character: 14},
+		},
+	}}
+	if diff := cmp.Diff(expected, last.Diagnostics); diff != "" {
+		t.Error(diff)
+	}
+}
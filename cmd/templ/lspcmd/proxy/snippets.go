@@ -1,6 +1,11 @@
 package proxy
 
-import lsp "github.com/a-h/protocol"
+import (
+	"regexp"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+)
 
 var htmlSnippets = []lsp.CompletionItem{
 	{
@@ -17,6 +22,22 @@ var htmlSnippets = []lsp.CompletionItem{
 		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
 		InsertTextFormat: lsp.InsertTextFormatSnippet,
 	},
+	{
+		Label: "html5",
+		InsertText: `!DOCTYPE html>
+<html lang="${1:en}">
+	<head>
+		<meta charset="UTF-8"/>
+		<meta name="viewport" content="width=device-width, initial-scale=1.0"/>
+		<title>${2}</title>
+	</head>
+	<body>
+		${0}
+	</body>
+</html>`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
 	{
 		Label: "div",
 		InsertText: `div>
@@ -26,3 +47,69 @@ var htmlSnippets = []lsp.CompletionItem{
 		InsertTextFormat: lsp.InsertTextFormatSnippet,
 	},
 }
+
+// statementSnippets are offered at the start of a line within a template, where
+// templ statements can be used.
+var statementSnippets = []lsp.CompletionItem{
+	{
+		Label: "switch",
+		InsertText: `switch ${1:value} {
+	case ${2:"a"}:
+		${0}
+	default:
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+	{
+		Label:            "@templ.JSONScript",
+		InsertText:       `@templ.JSONScript("${1:id}", ${2:data})`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+}
+
+// statementCompletion returns the statement snippets that start with the word being
+// typed. Statements are indented within templates, so the word must be indented.
+func statementCompletion(lines []string, pos lsp.Position) (items []lsp.CompletionItem, ok bool) {
+	if int(pos.Line) >= len(lines) || int(pos.Character) > len(lines[pos.Line]) {
+		return
+	}
+	prefix := lines[pos.Line][:pos.Character]
+	word := strings.TrimLeft(prefix, " \t")
+	if word == prefix || strings.ContainsAny(word, " \t") {
+		return
+	}
+	for _, item := range statementSnippets {
+		if strings.HasPrefix(item.Label, word) {
+			items = append(items, item)
+		}
+	}
+	return items, len(items) > 0
+}
+
+var (
+	snippetPlaceholderWithDefault = regexp.MustCompile(`\$\{\d+:([^}]*)\}`)
+	snippetPlaceholder            = regexp.MustCompile(`\$\{\d+\}|\$\d+`)
+)
+
+// stripSnippetPlaceholders converts snippet syntax into plain text, keeping
+// any default values, e.g. `a href="${1:url}">$0</a>` becomes `a href="url"></a>`.
+func stripSnippetPlaceholders(s string) string {
+	s = snippetPlaceholderWithDefault.ReplaceAllString(s, "$1")
+	return snippetPlaceholder.ReplaceAllString(s, "")
+}
+
+// snippetsAsPlainText returns a copy of the items, with snippets converted into
+// plain text for clients that don't support snippets.
+func snippetsAsPlainText(items []lsp.CompletionItem) (output []lsp.CompletionItem) {
+	output = make([]lsp.CompletionItem, len(items))
+	for i, item := range items {
+		if item.InsertTextFormat == lsp.InsertTextFormatSnippet {
+			item.InsertText = stripSnippetPlaceholders(item.InsertText)
+			item.InsertTextFormat = lsp.InsertTextFormatPlainText
+		}
+		output[i] = item
+	}
+	return output
+}
@@ -0,0 +1,205 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestStripSnippetPlaceholders(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "text without placeholders is unchanged",
+			input:    `div>`,
+			expected: `div>`,
+		},
+		{
+			name:     "numbered placeholders are removed",
+			input:    "div>\n\t$0\n</div>",
+			expected: "div>\n\t\n</div>",
+		},
+		{
+			name:     "braced placeholders are removed",
+			input:    "${1}>\n\t${0}\n</${1}>",
+			expected: ">\n\t\n</>",
+		},
+		{
+			name:     "default values are retained",
+			input:    `a href="${1:url}">${2:}</a>`,
+			expected: `a href="url"></a>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := stripSnippetPlaceholders(tt.input)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCompletionSnippetSupport(t *testing.T) {
+	tests := []struct {
+		name           string
+		capabilities   lsp.ClientCapabilities
+		expectedFormat lsp.InsertTextFormat
+	}{
+		{
+			name: "snippets are returned if the client supports them",
+			capabilities: lsp.ClientCapabilities{
+				TextDocument: &lsp.TextDocumentClientCapabilities{
+					Completion: &lsp.CompletionTextDocumentClientCapabilities{
+						CompletionItem: &lsp.CompletionTextDocumentClientCapabilitiesItem{
+							SnippetSupport: true,
+						},
+					},
+				},
+			},
+			expectedFormat: lsp.InsertTextFormatSnippet,
+		},
+		{
+			name:           "plain text is returned if the client does not support snippets",
+			capabilities:   lsp.ClientCapabilities{},
+			expectedFormat: lsp.InsertTextFormatPlainText,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := NewServer(zap.NewNop(), nil, NewSourceMapCache())
+			s.ClientCapabilities = tt.capabilities
+			result, err := s.Completion(context.Background(), &lsp.CompletionParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: "file:///example.templ"},
+				},
+				Context: &lsp.CompletionContext{
+					TriggerCharacter: "<",
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Items) != len(htmlSnippets) {
+				t.Fatalf("expected %d items, got %d", len(htmlSnippets), len(result.Items))
+			}
+			for _, item := range result.Items {
+				if item.InsertTextFormat != tt.expectedFormat {
+					t.Errorf("%s: expected format %v, got %v", item.Label, tt.expectedFormat, item.InsertTextFormat)
+				}
+			}
+		})
+	}
+}
+
+func TestStatementCompletion(t *testing.T) {
+	lines := []string{
+		"package main",
+		"",
+		"sw",
+		"templ A(v string) {",
+		"	sw",
+		"	<div>sw</div>",
+		"	switch",
+		"	@templ.JS",
+		"}",
+	}
+	tests := []struct {
+		name          string
+		pos           lsp.Position
+		expectedOK    bool
+		expectedLabel string
+	}{
+		{
+			name:          "partial statements within a template are completed",
+			pos:           lsp.Position{Line: 4, Character: 3},
+			expectedOK:    true,
+			expectedLabel: "switch",
+		},
+		{
+			name:          "complete statements are completed",
+			pos:           lsp.Position{Line: 6, Character: 7},
+			expectedOK:    true,
+			expectedLabel: "switch",
+		},
+		{
+			name:          "templ functions are completed",
+			pos:           lsp.Position{Line: 7, Character: 10},
+			expectedOK:    true,
+			expectedLabel: "@templ.JSONScript",
+		},
+		{
+			name:       "text within elements is not completed",
+			pos:        lsp.Position{Line: 5, Character: 8},
+			expectedOK: false,
+		},
+		{
+			name:       "top level text is not completed",
+			pos:        lsp.Position{Line: 2, Character: 2},
+			expectedOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			items, ok := statementCompletion(lines, tt.pos)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if ok && items[0].Label != tt.expectedLabel {
+				t.Errorf("expected the %s snippet, got %q", tt.expectedLabel, items[0].Label)
+			}
+		})
+	}
+}
+
+func TestHTML5SnippetIsAValidTemplate(t *testing.T) {
+	var snippet string
+	for _, item := range htmlSnippets {
+		if item.Label == "html5" {
+			snippet = item.InsertText
+		}
+	}
+	if snippet == "" {
+		t.Fatal("html5 snippet not found")
+	}
+	template := "package main\n\ntempl Page() {\n\t<" + stripSnippetPlaceholders(snippet) + "\n}\n"
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse the expanded snippet: %v\n%s", err, template)
+	}
+	if _, err = generator.GenerateFile(generator.GenerateOpts{}, tf, io.Discard); err != nil {
+		t.Fatalf("failed to generate the expanded snippet: %v", err)
+	}
+}
+
+func TestJSONScriptSnippetIsAValidTemplate(t *testing.T) {
+	var snippet string
+	for _, item := range statementSnippets {
+		if item.Label == "@templ.JSONScript" {
+			snippet = item.InsertText
+		}
+	}
+	if snippet == "" {
+		t.Fatal("@templ.JSONScript snippet not found")
+	}
+	template := "package main\n\ntempl Page(data any) {\n\t" + stripSnippetPlaceholders(snippet) + "\n}\n"
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse the expanded snippet: %v\n%s", err, template)
+	}
+	if _, err = generator.GenerateFile(generator.GenerateOpts{}, tf, io.Discard); err != nil {
+		t.Fatalf("failed to generate the expanded snippet: %v", err)
+	}
+}
@@ -1,7 +1,9 @@
 package proxy
 
 import (
+	"io/fs"
 	"sync"
+	"time"
 
 	"github.com/a-h/templ/parser/v2"
 )
@@ -11,6 +13,7 @@ func NewSourceMapCache() *SourceMapCache {
 	return &SourceMapCache{
 		m:              new(sync.Mutex),
 		uriToSourceMap: make(map[string]*parser.SourceMap),
+		outdated:       make(map[string]struct{}),
 	}
 }
 
@@ -18,12 +21,33 @@ func NewSourceMapCache() *SourceMapCache {
 type SourceMapCache struct {
 	m              *sync.Mutex
 	uriToSourceMap map[string]*parser.SourceMap
+	// outdated are the URIs of open documents that couldn't be generated, so the Go code
+	// in gopls doesn't match the document, and there's no source map between them.
+	outdated map[string]struct{}
 }
 
 func (fc *SourceMapCache) Set(uri string, m *parser.SourceMap) {
 	fc.m.Lock()
 	defer fc.m.Unlock()
 	fc.uriToSourceMap[uri] = m
+	delete(fc.outdated, uri)
+}
+
+// SetOutdated removes the source map of a document that couldn't be generated, until the
+// next call to Set.
+func (fc *SourceMapCache) SetOutdated(uri string) {
+	fc.m.Lock()
+	defer fc.m.Unlock()
+	delete(fc.uriToSourceMap, uri)
+	fc.outdated[uri] = struct{}{}
+}
+
+// Outdated returns true if the document couldn't be generated since its source map was set.
+func (fc *SourceMapCache) Outdated(uri string) bool {
+	fc.m.Lock()
+	defer fc.m.Unlock()
+	_, ok := fc.outdated[uri]
+	return ok
 }
 
 func (fc *SourceMapCache) Get(uri string) (m *parser.SourceMap, ok bool) {
@@ -37,6 +61,7 @@ func (fc *SourceMapCache) Delete(uri string) {
 	fc.m.Lock()
 	defer fc.m.Unlock()
 	delete(fc.uriToSourceMap, uri)
+	delete(fc.outdated, uri)
 }
 
 func (fc *SourceMapCache) URIs() (uris []string) {
@@ -50,3 +75,39 @@ func (fc *SourceMapCache) URIs() (uris []string) {
 	}
 	return uris
 }
+
+// diskSourceMaps caches the source maps of templ files that aren't open in the editor, so
+// that the results of a request, e.g. references, don't generate the same file again. A
+// source map is used until the size or modification time of the file changes.
+type diskSourceMaps struct {
+	m              sync.Mutex
+	uriToSourceMap map[string]diskSourceMap
+}
+
+type diskSourceMap struct {
+	size    int64
+	modTime time.Time
+	// sourceMap is nil if the file couldn't be parsed or generated.
+	sourceMap *parser.SourceMap
+}
+
+// Get the source map of the file, if it's cached for the same version of the file.
+func (dm *diskSourceMaps) Get(uri string, info fs.FileInfo) (m *parser.SourceMap, cached bool) {
+	dm.m.Lock()
+	defer dm.m.Unlock()
+	e, ok := dm.uriToSourceMap[uri]
+	if !ok || e.size != info.Size() || !e.modTime.Equal(info.ModTime()) {
+		return nil, false
+	}
+	return e.sourceMap, true
+}
+
+// Set the source map of the file. A nil source map records that it couldn't be generated.
+func (dm *diskSourceMaps) Set(uri string, info fs.FileInfo, m *parser.SourceMap) {
+	dm.m.Lock()
+	defer dm.m.Unlock()
+	if dm.uriToSourceMap == nil {
+		dm.uriToSourceMap = make(map[string]diskSourceMap)
+	}
+	dm.uriToSourceMap[uri] = diskSourceMap{size: info.Size(), modTime: info.ModTime(), sourceMap: m}
+}
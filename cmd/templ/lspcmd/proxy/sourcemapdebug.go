@@ -0,0 +1,107 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"go.uber.org/zap"
+)
+
+// SourceMapParams are the parameters of the templ/sourceMap debug request.
+type SourceMapParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+	// Format is either "json" (the default), or "text" for a human-readable table.
+	Format string `json:"format,omitempty"`
+}
+
+// SourceMapResult is the result of the templ/sourceMap debug request.
+type SourceMapResult struct {
+	Mappings []SourceMapMapping `json:"mappings"`
+	Go       string             `json:"go"`
+	// Text is populated instead of Mappings and Go when the "text" format is requested.
+	Text string `json:"text,omitempty"`
+}
+
+// SourceMapMapping is a run of characters in the templ file that map to the generated Go.
+type SourceMapMapping struct {
+	SourceLine uint32 `json:"sourceLine"`
+	SourceCol  uint32 `json:"sourceCol"`
+	TargetLine uint32 `json:"targetLine"`
+	TargetCol  uint32 `json:"targetCol"`
+	Length     int    `json:"length"`
+	Text       string `json:"text"`
+}
+
+// SourceMap returns the cached source map of a templ file, for debugging position mapping.
+func (p *Server) SourceMap(ctx context.Context, params interface{}) (result *SourceMapResult, err error) {
+	var smp SourceMapParams
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &smp); err != nil {
+		return nil, err
+	}
+	templURI := string(smp.TextDocument.URI)
+	p.Log.Info("client -> server: SourceMap", zap.String("uri", templURI))
+	sm, ok := p.SourceMapCache.Get(templURI)
+	if !ok {
+		return nil, fmt.Errorf("no source map found for %q", templURI)
+	}
+	var lines []string
+	if doc, ok := p.TemplSource.Get(templURI); ok {
+		lines = doc.Lines
+	}
+	goSource, _ := p.GetGoSource(templURI)
+	result = &SourceMapResult{
+		Mappings: sourceMapMappings(sm, lines),
+		Go:       goSource,
+	}
+	switch smp.Format {
+	case "", "json":
+		return result, nil
+	case "text":
+		return &SourceMapResult{Text: formatSourceMapMappings(result.Mappings)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q, expected \"json\" or \"text\"", smp.Format)
+}
+
+func sourceMapMappings(sm *parser.SourceMap, lines []string) (mappings []SourceMapMapping) {
+	for _, m := range sm.Mappings() {
+		mapping := SourceMapMapping{
+			SourceLine: m.Source.Line,
+			SourceCol:  m.Source.Col,
+			TargetLine: m.Target.Line,
+			TargetCol:  m.Target.Col,
+			Length:     m.Length,
+		}
+		if int(m.Source.Line) < len(lines) {
+			line := lines[m.Source.Line]
+			from, to := int(m.Source.Col), int(m.Source.Col)+m.Length
+			if to > len(line) {
+				to = len(line)
+			}
+			if from < to {
+				mapping.Text = line[from:to]
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+func formatSourceMapMappings(mappings []SourceMapMapping) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tTARGET\tLENGTH\tTEXT")
+	for _, m := range mappings {
+		fmt.Fprintf(w, "%d:%d\t%d:%d\t%d\t%q\n", m.SourceLine, m.SourceCol, m.TargetLine, m.TargetCol, m.Length, m.Text)
+	}
+	w.Flush()
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+func TestSourceMapDebugRequest(t *testing.T) {
+	s, init := NewServer(zap.NewNop(), testTarget{}, NewSourceMapCache())
+	init(&testClient{})
+	templURI := lsp.DocumentURI("file:///a.templ")
+	err := s.DidOpen(context.Background(), &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{
+			URI:  templURI,
+			Text: "package main\n\ntempl A(name string) {\n\t<div>{ name }</div>\n}\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	params := map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": string(templURI)},
+	}
+
+	t.Run("the request is disabled by default", func(t *testing.T) {
+		_, err := s.Request(context.Background(), "templ/sourceMap", params)
+		var rpcErr *jsonrpc2.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc2.MethodNotFound {
+			t.Errorf("expected a method not found error, got %v", err)
+		}
+	})
+
+	s.DebugRequests = true
+	t.Run("mappings include the source text", func(t *testing.T) {
+		r, err := s.Request(context.Background(), "templ/sourceMap", params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := r.(*SourceMapResult)
+		if !strings.Contains(result.Go, "func A(name string)") {
+			t.Errorf("expected the generated Go to be returned, got %q", result.Go)
+		}
+		for _, m := range result.Mappings {
+			if strings.TrimSpace(m.Text) == "name" && m.SourceLine == 3 && m.SourceCol == 8 {
+				return
+			}
+		}
+		t.Errorf("expected a mapping for the name expression, got %+v", result.Mappings)
+	})
+	t.Run("the text format is a table", func(t *testing.T) {
+		params["format"] = "text"
+		r, err := s.Request(context.Background(), "templ/sourceMap", params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := r.(*SourceMapResult).Text
+		if !strings.HasPrefix(text, "SOURCE") || !strings.Contains(text, `"name `) {
+			t.Errorf("unexpected table:\n%s", text)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// documentSymbols returns the templ, css and script declarations within the template.
+func documentSymbols(lines []string) (symbols []lsp.DocumentSymbol, err error) {
+	text := strings.Join(lines, "\n")
+	tf, err := parseString(text)
+	if err != nil {
+		return nil, err
+	}
+	sc := &spanCollector{text: text}
+	sc.templateFile(tf)
+	for i, n := range tf.Nodes {
+		var name parser.Expression
+		var detail string
+		switch n := n.(type) {
+		case parser.HTMLTemplate:
+			name, detail = n.Expression, "templ"
+		case parser.CSSTemplate:
+			name, detail = n.Name, "css"
+		case parser.ScriptTemplate:
+			name, detail = n.Name, "script"
+		default:
+			continue
+		}
+		selectionRange := lsp.Range{
+			Start: lsp.Position{Line: name.Range.From.Line, Character: name.Range.From.Col},
+			End:   lsp.Position{Line: name.Range.To.Line, Character: name.Range.To.Col},
+		}
+		// Only show the name of the templ, not its parameters.
+		symbolName, offset, length := declarationName(name.Value)
+		selectionRange.Start.Character += uint32(offset)
+		selectionRange.End.Character = selectionRange.Start.Character + uint32(length)
+		symbol := lsp.DocumentSymbol{
+			Name:           symbolName,
+			Detail:         detail,
+			Kind:           lsp.SymbolKindFunction,
+			Range:          selectionRange,
+			SelectionRange: selectionRange,
+		}
+		if s, ok := sc.declarations[i]; ok {
+			symbol.Range = lsp.Range{
+				Start: positionOfIndex(text, s.from),
+				End:   positionOfIndex(text, s.to),
+			}
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+// declarationName returns the name of a declaration from its header, without its type
+// parameters or parameters, and the offset and length of the name within the header.
+// Methods are qualified by their receiver type in the same way as gopls, e.g. the
+// name of "(p *Person) Card()" is "(*Person).Card".
+func declarationName(header string) (name string, offset, length int) {
+	var receiver string
+	if strings.HasPrefix(header, "(") {
+		end := strings.Index(header, ")")
+		if end < 0 {
+			return header, 0, len(header)
+		}
+		if fields := strings.Fields(header[1:end]); len(fields) > 0 {
+			receiver = fields[len(fields)-1]
+		}
+		offset = end + 1
+		offset += len(header[offset:]) - len(strings.TrimLeft(header[offset:], " \t"))
+	}
+	name = header[offset:]
+	if end := strings.IndexAny(name, "[("); end >= 0 {
+		name = name[:end]
+	}
+	length = len(name)
+	if receiver != "" {
+		name = "(" + receiver + ")." + name
+	}
+	return name, offset, length
+}
+
+// unqualifiedName removes the receiver type from a declaration name, e.g. "(*Person).Card" becomes "Card".
+func unqualifiedName(name string) string {
+	if i := strings.LastIndex(name, ")."); i >= 0 {
+		return name[i+2:]
+	}
+	return name
+}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	lsp "github.com/a-h/protocol"
+	"go.lsp.dev/uri"
+)
+
+// workspaceFolders are the directories that gopls has been asked to work on. gopls
+// rejects documents outside of them, so templ files elsewhere are handled by the
+// proxy alone.
+type workspaceFolders struct {
+	m     sync.Mutex
+	paths map[string]struct{}
+}
+
+func (wf *workspaceFolders) Add(folderURIs ...string) {
+	wf.m.Lock()
+	defer wf.m.Unlock()
+	if wf.paths == nil {
+		wf.paths = make(map[string]struct{})
+	}
+	for _, u := range folderURIs {
+		if u == "" {
+			continue
+		}
+		wf.paths[filepath.Clean(uri.URI(u).Filename())] = struct{}{}
+	}
+}
+
+func (wf *workspaceFolders) Remove(folderURIs ...string) {
+	wf.m.Lock()
+	defer wf.m.Unlock()
+	for _, u := range folderURIs {
+		delete(wf.paths, filepath.Clean(uri.URI(u).Filename()))
+	}
+}
+
+// Contains returns true if the document is within one of the workspace folders. If
+// no folders are known, all documents are assumed to be within the workspace.
+func (wf *workspaceFolders) Contains(documentURI lsp.DocumentURI) bool {
+	wf.m.Lock()
+	defer wf.m.Unlock()
+	if len(wf.paths) == 0 {
+		return true
+	}
+	fileName := filepath.Clean(documentURI.Filename())
+	for path := range wf.paths {
+		if fileName == path || strings.HasPrefix(fileName, path+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Paths returns the directories of the workspace folders, in order.
+func (wf *workspaceFolders) Paths() (paths []string) {
+	wf.m.Lock()
+	defer wf.m.Unlock()
+	for path := range wf.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func workspaceFolderURIs(folders []lsp.WorkspaceFolder) (uris []string) {
+	for _, f := range folders {
+		uris = append(uris, f.URI)
+	}
+	return uris
+}
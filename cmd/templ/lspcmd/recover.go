@@ -0,0 +1,56 @@
+package lspcmd
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+// newServerConn is equivalent to protocol.NewServer, except that panics in the server are
+// recovered, and calls are handled concurrently, with timeouts.
+func newServerConn(ctx context.Context, log *zap.Logger, opts RequestOptions, server protocol.Server, stream jsonrpc2.Stream) (jsonrpc2.Conn, protocol.Client) {
+	conn := jsonrpc2.NewConn(stream)
+	client := protocol.ClientDispatcher(conn, log.Named("client"))
+	ctx = protocol.WithClient(ctx, client)
+	conn.Go(ctx, protocol.CancelHandler(
+		concurrentHandler(log, opts,
+			jsonrpc2.ReplyHandler(recoverHandler(log, protocol.ServerHandler(server, jsonrpc2.MethodNotFoundHandler))),
+		),
+	))
+	return conn, client
+}
+
+// newClientConn is equivalent to protocol.NewClient, except that panics in the client are recovered.
+func newClientConn(ctx context.Context, log *zap.Logger, client protocol.Client, stream jsonrpc2.Stream) (jsonrpc2.Conn, protocol.Server) {
+	ctx = protocol.WithClient(ctx, client)
+	conn := jsonrpc2.NewConn(stream)
+	conn.Go(ctx, protocol.Handlers(recoverHandler(log, protocol.ClientHandler(client, jsonrpc2.MethodNotFoundHandler))))
+	return conn, protocol.ServerDispatcher(conn, log.Named("server"))
+}
+
+// recoverHandler recovers from panics in the handler, so that a single bad request doesn't
+// take down the whole LSP. Calls that panic are replied to with an internal error, so that
+// the caller isn't left waiting.
+func recoverHandler(log *zap.Logger, handler jsonrpc2.Handler) jsonrpc2.Handler {
+	return func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) (err error) {
+		var replied bool
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			log.Error("recovered from panic", zap.String("method", req.Method()), zap.Any("recovered", r), zap.String("stack", string(debug.Stack())))
+			if replied {
+				return
+			}
+			err = reply(ctx, nil, jsonrpc2.Errorf(jsonrpc2.InternalError, "templ: panic handling %q: %v", req.Method(), r))
+		}()
+		return handler(ctx, func(ctx context.Context, result interface{}, err error) error {
+			replied = true
+			return reply(ctx, result, err)
+		}, req)
+	}
+}
@@ -0,0 +1,59 @@
+package lspcmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+// panickingServer panics during completion, to simulate a bug in the rewrite functions.
+type panickingServer struct {
+	protocol.Server
+}
+
+func (s panickingServer) Completion(ctx context.Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
+	var sourceMap map[string]string
+	sourceMap["nil map"] = "panic"
+	return nil, nil
+}
+
+func (s panickingServer) Hover(ctx context.Context, params *protocol.HoverParams) (*protocol.Hover, error) {
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{Kind: protocol.PlainText, Value: "hover"},
+	}, nil
+}
+
+func TestPanicsAreRecovered(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	log := zap.NewNop()
+
+	serverSide, clientSide := net.Pipe()
+	serverConn, _ := newServerConn(ctx, log, RequestOptions{}, panickingServer{}, jsonrpc2.NewStream(serverSide))
+	defer serverConn.Close()
+	_, clientConn, server := protocol.NewClient(ctx, newFakeClient(), jsonrpc2.NewStream(clientSide), log)
+	defer clientConn.Close()
+
+	_, err := server.Completion(ctx, &protocol.CompletionParams{})
+	if err == nil {
+		t.Fatal("expected an error from the panicking request, got nil")
+	}
+	var rpcErr *jsonrpc2.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc2.InternalError {
+		t.Errorf("expected an internal error, got %v", err)
+	}
+
+	hover, err := server.Hover(ctx, &protocol.HoverParams{})
+	if err != nil {
+		t.Fatalf("expected subsequent requests to succeed, got %v", err)
+	}
+	if hover == nil || hover.Contents.Value != "hover" {
+		t.Errorf("unexpected hover result: %#v", hover)
+	}
+}
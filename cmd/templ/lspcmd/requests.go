@@ -0,0 +1,142 @@
+package lspcmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+// codeRequestFailed is the LSP error code for a request that was valid, but failed.
+const codeRequestFailed jsonrpc2.Code = -32803
+
+const (
+	// defaultTimeout is how long templ waits for the requests that aren't in
+	// defaultTimeouts.
+	defaultTimeout = 30 * time.Second
+	// defaultSlowRequest is how long a request can take before it's logged as slow.
+	defaultSlowRequest = time.Second
+)
+
+// defaultTimeouts are the timeouts of requests that differ from the defaultTimeout. gopls
+// loads the workspace during initialize, and searches all of it for workspace symbols,
+// while completion and hover are used while typing, so they're no use if they're late.
+var defaultTimeouts = map[string]time.Duration{
+	protocol.MethodInitialize:                2 * time.Minute,
+	protocol.MethodWorkspaceSymbol:           time.Minute,
+	protocol.MethodTextDocumentCompletion:    10 * time.Second,
+	protocol.MethodTextDocumentHover:         10 * time.Second,
+	protocol.MethodTextDocumentSignatureHelp: 10 * time.Second,
+}
+
+// RequestOptions configure how requests from the editor are handled.
+type RequestOptions struct {
+	// Timeouts override the defaultTimeouts, keyed by method. A timeout of zero waits forever.
+	Timeouts map[string]time.Duration
+	// SlowRequest is how long a request can take before it's logged as slow. If it's zero,
+	// the defaultSlowRequest is used.
+	SlowRequest time.Duration
+}
+
+func (o RequestOptions) timeout(method string) time.Duration {
+	if d, ok := o.Timeouts[method]; ok {
+		return d
+	}
+	if d, ok := defaultTimeouts[method]; ok {
+		return d
+	}
+	return defaultTimeout
+}
+
+func (o RequestOptions) slowRequest() time.Duration {
+	if o.SlowRequest > 0 {
+		return o.SlowRequest
+	}
+	return defaultSlowRequest
+}
+
+// concurrentHandler is used in place of jsonrpc2.AsyncHandler, which waits for each request
+// to be replied to before it handles the next one, so a slow gopls call blocks the editor.
+//
+// Calls are handled concurrently, and replied to with an error if they take longer than
+// their timeout. Notifications, such as textDocument/didChange, are handled one at a time,
+// in the order they're received, and calls wait for the notifications received before them,
+// so that they see the latest version of each document.
+func concurrentHandler(log *zap.Logger, opts RequestOptions, handler jsonrpc2.Handler) jsonrpc2.Handler {
+	notificationsHandled := make(chan struct{})
+	close(notificationsHandled)
+	// The handler is only called by the connection's read loop, so notificationsHandled
+	// doesn't need a lock.
+	return func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		previous := notificationsHandled
+		if _, isCall := req.(*jsonrpc2.Call); !isCall {
+			handled := make(chan struct{})
+			notificationsHandled = handled
+			go func() {
+				defer close(handled)
+				<-previous
+				start := time.Now()
+				_ = handler(ctx, reply, req)
+				logIfSlow(log, opts, req.Method(), time.Since(start))
+			}()
+			return nil
+		}
+		go func() {
+			<-previous
+			handleCall(ctx, log, opts, handler, reply, req)
+		}()
+		return nil
+	}
+}
+
+func handleCall(ctx context.Context, log *zap.Logger, opts RequestOptions, handler jsonrpc2.Handler, reply jsonrpc2.Replier, req jsonrpc2.Request) {
+	start := time.Now()
+	timeout := opts.timeout(req.Method())
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// If the call times out, it's replied to straight away, and the handler's reply, once it
+	// notices that it's been cancelled, is dropped.
+	var once sync.Once
+	replyOnce := func(replyCtx context.Context, result interface{}, err error) (replyErr error) {
+		once.Do(func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				log.Warn("request timed out", zap.String("method", req.Method()), zap.Duration("timeout", timeout))
+				result, err = nil, jsonrpc2.Errorf(codeRequestFailed, "templ: %s timed out after %v", req.Method(), timeout)
+			}
+			replyErr = reply(replyCtx, result, err)
+		})
+		return replyErr
+	}
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		_ = handler(ctx, replyOnce, req)
+	}()
+	select {
+	case <-handled:
+		logIfSlow(log, opts, req.Method(), time.Since(start))
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// The editor cancelled the request, and the handler replies.
+			<-handled
+			return
+		}
+		_ = replyOnce(ctx, nil, nil)
+	}
+}
+
+// logIfSlow logs requests that took longer than the slow request threshold, so that users can
+// report which requests are slow.
+func logIfSlow(log *zap.Logger, opts RequestOptions, method string, duration time.Duration) {
+	if duration < opts.slowRequest() {
+		return
+	}
+	log.Warn("slow request", zap.String("method", method), zap.Duration("duration", duration))
+}
@@ -0,0 +1,139 @@
+package lspcmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// slowServer blocks hover requests until they're released or cancelled, and records the
+// documents that it has been told about.
+type slowServer struct {
+	protocol.Server
+	releaseHover chan struct{}
+	changeDelay  time.Duration
+
+	m       sync.Mutex
+	changes []string
+}
+
+func (s *slowServer) Hover(ctx context.Context, params *protocol.HoverParams) (*protocol.Hover, error) {
+	select {
+	case <-s.releaseHover:
+		return &protocol.Hover{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *slowServer) DidChange(ctx context.Context, params *protocol.DidChangeTextDocumentParams) error {
+	time.Sleep(s.changeDelay)
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.changes = append(s.changes, params.ContentChanges[0].Text)
+	return nil
+}
+
+func (s *slowServer) Completion(ctx context.Context, params *protocol.CompletionParams) (*protocol.CompletionList, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	list := &protocol.CompletionList{}
+	for _, c := range s.changes {
+		list.Items = append(list.Items, protocol.CompletionItem{Label: c})
+	}
+	return list, nil
+}
+
+func startSlowServer(t *testing.T, ctx context.Context, log *zap.Logger, opts RequestOptions, s *slowServer) protocol.Server {
+	serverSide, clientSide := net.Pipe()
+	serverConn, _ := newServerConn(ctx, log, opts, s, jsonrpc2.NewStream(serverSide))
+	t.Cleanup(func() { serverConn.Close() })
+	_, clientConn, server := protocol.NewClient(ctx, newFakeClient(), jsonrpc2.NewStream(clientSide), log)
+	t.Cleanup(func() { clientConn.Close() })
+	return server
+}
+
+func TestSlowRequestsDontBlockOtherRequests(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s := &slowServer{releaseHover: make(chan struct{})}
+	opts := RequestOptions{Timeouts: map[string]time.Duration{protocol.MethodTextDocumentHover: 0}}
+	server := startSlowServer(t, ctx, zap.NewNop(), opts, s)
+
+	hoverErr := make(chan error)
+	go func() {
+		_, err := server.Hover(ctx, &protocol.HoverParams{})
+		hoverErr <- err
+	}()
+	if _, err := server.Completion(ctx, &protocol.CompletionParams{}); err != nil {
+		t.Fatalf("expected completion to succeed while hover is in progress, got %v", err)
+	}
+	close(s.releaseHover)
+	if err := <-hoverErr; err != nil {
+		t.Errorf("expected hover to succeed once released, got %v", err)
+	}
+}
+
+func TestRequestsThatTimeOutFail(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	core, logs := observer.New(zapcore.WarnLevel)
+	s := &slowServer{releaseHover: make(chan struct{})}
+	opts := RequestOptions{Timeouts: map[string]time.Duration{protocol.MethodTextDocumentHover: 50 * time.Millisecond}}
+	server := startSlowServer(t, ctx, zap.New(core), opts, s)
+
+	_, err := server.Hover(ctx, &protocol.HoverParams{})
+	var rpcErr *jsonrpc2.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeRequestFailed {
+		t.Fatalf("expected a request failed error, got %v", err)
+	}
+	if n := logs.FilterMessage("request timed out").FilterField(zap.String("method", protocol.MethodTextDocumentHover)).Len(); n != 1 {
+		t.Errorf("expected the timeout to be logged once, got %d", n)
+	}
+}
+
+func TestRequestsWaitForEarlierNotifications(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	core, logs := observer.New(zapcore.WarnLevel)
+	s := &slowServer{changeDelay: 20 * time.Millisecond}
+	server := startSlowServer(t, ctx, zap.New(core), RequestOptions{SlowRequest: 10 * time.Millisecond}, s)
+
+	expected := []string{"a", "b", "c"}
+	for _, text := range expected {
+		err := server.DidChange(ctx, &protocol.DidChangeTextDocumentParams{
+			ContentChanges: []protocol.TextDocumentContentChangeEvent{{Text: text}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	list, err := server.Completion(ctx, &protocol.CompletionParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []string
+	for _, item := range list.Items {
+		actual = append(actual, item.Label)
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected the changes %v to be handled before completion, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected the changes to be handled in order %v, got %v", expected, actual)
+		}
+	}
+	if n := logs.FilterMessage("slow request").FilterField(zap.String("method", protocol.MethodTextDocumentDidChange)).Len(); n != len(expected) {
+		t.Errorf("expected %d slow changes to be logged, got %d", len(expected), n)
+	}
+}
@@ -0,0 +1,52 @@
+package lspcmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+// serveStartupError tells the editor why the LSP couldn't start, by showing a message and
+// replying to the initialize request with an error. Without it, editors report that the
+// server quit unexpectedly. It returns once the editor has been told, or the connection
+// is closed.
+func serveStartupError(ctx context.Context, log *zap.Logger, stream io.ReadWriteCloser, startupErr error) {
+	conn := jsonrpc2.NewConn(jsonrpc2.NewStream(stream))
+	defer conn.Close()
+	message := "templ: " + startupErr.Error()
+	done := make(chan struct{})
+	var once sync.Once
+	conn.Go(ctx, func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		log.Info("startup error: received request", zap.String("method", req.Method()))
+		switch req.Method() {
+		case protocol.MethodInitialize:
+			defer once.Do(func() { close(done) })
+			err := conn.Notify(ctx, protocol.MethodWindowShowMessage, &protocol.ShowMessageParams{
+				Type:    protocol.MessageTypeError,
+				Message: message,
+			})
+			if err != nil {
+				log.Error("failed to show startup error", zap.Error(err))
+			}
+			// Tell the editor not to retry, since nothing will have changed.
+			data := json.RawMessage(`{"retry":false}`)
+			return reply(ctx, nil, &jsonrpc2.Error{Code: jsonrpc2.InternalError, Message: message, Data: &data})
+		case protocol.MethodShutdown:
+			return reply(ctx, nil, nil)
+		case protocol.MethodExit:
+			once.Do(func() { close(done) })
+			return nil
+		}
+		return jsonrpc2.MethodNotFoundHandler(ctx, reply, req)
+	})
+	select {
+	case <-done:
+	case <-conn.Done():
+	case <-ctx.Done():
+	}
+}
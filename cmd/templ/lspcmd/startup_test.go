@@ -0,0 +1,63 @@
+package lspcmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/protocol"
+	"go.lsp.dev/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+type showMessageClient struct {
+	protocol.Client
+	messages chan string
+}
+
+func (c showMessageClient) ShowMessage(ctx context.Context, params *protocol.ShowMessageParams) error {
+	c.messages <- params.Message
+	return nil
+}
+
+func TestStartupErrorsAreReturnedFromInitialize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	log := zap.NewNop()
+	proxySide, editorSide := net.Pipe()
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		serveStartupError(ctx, log, proxySide, errors.New("cannot find gopls on the path"))
+	}()
+
+	client := showMessageClient{messages: make(chan string, 1)}
+	_, conn, server := protocol.NewClient(ctx, client, jsonrpc2.NewStream(editorSide), log)
+	defer conn.Close()
+
+	_, err := server.Initialize(ctx, &protocol.InitializeParams{})
+	var rpcErr *jsonrpc2.Error
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected a JSON-RPC error, got %v", err)
+	}
+	if !strings.Contains(rpcErr.Message, "cannot find gopls") {
+		t.Errorf("expected the error to explain the problem, got %q", rpcErr.Message)
+	}
+	select {
+	case msg := <-client.messages:
+		if !strings.Contains(msg, "cannot find gopls") {
+			t.Errorf("unexpected message: %q", msg)
+		}
+	case <-ctx.Done():
+		t.Error("expected the error to be shown to the user")
+	}
+	select {
+	case <-served:
+	case <-ctx.Done():
+		t.Error("expected the startup error server to exit after replying")
+	}
+}
@@ -1,94 +1,155 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
-	"runtime/debug"
+	"strings"
+	"time"
 
-	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/a-h/templ/cmd/templ/configcmd"
+	"github.com/a-h/templ/cmd/templ/diagnostic"
 	"github.com/a-h/templ/cmd/templ/fmtcmd"
 	"github.com/a-h/templ/cmd/templ/generatecmd"
+	"github.com/a-h/templ/cmd/templ/i18ncmd"
+	"github.com/a-h/templ/cmd/templ/lintcmd"
 	"github.com/a-h/templ/cmd/templ/lspcmd"
 	"github.com/a-h/templ/cmd/templ/migratecmd"
+	"github.com/a-h/templ/cmd/templ/newcmd"
+	"github.com/a-h/templ/cmd/templ/sourcemapcmd"
+	"github.com/a-h/templ/generator"
 )
 
-// Source builds use this value. When installed using `go install github.com/a-h/templ/cmd/templ@latest` the `version` variable is empty, but
-// the debug.ReadBuildInfo return value provides the package version number installed by `go install`
-func goInstallVersion() string {
-	info, ok := debug.ReadBuildInfo()
-	if !ok {
-		return "unknown"
-	}
-	return info.Main.Version
-}
-
-func getVersion() string {
-	if templ.Version != "" {
-		return templ.Version
-	}
-	return goInstallVersion()
-}
-
 func main() {
-	if len(os.Args) < 2 {
+	args := os.Args[1:]
+	// The --json flag can be given before the command, e.g. templ --json generate.
+	var jsonOutput bool
+	if len(args) > 0 && (args[0] == "--json" || args[0] == "-json") {
+		jsonOutput = true
+		args = args[1:]
+	}
+	if len(args) < 1 {
 		usage()
 		os.Exit(1)
 	}
-	switch os.Args[1] {
+	switch args[0] {
 	case "generate":
-		generateCmd(os.Args[2:])
+		generateCmd(args[1:], jsonOutput)
 		return
 	case "migrate":
-		migrateCmd(os.Args[2:])
+		migrateCmd(args[1:])
 		return
 	case "fmt":
-		fmtCmd(os.Args[2:])
+		fmtCmd(args[1:], jsonOutput)
+		return
+	case "lint":
+		lintCmd(args[1:], jsonOutput)
 		return
 	case "lsp":
-		lspCmd(os.Args[2:])
+		lspCmd(args[1:])
+		return
+	case "sourcemap":
+		sourceMapCmd(args[1:])
+		return
+	case "i18n":
+		i18nCmd(args[1:])
+		return
+	case "config":
+		configCmd(args[1:])
+		return
+	case "new":
+		newCmd(args[1:])
+		return
+	case "serve":
+		serveCmd(args[1:])
 		return
 	case "version":
-		fmt.Println(getVersion())
+		fmt.Println(generator.Version())
 		return
 	case "--version":
-		fmt.Println(getVersion())
+		fmt.Println(generator.Version())
 		return
 	}
 	usage()
 }
 
 func usage() {
-	fmt.Println(`usage: templ <command> [parameters]
+	fmt.Println(`usage: templ [--json] <command> [parameters]
 To see help text, you can run:
   templ generate --help
   templ fmt --help
+  templ lint --help
   templ lsp --help
   templ migrate --help
+  templ sourcemap resolve --help
+  templ i18n extract --help
+  templ config init --help
+  templ new --help
+  templ serve --help
   templ version
+The --json flag writes the errors found by generate, fmt -check and lint to stdout as
+newline-delimited JSON, and writes other output to stderr.
 examples:
   templ generate`)
 	os.Exit(1)
 }
 
-func generateCmd(args []string) {
+func generateCmd(args []string, jsonOutput bool) {
 	cmd := flag.NewFlagSet("generate", flag.ExitOnError)
 	fileNameFlag := cmd.String("f", "", "Optionally generates code for a single file, e.g. -f header.templ")
 	pathFlag := cmd.String("path", ".", "Generates code for all files in path.")
 	sourceMapVisualisations := cmd.Bool("sourceMapVisualisations", false, "Set to true to generate HTML files to visualise the templ code and its corresponding Go code.")
-	watchFlag := cmd.Bool("watch", false, "Set to true to watch the path for changes and regenerate code.")
+	includeLineDirectivesFlag := cmd.Bool("include-line-directives", true, "Set to false to omit the //line directives that make stack traces and compiler errors refer to the templ files.")
+	minifyFlag := cmd.Bool("minify", false, "Set to true to remove HTML comments and whitespace that isn't rendered from the generated code.")
+	sourceMapFlag := cmd.Bool("sourcemap", false, "Set to true to write the source map of each generated file to <name>_templ.go.map.")
+	watchFlag := cmd.Bool("watch", false, "Set to true to watch the path for changes to templ files and regenerate code.")
 	cmdFlag := cmd.String("cmd", "", "Set the command to run after generating code.")
 	proxyFlag := cmd.String("proxy", "", "Set the URL to proxy after generating code and executing the command.")
 	proxyPortFlag := cmd.Int("proxyport", 7331, "The port the proxy will listen on.")
-	workerCountFlag := cmd.Int("w", runtime.NumCPU(), "Number of workers to run in parallel.")
+	openBrowserFlag := cmd.Bool("open-browser", true, "Set to false to not open the proxy in the browser.")
+	workerCountFlag := cmd.Int("w", runtime.GOMAXPROCS(0), "Number of workers to run in parallel.")
+	cmd.IntVar(workerCountFlag, "workers", runtime.GOMAXPROCS(0), "Number of workers to run in parallel, the same as -w.")
+	failFastFlag := cmd.Bool("fail-fast", false, "Set to true to stop generating code after the first error.")
+	forceFlag := cmd.Bool("force", false, "Set to true to generate code for all files, even if it's up to date.")
+	cleanFlag := cmd.Bool("clean", false, "Set to true to remove code generated from templ files that no longer exist.")
+	strictVersionFlag := cmd.Bool("strict-version", false, "Set to true to exit with an error, instead of a warning, if code generated by an incompatible version of templ is found.")
+	outDirFlag := cmd.String("out-dir", "", "The directory to write the generated code to, mirroring the directory tree under -path. Overrides generate.outDir in templ.json.")
+	suffixFlag := cmd.String("suffix", "", "The suffix of the generated Go files (default \"_templ.go\"). Overrides generate.suffix in templ.json.")
+	buildTagsFlag := cmd.String("build-tags", "", "A build constraint to write in a //go:build line in each generated file, e.g. -build-tags '!dev'.")
+	tagSuffixFlag := cmd.String("tag-suffix", "", "Added to the names of the generated Go files, before .go, so that a variant generated with -build-tags can be written alongside the default code, e.g. -tag-suffix _dev writes home_templ_dev.go.")
 	pprofPortFlag := cmd.Int("pprof", 0, "Port to start pprof web server on.")
+	jsonFlag := cmd.Bool("json", jsonOutput, jsonUsage)
 	helpFlag := cmd.Bool("help", false, "Print help and exit.")
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
 		cmd.PrintDefaults()
 		return
 	}
+	c := loadConfig(*pathFlag)
+	set := setFlags(cmd)
+	if !set["include-line-directives"] && c.Generate.IncludeLineDirectives != nil {
+		*includeLineDirectivesFlag = *c.Generate.IncludeLineDirectives
+	}
+	if !set["minify"] {
+		*minifyFlag = c.Generate.Minify
+	}
+	if !set["sourcemap"] {
+		*sourceMapFlag = c.Generate.SourceMap
+	}
+	if !set["w"] && !set["workers"] && c.Generate.Workers > 0 {
+		*workerCountFlag = c.Generate.Workers
+	}
+	if !set["build-tags"] {
+		*buildTagsFlag = c.Generate.BuildTags
+	}
+	if !set["tag-suffix"] {
+		*tagSuffixFlag = c.Generate.TagSuffix
+	}
 	err = generatecmd.Run(generatecmd.Arguments{
 		FileName:                        *fileNameFlag,
 		Path:                            *pathFlag,
@@ -96,9 +157,72 @@ func generateCmd(args []string) {
 		Command:                         *cmdFlag,
 		Proxy:                           *proxyFlag,
 		ProxyPort:                       *proxyPortFlag,
+		OpenBrowser:                     *openBrowserFlag,
 		WorkerCount:                     *workerCountFlag,
+		FailFast:                        *failFastFlag,
+		Force:                           *forceFlag,
+		Clean:                           *cleanFlag,
+		StrictVersion:                   *strictVersionFlag,
 		GenerateSourceMapVisualisations: *sourceMapVisualisations,
+		GenerateSourceMaps:              *sourceMapFlag,
+		IncludeLineDirectives:           *includeLineDirectivesFlag,
+		Minify:                          *minifyFlag,
+		OutDir:                          *outDirFlag,
+		Suffix:                          *suffixFlag,
+		BuildTags:                       *buildTagsFlag,
+		TagSuffix:                       *tagSuffixFlag,
 		PPROFPort:                       *pprofPortFlag,
+		Log:                             logOutput(*jsonFlag),
+		Diagnostics:                     diagnostics(*jsonFlag),
+	})
+	if err != nil {
+		fmt.Fprintln(logOutput(*jsonFlag), err.Error())
+		os.Exit(1)
+	}
+}
+
+func serveCmd(args []string) {
+	cmd := flag.NewFlagSet("serve", flag.ExitOnError)
+	pathFlag := cmd.String("path", ".", "Watches the templ files in path.")
+	proxyFlag := cmd.String("proxy", "", "The URL of the app to proxy, e.g. http://localhost:8080.")
+	proxyPortFlag := cmd.Int("proxyport", 7331, "The port the proxy will listen on.")
+	cmdFlag := cmd.String("cmd", "", "The command that builds and runs the app, e.g. \"go run .\". It's restarted after code is generated.")
+	openBrowserFlag := cmd.Bool("open-browser", true, "Set to false to not open the proxy in the browser.")
+	workerCountFlag := cmd.Int("w", runtime.GOMAXPROCS(0), "Number of workers to run in parallel.")
+	helpFlag := cmd.Bool("help", false, "Print help and exit.")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag || *proxyFlag == "" || cmd.NArg() != 0 {
+		fmt.Println(`usage: templ serve -proxy <url> [flags]
+Watches the templ files in path, and regenerates code when they change. The
+command is restarted after code is generated, and the proxy reloads the pages
+in the browser once the app is ready. If code can't be generated, the errors
+are shown in the browser instead, and the app isn't restarted.
+examples:
+  templ serve -proxy http://localhost:8080 -cmd "go run ."`)
+		cmd.PrintDefaults()
+		return
+	}
+	c := loadConfig(*pathFlag)
+	includeLineDirectives := true
+	if c.Generate.IncludeLineDirectives != nil {
+		includeLineDirectives = *c.Generate.IncludeLineDirectives
+	}
+	if !setFlags(cmd)["w"] && c.Generate.Workers > 0 {
+		*workerCountFlag = c.Generate.Workers
+	}
+	err = generatecmd.Run(generatecmd.Arguments{
+		Path:                  *pathFlag,
+		Watch:                 true,
+		Command:               *cmdFlag,
+		Proxy:                 *proxyFlag,
+		ProxyPort:             *proxyPortFlag,
+		OpenBrowser:           *openBrowserFlag,
+		WorkerCount:           *workerCountFlag,
+		GenerateSourceMaps:    c.Generate.SourceMap,
+		IncludeLineDirectives: includeLineDirectives,
+		Minify:                c.Generate.Minify,
+		BuildTags:             c.Generate.BuildTags,
+		TagSuffix:             c.Generate.TagSuffix,
 	})
 	if err != nil {
 		fmt.Println(err.Error())
@@ -108,17 +232,22 @@ func generateCmd(args []string) {
 
 func migrateCmd(args []string) {
 	cmd := flag.NewFlagSet("migrate", flag.ExitOnError)
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "usage: templ migrate [flags] [pattern ...]\nMigrates templ v1 files to templ v2, or, if patterns are given, converts the html/template files that match them to templ files.")
+		cmd.PrintDefaults()
+	}
 	fileName := cmd.String("f", "", "Optionally migrate a single file, e.g. -f header.templ")
 	path := cmd.String("path", ".", "Migrates code for all files in path.")
 	helpFlag := cmd.Bool("help", false, "Print help and exit.")
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
-		cmd.PrintDefaults()
+		cmd.Usage()
 		return
 	}
 	err = migratecmd.Run(migratecmd.Arguments{
 		FileName: *fileName,
 		Path:     *path,
+		Globs:    cmd.Args(),
 	})
 	if err != nil {
 		fmt.Println(err.Error())
@@ -126,15 +255,153 @@ func migrateCmd(args []string) {
 	}
 }
 
-func fmtCmd(args []string) {
+func fmtCmd(args []string, jsonOutput bool) {
 	cmd := flag.NewFlagSet("fmt", flag.ExitOnError)
+	cmd.Usage = func() {
+		fmt.Fprintln(cmd.Output(), "usage: templ fmt [flags] [path ...]\nFormats the templ files in each path, or stdin if the path is - or omitted.")
+		cmd.PrintDefaults()
+	}
+	checkFlag := cmd.Bool("check", false, "Set to true to print a diff of the files that aren't formatted, and exit with a non-zero status code, instead of formatting them.")
+	cmd.BoolVar(checkFlag, "d", false, "Print a diff of the files that aren't formatted, the same as -check.")
+	jsonFlag := cmd.Bool("json", jsonOutput, jsonUsage)
+	helpFlag := cmd.Bool("help", false, "Print help and exit.")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag {
+		cmd.Usage()
+		return
+	}
+	err = fmtcmd.Run(os.Stdin, os.Stdout, fmtcmd.Arguments{
+		Paths:       cmd.Args(),
+		Check:       *checkFlag,
+		Log:         logOutput(*jsonFlag),
+		Diagnostics: diagnostics(*jsonFlag),
+	})
+	if err == fmtcmd.ErrUnformattedFiles {
+		// The diff has already been printed.
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(logOutput(*jsonFlag), err.Error())
+		os.Exit(1)
+	}
+}
+
+func lintCmd(args []string, jsonOutput bool) {
+	cmd := flag.NewFlagSet("lint", flag.ExitOnError)
+	fileName := cmd.String("f", "", "Optionally lint a single file, e.g. -f header.templ")
+	path := cmd.String("path", ".", "Lints all files in path, if no paths are given as arguments.")
+	enableFlag := cmd.String("enable", "", "The optional rules to enable, separated by commas, e.g. -enable inline-style. Overrides lint.enable in templ.json.")
+	disableFlag := cmd.String("disable", "", "The rules to disable, separated by commas, e.g. -disable no-alt,unused-parameter. Overrides lint.disable in templ.json.")
+	formatFlag := cmd.String("format", lintcmd.FormatText, "The output format, text or json.")
+	jsonFlag := cmd.Bool("json", jsonOutput, jsonUsage+" The same as -format json.")
 	helpFlag := cmd.Bool("help", false, "Print help and exit.")
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
+		fmt.Println("usage: templ lint [flags] [paths...]")
 		cmd.PrintDefaults()
 		return
 	}
-	err = fmtcmd.Run(args)
+	paths := cmd.Args()
+	if *fileName != "" {
+		paths = append(paths, *fileName)
+	}
+	if len(paths) == 0 {
+		paths = []string{*path}
+	}
+	configDir := paths[0]
+	if info, err := os.Stat(configDir); err == nil && !info.IsDir() {
+		configDir = filepath.Dir(configDir)
+	}
+	c := loadConfig(configDir)
+	enable, disable := c.Lint.Enable, c.Lint.Disable
+	set := setFlags(cmd)
+	if set["enable"] {
+		enable = splitList(*enableFlag)
+	}
+	if set["disable"] {
+		disable = splitList(*disableFlag)
+	}
+	if *jsonFlag {
+		*formatFlag = lintcmd.FormatJSON
+	}
+	err = lintcmd.Run(os.Stdout, lintcmd.Arguments{
+		Paths:   paths,
+		Enable:  enable,
+		Disable: disable,
+		Format:  *formatFlag,
+	})
+	if errors.Is(err, lintcmd.ErrIssuesFound) {
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(logOutput(*formatFlag == lintcmd.FormatJSON), err.Error())
+		os.Exit(1)
+	}
+}
+
+// splitList splits a comma separated flag value, ignoring empty items.
+func splitList(s string) (items []string) {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func i18nCmd(args []string) {
+	if len(args) == 0 || args[0] != "extract" {
+		fmt.Println(`usage: templ i18n extract [flags] [paths...]`)
+		os.Exit(1)
+	}
+	cmd := flag.NewFlagSet("i18n extract", flag.ExitOnError)
+	path := cmd.String("path", ".", "Extracts text from all files in path, if no paths are given as arguments.")
+	formatFlag := cmd.String("format", i18ncmd.FormatPOT, "The catalog format, pot or json.")
+	outputFlag := cmd.String("o", "", "The file to write the catalog to, or leave empty to write it to stdout.")
+	funcFlag := cmd.String("func", "t", "The translation function, whose string literal arguments are extracted as message IDs, e.g. -func i18n.T.")
+	helpFlag := cmd.Bool("help", false, "Print help and exit.")
+	err := cmd.Parse(args[1:])
+	if err != nil || *helpFlag {
+		fmt.Println(`usage: templ i18n extract [flags] [paths...]
+Writes the text written in templates, and the message IDs passed to the translation
+function, to a gettext template (pot) or JSON catalog.`)
+		cmd.PrintDefaults()
+		return
+	}
+	paths := cmd.Args()
+	if len(paths) == 0 {
+		paths = []string{*path}
+	}
+	err = i18ncmd.Extract(os.Stdout, i18ncmd.Arguments{
+		Paths:    paths,
+		Format:   *formatFlag,
+		Output:   *outputFlag,
+		Function: *funcFlag,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func sourceMapCmd(args []string) {
+	if len(args) == 0 || args[0] != "resolve" {
+		fmt.Println(`usage: templ sourcemap resolve <file_templ.go:line[:col]>`)
+		os.Exit(1)
+	}
+	cmd := flag.NewFlagSet("sourcemap resolve", flag.ExitOnError)
+	helpFlag := cmd.Bool("help", false, "Print help and exit.")
+	err := cmd.Parse(args[1:])
+	if err != nil || *helpFlag || cmd.NArg() != 1 {
+		fmt.Println(`usage: templ sourcemap resolve <file_templ.go:line[:col]>
+Prints the position in the templ file that generated the Go code, using the
+source map written by templ generate -sourcemap.`)
+		cmd.PrintDefaults()
+		return
+	}
+	err = sourcemapcmd.Run(os.Stdout, sourcemapcmd.Arguments{
+		Position: cmd.Arg(0),
+	})
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
@@ -149,20 +416,165 @@ func lspCmd(args []string) {
 	helpFlag := cmd.Bool("help", false, "Print help and exit.")
 	pprofFlag := cmd.Bool("pprof", false, "Enable pprof web server (default address is localhost:9999)")
 	httpDebugFlag := cmd.String("http", "", "Enable http debug server by setting a listen address (e.g. localhost:7474)")
+	debugFlag := cmd.Bool("debug", false, "Enable debug requests, such as templ/sourceMap.")
+	outDirFlag := cmd.String("out-dir", "", "The directory that templ generate writes the generated code to, relative to the working directory. If -out-dir or -suffix is set, the generate settings in templ.json files are ignored.")
+	suffixFlag := cmd.String("suffix", "", "The suffix of the Go files generated by templ generate (default \"_templ.go\").")
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
 		cmd.PrintDefaults()
 		return
 	}
+	// The editor starts the language server in the workspace, and stdout is used for the
+	// protocol, so the templ.json warnings are shown by the language server instead.
+	c, err := config.Load(".")
+	if err != nil {
+		c = config.Default(".")
+	}
+	set := setFlags(cmd)
+	if !set["log"] {
+		*log = c.LSP.Log
+	}
+	if !set["goplsLog"] {
+		*goplsLog = c.LSP.GoplsLog
+	}
 	err = lspcmd.Run(lspcmd.Arguments{
 		Log:           *log,
 		GoplsLog:      *goplsLog,
 		GoplsRPCTrace: *goplsRPCTrace,
 		PPROF:         *pprofFlag,
 		HTTPDebug:     *httpDebugFlag,
+		Debug:         *debugFlag,
+		OutDir:        *outDirFlag,
+		Suffix:        *suffixFlag,
+		Requests:      lspRequestOptions(c.LSP),
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+// lspRequestOptions returns the request timeouts set in templ.json.
+func lspRequestOptions(c config.LSP) (opts lspcmd.RequestOptions) {
+	opts.SlowRequest = time.Duration(c.SlowRequest)
+	if len(c.Timeouts) > 0 {
+		opts.Timeouts = make(map[string]time.Duration, len(c.Timeouts))
+		for method, d := range c.Timeouts {
+			opts.Timeouts[method] = time.Duration(d)
+		}
+	}
+	return opts
+}
+
+func configCmd(args []string) {
+	if len(args) == 0 || args[0] != "init" {
+		fmt.Println(`usage: templ config init`)
+		os.Exit(1)
+	}
+	cmd := flag.NewFlagSet("config init", flag.ExitOnError)
+	helpFlag := cmd.Bool("help", false, "Print help and exit.")
+	err := cmd.Parse(args[1:])
+	if err != nil || *helpFlag || cmd.NArg() != 0 {
+		fmt.Println(`usage: templ config init
+Writes a templ.json file containing the default settings, with comments that
+describe them, to the current directory.`)
+		cmd.PrintDefaults()
+		return
+	}
+	err = configcmd.Init(os.Stdout, configcmd.Arguments{
+		Dir: ".",
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func newCmd(args []string) {
+	cmd := flag.NewFlagSet("new", flag.ExitOnError)
+	dirFlag := cmd.String("dir", ".", "The directory to write the files to.")
+	layoutFlag := cmd.String("layout", "Layout", "The name of the layout that a page renders its content within.")
+	testFlag := cmd.Bool("test", false, "Set to true to also write a _test.go file that renders the template.")
+	forceFlag := cmd.Bool("force", false, "Set to true to overwrite existing files.")
+	helpFlag := cmd.Bool("help", false, "Print help and exit.")
+	err := cmd.Parse(args)
+	// Flags can be given after the kind and name, e.g. templ new component UserCard -test.
+	if err == nil && cmd.NArg() > 2 {
+		positional := cmd.Args()[:2]
+		err = cmd.Parse(cmd.Args()[2:])
+		if cmd.NArg() != 0 {
+			err = fmt.Errorf("unexpected arguments: %s", strings.Join(cmd.Args(), " "))
+		}
+		cmd.Parse(positional)
+	}
+	if err != nil || *helpFlag || cmd.NArg() != 2 {
+		fmt.Println(`usage: templ new [flags] <component|page|layout> <Name>
+Writes a templ file, named after the template, from a built-in template:
+  component  a component with parameters
+  page       an HTML document that renders its content within a layout
+  layout     a component that renders its children within a header and footer
+The package name is taken from the files in the directory, or its name.
+Templates in a .templ/templates directory, in the directory or its parents up to
+the root of the module, replace the built-in templates, or add new kinds, e.g.
+.templ/templates/page.templ.tmpl and .templ/templates/page_test.go.tmpl.`)
+		cmd.PrintDefaults()
+		return
+	}
+	err = newcmd.Run(os.Stdout, newcmd.Arguments{
+		Kind:   cmd.Arg(0),
+		Name:   cmd.Arg(1),
+		Dir:    *dirFlag,
+		Layout: *layoutFlag,
+		Test:   *testFlag,
+		Force:  *forceFlag,
 	})
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 }
+
+// loadConfig loads the templ.json file that applies to dir, and prints its warnings. An
+// invalid templ.json file is a fatal error.
+func loadConfig(dir string) config.Config {
+	c, err := config.Load(dir)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	for _, warning := range c.Warnings {
+		// Warnings are written to stderr, so that they don't mix with JSON output.
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+	return c
+}
+
+const jsonUsage = "Set to true to write errors to stdout as newline-delimited JSON, and other output to stderr."
+
+// logOutput returns where human readable output is written. When JSON is written to stdout,
+// it's written to stderr instead, so that the two aren't mixed.
+func logOutput(jsonOutput bool) io.Writer {
+	if jsonOutput {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// diagnostics returns the encoder that JSON is written to stdout with, or nil if JSON output
+// isn't enabled.
+func diagnostics(jsonOutput bool) *diagnostic.Encoder {
+	if !jsonOutput {
+		return nil
+	}
+	return diagnostic.NewEncoder(os.Stdout)
+}
+
+// setFlags returns the names of the flags that were set on the command line, so that they
+// can override the settings in templ.json.
+func setFlags(cmd *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	cmd.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
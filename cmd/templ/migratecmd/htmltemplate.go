@@ -0,0 +1,1087 @@
+package migratecmd
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+	"time"
+	"unicode"
+
+	"github.com/a-h/templ/generator"
+	v2 "github.com/a-h/templ/parser/v2"
+	"github.com/natefinch/atomic"
+)
+
+// todoPrefix starts the comments left in place of html/template constructs that can't be
+// converted, so that they can be found and converted by hand.
+const todoPrefix = "TODO(templ-migrate): "
+
+// The constructs counted in the summary.
+const (
+	convertedValues        = "values"
+	convertedConditionals  = "conditionals"
+	convertedLoops         = "loops"
+	convertedTemplateCalls = "template calls"
+	convertedComments      = "comments"
+
+	flaggedFunctions = "functions"
+	flaggedPipelines = "pipelines"
+	flaggedWith      = "with blocks"
+	flaggedVariables = "variables"
+	flaggedContext   = "actions in attribute names, scripts and styles"
+	flaggedOther     = "other actions"
+)
+
+// migrationReport counts the constructs that were converted, and the constructs that were
+// left for the user to convert.
+type migrationReport struct {
+	converted map[string]int
+	flagged   map[string]int
+}
+
+func newMigrationReport() *migrationReport {
+	return &migrationReport{
+		converted: make(map[string]int),
+		flagged:   make(map[string]int),
+	}
+}
+
+func (r *migrationReport) add(other *migrationReport) {
+	for k, v := range other.converted {
+		r.converted[k] += v
+	}
+	for k, v := range other.flagged {
+		r.flagged[k] += v
+	}
+}
+
+func (r *migrationReport) total(counts map[string]int) (n int) {
+	for _, v := range counts {
+		n += v
+	}
+	return n
+}
+
+func (r *migrationReport) write(w io.Writer) {
+	for _, line := range []struct {
+		label  string
+		counts map[string]int
+	}{
+		{label: "converted", counts: r.converted},
+		{label: "flagged", counts: r.flagged},
+	} {
+		kinds := make([]string, 0, len(line.counts))
+		for k := range line.counts {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		items := make([]string, len(kinds))
+		for i, k := range kinds {
+			items[i] = fmt.Sprintf("%d %s", line.counts[k], k)
+		}
+		if len(items) == 0 {
+			items = []string{"none"}
+		}
+		fmt.Fprintf(w, "  %-10s %s\n", line.label+":", strings.Join(items, ", "))
+	}
+}
+
+// migrateHTMLTemplates converts the html/template files that match the glob patterns to
+// templ files, which are written alongside them, and writes a summary to w.
+func migrateHTMLTemplates(w io.Writer, patterns []string) (err error) {
+	start := time.Now()
+	var fileNames []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no files match %q", pattern)
+		}
+		fileNames = append(fileNames, matches...)
+	}
+	sort.Strings(fileNames)
+	total := newMigrationReport()
+	var errorCount int
+	for _, fileName := range fileNames {
+		targetFileName, r, migrateErr := migrateHTMLTemplate(fileName)
+		if migrateErr != nil {
+			err = errors.Join(err, migrateErr)
+			errorCount++
+			continue
+		}
+		total.add(r)
+		fmt.Fprintf(w, "%s -> %s: %d converted, %d flagged\n", fileName, targetFileName, r.total(r.converted), r.total(r.flagged))
+	}
+	fmt.Fprintf(w, "Migrated %d html/template files with %d errors in %s\n", len(fileNames), errorCount, time.Since(start))
+	total.write(w)
+	if total.total(total.flagged) > 0 {
+		fmt.Fprintf(w, "Search for %q to find the constructs that need to be converted by hand.\n", strings.TrimSuffix(todoPrefix, ": "))
+	}
+	return err
+}
+
+// migrateHTMLTemplate converts the html/template file to a templ file with the same name,
+// and a .templ extension. Existing files aren't overwritten.
+func migrateHTMLTemplate(fileName string) (targetFileName string, r *migrationReport, err error) {
+	base := filepath.Base(fileName)
+	if i := strings.Index(base, "."); i > 0 {
+		base = base[:i]
+	}
+	targetFileName = filepath.Join(filepath.Dir(fileName), base+".templ")
+	if targetFileName == fileName {
+		return "", nil, fmt.Errorf("%s: is already a templ file", fileName)
+	}
+	if _, err = os.Stat(targetFileName); err == nil {
+		return "", nil, fmt.Errorf("%s: %s already exists", fileName, targetFileName)
+	}
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: failed to read file: %w", fileName, err)
+	}
+	pkg := filepath.Base(filepath.Dir(fileName))
+	if abs, err := filepath.Abs(fileName); err == nil {
+		pkg = filepath.Base(filepath.Dir(abs))
+	}
+	output, r, err := convertHTMLTemplate(fileName, string(src), packageName(pkg))
+	if err != nil {
+		return "", nil, err
+	}
+	// Write the output, even if it can't be parsed, so that it can be fixed by hand.
+	formatted, formatErr := formatTempl(output)
+	if formatErr != nil {
+		formatted = output
+	}
+	if err = atomic.WriteFile(targetFileName, strings.NewReader(formatted)); err != nil {
+		return "", nil, fmt.Errorf("%s: failed to write file: %w", targetFileName, err)
+	}
+	if formatErr != nil {
+		return "", nil, fmt.Errorf("%s: the converted template isn't valid templ, and must be fixed by hand: %w", fileName, v2.FileError{FileName: targetFileName, Err: formatErr})
+	}
+	return targetFileName, r, nil
+}
+
+func formatTempl(src string) (string, error) {
+	tf, err := v2.ParseString(src)
+	if err != nil {
+		return "", err
+	}
+	w := new(strings.Builder)
+	if err = tf.Write(w); err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
+
+// convertHTMLTemplate converts the html/template source to templ source. The root template,
+// and each template defined with {{define}} or {{block}}, becomes a templ component. The
+// types of the data passed to each component are inferred from how the data is used.
+func convertHTMLTemplate(name, src, pkg string) (output string, r *migrationReport, err error) {
+	root := parse.New(name)
+	// Custom functions can't be converted, but are flagged rather than failing the parse.
+	root.Mode = parse.SkipFuncCheck | parse.ParseComments
+	trees := make(map[string]*parse.Tree)
+	if _, err = root.Parse(src, "", "", trees); err != nil {
+		return "", nil, fmt.Errorf("%s: %w", name, err)
+	}
+	c := &converter{
+		byName: make(map[string]*component),
+		names:  make(map[string]bool),
+		used:   make(map[any]bool),
+	}
+	var treeList []*parse.Tree
+	for _, tree := range trees {
+		if tree.Root == nil || (tree.Name == name && parse.IsEmptyTree(tree.Root)) {
+			continue
+		}
+		treeList = append(treeList, tree)
+	}
+	// The root template is first, followed by the defined templates in the order they appear.
+	sort.Slice(treeList, func(i, j int) bool {
+		if (treeList[i].Name == name) != (treeList[j].Name == name) {
+			return treeList[i].Name == name
+		}
+		return treeList[i].Root.Pos < treeList[j].Root.Pos
+	})
+	for _, tree := range treeList {
+		templateName := tree.Name
+		if templateName == name {
+			templateName = filepath.Base(name)
+			if i := strings.Index(templateName, "."); i > 0 {
+				templateName = templateName[:i]
+			}
+		}
+		comp := &component{
+			name:  c.uniqueName(identifier(templateName)),
+			tree:  tree,
+			param: &typ{},
+		}
+		c.components = append(c.components, comp)
+		c.byName[tree.Name] = comp
+	}
+
+	// The first pass infers the types, so that the second pass can write the expressions
+	// that depend on them, e.g. the conditions of if statements.
+	c.convert()
+	for _, comp := range c.components {
+		c.nameType(comp.param, comp.name+"Data")
+	}
+	c.report = newMigrationReport()
+	body := c.convert()
+
+	var sb strings.Builder
+	sb.WriteString("package " + pkg + "\n\n")
+	for _, t := range c.structs {
+		decl := new(strings.Builder)
+		fmt.Fprintf(decl, "type %s struct {\n", t.name)
+		for _, field := range t.order {
+			fmt.Fprintf(decl, "%s %s\n", field, t.fields[field].goType())
+		}
+		decl.WriteString("}\n")
+		formatted, err := format.Source([]byte(decl.String()))
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: failed to format type %s: %w", name, t.name, err)
+		}
+		sb.Write(formatted)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(body)
+	return sb.String(), c.report, nil
+}
+
+// packageName returns the name of a package in the directory, or main if the directory
+// name isn't a valid package name.
+func packageName(dir string) string {
+	name := strings.ToLower(dir)
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return "main"
+		}
+	}
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "main"
+	}
+	return name
+}
+
+// identifier converts a template name, e.g. "user-row" or "partials/nav", to an exported
+// Go identifier, e.g. "UserRow" or "PartialsNav".
+func identifier(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	s := sb.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "Template" + s
+	}
+	return s
+}
+
+type kind int
+
+const (
+	kindUnknown kind = iota
+	kindBool
+	kindString
+	kindSlice
+	kindStruct
+)
+
+// typ is the type of a value used in a template, inferred from how it's used. Types that
+// must be the same, e.g. the data passed to a template and the data it uses, are unified.
+type typ struct {
+	parent *typ
+	kind   kind
+	fields map[string]*typ
+	order  []string
+	elem   *typ
+	name   string
+}
+
+func (t *typ) find() *typ {
+	for t.parent != nil {
+		t = t.parent
+	}
+	return t
+}
+
+// use widens the kind of t to k. A value that's printed is also used as a condition, so
+// strings take precedence over bools. Structs and slices take precedence over both, but
+// a struct can't also be a slice, so the first of them is kept.
+func (t *typ) use(k kind) *typ {
+	t = t.find()
+	if t.kind < kindSlice && k > t.kind {
+		t.kind = k
+	}
+	return t
+}
+
+func (t *typ) field(name string) *typ {
+	t = t.use(kindStruct)
+	if t.kind != kindStruct {
+		return &typ{}
+	}
+	if t.fields == nil {
+		t.fields = make(map[string]*typ)
+	}
+	f, ok := t.fields[name]
+	if !ok {
+		f = &typ{}
+		t.fields[name] = f
+		t.order = append(t.order, name)
+	}
+	return f.find()
+}
+
+func (t *typ) element() *typ {
+	t = t.use(kindSlice)
+	if t.kind != kindSlice {
+		return &typ{}
+	}
+	if t.elem == nil {
+		t.elem = &typ{}
+	}
+	return t.elem.find()
+}
+
+func unify(a, b *typ) {
+	a, b = a.find(), b.find()
+	if a == b {
+		return
+	}
+	b.parent = a
+	a.use(b.kind)
+	for _, name := range b.order {
+		unify(a.field(name), b.fields[name])
+	}
+	if b.elem != nil {
+		unify(a.element(), b.elem)
+	}
+}
+
+// goType returns the Go type. Structs are used by pointer, so that they can be used as
+// conditions, as they can in html/template.
+func (t *typ) goType() string {
+	t = t.find()
+	switch t.kind {
+	case kindBool:
+		return "bool"
+	case kindString:
+		return "string"
+	case kindSlice:
+		return "[]" + t.elem.goType()
+	case kindStruct:
+		return "*" + t.name
+	}
+	return "any"
+}
+
+// zeroValue returns the Go expression for the zero value of the type.
+func (t *typ) zeroValue() string {
+	switch t.find().kind {
+	case kindBool:
+		return "false"
+	case kindString:
+		return `""`
+	}
+	return "nil"
+}
+
+// condition returns the Go expression that's true when the value is non-empty, which is
+// when html/template considers it to be true.
+func condition(expr string, t *typ) string {
+	switch t.find().kind {
+	case kindString:
+		return expr + ` != ""`
+	case kindSlice:
+		return "len(" + expr + ") > 0"
+	case kindStruct:
+		return expr + " != nil"
+	}
+	return expr
+}
+
+type component struct {
+	name  string
+	tree  *parse.Tree
+	param *typ
+}
+
+type converter struct {
+	components []*component
+	byName     map[string]*component
+	names      map[string]bool
+	structs    []*typ
+	// used records the components and loops that use their data, so that unused parameters
+	// and loop variables aren't declared.
+	used   map[any]bool
+	report *migrationReport
+	// loopVars is the number of loop variables declared in the current component.
+	loopVars int
+	w        *htmlWriter
+}
+
+// scope is the data available to an action.
+type scope struct {
+	dot     string
+	dotType *typ
+	// owner is the component or loop that declared the dot.
+	owner any
+	vars  map[string]variable
+}
+
+type variable struct {
+	expr  string
+	t     *typ
+	owner any
+}
+
+func (c *converter) uniqueName(name string) string {
+	unique := name
+	for i := 2; c.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	c.names[unique] = true
+	return unique
+}
+
+func (c *converter) nameType(t *typ, name string) {
+	t = t.find()
+	switch t.kind {
+	case kindSlice:
+		c.nameType(t.elem, name+"Item")
+	case kindStruct:
+		if t.name != "" {
+			return
+		}
+		t.name = c.uniqueName(name)
+		c.structs = append(c.structs, t)
+		for _, field := range t.order {
+			c.nameType(t.fields[field], t.name+field)
+		}
+	}
+}
+
+func (c *converter) hasParam(comp *component) bool {
+	return c.used[comp]
+}
+
+// convert writes each component, and returns the templ code.
+func (c *converter) convert() string {
+	var sb strings.Builder
+	for i, comp := range c.components {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		c.w = &htmlWriter{nodeStart: true}
+		c.loopVars = 0
+		s := &scope{dot: "v", dotType: comp.param, owner: comp}
+		s.vars = map[string]variable{"$": {expr: "v", t: comp.param, owner: comp}}
+		c.list(s, comp.tree.Root)
+		c.w.flush()
+		if c.hasParam(comp) {
+			paramType := comp.param.goType()
+			fmt.Fprintf(&sb, "templ %s(v %s) {\n", comp.name, paramType)
+		} else {
+			fmt.Fprintf(&sb, "templ %s() {\n", comp.name)
+		}
+		sb.WriteString(c.w.out.String())
+		sb.WriteString("\n}\n")
+	}
+	return sb.String()
+}
+
+func (c *converter) count(construct string) {
+	if c.report != nil {
+		c.report.converted[construct]++
+	}
+}
+
+// flag leaves the original text of the node in a TODO comment.
+func (c *converter) flag(reason string, n parse.Node) {
+	if c.report != nil {
+		c.report.flagged[reason]++
+	}
+	c.w.todo(n.String())
+}
+
+func (c *converter) list(s *scope, l *parse.ListNode) {
+	if l == nil {
+		return
+	}
+	for _, n := range l.Nodes {
+		c.node(s, n)
+	}
+}
+
+func (c *converter) node(s *scope, n parse.Node) {
+	switch n := n.(type) {
+	case *parse.TextNode:
+		c.w.text(string(n.Text))
+	case *parse.CommentNode:
+		if c.w.state != stateText {
+			return
+		}
+		c.count(convertedComments)
+		c.w.comment(strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(n.Text, "/*"), "*/")))
+	case *parse.ActionNode:
+		c.action(s, n)
+	case *parse.IfNode:
+		c.ifNode(s, n)
+	case *parse.RangeNode:
+		c.rangeNode(s, n)
+	case *parse.TemplateNode:
+		c.templateNode(s, n)
+	case *parse.WithNode:
+		c.flag(flaggedWith, n)
+	default:
+		c.flag(flaggedOther, n)
+	}
+}
+
+func (c *converter) action(s *scope, n *parse.ActionNode) {
+	if len(n.Pipe.Decl) > 0 {
+		c.flag(flaggedVariables, n)
+		return
+	}
+	switch c.w.state {
+	case stateText, stateAttrValue:
+	default:
+		c.flag(flaggedContext, n)
+		return
+	}
+	if c.w.state == stateAttrValue && generator.IsScriptAttribute(c.w.attrName.String()) {
+		c.flag(flaggedContext, n)
+		return
+	}
+	expr, t, reason := c.value(s, n.Pipe)
+	if reason != "" {
+		c.flag(reason, n)
+		return
+	}
+	t.use(kindString)
+	c.count(convertedValues)
+	c.w.expression(expr)
+}
+
+// value returns the Go expression of a pipeline that is a single field, variable, or
+// constant, or the reason that it can't be converted.
+func (c *converter) value(s *scope, pipe *parse.PipeNode) (expr string, t *typ, reason string) {
+	if len(pipe.Decl) > 0 {
+		return "", nil, flaggedVariables
+	}
+	if len(pipe.Cmds) != 1 {
+		return "", nil, flaggedPipelines
+	}
+	args := pipe.Cmds[0].Args
+	if _, ok := args[0].(*parse.IdentifierNode); ok {
+		return "", nil, flaggedFunctions
+	}
+	if len(args) != 1 {
+		return "", nil, flaggedPipelines
+	}
+	return c.arg(s, args[0])
+}
+
+func (c *converter) arg(s *scope, n parse.Node) (expr string, t *typ, reason string) {
+	switch n := n.(type) {
+	case *parse.DotNode:
+		c.used[s.owner] = true
+		return s.dot, s.dotType, ""
+	case *parse.FieldNode:
+		c.used[s.owner] = true
+		expr, t = s.dot, s.dotType
+		for _, ident := range n.Ident {
+			expr, t = expr+"."+ident, t.field(ident)
+		}
+		return expr, t, ""
+	case *parse.VariableNode:
+		v, ok := s.vars[n.Ident[0]]
+		if !ok {
+			return "", nil, flaggedVariables
+		}
+		c.used[v.owner] = true
+		expr, t = v.expr, v.t
+		for _, ident := range n.Ident[1:] {
+			expr, t = expr+"."+ident, t.field(ident)
+		}
+		return expr, t, ""
+	case *parse.StringNode:
+		return strconv.Quote(n.Text), &typ{kind: kindString}, ""
+	case *parse.BoolNode:
+		return strconv.FormatBool(n.True), &typ{kind: kindBool}, ""
+	case *parse.PipeNode:
+		return c.value(s, n)
+	case *parse.IdentifierNode:
+		return "", nil, flaggedFunctions
+	}
+	return "", nil, flaggedOther
+}
+
+// condition returns the Go expression of a pipeline used in an if statement. The not, and,
+// or, eq and ne functions are converted to Go operators.
+func (c *converter) condition(s *scope, pipe *parse.PipeNode) (expr string, reason string) {
+	if len(pipe.Decl) > 0 {
+		return "", flaggedVariables
+	}
+	if len(pipe.Cmds) != 1 {
+		return "", flaggedPipelines
+	}
+	args := pipe.Cmds[0].Args
+	fn, ok := args[0].(*parse.IdentifierNode)
+	if !ok {
+		if len(args) != 1 {
+			return "", flaggedPipelines
+		}
+		return c.conditionArg(s, args[0])
+	}
+	args = args[1:]
+	switch {
+	case fn.Ident == "not" && len(args) == 1:
+		expr, reason = c.conditionArg(s, args[0])
+		if reason != "" {
+			return "", reason
+		}
+		return "!" + parenthesize(expr), ""
+	case (fn.Ident == "and" || fn.Ident == "or") && len(args) > 1:
+		op := " && "
+		if fn.Ident == "or" {
+			op = " || "
+		}
+		exprs := make([]string, len(args))
+		for i, arg := range args {
+			if exprs[i], reason = c.conditionArg(s, arg); reason != "" {
+				return "", reason
+			}
+			exprs[i] = parenthesize(exprs[i])
+		}
+		return strings.Join(exprs, op), ""
+	case (fn.Ident == "eq" || fn.Ident == "ne") && len(args) == 2:
+		a, at, reason := c.arg(s, args[0])
+		if reason != "" {
+			return "", reason
+		}
+		b, bt, reason := c.arg(s, args[1])
+		if reason != "" {
+			return "", reason
+		}
+		unify(at, bt)
+		at.use(kindString)
+		op := " == "
+		if fn.Ident == "ne" {
+			op = " != "
+		}
+		return a + op + b, ""
+	}
+	return "", flaggedFunctions
+}
+
+func (c *converter) conditionArg(s *scope, n parse.Node) (expr string, reason string) {
+	if pipe, ok := n.(*parse.PipeNode); ok {
+		return c.condition(s, pipe)
+	}
+	expr, t, reason := c.arg(s, n)
+	if reason != "" {
+		return "", reason
+	}
+	return condition(expr, t.use(kindBool)), ""
+}
+
+func parenthesize(expr string) string {
+	if strings.ContainsAny(expr, " ") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+func (c *converter) ifNode(s *scope, n *parse.IfNode) {
+	switch c.w.state {
+	case stateText, stateTag:
+	default:
+		c.flag(flaggedContext, n)
+		return
+	}
+	cond, reason := c.condition(s, n.Pipe)
+	if reason != "" {
+		c.flag(reason, n)
+		return
+	}
+	c.count(convertedConditionals)
+	c.w.block("if " + cond + " {")
+	c.list(s, n.List)
+	for n.ElseList != nil {
+		if len(n.ElseList.Nodes) == 1 {
+			if elseIf, ok := n.ElseList.Nodes[0].(*parse.IfNode); ok {
+				if cond, reason = c.condition(s, elseIf.Pipe); reason == "" {
+					c.count(convertedConditionals)
+					c.w.block("} else if " + cond + " {")
+					c.list(s, elseIf.List)
+					n = elseIf
+					continue
+				}
+			}
+		}
+		c.w.block("} else {")
+		c.list(s, n.ElseList)
+		break
+	}
+	c.w.block("}")
+}
+
+func (c *converter) rangeNode(s *scope, n *parse.RangeNode) {
+	if c.w.state != stateText {
+		c.flag(flaggedContext, n)
+		return
+	}
+	if len(n.Pipe.Decl) > 2 {
+		c.flag(flaggedVariables, n)
+		return
+	}
+	expr, t, reason := c.value(s, &parse.PipeNode{Cmds: n.Pipe.Cmds})
+	if reason != "" {
+		c.flag(reason, n)
+		return
+	}
+	c.count(convertedLoops)
+	elem := t.element()
+	c.loopVars++
+	name := "item"
+	if c.loopVars > 1 {
+		name += strconv.Itoa(c.loopVars)
+	}
+	// The element is the dot within the loop, and is also assigned to the last variable.
+	// The index isn't available, because its type can't be inferred.
+	child := &scope{dot: name, dotType: elem, owner: n, vars: make(map[string]variable)}
+	for k, v := range s.vars {
+		child.vars[k] = v
+	}
+	if len(n.Pipe.Decl) > 0 {
+		decl := n.Pipe.Decl[len(n.Pipe.Decl)-1].Ident[0]
+		name = strings.TrimPrefix(decl, "$")
+		child.dot = name
+		child.vars[decl] = variable{expr: name, t: elem, owner: n}
+		if len(n.Pipe.Decl) == 2 {
+			delete(child.vars, n.Pipe.Decl[0].Ident[0])
+		}
+	}
+	if c.used[n] {
+		c.w.block("for _, " + name + " := range " + expr + " {")
+	} else {
+		c.w.block("for range " + expr + " {")
+	}
+	c.list(child, n.List)
+	c.w.block("}")
+	if n.ElseList != nil {
+		c.w.block("if len(" + expr + ") == 0 {")
+		c.list(s, n.ElseList)
+		c.w.block("}")
+	}
+}
+
+func (c *converter) templateNode(s *scope, n *parse.TemplateNode) {
+	if c.w.state != stateText {
+		c.flag(flaggedContext, n)
+		return
+	}
+	callee := c.byName[n.Name]
+	name := identifier(n.Name)
+	if callee != nil {
+		name = callee.name
+	}
+	var expr string
+	var t *typ
+	if n.Pipe != nil {
+		var reason string
+		if expr, t, reason = c.value(s, n.Pipe); reason != "" {
+			c.flag(reason, n)
+			return
+		}
+	}
+	c.count(convertedTemplateCalls)
+	if callee == nil {
+		c.w.call("@" + name + "(" + expr + ")")
+		return
+	}
+	if t != nil {
+		unify(callee.param, t)
+	}
+	if !c.hasParam(callee) {
+		c.w.call("@" + name + "()")
+		return
+	}
+	if expr == "" {
+		expr = callee.param.zeroValue()
+	}
+	c.w.call("@" + name + "(" + expr + ")")
+}
+
+type htmlState int
+
+const (
+	stateText htmlState = iota
+	stateTag
+	stateAttrValue
+	stateRawText
+	stateComment
+)
+
+// htmlWriter writes templ code, tracking where it is within the HTML, so that actions can
+// be converted to the templ syntax for each context, e.g. text or attribute values.
+type htmlWriter struct {
+	out   strings.Builder
+	state htmlState
+	// nodeStart is true at the start of a templ node, where text that starts with a templ
+	// keyword would be parsed as templ code.
+	nodeStart bool
+	// Within a tag.
+	tagName  strings.Builder
+	inName   bool
+	attrName strings.Builder
+	// Within an attribute value.
+	quote     byte
+	literal   strings.Builder
+	parts     []string
+	hasExpr   bool
+	attrTodos []string
+	// Within a script or style element.
+	rawEnd string
+}
+
+var templKeywords = []string{"if", "for", "switch", "case", "default", "else", "templ"}
+
+func (w *htmlWriter) text(s string) {
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch w.state {
+		case stateText:
+			if strings.HasPrefix(s[i:], "<!--") {
+				w.out.WriteString("<!--")
+				i += 3
+				w.state = stateComment
+				continue
+			}
+			if ch == '<' && i+1 < len(s) && (isASCIILetter(s[i+1]) || s[i+1] == '/' || s[i+1] == '!') {
+				w.out.WriteByte(ch)
+				w.state = stateTag
+				w.tagName.Reset()
+				w.attrName.Reset()
+				w.inName = true
+				continue
+			}
+			if ch == '{' || ch == '}' {
+				w.expression(strconv.Quote(string(ch)))
+				continue
+			}
+			if w.nodeStart && !isSpace(ch) {
+				w.nodeStart = false
+				if kw := startsWithKeyword(s[i:]); kw != "" {
+					w.expression(strconv.Quote(kw))
+					i += len(kw) - 1
+					continue
+				}
+			}
+			w.out.WriteByte(ch)
+		case stateTag:
+			if w.inName {
+				if !(isSpace(ch) || ch == '>' || (ch == '/' && w.tagName.Len() > 0)) {
+					w.tagName.WriteByte(ch)
+					w.out.WriteByte(ch)
+					continue
+				}
+				w.inName = false
+			}
+			switch {
+			case ch == '>':
+				w.out.WriteByte(ch)
+				name := strings.ToLower(w.tagName.String())
+				w.state = stateText
+				if name == "script" || name == "style" {
+					w.state = stateRawText
+					w.rawEnd = "</" + name
+				}
+				w.nodeStart = true
+			case ch == '"' || ch == '\'':
+				w.state = stateAttrValue
+				w.quote = ch
+				w.literal.Reset()
+				w.parts = nil
+				w.hasExpr = false
+				w.attrTodos = nil
+			case isSpace(ch):
+				w.attrName.Reset()
+				w.out.WriteByte(ch)
+			default:
+				if ch != '=' {
+					w.attrName.WriteByte(ch)
+				}
+				w.out.WriteByte(ch)
+			}
+		case stateAttrValue:
+			if ch == w.quote {
+				w.closeAttr()
+				continue
+			}
+			w.literal.WriteByte(ch)
+		case stateRawText:
+			if ch == '<' && len(s[i:]) >= len(w.rawEnd) && strings.EqualFold(s[i:i+len(w.rawEnd)], w.rawEnd) {
+				w.state = stateTag
+				w.tagName.Reset()
+				w.inName = true
+			}
+			w.out.WriteByte(ch)
+		case stateComment:
+			if strings.HasPrefix(s[i:], "-->") {
+				w.out.WriteString("-->")
+				i += 2
+				w.state = stateText
+				w.nodeStart = true
+				continue
+			}
+			w.out.WriteByte(ch)
+		}
+	}
+}
+
+func (w *htmlWriter) closeAttr() {
+	w.state = stateTag
+	if !w.hasExpr {
+		w.out.WriteByte(w.quote)
+		w.out.WriteString(w.literal.String())
+		w.out.WriteByte(w.quote)
+	} else {
+		if w.literal.Len() > 0 {
+			w.parts = append(w.parts, strconv.Quote(w.literal.String()))
+		}
+		expr := strings.Join(w.parts, " + ")
+		if generator.IsURLAttribute(w.tagName.String(), w.attrName.String()) {
+			expr = "templ.URL(" + expr + ")"
+		}
+		w.out.WriteString("{ " + expr + " }")
+	}
+	w.attrName.Reset()
+	for _, todo := range w.attrTodos {
+		w.startLine()
+		w.todoComment(todo)
+	}
+}
+
+// expression writes a Go expression.
+func (w *htmlWriter) expression(expr string) {
+	if w.state == stateAttrValue {
+		if w.literal.Len() > 0 {
+			w.parts = append(w.parts, strconv.Quote(w.literal.String()))
+			w.literal.Reset()
+		}
+		w.parts = append(w.parts, expr)
+		w.hasExpr = true
+		return
+	}
+	w.out.WriteString("{ " + expr + " }")
+	w.nodeStart = true
+}
+
+// startLine starts a new line, unless the output is already at the start of a line, so that
+// blank lines, which are kept by the formatter, aren't written between nodes.
+func (w *htmlWriter) startLine() {
+	if s := w.out.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		w.out.WriteString("\n")
+	}
+}
+
+// call writes a call to a templ component.
+func (w *htmlWriter) call(expr string) {
+	w.startLine()
+	w.out.WriteString(expr + "\n")
+	w.nodeStart = true
+}
+
+// block writes a line that opens or closes a block, e.g. an if statement.
+func (w *htmlWriter) block(line string) {
+	w.startLine()
+	w.out.WriteString(line + "\n")
+	w.nodeStart = true
+}
+
+// comment writes a templ comment.
+func (w *htmlWriter) comment(text string) {
+	w.startLine()
+	for _, line := range strings.Split(text, "\n") {
+		w.out.WriteString("// " + strings.TrimSpace(line) + "\n")
+	}
+	w.nodeStart = true
+}
+
+// todo writes the original text of a construct that couldn't be converted, in a comment
+// that can be written in the current context.
+func (w *htmlWriter) todo(original string) {
+	switch w.state {
+	case stateText, stateTag:
+		w.startLine()
+		w.todoComment(original)
+		w.nodeStart = true
+	case stateAttrValue:
+		// The original text is kept in the attribute value, and the comment is written
+		// after the attribute.
+		w.literal.WriteString(original)
+		w.attrTodos = append(w.attrTodos, original)
+	case stateRawText:
+		w.out.WriteString("/* " + todoPrefix + strings.ReplaceAll(original, "*/", "* /") + " */")
+	case stateComment:
+		w.out.WriteString(todoPrefix + strings.ReplaceAll(original, "-->", "-- >"))
+	}
+}
+
+func (w *htmlWriter) todoComment(original string) {
+	for i, line := range strings.Split(original, "\n") {
+		if i == 0 {
+			w.out.WriteString("// " + todoPrefix + line + "\n")
+			continue
+		}
+		w.out.WriteString("// " + line + "\n")
+	}
+}
+
+// flush writes an attribute value that wasn't closed.
+func (w *htmlWriter) flush() {
+	if w.state == stateAttrValue {
+		w.closeAttr()
+	}
+}
+
+func startsWithKeyword(s string) string {
+	if s[0] == '@' {
+		return "@"
+	}
+	for _, kw := range templKeywords {
+		if strings.HasPrefix(s, kw) && (len(s) == len(kw) || isSpace(s[len(kw)]) || s[len(kw)] == '{') {
+			return kw
+		}
+	}
+	return ""
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
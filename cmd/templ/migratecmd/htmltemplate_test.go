@@ -0,0 +1,383 @@
+package migratecmd
+
+import (
+	"bytes"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	v2 "github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertHTMLTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		converted map[string]int
+		flagged   map[string]int
+	}{
+		{
+			name:  "text without actions is converted to a component without parameters",
+			input: `<p>Hello</p>`,
+			expected: `package views
+
+templ Page() {
+	<p>Hello</p>
+}
+
+`,
+			converted: map[string]int{},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "fields are converted to expressions, and added to the data type",
+			input: `<h1>{{.Title}}</h1><p>{{.Author.Name}}</p>`,
+			expected: `package views
+
+type PageData struct {
+	Title  string
+	Author *PageDataAuthor
+}
+
+type PageDataAuthor struct {
+	Name string
+}
+
+templ Page(v *PageData) {
+	<h1>{ v.Title }</h1>
+	<p>{ v.Author.Name }</p>
+}
+
+`,
+			converted: map[string]int{convertedValues: 2},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "actions in attribute values are converted to expressions, and URLs are sanitized",
+			input: `<a class="link {{.Class}}" href="/users/{{.ID}}">Profile</a>`,
+			expected: `package views
+
+type PageData struct {
+	Class string
+	ID    string
+}
+
+templ Page(v *PageData) {
+	<a class={ "link " + v.Class } href={ templ.URL("/users/" + v.ID) }>Profile</a>
+}
+
+`,
+			converted: map[string]int{convertedValues: 2},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "if, else if and else are converted to templ, with conditions that depend on the type",
+			input: `{{if .Admin}}<p>Admin</p>{{else if .Name}}<p>{{.Name}}</p>{{else if .Groups}}{{range .Groups}}<p>{{.}}</p>{{end}}{{else}}<p>Guest</p>{{end}}`,
+			expected: `package views
+
+type PageData struct {
+	Admin  bool
+	Name   string
+	Groups []string
+}
+
+templ Page(v *PageData) {
+	if v.Admin {
+		<p>Admin</p>
+	} else if v.Name != "" {
+		<p>{ v.Name }</p>
+	} else if len(v.Groups) > 0 {
+		for _, item := range v.Groups {
+			<p>{ item }</p>
+		}
+	} else {
+		<p>Guest</p>
+	}
+}
+
+`,
+			converted: map[string]int{convertedConditionals: 3, convertedLoops: 1, convertedValues: 2},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "not, and, or, eq and ne are converted to operators",
+			input: `{{if and (not .Hidden) (or .A .B)}}<p>Shown</p>{{end}}{{if eq .Role "admin"}}<p>Admin</p>{{end}}{{if ne .Role .Other}}<p>Other</p>{{end}}`,
+			expected: `package views
+
+type PageData struct {
+	Hidden bool
+	A      bool
+	B      bool
+	Role   string
+	Other  string
+}
+
+templ Page(v *PageData) {
+	if !v.Hidden && (v.A || v.B) {
+		<p>Shown</p>
+	}
+	if v.Role == "admin" {
+		<p>Admin</p>
+	}
+	if v.Role != v.Other {
+		<p>Other</p>
+	}
+}
+
+`,
+			converted: map[string]int{convertedConditionals: 3},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "conditional attributes are converted",
+			input: `<input type="checkbox" {{if .Checked}}checked{{end}}/>`,
+			expected: `package views
+
+type PageData struct {
+	Checked bool
+}
+
+templ Page(v *PageData) {
+	<input type="checkbox"
+		if v.Checked {
+			checked
+		}
+		/>
+}
+
+`,
+			converted: map[string]int{convertedConditionals: 1},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "range is converted to a for loop, and range else to a check for an empty slice",
+			input: `<ul>{{range .Items}}<li>{{.Name}} {{$.Title}}</li>{{else}}<li>None</li>{{end}}</ul>`,
+			expected: `package views
+
+type PageData struct {
+	Items []*PageDataItemsItem
+	Title string
+}
+
+type PageDataItemsItem struct {
+	Name string
+}
+
+templ Page(v *PageData) {
+	<ul>
+		for _, item := range v.Items {
+			<li>{ item.Name } { v.Title }</li>
+		}
+		if len(v.Items) == 0 {
+			<li>None</li>
+		}
+	</ul>
+}
+
+`,
+			converted: map[string]int{convertedLoops: 1, convertedValues: 2},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "range variables are converted, but the index is flagged",
+			input: `{{range $i, $tag := .Tags}}<span>{{$tag}}</span><span>{{$i}}</span>{{end}}{{range .Rows}}<hr/>{{end}}`,
+			expected: `package views
+
+type PageData struct {
+	Tags []string
+	Rows []any
+}
+
+templ Page(v *PageData) {
+	for _, tag := range v.Tags {
+		<span>{ tag }</span>
+		<span>
+			// TODO(templ-migrate): {{$i}}
+		</span>
+	}
+	for range v.Rows {
+		<hr/>
+	}
+}
+
+`,
+			converted: map[string]int{convertedLoops: 2, convertedValues: 1},
+			flagged:   map[string]int{flaggedVariables: 1},
+		},
+		{
+			name:  "defined templates are converted to components, and template calls to component calls",
+			input: `{{define "user-row"}}<tr><td>{{.Name}}</td></tr>{{end}}<table>{{range .Users}}{{template "user-row" .}}{{end}}</table>{{template "footer"}}{{define "footer"}}<footer></footer>{{end}}`,
+			expected: `package views
+
+type PageData struct {
+	Users []*PageDataUsersItem
+}
+
+type PageDataUsersItem struct {
+	Name string
+}
+
+templ Page(v *PageData) {
+	<table>
+		for _, item := range v.Users {
+			@UserRow(item)
+		}
+	</table>
+	@Footer()
+}
+
+templ UserRow(v *PageDataUsersItem) {
+	<tr>
+		<td>{ v.Name }</td>
+	</tr>
+}
+
+templ Footer() {
+	<footer></footer>
+}
+
+`,
+			converted: map[string]int{convertedLoops: 1, convertedTemplateCalls: 2, convertedValues: 1},
+			flagged:   map[string]int{},
+		},
+		{
+			name:  "functions, pipelines, with blocks and variables are flagged",
+			input: `<p>{{upper .Name}}</p><p>{{.Date | printf "%s"}}</p>{{with .User}}<p>{{.Name}}</p>{{end}}{{$x := .Name}}`,
+			expected: `package views
+
+templ Page() {
+	<p>
+		// TODO(templ-migrate): {{upper .Name}}
+	</p>
+	<p>
+		// TODO(templ-migrate): {{.Date | printf "%s"}}
+	</p>
+	// TODO(templ-migrate): {{with .User}}<p>{{.Name}}</p>{{end}}
+	// TODO(templ-migrate): {{$x := .Name}}
+}
+
+`,
+			converted: map[string]int{},
+			flagged:   map[string]int{flaggedFunctions: 1, flaggedPipelines: 1, flaggedWith: 1, flaggedVariables: 1},
+		},
+		{
+			name:  "actions that can't be converted in attributes and scripts are left in place",
+			input: `<p class="{{upper .Class}}" onclick="go({{.ID}})">Text</p><script>var x = {{.X}};</script>`,
+			expected: `package views
+
+templ Page() {
+	<p class="{{upper .Class}}"
+		// TODO(templ-migrate): {{upper .Class}}
+		onclick="go({{.ID}})"
+		// TODO(templ-migrate): {{.ID}}
+		>Text</p>
+	<script>var x = /* TODO(templ-migrate): {{.X}} */;</script>
+}
+
+`,
+			converted: map[string]int{},
+			flagged:   map[string]int{flaggedFunctions: 1, flaggedContext: 2},
+		},
+		{
+			name:  "comments are converted, and text that would be parsed as templ is escaped",
+			input: "{{/* The title. */}}<p>if you like {braces}</p>",
+			expected: `package views
+
+templ Page() {
+	// The title.
+	<p>{ "if" } you like { "{" }braces{ "}" }</p>
+}
+
+`,
+			converted: map[string]int{convertedComments: 1},
+			flagged:   map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			output, r, err := convertHTMLTemplate("page.html", tt.input, "views")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			formatted, err := formatTempl(output)
+			if err != nil {
+				t.Fatalf("failed to parse output: %v\n%s", err, output)
+			}
+			if diff := cmp.Diff(tt.expected, formatted); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(tt.converted, r.converted); diff != "" {
+				t.Errorf("unexpected converted counts:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.flagged, r.flagged); diff != "" {
+				t.Errorf("unexpected flagged counts:\n%s", diff)
+			}
+			tf, err := v2.ParseString(formatted)
+			if err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+			code := new(bytes.Buffer)
+			if _, err = generator.GenerateFile(generator.GenerateOpts{}, tf, code); err != nil {
+				t.Fatalf("failed to generate code: %v", err)
+			}
+			if _, err = goparser.ParseFile(token.NewFileSet(), "page_templ.go", code, goparser.AllErrors); err != nil {
+				t.Errorf("generated code isn't valid Go: %v", err)
+			}
+		})
+	}
+}
+
+func TestMigrateHTMLTemplates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":      `<h1>{{.Title}}</h1>{{range .Posts}}{{template "post" .}}{{end}}{{define "post"}}<a href="/posts/{{.ID}}">{{.Title}}</a>{{end}}`,
+		"about.html.tmpl": `<p>{{.Name}}</p>{{upper .Name}}`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0660); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	w := new(strings.Builder)
+	if err := migrateHTMLTemplates(w, []string{filepath.Join(dir, "*.html"), filepath.Join(dir, "*.tmpl")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"index.html -> " + filepath.Join(dir, "index.templ") + ": 5 converted, 0 flagged",
+		"about.html.tmpl -> " + filepath.Join(dir, "about.templ") + ": 1 converted, 1 flagged",
+		"Migrated 2 html/template files with 0 errors",
+		"converted: 1 loops, 1 template calls, 4 values",
+		"flagged:   1 functions",
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, w.String())
+		}
+	}
+
+	for _, name := range []string{"index.templ", "about.templ"} {
+		if _, err := v2.ParseFile(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s: failed to parse: %v", name, err)
+		}
+	}
+
+	t.Run("existing templ files aren't overwritten", func(t *testing.T) {
+		err := migrateHTMLTemplates(new(strings.Builder), []string{filepath.Join(dir, "index.html")})
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("expected an error because the file exists, got %v", err)
+		}
+	})
+	t.Run("patterns that don't match any files are an error", func(t *testing.T) {
+		err := migrateHTMLTemplates(new(strings.Builder), []string{filepath.Join(dir, "*.gohtml")})
+		if err == nil || !strings.Contains(err.Error(), "no files match") {
+			t.Errorf("expected an error because no files match, got %v", err)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"html"
+	"os"
 	"reflect"
 	"strings"
 	"time"
@@ -19,9 +21,15 @@ const workerCount = 4
 type Arguments struct {
 	FileName string
 	Path     string
+	// Globs are patterns that match html/template files to convert to templ files. If set,
+	// FileName and Path are ignored.
+	Globs []string
 }
 
 func Run(args Arguments) (err error) {
+	if len(args.Globs) > 0 {
+		return migrateHTMLTemplates(os.Stdout, args.Globs)
+	}
 	if args.FileName != "" {
 		return processSingleFile(args.FileName)
 	}
@@ -55,11 +63,11 @@ func processPath(path string) (err error) {
 
 func migrate(fileName string) (err error) {
 	// Check that it's actually a V1 file.
-	_, err = v2.Parse(fileName)
+	_, err = v2.ParseFile(fileName)
 	if err == nil {
 		return fmt.Errorf("migrate: %s able to parse file as V2, are you sure this needs to be migrated?", fileName)
 	}
-	if err != v2.ErrLegacyFileFormat {
+	if !errors.Is(err, v2.ErrLegacyFileFormat) {
 		return fmt.Errorf("migrate: %s unexpected error: %v", fileName, err)
 	}
 	// Parse.
@@ -291,7 +299,8 @@ func migrateV1AttributeToV2Attribute(in v1.Attribute) (out v2.Attribute, err err
 	case v1.BoolConstantAttribute:
 		return v2.BoolConstantAttribute{Name: attr.Name}, nil
 	case v1.ConstantAttribute:
-		return v2.ConstantAttribute{Name: attr.Name, Value: attr.Value}, nil
+		// v1 attribute values are decoded, but v2 values are written as they appear in the template.
+		return v2.ConstantAttribute{Name: attr.Name, Value: html.EscapeString(attr.Value)}, nil
 	case v1.BoolExpressionAttribute:
 		bea := v2.BoolExpressionAttribute{
 			Name: attr.Name,
@@ -0,0 +1,291 @@
+package newcmd
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/a-h/templ/cmd/templ/fmtcmd"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// builtin contains the templates of the built-in kinds of file. Each kind has a .templ.tmpl
+// template, and a _test.go.tmpl template for its render test.
+//
+//go:embed templates
+var builtin embed.FS
+
+// OverrideDir is the directory, relative to a directory in the project, that contains
+// templates that replace the built-in templates, or add new kinds of file. The directory is
+// looked for in the target directory, and its parents up to the root of the Go module.
+const OverrideDir = ".templ/templates"
+
+const (
+	templSuffix = ".templ.tmpl"
+	testSuffix  = "_test.go.tmpl"
+)
+
+type Arguments struct {
+	// Kind of file to create, e.g. "component", "page" or "layout".
+	Kind string
+	// Name of the template, e.g. "UserCard". The file is named after it, e.g. user_card.templ.
+	Name string
+	// Dir is the directory to write the files to. It's created if it doesn't exist.
+	Dir string
+	// Layout is the name of the layout that a page renders its content within.
+	Layout string
+	// Test also writes a test that renders the template.
+	Test bool
+	// Force overwrites existing files.
+	Force bool
+}
+
+// Data is passed to the templates.
+type Data struct {
+	// Package name, inferred from the files in the directory, or its name.
+	Package string
+	// Name of the template.
+	Name string
+	// Layout is the name of the layout that a page renders its content within.
+	Layout string
+}
+
+// Run writes a templ file, and optionally its test, from the template for the kind of file.
+func Run(w io.Writer, args Arguments) (err error) {
+	if !token.IsIdentifier(args.Name) {
+		return fmt.Errorf("invalid name %q, the name must be a Go identifier, e.g. UserCard", args.Name)
+	}
+	if args.Dir == "" {
+		args.Dir = "."
+	}
+	if args.Dir, err = filepath.Abs(args.Dir); err != nil {
+		return err
+	}
+	if args.Layout == "" {
+		args.Layout = "Layout"
+	}
+	templates := findTemplates(args.Dir)
+	kind, ok := templates[args.Kind]
+	if !ok {
+		return fmt.Errorf("unknown kind %q, expected one of: %s", args.Kind, strings.Join(kinds(templates), ", "))
+	}
+	if kind.err != nil {
+		return fmt.Errorf("invalid template for %q: %w", args.Kind, kind.err)
+	}
+	if args.Test && kind.test == nil {
+		return fmt.Errorf("there's no test template for %q", args.Kind)
+	}
+
+	data := Data{
+		Package: packageName(args.Dir),
+		Name:    args.Name,
+		Layout:  args.Layout,
+	}
+	baseName := filepath.Join(args.Dir, snakeCase(args.Name))
+	files := []file{{fileName: baseName + ".templ", template: kind.templ, format: fmtcmd.Format}}
+	if args.Test {
+		files = append(files, file{fileName: baseName + "_test.go", template: kind.test, format: formatGo})
+	}
+	for i, f := range files {
+		if !args.Force && exists(f.fileName) {
+			return fmt.Errorf("%s already exists, use -force to overwrite it", f.fileName)
+		}
+		if files[i].contents, err = f.execute(data); err != nil {
+			return err
+		}
+	}
+	if err = os.MkdirAll(args.Dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err = os.WriteFile(f.fileName, []byte(f.contents), 0644); err != nil {
+			return fmt.Errorf("%s write file error: %w", f.fileName, err)
+		}
+		fmt.Fprintf(w, "Created %s\n", f.fileName)
+	}
+	return nil
+}
+
+// kind is the templates of a kind of file.
+type kind struct {
+	templ, test *template.Template
+	// err is set if one of the templates can't be parsed.
+	err error
+}
+
+// findTemplates returns the built-in templates, replaced or added to by the templates in the
+// nearest override directory.
+func findTemplates(dir string) map[string]kind {
+	templates := make(map[string]kind)
+	addTemplates(templates, builtin, "templates")
+	if overrides, ok := findOverrideDir(dir); ok {
+		addTemplates(templates, os.DirFS(overrides), ".")
+	}
+	return templates
+}
+
+func addTemplates(templates map[string]kind, fsys fs.FS, dir string) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		var kindName string
+		var isTest bool
+		switch {
+		case strings.HasSuffix(name, testSuffix):
+			kindName, isTest = strings.TrimSuffix(name, testSuffix), true
+		case strings.HasSuffix(name, templSuffix):
+			kindName = strings.TrimSuffix(name, templSuffix)
+		default:
+			continue
+		}
+		src, err := fs.ReadFile(fsys, path(dir, name))
+		if err != nil {
+			continue
+		}
+		k := templates[kindName]
+		t, err := template.New(name).Parse(string(src))
+		if err != nil {
+			// An invalid template is reported when it's used.
+			k.err = err
+		}
+		if isTest {
+			k.test = t
+		} else {
+			k.templ = t
+		}
+		templates[kindName] = k
+	}
+	// A test template on its own isn't a kind of file.
+	for name, k := range templates {
+		if k.templ == nil && k.err == nil {
+			delete(templates, name)
+		}
+	}
+}
+
+func path(dir, name string) string {
+	if dir == "." {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// findOverrideDir returns the nearest override directory in dir, or its parents up to the
+// root of the Go module.
+func findOverrideDir(dir string) (overrides string, ok bool) {
+	for d := dir; ; {
+		overrides = filepath.Join(d, filepath.FromSlash(OverrideDir))
+		if info, err := os.Stat(overrides); err == nil && info.IsDir() {
+			return overrides, true
+		}
+		parent := filepath.Dir(d)
+		if parent == d || exists(filepath.Join(d, "go.mod")) {
+			return "", false
+		}
+		d = parent
+	}
+}
+
+func kinds(templates map[string]kind) (names []string) {
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// file is a file to be written from a template.
+type file struct {
+	fileName string
+	template *template.Template
+	format   func(src string) (string, error)
+	contents string
+}
+
+func (f file) execute(data Data) (contents string, err error) {
+	var b bytes.Buffer
+	if err = f.template.Option("missingkey=error").Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%s: template error: %w", f.fileName, err)
+	}
+	if contents, err = f.format(b.String()); err != nil {
+		return "", fmt.Errorf("%s: the template %s doesn't produce a valid file: %w", f.fileName, f.template.Name(), err)
+	}
+	return contents, nil
+}
+
+func formatGo(src string) (string, error) {
+	formatted, err := format.Source([]byte(src))
+	return string(formatted), err
+}
+
+// packageName returns the package of the Go and templ files in the directory. If there
+// aren't any, the name of the directory is used, or main if it isn't a valid package name.
+func packageName(dir string) string {
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		fileName := filepath.Join(dir, entry.Name())
+		switch {
+		case strings.HasSuffix(fileName, ".go"):
+			f, err := goparser.ParseFile(token.NewFileSet(), fileName, nil, goparser.PackageClauseOnly)
+			if err == nil {
+				return strings.TrimSuffix(f.Name.Name, "_test")
+			}
+		case strings.HasSuffix(fileName, ".templ"):
+			tf, err := parser.ParseFile(fileName)
+			if err == nil {
+				return strings.TrimSpace(strings.TrimPrefix(tf.Package.Expression.Value, "package"))
+			}
+		}
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(dir))
+	if !token.IsIdentifier(name) {
+		return "main"
+	}
+	return name
+}
+
+// snakeCase returns the file name for a template name, e.g. user_card for UserCard, and
+// html_page for HTMLPage.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				sb.WriteRune('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+func exists(fileName string) bool {
+	_, err := os.Stat(fileName)
+	return !errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,197 @@
+package newcmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/generatecmd"
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+func readFile(t *testing.T, fileName string) string {
+	t.Helper()
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	return string(data)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("the package is taken from the Go files in the directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"handlers_test.go": "package components_test\n"})
+		if err := Run(io.Discard, Arguments{Kind: "component", Name: "UserCard", Dir: dir}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "package components\n\ntempl UserCard(title string) {\n\t<div>\n\t\t<h2>{ title }</h2>\n\t</div>\n}\n\n"
+		if diff := cmp.Diff(expected, readFile(t, filepath.Join(dir, "user_card.templ"))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the package is taken from the templ files in the directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"header.templ": "package ui\n\ntempl Header() {\n}\n"})
+		if err := Run(io.Discard, Arguments{Kind: "layout", Name: "Base", Dir: dir}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if contents := readFile(t, filepath.Join(dir, "base.templ")); !strings.HasPrefix(contents, "package ui\n") {
+			t.Errorf("expected package ui, got:\n%s", contents)
+		}
+	})
+	t.Run("the package is named after a new directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "My-Views")
+		if err := Run(io.Discard, Arguments{Kind: "page", Name: "Home", Dir: dir, Layout: "Base"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		contents := readFile(t, filepath.Join(dir, "home.templ"))
+		if !strings.HasPrefix(contents, "package myviews\n") {
+			t.Errorf("expected package myviews, got:\n%s", contents)
+		}
+		if !strings.Contains(contents, "@Base() {") {
+			t.Errorf("expected the page to render the Base layout, got:\n%s", contents)
+		}
+	})
+	t.Run("existing files aren't overwritten without force", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"user_card_test.go": "package views\n"})
+		args := Arguments{Kind: "component", Name: "UserCard", Dir: dir, Test: true}
+		if err := Run(io.Discard, args); err == nil || !strings.Contains(err.Error(), "use -force") {
+			t.Errorf("expected an error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "user_card.templ")); err == nil {
+			t.Error("expected no files to be written")
+		}
+		args.Force = true
+		if err := Run(io.Discard, args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if contents := readFile(t, filepath.Join(dir, "user_card_test.go")); !strings.Contains(contents, "func TestUserCard(") {
+			t.Errorf("expected the test to be overwritten, got:\n%s", contents)
+		}
+	})
+	t.Run("project templates replace the built-in templates, and add kinds", func(t *testing.T) {
+		root := t.TempDir()
+		writeFiles(t, root, map[string]string{
+			"go.mod":                                "module example.com/app\n",
+			".templ/templates/component.templ.tmpl": "package {{ .Package }}\n\n// {{ .Name }} is a component.\ntempl {{ .Name }}() {\n<span></span>\n}\n",
+			".templ/templates/form.templ.tmpl":      "package {{ .Package }}\n\ntempl {{ .Name }}() {\n\t<form></form>\n}\n",
+		})
+		dir := filepath.Join(root, "views")
+		if err := Run(io.Discard, Arguments{Kind: "component", Name: "Badge", Dir: dir}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "package views\n\n// Badge is a component.\ntempl Badge() {\n\t<span></span>\n}\n\n"
+		if diff := cmp.Diff(expected, readFile(t, filepath.Join(dir, "badge.templ"))); diff != "" {
+			t.Error(diff)
+		}
+		if err := Run(io.Discard, Arguments{Kind: "form", Name: "Login", Dir: dir}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// The built-in test template is still used for a replaced kind.
+		if err := Run(io.Discard, Arguments{Kind: "component", Name: "Badge", Dir: dir, Test: true, Force: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := Run(io.Discard, Arguments{Kind: "form", Name: "Login", Dir: dir, Test: true, Force: true}); err == nil {
+			t.Error("expected an error, because there's no test template for form")
+		}
+	})
+	t.Run("invalid project templates are an error", func(t *testing.T) {
+		root := t.TempDir()
+		writeFiles(t, root, map[string]string{
+			"go.mod":                           "module example.com/app\n",
+			".templ/templates/page.templ.tmpl": "package {{ .Package }\n",
+			".templ/templates/card.templ.tmpl": "package {{ .Package }}\n\ntempl {{ .Name }}() {\n\t<div>\n}\n",
+			".templ/templates/list.templ.tmpl": "package {{ .Package }}\n\ntempl {{ .Nmae }}() {\n}\n",
+		})
+		for _, kind := range []string{"page", "card", "list"} {
+			if err := Run(io.Discard, Arguments{Kind: kind, Name: "A", Dir: root}); err == nil {
+				t.Errorf("%s: expected an error", kind)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(root, "a.templ")); err == nil {
+			t.Error("expected no files to be written")
+		}
+	})
+	t.Run("unknown kinds are an error", func(t *testing.T) {
+		err := Run(io.Discard, Arguments{Kind: "widget", Name: "A", Dir: t.TempDir()})
+		expected := `unknown kind "widget", expected one of: component, layout, page`
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected %q, got %v", expected, err)
+		}
+	})
+	t.Run("names must be Go identifiers", func(t *testing.T) {
+		if err := Run(io.Discard, Arguments{Kind: "component", Name: "user-card", Dir: t.TempDir()}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Page", expected: "page"},
+		{name: "UserCard", expected: "user_card"},
+		{name: "HTMLPage", expected: "html_page"},
+		{name: "userID", expected: "user_id"},
+		{name: "Page2Column", expected: "page2_column"},
+	}
+	for _, tt := range tests {
+		if actual := snakeCase(tt.name); actual != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestBuiltinTemplatesCompileAndPass(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+	templ, err := filepath.Abs("../../..")
+	if err != nil {
+		t.Fatalf("failed to get module root: %v", err)
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.20\n\nrequire github.com/a-h/templ v0.0.0\n\nreplace github.com/a-h/templ => " + templ + "\n",
+	})
+	dir := filepath.Join(root, "views")
+	for _, args := range []Arguments{
+		{Kind: "layout", Name: "Layout"},
+		{Kind: "component", Name: "UserCard"},
+		{Kind: "page", Name: "HomePage"},
+	} {
+		args.Dir, args.Test = dir, true
+		if err := Run(io.Discard, args); err != nil {
+			t.Fatalf("%s: unexpected error: %v", args.Kind, err)
+		}
+	}
+	if err := generatecmd.Run(generatecmd.Arguments{Path: dir, Log: io.Discard}); err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("the tests of the generated files failed: %v\n%s", err, output)
+	}
+}
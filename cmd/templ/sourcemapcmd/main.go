@@ -0,0 +1,83 @@
+package sourcemapcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/config"
+	"github.com/a-h/templ/parser/v2"
+)
+
+type Arguments struct {
+	// Position in a generated file, as file_templ.go:line or file_templ.go:line:col. Lines
+	// and columns start at 1, and columns are byte offsets, as used by the Go compiler.
+	Position string
+}
+
+// Run resolves the position in a generated Go file to the templ source that generated it,
+// using the <name>_templ.go.map file written by templ generate -sourcemap, and writes the
+// templ position to w.
+func Run(w io.Writer, args Arguments) (err error) {
+	fileName, line, col, err := parsePosition(args.Position)
+	if err != nil {
+		return err
+	}
+	output, err := config.Load(filepath.Dir(fileName))
+	if err != nil {
+		return err
+	}
+	templFileName, ok := output.Output().TemplFileName(fileName)
+	if !ok {
+		return fmt.Errorf("%s is not a generated templ file", fileName)
+	}
+	data, err := os.ReadFile(fileName + ".map")
+	if err != nil {
+		return fmt.Errorf("failed to read source map, was the file generated with templ generate -sourcemap? %w", err)
+	}
+	sm := parser.NewSourceMap()
+	if err = json.Unmarshal(data, sm); err != nil {
+		return fmt.Errorf("%s.map: %w", fileName, err)
+	}
+
+	var src parser.Position
+	if col > 0 {
+		src, ok = sm.SourcePositionFromTarget(uint32(line-1), uint32(col-1))
+	} else {
+		// Use the first column on the line that's mapped.
+		goCode, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(string(goCode), "\n")
+		for c := 0; line <= len(lines) && c <= len(lines[line-1]) && !ok; c++ {
+			src, ok = sm.SourcePositionFromTarget(uint32(line-1), uint32(c))
+		}
+	}
+	if !ok {
+		return fmt.Errorf("%s: the position was not generated from templ source", args.Position)
+	}
+	_, err = fmt.Fprintf(w, "%s:%d:%d\n", templFileName, src.Line+1, src.Col+1)
+	return err
+}
+
+func parsePosition(s string) (fileName string, line, col int, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", 0, 0, fmt.Errorf("invalid position %q, expected file_templ.go:line or file_templ.go:line:col", s)
+	}
+	fileName = parts[0]
+	if line, err = strconv.Atoi(parts[1]); err != nil || line < 1 {
+		return "", 0, 0, fmt.Errorf("invalid line in position %q", s)
+	}
+	if len(parts) == 3 {
+		if col, err = strconv.Atoi(parts[2]); err != nil || col < 1 {
+			return "", 0, 0, fmt.Errorf("invalid column in position %q", s)
+		}
+	}
+	return fileName, line, col, nil
+}
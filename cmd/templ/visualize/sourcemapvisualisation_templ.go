@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: b7106057c4a7d06ecbffabff995021936a88041d2af3c220af050114ba8486e5
 
 package visualize
 
@@ -10,7 +12,9 @@ import "io"
 import "bytes"
 import "strings"
 
+//line sourcemapvisualisation.templ:3
 func row() templ.CSSClass {
+//line sourcemapvisualisation_templ.go:18
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`display:flex;`)
 	templCSSID := templ.CSSID(`row`, templCSSBuilder.String())
@@ -20,7 +24,9 @@ func row() templ.CSSClass {
 	}
 }
 
+//line sourcemapvisualisation.templ:7
 func column() templ.CSSClass {
+//line sourcemapvisualisation_templ.go:30
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`flex:50%;`)
 	templCSSBuilder.WriteString(`overflow-y:scroll;`)
@@ -32,7 +38,9 @@ func column() templ.CSSClass {
 	}
 }
 
+//line sourcemapvisualisation.templ:13
 func code() templ.CSSClass {
+//line sourcemapvisualisation_templ.go:44
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`font-family:monospace;`)
 	templCSSID := templ.CSSID(`code`, templCSSBuilder.String())
@@ -42,133 +50,153 @@ func code() templ.CSSClass {
 	}
 }
 
+//line sourcemapvisualisation.templ:17
 func combine(templFileName string, left, right templ.Component) templ.Component {
+//line sourcemapvisualisation_templ.go:56
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "visualize.combine"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html><head><title>")
-		if err != nil {
-			return err
-		}
-		var var_2 string = templFileName
-		_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-		if err != nil {
-			return err
-		}
-		var_3 := `- Source Map Visualisation`
-		_, err = templBuffer.WriteString(var_3)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</title><style type=\"text/css\">")
-		if err != nil {
-			return err
-		}
-		var_4 := `
-				.mapped { background-color: green }
-				.highlighted { background-color: yellow }
-			`
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</style></head><body><h1>")
-		if err != nil {
-			return err
-		}
-		var var_5 string = templFileName
-		_, err = templBuffer.WriteString(templ.EscapeString(var_5))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1>")
-		if err != nil {
-			return err
-		}
-		var var_6 = []any{templ.Classes(row())}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_6...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_6).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var var_7 = []any{templ.Classes(column(), code())}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_7...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_7).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html><head><title>")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line sourcemapvisualisation.templ:20
+			var_2, err = templ.EscapeAny(templFileName)
+//line sourcemapvisualisation_templ.go:82
+			if err != nil {
+				return templ.WrapError(err, "cmd/templ/visualize/sourcemapvisualisation.templ", 20, 13)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("- Source Map Visualisation</title><style type=\"text/css\">\n\t\t\t\t.mapped { background-color: green }\n\t\t\t\t.highlighted { background-color: yellow }\n\t\t\t</style></head><body><h1>")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line sourcemapvisualisation.templ:27
+			var_3, err = templ.EscapeAny(templFileName)
+//line sourcemapvisualisation_templ.go:97
+			if err != nil {
+				return templ.WrapError(err, "cmd/templ/visualize/sourcemapvisualisation.templ", 27, 10)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1>")
+			if err != nil {
+				return err
+			}
+//line sourcemapvisualisation.templ:28
+			var var_4 = []any{templ.Classes(row())}
+//line sourcemapvisualisation_templ.go:111
+			err = templ.RenderCSSItems(ctx, templBuffer, var_4...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_4).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+//line sourcemapvisualisation.templ:29
+			var var_5 = []any{templ.Classes(column(), code())}
+//line sourcemapvisualisation_templ.go:130
+			err = templ.RenderCSSItems(ctx, templBuffer, var_5...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_5).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+		}
+//line sourcemapvisualisation.templ:30
 		err = left.Render(ctx, templBuffer)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		var var_8 = []any{templ.Classes(column(), code())}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_8...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_8).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
+//line sourcemapvisualisation_templ.go:150
+		if err != nil {
+			return templ.WrapError(err, "cmd/templ/visualize/sourcemapvisualisation.templ", 30, 9)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div>")
+			if err != nil {
+				return err
+			}
+//line sourcemapvisualisation.templ:32
+			var var_6 = []any{templ.Classes(column(), code())}
+//line sourcemapvisualisation_templ.go:161
+			err = templ.RenderCSSItems(ctx, templBuffer, var_6...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_6).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+		}
+//line sourcemapvisualisation.templ:33
 		err = right.Render(ctx, templBuffer)
+//line sourcemapvisualisation_templ.go:181
 		if err != nil {
-			return err
+			return templ.WrapError(err, "cmd/templ/visualize/sourcemapvisualisation.templ", 33, 9)
 		}
-		_, err = templBuffer.WriteString("</div></div></body></html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div></div></body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line sourcemapvisualisation.templ:40
 func highlight(sourceId, targetId string) templ.ComponentScript {
+//line sourcemapvisualisation_templ.go:200
 	return templ.ComponentScript{
 		Name: `__templ_highlight_ae80`,
 		Function: `function __templ_highlight_ae80(sourceId, targetId){let items = document.getElementsByClassName(sourceId);
@@ -183,7 +211,9 @@ func highlight(sourceId, targetId string) templ.ComponentScript {
 	}
 }
 
+//line sourcemapvisualisation.templ:51
 func removeHighlight(sourceId, targetId string) templ.ComponentScript {
+//line sourcemapvisualisation_templ.go:217
 	return templ.ComponentScript{
 		Name: `__templ_removeHighlight_58f2`,
 		Function: `function __templ_removeHighlight_58f2(sourceId, targetId){let items = document.getElementsByClassName(sourceId);
@@ -198,69 +228,90 @@ func removeHighlight(sourceId, targetId string) templ.ComponentScript {
 	}
 }
 
+//line sourcemapvisualisation.templ:62
 func mappedCharacter(s string, sourceID, targetID string) templ.Component {
+//line sourcemapvisualisation_templ.go:234
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "visualize.mappedCharacter"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_9 := templ.GetChildren(ctx)
-		if var_9 == nil {
-			var_9 = templ.NopComponent
+		var_7 := templ.GetChildren(ctx)
+		if var_7 == nil {
+			var_7 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_10 = []any{templ.Classes(templ.Class("mapped"), templ.Class(sourceID), templ.Class(targetID))}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_10...)
-		if err != nil {
-			return err
-		}
-		err = templ.RenderScriptItems(ctx, templBuffer, highlight(sourceID, targetID), removeHighlight(sourceID, targetID))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<span class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_10).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" onMouseOver=\"")
-		if err != nil {
-			return err
-		}
-		var var_11 templ.ComponentScript = highlight(sourceID, targetID)
-		_, err = templBuffer.WriteString(var_11.Call)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" onMouseOut=\"")
-		if err != nil {
-			return err
-		}
-		var var_12 templ.ComponentScript = removeHighlight(sourceID, targetID)
-		_, err = templBuffer.WriteString(var_12.Call)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var var_13 string = s
-		_, err = templBuffer.WriteString(templ.EscapeString(var_13))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</span>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+//line sourcemapvisualisation.templ:63
+			var var_8 = []any{templ.Classes(templ.Class("mapped"), templ.Class(sourceID), templ.Class(targetID))}
+//line sourcemapvisualisation_templ.go:255
+			err = templ.RenderCSSItems(ctx, templBuffer, var_8...)
+			if err != nil {
+				return err
+			}
+			err = templ.RenderScriptItems(ctx, templBuffer, highlight(sourceID, targetID), removeHighlight(sourceID, targetID))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<span class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_8).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" onMouseOver=\"")
+			if err != nil {
+				return err
+			}
+//line sourcemapvisualisation.templ:63
+			var var_9 templ.ComponentScript = highlight(sourceID, targetID)
+//line sourcemapvisualisation_templ.go:278
+			_, err = templBuffer.WriteString(var_9.Call)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" onMouseOut=\"")
+			if err != nil {
+				return err
+			}
+//line sourcemapvisualisation.templ:63
+			var var_10 templ.ComponentScript = removeHighlight(sourceID, targetID)
+//line sourcemapvisualisation_templ.go:289
+			_, err = templBuffer.WriteString(var_10.Call)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_11 string
+//line sourcemapvisualisation.templ:63
+			var_11, err = templ.EscapeAny(s)
+//line sourcemapvisualisation_templ.go:301
+			if err != nil {
+				return templ.WrapError(err, "cmd/templ/visualize/sourcemapvisualisation.templ", 63, 200)
+			}
+			_, err = templBuffer.WriteString(var_11)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</span>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: ff56936011f9b46baf5586486d1ba74b1bb4114d19973586baf5fa3dbf4a7dd4
 
 package main
 
@@ -9,317 +11,411 @@ import "context"
 import "io"
 import "bytes"
 
+//line posts.templ:3
 import "fmt"
 import "time"
 
+//line posts.templ:6
 func headerTemplate(name string) templ.Component {
+//line posts_templ.go:21
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.headerTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<header data-testid=\"headerTemplate\"><h1>")
-		if err != nil {
-			return err
-		}
-		var var_2 string = name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1></header>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<header data-testid=\"headerTemplate\"><h1>")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line posts.templ:8
+			var_2, err = templ.EscapeAny(name)
+//line posts_templ.go:47
+			if err != nil {
+				return templ.WrapError(err, "examples/blog/posts.templ", 8, 9)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1></header>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line posts.templ:12
 func footerTemplate() templ.Component {
+//line posts_templ.go:69
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.footerTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_3 := templ.GetChildren(ctx)
 		if var_3 == nil {
 			var_3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<footer data-testid=\"footerTemplate\"><div>")
-		if err != nil {
-			return err
-		}
-		var_4 := `&copy; `
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		var var_5 string = fmt.Sprintf("%d", time.Now().Year())
-		_, err = templBuffer.WriteString(templ.EscapeString(var_5))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div></footer>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<footer data-testid=\"footerTemplate\"><div>&copy; ")
+			if err != nil {
+				return err
+			}
+			var var_4 string
+//line posts.templ:14
+			var_4, err = templ.EscapeAny(fmt.Sprintf("%d", time.Now().Year()))
+//line posts_templ.go:95
+			if err != nil {
+				return templ.WrapError(err, "examples/blog/posts.templ", 14, 17)
+			}
+			_, err = templBuffer.WriteString(var_4)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div></footer>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line posts.templ:18
 func navTemplate() templ.Component {
+//line posts_templ.go:117
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.navTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_6 := templ.GetChildren(ctx)
-		if var_6 == nil {
-			var_6 = templ.NopComponent
+		var_5 := templ.GetChildren(ctx)
+		if var_5 == nil {
+			var_5 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<nav data-testid=\"navTemplate\"><ul><li><a href=\"/\">")
-		if err != nil {
-			return err
-		}
-		var_7 := `Home`
-		_, err = templBuffer.WriteString(var_7)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a></li><li><a href=\"/posts\">")
-		if err != nil {
-			return err
-		}
-		var_8 := `Posts`
-		_, err = templBuffer.WriteString(var_8)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a></li></ul></nav>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<nav data-testid=\"navTemplate\"><ul><li><a href=\"/\">Home</a></li><li><a href=\"/posts\">Posts</a></li></ul></nav>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line posts.templ:27
 func layout(name string) templ.Component {
+//line posts_templ.go:150
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.layout"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_9 := templ.GetChildren(ctx)
-		if var_9 == nil {
-			var_9 = templ.NopComponent
+		var_6 := templ.GetChildren(ctx)
+		if var_6 == nil {
+			var_6 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html><head><title>")
-		if err != nil {
-			return err
-		}
-		var var_10 string = name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_10))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</title></head><body>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html><head><title>")
+			if err != nil {
+				return err
+			}
+			var var_7 string
+//line posts.templ:29
+			var_7, err = templ.EscapeAny(name)
+//line posts_templ.go:176
+			if err != nil {
+				return templ.WrapError(err, "examples/blog/posts.templ", 29, 18)
+			}
+			_, err = templBuffer.WriteString(var_7)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</title></head><body>")
+			if err != nil {
+				return err
+			}
 		}
+//line posts.templ:31
 		err = headerTemplate(name).Render(ctx, templBuffer)
+//line posts_templ.go:191
 		if err != nil {
-			return err
+			return templ.WrapError(err, "examples/blog/posts.templ", 31, 5)
 		}
+//line posts.templ:32
 		err = navTemplate().Render(ctx, templBuffer)
+//line posts_templ.go:197
 		if err != nil {
-			return err
+			return templ.WrapError(err, "examples/blog/posts.templ", 32, 5)
 		}
-		_, err = templBuffer.WriteString("<main>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<main>")
+			if err != nil {
+				return err
+			}
 		}
-		err = var_9.Render(ctx, templBuffer)
+		err = var_6.Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</main></body>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("</main></body>")
+			if err != nil {
+				return err
+			}
 		}
+//line posts.templ:37
 		err = footerTemplate().Render(ctx, templBuffer)
+//line posts_templ.go:219
 		if err != nil {
-			return err
+			return templ.WrapError(err, "examples/blog/posts.templ", 37, 4)
 		}
-		_, err = templBuffer.WriteString("</html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line posts.templ:41
 func postsTemplate(posts []Post) templ.Component {
+//line posts_templ.go:238
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.postsTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_11 := templ.GetChildren(ctx)
-		if var_11 == nil {
-			var_11 = templ.NopComponent
+		var_8 := templ.GetChildren(ctx)
+		if var_8 == nil {
+			var_8 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div data-testid=\"postsTemplate\">")
-		if err != nil {
-			return err
-		}
-		for _, p := range posts {
-			_, err = templBuffer.WriteString("<div data-testid=\"postsTemplatePost\"><div data-testid=\"postsTemplatePostName\">")
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div data-testid=\"postsTemplate\">")
 			if err != nil {
 				return err
 			}
-			var var_12 string = p.Name
-			_, err = templBuffer.WriteString(templ.EscapeString(var_12))
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("</div><div data-testid=\"postsTemplatePostAuthor\">")
-			if err != nil {
-				return err
+		}
+//line posts.templ:43
+		for _, p := range posts {
+//line posts_templ.go:264
+			if !templSkip {
+				_, err = templBuffer.WriteString("<div data-testid=\"postsTemplatePost\"><div data-testid=\"postsTemplatePostName\">")
+				if err != nil {
+					return err
+				}
+				var var_9 string
+//line posts.templ:45
+				var_9, err = templ.EscapeAny(p.Name)
+//line posts_templ.go:273
+				if err != nil {
+					return templ.WrapError(err, "examples/blog/posts.templ", 45, 48)
+				}
+				_, err = templBuffer.WriteString(var_9)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</div><div data-testid=\"postsTemplatePostAuthor\">")
+				if err != nil {
+					return err
+				}
+				var var_10 string
+//line posts.templ:46
+				var_10, err = templ.EscapeAny(p.Author)
+//line posts_templ.go:288
+				if err != nil {
+					return templ.WrapError(err, "examples/blog/posts.templ", 46, 50)
+				}
+				_, err = templBuffer.WriteString(var_10)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</div></div>")
+				if err != nil {
+					return err
+				}
 			}
-			var var_13 string = p.Author
-			_, err = templBuffer.WriteString(templ.EscapeString(var_13))
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div>")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("</div></div>")
-			if err != nil {
-				return err
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
 			}
 		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
-		}
 		return err
 	})
 }
 
+//line posts.templ:52
 func home() templ.Component {
+//line posts_templ.go:317
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.home"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_14 := templ.GetChildren(ctx)
-		if var_14 == nil {
-			var_14 = templ.NopComponent
+		var_11 := templ.GetChildren(ctx)
+		if var_11 == nil {
+			var_11 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var_15 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		var_12 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 			templBuffer, templIsBuffer := w.(*bytes.Buffer)
 			if !templIsBuffer {
 				templBuffer = templ.GetBuffer()
 				defer templ.ReleaseBuffer(templBuffer)
+				ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 			}
-			_, err = templBuffer.WriteString("<div data-testid=\"homeTemplate\">")
-			if err != nil {
-				return err
-			}
-			var_16 := `Welcome to my website.`
-			_, err = templBuffer.WriteString(var_16)
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("</div>")
-			if err != nil {
-				return err
-			}
-			if !templIsBuffer {
-				_, err = io.Copy(w, templBuffer)
+			templSkip := templ.IsOutputSkipped(ctx)
+			if !templSkip {
+				_, err = templBuffer.WriteString("<div data-testid=\"homeTemplate\">Welcome to my website.</div>")
+				if err != nil {
+					return err
+				}
+				if !templIsBuffer {
+					_, err = io.Copy(w, templBuffer)
+				}
 			}
 			return err
 		})
-		err = layout("Home").Render(templ.WithChildren(ctx, var_15), templBuffer)
+//line posts.templ:53
+		err = layout("Home").Render(templ.WithChildren(ctx, var_12), templBuffer)
+//line posts_templ.go:356
 		if err != nil {
-			return err
+			return templ.WrapError(err, "examples/blog/posts.templ", 53, 3)
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line posts.templ:58
 func posts(posts []Post) templ.Component {
+//line posts_templ.go:371
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.posts"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_17 := templ.GetChildren(ctx)
-		if var_17 == nil {
-			var_17 = templ.NopComponent
+		var_13 := templ.GetChildren(ctx)
+		if var_13 == nil {
+			var_13 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var_18 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		var_14 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 			templBuffer, templIsBuffer := w.(*bytes.Buffer)
 			if !templIsBuffer {
 				templBuffer = templ.GetBuffer()
 				defer templ.ReleaseBuffer(templBuffer)
+				ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 			}
+			templSkip := templ.IsOutputSkipped(ctx)
+//line posts.templ:60
 			err = postsTemplate(posts).Render(ctx, templBuffer)
+//line posts_templ.go:399
 			if err != nil {
-				return err
+				return templ.WrapError(err, "examples/blog/posts.templ", 60, 4)
 			}
-			if !templIsBuffer {
-				_, err = io.Copy(w, templBuffer)
+			if !templSkip {
+				if !templIsBuffer {
+					_, err = io.Copy(w, templBuffer)
+				}
 			}
 			return err
 		})
-		err = layout("Posts").Render(templ.WithChildren(ctx, var_18), templBuffer)
+//line posts.templ:59
+		err = layout("Posts").Render(templ.WithChildren(ctx, var_14), templBuffer)
+//line posts_templ.go:412
 		if err != nil {
-			return err
+			return templ.WrapError(err, "examples/blog/posts.templ", 59, 3)
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: c717f963d8cb90251939d84fc07bd14711acf724d123d2e9db929465774de72b
 
 package main
 
@@ -9,151 +11,157 @@ import "context"
 import "io"
 import "bytes"
 
+//line components.templ:3
 import "strconv"
 
+//line components.templ:5
 func counts(global, user int) templ.Component {
+//line components_templ.go:20
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.counts"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div>")
-		if err != nil {
-			return err
-		}
-		var_2 := `Global: `
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		var var_3 string = strconv.Itoa(global)
-		_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div><div>")
-		if err != nil {
-			return err
-		}
-		var_4 := `User: `
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		var var_5 string = strconv.Itoa(user)
-		_, err = templBuffer.WriteString(templ.EscapeString(var_5))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div>Global: ")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line components.templ:6
+			var_2, err = templ.EscapeAny(strconv.Itoa(global))
+//line components_templ.go:46
+			if err != nil {
+				return templ.WrapError(err, "components.templ", 6, 17)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div><div>User: ")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line components.templ:7
+			var_3, err = templ.EscapeAny(strconv.Itoa(user))
+//line components_templ.go:61
+			if err != nil {
+				return templ.WrapError(err, "components.templ", 7, 15)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line components.templ:10
 func form() templ.Component {
+//line components_templ.go:83
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.form"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_6 := templ.GetChildren(ctx)
-		if var_6 == nil {
-			var_6 = templ.NopComponent
+		var_4 := templ.GetChildren(ctx)
+		if var_4 == nil {
+			var_4 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<form action=\"/\" method=\"POST\"><div><button type=\"submit\" name=\"global\" value=\"global\">")
-		if err != nil {
-			return err
-		}
-		var_7 := `Global`
-		_, err = templBuffer.WriteString(var_7)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button></div><div><button type=\"submit\" name=\"user\" value=\"user\">")
-		if err != nil {
-			return err
-		}
-		var_8 := `User`
-		_, err = templBuffer.WriteString(var_8)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button></div></form>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<form action=\"/\" method=\"POST\"><div><button type=\"submit\" name=\"global\" value=\"global\">Global</button></div><div><button type=\"submit\" name=\"user\" value=\"user\">User</button></div></form>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line components.templ:17
 func page(global, user int) templ.Component {
+//line components_templ.go:116
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.page"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_9 := templ.GetChildren(ctx)
-		if var_9 == nil {
-			var_9 = templ.NopComponent
+		var_5 := templ.GetChildren(ctx)
+		if var_5 == nil {
+			var_5 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
-		if err != nil {
-			return err
-		}
-		var_10 := `Counts`
-		_, err = templBuffer.WriteString(var_10)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</title><link rel=\"stylesheet\" href=\"/assets/bulma.min.css\"><link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"/assets/favicon/apple-touch-icon.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"32x32\" href=\"/assets/favicon/favicon-32x32.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"16x16\" href=\"/assets/favicon/favicon-16x16.png\"><link rel=\"manifest\" href=\"/assets/favicon/site.webmanifest\"></head><body class=\"bg-gray-100\"><header class=\"hero is-primary\"><div class=\"hero-body\"><div class=\"container\"><h1 class=\"title\">")
-		if err != nil {
-			return err
-		}
-		var_11 := `Counts`
-		_, err = templBuffer.WriteString(var_11)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1></div></div></header><section class=\"section\"><div class=\"container\"><div class=\"columns is-centered\"><div class=\"column is-half\">")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>Counts</title><link rel=\"stylesheet\" href=\"/assets/bulma.min.css\"><link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"/assets/favicon/apple-touch-icon.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"32x32\" href=\"/assets/favicon/favicon-32x32.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"16x16\" href=\"/assets/favicon/favicon-16x16.png\"><link rel=\"manifest\" href=\"/assets/favicon/site.webmanifest\"></head><body class=\"bg-gray-100\"><header class=\"hero is-primary\"><div class=\"hero-body\"><div class=\"container\"><h1 class=\"title\">Counts</h1></div></div></header><section class=\"section\"><div class=\"container\"><div class=\"columns is-centered\"><div class=\"column is-half\">")
+			if err != nil {
+				return err
+			}
 		}
+//line components.templ:40
 		err = counts(global, user).Render(ctx, templBuffer)
+//line components_templ.go:142
 		if err != nil {
-			return err
+			return templ.WrapError(err, "components.templ", 40, 36)
 		}
-		err = form().Render(ctx, templBuffer)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div></div></div></section></body></html>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString(" ")
+			if err != nil {
+				return err
+			}
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+//line components.templ:40
+		err = form().Render(ctx, templBuffer)
+//line components_templ.go:154
+		if err != nil {
+			return templ.WrapError(err, "components.templ", 40, 58)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div></div></div></section></body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: e5ffb89d3e759928519f3db41d83c71565d02d5b42df3515017a04166a451269
 
 package components
 
@@ -10,9 +12,12 @@ import "io"
 import "bytes"
 import "strings"
 
+//line components.templ:3
 import "strconv"
 
+//line components.templ:5
 func border() templ.CSSClass {
+//line components_templ.go:21
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`border:1px solid #eeeeee;`)
 	templCSSBuilder.WriteString(`border-radius:4px;`)
@@ -26,172 +31,147 @@ func border() templ.CSSClass {
 	}
 }
 
+//line components.templ:13
 func counts(global, session int) templ.Component {
+//line components_templ.go:37
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "components.counts"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<form id=\"countsForm\" action=\"/\" method=\"POST\" hx-post=\"/\" hx-select=\"#countsForm\" hx-swap=\"outerHTML\"><div class=\"columns\">")
-		if err != nil {
-			return err
-		}
-		var var_2 = []any{"column", "has-text-centered", "is-primary", border}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"><h1 class=\"title is-size-1 has-text-centered\">")
-		if err != nil {
-			return err
-		}
-		var var_3 string = strconv.Itoa(global)
-		_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1><p class=\"subtitle has-text-centered\">")
-		if err != nil {
-			return err
-		}
-		var_4 := `Global`
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</p><div><button class=\"button is-primary\" type=\"submit\" name=\"global\" value=\"global\">")
-		if err != nil {
-			return err
-		}
-		var_5 := `+1`
-		_, err = templBuffer.WriteString(var_5)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button></div></div>")
-		if err != nil {
-			return err
-		}
-		var var_6 = []any{"column", "has-text-centered", border}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_6...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_6).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"><h1 class=\"title is-size-1 has-text-centered\">")
-		if err != nil {
-			return err
-		}
-		var var_7 string = strconv.Itoa(session)
-		_, err = templBuffer.WriteString(templ.EscapeString(var_7))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1><p class=\"subtitle has-text-centered\">")
-		if err != nil {
-			return err
-		}
-		var_8 := `Session`
-		_, err = templBuffer.WriteString(var_8)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</p><div><button class=\"button is-secondary\" type=\"submit\" name=\"session\" value=\"session\">")
-		if err != nil {
-			return err
-		}
-		var_9 := `+1`
-		_, err = templBuffer.WriteString(var_9)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button></div></div></div></form>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<form id=\"countsForm\" action=\"/\" method=\"POST\" hx-post=\"/\" hx-select=\"#countsForm\" hx-swap=\"outerHTML\"><div class=\"columns\">")
+			if err != nil {
+				return err
+			}
+//line components.templ:16
+			var var_2 = []any{"column", "has-text-centered", "is-primary", border}
+//line components_templ.go:62
+			err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"><h1 class=\"title is-size-1 has-text-centered\">")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line components.templ:17
+			var_3, err = templ.EscapeAny(strconv.Itoa(global))
+//line components_templ.go:82
+			if err != nil {
+				return templ.WrapError(err, "components/components.templ", 17, 53)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1><p class=\"subtitle has-text-centered\">Global</p><div><button class=\"button is-primary\" type=\"submit\" name=\"global\" value=\"global\">+1</button></div></div>")
+			if err != nil {
+				return err
+			}
+//line components.templ:21
+			var var_4 = []any{"column", "has-text-centered", border}
+//line components_templ.go:96
+			err = templ.RenderCSSItems(ctx, templBuffer, var_4...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_4).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"><h1 class=\"title is-size-1 has-text-centered\">")
+			if err != nil {
+				return err
+			}
+			var var_5 string
+//line components.templ:22
+			var_5, err = templ.EscapeAny(strconv.Itoa(session))
+//line components_templ.go:116
+			if err != nil {
+				return templ.WrapError(err, "components/components.templ", 22, 53)
+			}
+			_, err = templBuffer.WriteString(var_5)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1><p class=\"subtitle has-text-centered\">Session</p><div><button class=\"button is-secondary\" type=\"submit\" name=\"session\" value=\"session\">+1</button></div></div></div></form>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line components.templ:30
 func Page(global, session int) templ.Component {
+//line components_templ.go:138
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "components.Page"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_10 := templ.GetChildren(ctx)
-		if var_10 == nil {
-			var_10 = templ.NopComponent
+		var_6 := templ.GetChildren(ctx)
+		if var_6 == nil {
+			var_6 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
-		if err != nil {
-			return err
-		}
-		var_11 := `Counts`
-		_, err = templBuffer.WriteString(var_11)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</title><link rel=\"stylesheet\" href=\"/assets/css/bulma.min.css\"><link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"/assets/favicon/apple-touch-icon.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"32x32\" href=\"/assets/favicon/favicon-32x32.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"16x16\" href=\"/assets/favicon/favicon-16x16.png\"><link rel=\"manifest\" href=\"/assets/favicon/site.webmanifest\"><script src=\"/assets/js/htmx.min.js\">")
-		if err != nil {
-			return err
-		}
-		var_12 := ``
-		_, err = templBuffer.WriteString(var_12)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</script></head><body class=\"bg-gray-100\"><header class=\"hero is-primary\"><div class=\"hero-body\"><div class=\"container\"><h1 class=\"title\">")
-		if err != nil {
-			return err
-		}
-		var_13 := `Counts`
-		_, err = templBuffer.WriteString(var_13)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1></div></div></header><section class=\"section\"><div class=\"container\"><div class=\"columns is-centered\"><div class=\"column is-half\">")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>Counts</title><link rel=\"stylesheet\" href=\"/assets/css/bulma.min.css\"><link rel=\"apple-touch-icon\" sizes=\"180x180\" href=\"/assets/favicon/apple-touch-icon.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"32x32\" href=\"/assets/favicon/favicon-32x32.png\"><link rel=\"icon\" type=\"image/png\" sizes=\"16x16\" href=\"/assets/favicon/favicon-16x16.png\"><link rel=\"manifest\" href=\"/assets/favicon/site.webmanifest\"><script src=\"/assets/js/htmx.min.js\"></script></head><body class=\"bg-gray-100\"><header class=\"hero is-primary\"><div class=\"hero-body\"><div class=\"container\"><h1 class=\"title\">Counts</h1></div></div></header><section class=\"section\"><div class=\"container\"><div class=\"columns is-centered\"><div class=\"column is-half\">")
+			if err != nil {
+				return err
+			}
 		}
+//line components.templ:54
 		err = counts(global, session).Render(ctx, templBuffer)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div></div></div></section></body></html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+//line components_templ.go:164
+		if err != nil {
+			return templ.WrapError(err, "components/components.templ", 54, 36)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div></div></div></section></body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
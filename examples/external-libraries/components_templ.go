@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 9c48e42386580e03e7ecf07ad404159032ced78c864a27b748926c4bebee949c
 
 package main
 
@@ -9,7 +11,9 @@ import "context"
 import "io"
 import "bytes"
 
+//line components.templ:3
 func graph(data []TimeValue) templ.ComponentScript {
+//line components_templ.go:17
 	return templ.ComponentScript{
 		Name: `__templ_graph_c2ba`,
 		Function: `function __templ_graph_c2ba(data){const chart = LightweightCharts.createChart(document.body, { width: 400, height: 300 });
@@ -19,60 +23,53 @@ func graph(data []TimeValue) templ.ComponentScript {
 	}
 }
 
+//line components.templ:9
 func page(data []TimeValue) templ.Component {
+//line components_templ.go:29
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.page"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
-		if err != nil {
-			return err
-		}
-		var_2 := `Graphs`
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</title><script src=\"https://unpkg.com/lightweight-charts/dist/lightweight-charts.standalone.production.js\">")
-		if err != nil {
-			return err
-		}
-		var_3 := ``
-		_, err = templBuffer.WriteString(var_3)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</script></head>")
-		if err != nil {
-			return err
-		}
-		err = templ.RenderScriptItems(ctx, templBuffer, graph(data))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<body onload=\"")
-		if err != nil {
-			return err
-		}
-		var var_4 templ.ComponentScript = graph(data)
-		_, err = templBuffer.WriteString(var_4.Call)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"></body></html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>Graphs</title><script src=\"https://unpkg.com/lightweight-charts/dist/lightweight-charts.standalone.production.js\"></script></head>")
+			if err != nil {
+				return err
+			}
+			err = templ.RenderScriptItems(ctx, templBuffer, graph(data))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<body onload=\"")
+			if err != nil {
+				return err
+			}
+//line components.templ:17
+			var var_2 templ.ComponentScript = graph(data)
+//line components_templ.go:62
+			_, err = templBuffer.WriteString(var_2.Call)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
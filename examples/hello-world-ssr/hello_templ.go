@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 7a858e38f0b497d31ff0a570ed10febf43b0f86de6bbad2f8e2c1536f88a8fa4
 
 package main
 
@@ -9,39 +11,49 @@ import "context"
 import "io"
 import "bytes"
 
+//line hello.templ:3
 func hello(name string) templ.Component {
+//line hello_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.hello"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div>")
-		if err != nil {
-			return err
-		}
-		var_2 := `Hello, `
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		var var_3 string = name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div>Hello, ")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line hello.templ:4
+			var_2, err = templ.EscapeAny(name)
+//line hello_templ.go:43
+			if err != nil {
+				return templ.WrapError(err, "examples/hello-world-ssr/hello.templ", 4, 16)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: d180ada595600a9dadf7eb40b935eff9376a732f2805abff3a78ec31c5a42c0b
 
 package main
 
@@ -9,184 +11,252 @@ import "context"
 import "io"
 import "bytes"
 
+//line blog.templ:3
 import "path"
 import "github.com/gosimple/slug"
 
+//line blog.templ:6
 func headerComponent(title string) templ.Component {
+//line blog_templ.go:21
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.headerComponent"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<head><title>")
-		if err != nil {
-			return err
-		}
-		var var_2 string = title
-		_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</title></head>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<head><title>")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line blog.templ:7
+			var_2, err = templ.EscapeAny(title)
+//line blog_templ.go:47
+			if err != nil {
+				return templ.WrapError(err, "blog.templ", 7, 17)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</title></head>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line blog.templ:10
 func contentComponent(title string, body templ.Component) templ.Component {
+//line blog_templ.go:69
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.contentComponent"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_3 := templ.GetChildren(ctx)
 		if var_3 == nil {
 			var_3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<body><h1>")
-		if err != nil {
-			return err
-		}
-		var var_4 string = title
-		_, err = templBuffer.WriteString(templ.EscapeString(var_4))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1><div class=\"content\">")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<body><h1>")
+			if err != nil {
+				return err
+			}
+			var var_4 string
+//line blog.templ:12
+			var_4, err = templ.EscapeAny(title)
+//line blog_templ.go:95
+			if err != nil {
+				return templ.WrapError(err, "blog.templ", 12, 9)
+			}
+			_, err = templBuffer.WriteString(var_4)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1><div class=\"content\">")
+			if err != nil {
+				return err
+			}
 		}
+//line blog.templ:14
 		err = body.Render(ctx, templBuffer)
+//line blog_templ.go:110
 		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div></body>")
-		if err != nil {
-			return err
+			return templ.WrapError(err, "blog.templ", 14, 7)
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div></body>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line blog.templ:19
 func contentPage(title string, body templ.Component) templ.Component {
+//line blog_templ.go:129
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.contentPage"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_5 := templ.GetChildren(ctx)
 		if var_5 == nil {
 			var_5 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html>")
+			if err != nil {
+				return err
+			}
 		}
+//line blog.templ:21
 		err = headerComponent(title).Render(ctx, templBuffer)
+//line blog_templ.go:155
 		if err != nil {
-			return err
+			return templ.WrapError(err, "blog.templ", 21, 4)
 		}
+//line blog.templ:22
 		err = contentComponent(title, body).Render(ctx, templBuffer)
+//line blog_templ.go:161
 		if err != nil {
-			return err
+			return templ.WrapError(err, "blog.templ", 22, 4)
 		}
-		_, err = templBuffer.WriteString("</html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line blog.templ:26
 func indexPage(posts []Post) templ.Component {
+//line blog_templ.go:180
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.indexPage"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_6 := templ.GetChildren(ctx)
 		if var_6 == nil {
 			var_6 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html>")
+			if err != nil {
+				return err
+			}
 		}
+//line blog.templ:28
 		err = headerComponent("My Blog").Render(ctx, templBuffer)
+//line blog_templ.go:206
 		if err != nil {
-			return err
+			return templ.WrapError(err, "blog.templ", 28, 4)
 		}
-		_, err = templBuffer.WriteString("<body><h1>")
-		if err != nil {
-			return err
-		}
-		var_7 := `My Blog`
-		_, err = templBuffer.WriteString(var_7)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1>")
-		if err != nil {
-			return err
-		}
-		for _, post := range posts {
-			_, err = templBuffer.WriteString("<div><a href=\"")
+		if !templSkip {
+			_, err = templBuffer.WriteString("<body><h1>My Blog</h1>")
 			if err != nil {
 				return err
 			}
-			var var_8 templ.SafeURL = templ.SafeURL(path.Join(post.Date.Format("2006/01/02"), slug.Make(post.Title), "/"))
-			_, err = templBuffer.WriteString(templ.EscapeString(string(var_8)))
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString("\">")
-			if err != nil {
-				return err
+		}
+//line blog.templ:31
+		for _, post := range posts {
+//line blog_templ.go:218
+			if !templSkip {
+				_, err = templBuffer.WriteString("<div><a href=\"")
+				if err != nil {
+					return err
+				}
+//line blog.templ:32
+				var var_7 templ.SafeURL = templ.SafeURL(path.Join(post.Date.Format("2006/01/02"), slug.Make(post.Title), "/"))
+//line blog_templ.go:226
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_7)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\">")
+				if err != nil {
+					return err
+				}
+				var var_8 string
+//line blog.templ:32
+				var_8, err = templ.EscapeAny(post.Title)
+//line blog_templ.go:238
+				if err != nil {
+					return templ.WrapError(err, "blog.templ", 32, 109)
+				}
+				_, err = templBuffer.WriteString(var_8)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</a></div>")
+				if err != nil {
+					return err
+				}
 			}
-			var var_9 string = post.Title
-			_, err = templBuffer.WriteString(templ.EscapeString(var_9))
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</body></html>")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("</a></div>")
-			if err != nil {
-				return err
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
 			}
 		}
-		_, err = templBuffer.WriteString("</body></html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
-		}
 		return err
 	})
 }
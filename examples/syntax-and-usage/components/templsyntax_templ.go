@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 19a4d00912ccbaba1387258808518830fe64a416e0aeea0a8154f05e14011e6e
 
 package main
 
@@ -9,44 +11,65 @@ import "context"
 import "io"
 import "bytes"
 
+//line templsyntax.templ:3
 func list(items []string) templ.Component {
+//line templsyntax_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "main.list"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<ol>")
-		if err != nil {
-			return err
-		}
-		for _, item := range items {
-			_, err = templBuffer.WriteString("<li>")
+		if !templSkip {
+			_, err = templBuffer.WriteString("<ol>")
 			if err != nil {
 				return err
 			}
-			var var_2 string = item
-			_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-			if err != nil {
-				return err
+		}
+//line templsyntax.templ:5
+		for _, item := range items {
+//line templsyntax_templ.go:43
+			if !templSkip {
+				_, err = templBuffer.WriteString("<li>")
+				if err != nil {
+					return err
+				}
+				var var_2 string
+//line templsyntax.templ:6
+				var_2, err = templ.EscapeAny(item)
+//line templsyntax_templ.go:52
+				if err != nil {
+					return templ.WrapError(err, "examples/syntax-and-usage/components/templsyntax.templ", 6, 10)
+				}
+				_, err = templBuffer.WriteString(var_2)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</li>")
+				if err != nil {
+					return err
+				}
 			}
-			_, err = templBuffer.WriteString("</li>")
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</ol>")
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString("</ol>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -0,0 +1,22 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Error is an error that occurred during code generation.
+type Error struct {
+	Err error
+	// Range is the location of the templ, css or script declaration that caused the error.
+	Range parser.Range
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Range.From, e.Err)
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"go/format"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"sort"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// formatCode formats the generated code with gofmt, and moves the target positions of the
+// source map to the formatted code. If the code can't be formatted, e.g. because a Go
+// expression in the template is incomplete while it's being edited, the code and source
+// map are returned unchanged, with the error.
+func formatCode(generated []byte, sm *parser.SourceMap) ([]byte, *parser.SourceMap, error) {
+	formatted, err := format.Source(generated)
+	if err != nil {
+		return generated, sm, err
+	}
+	return formatted, formattedSourceMap(generated, formatted, sm), nil
+}
+
+// formattedSourceMap moves the target positions of the source map from the generated code
+// to the formatted code. gofmt only changes the whitespace between tokens, and removes the
+// occasional trailing comma, so each token of the generated code is matched with a token
+// of the formatted code.
+func formattedSourceMap(generated, formatted []byte, sm *parser.SourceMap) *parser.SourceMap {
+	from, to := goTokens(generated), goTokens(formatted)
+	// Pairs of offsets of matching tokens.
+	type pair struct {
+		from, to int
+	}
+	var pairs []pair
+	match := func(from, to []goToken) {
+		for i, j := 0, 0; i < len(from) && j < len(to); i++ {
+			if from[i].tok == to[j].tok && from[i].lit == to[j].lit {
+				pairs = append(pairs, pair{from: from[i].offset, to: to[j].offset})
+				j++
+			}
+		}
+	}
+	// gofmt also sorts the specs of each import block, so the tokens of an import spec are
+	// matched with the tokens of the spec that has the same name and path, wherever it is.
+	fromOther, fromSpecs := splitImportSpecs(from, importSpecs(generated))
+	toOther, toSpecs := splitImportSpecs(to, importSpecs(formatted))
+	match(fromOther, toOther)
+	for key, specs := range fromSpecs {
+		for i := 0; i < len(specs) && i < len(toSpecs[key]); i++ {
+			match(specs[i], toSpecs[key][i])
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].from < pairs[j].from })
+	fromLines, toLines := lineOffsets(generated), lineOffsets(formatted)
+	return sm.MapTargetPositions(func(p parser.Position) parser.Position {
+		line := int(p.Line)
+		if line >= len(fromLines) {
+			line = len(fromLines) - 1
+		}
+		offset := fromLines[line] + int(p.Col)
+		// Find the last token that starts at or before the offset.
+		i := sort.Search(len(pairs), func(i int) bool { return pairs[i].from > offset }) - 1
+		var mapped int
+		if i >= 0 {
+			mapped = pairs[i].to + offset - pairs[i].from
+			// Whitespace after the token can't move past the start of the next token, unless
+			// the next token is in an import spec that has been moved before it.
+			if i+1 < len(pairs) && pairs[i+1].to > pairs[i].to && mapped > pairs[i+1].to {
+				mapped = pairs[i+1].to
+			}
+		} else if len(pairs) > 0 {
+			mapped = pairs[0].to
+		}
+		line = sort.Search(len(toLines), func(i int) bool { return toLines[i] > mapped }) - 1
+		return parser.NewPosition(int64(mapped), uint32(line), uint32(mapped-toLines[line]))
+	})
+}
+
+type goToken struct {
+	offset int
+	tok    token.Token
+	lit    string
+}
+
+// goTokens returns the tokens of the Go code, including comments, but not the semicolons
+// that are automatically inserted at the end of lines.
+func goTokens(src []byte) (tokens []goToken) {
+	fset := token.NewFileSet()
+	var s scanner.Scanner
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return tokens
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		tokens = append(tokens, goToken{offset: fset.Position(pos).Offset, tok: tok, lit: lit})
+	}
+}
+
+// importSpec is the range of an import spec, e.g. `name "path"`, in Go code.
+type importSpec struct {
+	key      string
+	from, to int
+}
+
+// importSpecs returns the import specs of the Go code, in order.
+func importSpecs(src []byte) (specs []importSpec) {
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "", src, goparser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	for _, s := range f.Imports {
+		key := s.Path.Value
+		if s.Name != nil {
+			key = s.Name.Name + " " + key
+		}
+		specs = append(specs, importSpec{key: key, from: fset.Position(s.Pos()).Offset, to: fset.Position(s.End()).Offset})
+	}
+	return specs
+}
+
+// splitImportSpecs separates the tokens of the import specs from the other tokens. The
+// tokens of each spec are grouped by the spec's key, in order, since an import can appear
+// more than once.
+func splitImportSpecs(tokens []goToken, specs []importSpec) (other []goToken, byKey map[string][][]goToken) {
+	byKey = make(map[string][][]goToken)
+	var s int
+	for i := 0; i < len(tokens); {
+		for s < len(specs) && specs[s].to <= tokens[i].offset {
+			s++
+		}
+		if s == len(specs) || tokens[i].offset < specs[s].from {
+			other = append(other, tokens[i])
+			i++
+			continue
+		}
+		var spec []goToken
+		for ; i < len(tokens) && tokens[i].offset < specs[s].to; i++ {
+			spec = append(spec, tokens[i])
+		}
+		byKey[specs[s].key] = append(byKey[specs[s].key], spec)
+	}
+	return other, byKey
+}
+
+// lineOffsets returns the offset of the start of each line.
+func lineOffsets(src []byte) []int {
+	offsets := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
@@ -0,0 +1,400 @@
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratedCodeIsFormatted(t *testing.T) {
+	fileNames, err := filepath.Glob("test-*/template.templ")
+	if err != nil {
+		t.Fatalf("failed to find templates: %v", err)
+	}
+	if len(fileNames) == 0 {
+		t.Fatal("expected to find templates")
+	}
+	for _, fileName := range fileNames {
+		fileName := fileName
+		t.Run(filepath.Dir(fileName), func(t *testing.T) {
+			tf, err := parser.ParseFile(fileName)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			w := new(bytes.Buffer)
+			if _, err = Generate(tf, w, WithLineDirectives("template.templ"), WithIncludeDir(filepath.Dir(fileName))); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			formatted, err := format.Source(w.Bytes())
+			if err != nil {
+				t.Fatalf("failed to format: %v", err)
+			}
+			if !bytes.Equal(formatted, w.Bytes()) {
+				t.Error("expected the generated code to be formatted")
+			}
+		})
+	}
+}
+
+func TestGeneratedCodeSourceMapMatchesFormattedCode(t *testing.T) {
+	// gofmt adds lines to the switch statement, and changes the whitespace of the expressions.
+	template := `package main
+
+templ render(input any, items []string) {
+	switch v := input.(type) {
+		case int:
+			<span>{ strings.Repeat( "x",v ) }</span>
+		case string:
+			<div class={ map[string]bool{ "a": true } }>{ v }</div>
+	}
+	for _, item := range items {
+		<li>{ item }</li>
+	}
+}
+`
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w, WithLineDirectives("template.templ"))
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	templLines := strings.Split(template, "\n")
+	goLines := strings.Split(w.String(), "\n")
+	var checked int
+	for _, m := range sm.Mappings() {
+		for offset := 0; offset < m.Length; offset++ {
+			src := templLines[m.Source.Line]
+			tgt := goLines[m.Target.Line]
+			srcCol, tgtCol := int(m.Source.Col)+offset, int(m.Target.Col)+offset
+			if srcCol >= len(src) || src[srcCol] == ' ' {
+				continue
+			}
+			if tgtCol >= len(tgt) || src[srcCol] != tgt[tgtCol] {
+				t.Errorf("%d:%d %q is mapped to %d:%d in %q", m.Source.Line, srcCol, src, m.Target.Line, tgtCol, tgt)
+				continue
+			}
+			checked++
+		}
+	}
+	if checked < 50 {
+		t.Errorf("expected at least 50 mapped characters to be checked, got %d", checked)
+	}
+}
+
+func TestGeneratedCodeMapsImportsSortedByGofmt(t *testing.T) {
+	// gofmt sorts the import block, so the specs are in a different order in the Go code.
+	template := `package main
+
+import (
+	"strings"
+	str "strconv"
+	"fmt"
+)
+
+import "os"
+
+templ render() {
+	<p>{ strings.ToUpper(fmt.Sprint(str.Itoa(os.Getpid()))) }</p>
+}
+`
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	templLines := strings.Split(template, "\n")
+	goLines := strings.Split(w.String(), "\n")
+	for line, s := range templLines[:10] {
+		if !strings.Contains(s, `"`) {
+			continue
+		}
+		spec := strings.TrimPrefix(strings.TrimSpace(s), "import ")
+		col := strings.Index(s, spec)
+		for offset := 0; offset < len(spec); offset++ {
+			tgt, ok := sm.TargetPositionFromSource(uint32(line), uint32(col+offset))
+			if !ok {
+				t.Errorf("%d:%d: expected %q to be mapped", line, col+offset, spec)
+				break
+			}
+			if got := goLines[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, spec[offset:]) {
+				t.Errorf("%d:%d: expected %q to be mapped to the same import, got %q", line, col+offset, spec, got)
+				break
+			}
+		}
+	}
+}
+
+func TestGeneratedCodeCompletionPositionsRoundTrip(t *testing.T) {
+	template := "package main\n\ntempl name(s string) {\n\t<p>{ strings.ToUpper( s ) }</p>\n}\n"
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	sm.SetText(template, w.String())
+	templLines := strings.Split(template, "\n")
+	goLines := strings.Split(w.String(), "\n")
+	// Completion is requested after "strings.", and after "( s", which gofmt has moved.
+	for _, before := range []string{"strings.", "strings.ToUpper( s"} {
+		line := uint32(3)
+		col := uint32(strings.Index(template, before) - strings.Index(template, "\t<p>") + len(before))
+		tgt, ok := sm.TargetPositionFromSourceUTF16(line, col)
+		if !ok {
+			t.Fatalf("%q: expected the completion position to be mapped", before)
+		}
+		expected := strings.ReplaceAll(before, "( ", "(")
+		if got := goLines[tgt.Line][:tgt.Col]; !strings.HasSuffix(got, expected) {
+			t.Errorf("%q: expected the Go code before the completion position to end with %q, got %q", before, expected, got)
+		}
+		// The position maps back to the same place, other than whitespace removed by gofmt.
+		src, ok := sm.SourcePositionFromTargetUTF16(tgt.Line, tgt.Col)
+		if !ok || src.Line != line || src.Col < col || strings.TrimSpace(templLines[line][col:src.Col]) != "" {
+			t.Errorf("%q: expected the completion position to map back to %d:%d, got %v", before, line, col, src)
+		}
+	}
+}
+
+func TestGeneratedCodeIsNotFormattedIfItIsInvalid(t *testing.T) {
+	// The expression is incomplete, because it's being edited.
+	tf, err := parser.ParseString("package main\n\ntempl name(s string) {\n\t<p>{ strings. }</p>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err == nil {
+		t.Fatal("expected the generated code to be invalid")
+	}
+	tgt, ok := sm.TargetPositionFromSource(3, 6)
+	if !ok {
+		t.Fatal("expected the expression to be mapped")
+	}
+	if got := strings.Split(w.String(), "\n")[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, "strings.)") {
+		t.Errorf("expected the expression to be mapped, got %q", got)
+	}
+}
+
+func TestGeneratedCodeMapsCompletionPositionsInHeadersAndAttributes(t *testing.T) {
+	// Each template is being edited, so the Go code is often invalid. Completion is requested
+	// after the first instance of before, which must be the end of the Go code before the
+	// mapped position.
+	tests := []struct {
+		name     string
+		template string
+		before   string
+	}{
+		{
+			name:     "for loop header",
+			template: "for _, i := range items. {\n\t\t<li>{ i }</li>\n\t}",
+			before:   "range items.",
+		},
+		{
+			name:     "if condition",
+			template: "if strings. {\n\t\t<p></p>\n\t}",
+			before:   "if strings.",
+		},
+		{
+			name:     "else if condition",
+			template: "if ok {\n\t\t<p></p>\n\t} else if strings. {\n\t\t<p></p>\n\t}",
+			before:   "if strings.",
+		},
+		{
+			name:     "switch header",
+			template: "switch s. {\n\t\tcase \"a\":\n\t\t\t<p></p>\n\t}",
+			before:   "switch s.",
+		},
+		{
+			name:     "case expression",
+			template: "switch s {\n\t\tcase strings.:\n\t\t\t<p></p>\n\t}",
+			before:   "case strings.",
+		},
+		{
+			name:     "class attribute",
+			template: "<div class={ strings.Join(cl }></div>",
+			before:   "strings.Join(cl",
+		},
+		{
+			name:     "attribute",
+			template: "<div title={ strings.ToUpper(ti }></div>",
+			before:   "strings.ToUpper(ti",
+		},
+		{
+			name:     "URL attribute",
+			template: "<a href={ templ.URL(u. }></a>",
+			before:   "templ.URL(u.",
+		},
+		{
+			name:     "boolean attribute",
+			template: "<input disabled?={ s. }/>",
+			before:   "if s.",
+		},
+		{
+			name:     "conditional attribute",
+			template: "<div if s. {\n\t\tclass=\"a\"\n\t}></div>",
+			before:   "if s.",
+		},
+		{
+			name:     "spread attributes",
+			template: "<div { attrs.A... }></div>",
+			before:   "templBuffer, attrs.A",
+		},
+		{
+			name:     "templ element",
+			template: "@Button(strings.)",
+			before:   "Button(strings.",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			template := "package main\n\ntempl name(s string) {\n\t" + tt.template + "\n}\n"
+			tf, err := parser.ParseString(template)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			w := new(bytes.Buffer)
+			sm, err := Generate(tf, w)
+			if err != nil {
+				t.Fatalf("failed to generate code: %v", err)
+			}
+			sm.SetText(template, w.String())
+			// The template is searched for the source position, without the generated prefix,
+			// e.g. "if " for a boolean attribute.
+			marker := tt.before[strings.LastIndex(tt.before, " ")+1:]
+			index := strings.Index(template, marker) + len(marker)
+			line := uint32(strings.Count(template[:index], "\n"))
+			col := uint32(index - strings.LastIndex(template[:index], "\n") - 1)
+			tgt, ok := sm.TargetPositionFromSourceUTF16(line, col)
+			if !ok {
+				t.Fatalf("expected %d:%d to be mapped", line, col)
+			}
+			goLines := strings.Split(w.String(), "\n")
+			if got := goLines[tgt.Line][:tgt.Col]; !strings.HasSuffix(got, tt.before) {
+				t.Errorf("expected the Go code before the completion position to end with %q, got %q", tt.before, got)
+			}
+		})
+	}
+}
+
+func TestGeneratedCodeMapsCtxToTheRenderContext(t *testing.T) {
+	// Each use of ctx in the template must be mapped to an identifier in the Go code that's
+	// declared as the context.Context parameter of a render function, so that gopls knows its
+	// type.
+	template := `package main
+
+templ layout() {
+	<main>{ children... }</main>
+}
+
+templ page(items []string) {
+	<p title={ get(ctx, "title") }>{ get(ctx, "text") }</p>
+	if get(ctx, "user") != "" {
+		for _, item := range list(ctx, items) {
+			<li>{ item }</li>
+		}
+	}
+	switch get(ctx, "locale") {
+		case "fr":
+			<p>Bonjour</p>
+	}
+	<a href={ templ.URL(get(ctx, "home")) } class={ get(ctx, "class") }></a>
+	@layout() {
+		<p>{ get(ctx, "child") }</p>
+		@templ.Raw(get(ctx, "html"))
+	}
+}
+`
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	sm.SetText(template, w.String())
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "template_templ.go", w.String(), 0)
+	if err != nil {
+		t.Fatalf("failed to parse generated code: %v", err)
+	}
+	idents := map[token.Position]*ast.Ident{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == "ctx" {
+			pos := fset.Position(ident.Pos())
+			pos.Offset, pos.Filename = 0, ""
+			idents[pos] = ident
+		}
+		return true
+	})
+
+	var count int
+	for line, s := range strings.Split(template, "\n") {
+		for col := strings.Index(s, "ctx,"); col != -1; col = nextIndex(s, "ctx,", col) {
+			count++
+			tgt, ok := sm.TargetPositionFromSource(uint32(line), uint32(col))
+			if !ok {
+				t.Errorf("%d:%d: expected ctx to be mapped", line, col)
+				continue
+			}
+			ident, ok := idents[token.Position{Line: int(tgt.Line) + 1, Column: int(tgt.Col) + 1}]
+			if !ok {
+				t.Errorf("%d:%d: expected ctx to be mapped to ctx, got %d:%d", line, col, tgt.Line, tgt.Col)
+				continue
+			}
+			if !isContextParam(ident) {
+				t.Errorf("%d:%d: expected ctx to be the context.Context parameter of a render function", line, col)
+			}
+		}
+	}
+	if count != 9 {
+		t.Errorf("expected 9 uses of ctx in the template, got %d", count)
+	}
+}
+
+func nextIndex(s, substr string, after int) int {
+	i := strings.Index(s[after+1:], substr)
+	if i == -1 {
+		return -1
+	}
+	return after + 1 + i
+}
+
+func isContextParam(ident *ast.Ident) bool {
+	if ident.Obj == nil {
+		return false
+	}
+	field, ok := ident.Obj.Decl.(*ast.Field)
+	if !ok {
+		return false
+	}
+	sel, ok := field.Type.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
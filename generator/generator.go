@@ -1,27 +1,162 @@
 package generator
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"html"
 	"io"
+	"os"
+	"path"
+	"path/filepath"
 	"reflect"
 	"runtime/debug"
+	"strconv"
 	"strings"
 
+	"github.com/a-h/templ"
 	"github.com/a-h/templ/parser/v2"
 )
 
-func Generate(template parser.TemplateFile, w io.Writer) (sm *parser.SourceMap, err error) {
+// GenerateOpts configure the code generated by GenerateFile. The zero value generates the
+// code without line directives, minification, a source hash or a build constraint.
+type GenerateOpts struct {
+	// FileName is the templ file name that's written in the errors returned by the generated
+	// code, e.g. "views/home.templ". See templ.Error.
+	FileName string
+	// LineDirectiveFileName, if it's set, is the templ file name written in //line directives
+	// before the Go code that's generated from the template, so that the compiler, stack
+	// traces and debuggers report positions in the templ file rather than the generated file.
+	// It's relative to the directory of the generated file, e.g. "header.templ".
+	LineDirectiveFileName string
+	// LineDirectiveGoFileName is the name of the generated file, which is written in the
+	// //line directives after the Go code of each expression, so that the code that templ
+	// adds around it is reported at its own position. Defaults to the base name of the
+	// LineDirectiveFileName, with the .templ extension replaced by _templ.go.
+	LineDirectiveGoFileName string
+	// Minify removes constant content that doesn't change the rendered HTML from the
+	// generated code: HTML comments, runs of whitespace within text, whitespace between block
+	// elements, and the values of boolean attributes, e.g. disabled="disabled". The contents
+	// of <pre>, <textarea>, <script> and <style> elements, and the output of expressions, are
+	// written as they are.
+	Minify bool
+	// SourceHash, if it's set, is written in the header of the generated code, e.g. a hash of
+	// the templ source and the generation options, so that tools can read it with ReadHeader,
+	// and skip generating the code again if the hash hasn't changed.
+	SourceHash string
+	// BuildTags, if it's set, is written in a //go:build line after the header of the
+	// generated code, e.g. "dev" or "!dev", so that the code is only compiled when the
+	// constraint is satisfied. This allows variants of the code generated from the same
+	// template, with different options, to be built into different binaries.
+	BuildTags string
+	// IncludeDir is the directory that files included with @templ.Include("icons/logo.svg")
+	// are read from, which is usually the directory of the templ file. The contents of the
+	// files are written into the generated code. If it isn't set, templ.Include is an error.
+	IncludeDir string
+}
+
+// GenerateOpt is an option for Generate.
+type GenerateOpt func(opts *GenerateOpts)
+
+// WithLineDirectives sets GenerateOpts.LineDirectiveFileName.
+func WithLineDirectives(fileName string) GenerateOpt {
+	return func(opts *GenerateOpts) {
+		opts.LineDirectiveFileName = fileName
+	}
+}
+
+// WithFileName sets GenerateOpts.FileName.
+func WithFileName(fileName string) GenerateOpt {
+	return func(opts *GenerateOpts) {
+		opts.FileName = fileName
+	}
+}
+
+// WithMinification sets GenerateOpts.Minify.
+func WithMinification() GenerateOpt {
+	return func(opts *GenerateOpts) {
+		opts.Minify = true
+	}
+}
+
+// WithSourceHash sets GenerateOpts.SourceHash.
+func WithSourceHash(hash string) GenerateOpt {
+	return func(opts *GenerateOpts) {
+		opts.SourceHash = hash
+	}
+}
+
+// WithBuildTags sets GenerateOpts.BuildTags.
+func WithBuildTags(constraint string) GenerateOpt {
+	return func(opts *GenerateOpts) {
+		opts.BuildTags = constraint
+	}
+}
+
+// WithIncludeDir sets GenerateOpts.IncludeDir.
+func WithIncludeDir(dir string) GenerateOpt {
+	return func(opts *GenerateOpts) {
+		opts.IncludeDir = dir
+	}
+}
+
+// Generate writes the Go code for the template to w, formatted with gofmt, and returns a
+// source map between the template and the Go code.
+//
+// Deprecated: Use GenerateFile, which also returns the diagnostics found in the template.
+func Generate(template parser.TemplateFile, w io.Writer, opts ...GenerateOpt) (sm *parser.SourceMap, err error) {
+	var o GenerateOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	result, err := GenerateFile(o, template, w)
+	return result.SourceMap, err
+}
+
+// GenerateResult is the result of GenerateFile.
+type GenerateResult struct {
+	// SourceMap maps positions in the template to positions in the generated code.
+	SourceMap *parser.SourceMap
+	// Includes are the files included with templ.Include, relative to the IncludeDir, as
+	// listed in the header of the generated code.
+	Includes []string
+	// Diagnostics are the issues found in the template by parser.Validate. They don't
+	// prevent the code from being generated.
+	Diagnostics []parser.Issue
+	// FormatError is set if the generated code couldn't be formatted with gofmt, because it
+	// isn't valid Go, e.g. because a Go expression in the template is incomplete. The code
+	// is written without being formatted, so that editors can still use it.
+	FormatError error
+}
+
+// GenerateFile writes the Go code for the template to w, formatted with gofmt. If the code
+// can't be generated, the code generated before the error is written, and the result has
+// the source map of that code.
+func GenerateFile(opts GenerateOpts, tf parser.TemplateFile, w io.Writer) (result GenerateResult, err error) {
+	var b bytes.Buffer
 	g := generator{
-		tf:        template,
-		w:         NewRangeWriter(w),
+		tf:        tf,
+		w:         NewRangeWriter(&b),
 		sourceMap: parser.NewSourceMap(),
-	}
-	err = g.generate()
-	sm = g.sourceMap
-	return
+		opts:      opts,
+	}
+	result.Includes = parser.Includes(tf)
+	result.Diagnostics = parser.Validate(tf)
+	if err = g.generate(); err != nil {
+		_, _ = w.Write(b.Bytes())
+		result.SourceMap = g.sourceMap
+		return result, err
+	}
+	var code []byte
+	code, result.SourceMap, result.FormatError = formatCode(b.Bytes(), g.sourceMap)
+	if opts.LineDirectiveFileName != "" {
+		code = setLineResets(code, g.goFileName())
+	}
+	_, err = w.Write(code)
+	return result, err
 }
 
 type generator struct {
@@ -30,12 +165,100 @@ type generator struct {
 	sourceMap   *parser.SourceMap
 	variableID  int
 	childrenVar string
+	// preformatted is greater than zero within elements such as <pre>, where whitespace
+	// is written exactly as it appears in the template.
+	preformatted int
+	opts         GenerateOpts
+	// lineDirective is true if a //line directive has been written for an expression, and
+	// needs to be followed by a reset once its Go code has been written.
+	lineDirective bool
+	// indentLevel is the indent of the nodes that parser.Walk visits.
+	indentLevel int
+	// entered are the nodes that parser.Walk has entered, but not left, so that the end of
+	// each node is written after its children.
+	entered []enteredNode
+	// blockChildrenNames are the names of the variables of the components that render the
+	// children of the templ elements that have been entered.
+	blockChildrenNames []string
+	// err is the error that stopped the nodes from being written.
+	err error
+}
+
+type enteredNode struct {
+	node        parser.Node
+	indentLevel int
+}
+
+// writeLineDirective writes a //line directive, so that the next line of Go code is reported
+// at the line of the expression in the templ file. writeLineReset must be called after the
+// line of Go code, so that the lines that follow are reported at their position in the
+// generated file again. A directive is written before each statement, rather than only where
+// the lines don't already match, because gofmt can add lines to the generated code. The
+// directive is written with the RangeWriter, so that the positions in the source map take the
+// extra line into account.
+func (g *generator) writeLineDirective(indentLevel int, e parser.Expression) (err error) {
+	if g.opts.LineDirectiveFileName == "" || e.Range == (parser.Range{}) {
+		return nil
+	}
+	if g.w.inLiteral {
+		if _, err = g.w.closeLiteral(indentLevel); err != nil {
+			return err
+		}
+	}
+	// Directives must start at the beginning of a line.
+	if g.w.Current.Col != 0 {
+		return nil
+	}
+	if _, err = g.w.Write(fmt.Sprintf("//line %s:%d\n", g.opts.LineDirectiveFileName, e.Range.From.Line+1)); err != nil {
+		return err
+	}
+	g.lineDirective = true
+	return nil
+}
+
+// writeLineReset writes a //line directive that reports the following lines at their own
+// position in the generated file, if a directive was written by writeLineDirective. It's
+// written at the start of the line after the Go code of the expression, so that the code that
+// templ writes after it, e.g. to write HTML and check errors, isn't reported at the lines of
+// the templ file that follow the expression. The line numbers are set by setLineResets once
+// the code has been formatted, since gofmt can add and remove lines.
+func (g *generator) writeLineReset() (err error) {
+	if !g.lineDirective || g.w.Current.Col != 0 {
+		return nil
+	}
+	g.lineDirective = false
+	_, err = g.w.Write(fmt.Sprintf("//line %s:%d\n", g.goFileName(), g.w.Current.Line+2))
+	return err
+}
+
+// goFileName returns the name of the generated file that's written in //line directives.
+func (g *generator) goFileName() string {
+	if g.opts.LineDirectiveGoFileName != "" {
+		return g.opts.LineDirectiveGoFileName
+	}
+	return strings.TrimSuffix(path.Base(filepath.ToSlash(g.opts.LineDirectiveFileName)), ".templ") + "_templ.go"
+}
+
+// setLineResets sets the line number of each //line directive written by writeLineReset to
+// the line that follows it in the code.
+func setLineResets(code []byte, goFileName string) []byte {
+	prefix := "//line " + goFileName + ":"
+	lines := strings.SplitAfter(string(code), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			lines[i] = fmt.Sprintf("%s%d\n", prefix, i+2)
+		}
+	}
+	return []byte(strings.Join(lines, ""))
 }
 
 func (g *generator) generate() (err error) {
 	if err = g.writeCodeGeneratedComment(); err != nil {
 		return
 	}
+	if err = g.writeBuildConstraint(); err != nil {
+		return
+	}
 	if err = g.writePackage(); err != nil {
 		return
 	}
@@ -45,12 +268,12 @@ func (g *generator) generate() (err error) {
 	if err = g.writeTemplateNodes(); err != nil {
 		return
 	}
+	if err = g.writeTemplImportUse(); err != nil {
+		return
+	}
 	return err
 }
 
-// Binary builds set this version string. goreleaser sets the value using Go build ldflags.
-var version string
-
 // Source builds use this value. When installed using `go install github.com/a-h/templ/cmd/templ@latest` the `version` variable is empty, but
 // the debug.ReadBuildInfo return value provides the package version number installed by `go install`
 func goInstallVersion() string {
@@ -61,18 +284,104 @@ func goInstallVersion() string {
 	return info.Main.Version
 }
 
-func getVersion() string {
-	if version != "" {
-		return version
+// Version returns the version of templ that's written in the header of generated code.
+// Binary releases set templ.Version using Go build ldflags.
+func Version() string {
+	if templ.Version != "" {
+		return templ.Version
 	}
 	return goInstallVersion()
 }
 
-func (g *generator) writeCodeGeneratedComment() error {
-	_, err := g.w.Write(fmt.Sprintf("// Code generated by templ@%s DO NOT EDIT.\n\n", getVersion()))
+const (
+	codeGeneratedCommentPrefix = "// Code generated by templ@"
+	versionCommentPrefix       = "// templ: version: "
+	sourceHashCommentPrefix    = "// templ: source hash: "
+	includeCommentPrefix       = "// templ: include: "
+)
+
+func (g *generator) writeCodeGeneratedComment() (err error) {
+	version := Version()
+	if _, err = g.w.Write(fmt.Sprintf("%s%s DO NOT EDIT.\n", codeGeneratedCommentPrefix, version)); err != nil {
+		return err
+	}
+	if _, err = g.w.Write(versionCommentPrefix + version + "\n"); err != nil {
+		return err
+	}
+	if g.opts.SourceHash != "" {
+		if _, err = g.w.Write(sourceHashCommentPrefix + g.opts.SourceHash + "\n"); err != nil {
+			return err
+		}
+	}
+	// The included files are listed, so that tools can tell whether the code is up to date
+	// without parsing the template.
+	for _, fileName := range parser.Includes(g.tf) {
+		if _, err = g.w.Write(includeCommentPrefix + strconv.Quote(fileName) + "\n"); err != nil {
+			return err
+		}
+	}
+	_, err = g.w.Write("\n")
+	return err
+}
+
+// writeBuildConstraint writes the //go:build line. It's written after the header, rather
+// than at the top of the file, so that ReadHeader finds the header on the first line. The
+// constraint is still valid there, because only comments and blank lines precede it.
+func (g *generator) writeBuildConstraint() (err error) {
+	if g.opts.BuildTags == "" {
+		return nil
+	}
+	_, err = g.w.Write("//go:build " + g.opts.BuildTags + "\n\n")
 	return err
 }
 
+// Header is the information written in the header of generated code.
+type Header struct {
+	// Version is the version of templ that generated the code.
+	Version string
+	// SourceHash is the hash written with WithSourceHash, if there is one.
+	SourceHash string
+	// Includes are the files included with templ.Include, relative to the directory of the
+	// templ file.
+	Includes []string
+}
+
+// ReadHeader reads the header of generated code. It returns false if r doesn't start with
+// the header of code generated by templ. Code generated by versions of templ that didn't
+// write the version comment has the version from the "Code generated" comment, if any.
+func ReadHeader(r io.Reader) (h Header, ok bool) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadString('\n')
+	line = strings.TrimSpace(line)
+	// Early versions of templ didn't write the version, e.g. "// Code generated by templ DO NOT EDIT."
+	if err != nil || !strings.HasPrefix(line, "// Code generated by templ") || !strings.HasSuffix(line, " DO NOT EDIT.") {
+		return h, false
+	}
+	if strings.HasPrefix(line, codeGeneratedCommentPrefix) {
+		h.Version = strings.TrimSuffix(strings.TrimPrefix(line, codeGeneratedCommentPrefix), " DO NOT EDIT.")
+	}
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil {
+			return h, true
+		}
+		switch {
+		case strings.HasPrefix(line, versionCommentPrefix):
+			h.Version = strings.TrimSpace(strings.TrimPrefix(line, versionCommentPrefix))
+		case strings.HasPrefix(line, sourceHashCommentPrefix):
+			h.SourceHash = strings.TrimSpace(strings.TrimPrefix(line, sourceHashCommentPrefix))
+		case strings.HasPrefix(line, includeCommentPrefix):
+			fileName, err := strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(line, includeCommentPrefix)))
+			if err != nil {
+				return h, true
+			}
+			h.Includes = append(h.Includes, fileName)
+		default:
+			return h, true
+		}
+	}
+}
+
 func (g *generator) writePackage() error {
 	var r parser.Range
 	var err error
@@ -135,24 +444,53 @@ func (g *generator) writeImports() error {
 	return nil
 }
 
+// writeTemplImportUse uses the templ import in files that only contain Go code, or nothing but
+// a package, because the Go code may not use it. It's written after the Go code, which may
+// contain imports.
+func (g *generator) writeTemplImportUse() (err error) {
+	if g.hasDeclarations() {
+		return nil
+	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	_, err = g.w.Write("\nvar _ templ.Component\n")
+	return err
+}
+
+// hasDeclarations returns true if the file contains templ, css or script declarations.
+func (g *generator) hasDeclarations() bool {
+	for _, n := range g.tf.Nodes {
+		if _, isGo := n.(parser.GoExpression); !isGo {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *generator) writeTemplateNodes() error {
 	for i := 0; i < len(g.tf.Nodes); i++ {
 		switch n := g.tf.Nodes[i].(type) {
 		case parser.GoExpression:
 			if err := g.writeGoExpression(n); err != nil {
-				return err
+				return Error{Err: err, Range: n.Expression.Range}
 			}
 		case parser.HTMLTemplate:
 			if err := g.writeTemplate(i, n); err != nil {
-				return err
+				// Errors within the template, e.g. missing includes, have their own range.
+				var ge Error
+				if errors.As(err, &ge) {
+					return ge
+				}
+				return Error{Err: err, Range: n.Expression.Range}
 			}
 		case parser.CSSTemplate:
 			if err := g.writeCSS(n); err != nil {
-				return err
+				return Error{Err: err, Range: n.Name.Range}
 			}
 		case parser.ScriptTemplate:
 			if err := g.writeScript(n); err != nil {
-				return err
+				return Error{Err: err, Range: n.Name.Range}
 			}
 		default:
 			return fmt.Errorf("unknown node type: %v", reflect.TypeOf(n))
@@ -166,6 +504,12 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 	var err error
 	var indentLevel int
 
+	if err = g.writeDoc(n.Doc); err != nil {
+		return err
+	}
+	if err = g.writeLineDirective(indentLevel, n.Name); err != nil {
+		return err
+	}
 	// func
 	if _, err = g.w.Write("func "); err != nil {
 		return err
@@ -178,6 +522,9 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 	if _, err = g.w.Write("() templ.CSSClass {\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
 	{
 		indentLevel++
 		// var templCSSBuilder strings.Builder
@@ -192,6 +539,9 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 					return err
 				}
 			case parser.ExpressionCSSProperty:
+				if err = g.writeLineDirective(indentLevel, p.Value.Expression); err != nil {
+					return err
+				}
 				// templCSSBuilder.WriteString(templ.SanitizeCSS('name', p.Expression()))
 				if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templCSSBuilder.WriteString(string(templ.SanitizeCSS(`%s`, ", p.Name)); err != nil {
 					return err
@@ -203,6 +553,9 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 				if _, err = g.w.Write(")))\n"); err != nil {
 					return err
 				}
+				if err = g.writeLineReset(); err != nil {
+					return err
+				}
 			default:
 				return fmt.Errorf("unknown CSS property type: %v", reflect.TypeOf(p))
 			}
@@ -239,17 +592,52 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 }
 
 func (g *generator) writeGoExpression(n parser.GoExpression) (err error) {
+	if err = g.writeLineDirective(0, n.Expression); err != nil {
+		return err
+	}
 	r, err := g.w.Write(n.Expression.Value)
 	if err != nil {
 		return err
 	}
 	g.sourceMap.Add(n.Expression, r)
+	// The line directive isn't reset, because the next declaration has its own directive,
+	// and only comments and blank lines are written before it.
 	if _, err = g.w.WriteIndent(0, "\n\n"); err != nil {
 		return err
 	}
 	return err
 }
 
+// writeDoc writes the comments above a templ, css or script declaration, so that they're the
+// doc comments of the generated function.
+func (g *generator) writeDoc(doc string) (err error) {
+	if doc == "" {
+		return nil
+	}
+	_, err = g.w.Write(doc + "\n")
+	return err
+}
+
+func (g *generator) writeTraceRender(indentLevel int, t parser.HTMLTemplate) (err error) {
+	// if templTraceEnd := templ.TraceRender(ctx, "pkg.Name"); templTraceEnd != nil {
+	name := strconv.Quote(componentName(g.tf.Package.Expression.Value, t))
+	if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("if templTraceEnd := templ.TraceRender(ctx, %s); templTraceEnd != nil {\n", name)); err != nil {
+		return err
+	}
+	{
+		indentLevel++
+		// defer func() { templTraceEnd(err) }()
+		if _, err = g.w.WriteIndent(indentLevel, "defer func() { templTraceEnd(err) }()\n"); err != nil {
+			return err
+		}
+		indentLevel--
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		return err
+	}
+	return
+}
+
 func (g *generator) writeTemplBuffer(indentLevel int) (err error) {
 	// templBuffer, templIsBuffer := w.(*bytes.Buffer)
 	if _, err = g.w.WriteIndent(indentLevel, "templBuffer, templIsBuffer := w.(*bytes.Buffer)\n"); err != nil {
@@ -268,11 +656,19 @@ func (g *generator) writeTemplBuffer(indentLevel int) (err error) {
 		if _, err = g.w.WriteIndent(indentLevel, "defer templ.ReleaseBuffer(templBuffer)\n"); err != nil {
 			return err
 		}
+		// ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.WithFlushTarget(ctx, templBuffer, w)\n"); err != nil {
+			return err
+		}
 		indentLevel--
 	}
 	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 		return err
 	}
+	// templSkip := templ.IsOutputSkipped(ctx)
+	if _, err = g.w.WriteIndent(indentLevel, "templSkip := templ.IsOutputSkipped(ctx)\n"); err != nil {
+		return err
+	}
 	return
 }
 
@@ -281,6 +677,12 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	var err error
 	var indentLevel int
 
+	if err = g.writeDoc(t.Doc); err != nil {
+		return err
+	}
+	if err = g.writeLineDirective(indentLevel, t.Expression); err != nil {
+		return err
+	}
 	// func
 	if _, err = g.w.Write("func "); err != nil {
 		return err
@@ -294,6 +696,9 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	if _, err = g.w.Write(" templ.Component {\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
 	indentLevel++
 	// return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
 	if _, err = g.w.WriteIndent(indentLevel, "return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {\n"); err != nil {
@@ -301,6 +706,9 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	}
 	{
 		indentLevel++
+		if err := g.writeTraceRender(indentLevel, t); err != nil {
+			return err
+		}
 		if err := g.writeTemplBuffer(indentLevel); err != nil {
 			return err
 		}
@@ -334,10 +742,13 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 			return err
 		}
 		// Nodes.
-		if err = g.writeNodes(indentLevel, stripWhitespace(t.Children)); err != nil {
+		if err = g.writeNodes(indentLevel, t.Children); err != nil {
 			return err
 		}
 		// Return the buffer.
+		if err = g.w.StartOutput(indentLevel); err != nil {
+			return err
+		}
 		if _, err = g.w.WriteIndent(indentLevel, "if !templIsBuffer {\n"); err != nil {
 			return err
 		}
@@ -352,6 +763,7 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 			return err
 		}
+		g.w.EndOutput()
 		// return nil
 		if _, err = g.w.WriteIndent(indentLevel, "return err\n"); err != nil {
 			return err
@@ -378,102 +790,121 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	return nil
 }
 
-func stripNonCriticalElementWhitespace(input []parser.Node) (output []parser.Node) {
-	// Remove element, whitespace, element
-	// Remove element, whitespace, if etc.
-	// Retain text, whitespace, element
-	// Retain element, whitespace, text
-	for i := range input {
-		var prev, curr, next parser.Node
+// stripNonCriticalWhitespace removes whitespace that doesn't change the rendered output.
+// The remaining whitespace is rendered as a single space, except within preformatted
+// elements, where all whitespace is kept as written.
+func (g *generator) stripNonCriticalWhitespace(input []parser.Node) (output []parser.Node) {
+	if g.preformatted > 0 {
+		return input
+	}
+	if g.opts.Minify {
+		input = minifyNodes(input)
+	}
+	for i, n := range input {
+		ws, isWhitespace := n.(parser.Whitespace)
+		if !isWhitespace {
+			output = append(output, n)
+			continue
+		}
+		var prev, next parser.Node
 		if i > 0 {
 			prev = input[i-1]
 		}
-		curr = input[i]
 		if i < len(input)-1 {
 			next = input[i+1]
 		}
-		_, isWhiteSpace := curr.(parser.Whitespace)
-		if !isWhiteSpace {
-			output = append(output, curr)
-			continue
-		}
-		if prev == nil {
-			// Trim start whitespace.
-			continue
-		}
-		if next == nil {
-			// Trim end whitespace.
-			continue
-		}
-		_, prevIsText := prev.(parser.Text)
-		_, nextIsText := next.(parser.Text)
-		if prevIsText || nextIsText {
-			// Allow whitespace that includes text.
-			output = append(output, curr)
-			continue
-		}
-	}
-	return
-}
-
-func stripWhitespace(input []parser.Node) (output []parser.Node) {
-	for i, n := range input {
-		if _, isWhiteSpace := n.(parser.Whitespace); !isWhiteSpace {
-			output = append(output, input[i])
+		if parser.IsSignificantWhitespace(prev, ws, next) {
+			output = append(output, n)
 		}
 	}
 	return output
 }
 
-func stripLeadingWhitespace(nodes []parser.Node) []parser.Node {
-	for i := 0; i < len(nodes); i++ {
-		n := nodes[i]
-		if _, isWhiteSpace := n.(parser.Whitespace); !isWhiteSpace {
-			return nodes[i:]
-		}
-	}
-	return []parser.Node{}
+// writeNodes writes the nodes of a template, after removing the whitespace that doesn't
+// change the rendered output from them, and from their children.
+func (g *generator) writeNodes(indentLevel int, nodes []parser.Node) error {
+	return g.walkNodes(indentLevel, g.stripWhitespace(nodes))
 }
 
-func stripTrailingWhitespace(nodes []parser.Node) []parser.Node {
-	for i := len(nodes) - 1; i >= 0; i-- {
-		n := nodes[i]
-		if _, isWhiteSpace := n.(parser.Whitespace); !isWhiteSpace {
-			return nodes[0 : i+1]
+// stripWhitespace returns a copy of the nodes, and their children, without the whitespace that
+// doesn't change the rendered output, see stripNonCriticalWhitespace.
+func (g *generator) stripWhitespace(nodes []parser.Node) []parser.Node {
+	stripped := g.stripNonCriticalWhitespace(nodes)
+	output := make([]parser.Node, len(stripped))
+	for i, n := range stripped {
+		e, isElement := n.(parser.Element)
+		if isElement && e.IsPreformatted() {
+			g.preformatted++
+		}
+		output[i] = parser.MapChildren(n, g.stripWhitespace)
+		if isElement && e.IsPreformatted() {
+			g.preformatted--
 		}
 	}
-	return []parser.Node{}
-}
-
-func stripLeadingAndTrailingWhitespace(nodes []parser.Node) []parser.Node {
-	return stripTrailingWhitespace(stripLeadingWhitespace(nodes))
+	return output
 }
 
-func (g *generator) writeNodes(indentLevel int, nodes []parser.Node) error {
+// walkNodes writes the nodes, and their children, with parser.Walk.
+func (g *generator) walkNodes(indentLevel int, nodes []parser.Node) error {
+	parent := g.indentLevel
+	g.indentLevel = indentLevel
 	for _, n := range nodes {
-		if err := g.writeNode(indentLevel, n); err != nil {
-			return err
+		parser.Walk(g, n)
+		if g.err != nil {
+			return g.err
 		}
 	}
+	g.indentLevel = parent
 	return nil
 }
 
-func (g *generator) writeNode(indentLevel int, current parser.Node) (err error) {
+// Visit writes the Go code of the nodes that parser.Walk visits. The end of a node with
+// children, e.g. the closing tag of an element, is written when Walk leaves the node, with
+// Visit(nil), after its children.
+func (g *generator) Visit(node parser.Node) parser.Visitor {
+	if node == nil {
+		e := g.entered[len(g.entered)-1]
+		g.entered = g.entered[:len(g.entered)-1]
+		g.indentLevel = e.indentLevel
+		if g.err == nil {
+			g.err = g.writeNodeEnd(e.indentLevel, e.node)
+		}
+		return nil
+	}
+	if g.err != nil {
+		return nil
+	}
+	var enter bool
+	if enter, g.err = g.writeNode(g.indentLevel, node); g.err != nil || !enter {
+		return nil
+	}
+	g.entered = append(g.entered, enteredNode{node: node, indentLevel: g.indentLevel})
+	g.indentLevel++
+	return g
+}
+
+// writeNode writes the node, or the start of the node if its children are written by
+// walking them, in which case enter is true, and writeNodeEnd writes the end of the node.
+func (g *generator) writeNode(indentLevel int, current parser.Node) (enter bool, err error) {
 	switch n := current.(type) {
 	case parser.DocType:
 		err = g.writeDocType(indentLevel, n)
+	case parser.HTMLComment:
+		err = g.writeHTMLComment(indentLevel, n)
+	case parser.GoComment:
+		// Templ comments aren't rendered.
 	case parser.Element:
-		err = g.writeElement(indentLevel, n)
+		return g.writeElement(indentLevel, n)
 	case parser.ChildrenExpression:
 		err = g.writeChildrenExpression(indentLevel)
 	case parser.RawElement:
 		err = g.writeRawElement(indentLevel, n)
 	case parser.ForExpression:
-		err = g.writeForExpression(indentLevel, n)
+		return true, g.writeForExpression(indentLevel, n)
 	case parser.CallTemplateExpression:
 		err = g.writeCallTemplateExpression(indentLevel, n)
 	case parser.TemplElementExpression:
-		err = g.writeTemplElementExpression(indentLevel, n)
+		return g.writeTemplElementExpression(indentLevel, n)
 	case parser.IfExpression:
 		err = g.writeIfExpression(indentLevel, n)
 	case parser.SwitchExpression:
@@ -483,11 +914,31 @@ func (g *generator) writeNode(indentLevel int, current parser.Node) (err error)
 	case parser.Whitespace:
 		err = g.writeWhitespace(indentLevel, n)
 	case parser.Text:
-		err = g.writeText(indentLevel, n)
+		// Ampersands in text are literal, since character references are parsed separately.
+		err = g.writeText(indentLevel, parser.Text{Value: parser.EscapeStrayAmpersands(n.Value)})
+	case parser.CharacterReference:
+		err = g.writeCharacterReference(indentLevel, n)
 	default:
 		_, err = g.w.Write(fmt.Sprintf("Unhandled type: %v\n", reflect.TypeOf(n)))
 	}
-	return
+	return false, err
+}
+
+// writeNodeEnd writes the end of a node that was entered by writeNode.
+func (g *generator) writeNodeEnd(indentLevel int, current parser.Node) (err error) {
+	switch n := current.(type) {
+	case parser.Element:
+		return g.writeStandardElementEnd(indentLevel, n)
+	case parser.ForExpression:
+		return g.writeForExpressionEnd(indentLevel)
+	case parser.TemplElementExpression:
+		return g.writeBlockTemplElementExpressionEnd(indentLevel, n)
+	}
+	return nil
+}
+
+func (g *generator) writeHTMLComment(indentLevel int, n parser.HTMLComment) (err error) {
+	return g.writeText(indentLevel, parser.Text{Value: "<!--" + n.Contents + "-->"})
 }
 
 func (g *generator) writeDocType(indentLevel int, n parser.DocType) (err error) {
@@ -499,6 +950,9 @@ func (g *generator) writeDocType(indentLevel int, n parser.DocType) (err error)
 
 func (g *generator) writeIfExpression(indentLevel int, n parser.IfExpression) (err error) {
 	var r parser.Range
+	if err = g.writeLineDirective(indentLevel, n.Expression); err != nil {
+		return err
+	}
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
 		return err
@@ -512,14 +966,16 @@ func (g *generator) writeIfExpression(indentLevel int, n parser.IfExpression) (e
 	if _, err = g.w.Write(` {` + "\n"); err != nil {
 		return err
 	}
-	{
-		indentLevel++
-		if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Then)); err != nil {
-			return err
-		}
-		indentLevel--
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	if err = g.walkNodes(indentLevel+1, n.Then); err != nil {
+		return err
 	}
 	for _, elseIf := range n.ElseIfs {
+		if err = g.writeLineDirective(indentLevel, elseIf.Expression); err != nil {
+			return err
+		}
 		// } else if {
 		if _, err = g.w.WriteIndent(indentLevel, `} else if `); err != nil {
 			return err
@@ -533,12 +989,11 @@ func (g *generator) writeIfExpression(indentLevel int, n parser.IfExpression) (e
 		if _, err = g.w.Write(` {` + "\n"); err != nil {
 			return err
 		}
-		{
-			indentLevel++
-			if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(elseIf.Then)); err != nil {
-				return err
-			}
-			indentLevel--
+		if err = g.writeLineReset(); err != nil {
+			return err
+		}
+		if err = g.walkNodes(indentLevel+1, elseIf.Then); err != nil {
+			return err
 		}
 	}
 	if len(n.Else) > 0 {
@@ -546,12 +1001,8 @@ func (g *generator) writeIfExpression(indentLevel int, n parser.IfExpression) (e
 		if _, err = g.w.WriteIndent(indentLevel, `} else {`+"\n"); err != nil {
 			return err
 		}
-		{
-			indentLevel++
-			if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Else)); err != nil {
-				return err
-			}
-			indentLevel--
+		if err = g.walkNodes(indentLevel+1, n.Else); err != nil {
+			return err
 		}
 	}
 	// }
@@ -563,6 +1014,9 @@ func (g *generator) writeIfExpression(indentLevel int, n parser.IfExpression) (e
 
 func (g *generator) writeSwitchExpression(indentLevel int, n parser.SwitchExpression) (err error) {
 	var r parser.Range
+	if err = g.writeLineDirective(indentLevel, n.Expression); err != nil {
+		return err
+	}
 	// switch
 	if _, err = g.w.WriteIndent(indentLevel, `switch `); err != nil {
 		return err
@@ -576,20 +1030,30 @@ func (g *generator) writeSwitchExpression(indentLevel int, n parser.SwitchExpres
 	if _, err = g.w.Write(` {` + "\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
 
 	if len(n.Cases) > 0 {
 		for _, c := range n.Cases {
+			if err = g.writeLineDirective(indentLevel, c.Expression); err != nil {
+				return err
+			}
 			// case x:
 			// default:
 			if r, err = g.w.WriteIndent(indentLevel, c.Expression.Value); err != nil {
 				return err
 			}
 			g.sourceMap.Add(c.Expression, r)
-			indentLevel++
-			if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(c.Children)); err != nil {
+			if _, err = g.w.Write("\n"); err != nil {
+				return err
+			}
+			if err = g.writeLineReset(); err != nil {
+				return err
+			}
+			if err = g.walkNodes(indentLevel+1, c.Children); err != nil {
 				return err
 			}
-			indentLevel--
 		}
 	}
 	// }
@@ -609,27 +1073,41 @@ func (g *generator) writeChildrenExpression(indentLevel int) (err error) {
 	return nil
 }
 
-func (g *generator) writeTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
+func (g *generator) writeTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (enter bool, err error) {
+	fileName, isInclude, err := n.IncludeFileName()
+	if err != nil {
+		return false, Error{Err: err, Range: n.Expression.Range}
+	}
+	if isInclude {
+		return false, g.writeInclude(indentLevel, n.Expression, fileName)
+	}
 	if len(n.Children) == 0 {
-		return g.writeSelfClosingTemplElementExpression(indentLevel, n)
+		return false, g.writeSelfClosingTemplElementExpression(indentLevel, n)
 	}
-	return g.writeBlockTemplElementExpression(indentLevel, n)
+	return true, g.writeBlockTemplElementExpression(indentLevel, n)
 }
 
+// writeBlockTemplElementExpression writes the start of the component that renders the
+// children of the templ element. writeBlockTemplElementExpressionEnd writes the rest.
 func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
-	var r parser.Range
-	childrenName := g.createVariableName()
-	if _, err = g.w.WriteIndent(indentLevel, childrenName+" := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {\n"); err != nil {
+	g.blockChildrenNames = append(g.blockChildrenNames, g.createVariableName())
+	if _, err = g.w.WriteIndent(indentLevel, g.blockChildrenNames[len(g.blockChildrenNames)-1]+" := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {\n"); err != nil {
 		return err
 	}
+	return g.writeTemplBuffer(indentLevel + 1)
+}
+
+// writeBlockTemplElementExpressionEnd writes the end of the component that renders the
+// children of the templ element, and renders the templ element with it.
+func (g *generator) writeBlockTemplElementExpressionEnd(indentLevel int, n parser.TemplElementExpression) (err error) {
+	var r parser.Range
+	childrenName := g.blockChildrenNames[len(g.blockChildrenNames)-1]
+	g.blockChildrenNames = g.blockChildrenNames[:len(g.blockChildrenNames)-1]
 	indentLevel++
-	if err := g.writeTemplBuffer(indentLevel); err != nil {
-		return err
-	}
-	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children)); err != nil {
+	// Return the buffer.
+	if err = g.w.StartOutput(indentLevel); err != nil {
 		return err
 	}
-	// Return the buffer.
 	if _, err = g.w.WriteIndent(indentLevel, "if !templIsBuffer {\n"); err != nil {
 		return err
 	}
@@ -644,6 +1122,7 @@ func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.T
 	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 		return err
 	}
+	g.w.EndOutput()
 	// return nil
 	if _, err = g.w.WriteIndent(indentLevel, "return err\n"); err != nil {
 		return err
@@ -652,6 +1131,9 @@ func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.T
 	if _, err = g.w.WriteIndent(indentLevel, "})\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineDirective(indentLevel, n.Expression); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `err = `); err != nil {
 		return err
 	}
@@ -663,13 +1145,19 @@ func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.T
 	if _, err = g.w.Write(".Render(templ.WithChildren(ctx, " + childrenName + "), templBuffer)\n"); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	if err = g.writeExpressionErrorHandler(indentLevel, n.Expression); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
+	if err = g.writeLineDirective(indentLevel, n.Expression); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `err = `); err != nil {
 		return err
 	}
@@ -683,13 +1171,38 @@ func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n pa
 	if _, err = g.w.Write(".Render(ctx, templBuffer)\n"); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	if err = g.writeExpressionErrorHandler(indentLevel, n.Expression); err != nil {
 		return err
 	}
 	return nil
 }
 
+// writeInclude writes the contents of an included file as constant output. SVG and HTML files
+// are written as they are, and the contents of other files are escaped as text.
+func (g *generator) writeInclude(indentLevel int, e parser.Expression, fileName string) (err error) {
+	if g.opts.IncludeDir == "" {
+		return Error{Err: fmt.Errorf("templ.Include: %q can't be included, because the directory of the templ file isn't known", fileName), Range: e.Range}
+	}
+	contents, err := os.ReadFile(filepath.Join(g.opts.IncludeDir, filepath.FromSlash(fileName)))
+	if err != nil {
+		return Error{Err: fmt.Errorf("templ.Include: %w", err), Range: e.Range}
+	}
+	value := string(contents)
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".svg", ".html", ".htm":
+	default:
+		value = html.EscapeString(value)
+	}
+	return g.writeText(indentLevel, parser.Text{Value: value})
+}
+
 func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTemplateExpression) (err error) {
+	if err = g.writeLineDirective(indentLevel, n.Expression); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `err = `); err != nil {
 		return err
 	}
@@ -703,7 +1216,10 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTe
 	if _, err = g.w.Write(".Render(ctx, templBuffer)\n"); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	if err = g.writeExpressionErrorHandler(indentLevel, n.Expression); err != nil {
 		return err
 	}
 	return nil
@@ -711,6 +1227,9 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTe
 
 func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression) (err error) {
 	var r parser.Range
+	if err = g.writeLineDirective(indentLevel, n.Expression); err != nil {
+		return err
+	}
 	// for
 	if _, err = g.w.WriteIndent(indentLevel, `for `); err != nil {
 		return err
@@ -724,17 +1243,13 @@ func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression)
 	if _, err = g.w.Write(` {` + "\n"); err != nil {
 		return err
 	}
-	// Children.
-	indentLevel++
-	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children)); err != nil {
-		return err
-	}
-	indentLevel--
+	return g.writeLineReset()
+}
+
+func (g *generator) writeForExpressionEnd(indentLevel int) (err error) {
 	// }
-	if _, err = g.w.WriteIndent(indentLevel, `}`+"\n"); err != nil {
-		return err
-	}
-	return nil
+	_, err = g.w.WriteIndent(indentLevel, `}`+"\n")
+	return err
 }
 
 func (g *generator) writeErrorHandler(indentLevel int) (err error) {
@@ -755,11 +1270,28 @@ func (g *generator) writeErrorHandler(indentLevel int) (err error) {
 	return err
 }
 
-func (g *generator) writeElement(indentLevel int, n parser.Element) (err error) {
+// writeExpressionErrorHandler writes an error handler that returns the error with the
+// position of the expression in the templ file, using templ.WrapError.
+func (g *generator) writeExpressionErrorHandler(indentLevel int, e parser.Expression) (err error) {
+	if e.Range == (parser.Range{}) {
+		return g.writeErrorHandler(indentLevel)
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "if err != nil {\n"); err != nil {
+		return err
+	}
+	wrap := fmt.Sprintf("return templ.WrapError(err, %s, %d, %d)\n", strconv.Quote(g.opts.FileName), e.Range.From.Line+1, e.Range.From.Col+1)
+	if _, err = g.w.WriteIndent(indentLevel+1, wrap); err != nil {
+		return err
+	}
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
+func (g *generator) writeElement(indentLevel int, n parser.Element) (enter bool, err error) {
 	if n.IsVoidElement() {
-		return g.writeVoidElement(indentLevel, n)
+		return false, g.writeVoidElement(indentLevel, n)
 	}
-	return g.writeStandardElement(indentLevel, n)
+	return true, g.writeStandardElement(indentLevel, n)
 }
 
 func (g *generator) writeVoidElement(indentLevel int, n parser.Element) (err error) {
@@ -772,8 +1304,11 @@ func (g *generator) writeVoidElement(indentLevel int, n parser.Element) (err err
 			return err
 		}
 	} else {
+		if err = g.w.StartOutput(indentLevel); err != nil {
+			return err
+		}
 		// <style type="text/css"></style>
-		if err = g.writeElementCSS(indentLevel, n); err != nil {
+		if n.Attributes, err = g.writeElementCSS(indentLevel, n); err != nil {
 			return err
 		}
 		// <script type="text/javascript"></script>
@@ -791,6 +1326,7 @@ func (g *generator) writeVoidElement(indentLevel int, n parser.Element) (err err
 		if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
 			return err
 		}
+		g.w.EndOutput()
 	}
 	return err
 }
@@ -802,8 +1338,11 @@ func (g *generator) writeStandardElement(indentLevel int, n parser.Element) (err
 			return err
 		}
 	} else {
+		if err = g.w.StartOutput(indentLevel); err != nil {
+			return err
+		}
 		// <style type="text/css"></style>
-		if err = g.writeElementCSS(indentLevel, n); err != nil {
+		if n.Attributes, err = g.writeElementCSS(indentLevel, n); err != nil {
 			return err
 		}
 		// <script type="text/javascript"></script>
@@ -821,10 +1360,18 @@ func (g *generator) writeStandardElement(indentLevel int, n parser.Element) (err
 		if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
 			return err
 		}
+		g.w.EndOutput()
 	}
 	// Children.
-	if err = g.writeNodes(indentLevel, stripNonCriticalElementWhitespace(n.Children)); err != nil {
-		return err
+	if n.IsPreformatted() {
+		g.preformatted++
+	}
+	return nil
+}
+
+func (g *generator) writeStandardElementEnd(indentLevel int, n parser.Element) (err error) {
+	if n.IsPreformatted() {
+		g.preformatted--
 	}
 	// </div>
 	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`</%s>`, html.EscapeString(n.Name))); err != nil {
@@ -836,6 +1383,9 @@ func (g *generator) writeStandardElement(indentLevel int, n parser.Element) (err
 func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAttribute) (result parser.ExpressionAttribute, ok bool, err error) {
 	var r parser.Range
 	name := html.EscapeString(attr.Name)
+	if name == "style" {
+		return g.writeAttributeStyle(indentLevel, attr)
+	}
 	if name != "class" {
 		ok = false
 		return
@@ -844,6 +1394,9 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAtt
 	// The expression can either be expecting a templ.Classes call, or an expression that returns
 	// var templCSSClassess = []any{
 	classesName := g.createVariableName()
+	if err = g.writeLineDirective(indentLevel, attr.Expression); err != nil {
+		return
+	}
 	if _, err = g.w.WriteIndent(indentLevel, "var "+classesName+" = []any{"); err != nil {
 		return
 	}
@@ -856,6 +1409,9 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAtt
 	if _, err = g.w.Write("}\n"); err != nil {
 		return
 	}
+	if err = g.writeLineReset(); err != nil {
+		return
+	}
 	// Render the CSS before the element if required.
 	// err = templ.RenderCSSItems(ctx, templBuffer, templCSSClassess...)
 	if _, err = g.w.WriteIndent(indentLevel, "err = templ.RenderCSSItems(ctx, templBuffer, "+classesName+"...)\n"); err != nil {
@@ -871,37 +1427,117 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAtt
 	return attr, true, nil
 }
 
-func (g *generator) writeAttributesCSS(indentLevel int, attrs []parser.Attribute) (err error) {
-	for i := 0; i < len(attrs); i++ {
-		if attr, ok := attrs[i].(parser.ExpressionAttribute); ok {
+// writeAttributeStyle writes a variable containing the value of a style attribute expression,
+// and returns a copy of the attribute that renders it with templ.CSSStyles, so that the
+// expression can be a string, templ.SafeCSS, map[string]string or templ.KV("name", "value").
+// String literals are left as they are, because they're escaped when the code is generated.
+func (g *generator) writeAttributeStyle(indentLevel int, attr parser.ExpressionAttribute) (result parser.ExpressionAttribute, ok bool, err error) {
+	if _, isLiteral := stringLiteral(attr.Expression.Value); isLiteral {
+		return attr, false, nil
+	}
+	// var templStyles = []any{
+	stylesName := g.createVariableName()
+	if err = g.writeLineDirective(indentLevel, attr.Expression); err != nil {
+		return
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "var "+stylesName+" = []any{"); err != nil {
+		return
+	}
+	// p.Style()
+	var r parser.Range
+	if r, err = g.w.Write(attr.Expression.Value); err != nil {
+		return
+	}
+	g.sourceMap.Add(attr.Expression, r)
+	// }\n
+	if _, err = g.w.Write("}\n"); err != nil {
+		return
+	}
+	if err = g.writeLineReset(); err != nil {
+		return
+	}
+	// Rewrite the ExpressionAttribute to point at the new variable.
+	attr.Expression = parser.Expression{
+		Value: "templ.CSSStyles(" + stylesName + ").String()",
+	}
+	return attr, true, nil
+}
+
+// writeAttributesCSS renders the CSS classes used by the attributes, and returns a copy of the
+// attributes where the class expressions refer to the rendered classes. The attributes are
+// copied, rather than updated, so that the template can be generated again.
+func (g *generator) writeAttributesCSS(indentLevel int, attrs []parser.Attribute) (result []parser.Attribute, err error) {
+	result = make([]parser.Attribute, len(attrs))
+	copy(result, attrs)
+	for i := 0; i < len(result); i++ {
+		if attr, ok := result[i].(parser.ExpressionAttribute); ok {
 			attr, ok, err = g.writeAttributeCSS(indentLevel, attr)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if ok {
-				attrs[i] = attr
+				result[i] = attr
 			}
 		}
-		if cattr, ok := attrs[i].(parser.ConditionalAttribute); ok {
-			err = g.writeAttributesCSS(indentLevel, cattr.Then)
+		if cattr, ok := result[i].(parser.ConditionalAttribute); ok {
+			cattr.Then, err = g.writeAttributesCSS(indentLevel, cattr.Then)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			err = g.writeAttributesCSS(indentLevel, cattr.Else)
+			cattr.Else, err = g.writeAttributesCSS(indentLevel, cattr.Else)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			attrs[i] = cattr
+			result[i] = cattr
 		}
 	}
-	return nil
+	return result, nil
 }
 
-func (g *generator) writeElementCSS(indentLevel int, n parser.Element) (err error) {
+func (g *generator) writeElementCSS(indentLevel int, n parser.Element) (attrs []parser.Attribute, err error) {
 	return g.writeAttributesCSS(indentLevel, n.Attributes)
 }
 
-func isScriptAttribute(name string) bool {
+// urlAttributes are the attributes that load or navigate to a URL, by element name.
+var urlAttributes = map[string][]string{
+	"a":          {"href"},
+	"area":       {"href"},
+	"base":       {"href"},
+	"link":       {"href"},
+	"form":       {"action"},
+	"button":     {"formaction"},
+	"audio":      {"src"},
+	"embed":      {"src"},
+	"iframe":     {"src"},
+	"img":        {"src"},
+	"input":      {"src", "formaction"},
+	"object":     {"data"},
+	"script":     {"src"},
+	"source":     {"src"},
+	"track":      {"src"},
+	"video":      {"src", "poster"},
+	"blockquote": {"cite"},
+	"del":        {"cite"},
+	"ins":        {"cite"},
+	"q":          {"cite"},
+}
+
+// IsURLAttribute returns true if the attribute value of the element is a URL, and
+// must be a templ.SafeURL.
+func IsURLAttribute(elementName, name string) bool {
+	// Element and attribute names aren't case sensitive, e.g. <A HREF> is the same as <a href>.
+	for _, attr := range urlAttributes[strings.ToLower(elementName)] {
+		if strings.EqualFold(attr, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsScriptAttribute returns true if the attribute is an event handler, e.g. onclick, and
+// must be a templ.ComponentScript.
+func IsScriptAttribute(name string) bool {
+	name = strings.ToLower(name)
 	for _, prefix := range []string{"on", "hx-on:"} {
 		if strings.HasPrefix(name, prefix) {
 			return true
@@ -915,7 +1551,7 @@ func (g *generator) writeElementScript(indentLevel int, n parser.Element) (err e
 	for i := 0; i < len(n.Attributes); i++ {
 		if attr, ok := n.Attributes[i].(parser.ExpressionAttribute); ok {
 			name := html.EscapeString(attr.Name)
-			if isScriptAttribute(name) {
+			if IsScriptAttribute(name) {
 				scriptExpressions = append(scriptExpressions, attr.Expression.Value)
 			}
 		}
@@ -944,7 +1580,11 @@ func (g *generator) writeBoolConstantAttribute(indentLevel int, attr parser.Bool
 
 func (g *generator) writeConstantAttribute(indentLevel int, attr parser.ConstantAttribute) (err error) {
 	name := html.EscapeString(attr.Name)
-	value := html.EscapeString(attr.Value)
+	if g.opts.Minify && isShortenableBooleanAttribute(attr) {
+		_, err = g.w.WriteStringLiteral(indentLevel, " "+name)
+		return err
+	}
+	value := escapeConstantAttributeValue(attr.Value)
 	value = strings.ReplaceAll(value, "\n", "\\n")
 	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(` %s=\"%s\"`, name, value)); err != nil {
 		return err
@@ -952,8 +1592,20 @@ func (g *generator) writeConstantAttribute(indentLevel int, attr parser.Constant
 	return nil
 }
 
+// attributeValueEscaper escapes the same characters as html.EscapeString, other than ampersands.
+var attributeValueEscaper = strings.NewReplacer(`<`, "&lt;", `>`, "&gt;", `'`, "&#39;", `"`, "&#34;")
+
+// escapeConstantAttributeValue escapes the value of a constant attribute, which is written
+// in the template already HTML encoded, so character references are left unchanged.
+func escapeConstantAttributeValue(s string) string {
+	return parser.EscapeStrayAmpersands(attributeValueEscaper.Replace(s))
+}
+
 func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr parser.BoolExpressionAttribute) (err error) {
 	name := html.EscapeString(attr.Name)
+	if err = g.writeLineDirective(indentLevel, attr.Expression); err != nil {
+		return err
+	}
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
 		return err
@@ -968,6 +1620,9 @@ func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr parser.Bo
 	if _, err = g.w.Write(` {` + "\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
 	{
 		indentLevel++
 		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(` %s`, name)); err != nil {
@@ -993,7 +1648,18 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 	if _, err = g.w.WriteStringLiteral(indentLevel, `\"`); err != nil {
 		return err
 	}
-	if elementName == "a" && attr.Name == "href" {
+	// String literals are escaped when the code is generated, e.g. data-contents={ "<tag>" }.
+	if s, ok := stringLiteral(attr.Expression.Value); ok && !IsURLAttribute(elementName, attr.Name) && !IsScriptAttribute(attr.Name) {
+		q := strconv.Quote(html.EscapeString(s))
+		if _, err = g.w.WriteStringLiteral(indentLevel, q[1:len(q)-1]+`\"`); err != nil {
+			return err
+		}
+		return nil
+	}
+	if err = g.writeLineDirective(indentLevel, attr.Expression); err != nil {
+		return err
+	}
+	if IsURLAttribute(elementName, attr.Name) {
 		vn := g.createVariableName()
 		// var vn templ.SafeURL =
 		if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" templ.SafeURL = "); err != nil {
@@ -1008,6 +1674,9 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 		if _, err = g.w.Write("\n"); err != nil {
 			return err
 		}
+		if err = g.writeLineReset(); err != nil {
+			return err
+		}
 		if _, err = g.w.WriteIndent(indentLevel, "_, err = templBuffer.WriteString(templ.EscapeString(string("+vn+")))\n"); err != nil {
 			return err
 		}
@@ -1015,7 +1684,7 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 			return err
 		}
 	} else {
-		if isScriptAttribute(attr.Name) {
+		if IsScriptAttribute(attr.Name) {
 			// It's a JavaScript handler, and requires special handling, because we expect a JavaScript expression.
 			vn := g.createVariableName()
 			// var vn templ.ComponentScript =
@@ -1031,6 +1700,9 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 			if _, err = g.w.Write("\n"); err != nil {
 				return err
 			}
+			if err = g.writeLineReset(); err != nil {
+				return err
+			}
 			if _, err = g.w.WriteIndent(indentLevel, "_, err = templBuffer.WriteString("+vn+".Call)\n"); err != nil {
 				return err
 			}
@@ -1047,11 +1719,18 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 			if r, err = g.w.Write(attr.Expression.Value); err != nil {
 				return err
 			}
-			g.sourceMap.Add(attr.Expression, r)
+			// CSS class expressions are replaced with a call to templ.CSSClasses, which has
+			// no position, because the original expression has already been written.
+			if attr.Expression.Range != (parser.Range{}) {
+				g.sourceMap.Add(attr.Expression, r)
+			}
 			// ))
 			if _, err = g.w.Write("))\n"); err != nil {
 				return err
 			}
+			if err = g.writeLineReset(); err != nil {
+				return err
+			}
 			if err = g.writeErrorHandler(indentLevel); err != nil {
 				return err
 			}
@@ -1065,6 +1744,9 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 }
 
 func (g *generator) writeConditionalAttribute(indentLevel int, elementName string, attr parser.ConditionalAttribute) (err error) {
+	if err = g.writeLineDirective(indentLevel, attr.Expression); err != nil {
+		return err
+	}
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
 		return err
@@ -1079,6 +1761,9 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 	if _, err = g.w.Write(` {` + "\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
 	{
 		indentLevel++
 		if err = g.writeElementAttributes(indentLevel, elementName, attr.Then); err != nil {
@@ -1106,6 +1791,30 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 	return nil
 }
 
+func (g *generator) writeSpreadAttributes(indentLevel int, attr parser.SpreadAttributes) (err error) {
+	if err = g.writeLineDirective(indentLevel, attr.Expression); err != nil {
+		return err
+	}
+	// err = templ.RenderAttributes(ctx, templBuffer,
+	if _, err = g.w.WriteIndent(indentLevel, "err = templ.RenderAttributes(ctx, templBuffer, "); err != nil {
+		return err
+	}
+	// p.Attrs
+	var r parser.Range
+	if r, err = g.w.Write(attr.Expression.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(attr.Expression, r)
+	// )
+	if _, err = g.w.Write(")\n"); err != nil {
+		return err
+	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	return g.writeErrorHandler(indentLevel)
+}
+
 func (g *generator) writeElementAttributes(indentLevel int, name string, attrs []parser.Attribute) (err error) {
 	for i := 0; i < len(attrs); i++ {
 		switch attr := attrs[i].(type) {
@@ -1119,6 +1828,10 @@ func (g *generator) writeElementAttributes(indentLevel int, name string, attrs [
 			err = g.writeExpressionAttribute(indentLevel, name, attr)
 		case parser.ConditionalAttribute:
 			err = g.writeConditionalAttribute(indentLevel, name, attr)
+		case parser.SpreadAttributes:
+			err = g.writeSpreadAttributes(indentLevel, attr)
+		case parser.CommentAttribute:
+			// Templ comments aren't rendered.
 		default:
 			err = fmt.Errorf("unknown attribute type %s", reflect.TypeOf(attrs[i]))
 		}
@@ -1133,6 +1846,9 @@ func (g *generator) writeRawElement(indentLevel int, n parser.RawElement) (err e
 			return err
 		}
 	} else {
+		if err = g.w.StartOutput(indentLevel); err != nil {
+			return err
+		}
 		// <div
 		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s`, html.EscapeString(n.Name))); err != nil {
 			return err
@@ -1144,6 +1860,7 @@ func (g *generator) writeRawElement(indentLevel int, n parser.RawElement) (err e
 		if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
 			return err
 		}
+		g.w.EndOutput()
 	}
 	// Contents.
 	if err = g.writeText(indentLevel, parser.Text{Value: n.Contents}); err != nil {
@@ -1165,19 +1882,45 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 	if strings.TrimSpace(e.Value) == "" {
 		return
 	}
+	// String literals are escaped when the code is generated, e.g. { "<br>" }.
+	if s, ok := stringLiteral(e.Value); ok {
+		q := strconv.Quote(html.EscapeString(s))
+		_, err = g.w.WriteStringLiteral(indentLevel, q[1:len(q)-1])
+		return err
+	}
 	var r parser.Range
+	if err = g.w.StartOutput(indentLevel); err != nil {
+		return err
+	}
+	defer g.w.EndOutput()
 	vn := g.createVariableName()
-	// var vn string = sExpr
-	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string = "); err != nil {
+	// var vn string
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
+		return err
+	}
+	if err = g.writeLineDirective(indentLevel, e); err != nil {
+		return err
+	}
+	// vn, err = templ.EscapeAny(sExpr)
+	if _, err = g.w.WriteIndent(indentLevel, vn+", err = templ.EscapeAny("); err != nil {
 		return err
 	}
 	// p.Name()
-	if r, err = g.w.Write(e.Value + "\n"); err != nil {
+	if r, err = g.w.Write(e.Value); err != nil {
 		return err
 	}
 	g.sourceMap.Add(e, r)
+	if _, err = g.w.Write(")\n"); err != nil {
+		return err
+	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
+	if err = g.writeExpressionErrorHandler(indentLevel, e); err != nil {
+		return err
+	}
 	// _, err = templBuffer.WriteString(vn)
-	if _, err = g.w.WriteIndent(indentLevel, "_, err = templBuffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, "_, err = templBuffer.WriteString("+vn+")\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -1186,28 +1929,44 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 	return nil
 }
 
+// stringLiteral returns the value of the expression if it's a Go string literal.
+func stringLiteral(expr string) (s string, ok bool) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" || (expr[0] != '"' && expr[0] != '`') {
+		return "", false
+	}
+	s, err := strconv.Unquote(expr)
+	return s, err == nil
+}
+
 func (g *generator) writeWhitespace(indentLevel int, n parser.Whitespace) (err error) {
 	if len(n.Value) == 0 {
 		return
 	}
+	value := " "
+	if g.preformatted > 0 {
+		value = n.Value
+	}
 	// _, err = templBuffer.WriteString(` `)
-	if _, err = g.w.WriteStringLiteral(indentLevel, " "); err != nil {
+	if _, err = g.w.WriteStringLiteral(indentLevel, strings.Trim(strconv.Quote(value), `"`)); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (g *generator) writeText(indentLevel int, n parser.Text) (err error) {
-	vn := g.createVariableName()
-	// vn := sExpr
-	if _, err = g.w.WriteIndent(indentLevel, vn+" := "+createGoString(n.Value)+"\n"); err != nil {
-		return err
-	}
-	// _, err = templBuffer.WriteString(vn)
-	if _, err = g.w.WriteIndent(indentLevel, "_, err = templBuffer.WriteString("+vn+")\n"); err != nil {
+	// Text is written as part of the surrounding string literal, so that constant output is
+	// written in a single call.
+	q := strconv.Quote(n.Value)
+	if _, err = g.w.WriteStringLiteral(indentLevel, q[1:len(q)-1]); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	return nil
+}
+
+func (g *generator) writeCharacterReference(indentLevel int, n parser.CharacterReference) (err error) {
+	// _, err = templBuffer.WriteString(`&nbsp;`)
+	if _, err = g.w.WriteStringLiteral(indentLevel, n.Value); err != nil {
 		return err
 	}
 	return nil
@@ -1232,6 +1991,12 @@ func (g *generator) writeScript(t parser.ScriptTemplate) error {
 	var err error
 	var indentLevel int
 
+	if err = g.writeDoc(t.Doc); err != nil {
+		return err
+	}
+	if err = g.writeLineDirective(indentLevel, t.Name); err != nil {
+		return err
+	}
 	// func
 	if _, err = g.w.Write("func "); err != nil {
 		return err
@@ -1253,6 +2018,9 @@ func (g *generator) writeScript(t parser.ScriptTemplate) error {
 	if _, err = g.w.Write(") templ.ComponentScript {\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineReset(); err != nil {
+		return err
+	}
 	indentLevel++
 	// return templ.ComponentScript{
 	if _, err = g.w.WriteIndent(indentLevel, "return templ.ComponentScript{\n"); err != nil {
@@ -2,6 +2,15 @@ package generator
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ/parser/v2"
@@ -33,3 +42,735 @@ func TestGeneratorSourceMap(t *testing.T) {
 		t.Errorf("unexpected target:\n%v", diff)
 	}
 }
+
+func TestGeneratorErrorsIncludeTheTemplateRange(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A() {\n\t<br/>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	// The parser rejects void elements with children, so add them to the parsed template.
+	tmpl := tf.Nodes[0].(parser.HTMLTemplate)
+	tmpl.Children = []parser.Node{
+		parser.Element{Name: "br", Children: []parser.Node{parser.Text{Value: "x"}}},
+	}
+	tf.Nodes[0] = tmpl
+	_, err = Generate(tf, new(bytes.Buffer))
+	var ge Error
+	if !errors.As(err, &ge) {
+		t.Fatalf("expected a generator error, got %v", err)
+	}
+	expected := parser.Range{
+		From: parser.NewPosition(20, 2, 6),
+		To:   parser.NewPosition(23, 2, 9),
+	}
+	if diff := cmp.Diff(expected, ge.Range); diff != "" {
+		t.Errorf("unexpected range:\n%v", diff)
+	}
+}
+
+func TestGeneratorSourceMapIncludesForLoopVariables(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(items []string) {\n\tfor i, item := range items {\n\t\t<div>{ item }</div>\n\t}\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	goLines := strings.Split(w.String(), "\n")
+	for _, src := range []parser.Position{
+		parser.NewPosition(0, 3, 8), // item in the loop header.
+		parser.NewPosition(0, 4, 9), // item in the loop body.
+	} {
+		tgt, ok := sm.TargetPositionFromSource(src.Line, src.Col)
+		if !ok {
+			t.Fatalf("expected %d:%d to be mapped", src.Line, src.Col)
+		}
+		if got := goLines[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, "item") {
+			t.Errorf("expected %d:%d to map to the item variable, got %q", src.Line, src.Col, got)
+		}
+	}
+}
+
+func TestGeneratorSourceMapCoversMultilineExpressions(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(items []string) {\n\t<p>{ strings.Join(\n\t\titems,\n\t\t\", \",\n\t) }</p>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	goLines := strings.Split(w.String(), "\n")
+	for _, src := range []struct {
+		pos      parser.Position
+		expected string
+	}{
+		{pos: parser.NewPosition(0, 3, 6), expected: "strings.Join("},
+		{pos: parser.NewPosition(0, 4, 2), expected: "items,"},
+		{pos: parser.NewPosition(0, 5, 2), expected: `", ",`},
+		{pos: parser.NewPosition(0, 6, 1), expected: ")"},
+	} {
+		tgt, ok := sm.TargetPositionFromSource(src.pos.Line, src.pos.Col)
+		if !ok {
+			t.Fatalf("expected %d:%d to be mapped", src.pos.Line, src.pos.Col)
+		}
+		if got := goLines[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, src.expected) {
+			t.Errorf("expected %d:%d to map to %q, got %q", src.pos.Line, src.pos.Col, src.expected, got)
+		}
+	}
+}
+
+func TestGeneratorSourceMapIncludesBoolExpressionAttributes(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(disabled bool) {\n\t<button disabled?={ disabled }>Submit</button>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	goLines := strings.Split(w.String(), "\n")
+	tgt, ok := sm.TargetPositionFromSource(3, 21)
+	if !ok {
+		t.Fatalf("expected the bool expression to be mapped")
+	}
+	if got := goLines[tgt.Line]; !strings.Contains(got, "if disabled {") || !strings.HasPrefix(got[tgt.Col:], "disabled") {
+		t.Errorf("expected the bool expression to map to the if statement, got %q", got)
+	}
+}
+
+func TestGeneratorCoalescesConstantOutput(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(name string) {\n\t<div class=\"a\" title={ \"<b>\" }>Hello, <!-- comment -->{ name }!</div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	for _, expected := range []string{
+		`_, err = templBuffer.WriteString("<div class=\"a\" title=\"&lt;b&gt;\">Hello, <!-- comment -->")`,
+		`_, err = templBuffer.WriteString("!</div>")`,
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected a single write of %s, got:\n%s", expected, w.String())
+		}
+	}
+	goLines := strings.Split(w.String(), "\n")
+	tgt, ok := sm.TargetPositionFromSource(3, 57)
+	if !ok {
+		t.Fatalf("expected the string expression to be mapped")
+	}
+	if got := goLines[tgt.Line]; !strings.HasPrefix(got[tgt.Col:], "name)") {
+		t.Errorf("expected the string expression to map to the expression, got %q", got)
+	}
+}
+
+func TestGeneratorSourceMapDoesNotMapCSSClassCalls(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A() {\n\t<div class={ \"a\" }></div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	// The package declaration is mapped to the generated package declaration, not the
+	// call to templ.CSSClasses that replaces the class expression.
+	goLines := strings.Split(w.String(), "\n")
+	tgt, ok := sm.TargetPositionFromSource(0, 0)
+	if !ok || !strings.HasPrefix(goLines[tgt.Line], "package main") {
+		t.Errorf("expected the package to be mapped to the package declaration, got %q", goLines[tgt.Line])
+	}
+	if _, ok := sm.TargetPositionFromSource(3, 14); !ok {
+		t.Error("expected the class expression to be mapped")
+	}
+}
+
+func TestIsURLAttribute(t *testing.T) {
+	tests := []struct {
+		element  string
+		attr     string
+		expected bool
+	}{
+		{element: "a", attr: "href", expected: true},
+		{element: "img", attr: "src", expected: true},
+		{element: "form", attr: "action", expected: true},
+		{element: "a", attr: "title", expected: false},
+		{element: "div", attr: "src", expected: false},
+		{element: "turbo-stream", attr: "action", expected: false},
+		{element: "A", attr: "HREF", expected: true},
+		{element: "button", attr: "formaction", expected: true},
+		{element: "input", attr: "formAction", expected: true},
+		{element: "video", attr: "poster", expected: true},
+		{element: "object", attr: "data", expected: true},
+		{element: "blockquote", attr: "cite", expected: true},
+	}
+	for _, tt := range tests {
+		if actual := IsURLAttribute(tt.element, tt.attr); actual != tt.expected {
+			t.Errorf("<%s %s>: expected %v, got %v", tt.element, tt.attr, tt.expected, actual)
+		}
+	}
+}
+
+func TestIsScriptAttribute(t *testing.T) {
+	tests := []struct {
+		attr     string
+		expected bool
+	}{
+		{attr: "onclick", expected: true},
+		{attr: "onClick", expected: true},
+		{attr: "ONMOUSEOVER", expected: true},
+		{attr: "hx-on:click", expected: true},
+		{attr: "HX-ON:click", expected: true},
+		{attr: "data-onclick", expected: false},
+		{attr: "title", expected: false},
+	}
+	for _, tt := range tests {
+		if actual := IsScriptAttribute(tt.attr); actual != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.attr, tt.expected, actual)
+		}
+	}
+}
+
+func TestGeneratorRequiresTypesForURLAndScriptAttributes(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(s string) {\n\t<A HREF={ s } ONCLICK={ s }>{ s }</A>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	// A string isn't assignable to a templ.SafeURL or templ.ComponentScript variable, so the
+	// generated code doesn't compile unless the values are sanitized, or explicitly trusted.
+	for _, expected := range []string{"templ.SafeURL = s", "templ.ComponentScript = s"} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected %q in the generated code, got:\n%s", expected, w.String())
+		}
+	}
+}
+
+func TestGeneratorSourceMapIncludesGoCode(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\nfunc formatDate(t time.Time) string {\n\treturn t.Format(\"2006-01-02\")\n}\n\ntempl A() {\n\t<div></div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	goLines := strings.Split(w.String(), "\n")
+	for _, src := range []parser.Position{
+		parser.NewPosition(0, 2, 5), // formatDate in the function declaration.
+		parser.NewPosition(0, 3, 8), // t.Format in the function body.
+	} {
+		tgt, ok := sm.TargetPositionFromSource(src.Line, src.Col)
+		if !ok {
+			t.Fatalf("expected %d:%d to be mapped", src.Line, src.Col)
+		}
+		expected := strings.Split(tf.Nodes[0].(parser.GoExpression).Expression.Value, "\n")[src.Line-2][src.Col:]
+		if got := goLines[tgt.Line][tgt.Col:]; got != expected {
+			t.Errorf("expected %d:%d to map to %q, got %q", src.Line, src.Col, expected, got)
+		}
+	}
+}
+
+func TestGeneratorWritesImportsVerbatim(t *testing.T) {
+	imports := "import (\n\t_ \"embed\"\n\th \"html\"\n)"
+	tf, err := parser.ParseString("package main\n\n" + imports + "\n\ntempl A() {\n\t<div>{ h.EscapeString(\"a\") }</div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if !strings.Contains(w.String(), imports) {
+		t.Errorf("expected the imports to be written verbatim, got:\n%s", w.String())
+	}
+	if _, err = goparser.ParseFile(token.NewFileSet(), "template_templ.go", w.Bytes(), goparser.ImportsOnly); err != nil {
+		t.Errorf("failed to parse generated imports: %v", err)
+	}
+}
+
+func TestGeneratorSourceMapIncludesTypeParameters(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl list[T fmt.Stringer](items []T) {\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	goLines := strings.Split(w.String(), "\n")
+	// fmt.Stringer in the type parameter constraint.
+	tgt, ok := sm.TargetPositionFromSource(2, 13)
+	if !ok {
+		t.Fatalf("expected the type parameter constraint to be mapped")
+	}
+	if got := goLines[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, "fmt.Stringer") {
+		t.Errorf("expected the constraint to be mapped, got %q", got)
+	}
+}
+
+func TestGeneratorSourceMapHandlesMultiByteCharacters(t *testing.T) {
+	src := "package main\n\ntempl greet(name string) {\n\t<p title={ \"こんにちは \" + name } class=\"😀\">😀 你好 { name + \"👋\" } 🎉 { \"日本\" + name }</p>\n}\n"
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	sm.SetText(src, w.String())
+	srcLines := strings.Split(src, "\n")
+	goLines := strings.Split(w.String(), "\n")
+	const line = 3
+	for _, expr := range []string{`"こんにちは " + name`, `name + "👋"`, `"日本" + name`} {
+		t.Run(expr, func(t *testing.T) {
+			col := uint32(strings.Index(srcLines[line], expr))
+			// Every byte of the expression maps to the Go code and back again.
+			for i := range expr {
+				tgt, ok := sm.TargetPositionFromSource(line, col+uint32(i))
+				if !ok {
+					t.Fatalf("byte %d: expected the expression to be mapped", i)
+				}
+				if got := goLines[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, expr[i:]) {
+					t.Errorf("byte %d: expected the target to start with %q, got %q", i, expr[i:], got)
+				}
+				src, ok := sm.SourcePositionFromTarget(tgt.Line, tgt.Col)
+				if !ok || src.Line != line || src.Col != col+uint32(i) {
+					t.Errorf("byte %d: expected the target to map back to %d:%d, got %v", i, line, col+uint32(i), src)
+				}
+			}
+			// LSP clients use UTF-16 columns.
+			utf16Col := parser.UTF16Col(srcLines[line], col)
+			tgt, ok := sm.TargetPositionFromSourceUTF16(line, utf16Col)
+			if !ok {
+				t.Fatalf("expected the UTF-16 position to be mapped")
+			}
+			goLine := goLines[tgt.Line]
+			if got := goLine[parser.ByteColFromUTF16(goLine, tgt.Col):]; !strings.HasPrefix(got, expr) {
+				t.Errorf("expected the UTF-16 target to start with %q, got %q", expr, got)
+			}
+			src, ok := sm.SourcePositionFromTargetUTF16(tgt.Line, tgt.Col)
+			if !ok || src.Line != line || src.Col != utf16Col {
+				t.Errorf("expected the UTF-16 target to map back to %d:%d, got %v", line, utf16Col, src)
+			}
+		})
+	}
+}
+
+func TestGeneratorLineDirectives(t *testing.T) {
+	src := `package main
+
+import "fmt"
+
+templ list(items []string, class string) {
+	<ul class={ class }>
+		for i, item := range items {
+			if i > 0 {
+				<li>{ fmt.Sprint(i) }: { item }</li>
+			} else if item != "" {
+				@other(item)
+			}
+		}
+	</ul>
+}
+
+templ other(s string) {
+	<span>{ s }</span>
+}
+`
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, err := Generate(tf, w, WithLineDirectives("list.templ"))
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = goparser.ParseFile(token.NewFileSet(), "list_templ.go", w.String(), goparser.AllErrors); err != nil {
+		t.Fatalf("failed to parse the generated code: %v", err)
+	}
+	srcLines := strings.Split(src, "\n")
+	goLines := strings.Split(w.String(), "\n")
+	// reportedLine returns the templ line that the Go line is reported at by the //line directives.
+	reportedLine := func(goLine uint32) (line int, ok bool) {
+		for i := int(goLine) - 1; i >= 0; i-- {
+			if rest, isDirective := strings.CutPrefix(goLines[i], "//line list.templ:"); isDirective {
+				n, _ := strconv.Atoi(rest)
+				return n + int(goLine) - i - 1, true
+			}
+		}
+		return 0, false
+	}
+	var checked int
+	for _, m := range sm.Mappings() {
+		// The source map takes the directives into account.
+		expr := srcLines[m.Source.Line][m.Source.Col:]
+		if len(expr) > m.Length-1 {
+			expr = expr[:m.Length-1]
+		}
+		if got := goLines[m.Target.Line][m.Target.Col:]; !strings.HasPrefix(got, expr) {
+			t.Errorf("expected %q to be mapped, got %q", expr, got)
+		}
+		if m.Source.Line < 4 {
+			// The package and imports aren't within a template.
+			continue
+		}
+		line, ok := reportedLine(m.Target.Line)
+		if !ok || line != int(m.Source.Line)+1 {
+			t.Errorf("expected %q to be reported at line %d, got %d", expr, m.Source.Line+1, line)
+		}
+		checked++
+	}
+	if checked < 8 {
+		t.Errorf("expected at least 8 expressions to be checked, got %d", checked)
+	}
+}
+
+func TestGeneratorDoesNotWriteLineDirectivesByDefault(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(s string) {\n\t<p>{ s }</p>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if strings.Contains(w.String(), "//line") {
+		t.Errorf("expected no line directives, got:\n%s", w.String())
+	}
+}
+
+func TestGeneratorOutputIsDeterministic(t *testing.T) {
+	var fileNames []string
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "node_modules" || d.Name() == ".git") {
+			return filepath.SkipDir
+		}
+		if strings.HasSuffix(path, ".templ") {
+			fileNames = append(fileNames, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to find templates: %v", err)
+	}
+	if len(fileNames) < 40 {
+		t.Fatalf("expected to find the templates in the repository, found %d", len(fileNames))
+	}
+	header := fmt.Sprintf("// Code generated by templ@%s DO NOT EDIT.\n// templ: version: %s\n", Version(), Version())
+	for _, fileName := range fileNames {
+		tf, err := parser.ParseFile(fileName)
+		if err != nil {
+			t.Fatalf("%s: failed to parse template: %v", fileName, err)
+		}
+		includeDir := WithIncludeDir(filepath.Dir(fileName))
+		first := new(bytes.Buffer)
+		if _, err = Generate(tf, first, includeDir); err != nil {
+			t.Fatalf("%s: failed to generate: %v", fileName, err)
+		}
+		if !strings.HasPrefix(first.String(), header) {
+			t.Errorf("%s: expected the code to start with %q", fileName, header)
+		}
+		// Generating the same template again, or the template parsed again, gives the same code.
+		second := new(bytes.Buffer)
+		if _, err = Generate(tf, second, includeDir); err != nil {
+			t.Fatalf("%s: failed to generate: %v", fileName, err)
+		}
+		if tf, err = parser.ParseFile(fileName); err != nil {
+			t.Fatalf("%s: failed to parse template: %v", fileName, err)
+		}
+		third := new(bytes.Buffer)
+		if _, err = Generate(tf, third, includeDir); err != nil {
+			t.Fatalf("%s: failed to generate: %v", fileName, err)
+		}
+		if first.String() != second.String() || first.String() != third.String() {
+			t.Errorf("%s: expected the generated code to be the same each time", fileName)
+		}
+	}
+}
+
+func TestGeneratorHeader(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A() {\n\t<div></div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	t.Run("the version and hash can be read from the generated code", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err = Generate(tf, w, WithSourceHash("abc123")); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = goparser.ParseFile(token.NewFileSet(), "", w.Bytes(), goparser.PackageClauseOnly); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		h, ok := ReadHeader(w)
+		if !ok {
+			t.Fatal("expected the header to be read")
+		}
+		if diff := cmp.Diff(Header{Version: Version(), SourceHash: "abc123"}, h); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the header is read from code with a build constraint", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err = Generate(tf, w, WithSourceHash("abc123"), WithBuildTags("dev && !prod")); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if !strings.Contains(w.String(), "\n\n//go:build dev && !prod\n\npackage main\n") {
+			t.Errorf("expected the build constraint before the package clause, got:\n%s", w.String())
+		}
+		if _, err = goparser.ParseFile(token.NewFileSet(), "", w.Bytes(), goparser.PackageClauseOnly); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		if h, ok := ReadHeader(w); !ok || h.SourceHash != "abc123" {
+			t.Errorf("expected the header to be read, got %#v, %v", h, ok)
+		}
+	})
+	t.Run("code generated without a hash doesn't have one", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err = Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if h, ok := ReadHeader(w); !ok || h.SourceHash != "" {
+			t.Errorf("expected no hash, got %#v, %v", h, ok)
+		}
+	})
+	t.Run("the version of code generated before the version comment is read from the first line", func(t *testing.T) {
+		h, ok := ReadHeader(strings.NewReader("// Code generated by templ@v0.2.282 DO NOT EDIT.\n\npackage main\n"))
+		if !ok {
+			t.Fatal("expected the header to be read")
+		}
+		if diff := cmp.Diff(Header{Version: "v0.2.282"}, h); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("code generated by early versions of templ doesn't have a version", func(t *testing.T) {
+		h, ok := ReadHeader(strings.NewReader("// Code generated by templ DO NOT EDIT.\n\npackage main\n"))
+		if !ok || h.Version != "" {
+			t.Errorf("expected the header to be read without a version, got %#v, %v", h, ok)
+		}
+	})
+	t.Run("files that weren't generated by templ don't have a header", func(t *testing.T) {
+		if h, ok := ReadHeader(strings.NewReader("// templ: source hash: abc123\n\npackage main\n")); ok {
+			t.Errorf("expected no header, got %#v", h)
+		}
+	})
+	t.Run("the included files are listed in the header", func(t *testing.T) {
+		tf, err := parser.ParseString("package main\n\ntempl A() {\n\t@templ.Include(\"icons/logo.svg\")\n\t@templ.Include(\"notice.txt\")\n\t@templ.Include(\"icons/logo.svg\")\n}\n")
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		w := new(bytes.Buffer)
+		if _, err = Generate(tf, w, WithSourceHash("abc123"), WithIncludeDir("test-include")); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		h, ok := ReadHeader(w)
+		if !ok {
+			t.Fatal("expected the header to be read")
+		}
+		if diff := cmp.Diff([]string{"icons/logo.svg", "notice.txt"}, h.Includes); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestGeneratorIncludeErrorsIncludeTheTemplateRange(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A() {\n\t@templ.Include(\"missing.svg\")\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := parser.Range{
+		From: parser.NewPosition(28, 3, 2),
+		To:   parser.NewPosition(56, 3, 30),
+	}
+	tests := []struct {
+		name string
+		opts []GenerateOpt
+	}{
+		{
+			name: "missing files",
+			opts: []GenerateOpt{WithIncludeDir("test-include")},
+		},
+		{
+			name: "files can't be included if the directory isn't known",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tf, new(bytes.Buffer), tt.opts...)
+			var ge Error
+			if !errors.As(err, &ge) {
+				t.Fatalf("expected a generator error, got %v", err)
+			}
+			if !strings.HasPrefix(ge.Err.Error(), "templ.Include: ") {
+				t.Errorf("unexpected error: %v", ge.Err)
+			}
+			if diff := cmp.Diff(expected, ge.Range); diff != "" {
+				t.Errorf("unexpected range:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateFile(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A() {\n\t<!-- comment -->\n\t<img src=\"a.png\"/>\n\t@templ.Include(\"icons/logo.svg\")\n\t{ fmt.Sprint(1) }\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	opts := GenerateOpts{
+		FileName:              "views/a.templ",
+		LineDirectiveFileName: "a.templ",
+		Minify:                true,
+		SourceHash:            "abc123",
+		BuildTags:             "dev",
+		IncludeDir:            "test-include",
+	}
+	w := new(bytes.Buffer)
+	result, err := GenerateFile(opts, tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	code := w.String()
+
+	t.Run("the options are applied", func(t *testing.T) {
+		h, ok := ReadHeader(strings.NewReader(code))
+		if !ok || h.SourceHash != "abc123" {
+			t.Errorf("expected the source hash in the header, got %#v, %v", h, ok)
+		}
+		for _, expected := range []string{"//go:build dev\n", "//line a.templ:7\n", `"views/a.templ", 7, 4)`, "<svg "} {
+			if !strings.Contains(code, expected) {
+				t.Errorf("expected the code to contain %q:\n%s", expected, code)
+			}
+		}
+		if strings.Contains(code, "comment") {
+			t.Errorf("expected the comment to be minified:\n%s", code)
+		}
+	})
+	t.Run("the result has the source map", func(t *testing.T) {
+		tgt, ok := result.SourceMap.TargetPositionFromSource(6, 3)
+		if !ok {
+			t.Fatal("expected the expression to be mapped")
+		}
+		if got := strings.Split(code, "\n")[tgt.Line][tgt.Col:]; !strings.HasPrefix(got, "fmt.Sprint(1)") {
+			t.Errorf("expected the expression to be mapped, got %q", got)
+		}
+	})
+	t.Run("the result has the included files", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"icons/logo.svg"}, result.Includes); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the result has the diagnostics", func(t *testing.T) {
+		var checks []string
+		for _, issue := range result.Diagnostics {
+			checks = append(checks, issue.Check)
+		}
+		if diff := cmp.Diff([]string{parser.CheckNoAlt}, checks); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("code is formatted", func(t *testing.T) {
+		if result.FormatError != nil {
+			t.Errorf("unexpected format error: %v", result.FormatError)
+		}
+	})
+	t.Run("Generate writes the same code with the equivalent options", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		_, err := Generate(tf, w,
+			WithFileName(opts.FileName),
+			WithLineDirectives(opts.LineDirectiveFileName),
+			WithMinification(),
+			WithSourceHash(opts.SourceHash),
+			WithBuildTags(opts.BuildTags),
+			WithIncludeDir(opts.IncludeDir),
+		)
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if diff := cmp.Diff(code, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestGenerateFileReportsCodeThatCantBeFormatted(t *testing.T) {
+	// The expression is incomplete, because it's being edited.
+	tf, err := parser.ParseString("package main\n\ntempl name(s string) {\n\t<p>{ strings. }</p>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	result, err := GenerateFile(GenerateOpts{}, tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if result.FormatError == nil {
+		t.Error("expected a format error")
+	}
+	if result.SourceMap == nil || w.Len() == 0 {
+		t.Error("expected the code to be written, with its source map")
+	}
+}
+
+func TestGeneratorWritesDocComments(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+// page renders the page.
+templ page() {
+	<div class={ red() }></div>
+}
+
+/* red is a class. */
+css red() {
+	color: red;
+}
+
+// greet is a script.
+script greet() {
+	alert("hello");
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err = GenerateFile(GenerateOpts{LineDirectiveFileName: "a.templ"}, tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	f, err := goparser.ParseFile(token.NewFileSet(), "", w.String(), goparser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse the generated code: %v", err)
+	}
+	actual := make(map[string]string)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			actual[fn.Name.Name] = fn.Doc.Text()
+		}
+	}
+	expected := map[string]string{
+		"page":  "page renders the page.\n",
+		"red":   " red is a class.\n",
+		"greet": "greet is a script.\n",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -14,6 +14,12 @@ import (
 )
 
 func Diff(input templ.Component, expected string) (diff string, err error) {
+	return DiffCtx(context.Background(), input, expected)
+}
+
+// DiffCtx renders the component with the context, and compares the formatted output with
+// the expected HTML.
+func DiffCtx(ctx context.Context, input templ.Component, expected string) (diff string, err error) {
 	var wg sync.WaitGroup
 	wg.Add(2)
 
@@ -42,7 +48,7 @@ func Diff(input templ.Component, expected string) (diff string, err error) {
 	}()
 
 	// Render the component.
-	err = input.Render(context.Background(), w)
+	err = input.Render(ctx, w)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to render component: %w", err))
 	}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// minifyNodes removes the constant content that doesn't change how the nodes are rendered:
+// HTML comments, runs of whitespace within text, and whitespace between block elements.
+// Expressions, and the contents of elements such as <pre>, <script> and <style>, are
+// never changed.
+func minifyNodes(input []parser.Node) (output []parser.Node) {
+	// Remove comments, and join the whitespace on either side of them.
+	nodes := make([]parser.Node, 0, len(input))
+	for _, n := range input {
+		switch n := n.(type) {
+		case parser.HTMLComment:
+			if isConditionalComment(n) {
+				nodes = append(nodes, n)
+			}
+			continue
+		case parser.Whitespace:
+			if len(nodes) > 0 {
+				if prev, ok := nodes[len(nodes)-1].(parser.Whitespace); ok {
+					nodes[len(nodes)-1] = parser.Whitespace{Value: prev.Value + n.Value}
+					continue
+				}
+			}
+		case parser.Text:
+			n.Value = collapseWhitespace(n.Value)
+			nodes = append(nodes, n)
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	for i, n := range nodes {
+		if _, isWhitespace := n.(parser.Whitespace); !isWhitespace {
+			output = append(output, n)
+			continue
+		}
+		var prev, next parser.Node
+		if i > 0 {
+			prev = nodes[i-1]
+		}
+		if i < len(nodes)-1 {
+			next = nodes[i+1]
+		}
+		if isMinifiableWhitespace(prev, next) {
+			continue
+		}
+		output = append(output, n)
+	}
+	return output
+}
+
+// isMinifiableWhitespace returns true if whitespace between prev and next isn't rendered,
+// because it's between two block elements, or the text next to it already has a space.
+func isMinifiableWhitespace(prev, next parser.Node) bool {
+	if isMinifiableBlockElement(prev) && isMinifiableBlockElement(next) {
+		return true
+	}
+	if t, ok := prev.(parser.Text); ok && strings.HasSuffix(t.Value, " ") {
+		return true
+	}
+	if t, ok := next.(parser.Text); ok && strings.HasPrefix(t.Value, " ") {
+		return true
+	}
+	return false
+}
+
+// minifiableBlockElements are displayed as blocks by default, so the whitespace between
+// them isn't rendered. Replaced elements, such as <video>, are inline, and aren't included.
+var minifiableBlockElements = map[string]struct{}{
+	"address": {}, "article": {}, "aside": {}, "blockquote": {}, "body": {}, "dd": {}, "details": {}, "dialog": {}, "div": {}, "dl": {}, "dt": {}, "fieldset": {}, "figcaption": {}, "figure": {}, "footer": {}, "form": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}, "head": {}, "header": {}, "hgroup": {}, "hr": {}, "html": {}, "li": {}, "link": {}, "main": {}, "meta": {}, "nav": {}, "ol": {}, "p": {}, "section": {}, "summary": {}, "table": {}, "tbody": {}, "td": {}, "tfoot": {}, "th": {}, "thead": {}, "title": {}, "tr": {}, "ul": {},
+}
+
+func isMinifiableBlockElement(n parser.Node) bool {
+	e, ok := n.(parser.Element)
+	if !ok {
+		return false
+	}
+	_, ok = minifiableBlockElements[e.Name]
+	return ok
+}
+
+// isConditionalComment returns true for Internet Explorer conditional comments, e.g.
+// <!--[if IE]> and <![endif]-->, which are kept, because they contain markup.
+func isConditionalComment(n parser.HTMLComment) bool {
+	return strings.Contains(n.Contents, "[if ") || strings.Contains(n.Contents, "[endif]")
+}
+
+// collapseWhitespace replaces each run of HTML whitespace in s with a single space.
+func collapseWhitespace(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	var inWhitespace bool
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '\r', '\f':
+			if !inWhitespace {
+				sb.WriteRune(' ')
+			}
+			inWhitespace = true
+		default:
+			sb.WriteRune(r)
+			inWhitespace = false
+		}
+	}
+	return sb.String()
+}
+
+// booleanAttributes are true when they're present, whatever their value, so disabled="disabled"
+// can be written as disabled.
+var booleanAttributes = map[string]struct{}{
+	"allowfullscreen": {}, "async": {}, "autofocus": {}, "autoplay": {}, "checked": {}, "controls": {}, "default": {}, "defer": {}, "disabled": {}, "formnovalidate": {}, "hidden": {}, "inert": {}, "ismap": {}, "itemscope": {}, "loop": {}, "multiple": {}, "muted": {}, "nomodule": {}, "novalidate": {}, "open": {}, "playsinline": {}, "readonly": {}, "required": {}, "reversed": {}, "selected": {},
+}
+
+// isShortenableBooleanAttribute returns true if the attribute is a boolean attribute with
+// an empty value, or a value that's the same as its name, e.g. disabled="disabled".
+func isShortenableBooleanAttribute(attr parser.ConstantAttribute) bool {
+	name := strings.ToLower(attr.Name)
+	if _, ok := booleanAttributes[name]; !ok {
+		return false
+	}
+	return attr.Value == "" || strings.EqualFold(attr.Value, name)
+}
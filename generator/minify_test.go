@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"golang.org/x/net/html"
+)
+
+const minifyTestTemplate = `package main
+
+templ Page() {
+	<!DOCTYPE html>
+	<html lang="en">
+		<!-- The page. -->
+		<head>
+			<title>Minification</title>
+			<!--[if IE]><link rel="stylesheet" href="/ie.css"/><![endif]-->
+			<style>
+				body   { color: red; }
+			</style>
+		</head>
+		<body>
+			<div class="a"></div> <div class="b"></div>
+			<p>Some    text,   with <b>bold</b> <i>and italic</i>  words. <!-- Between words. --> And more.</p>
+			<ul> <li>One</li> <li>Two</li> </ul>
+			<pre>
+  indented   <!-- kept -->
+    text
+</pre>
+			<textarea>  a   b  </textarea>
+			<form>
+				<input type="checkbox" checked="checked" disabled="" required="required" value="value"/>
+				<option selected="no">Selected</option>
+			</form>
+			<script type="text/javascript">
+				var  a = "  <!-- not a comment -->  ";
+			</script>
+		</body>
+	</html>
+}
+`
+
+func TestMinifiedOutputHasTheSameDOM(t *testing.T) {
+	tf, err := parser.ParseString(minifyTestTemplate)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	unminified := renderConstantOutput(t, tf)
+	minified := renderConstantOutput(t, tf, WithMinification())
+	if len(minified) >= len(unminified) {
+		t.Errorf("expected the minified output to be smaller, got %d bytes, unminified %d bytes", len(minified), len(unminified))
+	}
+	if expected, actual := normalizeDOM(t, unminified), normalizeDOM(t, minified); expected != actual {
+		t.Errorf("expected the same DOM\nunminified: %s\nminified:   %s\nunminified DOM:\n%s\nminified DOM:\n%s", unminified, minified, expected, actual)
+	}
+	for _, expected := range []string{
+		"<pre>\n  indented   <!-- kept -->\n    text\n</pre>",
+		"<textarea>  a   b  </textarea>",
+		"var  a = \"  <!-- not a comment -->  \";",
+		"body   { color: red; }",
+		"<!--[if IE]><link rel=\"stylesheet\" href=\"/ie.css\"/><![endif]-->",
+		"<p>Some text, with <b>bold</b> <i>and italic</i> words. And more.</p>",
+		"<div class=\"a\"></div><div class=\"b\"></div>",
+		"<ul><li>One</li><li>Two</li></ul>",
+		"<input type=\"checkbox\" checked disabled required value=\"value\">",
+		"<option selected=\"no\">",
+	} {
+		if !strings.Contains(minified, expected) {
+			t.Errorf("expected the minified output to contain %q, got:\n%s", expected, minified)
+		}
+	}
+	for _, unexpected := range []string{"The page.", "Between words."} {
+		if strings.Contains(minified, unexpected) {
+			t.Errorf("expected the comment %q to be removed, got:\n%s", unexpected, minified)
+		}
+	}
+}
+
+func TestMinificationDoesNotChangeExpressions(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl A(s string) {\n\t<div>{ s }   <!-- comment --> { \"a   b\" }</div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err = Generate(tf, w, WithMinification()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	for _, expected := range []string{
+		"templ.EscapeAny(s)",
+		`_, err = templBuffer.WriteString(" a   b</div>")`,
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected %s in the generated code, got:\n%s", expected, w.String())
+		}
+	}
+}
+
+// renderConstantOutput generates the code for a template that doesn't contain any expressions,
+// and returns the HTML it renders, by joining the string literals written to the buffer.
+func renderConstantOutput(t *testing.T, tf parser.TemplateFile, opts ...GenerateOpt) string {
+	t.Helper()
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, opts...); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	f, err := goparser.ParseFile(token.NewFileSet(), "template_templ.go", w.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("failed to parse generated code: %v", err)
+	}
+	var sb strings.Builder
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "WriteString" || sel.X.(*ast.Ident).Name != "templBuffer" {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok {
+			t.Fatalf("expected only constant output, got a call to WriteString with %T", call.Args[0])
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			t.Fatalf("failed to unquote %s: %v", lit.Value, err)
+		}
+		sb.WriteString(s)
+		return false
+	})
+	return sb.String()
+}
+
+// normalizeDOM parses the HTML and writes its elements and text, without comments or
+// whitespace that isn't rendered, and with the values of boolean attributes removed.
+func normalizeDOM(t *testing.T, s string) string {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+	var tokens []string
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, "text:"+collapseWhitespace(text.String()))
+			text.Reset()
+		}
+	}
+	var walk func(n *html.Node, preserve bool)
+	walk = func(n *html.Node, preserve bool) {
+		switch n.Type {
+		case html.TextNode:
+			if preserve {
+				flush()
+				tokens = append(tokens, "raw:"+n.Data)
+				return
+			}
+			text.WriteString(n.Data)
+			return
+		case html.CommentNode:
+			return
+		case html.DoctypeNode:
+			flush()
+			tokens = append(tokens, "<!doctype "+n.Data+">")
+			return
+		case html.ElementNode:
+			flush()
+			attrs := make([]string, len(n.Attr))
+			for i, a := range n.Attr {
+				if _, ok := booleanAttributes[a.Key]; ok && (a.Val == "" || a.Val == a.Key) {
+					a.Val = ""
+				}
+				attrs[i] = a.Key + "=" + strconv.Quote(a.Val)
+			}
+			sort.Strings(attrs)
+			tokens = append(tokens, "<"+n.Data+" "+strings.Join(attrs, " ")+">")
+			switch n.Data {
+			case "pre", "textarea", "script", "style":
+				preserve = true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, preserve)
+		}
+		if n.Type == html.ElementNode {
+			flush()
+			tokens = append(tokens, "</"+n.Data+">")
+		}
+	}
+	walk(doc, false)
+	flush()
+	// Whitespace next to the start or end of a block element isn't rendered.
+	isBlock := func(i int) bool {
+		if i < 0 || i >= len(tokens) || !strings.HasPrefix(tokens[i], "<") {
+			return false
+		}
+		name := strings.Trim(strings.Fields(tokens[i])[0], "</>")
+		_, ok := minifiableBlockElements[name]
+		return ok
+	}
+	var output []string
+	for i, tok := range tokens {
+		if strings.HasPrefix(tok, "text:") {
+			value := strings.TrimPrefix(tok, "text:")
+			if isBlock(i - 1) {
+				value = strings.TrimLeft(value, " ")
+			}
+			if isBlock(i + 1) {
+				value = strings.TrimRight(value, " ")
+			}
+			if value == "" {
+				continue
+			}
+			tok = "text:" + value
+		}
+		output = append(output, tok)
+	}
+	return strings.Join(output, "\n")
+}
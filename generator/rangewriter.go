@@ -16,7 +16,12 @@ func NewRangeWriter(w io.Writer) *RangeWriter {
 type RangeWriter struct {
 	Current   parser.Position
 	inLiteral bool
-	w         io.Writer
+	// inOutput is true while the code in an if !templSkip { ... } statement is written, so
+	// that the output of a component isn't evaluated or written when it's skipped.
+	inOutput bool
+	// outputs is the number of StartOutput calls that haven't been ended.
+	outputs int
+	w       io.Writer
 }
 
 func (rw *RangeWriter) closeLiteral(indent int) (r parser.Range, err error) {
@@ -29,12 +34,47 @@ func (rw *RangeWriter) closeLiteral(indent int) (r parser.Range, err error) {
 	return
 }
 
-func (rw *RangeWriter) WriteIndent(level int, s string) (r parser.Range, err error) {
+// close closes the string literal, and the if !templSkip statement, unless output is being
+// written.
+func (rw *RangeWriter) close(level int) (err error) {
 	if rw.inLiteral {
 		if _, err = rw.closeLiteral(level); err != nil {
 			return
 		}
 	}
+	if rw.inOutput && rw.outputs == 0 {
+		rw.inOutput = false
+		_, err = rw.writeIndent(level, "}\n")
+	}
+	return
+}
+
+// StartOutput starts code that evaluates or writes output, so that it's skipped when the
+// output of the component is skipped. Consecutive output is written within the same
+// if !templSkip statement.
+func (rw *RangeWriter) StartOutput(level int) (err error) {
+	rw.outputs++
+	if rw.inOutput {
+		return nil
+	}
+	rw.inOutput = true
+	_, err = rw.writeIndent(level, "if !templSkip {\n")
+	return err
+}
+
+// EndOutput ends the code started by StartOutput.
+func (rw *RangeWriter) EndOutput() {
+	rw.outputs--
+}
+
+func (rw *RangeWriter) WriteIndent(level int, s string) (r parser.Range, err error) {
+	if err = rw.close(level); err != nil {
+		return
+	}
+	return rw.writeIndent(level, s)
+}
+
+func (rw *RangeWriter) writeIndent(level int, s string) (r parser.Range, err error) {
 	_, err = rw.write(strings.Repeat("\t", level))
 	if err != nil {
 		return
@@ -44,11 +84,11 @@ func (rw *RangeWriter) WriteIndent(level int, s string) (r parser.Range, err err
 
 func (rw *RangeWriter) WriteStringLiteral(level int, s string) (r parser.Range, err error) {
 	if !rw.inLiteral {
-		_, err = rw.write(strings.Repeat("\t", level))
-		if err != nil {
+		if err = rw.StartOutput(level); err != nil {
 			return
 		}
-		if _, err = rw.WriteIndent(level, `_, err = templBuffer.WriteString("`); err != nil {
+		rw.EndOutput()
+		if _, err = rw.writeIndent(level, `_, err = templBuffer.WriteString("`); err != nil {
 			return
 		}
 	}
@@ -61,10 +101,8 @@ func (rw *RangeWriter) WriteStringLiteral(level int, s string) (r parser.Range,
 }
 
 func (rw *RangeWriter) Write(s string) (r parser.Range, err error) {
-	if rw.inLiteral {
-		if _, err = rw.closeLiteral(0); err != nil {
-			return
-		}
+	if err = rw.close(0); err != nil {
+		return
 	}
 	return rw.write(s)
 }
@@ -75,14 +113,15 @@ func (rw *RangeWriter) write(s string) (r parser.Range, err error) {
 		Line:  rw.Current.Line,
 		Col:   rw.Current.Col,
 	}
+	// Columns are byte offsets within the line, to match the parser.
 	var n int
 	for _, c := range s {
-		rw.Current.Col++
+		n, err = io.WriteString(rw.w, string(c))
+		rw.Current.Col += uint32(n)
 		if c == '\n' {
 			rw.Current.Line++
 			rw.Current.Col = 0
 		}
-		n, err = io.WriteString(rw.w, string(c))
 		rw.Current.Index += int64(n)
 		if err != nil {
 			return r, err
@@ -93,17 +132,17 @@ func (rw *RangeWriter) write(s string) (r parser.Range, err error) {
 }
 
 func (rw *RangeWriter) writeErrorHandler(indentLevel int) (err error) {
-	_, err = rw.WriteIndent(indentLevel, "if err != nil {\n")
+	_, err = rw.writeIndent(indentLevel, "if err != nil {\n")
 	if err != nil {
 		return err
 	}
 	indentLevel++
-	_, err = rw.WriteIndent(indentLevel, "return err\n")
+	_, err = rw.writeIndent(indentLevel, "return err\n")
 	if err != nil {
 		return err
 	}
 	indentLevel--
-	_, err = rw.WriteIndent(indentLevel, "}\n")
+	_, err = rw.writeIndent(indentLevel, "}\n")
 	if err != nil {
 		return err
 	}
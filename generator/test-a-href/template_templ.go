@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: b20b353437597627d7a7c526a9987c75b0ed2fd0f535c919a4c84f5c5202ed17
 
 package testahref
 
@@ -9,70 +11,56 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func render() templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testahref.render"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<a href=\"javascript:alert(&#39;unaffected&#39;);\">")
-		if err != nil {
-			return err
-		}
-		var_2 := `Ignored`
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a><a href=\"")
-		if err != nil {
-			return err
-		}
-		var var_3 templ.SafeURL = templ.URL("javascript:alert('should be sanitized')")
-		_, err = templBuffer.WriteString(templ.EscapeString(string(var_3)))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var_4 := `Sanitized`
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a><a href=\"")
-		if err != nil {
-			return err
-		}
-		var var_5 templ.SafeURL = templ.SafeURL("javascript:alert('should not be sanitized')")
-		_, err = templBuffer.WriteString(templ.EscapeString(string(var_5)))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var_6 := `Unsanitized`
-		_, err = templBuffer.WriteString(var_6)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<a href=\"javascript:alert(&#39;unaffected&#39;);\">Ignored</a><a href=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:5
+			var var_2 templ.SafeURL = templ.URL("javascript:alert('should be sanitized')")
+//line template_templ.go:42
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_2)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">Sanitized</a><a href=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:6
+			var var_3 templ.SafeURL = templ.SafeURL("javascript:alert('should not be sanitized')")
+//line template_templ.go:53
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_3)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">Unsanitized</a>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 36f970cba1f80abdef545adec61fd4e6ae7264cfb3364fb43404d29a705e5ecc
 
 package testhtml
 
@@ -9,43 +11,158 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
+import "fmt"
+
+//line template.templ:5
 func BasicTemplate(url string) templ.Component {
+//line template_templ.go:20
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.BasicTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div><a href=\"")
-		if err != nil {
-			return err
-		}
-		var var_2 templ.SafeURL = templ.URL(url)
-		_, err = templBuffer.WriteString(templ.EscapeString(string(var_2)))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var_3 := `text`
-		_, err = templBuffer.WriteString(var_3)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a></div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div><a href=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:7
+			var var_2 templ.SafeURL = templ.URL(url)
+//line template_templ.go:45
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_2)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">text</a></div><div><img src=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:10
+			var var_3 templ.SafeURL = templ.URL(url)
+//line template_templ.go:56
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_3)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"><form action=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:11
+			var var_4 templ.SafeURL = templ.URL(url)
+//line template_templ.go:67
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_4)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></form><div id=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:12
+			_, err = templBuffer.WriteString(templ.EscapeString(fmt.Sprintf("row-%d", 1)))
+//line template_templ.go:78
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" title=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:12
+			_, err = templBuffer.WriteString(templ.EscapeString(url))
+//line template_templ.go:88
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></div></div><div><button formaction=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:15
+			var var_5 templ.SafeURL = templ.URL(url)
+//line template_templ.go:98
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_5)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">text</button><video poster=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:16
+			var var_6 templ.SafeURL = templ.URL(url)
+//line template_templ.go:109
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_6)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></video><A HREF=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:17
+			var var_7 templ.SafeURL = templ.URL(url)
+//line template_templ.go:120
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_7)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">text</A><p title=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:18
+			_, err = templBuffer.WriteString(templ.EscapeString(url))
+//line template_templ.go:131
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_8 string
+//line template.templ:18
+			var_8, err = templ.EscapeAny(url)
+//line template_templ.go:142
+			if err != nil {
+				return templ.WrapError(err, "generator/test-attribute-escaping/template.templ", 18, 24)
+			}
+			_, err = templBuffer.WriteString(var_8)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</p><div")
+			if err != nil {
+				return err
+			}
+//line template.templ:19
+			err = templ.RenderAttributes(ctx, templBuffer, templ.Attributes{"onclick": url, "href": url})
+//line template_templ.go:156
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("></div></div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
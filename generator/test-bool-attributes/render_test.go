@@ -0,0 +1,41 @@
+package testboolattributes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled bool
+		expected string
+	}{
+		{
+			name:     "bool expression attributes are rendered when true",
+			disabled: true,
+			expected: `<input type="checkbox" checked><select><option selected>A</option></select><button disabled>Submit</button>`,
+		},
+		{
+			name:     "bool expression attributes are omitted when false",
+			disabled: false,
+			expected: `<input type="checkbox" checked><select><option selected>A</option></select><button>Submit</button>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := render(tt.disabled).Render(context.Background(), w)
+			if err != nil {
+				t.Errorf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -1,26 +1,14 @@
 package testcall
 
 import (
-	_ "embed"
 	"testing"
 
-	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/a-h/templ/templtest"
 )
 
-//go:embed expected.html
-var expected string
-
 func Test(t *testing.T) {
-	component := personTemplate(person{
+	templtest.Golden(t, "call", personTemplate(person{
 		name:  "Luiz Bonfa",
 		email: "luiz@example.com",
-	})
-
-	diff, err := htmldiff.Diff(component, expected)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if diff != "" {
-		t.Error(diff)
-	}
+	}))
 }
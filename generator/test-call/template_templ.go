@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 71efba9a920c794799ec8b1c4dd0cc143f0429c617340cda13859f2ed4a06479
 
 package testcall
 
@@ -9,101 +11,120 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func personTemplate(p person) templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcall.personTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div><h1>")
-		if err != nil {
-			return err
-		}
-		var var_2 string = p.name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1><div style=\"font-family: &#39;sans-serif&#39;\" id=\"test\" data-contents=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(`something with "quotes" and a <tag>`))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div><h1>")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line template.templ:5
+			var_2, err = templ.EscapeAny(p.name)
+//line template_templ.go:43
+			if err != nil {
+				return templ.WrapError(err, "generator/test-call/template.templ", 5, 9)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1><div style=\"font-family: &#39;sans-serif&#39;\" id=\"test\" data-contents=\"something with &#34;quotes&#34; and a &lt;tag&gt;\">")
+			if err != nil {
+				return err
+			}
+		}
+//line template.templ:7
 		err = email(p.email).Render(ctx, templBuffer)
+//line template_templ.go:58
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-call/template.templ", 7, 7)
 		}
-		_, err = templBuffer.WriteString("</div></div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div></div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:12
 func email(s string) templ.Component {
+//line template_templ.go:77
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcall.email"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_3 := templ.GetChildren(ctx)
 		if var_3 == nil {
 			var_3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div>")
-		if err != nil {
-			return err
-		}
-		var_4 := `email:`
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<a href=\"")
-		if err != nil {
-			return err
-		}
-		var var_5 templ.SafeURL = templ.URL("mailto: " + s)
-		_, err = templBuffer.WriteString(templ.EscapeString(string(var_5)))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var var_6 string = s
-		_, err = templBuffer.WriteString(templ.EscapeString(var_6))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a></div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div>email:<a href=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:13
+			var var_4 templ.SafeURL = templ.URL("mailto: " + s)
+//line template_templ.go:102
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_4)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_5 string
+//line template.templ:13
+			var_5, err = templ.EscapeAny(s)
+//line template_templ.go:114
+			if err != nil {
+				return templ.WrapError(err, "generator/test-call/template.templ", 13, 53)
+			}
+			_, err = templBuffer.WriteString(var_5)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a></div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -0,0 +1,51 @@
+package testcharacterreferences
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCharacterReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    templ.Component
+		expected string
+	}{
+		{
+			name:     "named references are not escaped",
+			input:    named(),
+			expected: `<p>Non&nbsp;breaking &copy; 2023</p>`,
+		},
+		{
+			name:     "decimal and hexadecimal references are not escaped",
+			input:    numeric(),
+			expected: `<p>It&#x2019;s &#169; &#X2019;</p>`,
+		},
+		{
+			name:     "ampersands that don't start a reference are escaped",
+			input:    ampersands("&copy;"),
+			expected: `<p>Fish &amp; chips &amp;unknown; &amp; &amp;copy;</p>`,
+		},
+		{
+			name:     "references in attribute values are not escaped",
+			input:    attributes(),
+			expected: `<a title="Tom &amp; Jerry &copy; &amp; co" data-quote="say &#34;hi&#34;" href="/search?a=1&amp;b=2">Link</a>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := tt.input.Render(context.Background(), w); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
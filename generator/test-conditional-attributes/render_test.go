@@ -0,0 +1,47 @@
+package testconditionalattributes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		expanded bool
+		selected bool
+		expected string
+	}{
+		{
+			name:     "else branch",
+			expected: `<div class="panel" aria-expanded="false">Panel</div>`,
+		},
+		{
+			name:     "nested else branch",
+			expanded: true,
+			expected: `<div class="panel" aria-expanded="true" tabindex="0" aria-selected="false">Panel</div>`,
+		},
+		{
+			name:     "nested then branch",
+			expanded: true,
+			selected: true,
+			expected: `<div class="panel" aria-expanded="true" tabindex="0" aria-selected="true">Panel</div>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := render(tt.expanded, tt.selected).Render(context.Background(), w)
+			if err != nil {
+				t.Errorf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
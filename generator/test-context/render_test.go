@@ -0,0 +1,55 @@
+package testcontext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/a-h/templ"
+)
+
+// withUser sets the user and locale in the request context, like authentication middleware.
+func withUser(user, locale string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := templ.WithValue(r.Context(), userContextKey, user)
+		ctx = templ.WithValue(ctx, localeContextKey, locale)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func render(t *testing.T, h http.Handler) *goquery.Document {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	doc, err := goquery.NewDocumentFromReader(w.Body)
+	if err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	return doc
+}
+
+func Test(t *testing.T) {
+	t.Run("nested components read values set by middleware", func(t *testing.T) {
+		doc := render(t, withUser("Alice", "fr", templ.Handler(Page())))
+		user := doc.Find("nav > span.user")
+		if text := user.Text(); text != "Bonjour, Alice" {
+			t.Errorf("expected the user to be greeted, got %q", text)
+		}
+		if locale, _ := user.Attr("data-locale"); locale != "fr" {
+			t.Errorf("expected the locale attribute to be fr, got %q", locale)
+		}
+		if lang, _ := doc.Find("main > p").Attr("lang"); lang != "fr" {
+			t.Errorf("expected children to read the locale, got %q", lang)
+		}
+	})
+	t.Run("missing values are the zero value", func(t *testing.T) {
+		doc := render(t, templ.Handler(Page()))
+		if href, _ := doc.Find("nav > a").Attr("href"); href != "/login" {
+			t.Errorf("expected a login link, got %q", href)
+		}
+		if lang, ok := doc.Find("main > p").Attr("lang"); !ok || lang != "" {
+			t.Errorf("expected an empty lang attribute, got %q", lang)
+		}
+	})
+}
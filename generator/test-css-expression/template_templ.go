@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: b3dee2e877c7da07fc907c1a8a6fc92e6d2741ef6372106b28cdbd83c0d603db
 
 package testcssexpression
 
@@ -7,11 +9,15 @@ package testcssexpression
 import "github.com/a-h/templ"
 import "strings"
 
+//line template.templ:3
 func className() templ.CSSClass {
+//line template_templ.go:15
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`background-color:#ffffff;`)
 	templCSSBuilder.WriteString(`max-height:calc(100vh - 170px);`)
+//line template.templ:6
 	templCSSBuilder.WriteString(string(templ.SanitizeCSS(`color`, red)))
+//line template_templ.go:21
 	templCSSID := templ.CSSID(`className`, templCSSBuilder.String())
 	return templ.ComponentCSSClass{
 		ID:    templCSSID,
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: ed91d31cf7eef70d2f5a0c7f1526dd06dc517476633be2ae39a2b8c691561efe
 
 package testcssmiddleware
 
@@ -10,7 +12,9 @@ import "io"
 import "bytes"
 import "strings"
 
+//line template.templ:3
 func red() templ.CSSClass {
+//line template_templ.go:18
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`color:red;`)
 	templCSSID := templ.CSSID(`red`, templCSSBuilder.String())
@@ -20,47 +24,64 @@ func red() templ.CSSClass {
 	}
 }
 
+//line template.templ:7
 func render(s string) templ.Component {
+//line template_templ.go:30
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssmiddleware.render"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_2 = []any{red}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var var_3 string = s
-		_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+//line template.templ:8
+			var var_2 = []any{red}
+//line template_templ.go:51
+			err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line template.templ:8
+			var_3, err = templ.EscapeAny(s)
+//line template_templ.go:71
+			if err != nil {
+				return templ.WrapError(err, "generator/test-css-middleware/template.templ", 8, 23)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: a07d86d954d6da0ab084597fda03b00dde56efb3f9c00213025262f2d0a63753
 
 package testcssusage
 
@@ -10,7 +12,9 @@ import "io"
 import "bytes"
 import "strings"
 
+//line template.templ:3
 func green() templ.CSSClass {
+//line template_templ.go:18
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`color:#00ff00;`)
 	templCSSID := templ.CSSID(`green`, templCSSBuilder.String())
@@ -20,10 +24,14 @@ func green() templ.CSSClass {
 	}
 }
 
+//line template.templ:7
 func className() templ.CSSClass {
+//line template_templ.go:30
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`background-color:#ffffff;`)
+//line template.templ:9
 	templCSSBuilder.WriteString(string(templ.SanitizeCSS(`color`, red)))
+//line template_templ.go:35
 	templCSSID := templ.CSSID(`className`, templCSSBuilder.String())
 	return templ.ComponentCSSClass{
 		ID:    templCSSID,
@@ -31,237 +39,402 @@ func className() templ.CSSClass {
 	}
 }
 
+//line template.templ:12
 func Button(text string) templ.Component {
+//line template_templ.go:45
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssusage.Button"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_2 = []any{className(), templ.Class("&&&unsafe"), "safe", templ.SafeClass("safe2")}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<button class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" type=\"button\">")
-		if err != nil {
-			return err
-		}
-		var var_3 string = text
-		_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+//line template.templ:13
+			var var_2 = []any{className(), templ.Class("&&&unsafe"), "safe", templ.SafeClass("safe2")}
+//line template_templ.go:66
+			err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<button class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" type=\"button\">")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line template.templ:13
+			var_3, err = templ.EscapeAny(text)
+//line template_templ.go:86
+			if err != nil {
+				return templ.WrapError(err, "generator/test-css-usage/template.templ", 13, 108)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</button>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:16
 func LegacySupport() templ.Component {
+//line template_templ.go:108
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssusage.LegacySupport"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_4 := templ.GetChildren(ctx)
 		if var_4 == nil {
 			var_4 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_5 = []any{templ.Classes(templ.Class("test"), "a")}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_5...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_5).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"></div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+//line template.templ:17
+			var var_5 = []any{templ.Classes(templ.Class("test"), "a")}
+//line template_templ.go:129
+			err = templ.RenderCSSItems(ctx, templBuffer, var_5...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_5).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:20
 func MapCSSExample() templ.Component {
+//line template_templ.go:156
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssusage.MapCSSExample"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_6 := templ.GetChildren(ctx)
 		if var_6 == nil {
 			var_6 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_7 = []any{map[string]bool{"a": true, "b": false, "c": true}}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_7...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_7).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"></div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+//line template.templ:21
+			var var_7 = []any{map[string]bool{"a": true, "b": false, "c": true}}
+//line template_templ.go:177
+			err = templ.RenderCSSItems(ctx, templBuffer, var_7...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_7).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:24
 func KVExample() templ.Component {
+//line template_templ.go:204
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssusage.KVExample"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_8 := templ.GetChildren(ctx)
 		if var_8 == nil {
 			var_8 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_9 = []any{"a", templ.KV("b", false)}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_9...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_9).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"></div>")
-		if err != nil {
-			return err
-		}
-		var var_10 = []any{"a", "b", "c", templ.KV("c", false)}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_10...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<input type=\"email\" id=\"email\" name=\"email\" class=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_10).String()))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" placeholder=\"your@email.com\" autocomplete=\"off\">")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+//line template.templ:25
+			var var_9 = []any{"a", templ.KV("b", false)}
+//line template_templ.go:225
+			err = templ.RenderCSSItems(ctx, templBuffer, var_9...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_9).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></div>")
+			if err != nil {
+				return err
+			}
+//line template.templ:26
+			var var_10 = []any{"a", "b", "c", templ.KV("c", false)}
+//line template_templ.go:244
+			err = templ.RenderCSSItems(ctx, templBuffer, var_10...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<input type=\"email\" id=\"email\" name=\"email\" class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_10).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" placeholder=\"your@email.com\" autocomplete=\"off\">")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
-func ThreeButtons() templ.Component {
+//line template.templ:29
+func StyleExample(color string) templ.Component {
+//line template_templ.go:271
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssusage.StyleExample"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_11 := templ.GetChildren(ctx)
 		if var_11 == nil {
 			var_11 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		err = Button("A").Render(ctx, templBuffer)
-		if err != nil {
-			return err
+		if !templSkip {
+//line template.templ:30
+			var var_12 = []any{"a  b", "", templ.KV("c", true), templ.KV("c", false), templ.KV("a", true)}
+//line template_templ.go:292
+			err = templ.RenderCSSItems(ctx, templBuffer, var_12...)
+			if err != nil {
+				return err
+			}
+//line template.templ:30
+			var var_13 = []any{templ.Styles(map[string]string{"width": "", "color": color}, templ.KV("padding", "4px"), templ.KV("color", color))}
+//line template_templ.go:299
+			_, err = templBuffer.WriteString("<div class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_12).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" style=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSStyles(var_13).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></div>")
+			if err != nil {
+				return err
+			}
+//line template.templ:31
+			var var_14 = []any{templ.SafeCSS("font-weight: bold")}
+//line template_templ.go:322
+			_, err = templBuffer.WriteString("<p style=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSStyles(var_14).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></p>")
+			if err != nil {
+				return err
+			}
+//line template.templ:32
+			var var_15 = []any{"display: none; color: " + color}
+//line template_templ.go:337
+			_, err = templBuffer.WriteString("<p style=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSStyles(var_15).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></p><p style=\"color: red\"></p>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
-		err = Button("B").Render(ctx, templBuffer)
-		if err != nil {
-			return err
-		}
-		var var_12 = []any{templ.Classes(green)}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_12...)
-		if err != nil {
-			return err
+		return err
+	})
+}
+
+//line template.templ:36
+func ThreeButtons() templ.Component {
+//line template_templ.go:360
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testcssusage.ThreeButtons"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
 		}
-		_, err = templBuffer.WriteString("<button class=\"")
-		if err != nil {
-			return err
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
-		_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_12).String()))
-		if err != nil {
-			return err
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_16 := templ.GetChildren(ctx)
+		if var_16 == nil {
+			var_16 = templ.NopComponent
 		}
-		_, err = templBuffer.WriteString("\" type=\"button\">")
+		ctx = templ.ClearChildren(ctx)
+//line template.templ:37
+		err = Button("A").Render(ctx, templBuffer)
+//line template_templ.go:380
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-css-usage/template.templ", 37, 5)
 		}
-		var var_13 string = "Green"
-		_, err = templBuffer.WriteString(templ.EscapeString(var_13))
+//line template.templ:38
+		err = Button("B").Render(ctx, templBuffer)
+//line template_templ.go:386
+		if err != nil {
+			return templ.WrapError(err, "generator/test-css-usage/template.templ", 38, 5)
+		}
+		if !templSkip {
+//line template.templ:39
+			var var_17 = []any{templ.Classes(green)}
+//line template_templ.go:393
+			err = templ.RenderCSSItems(ctx, templBuffer, var_17...)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<button class=\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_17).String()))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" type=\"button\">Green</button>")
+			if err != nil {
+				return err
+			}
+		}
+//line template.templ:40
+		err = MapCSSExample().Render(ctx, templBuffer)
+//line template_templ.go:413
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-css-usage/template.templ", 40, 5)
 		}
-		_, err = templBuffer.WriteString("</button>")
+//line template.templ:41
+		err = KVExample().Render(ctx, templBuffer)
+//line template_templ.go:419
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-css-usage/template.templ", 41, 5)
 		}
-		err = MapCSSExample().Render(ctx, templBuffer)
+//line template.templ:42
+		err = StyleExample("blue").Render(ctx, templBuffer)
+//line template_templ.go:425
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-css-usage/template.templ", 42, 3)
 		}
-		err = KVExample().Render(ctx, templBuffer)
+//line template.templ:43
+		err = StyleExample("red\" onclick=\"alert(1)").Render(ctx, templBuffer)
+//line template_templ.go:431
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-css-usage/template.templ", 43, 3)
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: cc0ea38e4dcfef1aa16568535d0cd487186be8c356ae5a6b4e645f039b89544c
 
 package testelementattributes
 
@@ -10,7 +12,9 @@ import "io"
 import "bytes"
 import "strings"
 
+//line template.templ:3
 func important() templ.CSSClass {
+//line template_templ.go:18
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`width:100;`)
 	templCSSID := templ.CSSID(`important`, templCSSBuilder.String())
@@ -20,7 +24,9 @@ func important() templ.CSSClass {
 	}
 }
 
+//line template.templ:7
 func unimportant() templ.CSSClass {
+//line template_templ.go:30
 	var templCSSBuilder strings.Builder
 	templCSSBuilder.WriteString(`width:50;`)
 	templCSSID := templ.CSSID(`unimportant`, templCSSBuilder.String())
@@ -30,147 +36,143 @@ func unimportant() templ.CSSClass {
 	}
 }
 
+//line template.templ:11
 func render(p person) templ.Component {
+//line template_templ.go:42
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testelementattributes.render"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var var_2 = []any{important()}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div style=\"width: 100;\"")
-		if err != nil {
-			return err
-		}
-		if p.important {
-			_, err = templBuffer.WriteString(" class=\"")
+		if !templSkip {
+//line template.templ:14
+			var var_2 = []any{important()}
+//line template_templ.go:63
+			err = templ.RenderCSSItems(ctx, templBuffer, var_2...)
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+			_, err = templBuffer.WriteString("<div style=\"width: 100;\"")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("\"")
-			if err != nil {
-				return err
+//line template.templ:13
+			if p.important {
+//line template_templ.go:74
+				_, err = templBuffer.WriteString(" class=\"")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_2).String()))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\"")
+				if err != nil {
+					return err
+				}
 			}
-		}
-		_, err = templBuffer.WriteString(">")
-		if err != nil {
-			return err
-		}
-		var_3 := `Important`
-		_, err = templBuffer.WriteString(var_3)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		var var_4 = []any{unimportant}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_4...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div style=\"width: 100;\"")
-		if err != nil {
-			return err
-		}
-		if !p.important {
-			_, err = templBuffer.WriteString(" class=\"")
+			_, err = templBuffer.WriteString(">Important</div>")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_4).String()))
+//line template.templ:19
+			var var_3 = []any{unimportant}
+//line template_templ.go:94
+			err = templ.RenderCSSItems(ctx, templBuffer, var_3...)
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("\"")
+			_, err = templBuffer.WriteString("<div style=\"width: 100;\"")
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString(">")
-		if err != nil {
-			return err
-		}
-		var_5 := `Unimportant`
-		_, err = templBuffer.WriteString(var_5)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		var var_6 = []any{important}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_6...)
-		if err != nil {
-			return err
-		}
-		var var_7 = []any{unimportant}
-		err = templ.RenderCSSItems(ctx, templBuffer, var_7...)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<div style=\"width: 100;\"")
-		if err != nil {
-			return err
-		}
-		if p.important {
-			_, err = templBuffer.WriteString(" class=\"")
-			if err != nil {
-				return err
+//line template.templ:18
+			if !p.important {
+//line template_templ.go:105
+				_, err = templBuffer.WriteString(" class=\"")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_3).String()))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\"")
+				if err != nil {
+					return err
+				}
 			}
-			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_6).String()))
+			_, err = templBuffer.WriteString(">Unimportant</div>")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("\"")
+//line template.templ:24
+			var var_4 = []any{important}
+//line template_templ.go:125
+			err = templ.RenderCSSItems(ctx, templBuffer, var_4...)
 			if err != nil {
 				return err
 			}
-		} else {
-			_, err = templBuffer.WriteString(" class=\"")
+//line template.templ:26
+			var var_5 = []any{unimportant}
+//line template_templ.go:132
+			err = templ.RenderCSSItems(ctx, templBuffer, var_5...)
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_7).String()))
+			_, err = templBuffer.WriteString("<div style=\"width: 100;\"")
 			if err != nil {
 				return err
 			}
-			_, err = templBuffer.WriteString("\"")
+//line template.templ:23
+			if p.important {
+//line template_templ.go:143
+				_, err = templBuffer.WriteString(" class=\"")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_4).String()))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\"")
+				if err != nil {
+					return err
+				}
+			} else {
+				_, err = templBuffer.WriteString(" class=\"")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString(templ.EscapeString(templ.CSSClasses(var_5).String()))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\"")
+				if err != nil {
+					return err
+				}
+			}
+			_, err = templBuffer.WriteString(">Else</div><div data-script=\"on click\n                do something\n             end\"></div>")
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString(">")
-		if err != nil {
-			return err
-		}
-		var_8 := `Else`
-		_, err = templBuffer.WriteString(var_8)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div><div data-script=\"on click\n                do something\n             end\"></div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: f23e791a13ca0a2204b45abe494ccf1223b0a47268e321cd1e7d2d318719fe34
 
 package elseif
 
@@ -9,94 +11,134 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func render(d data) templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "elseif.render"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div>")
-		if err != nil {
-			return err
-		}
-		if d.IsTrue() {
-			var var_2 string = "True"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_2))
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div>")
 			if err != nil {
 				return err
 			}
+		}
+//line template.templ:5
+		if d.IsTrue() {
+//line template_templ.go:43
+			if !templSkip {
+				_, err = templBuffer.WriteString("True")
+				if err != nil {
+					return err
+				}
+			}
+//line template.templ:7
 		} else if !d.IsTrue() {
-			var var_3 string = "False"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-			if err != nil {
-				return err
+//line template_templ.go:52
+			if !templSkip {
+				_, err = templBuffer.WriteString("False")
+				if err != nil {
+					return err
+				}
 			}
 		} else {
-			var var_4 string = "Else"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_4))
+			if !templSkip {
+				_, err = templBuffer.WriteString("Else")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div><div>")
 			if err != nil {
 				return err
 			}
 		}
-		_, err = templBuffer.WriteString("</div><div>")
-		if err != nil {
-			return err
-		}
+//line template.templ:14
 		if 1 == 2 {
-			var var_5 string = "If"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_5))
-			if err != nil {
-				return err
+//line template_templ.go:75
+			if !templSkip {
+				_, err = templBuffer.WriteString("If")
+				if err != nil {
+					return err
+				}
 			}
+//line template.templ:16
 		} else if 1 == 1 {
-			var var_6 string = "ElseIf"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_6))
+//line template_templ.go:84
+			if !templSkip {
+				_, err = templBuffer.WriteString("ElseIf")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div><div>")
 			if err != nil {
 				return err
 			}
 		}
-		_, err = templBuffer.WriteString("</div><div>")
-		if err != nil {
-			return err
-		}
+//line template.templ:21
 		if 1 == 2 {
-			var var_7 string = "If"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_7))
-			if err != nil {
-				return err
+//line template_templ.go:100
+			if !templSkip {
+				_, err = templBuffer.WriteString("If")
+				if err != nil {
+					return err
+				}
 			}
+//line template.templ:23
 		} else if 1 == 3 {
-			var var_8 string = "ElseIf"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_8))
-			if err != nil {
-				return err
+//line template_templ.go:109
+			if !templSkip {
+				_, err = templBuffer.WriteString("ElseIf")
+				if err != nil {
+					return err
+				}
 			}
+//line template.templ:25
 		} else if 1 == 4 {
-			var var_9 string = "ElseIf"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_9))
-			if err != nil {
-				return err
+//line template_templ.go:118
+			if !templSkip {
+				_, err = templBuffer.WriteString("ElseIf")
+				if err != nil {
+					return err
+				}
 			}
+//line template.templ:27
 		} else if 1 == 1 {
-			var var_10 string = "OK"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_10))
+//line template_templ.go:127
+			if !templSkip {
+				_, err = templBuffer.WriteString("OK")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div>")
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
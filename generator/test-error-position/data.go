@@ -0,0 +1,30 @@
+package testerrorposition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+var errPriceUnavailable = errors.New("price unavailable")
+
+type item struct {
+	name  string
+	pence int
+	// priced is false if the price of the item isn't known.
+	priced bool
+}
+
+// price renders the price of the item, failing if it isn't known.
+func price(i item) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if !i.priced {
+			return fmt.Errorf("%s: %w", i.name, errPriceUnavailable)
+		}
+		_, err := fmt.Fprintf(w, "£%d.%02d", i.pence/100, i.pence%100)
+		return err
+	})
+}
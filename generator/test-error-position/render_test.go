@@ -0,0 +1,37 @@
+package testerrorposition
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func Test(t *testing.T) {
+	items := []item{
+		{name: "Tea", pence: 250, priced: true},
+		{name: "Cake"},
+	}
+	err := page(items).Render(context.Background(), new(bytes.Buffer))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// The position is where the error occurred, in the innermost template, not where the
+	// outer templates were called.
+	expected := "generator/test-error-position/template.templ:20:4: Cake: price unavailable"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(err, errPriceUnavailable) {
+		t.Error("expected errors.Is to find the error returned by the component")
+	}
+	var e *templ.Error
+	if !errors.As(err, &e) {
+		t.Fatal("expected errors.As to find the templ.Error")
+	}
+	if e.Line != 20 || e.Col != 4 {
+		t.Errorf("expected the error to be at 20:4, got %d:%d", e.Line, e.Col)
+	}
+}
@@ -0,0 +1,55 @@
+package testflush
+
+import (
+	"context"
+	_ "embed"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := Page([]string{"A", "B"})
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+// flushRecorder records the body that had been written each time it's flushed.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushed = append(r.flushed, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+func TestFlush(t *testing.T) {
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	if err := Page([]string{"A", "B"}).Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	expectedFlushes := []string{
+		`<html><head><title>Streaming</title></head><body><h1>Streaming</h1>`,
+		`<html><head><title>Streaming</title></head><body><h1>Streaming</h1><ul><li>A</li><li>B</li></ul>`,
+	}
+	if diff := cmp.Diff(expectedFlushes, w.flushed); diff != "" {
+		t.Errorf("unexpected output when flushed:\n%s", diff)
+	}
+	expectedBody := `<html><head><title>Streaming</title></head><body><h1>Streaming</h1><ul><li>A</li><li>B</li></ul><footer>Done</footer></body></html>`
+	if diff := cmp.Diff(expectedBody, w.Body.String()); diff != "" {
+		t.Errorf("unexpected body:\n%s", diff)
+	}
+}
@@ -0,0 +1,85 @@
+package testfragment
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test(t *testing.T) {
+	items := []string{"A", "B"}
+	tests := []struct {
+		name      string
+		fragments []string
+		expected  string
+	}{
+		{
+			name:      "a fragment is rendered with its nested fragments",
+			fragments: []string{"list"},
+			expected:  `<ul><li>A</li><li>B</li></ul><p>2 items</p>`,
+		},
+		{
+			name:      "nested fragments can be rendered on their own",
+			fragments: []string{"count"},
+			expected:  `<p>2 items</p>`,
+		},
+		{
+			name:      "multiple fragments are rendered in the order of the template",
+			fragments: []string{"footer", "count"},
+			expected:  `<p>2 items</p><footer>The end</footer>`,
+		},
+		{
+			name:      "nested fragments aren't rendered twice",
+			fragments: []string{"list", "count"},
+			expected:  `<ul><li>A</li><li>B</li></ul><p>2 items</p>`,
+		},
+		{
+			name:      "nothing is rendered if no fragments match",
+			fragments: []string{"missing"},
+			expected:  ``,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if err := templ.RenderFragment(context.Background(), w, page(items), tt.fragments...); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("expressions outside the fragments aren't evaluated", func(t *testing.T) {
+		var calls int
+		count := func() string {
+			calls++
+			return strconv.Itoa(calls)
+		}
+		w := new(bytes.Buffer)
+		if err := templ.RenderFragment(context.Background(), w, counted(count), "content"); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if diff := cmp.Diff(`<p>1</p>`, w.String()); diff != "" {
+			t.Error(diff)
+		}
+		if calls != 1 {
+			t.Errorf("expected only the expression in the fragment to be evaluated, got %d calls", calls)
+		}
+	})
+	t.Run("fragments are rendered as part of the page", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if err := page(items).Render(context.Background(), w); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		expected := `<html><body><h1>Items</h1><ul><li>A</li><li>B</li></ul><p>2 items</p><footer>The end</footer></body></html>`
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package testgenerics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		component templ.Component
+		expected  string
+	}{
+		{
+			name:      "single type parameter with an imported constraint",
+			component: list([]name{"a", "b"}),
+			expected:  `<ul><li>a</li><li>b</li></ul>`,
+		},
+		{
+			name:      "multiple type parameters",
+			component: pair(1, name("one")),
+			expected:  `<dl><dt>1</dt><dd>one</dd></dl>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := tt.component.Render(context.Background(), w); err != nil {
+				t.Errorf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
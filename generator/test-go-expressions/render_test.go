@@ -0,0 +1,51 @@
+package testgoexpressions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGoExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    templ.Component
+		expected string
+	}{
+		{
+			name:     "expressions can contain nested braces",
+			input:    nested(),
+			expected: `<p>1 items</p>`,
+		},
+		{
+			name:     "expressions can span multiple lines and contain comments",
+			input:    multiline([]string{"a", "b"}),
+			expected: `<p>a}, b</p>`,
+		},
+		{
+			name:     "attribute expressions can span multiple lines",
+			input:    attribute(1),
+			expected: `<div id="item-1"></div>`,
+		},
+		{
+			name:     "templ element arguments can span multiple lines",
+			input:    element(),
+			expected: `<div class="card"><h1>Title (with brackets)</h1>41}</div>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := tt.input.Render(context.Background(), w); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 260522386d2326ae4949c814b1853ad178328c92f058762318f11608000893a5
 
 package testhtml
 
@@ -9,99 +11,111 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func render(p person) templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testhtml.render"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div><h1>")
-		if err != nil {
-			return err
-		}
-		var var_2 string = p.name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1><div style=\"font-family: &#39;sans-serif&#39;\" id=\"test\" data-contents=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(`something with "quotes" and a <tag>`))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"><div>")
-		if err != nil {
-			return err
-		}
-		var_3 := `email:`
-		_, err = templBuffer.WriteString(var_3)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<a href=\"")
-		if err != nil {
-			return err
-		}
-		var var_4 templ.SafeURL = templ.URL("mailto: " + p.email)
-		_, err = templBuffer.WriteString(templ.EscapeString(string(var_4)))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
-		}
-		var var_5 string = p.email
-		_, err = templBuffer.WriteString(templ.EscapeString(var_5))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</a></div></div></div><hr")
-		if err != nil {
-			return err
-		}
-		if true {
-			_, err = templBuffer.WriteString(" noshade")
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div><h1>")
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString("><hr optionA")
-		if err != nil {
-			return err
-		}
-		if true {
-			_, err = templBuffer.WriteString(" optionB")
+			var var_2 string
+//line template.templ:5
+			var_2, err = templ.EscapeAny(p.name)
+//line template_templ.go:43
+			if err != nil {
+				return templ.WrapError(err, "generator/test-html/template.templ", 5, 9)
+			}
+			_, err = templBuffer.WriteString(var_2)
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString(" optionC=\"other\"")
-		if err != nil {
-			return err
-		}
-		if false {
-			_, err = templBuffer.WriteString(" optionD")
+			_, err = templBuffer.WriteString("</h1><div style=\"font-family: &#39;sans-serif&#39;\" id=\"test\" data-contents=\"something with &#34;quotes&#34; and a &lt;tag&gt;\"><div>email:<a href=\"")
 			if err != nil {
 				return err
 			}
-		}
-		_, err = templBuffer.WriteString("><hr noshade>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+//line template.templ:7
+			var var_3 templ.SafeURL = templ.URL("mailto: " + p.email)
+//line template_templ.go:57
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_3)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
+			var var_4 string
+//line template.templ:7
+			var_4, err = templ.EscapeAny(p.email)
+//line template_templ.go:69
+			if err != nil {
+				return templ.WrapError(err, "generator/test-html/template.templ", 7, 61)
+			}
+			_, err = templBuffer.WriteString(var_4)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a></div></div></div><hr")
+			if err != nil {
+				return err
+			}
+//line template.templ:10
+			if true {
+//line template_templ.go:83
+				_, err = templBuffer.WriteString(" noshade")
+				if err != nil {
+					return err
+				}
+			}
+			_, err = templBuffer.WriteString("><hr optionA")
+			if err != nil {
+				return err
+			}
+//line template.templ:11
+			if true {
+//line template_templ.go:95
+				_, err = templBuffer.WriteString(" optionB")
+				if err != nil {
+					return err
+				}
+			}
+			_, err = templBuffer.WriteString(" optionC=\"other\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:11
+			if false {
+//line template_templ.go:107
+				_, err = templBuffer.WriteString(" optionD")
+				if err != nil {
+					return err
+				}
+			}
+			_, err = templBuffer.WriteString("><hr noshade>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
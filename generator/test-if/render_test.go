@@ -1,23 +1,11 @@
 package testif
 
 import (
-	_ "embed"
 	"testing"
 
-	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/a-h/templ/templtest"
 )
 
-//go:embed expected.html
-var expected string
-
 func Test(t *testing.T) {
-	component := render(data{})
-
-	diff, err := htmldiff.Diff(component, expected)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if diff != "" {
-		t.Error(diff)
-	}
+	templtest.Golden(t, "if", render(data{}))
 }
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: c504c44425aa8c2e4ea4c93a8f49441ba658239f314665a0e11fb1486101633f
 
 package testif
 
@@ -9,34 +11,47 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func render(d data) templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testif.render"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
+//line template.templ:4
 		if d.IsTrue() {
-			var var_2 string = "True"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-			if err != nil {
-				return err
+//line template_templ.go:37
+			if !templSkip {
+				_, err = templBuffer.WriteString("True")
+				if err != nil {
+					return err
+				}
 			}
 		} else {
-			var var_3 string = "False"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-			if err != nil {
-				return err
+			if !templSkip {
+				_, err = templBuffer.WriteString("False")
+				if err != nil {
+					return err
+				}
 			}
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 0e7b0ed40eae9734accacf33501f3b8951d9922c0f0b2d9be1036c67d54d1a67
 
 package testimport
 
@@ -9,77 +11,106 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func listItem() templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testimport.listItem"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<li>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<li>")
+			if err != nil {
+				return err
+			}
 		}
 		err = var_1.Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</li>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</li>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:7
 func list() templ.Component {
+//line template_templ.go:60
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testimport.list"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_2 := templ.GetChildren(ctx)
 		if var_2 == nil {
 			var_2 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<ul>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<ul>")
+			if err != nil {
+				return err
+			}
 		}
 		err = var_2.Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</ul>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</ul>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:13
 func main() templ.Component {
+//line template_templ.go:103
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testimport.main"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_3 := templ.GetChildren(ctx)
 		if var_3 == nil {
@@ -91,110 +122,101 @@ func main() templ.Component {
 			if !templIsBuffer {
 				templBuffer = templ.GetBuffer()
 				defer templ.ReleaseBuffer(templBuffer)
+				ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 			}
+			templSkip := templ.IsOutputSkipped(ctx)
 			var_5 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 				templBuffer, templIsBuffer := w.(*bytes.Buffer)
 				if !templIsBuffer {
 					templBuffer = templ.GetBuffer()
 					defer templ.ReleaseBuffer(templBuffer)
+					ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 				}
-				_, err = templBuffer.WriteString("<u>")
-				if err != nil {
-					return err
-				}
-				var_6 := `Item 1`
-				_, err = templBuffer.WriteString(var_6)
-				if err != nil {
-					return err
-				}
-				_, err = templBuffer.WriteString("</u>")
-				if err != nil {
-					return err
-				}
-				if !templIsBuffer {
-					_, err = io.Copy(w, templBuffer)
+				templSkip := templ.IsOutputSkipped(ctx)
+				if !templSkip {
+					_, err = templBuffer.WriteString("<u>Item 1</u>")
+					if err != nil {
+						return err
+					}
+					if !templIsBuffer {
+						_, err = io.Copy(w, templBuffer)
+					}
 				}
 				return err
 			})
+//line template.templ:15
 			err = listItem().Render(templ.WithChildren(ctx, var_5), templBuffer)
+//line template_templ.go:150
 			if err != nil {
-				return err
+				return templ.WrapError(err, "generator/test-import/template.templ", 15, 4)
 			}
-			_, err = templBuffer.WriteString(" ")
-			if err != nil {
-				return err
-			}
-			var_7 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			var_6 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 				templBuffer, templIsBuffer := w.(*bytes.Buffer)
 				if !templIsBuffer {
 					templBuffer = templ.GetBuffer()
 					defer templ.ReleaseBuffer(templBuffer)
+					ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 				}
-				_, err = templBuffer.WriteString("<u>")
-				if err != nil {
-					return err
-				}
-				var_8 := `Item 2`
-				_, err = templBuffer.WriteString(var_8)
-				if err != nil {
-					return err
-				}
-				_, err = templBuffer.WriteString("</u>")
-				if err != nil {
-					return err
-				}
-				if !templIsBuffer {
-					_, err = io.Copy(w, templBuffer)
+				templSkip := templ.IsOutputSkipped(ctx)
+				if !templSkip {
+					_, err = templBuffer.WriteString("<u>Item 2</u>")
+					if err != nil {
+						return err
+					}
+					if !templIsBuffer {
+						_, err = io.Copy(w, templBuffer)
+					}
 				}
 				return err
 			})
-			err = listItem().Render(templ.WithChildren(ctx, var_7), templBuffer)
+//line template.templ:18
+			err = listItem().Render(templ.WithChildren(ctx, var_6), templBuffer)
+//line template_templ.go:175
 			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString(" ")
-			if err != nil {
-				return err
+				return templ.WrapError(err, "generator/test-import/template.templ", 18, 4)
 			}
-			var_9 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			var_7 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 				templBuffer, templIsBuffer := w.(*bytes.Buffer)
 				if !templIsBuffer {
 					templBuffer = templ.GetBuffer()
 					defer templ.ReleaseBuffer(templBuffer)
+					ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 				}
-				_, err = templBuffer.WriteString("<u>")
-				if err != nil {
-					return err
-				}
-				var_10 := `Item 3`
-				_, err = templBuffer.WriteString(var_10)
-				if err != nil {
-					return err
-				}
-				_, err = templBuffer.WriteString("</u>")
-				if err != nil {
-					return err
-				}
-				if !templIsBuffer {
-					_, err = io.Copy(w, templBuffer)
+				templSkip := templ.IsOutputSkipped(ctx)
+				if !templSkip {
+					_, err = templBuffer.WriteString("<u>Item 3</u>")
+					if err != nil {
+						return err
+					}
+					if !templIsBuffer {
+						_, err = io.Copy(w, templBuffer)
+					}
 				}
 				return err
 			})
-			err = listItem().Render(templ.WithChildren(ctx, var_9), templBuffer)
+//line template.templ:21
+			err = listItem().Render(templ.WithChildren(ctx, var_7), templBuffer)
+//line template_templ.go:200
 			if err != nil {
-				return err
+				return templ.WrapError(err, "generator/test-import/template.templ", 21, 4)
 			}
-			if !templIsBuffer {
-				_, err = io.Copy(w, templBuffer)
+			if !templSkip {
+				if !templIsBuffer {
+					_, err = io.Copy(w, templBuffer)
+				}
 			}
 			return err
 		})
+//line template.templ:14
 		err = list().Render(templ.WithChildren(ctx, var_4), templBuffer)
+//line template_templ.go:213
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-import/template.templ", 14, 3)
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -0,0 +1,90 @@
+package testlinedirectives
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render(Person{Name: "Leeds Person", Address: &Address{City: "Leeds"}})
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+// panicPosition returns the position of the render function in the stack of the panic.
+func panicPosition(t *testing.T, render func()) (file string, line int) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+		// Find the frame of the template that panicked.
+		pc := make([]uintptr, 32)
+		frames := runtime.CallersFrames(pc[:runtime.Callers(0, pc)])
+		for {
+			frame, more := frames.Next()
+			if strings.HasPrefix(frame.Function, "github.com/a-h/templ/generator/test-line-directives.render") {
+				file, line = frame.File, frame.Line
+				return
+			}
+			if !more {
+				return
+			}
+		}
+	}()
+	render()
+	return
+}
+
+func TestPanicsAreReportedAtTheTemplateLine(t *testing.T) {
+	file, line := panicPosition(t, func() {
+		// The address is nil, so the if statement panics.
+		_ = render(Person{Name: "Homeless Person"}).Render(context.Background(), io.Discard)
+	})
+	if !strings.HasSuffix(file, "/test-line-directives/template.templ") || line != 15 {
+		t.Errorf("expected the panic to be reported at template.templ:15, got %s:%d", file, line)
+	}
+}
+
+type panicWriter struct{}
+
+func (panicWriter) Write(p []byte) (int, error) {
+	panic("write failed")
+}
+
+func TestPanicsInGeneratedCodeAreReportedAtTheGeneratedLine(t *testing.T) {
+	// The code that writes the output to w follows the expressions of the template, but
+	// isn't part of them, so it's reported at its line in the generated file.
+	src, err := os.ReadFile("template_templ.go")
+	if err != nil {
+		t.Fatalf("failed to read generated code: %v", err)
+	}
+	var expectedLine int
+	for i, l := range strings.Split(string(src), "\n") {
+		if strings.Contains(l, "templBuffer.WriteTo(w)") {
+			expectedLine = i + 1
+		}
+	}
+	file, line := panicPosition(t, func() {
+		_ = render(Person{Name: "Leeds Person", Address: &Address{City: "Leeds"}}).Render(context.Background(), panicWriter{})
+	})
+	if !strings.HasSuffix(file, "/test-line-directives/template_templ.go") || line != expectedLine {
+		t.Errorf("expected the panic to be reported at template_templ.go:%d, got %s:%d", expectedLine, file, line)
+	}
+}
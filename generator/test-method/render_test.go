@@ -0,0 +1,20 @@
+package testmethod
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	w := new(strings.Builder)
+	if err := page(person{name: "a"}).Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	expected := `<div>a</div><div>a<span>child</span></div>`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
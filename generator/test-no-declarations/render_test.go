@@ -0,0 +1,9 @@
+package testnodeclarations
+
+import "testing"
+
+func TestFilesWithoutDeclarationsCompile(t *testing.T) {
+	if actual := title("templ"); actual != "Templ" {
+		t.Errorf("expected %q, got %q", "Templ", actual)
+	}
+}
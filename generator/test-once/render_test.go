@@ -0,0 +1,33 @@
+package testonce
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/a-h/templ/templtest"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := Page()
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOnceIsRenderedInEachRender(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		doc := templtest.RenderToDocument(t, heading("Heading"))
+		if count := doc.Find("link").Length(); count != 1 {
+			t.Errorf("render %d: expected the link to be rendered once, got %d times", i, count)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package testrawelements
 
 import (
+	"context"
 	_ "embed"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ/generator/htmldiff"
@@ -20,3 +22,15 @@ func Test(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestRawElementContentsAreNotEscaped(t *testing.T) {
+	var sb strings.Builder
+	if err := Example().Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"if (a < b && b > 0) {", "`${a} is less than ${b}`"} {
+		if !strings.Contains(sb.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, sb.String())
+		}
+	}
+}
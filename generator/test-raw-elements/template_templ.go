@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: a4453e736d612f154abb6ac504e03def7254ecfd256e09d8527126c3755d280e
 
 package testrawelements
 
@@ -9,70 +11,34 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func Example() templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testrawelements.Example"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<html><head></head><body><style>")
-		if err != nil {
-			return err
-		}
-		var_2 := `<!-- Some stuff -->`
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</style><style>")
-		if err != nil {
-			return err
-		}
-		var_3 := `
-        .customClass {
-          border: 1px solid black;
-        }
-      `
-		_, err = templBuffer.WriteString(var_3)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</style><script type=\"text/javascript\">")
-		if err != nil {
-			return err
-		}
-		var_4 := `
-        $("div").marquee();
-        function test() {
-              window.open("https://example.com")
-        }
-      `
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</script><h1>")
-		if err != nil {
-			return err
-		}
-		var_5 := `Hello`
-		_, err = templBuffer.WriteString(var_5)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</h1></body></html>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<html><head></head><body><style><!-- Some & stuff --></style><style>\n        .customClass {\n          border: 1px solid black;\n        }\n      </style><script type=\"text/javascript\">\n        $(\"div\").marquee();\n        function test() {\n              window.open(\"https://example.com\")\n        }\n      </script><script type=\"text/javascript\">\n        if (a < b && b > 0) {\n          console.log(`${a} is less than ${b}`);\n        }\n      </script><h1>Hello</h1></body></html>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
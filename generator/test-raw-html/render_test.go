@@ -0,0 +1,41 @@
+package testrawhtml
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+const markdown = `<h1 id="title">Title</h1><p>Fish &amp; chips <em>&lt;3</em></p><script>alert("trusted")</script>`
+
+func Test(t *testing.T) {
+	component := render(markdown)
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRawOutputIsIdenticalToTheInput(t *testing.T) {
+	w := new(bytes.Buffer)
+	if err := render(markdown).Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if !strings.Contains(w.String(), "<div>"+markdown+"</div>") {
+		t.Errorf("expected the raw HTML to be rendered unchanged, got %q", w.String())
+	}
+	if strings.Count(w.String(), "<script>") != 1 {
+		t.Errorf("expected the escaped expression not to contain a script element, got %q", w.String())
+	}
+}
@@ -1,7 +1,9 @@
 package testscriptusage
 
 import (
+	"context"
 	_ "embed"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ/generator/htmldiff"
@@ -21,3 +23,26 @@ func Test(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestScriptsAreRenderedOncePerResponse(t *testing.T) {
+	w := new(strings.Builder)
+	if err := ThreeButtons().Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if count := strings.Count(w.String(), "function __templ_withParameters_"); count != 1 {
+		t.Errorf("expected the script to be rendered once, but it was rendered %d times", count)
+	}
+}
+
+func TestScriptArgumentsCannotCloseTheScriptElement(t *testing.T) {
+	w := new(strings.Builder)
+	if err := Button(`</script><script>alert("x")</script>`).Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if count := strings.Count(w.String(), "</script>"); count != 1 {
+		t.Errorf("expected only the rendered script element to be closed, got %d closing tags in:\n%s", count, w.String())
+	}
+	if !strings.Contains(w.String(), `\u003c/script\u003e`) {
+		t.Errorf("expected the argument to be JSON encoded, got:\n%s", w.String())
+	}
+}
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 26b1112a43f32e5cb6e8ad49f415a88bc728b73835ddc29ae4f001a3afcbc185
 
 package testscriptusage
 
@@ -9,7 +11,9 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func withParameters(a string, b string, c int) templ.ComponentScript {
+//line template_templ.go:17
 	return templ.ComponentScript{
 		Name:     `__templ_withParameters_1056`,
 		Function: `function __templ_withParameters_1056(a, b, c){console.log(a, b, c);}`,
@@ -17,7 +21,9 @@ func withParameters(a string, b string, c int) templ.ComponentScript {
 	}
 }
 
+//line template.templ:7
 func withoutParameters() templ.ComponentScript {
+//line template_templ.go:27
 	return templ.ComponentScript{
 		Name:     `__templ_withoutParameters_6bbf`,
 		Function: `function __templ_withoutParameters_6bbf(){alert("hello");}`,
@@ -25,7 +31,9 @@ func withoutParameters() templ.ComponentScript {
 	}
 }
 
+//line template.templ:11
 func onClick() templ.ComponentScript {
+//line template_templ.go:37
 	return templ.ComponentScript{
 		Name:     `__templ_onClick_657d`,
 		Function: `function __templ_onClick_657d(){alert("clicked");}`,
@@ -33,132 +41,139 @@ func onClick() templ.ComponentScript {
 	}
 }
 
+//line template.templ:15
 func Button(text string) templ.Component {
+//line template_templ.go:47
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testscriptusage.Button"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		err = templ.RenderScriptItems(ctx, templBuffer, withParameters("test", text, 123), withoutParameters())
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<button onClick=\"")
-		if err != nil {
-			return err
-		}
-		var var_2 templ.ComponentScript = withParameters("test", text, 123)
-		_, err = templBuffer.WriteString(var_2.Call)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" onMouseover=\"")
-		if err != nil {
-			return err
-		}
-		var var_3 templ.ComponentScript = withoutParameters()
-		_, err = templBuffer.WriteString(var_3.Call)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" type=\"button\">")
-		if err != nil {
-			return err
-		}
-		var var_4 string = text
-		_, err = templBuffer.WriteString(templ.EscapeString(var_4))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			err = templ.RenderScriptItems(ctx, templBuffer, withParameters("test", text, 123), withoutParameters())
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<button onClick=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:16
+			var var_2 templ.ComponentScript = withParameters("test", text, 123)
+//line template_templ.go:76
+			_, err = templBuffer.WriteString(var_2.Call)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" onMouseover=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:16
+			var var_3 templ.ComponentScript = withoutParameters()
+//line template_templ.go:87
+			_, err = templBuffer.WriteString(var_3.Call)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" type=\"button\">")
+			if err != nil {
+				return err
+			}
+			var var_4 string
+//line template.templ:16
+			var_4, err = templ.EscapeAny(text)
+//line template_templ.go:99
+			if err != nil {
+				return templ.WrapError(err, "generator/test-script-usage/template.templ", 16, 108)
+			}
+			_, err = templBuffer.WriteString(var_4)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</button>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:19
 func ThreeButtons() templ.Component {
+//line template_templ.go:121
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testscriptusage.ThreeButtons"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_5 := templ.GetChildren(ctx)
 		if var_5 == nil {
 			var_5 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
+//line template.templ:20
 		err = Button("A").Render(ctx, templBuffer)
+//line template_templ.go:141
 		if err != nil {
-			return err
+			return templ.WrapError(err, "generator/test-script-usage/template.templ", 20, 5)
 		}
+//line template.templ:21
 		err = Button("B").Render(ctx, templBuffer)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<button onMouseover=\"console.log(&#39;mouseover&#39;)\" type=\"button\">")
-		if err != nil {
-			return err
-		}
-		var_6 := `Button C`
-		_, err = templBuffer.WriteString(var_6)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button><button hx-on::click=\"alert(&#39;clicked inline&#39;)\" type=\"button\">")
-		if err != nil {
-			return err
-		}
-		var_7 := `Button D`
-		_, err = templBuffer.WriteString(var_7)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button>")
-		if err != nil {
-			return err
-		}
-		err = templ.RenderScriptItems(ctx, templBuffer, onClick())
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<button hx-on::click=\"")
-		if err != nil {
-			return err
-		}
-		var var_8 templ.ComponentScript = onClick()
-		_, err = templBuffer.WriteString(var_8.Call)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" type=\"button\">")
-		if err != nil {
-			return err
-		}
-		var_9 := `Button E`
-		_, err = templBuffer.WriteString(var_9)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</button>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+//line template_templ.go:147
+		if err != nil {
+			return templ.WrapError(err, "generator/test-script-usage/template.templ", 21, 3)
+		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("<button onMouseover=\"console.log(&#39;mouseover&#39;)\" type=\"button\">Button C</button><button hx-on::click=\"alert(&#39;clicked inline&#39;)\" type=\"button\">Button D</button>")
+			if err != nil {
+				return err
+			}
+			err = templ.RenderScriptItems(ctx, templBuffer, onClick())
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("<button hx-on::click=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:24
+			var var_6 templ.ComponentScript = onClick()
+//line template_templ.go:166
+			_, err = templBuffer.WriteString(var_6.Call)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" type=\"button\">Button E</button>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
@@ -0,0 +1,59 @@
+package testspreadattributes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    templ.Attributes
+		expected string
+	}{
+		{
+			name:     "nil attributes",
+			attrs:    nil,
+			expected: `<div class="explicit">Content</div>`,
+		},
+		{
+			name:     "empty attributes",
+			attrs:    templ.Attributes{},
+			expected: `<div class="explicit">Content</div>`,
+		},
+		{
+			name: "attributes are rendered in name order",
+			attrs: templ.Attributes{
+				"id":       "content",
+				"hidden":   true,
+				"disabled": false,
+				"data-url": templ.SafeURL("/a?b=c&d=e"),
+			},
+			expected: `<div class="explicit" data-url="/a?b=c&amp;d=e" hidden id="content">Content</div>`,
+		},
+		{
+			name: "colliding attributes are rendered after explicit attributes",
+			attrs: templ.Attributes{
+				"class": "spread",
+			},
+			expected: `<div class="explicit" class="spread">Content</div>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := render(tt.attrs).Render(context.Background(), w)
+			if err != nil {
+				t.Errorf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
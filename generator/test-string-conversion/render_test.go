@@ -0,0 +1,44 @@
+package teststringconversion
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render(42, 0.5, true, temperature(21.5), nil)
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestStringersAreEscaped(t *testing.T) {
+	component := render(0, 0, false, 0, &user{Name: "<b>Alice</b>"})
+
+	diff, err := htmldiff.Diff(component, `<ul><li>&lt;constant&gt;</li><li>raw &amp; constant</li><li>0</li><li>1</li><li>0</li><li>false</li><li>0.0°C</li><li>&lt;b&gt;Alice&lt;/b&gt;</li></ul>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestUnsupportedTypesReturnAnError(t *testing.T) {
+	err := unsupported([]string{"a"}).Render(context.Background(), io.Discard)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -1,10 +1,9 @@
 package testswitch
 
 import (
-	"context"
-	"strings"
 	"testing"
 
+	"github.com/a-h/templ/templtest"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -13,12 +12,7 @@ var input = "a"
 const expected = `it was &#39;a&#39;`
 
 func TestRender(t *testing.T) {
-	w := new(strings.Builder)
-	err := render(input).Render(context.Background(), w)
-	if err != nil {
-		t.Errorf("failed to render: %v", err)
-	}
-	if diff := cmp.Diff(expected, w.String()); diff != "" {
+	if diff := cmp.Diff(expected, templtest.Render(t, render(input))); diff != "" {
 		t.Error(diff)
 	}
 }
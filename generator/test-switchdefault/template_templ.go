@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 2bda0b6dc4b4da5df4e064bf55aefddfc468962420970b76301d8ba9c9021f59
 
 package testswitchdefault
 
@@ -9,35 +11,52 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func template(input string) templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testswitchdefault.template"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
+//line template.templ:4
 		switch input {
+//line template_templ.go:37
+//line template.templ:5
 		case "a":
-			var var_2 string = "it was 'a'"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_2))
-			if err != nil {
-				return err
+//line template_templ.go:40
+			if !templSkip {
+				_, err = templBuffer.WriteString("it was &#39;a&#39;")
+				if err != nil {
+					return err
+				}
 			}
+//line template.templ:7
 		default:
-			var var_3 string = "it was something else"
-			_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-			if err != nil {
-				return err
+//line template_templ.go:49
+			if !templSkip {
+				_, err = templBuffer.WriteString("it was something else")
+				if err != nil {
+					return err
+				}
 			}
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
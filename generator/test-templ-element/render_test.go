@@ -22,3 +22,16 @@ func Test(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestLayout(t *testing.T) {
+	expected := `<section><h1>outer</h1><p>body</p><section><h1>inner</h1><p>nested</p></section></section>` +
+		`<section><h1>empty</h1></section>`
+
+	diff, err := htmldiff.Diff(page(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
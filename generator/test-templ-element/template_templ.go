@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: dd926f525671fa23fdd9c3b0308248671d4e23b170793b005e05741f9ee1fb3a
 
 package testtemplelement
 
@@ -9,55 +11,76 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 import "fmt"
 
+//line template.templ:5
 func wrapper(index int) templ.Component {
+//line template_templ.go:20
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtemplelement.wrapper"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div id=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(fmt.Sprint(index)))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\">")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div id=\"")
+			if err != nil {
+				return err
+			}
+//line template.templ:6
+			_, err = templBuffer.WriteString(templ.EscapeString(fmt.Sprint(index)))
+//line template_templ.go:45
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\">")
+			if err != nil {
+				return err
+			}
 		}
 		err = var_1.Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line template.templ:11
 func template() templ.Component {
+//line template_templ.go:73
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtemplelement.template"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_2 := templ.GetChildren(ctx)
 		if var_2 == nil {
@@ -69,79 +92,237 @@ func template() templ.Component {
 			if !templIsBuffer {
 				templBuffer = templ.GetBuffer()
 				defer templ.ReleaseBuffer(templBuffer)
+				ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 			}
-			var_4 := `child1`
-			_, err = templBuffer.WriteString(var_4)
-			if err != nil {
-				return err
-			}
-			_, err = templBuffer.WriteString(" ")
-			if err != nil {
-				return err
+			templSkip := templ.IsOutputSkipped(ctx)
+			if !templSkip {
+				_, err = templBuffer.WriteString("child1 ")
+				if err != nil {
+					return err
+				}
 			}
-			var_5 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			var_4 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 				templBuffer, templIsBuffer := w.(*bytes.Buffer)
 				if !templIsBuffer {
 					templBuffer = templ.GetBuffer()
 					defer templ.ReleaseBuffer(templBuffer)
+					ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 				}
-				var_6 := `child2`
-				_, err = templBuffer.WriteString(var_6)
-				if err != nil {
-					return err
-				}
-				_, err = templBuffer.WriteString(" ")
-				if err != nil {
-					return err
+				templSkip := templ.IsOutputSkipped(ctx)
+				if !templSkip {
+					_, err = templBuffer.WriteString("child2 ")
+					if err != nil {
+						return err
+					}
 				}
-				var_7 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+				var_5 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 					templBuffer, templIsBuffer := w.(*bytes.Buffer)
 					if !templIsBuffer {
 						templBuffer = templ.GetBuffer()
 						defer templ.ReleaseBuffer(templBuffer)
+						ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 					}
-					var_8 := `child3`
-					_, err = templBuffer.WriteString(var_8)
-					if err != nil {
-						return err
-					}
-					_, err = templBuffer.WriteString(" ")
-					if err != nil {
-						return err
+					templSkip := templ.IsOutputSkipped(ctx)
+					if !templSkip {
+						_, err = templBuffer.WriteString("child3 ")
+						if err != nil {
+							return err
+						}
 					}
+//line template.templ:18
 					err = wrapper(4).Render(ctx, templBuffer)
+//line template_templ.go:135
 					if err != nil {
-						return err
+						return templ.WrapError(err, "generator/test-templ-element/template.templ", 18, 6)
 					}
-					if !templIsBuffer {
-						_, err = io.Copy(w, templBuffer)
+					if !templSkip {
+						if !templIsBuffer {
+							_, err = io.Copy(w, templBuffer)
+						}
 					}
 					return err
 				})
-				err = wrapper(3).Render(templ.WithChildren(ctx, var_7), templBuffer)
+//line template.templ:16
+				err = wrapper(3).Render(templ.WithChildren(ctx, var_5), templBuffer)
+//line template_templ.go:148
 				if err != nil {
-					return err
+					return templ.WrapError(err, "generator/test-templ-element/template.templ", 16, 5)
 				}
-				if !templIsBuffer {
-					_, err = io.Copy(w, templBuffer)
+				if !templSkip {
+					if !templIsBuffer {
+						_, err = io.Copy(w, templBuffer)
+					}
 				}
 				return err
 			})
-			err = wrapper(2).Render(templ.WithChildren(ctx, var_5), templBuffer)
+//line template.templ:14
+			err = wrapper(2).Render(templ.WithChildren(ctx, var_4), templBuffer)
+//line template_templ.go:161
 			if err != nil {
-				return err
+				return templ.WrapError(err, "generator/test-templ-element/template.templ", 14, 4)
 			}
-			if !templIsBuffer {
-				_, err = io.Copy(w, templBuffer)
+			if !templSkip {
+				if !templIsBuffer {
+					_, err = io.Copy(w, templBuffer)
+				}
 			}
 			return err
 		})
+//line template.templ:12
 		err = wrapper(1).Render(templ.WithChildren(ctx, var_3), templBuffer)
+//line template_templ.go:174
+		if err != nil {
+			return templ.WrapError(err, "generator/test-templ-element/template.templ", 12, 3)
+		}
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line template.templ:25
+func layout(title string) templ.Component {
+//line template_templ.go:189
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtemplelement.layout"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_6 := templ.GetChildren(ctx)
+		if var_6 == nil {
+			var_6 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<section><h1>")
+			if err != nil {
+				return err
+			}
+			var var_7 string
+//line template.templ:27
+			var_7, err = templ.EscapeAny(title)
+//line template_templ.go:215
+			if err != nil {
+				return templ.WrapError(err, "generator/test-templ-element/template.templ", 27, 9)
+			}
+			_, err = templBuffer.WriteString(var_7)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h1>")
+			if err != nil {
+				return err
+			}
+		}
+		err = var_6.Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
+		if !templSkip {
+			_, err = templBuffer.WriteString("</section>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line template.templ:32
+func page() templ.Component {
+//line template_templ.go:247
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtemplelement.page"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_8 := templ.GetChildren(ctx)
+		if var_8 == nil {
+			var_8 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		var_9 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			templBuffer, templIsBuffer := w.(*bytes.Buffer)
+			if !templIsBuffer {
+				templBuffer = templ.GetBuffer()
+				defer templ.ReleaseBuffer(templBuffer)
+				ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+			}
+			templSkip := templ.IsOutputSkipped(ctx)
+			if !templSkip {
+				_, err = templBuffer.WriteString("<p>body</p>")
+				if err != nil {
+					return err
+				}
+			}
+			var_10 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+				templBuffer, templIsBuffer := w.(*bytes.Buffer)
+				if !templIsBuffer {
+					templBuffer = templ.GetBuffer()
+					defer templ.ReleaseBuffer(templBuffer)
+					ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+				}
+				templSkip := templ.IsOutputSkipped(ctx)
+				if !templSkip {
+					_, err = templBuffer.WriteString("<p>nested</p>")
+					if err != nil {
+						return err
+					}
+					if !templIsBuffer {
+						_, err = io.Copy(w, templBuffer)
+					}
+				}
+				return err
+			})
+//line template.templ:35
+			err = layout("inner").Render(templ.WithChildren(ctx, var_10), templBuffer)
+//line template_templ.go:300
+			if err != nil {
+				return templ.WrapError(err, "generator/test-templ-element/template.templ", 35, 4)
+			}
+			if !templSkip {
+				if !templIsBuffer {
+					_, err = io.Copy(w, templBuffer)
+				}
+			}
+			return err
+		})
+//line template.templ:33
+		err = layout("outer").Render(templ.WithChildren(ctx, var_9), templBuffer)
+//line template_templ.go:313
+		if err != nil {
+			return templ.WrapError(err, "generator/test-templ-element/template.templ", 33, 3)
+		}
+//line template.templ:39
+		err = layout("empty").Render(ctx, templBuffer)
+//line template_templ.go:319
+		if err != nil {
+			return templ.WrapError(err, "generator/test-templ-element/template.templ", 39, 3)
+		}
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
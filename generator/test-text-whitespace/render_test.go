@@ -35,6 +35,36 @@ func TestTextWhitespace(t *testing.T) {
 			input:    WhiteSpaceAroundValues(),
 			expected: WhiteSpaceAroundValuesExpected,
 		},
+		{
+			name:     "inline elements on the same line are separated by a space",
+			input:    InlineElementsOnTheSameLineAreSpaced("a", "b"),
+			expected: InlineElementsOnTheSameLineAreSpacedExpected,
+		},
+		{
+			name:     "runs of whitespace are collapsed to a single space",
+			input:    RunsOfWhitespaceAreCollapsed(),
+			expected: RunsOfWhitespaceAreCollapsedExpected,
+		},
+		{
+			name:     "line breaks between elements are not rendered",
+			input:    LineBreaksBetweenElementsAreNotRendered(),
+			expected: LineBreaksBetweenElementsAreNotRenderedExpected,
+		},
+		{
+			name:     "spaces between elements are kept outside of elements",
+			input:    SpacesAreKeptOutsideElements("a", "b"),
+			expected: SpacesAreKeptOutsideElementsExpected,
+		},
+		{
+			name:     "whitespace within pre elements is preserved",
+			input:    PreformattedTextIsPreserved("value"),
+			expected: PreformattedTextIsPreservedExpected,
+		},
+		{
+			name:     "whitespace within textarea elements is preserved",
+			input:    TextareaContentsArePreserved(),
+			expected: TextareaContentsArePreservedExpected,
+		},
 	} {
 		w := new(strings.Builder)
 		err := test.input.Render(context.Background(), w)
@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: e9a41ef1400212638066442f3a91c0e4a762e8c2e3580b840ab64357b5fefc08
 
 package testtextwhitespace
 
@@ -9,203 +11,495 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func WhitespaceIsAddedWithinTemplStatements() templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.WhitespaceIsAddedWithinTemplStatements"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<p>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<p>This is some text. ")
+			if err != nil {
+				return err
+			}
 		}
-		var_2 := `This is some text.`
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
+//line template.templ:6
+		if true {
+//line template_templ.go:43
+			if !templSkip {
+				_, err = templBuffer.WriteString("So is this.")
+				if err != nil {
+					return err
+				}
+			}
 		}
-		_, err = templBuffer.WriteString(" ")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("</p>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
-		if true {
-			var_3 := `So is this.`
-			_, err = templBuffer.WriteString(var_3)
+		return err
+	})
+}
+
+//line template.templ:12
+const WhitespaceIsAddedWithinTemplStatementsExpected = `<p>This is some text. So is this.</p>`
+
+//line template.templ:14
+func InlineElementsAreNotPadded() templ.Component {
+//line template_templ.go:69
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.InlineElementsAreNotPadded"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_2 := templ.GetChildren(ctx)
+		if var_2 == nil {
+			var_2 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<p>Inline text <b>is spaced properly</b> without adding extra spaces.</p>")
 			if err != nil {
 				return err
 			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
-		_, err = templBuffer.WriteString("</p>")
-		if err != nil {
-			return err
+		return err
+	})
+}
+
+//line template.templ:18
+const InlineElementsAreNotPaddedExpected = `<p>Inline text <b>is spaced properly</b> without adding extra spaces.</p>`
+
+//line template.templ:20
+func WhiteSpaceInHTMLIsNormalised() templ.Component {
+//line template_templ.go:105
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.WhiteSpaceInHTMLIsNormalised"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
 		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_3 := templ.GetChildren(ctx)
+		if var_3 == nil {
+			var_3 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<p>newlines and other whitespace are stripped but it is normalised like HTML.</p>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
-const WhitespaceIsAddedWithinTemplStatementsExpected = `<p>This is some text. So is this.</p>`
+//line template.templ:27
+const WhiteSpaceInHTMLIsNormalisedExpected = `<p>newlines and other whitespace are stripped but it is normalised like HTML.</p>`
 
-func InlineElementsAreNotPadded() templ.Component {
+//line template.templ:29
+func WhiteSpaceAroundValues() templ.Component {
+//line template_templ.go:141
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.WhiteSpaceAroundValues"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_4 := templ.GetChildren(ctx)
 		if var_4 == nil {
 			var_4 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<p>")
-		if err != nil {
-			return err
-		}
-		var_5 := `Inline text `
-		_, err = templBuffer.WriteString(var_5)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("<b>")
-		if err != nil {
-			return err
-		}
-		var_6 := `is spaced properly`
-		_, err = templBuffer.WriteString(var_6)
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<p>templ allows strings to be included in sentences.</p>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
-		_, err = templBuffer.WriteString("</b> ")
-		if err != nil {
-			return err
+		return err
+	})
+}
+
+//line template.templ:33
+const WhiteSpaceAroundValuesExpected = `<p>templ allows strings to be included in sentences.</p>`
+
+//line template.templ:35
+func InlineElementsOnTheSameLineAreSpaced(a, b string) templ.Component {
+//line template_templ.go:177
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.InlineElementsOnTheSameLineAreSpaced"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
 		}
-		var_7 := `without adding extra spaces.`
-		_, err = templBuffer.WriteString(var_7)
-		if err != nil {
-			return err
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
-		_, err = templBuffer.WriteString("</p>")
-		if err != nil {
-			return err
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_5 := templ.GetChildren(ctx)
+		if var_5 == nil {
+			var_5 = templ.NopComponent
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<p><span>")
+			if err != nil {
+				return err
+			}
+			var var_6 string
+//line template.templ:36
+			var_6, err = templ.EscapeAny(a)
+//line template_templ.go:203
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 36, 14)
+			}
+			_, err = templBuffer.WriteString(var_6)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</span> <span>")
+			if err != nil {
+				return err
+			}
+			var var_7 string
+//line template.templ:36
+			var_7, err = templ.EscapeAny(b)
+//line template_templ.go:218
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 36, 33)
+			}
+			_, err = templBuffer.WriteString(var_7)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</span></p>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
-const InlineElementsAreNotPaddedExpected = `<p>Inline text <b>is spaced properly</b> without adding extra spaces.</p>`
+//line template.templ:39
+const InlineElementsOnTheSameLineAreSpacedExpected = `<p><span>a</span> <span>b</span></p>`
 
-func WhiteSpaceInHTMLIsNormalised() templ.Component {
+//line template.templ:41
+func RunsOfWhitespaceAreCollapsed() templ.Component {
+//line template_templ.go:243
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.RunsOfWhitespaceAreCollapsed"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_8 := templ.GetChildren(ctx)
 		if var_8 == nil {
 			var_8 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<p>")
-		if err != nil {
-			return err
-		}
-		var_9 := `newlines and other whitespace are stripped`
-		_, err = templBuffer.WriteString(var_9)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(" ")
-		if err != nil {
-			return err
-		}
-		var_10 := `but it is normalised`
-		_, err = templBuffer.WriteString(var_10)
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<p><b>bold</b> <i>italic</i></p>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
-		_, err = templBuffer.WriteString(" ")
-		if err != nil {
-			return err
+		return err
+	})
+}
+
+//line template.templ:45
+const RunsOfWhitespaceAreCollapsedExpected = `<p><b>bold</b> <i>italic</i></p>`
+
+//line template.templ:47
+func LineBreaksBetweenElementsAreNotRendered() templ.Component {
+//line template_templ.go:279
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.LineBreaksBetweenElementsAreNotRendered"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
 		}
-		var_11 := `like HTML.`
-		_, err = templBuffer.WriteString(var_11)
-		if err != nil {
-			return err
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
-		_, err = templBuffer.WriteString("</p>")
-		if err != nil {
-			return err
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_9 := templ.GetChildren(ctx)
+		if var_9 == nil {
+			var_9 = templ.NopComponent
 		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<ul><li>one</li><li>two</li></ul>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
-const WhiteSpaceInHTMLIsNormalisedExpected = `<p>newlines and other whitespace are stripped but it is normalised like HTML.</p>`
+//line template.templ:54
+const LineBreaksBetweenElementsAreNotRenderedExpected = `<ul><li>one</li><li>two</li></ul>`
 
-func WhiteSpaceAroundValues() templ.Component {
+//line template.templ:56
+func SpacesAreKeptOutsideElements(a, b string) templ.Component {
+//line template_templ.go:315
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.SpacesAreKeptOutsideElements"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
-		var_12 := templ.GetChildren(ctx)
-		if var_12 == nil {
-			var_12 = templ.NopComponent
+		var_10 := templ.GetChildren(ctx)
+		if var_10 == nil {
+			var_10 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<p>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<span>")
+			if err != nil {
+				return err
+			}
+			var var_11 string
+//line template.templ:57
+			var_11, err = templ.EscapeAny(a)
+//line template_templ.go:341
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 57, 11)
+			}
+			_, err = templBuffer.WriteString(var_11)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</span> <span>")
+			if err != nil {
+				return err
+			}
+			var var_12 string
+//line template.templ:57
+			var_12, err = templ.EscapeAny(b)
+//line template_templ.go:356
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 57, 30)
+			}
+			_, err = templBuffer.WriteString(var_12)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</span>")
+			if err != nil {
+				return err
+			}
 		}
-		var_13 := `templ allows `
-		_, err = templBuffer.WriteString(var_13)
-		if err != nil {
-			return err
+//line template.templ:58
+		if true {
+//line template_templ.go:371
+			if !templSkip {
+				_, err = templBuffer.WriteString("<b>")
+				if err != nil {
+					return err
+				}
+				var var_13 string
+//line template.templ:59
+				var_13, err = templ.EscapeAny(a)
+//line template_templ.go:380
+				if err != nil {
+					return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 59, 10)
+				}
+				_, err = templBuffer.WriteString(var_13)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</b> <i>")
+				if err != nil {
+					return err
+				}
+				var var_14 string
+//line template.templ:59
+				var_14, err = templ.EscapeAny(b)
+//line template_templ.go:395
+				if err != nil {
+					return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 59, 23)
+				}
+				_, err = templBuffer.WriteString(var_14)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</i>")
+				if err != nil {
+					return err
+				}
+			}
 		}
-		var var_14 string = "strings"
-		_, err = templBuffer.WriteString(templ.EscapeString(var_14))
-		if err != nil {
-			return err
+		if !templSkip {
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
-		_, err = templBuffer.WriteString(" ")
-		if err != nil {
-			return err
+		return err
+	})
+}
+
+//line template.templ:63
+const SpacesAreKeptOutsideElementsExpected = `<span>a</span> <span>b</span><b>a</b> <i>b</i>`
+
+//line template.templ:65
+func PreformattedTextIsPreserved(s string) templ.Component {
+//line template_templ.go:423
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.PreformattedTextIsPreserved"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
 		}
-		var_15 := `to be included in sentences.`
-		_, err = templBuffer.WriteString(var_15)
-		if err != nil {
-			return err
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
-		_, err = templBuffer.WriteString("</p>")
-		if err != nil {
-			return err
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_15 := templ.GetChildren(ctx)
+		if var_15 == nil {
+			var_15 = templ.NopComponent
 		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<pre>\n  indented   text\n\t<b>tabbed</b>  ")
+			if err != nil {
+				return err
+			}
+			var var_16 string
+//line template.templ:68
+			var_16, err = templ.EscapeAny(s)
+//line template_templ.go:449
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text-whitespace/template.templ", 68, 19)
+			}
+			_, err = templBuffer.WriteString(var_16)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\n</pre>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
+		}
+		return err
+	})
+}
+
+//line template.templ:72
+const PreformattedTextIsPreservedExpected = "<pre>\n  indented   text\n\t<b>tabbed</b>  value\n</pre>"
+
+//line template.templ:74
+func TextareaContentsArePreserved() templ.Component {
+//line template_templ.go:474
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtextwhitespace.TextareaContentsArePreserved"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
+		}
+		templSkip := templ.IsOutputSkipped(ctx)
+		ctx = templ.InitializeContext(ctx)
+		var_17 := templ.GetChildren(ctx)
+		if var_17 == nil {
+			var_17 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<textarea name=\"notes\">\n   line one\n     line two\n</textarea>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
-const WhiteSpaceAroundValuesExpected = `<p>templ allows strings to be included in sentences.</p>`
+//line template.templ:81
+const TextareaContentsArePreservedExpected = "<textarea name=\"notes\">\n   line one\n     line two\n</textarea>"
@@ -3,22 +3,9 @@ package testtext
 import (
 	"testing"
 
-	_ "embed"
-
-	"github.com/a-h/templ/generator/htmldiff"
+	"github.com/a-h/templ/templtest"
 )
 
-//go:embed expected.html
-var expected string
-
 func Test(t *testing.T) {
-	component := BasicTemplate("Luiz Bonfa")
-
-	diff, err := htmldiff.Diff(component, expected)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if diff != "" {
-		t.Error(diff)
-	}
+	templtest.Golden(t, "text", BasicTemplate("Luiz Bonfa"))
 }
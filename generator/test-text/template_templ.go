@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: d7924a52aba77a4cfadf133b7dd6f65555712c1aacaa66ed7a5503de06a2f86d
 
 package testtext
 
@@ -9,71 +11,64 @@ import "context"
 import "io"
 import "bytes"
 
+//line template.templ:3
 func BasicTemplate(name string) templ.Component {
+//line template_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "testtext.BasicTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<div>")
-		if err != nil {
-			return err
-		}
-		var_2 := `Name: `
-		_, err = templBuffer.WriteString(var_2)
-		if err != nil {
-			return err
-		}
-		var var_3 string = name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_3))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div><div>")
-		if err != nil {
-			return err
-		}
-		var_4 := `Text ` + "`" + `with backticks` + "`" + ``
-		_, err = templBuffer.WriteString(var_4)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div><div>")
-		if err != nil {
-			return err
-		}
-		var_5 := `Text ` + "`" + `with backtick`
-		_, err = templBuffer.WriteString(var_5)
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div><div>")
-		if err != nil {
-			return err
-		}
-		var_6 := `Text ` + "`" + `with backtick alongside variable: `
-		_, err = templBuffer.WriteString(var_6)
-		if err != nil {
-			return err
-		}
-		var var_7 string = name
-		_, err = templBuffer.WriteString(templ.EscapeString(var_7))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("</div>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<div>Name: ")
+			if err != nil {
+				return err
+			}
+			var var_2 string
+//line template.templ:4
+			var_2, err = templ.EscapeAny(name)
+//line template_templ.go:43
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text/template.templ", 4, 16)
+			}
+			_, err = templBuffer.WriteString(var_2)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div><div>Text `with backticks`</div><div>Text `with backtick</div><div>Text `with backtick alongside variable: ")
+			if err != nil {
+				return err
+			}
+			var var_3 string
+//line template.templ:7
+			var_3, err = templ.EscapeAny(name)
+//line template_templ.go:58
+			if err != nil {
+				return templ.WrapError(err, "generator/test-text/template.templ", 7, 50)
+			}
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</div>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
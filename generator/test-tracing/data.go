@@ -0,0 +1,25 @@
+package testtracing
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+type menu struct {
+	title string
+}
+
+var errPriceUnavailable = errors.New("price unavailable")
+
+func price(item string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if item == "" {
+			return errPriceUnavailable
+		}
+		_, err := io.WriteString(w, templ.EscapeString(item))
+		return err
+	})
+}
@@ -0,0 +1,80 @@
+package testtracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func tracer(events *[]string) templ.Tracer {
+	return func(name string) func(err error) {
+		*events = append(*events, "start "+name)
+		return func(err error) {
+			if err != nil {
+				*events = append(*events, fmt.Sprintf("end %s: %v", name, err))
+				return
+			}
+			*events = append(*events, "end "+name)
+		}
+	}
+}
+
+func Test(t *testing.T) {
+	t.Run("nested components are traced in the order they're rendered", func(t *testing.T) {
+		var events []string
+		ctx := templ.WithTracing(context.Background(), tracer(&events))
+		err := page(&menu{title: "Menu"}, []string{"Tea", "Cake"}).Render(ctx, new(bytes.Buffer))
+		if err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		expected := []string{
+			"start testtracing.page",
+			"start testtracing.(*menu).render",
+			"end testtracing.(*menu).render",
+			"start testtracing.list",
+			"start testtracing.row",
+			"end testtracing.row",
+			"start testtracing.row",
+			"end testtracing.row",
+			"end testtracing.list",
+			"end testtracing.page",
+		}
+		if diff := cmp.Diff(expected, events); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("errors are passed to the end function of each component they're returned from", func(t *testing.T) {
+		var events []string
+		ctx := templ.WithTracing(context.Background(), tracer(&events))
+		err := list([]string{"Tea", ""}).Render(ctx, new(bytes.Buffer))
+		if !errors.Is(err, errPriceUnavailable) {
+			t.Fatalf("expected the price error, got %v", err)
+		}
+		expected := []string{
+			"start testtracing.list",
+			"start testtracing.row",
+			"end testtracing.row",
+			"start testtracing.row",
+			fmt.Sprintf("end testtracing.row: %v", err),
+			fmt.Sprintf("end testtracing.list: %v", err),
+		}
+		if diff := cmp.Diff(expected, events); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("components aren't traced without a tracer", func(t *testing.T) {
+		b := new(bytes.Buffer)
+		if err := page(&menu{title: "Menu"}, []string{"Tea"}).Render(context.Background(), b); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		expected := `<main><nav>Menu</nav><ul><li>Tea</li></ul></main>`
+		if diff := cmp.Diff(expected, b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package testtypeswitch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		size     string
+		expected string
+	}{
+		{
+			name:     "nested switch case",
+			input:    1,
+			size:     "small",
+			expected: `<small>1</small>`,
+		},
+		{
+			name:     "nested switch default",
+			input:    2,
+			size:     "large",
+			expected: `<span>2</span>`,
+		},
+		{
+			name:     "type switch case",
+			input:    "a",
+			expected: `<span>a</span>`,
+		},
+		{
+			name:     "no matching case renders nothing",
+			input:    1.5,
+			expected: ``,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := render(tt.input, tt.size).Render(context.Background(), w)
+			if err != nil {
+				t.Errorf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// componentName returns the qualified name of the template that's passed to the tracer set
+// with templ.WithTracing, in the form used by the Go runtime, e.g. "main.page" for functions,
+// "main.Page.Title" for methods, and "main.(*Page).Title" for methods with pointer receivers.
+func componentName(pkg string, t parser.HTMLTemplate) string {
+	pkg = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(pkg), "package"))
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc "+t.Expression.Value+" {}", goparser.SkipObjectResolution)
+	if err != nil || len(f.Decls) != 1 {
+		// The Go compiler reports the error in the expression, so the name only needs to be
+		// readable.
+		name, _, _ := strings.Cut(t.Expression.Value, "(")
+		return pkg + "." + strings.TrimSpace(name)
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return pkg + "." + strings.TrimSpace(t.Expression.Value)
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return pkg + "." + fn.Name.Name
+	}
+	return pkg + "." + receiverName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+}
+
+// receiverName returns the name of the type of a receiver, without its type parameters,
+// e.g. "(*Page)" for "*Page[T]".
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return "(*" + receiverName(expr.X) + ")"
+	case *ast.ParenExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
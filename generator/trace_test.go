@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestComponentName(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{
+			expression: "page(title string)",
+			expected:   "main.page",
+		},
+		{
+			expression: "List[T any](items []T)",
+			expected:   "main.List",
+		},
+		{
+			expression: "(p Page) Title(name string)",
+			expected:   "main.Page.Title",
+		},
+		{
+			expression: "(p *Page) Title()",
+			expected:   "main.(*Page).Title",
+		},
+		{
+			expression: "(l *List[T]) Render()",
+			expected:   "main.(*List).Render",
+		},
+		{
+			expression: "broken(",
+			expected:   "main.broken",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			actual := componentName("package main", parser.HTMLTemplate{
+				Expression: parser.Expression{Value: tt.expression},
+			})
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
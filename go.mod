@@ -11,13 +11,17 @@ require (
 	github.com/a-h/protocol v0.0.0-20230224160810-b4eec67c1c22
 	github.com/cenkalti/backoff/v4 v4.2.1
 	github.com/cli/browser v1.2.0
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/google/go-cmp v0.5.9
 	github.com/natefinch/atomic v1.0.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/rs/cors v1.8.3
 	go.lsp.dev/jsonrpc2 v0.10.0
 	go.lsp.dev/uri v0.3.0
 	go.uber.org/zap v1.24.0
 	golang.org/x/mod v0.8.0
+	golang.org/x/net v0.9.0
+	golang.org/x/tools v0.1.12
 )
 
 require (
@@ -27,7 +31,6 @@ require (
 	go.lsp.dev/pkg v0.0.0-20210717090340-384b27a52fb2 // indirect
 	go.uber.org/atomic v1.10.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
-	golang.org/x/net v0.9.0 // indirect
 	golang.org/x/sys v0.8.0 // indirect
 )
 
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+)
+
+var htmlCommentStart = parse.String("<!--")
+var htmlCommentEnd = parse.String("-->")
+
+// <!-- ... -->
+var htmlComment = parse.Func(func(pi *parse.Input) (c HTMLComment, ok bool, err error) {
+	// Comment start.
+	var from = pi.Position()
+	if _, ok, err = htmlCommentStart.Parse(pi); err != nil || !ok {
+		return
+	}
+
+	// Once we've got the comment start sequence, parse anything until the end
+	// sequence as the comment contents.
+	if c.Contents, ok, err = parse.StringUntil(htmlCommentEnd).Parse(pi); err != nil || !ok {
+		err = parse.Error("expected end comment literal '-->' not found", from)
+		return
+	}
+	// Cut the end element.
+	_, _, _ = htmlCommentEnd.Parse(pi)
+
+	return c, true, nil
+})
+
+var goSingleLineCommentStart = parse.String("//")
+var goSingleLineCommentEnd = parse.Any(parse.NewLine, parse.EOF[string]())
+
+var goMultiLineCommentStart = parse.String("/*")
+var goMultiLineCommentEnd = parse.String("*/")
+
+// // ...
+// /* ... */
+var goComment = parse.Func(func(pi *parse.Input) (c GoComment, ok bool, err error) {
+	var from = pi.Position()
+	if _, ok, err = goSingleLineCommentStart.Parse(pi); err != nil {
+		return
+	}
+	if ok {
+		if c.Contents, ok, err = parse.StringUntil(goSingleLineCommentEnd).Parse(pi); err != nil || !ok {
+			return
+		}
+		return c, true, nil
+	}
+	if _, ok, err = goMultiLineCommentStart.Parse(pi); err != nil || !ok {
+		return
+	}
+	if c.Contents, ok, err = parse.StringUntil(goMultiLineCommentEnd).Parse(pi); err != nil || !ok {
+		err = parse.Error("expected end comment literal '*/' not found", from)
+		return
+	}
+	// Cut the end element.
+	_, _, _ = goMultiLineCommentEnd.Parse(pi)
+	c.Multiline = true
+	return c, true, nil
+})
+
+// Comments between attributes.
+var commentAttributeParser = parse.Func(func(pi *parse.Input) (ca CommentAttribute, ok bool, err error) {
+	start := pi.Index()
+
+	// Optional whitespace leader.
+	if _, ok, err = parse.OptionalWhitespace.Parse(pi); err != nil || !ok {
+		return
+	}
+
+	if ca.Comment, ok, err = goComment.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+
+	return ca, true, nil
+})
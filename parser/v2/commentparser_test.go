@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHTMLCommentParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected HTMLComment
+	}{
+		{
+			name:  "comment - single line",
+			input: `<!-- single line comment -->`,
+			expected: HTMLComment{
+				Contents: " single line comment ",
+			},
+		},
+		{
+			name:  "comment - no whitespace",
+			input: `<!--no whitespace between sequence open and close-->`,
+			expected: HTMLComment{
+				Contents: "no whitespace between sequence open and close",
+			},
+		},
+		{
+			name: "comment - multiline",
+			input: `<!-- multiline
+								comment
+					-->`,
+			expected: HTMLComment{
+				Contents: ` multiline
+								comment
+					`,
+			},
+		},
+		{
+			name:  "comment - with tag",
+			input: `<!-- <p class="test">tag</p> -->`,
+			expected: HTMLComment{
+				Contents: ` <p class="test">tag</p> `,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := htmlComment.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+func TestHTMLCommentParserErrors(t *testing.T) {
+	input := parse.NewInput(`<!-- unclosed HTML comment`)
+	_, _, err := htmlComment.Parse(input)
+	if err == nil {
+		t.Fatal("expected an error for an unclosed comment")
+	}
+}
+
+func TestGoCommentParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected GoComment
+	}{
+		{
+			name:  "single line comment",
+			input: "// single line comment\n",
+			expected: GoComment{
+				Contents: " single line comment",
+			},
+		},
+		{
+			name:  "single line comment at the end of the file",
+			input: "// single line comment",
+			expected: GoComment{
+				Contents: " single line comment",
+			},
+		},
+		{
+			name:  "multiline comment",
+			input: "/* multiline\ncomment */",
+			expected: GoComment{
+				Contents:  " multiline\ncomment ",
+				Multiline: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := goComment.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
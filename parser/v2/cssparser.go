@@ -172,17 +172,61 @@ var expressionCSSPropertyParser = parse.Func(func(pi *parse.Input) (r Expression
 	}
 
 	// ;
-	if _, ok, err = Must(parse.String(";"), "missing expected semicolon (;)").Parse(pi); err != nil || !ok {
-		return
-	}
-	// \n
-	if _, ok, err = Must(parse.NewLine, "missing expected linebreak").Parse(pi); err != nil || !ok {
+	if _, ok, err = Must(cssPropertyEnd, "missing expected semicolon (;)").Parse(pi); err != nil || !ok {
 		return
 	}
 
 	return r, true, nil
 })
 
+// cssPropertyEnd is the semicolon at the end of a declaration, with optional surrounding
+// spaces. Multiple declarations can be on the same line.
+var cssPropertyEnd = parse.All(
+	optionalSpacesOrTabs,
+	parse.Rune(';'),
+	optionalSpacesOrTabs,
+	parse.StringFrom(parse.Optional(parse.NewLine)),
+)
+
+var optionalSpacesOrTabs = parse.StringFrom(parse.Optional(parse.AtLeast(1, parse.RuneIn(" \t"))))
+
+// cssConstantValueParser reads a CSS value up to the semicolon that ends the declaration.
+// Semicolons within quotes or brackets are part of the value, e.g. url('data:image/png;base64,...').
+var cssConstantValueParser = parse.Func(func(pi *parse.Input) (value string, ok bool, err error) {
+	start := pi.Index()
+	var quote rune
+	var depth int
+	for {
+		c, ok := pi.Peek(1)
+		if !ok {
+			pi.Seek(start)
+			return "", false, nil
+		}
+		r := rune(c[0])
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ';' && depth == 0:
+			end := pi.Index()
+			pi.Seek(start)
+			value, _ = pi.Take(end - start)
+			return strings.TrimRight(value, " \t\r\n"), true, nil
+		case r == '}' && depth == 0:
+			pi.Seek(start)
+			return "", false, nil
+		}
+		pi.Take(1)
+	}
+})
+
 // background-color: #ffffff;
 var constantCSSPropertyParser = parse.Func(func(pi *parse.Input) (r ConstantCSSProperty, ok bool, err error) {
 	start := pi.Index()
@@ -202,18 +246,13 @@ var constantCSSPropertyParser = parse.Func(func(pi *parse.Input) (r ConstantCSSP
 		return
 	}
 
-	// Everything until ';\n'
-	untilEnd := parse.All(
-		parse.OptionalWhitespace,
-		parse.Rune(';'),
-		parse.NewLine,
-	)
-	if r.Value, ok, err = Must(parse.StringUntil(untilEnd), "missing expected semicolon and linebreak (;\\n").Parse(pi); err != nil || !ok {
+	// Everything until ';'
+	if r.Value, ok, err = Must(cssConstantValueParser, "missing expected semicolon (;)").Parse(pi); err != nil || !ok {
 		return
 	}
 
-	// Chomp the ;\n
-	if _, ok, err = Must(untilEnd, "failed to chomp semicolon and linebreak (;\\n)").Parse(pi); err != nil || !ok {
+	// Chomp the ;
+	if _, ok, err = Must(cssPropertyEnd, "missing expected semicolon (;)").Parse(pi); err != nil || !ok {
 		return
 	}
 
@@ -94,6 +94,22 @@ func TestConstantCSSPropertyParser(t *testing.T) {
 				Value: "#ffffff",
 			},
 		},
+		{
+			name:  "css: semicolons within quotes and brackets are part of the value",
+			input: `background-image: url('data:image/png;base64,AAAA') ;`,
+			expected: ConstantCSSProperty{
+				Name:  "background-image",
+				Value: "url('data:image/png;base64,AAAA')",
+			},
+		},
+		{
+			name:  "css: whitespace before the colon",
+			input: `color :red;`,
+			expected: ConstantCSSProperty{
+				Name:  "color",
+				Value: "red",
+			},
+		},
 		{
 			name:  "css: single constant webkit property",
 			input: `-webkit-text-stroke-color: #ffffff;`,
@@ -202,6 +218,38 @@ background-color: #ffffff;
 				},
 			},
 		},
+		{
+			name: "css: multiple properties on the same line",
+			input: `css Name() {
+	color: red;  background-color: blue; }`,
+			expected: CSSTemplate{
+				Name: Expression{
+					Value: "Name",
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 8,
+							Line:  0,
+							Col:   8,
+						},
+					},
+				},
+				Properties: []CSSProperty{
+					ConstantCSSProperty{
+						Name:  "color",
+						Value: "red",
+					},
+					ConstantCSSProperty{
+						Name:  "background-color",
+						Value: "blue",
+					},
+				},
+			},
+		},
 		{
 			name: "css: single expression property",
 			input: `css Name() {
@@ -265,3 +313,34 @@ background-color: { constants.BackgroundColor };
 		})
 	}
 }
+
+func TestCSSParserErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected error
+	}{
+		{
+			name: "css: missing semicolon",
+			input: `css Name() {
+	color: red
+}`,
+			expected: parse.Error("missing expected semicolon (;)",
+				parse.Position{
+					Index: 21,
+					Line:  1,
+					Col:   8,
+				}),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			_, _, err := cssParser.Parse(input)
+			if diff := cmp.Diff(tt.expected, err); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/a-h/parse"
+)
+
+// CodeParseError is the Code of the diagnostics of parse errors. Validation issues have the
+// name of the check that found them, e.g. "no-alt".
+const CodeParseError = "parse-error"
+
+// Diagnostic is a problem found in a templ file, e.g. a parse error or a validation issue. It's
+// the form that the problems are reported in by templ generate, fmt and lint, and the language
+// server, so that they have the same ranges, severities, codes and messages.
+type Diagnostic struct {
+	// FileName of the templ file, which may be empty.
+	FileName string
+	// Range of the problem. Errors that are found at a position have an empty range.
+	Range    Range
+	Severity Severity
+	// Code is the kind of problem, e.g. "parse-error", or the name of a check, e.g. "no-alt".
+	Code    string
+	Message string
+}
+
+// DiagnoseOption configures Diagnose.
+type DiagnoseOption func(*diagnoseOptions)
+
+type diagnoseOptions struct {
+	validate bool
+}
+
+// WithValidation adds the issues found by Validate to the diagnostics of files that are
+// parsed without errors.
+func WithValidation() DiagnoseOption {
+	return func(o *diagnoseOptions) {
+		o.validate = true
+	}
+}
+
+// Diagnose parses the templ file, and returns a diagnostic for each parse error. The parser
+// recovers from errors within a declaration, so the errors in later declarations are returned
+// too. The fileName is set on each diagnostic.
+func Diagnose(src string, fileName string, opts ...DiagnoseOption) (diagnostics []Diagnostic) {
+	var o diagnoseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tf, err := ParseString(src)
+	if err != nil {
+		if diagnostics, ok := ParseErrorDiagnostics(fileName, err); ok {
+			return diagnostics
+		}
+		return []Diagnostic{{FileName: fileName, Severity: SeverityError, Code: CodeParseError, Message: err.Error()}}
+	}
+	if o.validate {
+		for _, issue := range Validate(tf) {
+			diagnostics = append(diagnostics, issue.Diagnostic(fileName))
+		}
+	}
+	return diagnostics
+}
+
+// ParseErrorDiagnostics returns a diagnostic for each of the parse errors in the error returned
+// by Parse, ParseString or ParseFile. It returns false if the error isn't a parse error, e.g.
+// if the file couldn't be read.
+func ParseErrorDiagnostics(fileName string, err error) (diagnostics []Diagnostic, ok bool) {
+	var errs ParseErrors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			diagnostics = append(diagnostics, parseErrorDiagnostic(fileName, e.ParseError, e.To))
+		}
+		return diagnostics, true
+	}
+	var pe ParseError
+	if errors.As(err, &pe) {
+		return []Diagnostic{parseErrorDiagnostic(fileName, pe.ParseError, pe.To)}, true
+	}
+	var ppe parse.ParseError
+	if errors.As(err, &ppe) {
+		return []Diagnostic{parseErrorDiagnostic(fileName, ppe, ppe.Pos)}, true
+	}
+	if errors.Is(err, ErrLegacyFileFormat) {
+		return []Diagnostic{{FileName: fileName, Severity: SeverityError, Code: CodeParseError, Message: ErrLegacyFileFormat.Error()}}, true
+	}
+	return nil, false
+}
+
+func parseErrorDiagnostic(fileName string, pe parse.ParseError, to parse.Position) Diagnostic {
+	if to.Index < pe.Pos.Index {
+		to = pe.Pos
+	}
+	return Diagnostic{
+		FileName: fileName,
+		Range:    NewRange(pe.Pos, to),
+		Severity: SeverityError,
+		Code:     CodeParseError,
+		Message:  pe.Msg,
+	}
+}
+
+// Diagnostic returns the issue as a diagnostic of the file.
+func (i Issue) Diagnostic(fileName string) Diagnostic {
+	return Diagnostic{
+		FileName: fileName,
+		Range:    i.Range,
+		Severity: i.Severity,
+		Code:     i.Check,
+		Message:  i.Message,
+	}
+}
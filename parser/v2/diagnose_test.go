@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []DiagnoseOption
+		expected []Diagnostic
+	}{
+		{
+			name:  "valid files have no diagnostics",
+			input: "package main\n\ntempl Page() {\n\t<img src=\"a.png\"/>\n}\n",
+		},
+		{
+			name:  "each parse error is returned, with its range",
+			input: "package main\n\ntempl A() {\n\t<a></b>\n}\n\ntempl B() {\n\t<div data-x!=\"1\"></div>\n}\n",
+			expected: []Diagnostic{
+				{
+					FileName: "x.templ",
+					Range:    NewRange(parse.Position{Index: 30, Line: 3, Col: 4}, parse.Position{Index: 30, Line: 3, Col: 4}),
+					Severity: SeverityError,
+					Code:     CodeParseError,
+					Message:  "<a>: mismatched end tag, expected '</a>', got '</b>'",
+				},
+				{
+					FileName: "x.templ",
+					Range:    NewRange(parse.Position{Index: 56, Line: 7, Col: 6}, parse.Position{Index: 63, Line: 7, Col: 13}),
+					Severity: SeverityError,
+					Code:     CodeParseError,
+					Message:  `invalid attribute name "data-x!": found '!', but attribute names can only contain letters, digits, '-', '.', ':', '_' and '@'`,
+				},
+			},
+		},
+		{
+			name:  "a missing package is a parse error at the start of the file",
+			input: "templ A() {\n}\n",
+			expected: []Diagnostic{
+				{
+					FileName: "x.templ",
+					Severity: SeverityError,
+					Code:     CodeParseError,
+					Message:  ErrMissingPackage.Msg,
+				},
+			},
+		},
+		{
+			name:  "validation issues are only returned if requested",
+			input: "package main\n\ntempl Page() {\n\t<img src=\"a.png\"/>\n}\n",
+			opts:  []DiagnoseOption{WithValidation()},
+			expected: []Diagnostic{
+				{
+					FileName: "x.templ",
+					Range:    NewRange(parse.Position{Index: 31, Line: 3, Col: 2}, parse.Position{Index: 34, Line: 3, Col: 5}),
+					Severity: SeverityWarning,
+					Code:     CheckNoAlt,
+					Message:  `<img>: missing alt attribute, use alt="" for decorative images`,
+				},
+			},
+		},
+		{
+			name:  "files with parse errors aren't validated",
+			input: "package main\n\ntempl Page() {\n\t<img src=\"a.png\"/>\n\t<a></b>\n}\n",
+			opts:  []DiagnoseOption{WithValidation()},
+			expected: []Diagnostic{
+				{
+					FileName: "x.templ",
+					Range:    NewRange(parse.Position{Index: 53, Line: 4, Col: 4}, parse.Position{Index: 53, Line: 4, Col: 4}),
+					Severity: SeverityError,
+					Code:     CodeParseError,
+					Message:  "<a>: mismatched end tag, expected '</a>', got '</b>'",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Diagnose(tt.input, "x.templ", tt.opts...)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
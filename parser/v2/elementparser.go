@@ -2,8 +2,9 @@ package parser
 
 import (
 	"fmt"
-	"html"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/a-h/parse"
 )
@@ -13,6 +14,7 @@ import (
 // Element open tag.
 type elementOpenTag struct {
 	Name       string
+	NameRange  Range
 	Attributes []Attribute
 }
 
@@ -25,10 +27,12 @@ var elementOpenTagParser = parse.Func(func(pi *parse.Input) (e elementOpenTag, o
 	}
 
 	// Element name.
-	if e.Name, ok, err = elementNameParser.Parse(pi); err != nil || !ok {
+	var name Expression
+	if name, ok, err = elementNameExpression.Parse(pi); err != nil || !ok {
 		pi.Seek(start)
 		return
 	}
+	e.Name, e.NameRange = name.Value, name.Range
 
 	if e.Attributes, ok, err = (attributesParser{}).Parse(pi); err != nil || !ok {
 		pi.Seek(start)
@@ -46,7 +50,12 @@ var elementOpenTagParser = parse.Func(func(pi *parse.Input) (e elementOpenTag, o
 		return
 	}
 	if !ok {
-		err = parse.Error(fmt.Sprintf("<%s>: malformed open element", e.Name), pi.Position())
+		// Report names that can't start an attribute, e.g. <div $x="1">.
+		from := pi.Position()
+		if msg := checkAttributeName(scanName(pi, attributeNameEnd)); msg != "" {
+			return e, false, newRangeError(msg, from, pi.Position())
+		}
+		err = parse.Error(fmt.Sprintf("<%s>: malformed open element", e.Name), from)
 		return e, false, err
 	}
 
@@ -75,25 +84,56 @@ var elementCloseTagParser = parse.Func(func(in *parse.Input) (ect elementCloseTa
 var (
 	attributeNameFirst      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ:_@"
 	attributeNameSubsequent = attributeNameFirst + "-.0123456789"
-	attributeNameParser     = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
-		start := in.Index()
-		var prefix, suffix string
-		if prefix, ok, err = parse.RuneIn(attributeNameFirst).Parse(in); err != nil || !ok {
-			return
+	// attributeNameEnd are the characters, other than whitespace, that end an attribute name.
+	attributeNameEnd    = "=?>/{}"
+	attributeNameParser = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
+		start := in.Position()
+		if name = scanName(in, attributeNameEnd); name == "" || !strings.ContainsRune(attributeNameFirst, rune(name[0])) {
+			in.Seek(start.Index)
+			return "", false, nil
 		}
-		if suffix, ok, err = parse.StringUntil(parse.RuneNotIn(attributeNameSubsequent)).Parse(in); err != nil {
-			in.Seek(start)
-			return
-		}
-		if len(suffix)+1 > 128 {
-			ok = false
-			err = parse.Error("attribute names must be < 128 characters long", in.Position())
-			return
+		if msg := checkAttributeName(name); msg != "" {
+			return name, false, newRangeError(msg, start, in.Position())
 		}
-		return prefix + suffix, true, nil
+		return name, true, nil
 	})
 )
 
+// checkAttributeName returns a description of the problem with an attribute name, or an
+// empty string if it's valid. Names such as data-*, aria-*, hx-on:click and @click are valid.
+func checkAttributeName(name string) (msg string) {
+	if name == "" {
+		return ""
+	}
+	if !strings.ContainsRune(attributeNameFirst, rune(name[0])) {
+		r, _ := utf8.DecodeRuneInString(name)
+		return fmt.Sprintf("invalid attribute name %q: found %q, but attribute names must start with a letter, ':', '_' or '@'", name, r)
+	}
+	if i := strings.IndexFunc(name, func(r rune) bool { return !strings.ContainsRune(attributeNameSubsequent, r) }); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(name[i:])
+		return fmt.Sprintf("invalid attribute name %q: found %q, but attribute names can only contain letters, digits, '-', '.', ':', '_' and '@'", name, r)
+	}
+	if len(name) > 128 {
+		return "attribute names must be < 128 characters long"
+	}
+	return ""
+}
+
+// scanName reads a name from the input, up to whitespace, one of the end characters, or
+// the end of the input. Characters that aren't allowed in names are included, so that the
+// whole of an invalid name can be reported.
+func scanName(in *parse.Input, end string) (name string) {
+	rest, _ := in.Peek(-1)
+	i := strings.IndexFunc(rest, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(end, r)
+	})
+	if i < 0 {
+		i = len(rest)
+	}
+	name, _ = in.Take(i)
+	return name
+}
+
 // Constant attribute.
 var (
 	attributeConstantValueParser            = parse.StringUntil(parse.Rune('"'))
@@ -132,8 +172,6 @@ var (
 			return
 		}
 
-		attr.Value = html.UnescapeString(attr.Value)
-
 		// " - closing quote.
 		if _, ok, err = Must(closeParser, fmt.Sprintf("missing closing quote on attribute %q", attr.Name)).Parse(pi); err != nil || !ok {
 			pi.Seek(start)
@@ -252,16 +290,62 @@ var expressionAttributeParser = parse.Func(func(pi *parse.Input) (attr Expressio
 	return attr, true, nil
 })
 
+// SpreadAttributes.
+var spreadAttributesParser = parse.Func(func(pi *parse.Input) (attr SpreadAttributes, ok bool, err error) {
+	start := pi.Index()
+
+	// Optional whitespace leader.
+	if _, ok, err = parse.OptionalWhitespace.Parse(pi); err != nil || !ok {
+		return
+	}
+
+	// {
+	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+
+	// Expression, followed by ...
+	if attr.Expression, ok, err = exp.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+	value := strings.TrimRight(attr.Expression.Value, " ")
+	if !strings.HasSuffix(value, "...") {
+		err = parse.Error("spread attributes: expected '...' after the attributes expression", pi.Position())
+		return attr, false, err
+	}
+	value = strings.TrimSuffix(value, "...")
+	trimmed := len(attr.Expression.Value) - len(value)
+	attr.Expression.Value = value
+	attr.Expression.Range.To.Index -= int64(trimmed)
+	attr.Expression.Range.To.Col -= uint32(trimmed)
+
+	// Eat the final brace.
+	if _, ok, err = Must(closeBraceWithOptionalPadding, "spread attributes: missing closing brace").Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+
+	return attr, true, nil
+})
+
 // Attributes.
 type attributeParser struct{}
 
 func (attributeParser) Parse(in *parse.Input) (out Attribute, ok bool, err error) {
+	if out, ok, err = commentAttributeParser.Parse(in); err != nil || ok {
+		return
+	}
 	if out, ok, err = boolExpressionAttributeParser.Parse(in); err != nil || ok {
 		return
 	}
 	if out, ok, err = expressionAttributeParser.Parse(in); err != nil || ok {
 		return
 	}
+	if out, ok, err = spreadAttributesParser.Parse(in); err != nil || ok {
+		return
+	}
 	if out, ok, err = conditionalAttributeParser.Parse(in); err != nil || ok {
 		return
 	}
@@ -294,41 +378,66 @@ func (attributesParser) Parse(in *parse.Input) (attributes []Attribute, ok bool,
 
 // Element name.
 var (
-	elementNameFirst      = "abcdefghijklmnopqrstuvwxyz"
-	elementNameSubsequent = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-"
-	elementNameParser     = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
-		start := in.Index()
-		var prefix, suffix string
-		if prefix, ok, err = parse.RuneIn(elementNameFirst).Parse(in); err != nil || !ok {
-			return
+	elementNameLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	elementNameAllowed = elementNameLetters + "0123456789-._"
+	// elementNameEnd are the characters, other than whitespace, that end an element name.
+	elementNameEnd    = ">/"
+	elementNameParser = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
+		start := in.Position()
+		if name = scanName(in, elementNameEnd); name == "" || !strings.ContainsRune(elementNameLetters, rune(name[0])) {
+			in.Seek(start.Index)
+			return "", false, nil
 		}
-		if suffix, ok, err = parse.StringUntil(parse.RuneNotIn(elementNameSubsequent)).Parse(in); err != nil || !ok {
-			in.Seek(start)
-			return
+		if msg := checkElementName(name); msg != "" {
+			return name, false, newRangeError(msg, start, in.Position())
 		}
-		if len(suffix)+1 > 16 {
-			ok = false
-			err = parse.Error("element property names must be < 16 characters long", in.Position())
-			return
-		}
-		return prefix + suffix, true, nil
+		return name, true, nil
 	})
+	elementNameExpression = ExpressionOf(elementNameParser)
 )
 
+// checkElementName returns a description of the problem with an element name, or an empty
+// string if it's valid. HTML, SVG and MathML element names contain only letters and digits.
+// Custom element names must contain a hyphen, and can also contain dots and underscores.
+func checkElementName(name string) (msg string) {
+	if i := strings.IndexFunc(name, func(r rune) bool { return !strings.ContainsRune(elementNameAllowed, r) }); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(name[i:])
+		return fmt.Sprintf("invalid element name %q: found %q, but element names can only contain letters and digits, and custom element names can also contain '-', '.' and '_'", name, r)
+	}
+	if i := strings.IndexAny(name, "._"); i >= 0 && !strings.Contains(name, "-") {
+		return fmt.Sprintf("invalid element name %q: found %q, which is only allowed in custom element names, and they must contain a '-', e.g. my-element", name, name[i])
+	}
+	if len(name) > 128 {
+		return "element names must be < 128 characters long"
+	}
+	return ""
+}
+
 // Element.
 var elementOpenClose elementOpenCloseParser
 
 type elementOpenCloseParser struct{}
 
 func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err error) {
+	start := pi.Position()
+
 	// Check the open tag.
 	var ot elementOpenTag
 	if ot, ok, err = elementOpenTagParser.Parse(pi); err != nil || !ok {
 		return
 	}
 	r.Name = ot.Name
+	r.NameRange = ot.NameRange
 	r.Attributes = ot.Attributes
 
+	// Void elements, e.g. <br>, don't have children or an end tag.
+	if r.IsVoidElement() {
+		if err = voidElementEnd(pi, r.Name, start); err != nil {
+			return r, false, err
+		}
+		return r, true, nil
+	}
+
 	// Once we've got an open tag, the rest must be present.
 	if r.Children, ok, err = newTemplateNodeParser[any](nil, "").Parse(pi); err != nil || !ok {
 		return
@@ -353,6 +462,35 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 	return r, true, nil
 }
 
+// voidElementEnd accepts an end tag that immediately follows the open tag of a void
+// element, e.g. <br></br>, and returns an error if the element has contents.
+func voidElementEnd(pi *parse.Input, name string, start parse.Position) (err error) {
+	afterOpenTag := pi.Index()
+	var ct elementCloseTag
+	var ok bool
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	if ct, ok, err = elementCloseTagParser.Parse(pi); err != nil || (ok && ct.Name == name) {
+		return
+	}
+	pi.Seek(afterOpenTag)
+
+	// Check for contents, e.g. <br>text</br>.
+	if _, _, err = parse.StringUntil(lt).Parse(pi); err != nil {
+		return
+	}
+	ct, ok, err = elementCloseTagParser.Parse(pi)
+	pi.Seek(afterOpenTag)
+	if err != nil {
+		return
+	}
+	if ok && ct.Name == name {
+		return parse.Error(fmt.Sprintf("<%s>: void element cannot have children", name), start)
+	}
+	return nil
+}
+
 // Element self-closing tag.
 var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, err error) {
 	start := pi.Index()
@@ -363,10 +501,12 @@ var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, e
 	}
 
 	// Element name.
-	if e.Name, ok, err = elementNameParser.Parse(pi); err != nil || !ok {
+	var name Expression
+	if name, ok, err = elementNameExpression.Parse(pi); err != nil || !ok {
 		pi.Seek(start)
 		return
 	}
+	e.Name, e.NameRange = name.Value, name.Range
 
 	if e.Attributes, ok, err = (attributesParser{}).Parse(pi); err != nil || !ok {
 		pi.Seek(start)
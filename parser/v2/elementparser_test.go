@@ -23,6 +23,18 @@ func TestAttributeParser(t *testing.T) {
 			parser: StripType(elementOpenTagParser),
 			expected: elementOpenTag{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 			},
 		},
 		{
@@ -31,6 +43,18 @@ func TestAttributeParser(t *testing.T) {
 			parser: StripType(elementOpenTagParser),
 			expected: elementOpenTag{
 				Name: "turbo-frame",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 12,
+						Line:  0,
+						Col:   12,
+					},
+				},
 			},
 		},
 		{
@@ -39,6 +63,18 @@ func TestAttributeParser(t *testing.T) {
 			parser: StripType(elementOpenTagParser),
 			expected: elementOpenTag{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "_",
@@ -53,6 +89,18 @@ func TestAttributeParser(t *testing.T) {
 			parser: StripType(elementOpenTagParser),
 			expected: elementOpenTag{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "@click",
@@ -71,6 +119,18 @@ func TestAttributeParser(t *testing.T) {
 			parser: StripType(elementOpenTagParser),
 			expected: elementOpenTag{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "id",
@@ -115,6 +175,66 @@ func TestAttributeParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "conditional expression attribute - nested with else",
+			input: `
+if a {
+	if b {
+		x="1"
+	} else {
+		y
+	}
+}
+"`,
+			parser: StripType(conditionalAttributeParser),
+			expected: ConditionalAttribute{
+				Expression: Expression{
+					Value: "a",
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  1,
+							Col:   3,
+						},
+						To: Position{
+							Index: 5,
+							Line:  1,
+							Col:   4,
+						},
+					},
+				},
+				Then: []Attribute{
+					ConditionalAttribute{
+						Expression: Expression{
+							Value: "b",
+							Range: Range{
+								From: Position{
+									Index: 12,
+									Line:  2,
+									Col:   4,
+								},
+								To: Position{
+									Index: 13,
+									Line:  2,
+									Col:   5,
+								},
+							},
+						},
+						Then: []Attribute{
+							ConstantAttribute{
+								Name:  "x",
+								Value: "1",
+							},
+						},
+						Else: []Attribute{
+							BoolConstantAttribute{
+								Name: "y",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "conditional expression attribute - multiple",
 			input: `
@@ -239,6 +359,50 @@ if test {
 				},
 			},
 		},
+		{
+			name:   "attribute parsing handles spread attributes",
+			input:  ` { attrs... }`,
+			parser: StripType[Attribute](attributeParser{}),
+			expected: SpreadAttributes{
+				Expression: Expression{
+					Value: "attrs",
+					Range: Range{
+						From: Position{
+							Index: 3,
+							Line:  0,
+							Col:   3,
+						},
+						To: Position{
+							Index: 8,
+							Line:  0,
+							Col:   8,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "spread attributes without padding",
+			input:  ` {p.Attrs()...}`,
+			parser: StripType(spreadAttributesParser),
+			expected: SpreadAttributes{
+				Expression: Expression{
+					Value: "p.Attrs()",
+					Range: Range{
+						From: Position{
+							Index: 2,
+							Line:  0,
+							Col:   2,
+						},
+						To: Position{
+							Index: 11,
+							Line:  0,
+							Col:   11,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:   "constant attribute",
 			input:  ` href="test"`,
@@ -293,6 +457,18 @@ if test {
 			parser: StripType(elementOpenTagParser),
 			expected: elementOpenTag{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "data",
@@ -306,7 +482,7 @@ if test {
 			parser: StripType(constantAttributeParser),
 			expected: ConstantAttribute{
 				Name:  "href",
-				Value: `<">`,
+				Value: `&lt;&quot;&gt;`,
 			},
 		},
 	}
@@ -339,6 +515,18 @@ func TestElementParser(t *testing.T) {
 			input: `<a href="test"/>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "href",
@@ -352,6 +540,18 @@ func TestElementParser(t *testing.T) {
 			input: `<hr noshade?={ true }/>`,
 			expected: Element{
 				Name: "hr",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
 				Attributes: []Attribute{
 					BoolExpressionAttribute{
 						Name: "noshade",
@@ -379,6 +579,18 @@ func TestElementParser(t *testing.T) {
 			input: `<a href={ "test" }/>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Attributes: []Attribute{
 					ExpressionAttribute{
 						Name: "href",
@@ -406,6 +618,18 @@ func TestElementParser(t *testing.T) {
 			input: `<a href="test" style="text-underline: auto"/>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "href",
@@ -423,6 +647,18 @@ func TestElementParser(t *testing.T) {
 			input: `<hr optionA optionB?={ true } optionC="other"/>`,
 			expected: Element{
 				Name: "hr",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "optionA",
@@ -457,6 +693,18 @@ func TestElementParser(t *testing.T) {
 			input: `<a href="test" title={ localisation.Get("a_title") } style="text-underline: auto"/>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "href",
@@ -499,6 +747,18 @@ func TestElementParser(t *testing.T) {
 `,
 			expected: Element{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -540,6 +800,18 @@ func TestElementParser(t *testing.T) {
 			input: `<hr/>`,
 			expected: Element{
 				Name: "hr",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
 			},
 		},
 		{
@@ -547,6 +819,18 @@ func TestElementParser(t *testing.T) {
 			input: `<hr style="padding: 10px" />`,
 			expected: Element{
 				Name: "hr",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -564,6 +848,18 @@ func TestElementParser(t *testing.T) {
 />`,
 			expected: Element{
 				Name: "hr",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -606,6 +902,18 @@ func TestElementParser(t *testing.T) {
 />`,
 			expected: Element{
 				Name: "hr",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -652,6 +960,18 @@ func TestElementParser(t *testing.T) {
 >Test</p>`,
 			expected: Element{
 				Name: "p",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -691,6 +1011,18 @@ func TestElementParser(t *testing.T) {
 			input: `<a></a>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 			},
 		},
 		{
@@ -698,6 +1030,18 @@ func TestElementParser(t *testing.T) {
 			input: `<a>The text</a>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Children: []Node{
 					Text{
 						Value: "The text",
@@ -710,9 +1054,33 @@ func TestElementParser(t *testing.T) {
 			input: `<a><b/></a>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Children: []Node{
 					Element{
 						Name: "b",
+						NameRange: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+						},
 					},
 				},
 			},
@@ -722,9 +1090,33 @@ func TestElementParser(t *testing.T) {
 			input: `<a><b></b></a>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Children: []Node{
 					Element{
 						Name: "b",
+						NameRange: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+						},
 					},
 				},
 			},
@@ -734,10 +1126,34 @@ func TestElementParser(t *testing.T) {
 			input: `<a> <b> </b> </a>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Children: []Node{
 					Whitespace{Value: " "},
 					Element{
 						Name: "b",
+						NameRange: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 6,
+								Line:  0,
+								Col:   6,
+							},
+						},
 						Children: []Node{
 							Whitespace{Value: " "},
 						},
@@ -751,15 +1167,63 @@ func TestElementParser(t *testing.T) {
 			input: `<a><b></b><c><d/></c></a>`,
 			expected: Element{
 				Name: "a",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
 				Children: []Node{
 					Element{
 						Name: "b",
+						NameRange: Range{
+							From: Position{
+								Index: 4,
+								Line:  0,
+								Col:   4,
+							},
+							To: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+						},
 					},
 					Element{
 						Name: "c",
+						NameRange: Range{
+							From: Position{
+								Index: 11,
+								Line:  0,
+								Col:   11,
+							},
+							To: Position{
+								Index: 12,
+								Line:  0,
+								Col:   12,
+							},
+						},
 						Children: []Node{
 							Element{
 								Name: "d",
+								NameRange: Range{
+									From: Position{
+										Index: 14,
+										Line:  0,
+										Col:   14,
+									},
+									To: Position{
+										Index: 15,
+										Line:  0,
+										Col:   15,
+									},
+								},
 							},
 						},
 					},
@@ -771,6 +1235,18 @@ func TestElementParser(t *testing.T) {
 			input: `<div></div>`,
 			expected: Element{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 			},
 		},
 		{
@@ -778,6 +1254,18 @@ func TestElementParser(t *testing.T) {
 			input: `<div>{ "test" }</div>`,
 			expected: Element{
 				Name: "div",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 4,
+						Line:  0,
+						Col:   4,
+					},
+				},
 				Children: []Node{
 					StringExpression{
 						Expression: Expression{
@@ -800,10 +1288,123 @@ func TestElementParser(t *testing.T) {
 			},
 		},
 		{
-			name: "element: inputs can contain class attributes",
+			name:  "element: void element without end tag",
+			input: `<br>`,
+			expected: Element{
+				Name: "br",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: void element with attributes, without end tag",
+			input: `<input type="text">`,
+			expected: Element{
+				Name: "input",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 6,
+						Line:  0,
+						Col:   6,
+					},
+				},
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "type",
+						Value: "text",
+					},
+				},
+			},
+		},
+		{
+			name:  "element: void element with end tag",
+			input: `<br></br>`,
+			expected: Element{
+				Name: "br",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 3,
+						Line:  0,
+						Col:   3,
+					},
+				},
+			},
+		},
+		{
+			name:  "element: void elements within an element",
+			input: `<p>a<br>b</p>`,
+			expected: Element{
+				Name: "p",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 2,
+						Line:  0,
+						Col:   2,
+					},
+				},
+				Children: []Node{
+					Text{Value: "a"},
+					Element{
+						Name: "br",
+						NameRange: Range{
+							From: Position{
+								Index: 5,
+								Line:  0,
+								Col:   5,
+							},
+							To: Position{
+								Index: 7,
+								Line:  0,
+								Col:   7,
+							},
+						},
+					},
+					Text{Value: "b"},
+				},
+			},
+		},
+		{
+			name:  "element: inputs can contain class attributes",
 			input: `<input  type="email" id="email" name="email" class={ "a", "b", "c",  templ.KV("c", false)}	placeholder="your@email.com" autocomplete="off"/>`,
 			expected: Element{
 				Name: "input",
+				NameRange: Range{
+					From: Position{
+						Index: 1,
+						Line:  0,
+						Col:   1,
+					},
+					To: Position{
+						Index: 6,
+						Line:  0,
+						Col:   6,
+					},
+				},
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "type",
@@ -882,19 +1483,19 @@ func TestElementParserErrors(t *testing.T) {
 				}),
 		},
 		{
-			name:  "element: style must only contain text",
-			input: `<style><button /></style>`,
-			expected: parse.Error("<style>: invalid node contents: script and style attributes must only contain text",
+			name:  "element: spread attributes must end with ...",
+			input: `<div { attrs }></div>`,
+			expected: parse.Error("spread attributes: expected '...' after the attributes expression",
 				parse.Position{
-					Index: 0,
+					Index: 12,
 					Line:  0,
-					Col:   0,
+					Col:   12,
 				}),
 		},
 		{
-			name:  "element: script must only contain text",
-			input: `<script><button /></script>`,
-			expected: parse.Error("<script>: invalid node contents: script and style attributes must only contain text",
+			name:  "element: void elements cannot have children",
+			input: `<br>text</br>`,
+			expected: parse.Error("<br>: void element cannot have children",
 				parse.Position{
 					Index: 0,
 					Line:  0,
@@ -902,9 +1503,9 @@ func TestElementParserErrors(t *testing.T) {
 				}),
 		},
 		{
-			name:  "element: attempted use of expression for style attribute (open/close)",
-			input: `<a style={ value }></a>`,
-			expected: parse.Error(`<a>: invalid style attribute: style attributes cannot be a templ expression`,
+			name:  "element: style must only contain text",
+			input: `<style><button /></style>`,
+			expected: parse.Error("<style>: invalid node contents: script and style attributes must only contain text",
 				parse.Position{
 					Index: 0,
 					Line:  0,
@@ -912,9 +1513,9 @@ func TestElementParserErrors(t *testing.T) {
 				}),
 		},
 		{
-			name:  "element: attempted use of expression for style attribute (self-closing)",
-			input: `<a style={ value }/>`,
-			expected: parse.Error(`<a>: invalid style attribute: style attributes cannot be a templ expression`,
+			name:  "element: script must only contain text",
+			input: `<script><button /></script>`,
+			expected: parse.Error("<script>: invalid node contents: script and style attributes must only contain text",
 				parse.Position{
 					Index: 0,
 					Line:  0,
@@ -954,6 +1555,22 @@ func TestElementParserErrors(t *testing.T) {
 	}
 }
 
+func TestStyleAttributeExpressions(t *testing.T) {
+	for _, input := range []string{
+		`<a style={ templ.Styles(styles) }></a>`,
+		`<a style={ templ.Styles(styles) }/>`,
+	} {
+		e, ok, err := element.Parse(parse.NewInput(input))
+		if err != nil || !ok {
+			t.Fatalf("%s: failed to parse: %v", input, err)
+		}
+		attr, isExpression := e.Attributes[0].(ExpressionAttribute)
+		if !isExpression || attr.Name != "style" || attr.Expression.Value != "templ.Styles(styles)" {
+			t.Errorf("%s: expected a style expression attribute, got %#v", input, e.Attributes[0])
+		}
+	}
+}
+
 func TestBigElement(t *testing.T) {
 	var sb strings.Builder
 	sb.WriteString("<div>")
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/txtar"
+)
+
+var update = flag.Bool("update", false, "Update the expected error messages in testdata/errors.txtar.")
+
+// TestErrorMessages checks the messages and ranges of parse errors against testdata/errors.txtar,
+// where each name.templ file is followed by a name.err file containing the expected errors, one
+// per line, as line:col-line:col: message. Run go test -update to rewrite the expected errors.
+func TestErrorMessages(t *testing.T) {
+	const fileName = "testdata/errors.txtar"
+	archive, err := txtar.ParseFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+	files := make(map[string]string, len(archive.Files))
+	for _, f := range archive.Files {
+		files[f.Name] = string(f.Data)
+	}
+	updated := &txtar.Archive{Comment: archive.Comment}
+	for _, f := range archive.Files {
+		if !strings.HasSuffix(f.Name, ".templ") {
+			continue
+		}
+		errName := strings.TrimSuffix(f.Name, ".templ") + ".err"
+		_, err := ParseString(string(f.Data))
+		actual := formatErrors(err)
+		updated.Files = append(updated.Files, f, txtar.File{Name: errName, Data: []byte(actual)})
+		if *update {
+			continue
+		}
+		t.Run(strings.TrimSuffix(f.Name, ".templ"), func(t *testing.T) {
+			expected, ok := files[errName]
+			if !ok {
+				t.Fatalf("%s not found", errName)
+			}
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	if *update {
+		if err := os.WriteFile(fileName, txtar.Format(updated), 0644); err != nil {
+			t.Fatalf("failed to update test data: %v", err)
+		}
+	}
+}
+
+func formatErrors(err error) string {
+	if err == nil {
+		return "no errors\n"
+	}
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		return err.Error() + "\n"
+	}
+	var sb strings.Builder
+	for _, e := range errs {
+		fmt.Fprintf(&sb, "%d:%d-%d:%d: %s\n", e.Pos.Line+1, e.Pos.Col+1, e.To.Line+1, e.To.Col+1, e.Msg)
+	}
+	return sb.String()
+}
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	"go/scanner"
+	"go/token"
 	"strings"
 
 	"github.com/a-h/parse"
@@ -57,7 +60,12 @@ var openBracket = parse.String("(")
 var closeBracket = parse.String(")")
 var closeBracketWithOptionalPadding = parse.StringFrom(optionalSpaces, closeBracket)
 
-var exp = expressionParser{
+// exp parses a Go expression up to the closing brace of a "{ ... }" block.
+var exp = goExpressionParser{closer: token.RBRACE}
+
+// scriptExp parses the contents of a script template. Script templates contain
+// JavaScript, so only braces within strings and rune literals are skipped.
+var scriptExp = expressionParser{
 	startBraceCount: 1,
 }
 
@@ -138,81 +146,102 @@ loop:
 	return NewExpression(sb.String(), from, pi.Position()), true, nil
 }
 
-type functionArgsParser struct {
-	startBracketCount int
+// goExpressionParser reads Go code up to, but not including, the closer that ends
+// it, e.g. the "}" of "{ x }", or the ")" of "@Component(x)". The closer must not be
+// nested within braces, brackets or parentheses, or be part of a string, rune
+// literal or comment, so the expression can contain any Go code, including
+// function literals that span multiple lines.
+//
+// When the closer is a brace, a single space before it is padding, and isn't part
+// of the expression.
+type goExpressionParser struct {
+	closer token.Token
 }
 
-func (p functionArgsParser) Parse(pi *parse.Input) (s Expression, ok bool, err error) {
+func (p goExpressionParser) Parse(pi *parse.Input) (s Expression, ok bool, err error) {
 	from := pi.Position()
+	start := pi.Index()
+	src, _ := pi.Peek(-1)
+	n, errOffset, msg := scanGoExpression(src, p.closer)
+	if msg != "" {
+		pi.Seek(start + errOffset)
+		err = parse.Error("expression: "+msg, pi.Position())
+		return
+	}
+	if p.closer == token.RBRACE && n > 0 && src[n-1] == ' ' {
+		n--
+	}
+	pi.Seek(start + n)
+	return NewExpression(src[:n], from, pi.Position()), true, nil
+}
 
-	bracketCount := p.startBracketCount
-
-	var sb strings.Builder
-loop:
-	for {
-		var result string
-
-		// Try to read a string literal first.
-		if result, ok, err = string_lit.Parse(pi); err != nil {
-			return
+// scanGoExpressionWindow is the number of bytes of input that's scanned at first
+// when looking for the end of a Go expression. Expressions are rarely this long, so
+// the rest of the file doesn't need to be copied for the scanner.
+const scanGoExpressionWindow = 256
+
+// scanGoExpression returns the length of the Go code at the start of src that's
+// ended by closer. If the code isn't closed, msg describes the problem, and
+// errOffset is where it was found.
+func scanGoExpression(src string, closer token.Token) (n, errOffset int, msg string) {
+	for size := scanGoExpressionWindow; ; size *= 2 {
+		isFinal := size >= len(src)
+		if isFinal {
+			size = len(src)
 		}
-		if ok {
-			sb.WriteString(result)
-			continue
-		}
-		// Also try for a rune literal.
-		if result, ok, err = rune_lit.Parse(pi); err != nil {
-			return
-		}
-		if ok {
-			sb.WriteString(result)
-			continue
+		var found bool
+		n, found, errOffset, msg = scanGoExpressionFrom(src[:size], closer)
+		if found {
+			return n, 0, ""
 		}
-		// Try opener.
-		if result, ok, err = openBracket.Parse(pi); err != nil {
-			return
+		if isFinal {
+			if msg == "" {
+				errOffset, msg = len(src), fmt.Sprintf("missing closing %q", closer.String())
+			}
+			return 0, errOffset, msg
 		}
-		if ok {
-			bracketCount++
-			sb.WriteString(result)
-			continue
+		// A token might have been cut short by the end of the window, so try again.
+	}
+}
+
+func scanGoExpressionFrom(src string, closer token.Token) (n int, found bool, errOffset int, msg string) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(f, []byte(src), func(pos token.Position, m string) {
+		// Other errors, such as invalid escape sequences, are left for the Go
+		// compiler to report, since they don't change where the expression ends.
+		if msg == "" && strings.HasSuffix(m, "not terminated") {
+			errOffset, msg = pos.Offset, m
 		}
-		// Try closer.
-		startOfCloseBracket := pi.Index()
-		if result, ok, err = closeBracketWithOptionalPadding.Parse(pi); err != nil {
-			return
+	}, scanner.ScanComments)
+	var open []token.Token
+	for {
+		pos, tok, _ := s.Scan()
+		if msg != "" || tok == token.EOF {
+			return 0, false, errOffset, msg
 		}
-		if ok {
-			bracketCount--
-			if bracketCount < 0 {
-				err = parse.Error("expression: too many closing brackets", pi.Position())
-				return
+		switch tok {
+		case token.LBRACE, token.LBRACK, token.LPAREN:
+			open = append(open, tok)
+		case token.RBRACE, token.RBRACK, token.RPAREN:
+			if len(open) > 0 && open[len(open)-1] == matchingOpener[tok] {
+				open = open[:len(open)-1]
+				continue
 			}
-			if bracketCount == 0 {
-				pi.Seek(startOfCloseBracket)
-				break loop
+			// Unbalanced openers are left for the Go compiler to report, so that
+			// incomplete code, e.g. while it's being typed, ends at the closer.
+			if tok == closer {
+				return f.Offset(pos), true, 0, ""
 			}
-			sb.WriteString(result)
-			continue
-		}
-
-		// Read anything else.
-		var c string
-		c, ok = pi.Take(1)
-		if !ok {
-			break loop
-		}
-		if rune(c[0]) == 65533 { // Invalid Unicode.
-			break loop
 		}
-		sb.WriteString(c)
-	}
-	if bracketCount != 0 {
-		err = parse.Error("expression: unexpected bracket count", pi.Position())
-		return
 	}
+}
 
-	return NewExpression(sb.String(), from, pi.Position()), true, nil
+var matchingOpener = map[token.Token]token.Token{
+	token.RBRACE: token.LBRACE,
+	token.RBRACK: token.LBRACK,
+	token.RPAREN: token.LPAREN,
 }
 
 // Letters and digits
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"go/token"
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
@@ -240,3 +242,151 @@ func TestExpressions(t *testing.T) {
 		})
 	}
 }
+
+func TestGoExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		closer   token.Token
+		expected Expression
+	}{
+		{
+			name:   "braces: the padding before the closing brace is not included",
+			input:  `name }`,
+			closer: token.RBRACE,
+			expected: Expression{
+				Value: "name",
+				Range: Range{From: Position{0, 0, 0}, To: Position{4, 0, 4}},
+			},
+		},
+		{
+			name:   "braces: nested braces, brackets and parentheses",
+			input:  `fmt.Sprintf("%d items", len(map[string]int{"a": 1})) }`,
+			closer: token.RBRACE,
+			expected: Expression{
+				Value: `fmt.Sprintf("%d items", len(map[string]int{"a": 1}))`,
+				Range: Range{From: Position{0, 0, 0}, To: Position{52, 0, 52}},
+			},
+		},
+		{
+			name:   "braces: strings, raw strings and rune literals",
+			input:  "f(\"}\\\"\", `}`, '}') }",
+			closer: token.RBRACE,
+			expected: Expression{
+				Value: "f(\"}\\\"\", `}`, '}')",
+				Range: Range{From: Position{0, 0, 0}, To: Position{18, 0, 18}},
+			},
+		},
+		{
+			name:   "braces: multi-line function literal with comments",
+			input:  "func() string {\n\t// }\n\t/* } */\n\treturn \"a\"\n}() }",
+			closer: token.RBRACE,
+			expected: Expression{
+				Value: "func() string {\n\t// }\n\t/* } */\n\treturn \"a\"\n}()",
+				Range: Range{From: Position{0, 0, 0}, To: Position{46, 4, 3}},
+			},
+		},
+		{
+			name:   "braces: raw strings can contain new lines",
+			input:  "`\n}\n` }",
+			closer: token.RBRACE,
+			expected: Expression{
+				Value: "`\n}\n`",
+				Range: Range{From: Position{0, 0, 0}, To: Position{5, 2, 1}},
+			},
+		},
+		{
+			name:   "braces: unbalanced openers end at the closer",
+			input:  "f( }",
+			closer: token.RBRACE,
+			expected: Expression{
+				Value: "f(",
+				Range: Range{From: Position{0, 0, 0}, To: Position{2, 0, 2}},
+			},
+		},
+		{
+			name:   "parentheses: multi-line arguments",
+			input:  "\n\t\"a)\",\n\tfunc() { f(')') },\n)",
+			closer: token.RPAREN,
+			expected: Expression{
+				Value: "\n\t\"a)\",\n\tfunc() { f(')') },\n",
+				Range: Range{From: Position{0, 0, 0}, To: Position{28, 3, 0}},
+			},
+		},
+		{
+			name:   "parentheses: the padding before the closer is included",
+			input:  "a )",
+			closer: token.RPAREN,
+			expected: Expression{
+				Value: "a ",
+				Range: Range{From: Position{0, 0, 0}, To: Position{2, 0, 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok, err := goExpressionParser{closer: tt.closer}.Parse(parse.NewInput(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGoExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "missing closer",
+			input:    "f(a, b)\n",
+			expected: `expression: missing closing "}": line 1, col 0`,
+		},
+		{
+			name:     "unterminated string",
+			input:    "f(\"a }\n",
+			expected: "expression: string literal not terminated: line 0, col 2",
+		},
+		{
+			name:     "unterminated comment",
+			input:    "a /* }\n",
+			expected: "expression: comment not terminated: line 0, col 2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := exp.Parse(parse.NewInput(tt.input))
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if diff := cmp.Diff(tt.expected, err.Error()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGoExpressionsLongerThanTheScanWindow(t *testing.T) {
+	for _, offset := range []int{-2, -1, 0, 1} {
+		// Place the start of a comment and a string across the end of the first window.
+		padding := strings.Repeat("a", scanGoExpressionWindow+offset-2)
+		input := padding + "/* } */ + \"}\" + `\n}` }"
+		actual, ok, err := exp.Parse(parse.NewInput(input))
+		if err != nil || !ok {
+			t.Fatalf("offset %d: unexpected failure: %v", offset, err)
+		}
+		if expected := input[:len(input)-2]; actual.Value != expected {
+			t.Errorf("offset %d: expected value of length %d, got %d", offset, len(expected), len(actual.Value))
+		}
+	}
+}
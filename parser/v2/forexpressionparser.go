@@ -26,10 +26,12 @@ var forExpression = parse.Func(func(pi *parse.Input) (r ForExpression, ok bool,
 	}
 
 	// Node contents.
+	from = pi.Position()
 	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "for expression closing brace")
 	if r.Children, ok, err = Must[[]Node](tnp, "for: expected nodes, but none were found").Parse(pi); err != nil || !ok {
 		return
 	}
+	r.ChildrenRange = NewRange(from, pi.Position())
 
 	// Read the required closing brace.
 	if _, ok, err = Must(closeBraceWithOptionalPadding, "for: missing end (expected '}')").Parse(pi); err != nil || !ok {
@@ -39,6 +39,18 @@ func TestForExpressionParser(t *testing.T) {
 					Whitespace{Value: "\t\t\t\t\t"},
 					Element{
 						Name: "div",
+						NameRange: Range{
+							From: Position{
+								Index: 37,
+								Line:  1,
+								Col:   6,
+							},
+							To: Position{
+								Index: 40,
+								Line:  1,
+								Col:   9,
+							},
+						},
 						Children: []Node{
 							StringExpression{
 								Expression: Expression{
@@ -62,6 +74,126 @@ func TestForExpressionParser(t *testing.T) {
 					},
 					Whitespace{Value: "\n\t\t\t\t"},
 				},
+				ChildrenRange: Range{
+					From: Position{
+						Index: 31,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 60,
+						Line:  2,
+						Col:   4,
+					},
+				},
+			},
+		},
+		{
+			name: "for: counter loop",
+			input: `for i := 0; i < n; i++ {
+					<br/>
+				}`,
+			expected: ForExpression{
+				Expression: Expression{
+					Value: `i := 0; i < n; i++`,
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 22,
+							Line:  0,
+							Col:   22,
+						},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t\t\t\t\t"},
+					Element{
+						Name: "br",
+						NameRange: Range{
+							From: Position{
+								Index: 31,
+								Line:  1,
+								Col:   6,
+							},
+							To: Position{
+								Index: 33,
+								Line:  1,
+								Col:   8,
+							},
+						},
+					},
+					Whitespace{Value: "\n\t\t\t\t"},
+				},
+				ChildrenRange: Range{
+					From: Position{
+						Index: 25,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 40,
+						Line:  2,
+						Col:   4,
+					},
+				},
+			},
+		},
+		{
+			name: "for: map range with key and value",
+			input: `for k, v := range m {
+					<br/>
+				}`,
+			expected: ForExpression{
+				Expression: Expression{
+					Value: `k, v := range m`,
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 19,
+							Line:  0,
+							Col:   19,
+						},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t\t\t\t\t"},
+					Element{
+						Name: "br",
+						NameRange: Range{
+							From: Position{
+								Index: 28,
+								Line:  1,
+								Col:   6,
+							},
+							To: Position{
+								Index: 30,
+								Line:  1,
+								Col:   8,
+							},
+						},
+					},
+					Whitespace{Value: "\n\t\t\t\t"},
+				},
+				ChildrenRange: Range{
+					From: Position{
+						Index: 22,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 37,
+						Line:  2,
+						Col:   4,
+					},
+				},
 			},
 		},
 		{
@@ -90,6 +222,18 @@ func TestForExpressionParser(t *testing.T) {
 					Whitespace{Value: "\t\t\t\t\t"},
 					Element{
 						Name: "div",
+						NameRange: Range{
+							From: Position{
+								Index: 36,
+								Line:  1,
+								Col:   6,
+							},
+							To: Position{
+								Index: 39,
+								Line:  1,
+								Col:   9,
+							},
+						},
 						Children: []Node{
 							StringExpression{
 								Expression: Expression{
@@ -113,6 +257,18 @@ func TestForExpressionParser(t *testing.T) {
 					},
 					Whitespace{Value: "\n\t\t\t\t"},
 				},
+				ChildrenRange: Range{
+					From: Position{
+						Index: 30,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 59,
+						Line:  2,
+						Col:   4,
+					},
+				},
 			},
 		},
 	}
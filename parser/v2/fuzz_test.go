@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/txtar"
+)
+
+// parseTimeout is how long a parse can take before the fuzz target reports it as a hang. The
+// seed corpus parses in microseconds, so it's only exceeded by loops that don't terminate.
+const parseTimeout = 5 * time.Second
+
+// FuzzParseString checks that ParseString returns, without panicking, for any input. It's
+// seeded with the templates in testdata/errors.txtar and the generator tests. Run it with
+// go test -fuzz=FuzzParseString ./parser/v2, and add the inputs that fail to the seeds.
+func FuzzParseString(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = ParseString(input)
+		}()
+		select {
+		case <-done:
+		case <-time.After(parseTimeout):
+			t.Fatalf("ParseString didn't return within %v for input %q", parseTimeout, input)
+		}
+	})
+}
+
+// FuzzOpenElements checks that OpenElements returns, without panicking, for any input and
+// index. Run it with go test -fuzz=FuzzOpenElements ./parser/v2.
+func FuzzOpenElements(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed, len(seed)/2)
+	}
+	f.Fuzz(func(t *testing.T, input string, index int) {
+		if index < 0 {
+			index = -index
+		}
+		index %= len(input) + 1
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = OpenElements(input, index)
+		}()
+		select {
+		case <-done:
+		case <-time.After(parseTimeout):
+			t.Fatalf("OpenElements didn't return within %v for input %q at index %d", parseTimeout, input, index)
+		}
+	})
+}
+
+func fuzzSeeds(tb testing.TB) (seeds []string) {
+	archive, err := txtar.ParseFile("testdata/errors.txtar")
+	if err != nil {
+		tb.Fatalf("failed to read test data: %v", err)
+	}
+	for _, f := range archive.Files {
+		if strings.HasSuffix(f.Name, ".templ") {
+			seeds = append(seeds, string(f.Data))
+		}
+	}
+	fileNames, err := filepath.Glob("../../generator/test-*/template.templ")
+	if err != nil {
+		tb.Fatalf("failed to find templates: %v", err)
+	}
+	for _, fileName := range fileNames {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			tb.Fatalf("failed to read template: %v", err)
+		}
+		seeds = append(seeds, string(data))
+	}
+	// Templates that end within a construct, which the scanners must stop at.
+	return append(seeds,
+		benchmarkTemplate(1),
+		"package main\n\ntempl a() {\n\t<a href=\"/",
+		"package main\n\ntempl a() {\n\t<a href={ \"/",
+		"package main\n\ntempl a() {\n\t<div>{",
+		"package main\n\ntempl a() {\n\t{",
+		"package main\n\ntempl a() {\n\t@b(\"",
+		"package main\n\ncss a() {\n\tcolor: {",
+		"package main\n\nscript a() {\n\t\"",
+	)
+}
@@ -29,10 +29,12 @@ func (ifExpressionParser) Parse(pi *parse.Input) (r IfExpression, ok bool, err e
 
 	// Read the 'Then' nodes.
 	// If there's no match, there's a problem in the template nodes.
+	from := pi.Position()
 	np := newTemplateNodeParser(parse.Any(StripType(elseIfExpression), StripType(elseExpression), StripType(closeBraceWithOptionalPadding)), "else expression or closing brace")
 	if r.Then, ok, err = Must[[]Node](np, "if: expected nodes, but none were found").Parse(pi); err != nil || !ok {
 		return
 	}
+	r.ThenRange = NewRange(from, pi.Position())
 
 	// Read the optional 'ElseIf' Nodes.
 	if r.ElseIfs, _, err = parse.ZeroOrMore(elseIfExpression).Parse(pi); err != nil {
@@ -40,9 +42,11 @@ func (ifExpressionParser) Parse(pi *parse.Input) (r IfExpression, ok bool, err e
 	}
 
 	// Read the optional 'Else' Nodes.
-	if r.Else, _, err = elseExpression.Parse(pi); err != nil {
+	var eb elseBlock
+	if eb, _, err = elseExpression.Parse(pi); err != nil {
 		return
 	}
+	r.Else, r.ElseRange = eb.nodes, eb.r
 
 	// Read the required closing brace.
 	if _, ok, err = Must(closeBraceWithOptionalPadding, "if: missing end (expected '}')").Parse(pi); err != nil || !ok {
@@ -82,10 +86,12 @@ func (elseIfExpressionParser) Parse(pi *parse.Input) (r ElseIfExpression, ok boo
 
 	// Read the 'Then' nodes.
 	// If there's no match, there's a problem in the template nodes.
+	from := pi.Position()
 	np := newTemplateNodeParser(parse.Any(StripType(elseIfExpression), StripType(elseExpression), StripType(closeBraceWithOptionalPadding)), "else expression or closing brace")
 	if r.Then, ok, err = Must[[]Node](np, "if: expected nodes, but none were found").Parse(pi); err != nil || !ok {
 		return
 	}
+	r.ThenRange = NewRange(from, pi.Position())
 
 	return r, true, nil
 }
@@ -98,11 +104,17 @@ var endElseParser = parse.All(
 	parse.Rune('{'),
 	parse.OptionalWhitespace)
 
-var elseExpression parse.Parser[[]Node] = elseExpressionParser{}
+// elseBlock is the contents of an else block, and their range.
+type elseBlock struct {
+	nodes []Node
+	r     Range
+}
+
+var elseExpression parse.Parser[elseBlock] = elseExpressionParser{}
 
 type elseExpressionParser struct{}
 
-func (elseExpressionParser) Parse(in *parse.Input) (r []Node, ok bool, err error) {
+func (elseExpressionParser) Parse(in *parse.Input) (r elseBlock, ok bool, err error) {
 	start := in.Index()
 
 	// } else {
@@ -112,10 +124,12 @@ func (elseExpressionParser) Parse(in *parse.Input) (r []Node, ok bool, err error
 	}
 
 	// Else contents
-	if r, ok, err = newTemplateNodeParser(closeBraceWithOptionalPadding, "else expression closing brace").Parse(in); err != nil || !ok {
+	from := in.Position()
+	if r.nodes, ok, err = newTemplateNodeParser(closeBraceWithOptionalPadding, "else expression closing brace").Parse(in); err != nil || !ok {
 		in.Seek(start)
 		return
 	}
+	r.r = NewRange(from, in.Position())
 
 	return r, true, nil
 }
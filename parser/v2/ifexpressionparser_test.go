@@ -40,6 +40,18 @@ func TestIfExpression(t *testing.T) {
 				Then: []Node{
 					Element{
 						Name: "span",
+						NameRange: Range{
+							From: Position{
+								Index: 13,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   5,
+							},
+						},
 						Children: []Node{
 							Whitespace{Value: "\n  "},
 							StringExpression{
@@ -64,6 +76,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 12,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 48,
+						Line:  4,
+						Col:   0,
+					},
+				},
 			},
 		},
 		{
@@ -110,6 +134,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 9,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 18,
+						Line:  2,
+						Col:   0,
+					},
+				},
 				Else: []Node{
 					StringExpression{
 						Expression: Expression{
@@ -130,6 +166,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ElseRange: Range{
+					From: Position{
+						Index: 28,
+						Line:  3,
+						Col:   1,
+					},
+					To: Position{
+						Index: 36,
+						Line:  4,
+						Col:   0,
+					},
+				},
 			},
 		},
 		{
@@ -159,6 +207,18 @@ func TestIfExpression(t *testing.T) {
 					Text{Value: "text"},
 					Whitespace{Value: "\n"},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 13,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 20,
+						Line:  2,
+						Col:   0,
+					},
+				},
 			},
 		},
 		{
@@ -188,6 +248,18 @@ func TestIfExpression(t *testing.T) {
 				Then: []Node{
 					Element{
 						Name: "span",
+						NameRange: Range{
+							From: Position{
+								Index: 13,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 17,
+								Line:  1,
+								Col:   5,
+							},
+						},
 						Children: []Node{
 							Whitespace{Value: "\n  "},
 							StringExpression{
@@ -212,6 +284,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 12,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 48,
+						Line:  4,
+						Col:   0,
+					},
+				},
 			},
 		},
 		{
@@ -258,6 +342,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 8,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  2,
+						Col:   0,
+					},
+				},
 				Else: []Node{
 					StringExpression{
 						Expression: Expression{
@@ -278,6 +374,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ElseRange: Range{
+					From: Position{
+						Index: 27,
+						Line:  3,
+						Col:   1,
+					},
+					To: Position{
+						Index: 35,
+						Line:  4,
+						Col:   0,
+					},
+				},
 			},
 		},
 		{
@@ -325,6 +433,18 @@ func TestIfExpression(t *testing.T) {
 							Whitespace{Value: "\t\t\t\t\t\t"},
 							Element{
 								Name: "div",
+								NameRange: Range{
+									From: Position{
+										Index: 30,
+										Line:  2,
+										Col:   7,
+									},
+									To: Position{
+										Index: 33,
+										Line:  2,
+										Col:   10,
+									},
+								},
 								Children: []Node{
 									StringExpression{
 										Expression: Expression{
@@ -347,9 +467,33 @@ func TestIfExpression(t *testing.T) {
 							},
 							Whitespace{Value: "\n\t\t\t\t\t"},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 23,
+								Line:  2,
+								Col:   0,
+							},
+							To: Position{
+								Index: 53,
+								Line:  3,
+								Col:   5,
+							},
+						},
 					},
 					Whitespace{Value: "\n\t\t\t\t"},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 9,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 59,
+						Line:  4,
+						Col:   4,
+					},
+				},
 			},
 		},
 		{
@@ -379,6 +523,18 @@ func TestIfExpression(t *testing.T) {
 						},
 					},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 9,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  1,
+						Col:   8,
+					},
+				},
 				ElseIfs: []ElseIfExpression{
 					{
 						Expression: Expression{
@@ -401,6 +557,18 @@ func TestIfExpression(t *testing.T) {
 							},
 							Whitespace{Value: "\n"},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 34,
+								Line:  3,
+								Col:   0,
+							},
+							To: Position{
+								Index: 43,
+								Line:  4,
+								Col:   0,
+							},
+						},
 					},
 				},
 			},
@@ -434,6 +602,18 @@ func TestIfExpression(t *testing.T) {
 						},
 					},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 9,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  1,
+						Col:   8,
+					},
+				},
 				ElseIfs: []ElseIfExpression{
 					{
 						Expression: Expression{
@@ -455,6 +635,18 @@ func TestIfExpression(t *testing.T) {
 								},
 							},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 34,
+								Line:  3,
+								Col:   0,
+							},
+							To: Position{
+								Index: 42,
+								Line:  3,
+								Col:   8,
+							},
+						},
 					},
 					{
 						Expression: Expression{
@@ -477,6 +669,18 @@ func TestIfExpression(t *testing.T) {
 							},
 							Whitespace{Value: "\n"},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 59,
+								Line:  5,
+								Col:   0,
+							},
+							To: Position{
+								Index: 68,
+								Line:  6,
+								Col:   0,
+							},
+						},
 					},
 				},
 			},
@@ -512,6 +716,18 @@ func TestIfExpression(t *testing.T) {
 						},
 					},
 				},
+				ThenRange: Range{
+					From: Position{
+						Index: 9,
+						Line:  1,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  1,
+						Col:   8,
+					},
+				},
 				ElseIfs: []ElseIfExpression{
 					{
 						Expression: Expression{
@@ -533,6 +749,18 @@ func TestIfExpression(t *testing.T) {
 								},
 							},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 34,
+								Line:  3,
+								Col:   0,
+							},
+							To: Position{
+								Index: 42,
+								Line:  3,
+								Col:   8,
+							},
+						},
 					},
 					{
 						Expression: Expression{
@@ -555,6 +783,18 @@ func TestIfExpression(t *testing.T) {
 							},
 							Whitespace{Value: "\n"},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 59,
+								Line:  5,
+								Col:   0,
+							},
+							To: Position{
+								Index: 68,
+								Line:  6,
+								Col:   0,
+							},
+						},
 					},
 				},
 				Else: []Node{
@@ -569,6 +809,18 @@ func TestIfExpression(t *testing.T) {
 					},
 					Whitespace{Value: "\n"},
 				},
+				ElseRange: Range{
+					From: Position{
+						Index: 78,
+						Line:  7,
+						Col:   1,
+					},
+					To: Position{
+						Index: 86,
+						Line:  8,
+						Col:   0,
+					},
+				},
 			},
 		},
 	}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"errors"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strconv"
+)
+
+// IncludeFileName returns the name of the file included by @templ.Include("icons/logo.svg"),
+// relative to the directory of the templ file. The contents of the file are written into the
+// generated code when it's generated, in place of the expression. ok is false if the
+// expression doesn't call templ.Include, and err is set if it does, but isn't valid.
+func (tee TemplElementExpression) IncludeFileName() (fileName string, ok bool, err error) {
+	e, parseErr := goparser.ParseExpr(tee.Expression.Value)
+	if parseErr != nil {
+		return "", false, nil
+	}
+	call, isCall := e.(*ast.CallExpr)
+	if isCall {
+		e = call.Fun
+	}
+	sel, isSelector := e.(*ast.SelectorExpr)
+	if !isSelector || sel.Sel.Name != "Include" {
+		return "", false, nil
+	}
+	if pkg, isIdent := sel.X.(*ast.Ident); !isIdent || pkg.Name != "templ" {
+		return "", false, nil
+	}
+	if !isCall || len(call.Args) != 1 || call.Ellipsis != token.NoPos {
+		return "", true, errors.New("templ.Include: expected a single file name, e.g. @templ.Include(\"icons/logo.svg\")")
+	}
+	lit, isLiteral := call.Args[0].(*ast.BasicLit)
+	if !isLiteral || lit.Kind != token.STRING {
+		return "", true, errors.New("templ.Include: the file name must be a string literal, because the file is read when the code is generated")
+	}
+	if fileName, err = strconv.Unquote(lit.Value); err != nil {
+		return "", true, err
+	}
+	if fileName == "" {
+		return "", true, errors.New("templ.Include: the file name is empty")
+	}
+	if len(tee.Children) > 0 {
+		return "", true, errors.New("templ.Include: included files can't have children")
+	}
+	return fileName, true, nil
+}
+
+// Includes returns the names of the files included in the templates of the file with
+// @templ.Include, in document order, without duplicates. Includes that aren't valid are
+// skipped, since they're reported when the code is generated.
+func Includes(tf TemplateFile) (fileNames []string) {
+	seen := make(map[string]struct{})
+	InspectFile(tf, func(n Node) bool {
+		tee, ok := n.(TemplElementExpression)
+		if !ok {
+			return true
+		}
+		fileName, ok, err := tee.IncludeFileName()
+		if !ok || err != nil {
+			return true
+		}
+		if _, found := seen[fileName]; !found {
+			seen[fileName] = struct{}{}
+			fileNames = append(fileNames, fileName)
+		}
+		return true
+	})
+	return fileNames
+}
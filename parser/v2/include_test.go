@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIncludeFileName(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedFileName string
+		expectedOK       bool
+		expectedErr      bool
+	}{
+		{
+			name:             "string literals are included",
+			input:            `@templ.Include("icons/logo.svg")`,
+			expectedFileName: "icons/logo.svg",
+			expectedOK:       true,
+		},
+		{
+			name:             "raw string literals are included",
+			input:            "@templ.Include(`icons/logo.svg`)",
+			expectedFileName: "icons/logo.svg",
+			expectedOK:       true,
+		},
+		{
+			name:  "other components aren't includes",
+			input: `@Button("Include")`,
+		},
+		{
+			name:  "functions named Include in other packages aren't includes",
+			input: `@components.Include("icons/logo.svg")`,
+		},
+		{
+			name:        "variables can't be included",
+			input:       `@templ.Include(fileName)`,
+			expectedOK:  true,
+			expectedErr: true,
+		},
+		{
+			name:        "a file name is required",
+			input:       `@templ.Include()`,
+			expectedOK:  true,
+			expectedErr: true,
+		},
+		{
+			name:        "the file name can't be empty",
+			input:       `@templ.Include("")`,
+			expectedOK:  true,
+			expectedErr: true,
+		},
+		{
+			name:        "includes can't have children",
+			input:       "@templ.Include(\"icons/logo.svg\") {\n\t<span></span>\n}",
+			expectedOK:  true,
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok, err := templElementExpression.Parse(parse.NewInput(tt.input))
+			if err != nil || !ok {
+				t.Fatalf("failed to parse %q: %v", tt.input, err)
+			}
+			fileName, ok, err := result.IncludeFileName()
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectedErr, err)
+			}
+			if ok != tt.expectedOK {
+				t.Errorf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if fileName != tt.expectedFileName {
+				t.Errorf("expected file name %q, got %q", tt.expectedFileName, fileName)
+			}
+		})
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	tf, err := ParseString(`package main
+
+templ Header() {
+	<header>
+		@templ.Include("icons/logo.svg")
+		@templ.Include("banner.txt")
+	</header>
+}
+
+templ Footer() {
+	if true {
+		@templ.Include("icons/logo.svg")
+	}
+	@templ.Include(fileName)
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	if diff := cmp.Diff([]string{"icons/logo.svg", "banner.txt"}, Includes(tf)); diff != "" {
+		t.Error(diff)
+	}
+}
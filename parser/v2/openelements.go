@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"go/token"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// OpenElements parses the template as far as the index, and returns the names of the
+// elements that are open at the index, outermost first.
+//
+// Unlike ParseString, it doesn't need the template to be valid, because it's used while
+// the template is being edited. The template after the index is ignored, unclosed elements
+// are left open, end tags that don't match an open element are ignored, and anything that
+// can't be parsed is skipped.
+func OpenElements(template string, index int) (names []string) {
+	if index > len(template) {
+		index = len(template)
+	}
+	src := template[:index]
+	pi := parse.NewInput(src)
+	// The template of the declaration that contains the index is parsed, other declarations,
+	// and the Go code between them, are skipped.
+	var inTemplate bool
+	for pi.Index() < len(src) {
+		i := pi.Index()
+		if i == 0 || src[i-1] == '\n' {
+			line, _, _ := strings.Cut(src[i:], "\n")
+			if isDeclarationStart(line) {
+				names = nil
+				inTemplate = strings.HasPrefix(line, "templ ")
+				pi.Seek(i + len(line))
+				continue
+			}
+		}
+		if !inTemplate {
+			pi.Seek(nextLine(src, i))
+			continue
+		}
+		rest := src[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			pi.Seek(skipPast(src, i, "-->"))
+		case strings.HasPrefix(rest, "</"):
+			ct, ok, err := elementCloseTagParser.Parse(pi)
+			if err != nil || !ok {
+				pi.Seek(i + len("</"))
+				continue
+			}
+			for j := len(names) - 1; j >= 0; j-- {
+				if names[j] == ct.Name {
+					names = names[:j]
+					break
+				}
+			}
+		case rest[0] == '<':
+			if _, ok, err := selfClosingElement.Parse(pi); err == nil && ok {
+				continue
+			}
+			ot, ok, err := elementOpenTagParser.Parse(pi)
+			if err != nil || !ok {
+				pi.Seek(i + 1)
+				continue
+			}
+			e := Element{Name: ot.Name}
+			if e.IsVoidElement() {
+				continue
+			}
+			// The contents of script and style elements aren't HTML.
+			if name := strings.ToLower(e.Name); name == "script" || name == "style" {
+				end := strings.Index(strings.ToLower(src[pi.Index():]), "</"+name)
+				if end < 0 {
+					return append(names, e.Name)
+				}
+				pi.Seek(pi.Index() + end)
+				continue
+			}
+			names = append(names, e.Name)
+		case rest[0] == '{':
+			// A brace at the end of a line opens the block of a statement, e.g. "if x {".
+			if line, _, _ := strings.Cut(rest[1:], "\n"); strings.TrimSpace(line) == "" {
+				pi.Seek(i + 1)
+				continue
+			}
+			n, _, msg := scanGoExpression(rest[1:], token.RBRACE)
+			if msg != "" {
+				// The index is within the expression.
+				return names
+			}
+			pi.Seek(i + 1 + n + 1)
+		case rest[0] == '\n':
+			pi.Seek(i + 1)
+		default:
+			next := strings.IndexAny(rest, "<{\n")
+			if next < 0 {
+				return names
+			}
+			pi.Seek(i + next)
+		}
+	}
+	return names
+}
+
+// nextLine returns the index of the start of the line after the index.
+func nextLine(s string, index int) int {
+	if n := strings.IndexByte(s[index:], '\n'); n >= 0 {
+		return index + n + 1
+	}
+	return len(s)
+}
+
+// skipPast returns the index after the first instance of substr after the index.
+func skipPast(s string, index int, substr string) int {
+	if n := strings.Index(s[index:], substr); n >= 0 {
+		return index + n + len(substr)
+	}
+	return len(s)
+}
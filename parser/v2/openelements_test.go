@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOpenElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "nested unclosed elements",
+			input: `package main
+
+templ page() {
+	<html>
+		<body>
+			<div class="a">
+				|`,
+			expected: []string{"html", "body", "div"},
+		},
+		{
+			name: "closed elements aren't open",
+			input: `templ page() {
+	<ul>
+		<li>a</li>
+		<li><span>b</span></li>
+		|
+	</ul>
+}`,
+			expected: []string{"ul"},
+		},
+		{
+			name: "the cursor is inside an if block",
+			input: `templ list(items []string, show bool) {
+	<ul>
+		if show {
+			for _, item := range items {
+				<li>
+					<span>{ item }</span>
+					</|
+				}
+		}
+	</ul>
+}`,
+			expected: []string{"ul", "li"},
+		},
+		{
+			name: "void and self-closing elements aren't open",
+			input: `templ form() {
+	<form>
+		<input type="text">
+		<br>
+		<img src="a.png"/>
+		|`,
+			expected: []string{"form"},
+		},
+		{
+			name: "expressions and attributes can contain angle brackets and braces",
+			input: `templ page(a, b int) {
+	<div class={ map[string]bool{"a": a < b} } data-x="<p>">
+		{ fmt.Sprint(a < b, "</div>") }
+		<p>
+			|`,
+			expected: []string{"div", "p"},
+		},
+		{
+			name: "the contents of script elements aren't HTML",
+			input: `templ page() {
+	<body>
+		<script>if (a<b) { document.write("</body><p>") }</script>
+		|`,
+			expected: []string{"body"},
+		},
+		{
+			name: "comments are ignored",
+			input: `templ page() {
+	<main>
+		<!-- <div> -->
+		|`,
+			expected: []string{"main"},
+		},
+		{
+			name: "end tags that don't match an open element are ignored",
+			input: `templ page() {
+	<main>
+		</span>
+		<section>
+			|`,
+			expected: []string{"main", "section"},
+		},
+		{
+			name: "end tags close elements that were left open within them",
+			input: `templ page() {
+	<main>
+		<section>
+			<p>
+		</section>
+		|`,
+			expected: []string{"main"},
+		},
+		{
+			name: "only the template that contains the cursor is parsed",
+			input: `templ first() {
+	<div>
+}
+
+css red() {
+	color: red;
+}
+
+script log(a, b int) {
+	if (a<b) { console.log(a) }
+}
+
+templ second() {
+	<span>
+		|`,
+			expected: []string{"span"},
+		},
+		{
+			name: "the rest of the file isn't parsed",
+			input: `templ page() {
+	<div>
+		|
+	</div>
+}`,
+			expected: []string{"div"},
+		},
+		{
+			name: "incomplete open tags aren't open",
+			input: `templ render(expanded bool) {
+	<main>
+		<div class="panel"
+			if expanded {
+				aria-expanded="t|`,
+			expected: []string{"main"},
+		},
+		{
+			name: "the cursor is within an expression",
+			input: `templ page() {
+	<div>
+		<p>{ strings.ToUpper(|`,
+			expected: []string{"div", "p"},
+		},
+		{
+			name:     "the cursor is in Go code",
+			input:    "package main\n\nvar a = 1 < 2\n|",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			index := strings.Index(tt.input, "|")
+			template := tt.input[:index] + tt.input[index+1:]
+			actual := OpenElements(template, index)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
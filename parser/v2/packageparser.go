@@ -13,9 +13,10 @@ var pkg = parse.Func(func(pi *parse.Input) (pkg Package, ok bool, err error) {
 		return
 	}
 
-	// Once we have the prefix, it's an expression until the end of the line.
+	// Once we have the prefix, it's an expression until the end of the line, or the end of
+	// a file that only contains the package declaration.
 	var exp string
-	if exp, ok, err = Must(parse.StringUntil(parse.NewLine), "package literal not terminated").Parse(pi); err != nil || !ok {
+	if exp, ok, err = Must(parse.StringUntil(parse.Or(parse.NewLine, parse.EOF[string]())), "package literal not terminated").Parse(pi); err != nil || !ok {
 		return
 	}
 	if len(exp) == 0 {
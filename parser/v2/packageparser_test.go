@@ -19,9 +19,9 @@ func TestPackageParserErrors(t *testing.T) {
 			expected: parse.Error(
 				"package literal not terminated",
 				parse.Position{
-					Index: 8,
+					Index: 0,
 					Line:  0,
-					Col:   8,
+					Col:   0,
 				},
 			),
 		},
@@ -20,6 +20,7 @@ type rawElementParser struct {
 
 func (p rawElementParser) Parse(pi *parse.Input) (e RawElement, ok bool, err error) {
 	start := pi.Index()
+	startPos := pi.Position()
 
 	// <
 	if _, ok, err = lt.Parse(pi); err != nil || !ok {
@@ -50,9 +51,15 @@ func (p rawElementParser) Parse(pi *parse.Input) (e RawElement, ok bool, err err
 	}
 
 	// Once we've got an open tag, parse anything until the end tag as the tag contents.
-	// It's going to be rendered out raw.
+	// It's going to be rendered out raw, so expressions and other templ syntax aren't
+	// interpreted, e.g. `if (a < b) {` or a JavaScript template literal.
 	end := parse.All(parse.String("</"), parse.String(p.name), parse.String(">"))
-	if e.Contents, ok, err = Must(parse.StringUntil(end), fmt.Sprintf("<%s>: expected end tag not present", e.Name)).Parse(pi); err != nil || !ok {
+	if e.Contents, ok, err = parse.StringUntil(end).Parse(pi); err != nil {
+		return
+	}
+	if !ok {
+		// Report the error against the open tag, rather than the end of the file.
+		err = parse.Error(fmt.Sprintf("<%s>: expected end tag not present", e.Name), startPos)
 		return
 	}
 	// Cut the end element.
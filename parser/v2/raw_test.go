@@ -60,6 +60,22 @@ func TestRawElementParser(t *testing.T) {
 				Contents: "dim x = 1",
 			},
 		},
+		{
+			name: "script tag containing comparisons and template literals",
+			input: `<script type="text/javascript">
+	if (a < b) { console.log(` + "`${a} is less than ${b}`" + `); }
+</script>`,
+			expected: RawElement{
+				Name: "script",
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "type",
+						Value: "text/javascript",
+					},
+				},
+				Contents: "\n\tif (a < b) { console.log(`${a} is less than ${b}`); }\n",
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -78,3 +94,33 @@ func TestRawElementParser(t *testing.T) {
 		})
 	}
 }
+
+func TestRawElementParserErrors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected error
+	}{
+		{
+			name:  "script tag: missing end tag",
+			input: "<div>\n\t<script>\n\t\tif (a < b) {}\n</div>",
+			expected: parse.Error("<script>: expected end tag not present",
+				parse.Position{
+					Index: 7,
+					Line:  1,
+					Col:   1,
+				}),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			input.Seek(7)
+			_, _, err := rawElements.Parse(input)
+			if diff := cmp.Diff(tt.expected, err); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
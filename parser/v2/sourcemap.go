@@ -1,24 +1,45 @@
 package parser
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 )
 
 // NewSourceMap creates a new lookup to map templ source code to items in the
 // parsed template.
 func NewSourceMap() *SourceMap {
-	return &SourceMap{
-		SourceLinesToTarget: make(map[uint32]map[uint32]Position),
-		TargetLinesToSource: make(map[uint32]map[uint32]Position),
-	}
+	return &SourceMap{}
 }
 
+// SourceMap maps positions in templ source code to positions in the generated Go code,
+// and back again. Columns within the map are byte offsets from the start of the line.
+//
+// Each line of an expression is stored as a span, and positions within a span are found by
+// their offset from its start. The spans don't overlap, and are sorted by position, so that
+// they can be found with a binary search.
 type SourceMap struct {
-	SourceLinesToTarget map[uint32]map[uint32]Position
-	TargetLinesToSource map[uint32]map[uint32]Position
+	// bySource and byTarget contain the same spans, ordered by source and target position.
+	bySource []Mapping
+	byTarget []Mapping
+	// sourceLines and targetLines are the text of the source and target files, if known.
+	// They're used to convert the byte columns to and from UTF-16 columns.
+	sourceLines []string
+	targetLines []string
+}
+
+// SetText sets the text of the templ source and the generated Go code, so that
+// the UTF-16 lookups can convert between byte and UTF-16 columns.
+func (sm *SourceMap) SetText(source, target string) {
+	sm.sourceLines = strings.Split(source, "\n")
+	sm.targetLines = strings.Split(target, "\n")
 }
 
-// Add an item to the lookup.
+// Add an item to the lookup. Every column of the expression is mapped, including the
+// column after the end of each line, since LSP clients include the newline as a column.
+// If the expression overlaps items that have already been added, it replaces them within
+// the overlap.
 func (sm *SourceMap) Add(src Expression, tgt Range) (updatedFrom Position) {
 	srcIndex := src.Range.From.Index
 	tgtIndex := tgt.From.Index
@@ -35,57 +56,340 @@ func (sm *SourceMap) Add(src Expression, tgt Range) (updatedFrom Position) {
 			tgtCol += tgt.From.Col
 		}
 
-		// Process the cols.
-		for colIndex := 0; colIndex < len(line); colIndex++ {
-			if _, ok := sm.SourceLinesToTarget[srcLine]; !ok {
-				sm.SourceLinesToTarget[srcLine] = make(map[uint32]Position)
-			}
-			sm.SourceLinesToTarget[srcLine][srcCol] = NewPosition(tgtIndex, tgtLine, tgtCol)
-
-			if _, ok := sm.TargetLinesToSource[tgtLine]; !ok {
-				sm.TargetLinesToSource[tgtLine] = make(map[uint32]Position)
-			}
-			sm.TargetLinesToSource[tgtLine][tgtCol] = NewPosition(srcIndex, srcLine, srcCol)
-
-			srcCol++
-			tgtCol++
-			srcIndex++
-			tgtIndex++
+		m := Mapping{
+			Source: NewPosition(srcIndex, srcLine, srcCol),
+			Target: NewPosition(tgtIndex, tgtLine, tgtCol),
+			Length: len(line) + 1,
 		}
+		sm.bySource = insertSpan(sm.bySource, m, mappingSource)
+		sm.byTarget = insertSpan(sm.byTarget, m, mappingTarget)
 
-		// LSPs include the newline char as a col.
-		if _, ok := sm.SourceLinesToTarget[srcLine]; !ok {
-			sm.SourceLinesToTarget[srcLine] = make(map[uint32]Position)
-		}
-		sm.SourceLinesToTarget[srcLine][srcCol] = NewPosition(tgtIndex, tgtLine, tgtCol)
-
-		if _, ok := sm.TargetLinesToSource[tgtLine]; !ok {
-			sm.TargetLinesToSource[tgtLine] = make(map[uint32]Position)
-		}
-		sm.TargetLinesToSource[tgtLine][tgtCol] = NewPosition(srcIndex, srcLine, srcCol)
-
-		srcIndex++
-		tgtIndex++
+		srcIndex += int64(m.Length)
+		tgtIndex += int64(m.Length)
 	}
 	return src.Range.From
 }
 
 // TargetPositionFromSource looks up the target position using the source position.
 func (sm *SourceMap) TargetPositionFromSource(line, col uint32) (tgt Position, ok bool) {
-	lm, ok := sm.SourceLinesToTarget[line]
+	m, offset, ok := findSpan(sm.bySource, line, col, mappingSource)
 	if !ok {
 		return
 	}
-	tgt, ok = lm[col]
-	return
+	return m.Target.add(offset), true
 }
 
 // SourcePositionFromTarget looks the source position using the target position.
 func (sm *SourceMap) SourcePositionFromTarget(line, col uint32) (src Position, ok bool) {
-	lm, ok := sm.TargetLinesToSource[line]
+	m, offset, ok := findSpan(sm.byTarget, line, col, mappingTarget)
 	if !ok {
 		return
 	}
-	src, ok = lm[col]
+	return m.Source.add(offset), true
+}
+
+func mappingSource(m Mapping) Position { return m.Source }
+func mappingTarget(m Mapping) Position { return m.Target }
+
+// add moves the position n columns to the right.
+func (p Position) add(n uint32) Position {
+	return NewPosition(p.Index+int64(n), p.Line, p.Col+n)
+}
+
+// endsAfter returns true if the span, ordered by the position returned by key, is on a later
+// line than the position, or ends after it.
+func endsAfter(m Mapping, line, col uint32, key func(Mapping) Position) bool {
+	start := key(m)
+	return start.Line > line || (start.Line == line && start.Col+uint32(m.Length) > col)
+}
+
+// findSpan finds the span that contains the position, and the offset of the position within it.
+func findSpan(spans []Mapping, line, col uint32, key func(Mapping) Position) (m Mapping, offset uint32, ok bool) {
+	i := sort.Search(len(spans), func(i int) bool { return endsAfter(spans[i], line, col, key) })
+	if i == len(spans) {
+		return
+	}
+	start := key(spans[i])
+	if start.Line != line || start.Col > col {
+		return
+	}
+	return spans[i], col - start.Col, true
+}
+
+// insertSpan adds the span to the spans, which are ordered by the position returned by key.
+// The parts of existing spans that overlap the new span are removed.
+func insertSpan(spans []Mapping, m Mapping, key func(Mapping) Position) []Mapping {
+	start := key(m)
+	end := start.Col + uint32(m.Length)
+	// The spans from i to j overlap the new span.
+	i := sort.Search(len(spans), func(i int) bool { return endsAfter(spans[i], start.Line, start.Col, key) })
+	j := i
+	for j < len(spans) && key(spans[j]).Line == start.Line && key(spans[j]).Col < end {
+		j++
+	}
+	replacement := make([]Mapping, 0, 3)
+	// Only the first overlapping span can start before the new span.
+	if j > i {
+		if first := spans[i]; key(first).Col < start.Col {
+			first.Length = int(start.Col - key(first).Col)
+			replacement = append(replacement, first)
+		}
+	}
+	replacement = append(replacement, m)
+	// Only the last overlapping span can end after the new span.
+	if j > i {
+		if last := spans[j-1]; key(last).Col+uint32(last.Length) > end {
+			n := end - key(last).Col
+			replacement = append(replacement, Mapping{Source: last.Source.add(n), Target: last.Target.add(n), Length: last.Length - int(n)})
+		}
+	}
+	return append(spans[:i], append(replacement, spans[j:]...)...)
+}
+
+// MapTargetPositions returns a copy of the source map where each target position has been
+// moved by f, e.g. because the target has been reformatted. Spans are split where f doesn't
+// move consecutive columns to consecutive columns. The text set with SetText is not copied.
+func (sm *SourceMap) MapTargetPositions(f func(Position) Position) *SourceMap {
+	mapped := NewSourceMap()
+	mapped.bySource = mapSpans(sm.bySource, f, mappingSource)
+	mapped.byTarget = mapSpans(sm.byTarget, f, mappingTarget)
+	return mapped
+}
+
+func mapSpans(spans []Mapping, f func(Position) Position, key func(Mapping) Position) (mapped []Mapping) {
+	for _, m := range spans {
+		var current Mapping
+		for offset := 0; offset < m.Length; offset++ {
+			tgt := f(m.Target.add(uint32(offset)))
+			if current.Length > 0 {
+				if next := current.Target.add(uint32(current.Length)); tgt.Line == next.Line && tgt.Col == next.Col {
+					current.Length++
+					continue
+				}
+				mapped = insertSpan(mapped, current, key)
+			}
+			current = Mapping{Source: m.Source.add(uint32(offset)), Target: tgt, Length: 1}
+		}
+		if current.Length > 0 {
+			mapped = insertSpan(mapped, current, key)
+		}
+	}
+	return mapped
+}
+
+// TargetPositionFromSourceUTF16 looks up the target position using a source position
+// where the column is measured in UTF-16 code units, as used by the Language Server
+// Protocol. The column of the returned position is also measured in UTF-16 code units.
+// If the text hasn't been set with SetText, columns are assumed to be bytes.
+func (sm *SourceMap) TargetPositionFromSourceUTF16(line, col uint32) (tgt Position, ok bool) {
+	tgt, ok = sm.TargetPositionFromSource(line, ByteColFromUTF16(lineAt(sm.sourceLines, line), col))
+	tgt.Col = UTF16Col(lineAt(sm.targetLines, tgt.Line), tgt.Col)
+	return
+}
+
+// SourcePositionFromTargetUTF16 looks up the source position using a target position
+// where the column is measured in UTF-16 code units. The column of the returned position
+// is also measured in UTF-16 code units.
+func (sm *SourceMap) SourcePositionFromTargetUTF16(line, col uint32) (src Position, ok bool) {
+	src, ok = sm.SourcePositionFromTarget(line, ByteColFromUTF16(lineAt(sm.targetLines, line), col))
+	src.Col = UTF16Col(lineAt(sm.sourceLines, src.Line), src.Col)
 	return
 }
+
+func lineAt(lines []string, line uint32) string {
+	if int(line) < len(lines) {
+		return lines[line]
+	}
+	return ""
+}
+
+// UTF16Col converts a byte column within the line to a column measured in UTF-16 code units.
+func UTF16Col(line string, byteCol uint32) uint32 {
+	return fromByteCol(line, byteCol, utf16Width)
+}
+
+// ByteColFromUTF16 converts a column measured in UTF-16 code units to a byte column within the line.
+func ByteColFromUTF16(line string, col uint32) uint32 {
+	return toByteCol(line, col, utf16Width)
+}
+
+// RuneCol converts a byte column within the line to a column measured in runes.
+func RuneCol(line string, byteCol uint32) uint32 {
+	return fromByteCol(line, byteCol, runeWidth)
+}
+
+// ByteColFromRune converts a column measured in runes to a byte column within the line.
+func ByteColFromRune(line string, col uint32) uint32 {
+	return toByteCol(line, col, runeWidth)
+}
+
+func utf16Width(r rune) uint32 {
+	if r >= 0x10000 {
+		// Encoded as a surrogate pair.
+		return 2
+	}
+	return 1
+}
+
+func runeWidth(r rune) uint32 {
+	return 1
+}
+
+// fromByteCol counts the width of the runes before byteCol. Columns past the end of the
+// line are counted as one per byte, and a column within a rune is rounded down to its start.
+func fromByteCol(line string, byteCol uint32, width func(r rune) uint32) (col uint32) {
+	for i, r := range line {
+		if uint32(i) >= byteCol {
+			return col
+		}
+		col += width(r)
+	}
+	if byteCol > uint32(len(line)) {
+		col += byteCol - uint32(len(line))
+	}
+	return col
+}
+
+// toByteCol finds the byte offset of the rune at col. Columns past the end of the line are
+// counted as one per byte, and a column within a rune is rounded up to the next rune.
+func toByteCol(line string, col uint32, width func(r rune) uint32) uint32 {
+	var current uint32
+	for i, r := range line {
+		if current >= col {
+			return uint32(i)
+		}
+		current += width(r)
+	}
+	if col > current {
+		return uint32(len(line)) + col - current
+	}
+	return uint32(len(line))
+}
+
+// Mapping is a run of consecutive characters in the source that map to
+// consecutive characters in the target.
+type Mapping struct {
+	Source Position
+	Target Position
+	Length int
+}
+
+// Mappings returns the contents of the source map as a list of runs, ordered
+// by their position in the source.
+func (sm *SourceMap) Mappings() (mappings []Mapping) {
+	return mergeSpans(sm.bySource)
+}
+
+// mergeSpans joins spans that are adjacent in both the source and the target.
+func mergeSpans(spans []Mapping) (mappings []Mapping) {
+	for _, m := range spans {
+		if len(mappings) > 0 {
+			last := &mappings[len(mappings)-1]
+			if last.Source.Line == m.Source.Line && last.Source.Col+uint32(last.Length) == m.Source.Col &&
+				last.Target.Line == m.Target.Line && last.Target.Col+uint32(last.Length) == m.Target.Col {
+				last.Length += m.Length
+				continue
+			}
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings
+}
+
+// SourceMapVersion is the version of the JSON representation of a SourceMap. It's
+// incremented if the schema changes in a way that older readers can't handle.
+const SourceMapVersion = 1
+
+// sourceMapJSON is the JSON representation of a SourceMap:
+//
+//	{
+//	  "version": 1,
+//	  "bySource": [
+//	    {
+//	      "source": { "index": 120, "line": 5, "col": 4 },
+//	      "target": { "index": 2048, "line": 61, "col": 30 },
+//	      "length": 12
+//	    }
+//	  ],
+//	  "byTarget": [ ... ]
+//	}
+//
+// Lines and columns are zero-based, and columns are byte offsets from the start of the
+// line. Each mapping is a run of length bytes on a single line, starting at source in the
+// templ file, and target in the generated Go code.
+//
+// The mappings in bySource don't overlap in the source, are ordered by source position,
+// and are used to find the target of a source position. The mappings in byTarget are the
+// same, but for the target. They only differ where an expression in the templ file has
+// been mapped more than once.
+type sourceMapJSON struct {
+	Version  int           `json:"version"`
+	BySource []mappingJSON `json:"bySource"`
+	ByTarget []mappingJSON `json:"byTarget"`
+}
+
+type mappingJSON struct {
+	Source positionJSON `json:"source"`
+	Target positionJSON `json:"target"`
+	Length int          `json:"length"`
+}
+
+type positionJSON struct {
+	Index int64  `json:"index"`
+	Line  uint32 `json:"line"`
+	Col   uint32 `json:"col"`
+}
+
+// MarshalJSON writes the mappings of the source map, see SourceMapVersion. The text set
+// with SetText is not included.
+func (sm *SourceMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sourceMapJSON{
+		Version:  SourceMapVersion,
+		BySource: toMappingJSON(mergeSpans(sm.bySource)),
+		ByTarget: toMappingJSON(mergeSpans(sm.byTarget)),
+	})
+}
+
+// UnmarshalJSON reads a source map written by MarshalJSON, replacing any existing mappings.
+func (sm *SourceMap) UnmarshalJSON(data []byte) (err error) {
+	var v sourceMapJSON
+	if err = json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Version != SourceMapVersion {
+		return fmt.Errorf("unsupported source map version %d, expected %d", v.Version, SourceMapVersion)
+	}
+	if sm.bySource, err = fromMappingJSON(v.BySource, mappingSource); err != nil {
+		return err
+	}
+	if sm.byTarget, err = fromMappingJSON(v.ByTarget, mappingTarget); err != nil {
+		return err
+	}
+	return nil
+}
+
+func toMappingJSON(spans []Mapping) []mappingJSON {
+	mappings := make([]mappingJSON, len(spans))
+	for i, m := range spans {
+		mappings[i] = mappingJSON{
+			Source: positionJSON(m.Source),
+			Target: positionJSON(m.Target),
+			Length: m.Length,
+		}
+	}
+	return mappings
+}
+
+func fromMappingJSON(mappings []mappingJSON, key func(Mapping) Position) (spans []Mapping, err error) {
+	for _, m := range mappings {
+		if m.Length <= 0 {
+			return nil, fmt.Errorf("invalid source map: mapping from line %d, col %d has a length of %d", m.Source.Line, m.Source.Col, m.Length)
+		}
+		spans = insertSpan(spans, Mapping{
+			Source: Position(m.Source),
+			Target: Position(m.Target),
+			Length: m.Length,
+		}, key)
+	}
+	return spans, nil
+}
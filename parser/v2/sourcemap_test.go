@@ -1,7 +1,9 @@
 package parser
 
 import (
-	"sort"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
@@ -124,17 +126,7 @@ func TestSourceMapPosition(t *testing.T) {
 				t.Errorf("TargetPositionFromSource: expected result from source %v, got no results", tt.source)
 			}
 			if diff := cmp.Diff(tt.target, actualTarget); diff != "" {
-				lines := keys(sm.SourceLinesToTarget)
-				sort.Slice(lines, func(i, j int) bool {
-					return lines[i] < lines[j]
-				})
-				for _, lineIndex := range lines {
-					cols := keys(sm.SourceLinesToTarget[lineIndex])
-					sort.Slice(cols, func(i, j int) bool {
-						return cols[i] < cols[j]
-					})
-					t.Error(lineIndex, cols)
-				}
+				t.Error(sm.Mappings())
 				t.Error("TargetPositionFromSource\n\n" + diff)
 			}
 			actualSource, ok := sm.SourcePositionFromTarget(actualTarget.Line, actualTarget.Col)
@@ -148,9 +140,369 @@ func TestSourceMapPosition(t *testing.T) {
 	}
 }
 
-func keys[K comparable, V any](m map[K]V) (keys []K) {
-	for k := range m {
-		keys = append(keys, k)
+func TestSourceMapMappings(t *testing.T) {
+	sm := NewSourceMap()
+	sm.Add(NewExpression("abc", pos(10, 1, 1), pos(12, 1, 3)),
+		Range{From: NewPosition(20, 5, 1), To: NewPosition(22, 5, 3)})
+	sm.Add(NewExpression("de", pos(0, 0, 0), pos(1, 0, 1)),
+		Range{From: NewPosition(40, 8, 4), To: NewPosition(41, 8, 5)})
+
+	expected := []Mapping{
+		{Source: NewPosition(0, 0, 0), Target: NewPosition(40, 8, 4), Length: 3},
+		{Source: NewPosition(10, 1, 1), Target: NewPosition(20, 5, 1), Length: 4},
+	}
+	if diff := cmp.Diff(expected, sm.Mappings()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSourceMapRoundTrip(t *testing.T) {
+	for _, length := range []int{1, 2, 3, 10, 100, 1000} {
+		length := length
+		t.Run(fmt.Sprintf("%d characters", length), func(t *testing.T) {
+			sm := NewSourceMap()
+			// The expression starts at line 2, col 7 of the source, and line 40, col 12 of the target.
+			expr := strings.Repeat("x", length)
+			sm.Add(NewExpression(expr, pos(100, 2, 7), pos(100+length, 2, 7+length)),
+				Range{From: NewPosition(900, 40, 12), To: NewPosition(int64(900+length), 40, uint32(12+length))})
+			for _, offset := range []uint32{0, uint32(length) / 2, uint32(length) - 1} {
+				tgt, ok := sm.TargetPositionFromSource(2, 7+offset)
+				if !ok {
+					t.Fatalf("offset %d: expected the source to be mapped", offset)
+				}
+				if expected := NewPosition(900+int64(offset), 40, 12+offset); tgt != expected {
+					t.Errorf("offset %d: expected target %v, got %v", offset, expected, tgt)
+				}
+				src, ok := sm.SourcePositionFromTarget(tgt.Line, tgt.Col)
+				if !ok {
+					t.Fatalf("offset %d: expected the target to be mapped", offset)
+				}
+				if expected := NewPosition(100+int64(offset), 2, 7+offset); src != expected {
+					t.Errorf("offset %d: expected source %v, got %v", offset, expected, src)
+				}
+			}
+			// The column after the end of the expression is mapped, but no further.
+			if _, ok := sm.TargetPositionFromSource(2, 7+uint32(length)); !ok {
+				t.Error("expected the end of the expression to be mapped")
+			}
+			if tgt, ok := sm.TargetPositionFromSource(2, 8+uint32(length)); ok {
+				t.Errorf("expected the column after the end of the expression not to be mapped, got %v", tgt)
+			}
+			if tgt, ok := sm.TargetPositionFromSource(2, 6); ok {
+				t.Errorf("expected the column before the expression not to be mapped, got %v", tgt)
+			}
+		})
+	}
+}
+
+func TestSourceMapOverlappingSpans(t *testing.T) {
+	sm := NewSourceMap()
+	// 0123456789
+	// abcdefghi
+	sm.Add(NewExpression("abcdefghi", pos(0, 0, 0), pos(9, 0, 9)),
+		Range{From: NewPosition(100, 1, 0), To: NewPosition(109, 1, 9)})
+	// def is mapped somewhere else.
+	sm.Add(NewExpression("def", pos(3, 0, 3), pos(6, 0, 6)),
+		Range{From: NewPosition(200, 2, 0), To: NewPosition(203, 2, 3)})
+	expected := []Mapping{
+		{Source: NewPosition(0, 0, 0), Target: NewPosition(100, 1, 0), Length: 3},
+		{Source: NewPosition(3, 0, 3), Target: NewPosition(200, 2, 0), Length: 4},
+		{Source: NewPosition(7, 0, 7), Target: NewPosition(107, 1, 7), Length: 3},
+	}
+	if diff := cmp.Diff(expected, sm.Mappings()); diff != "" {
+		t.Error(diff)
+	}
+	tests := []struct {
+		source Position
+		target Position
+	}{
+		{source: NewPosition(1, 0, 1), target: NewPosition(101, 1, 1)},
+		{source: NewPosition(4, 0, 4), target: NewPosition(201, 2, 1)},
+		{source: NewPosition(8, 0, 8), target: NewPosition(108, 1, 8)},
+	}
+	for _, tt := range tests {
+		if tgt, ok := sm.TargetPositionFromSource(tt.source.Line, tt.source.Col); !ok || tgt != tt.target {
+			t.Errorf("source %v: expected target %v, got %v", tt.source, tt.target, tgt)
+		}
+		if src, ok := sm.SourcePositionFromTarget(tt.target.Line, tt.target.Col); !ok || src != tt.source {
+			t.Errorf("target %v: expected source %v, got %v", tt.target, tt.source, src)
+		}
+	}
+	// The target of the replaced columns is still mapped back to the source.
+	if src, ok := sm.SourcePositionFromTarget(1, 4); !ok || src != NewPosition(4, 0, 4) {
+		t.Errorf("expected the original target to map to the source, got %v", src)
+	}
+}
+
+func TestColumnConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		byteCol  uint32
+		utf16Col uint32
+		runeCol  uint32
+	}{
+		{
+			name: "ASCII columns are the same",
+			line: "abc", byteCol: 2, utf16Col: 2, runeCol: 2,
+		},
+		{
+			name: "CJK characters are 3 bytes, but a single UTF-16 code unit",
+			line: "你好 { name }", byteCol: 7, utf16Col: 3, runeCol: 3,
+		},
+		{
+			name: "emoji are 4 bytes, and a UTF-16 surrogate pair",
+			line: "😀 { name }", byteCol: 5, utf16Col: 3, runeCol: 2,
+		},
+		{
+			name: "the end of the line",
+			line: "😀你", byteCol: 7, utf16Col: 3, runeCol: 2,
+		},
+		{
+			name: "columns past the end of the line are counted as single bytes",
+			line: "😀", byteCol: 6, utf16Col: 4, runeCol: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := UTF16Col(tt.line, tt.byteCol); actual != tt.utf16Col {
+				t.Errorf("UTF16Col: expected %d, got %d", tt.utf16Col, actual)
+			}
+			if actual := ByteColFromUTF16(tt.line, tt.utf16Col); actual != tt.byteCol {
+				t.Errorf("ByteColFromUTF16: expected %d, got %d", tt.byteCol, actual)
+			}
+			if actual := RuneCol(tt.line, tt.byteCol); actual != tt.runeCol {
+				t.Errorf("RuneCol: expected %d, got %d", tt.runeCol, actual)
+			}
+			if actual := ByteColFromRune(tt.line, tt.runeCol); actual != tt.byteCol {
+				t.Errorf("ByteColFromRune: expected %d, got %d", tt.byteCol, actual)
+			}
+		})
+	}
+}
+
+func TestSourceMapUTF16(t *testing.T) {
+	source := "<p>😀 { name }</p>"
+	target := "\tvar_1 := 日本 + name"
+	sm := NewSourceMap()
+	sm.Add(NewExpression("name", pos(10, 0, 10), pos(14, 0, 14)),
+		Range{From: NewPosition(19, 0, 19), To: NewPosition(23, 0, 23)})
+	t.Run("without text, columns are bytes", func(t *testing.T) {
+		tgt, ok := sm.TargetPositionFromSourceUTF16(0, 10)
+		if !ok || tgt.Col != 19 {
+			t.Errorf("expected col 19, got %v (ok=%v)", tgt, ok)
+		}
+	})
+	sm.SetText(source, target)
+	t.Run("source UTF-16 columns are mapped to target UTF-16 columns", func(t *testing.T) {
+		tgt, ok := sm.TargetPositionFromSourceUTF16(0, 8)
+		if !ok || tgt.Col != 15 {
+			t.Errorf("expected col 15, got %v (ok=%v)", tgt, ok)
+		}
+	})
+	t.Run("target UTF-16 columns are mapped to source UTF-16 columns", func(t *testing.T) {
+		src, ok := sm.SourcePositionFromTargetUTF16(0, 15)
+		if !ok || src.Col != 8 {
+			t.Errorf("expected col 8, got %v (ok=%v)", src, ok)
+		}
+	})
+}
+
+func TestSourceMapPositionsBetweenMappings(t *testing.T) {
+	sm := NewSourceMap()
+	// 0123456789012
+	// { abc } { de }
+	sm.Add(NewExpression("abc", pos(2, 0, 2), pos(5, 0, 5)),
+		Range{From: NewPosition(20, 3, 10), To: NewPosition(23, 3, 13)})
+	sm.Add(NewExpression("de", pos(10, 0, 10), pos(12, 0, 12)),
+		Range{From: NewPosition(40, 4, 10), To: NewPosition(42, 4, 12)})
+	// Positions that aren't within an expression, or at the end of one, aren't mapped,
+	// rather than being mapped to the nearest expression.
+	for _, col := range []uint32{0, 1, 6, 7, 8, 9, 13} {
+		if tgt, ok := sm.TargetPositionFromSource(0, col); ok {
+			t.Errorf("col %d: expected no mapping, got %v", col, tgt)
+		}
+	}
+	for _, col := range []uint32{0, 9, 14, 20} {
+		if src, ok := sm.SourcePositionFromTarget(3, col); ok {
+			t.Errorf("target col %d: expected no mapping, got %v", col, src)
+		}
+	}
+	if tgt, ok := sm.TargetPositionFromSource(1, 2); ok {
+		t.Errorf("expected lines without expressions not to be mapped, got %v", tgt)
+	}
+}
+
+// columnSourceMap is the previous implementation of the source map, which stored a map entry
+// for every column. It's used to compare the performance of lookups.
+type columnSourceMap map[uint32]map[uint32]Position
+
+func (sm columnSourceMap) add(src, tgt Position, length int) {
+	for i := 0; i < length; i++ {
+		if _, ok := sm[src.Line]; !ok {
+			sm[src.Line] = make(map[uint32]Position)
+		}
+		sm[src.Line][src.Col+uint32(i)] = tgt.add(uint32(i))
 	}
+}
+
+func (sm columnSourceMap) TargetPositionFromSource(line, col uint32) (tgt Position, ok bool) {
+	tgt, ok = sm[line][col]
 	return
 }
+
+// benchmarkMappings creates n mappings, with 5 expressions on each line, and the expressions
+// to add to a SourceMap to create them.
+func TestSourceMapJSON(t *testing.T) {
+	mappings, exprs := benchmarkMappings(100)
+	sm := newBenchmarkSourceMap(mappings, exprs)
+	// Overlapping expressions, and expressions that span lines, are also stored.
+	sm.Add(NewExpression("abcdefghi", pos(0, 0, 0), pos(9, 0, 9)), Range{From: NewPosition(50000, 500, 0)})
+	sm.Add(NewExpression("def\nghi", pos(3, 0, 3), pos(10, 1, 3)), Range{From: NewPosition(60000, 600, 2)})
+
+	data, err := json.Marshal(sm)
+	if err != nil {
+		t.Fatalf("failed to marshal source map: %v", err)
+	}
+	actual := NewSourceMap()
+	if err = json.Unmarshal(data, actual); err != nil {
+		t.Fatalf("failed to unmarshal source map: %v", err)
+	}
+	if diff := cmp.Diff(sm.Mappings(), actual.Mappings()); diff != "" {
+		t.Error(diff)
+	}
+	for line := uint32(0); line < 700; line++ {
+		for col := uint32(0); col < 250; col++ {
+			expectedTarget, expectedOK := sm.TargetPositionFromSource(line, col)
+			actualTarget, actualOK := actual.TargetPositionFromSource(line, col)
+			if actualOK != expectedOK || actualTarget != expectedTarget {
+				t.Fatalf("source %d:%d: expected %v (%v), got %v (%v)", line, col, expectedTarget, expectedOK, actualTarget, actualOK)
+			}
+			expectedSource, expectedOK := sm.SourcePositionFromTarget(line, col)
+			actualSource, actualOK := actual.SourcePositionFromTarget(line, col)
+			if actualOK != expectedOK || actualSource != expectedSource {
+				t.Fatalf("target %d:%d: expected %v (%v), got %v (%v)", line, col, expectedSource, expectedOK, actualSource, actualOK)
+			}
+		}
+	}
+}
+
+func TestSourceMapJSONSchema(t *testing.T) {
+	sm := NewSourceMap()
+	sm.Add(NewExpression("abc", pos(10, 1, 1), pos(12, 1, 3)),
+		Range{From: NewPosition(20, 5, 1), To: NewPosition(22, 5, 3)})
+	data, err := json.Marshal(sm)
+	if err != nil {
+		t.Fatalf("failed to marshal source map: %v", err)
+	}
+	mapping := `{"source":{"index":10,"line":1,"col":1},"target":{"index":20,"line":5,"col":1},"length":4}`
+	expected := `{"version":1,"bySource":[` + mapping + `],"byTarget":[` + mapping + `]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+	if err = json.Unmarshal([]byte(`{"version":2,"bySource":[],"byTarget":[]}`), NewSourceMap()); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+}
+
+func TestSourceMapMapTargetPositions(t *testing.T) {
+	sm := NewSourceMap()
+	// The target "a+b" is reformatted to "a + b" on the next line.
+	sm.Add(NewExpression("a+b", pos(0, 0, 0), pos(3, 0, 3)), Range{From: NewPosition(10, 1, 4)})
+	mapped := sm.MapTargetPositions(func(p Position) Position {
+		col := p.Col - 4
+		if col > 0 {
+			col++
+		}
+		if col > 2 {
+			col++
+		}
+		return NewPosition(int64(20+col), 2, col)
+	})
+	expected := []Mapping{
+		{Source: NewPosition(0, 0, 0), Target: NewPosition(20, 2, 0), Length: 1},
+		{Source: NewPosition(1, 0, 1), Target: NewPosition(22, 2, 2), Length: 1},
+		{Source: NewPosition(2, 0, 2), Target: NewPosition(24, 2, 4), Length: 2},
+	}
+	if diff := cmp.Diff(expected, mapped.Mappings()); diff != "" {
+		t.Error(diff)
+	}
+	if src, ok := mapped.SourcePositionFromTarget(2, 4); !ok || src != NewPosition(2, 0, 2) {
+		t.Errorf("expected the target to map to the source, got %v", src)
+	}
+	if src, ok := mapped.SourcePositionFromTarget(2, 1); ok {
+		t.Errorf("expected the inserted space not to be mapped, got %v", src)
+	}
+}
+
+func benchmarkMappings(n int) (mappings []Mapping, exprs []Expression) {
+	for i := 0; i < n; i++ {
+		line, col := uint32(i/5), uint32(i%5)*40
+		m := Mapping{
+			Source: NewPosition(int64(i*200), line, col),
+			Target: NewPosition(int64(i*300), line*3, col+15),
+			// The column after the end of the expression is also mapped.
+			Length: 10 + i%20 + 1,
+		}
+		mappings = append(mappings, m)
+		exprs = append(exprs, Expression{
+			Value: strings.Repeat("x", m.Length-1),
+			Range: Range{From: m.Source},
+		})
+	}
+	return mappings, exprs
+}
+
+func newBenchmarkSourceMap(mappings []Mapping, exprs []Expression) *SourceMap {
+	sm := NewSourceMap()
+	for i, m := range mappings {
+		sm.Add(exprs[i], Range{From: m.Target})
+	}
+	return sm
+}
+
+func BenchmarkSourceMapLookup(b *testing.B) {
+	mappings, exprs := benchmarkMappings(5000)
+	b.Run("spans", func(b *testing.B) {
+		sm := newBenchmarkSourceMap(mappings, exprs)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m := mappings[i%len(mappings)]
+			if _, ok := sm.TargetPositionFromSource(m.Source.Line, m.Source.Col+uint32(m.Length/2)); !ok {
+				b.Fatal("expected a mapping")
+			}
+		}
+	})
+	b.Run("columns", func(b *testing.B) {
+		sm := make(columnSourceMap)
+		for _, m := range mappings {
+			sm.add(m.Source, m.Target, m.Length)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m := mappings[i%len(mappings)]
+			if _, ok := sm.TargetPositionFromSource(m.Source.Line, m.Source.Col+uint32(m.Length/2)); !ok {
+				b.Fatal("expected a mapping")
+			}
+		}
+	})
+}
+
+func BenchmarkSourceMapAdd(b *testing.B) {
+	mappings, exprs := benchmarkMappings(5000)
+	b.Run("spans", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			newBenchmarkSourceMap(mappings, exprs)
+		}
+	})
+	b.Run("columns", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sm := make(columnSourceMap)
+			for _, m := range mappings {
+				sm.add(m.Source, m.Target, m.Length)
+			}
+		}
+	})
+}
@@ -80,6 +80,18 @@ default:
 							Whitespace{Value: "\t"},
 							Element{
 								Name: "span",
+								NameRange: Range{
+									From: Position{
+										Index: 30,
+										Line:  2,
+										Col:   2,
+									},
+									To: Position{
+										Index: 34,
+										Line:  2,
+										Col:   6,
+									},
+								},
 								Children: []Node{
 									Whitespace{Value: "\n\t  "},
 									StringExpression{
@@ -152,6 +164,18 @@ default:
 						Children: []Node{
 							Element{
 								Name: "span",
+								NameRange: Range{
+									From: Position{
+										Index: 37,
+										Line:  2,
+										Col:   1,
+									},
+									To: Position{
+										Index: 41,
+										Line:  2,
+										Col:   5,
+									},
+								},
 								Children: []Node{
 									Whitespace{Value: "\n  "},
 									StringExpression{
@@ -289,6 +313,74 @@ default:
 				},
 			},
 		},
+		{
+			name: "switch: type switch",
+			input: `switch v := x.(type) {
+	case int:
+		{ "int" }
+}`,
+			expected: SwitchExpression{
+				Expression: Expression{
+					Value: `v := x.(type)`,
+					Range: Range{
+						From: Position{
+							Index: 7,
+							Line:  0,
+							Col:   7,
+						},
+						To: Position{
+							Index: 20,
+							Line:  0,
+							Col:   20,
+						},
+					},
+				},
+				Cases: []CaseExpression{
+					{
+						Expression: Expression{
+							Value: "case int:",
+							Range: Range{
+								From: Position{
+									Index: 24,
+									Line:  1,
+									Col:   1,
+								},
+								To: Position{
+									Index: 33,
+									Line:  1,
+									Col:   10,
+								},
+							},
+						},
+						Children: []Node{
+							Whitespace{
+								Value: "\t\t",
+							},
+							StringExpression{
+								Expression: Expression{
+									Value: `"int"`,
+									Range: Range{
+										From: Position{
+											Index: 38,
+											Line:  2,
+											Col:   4,
+										},
+										To: Position{
+											Index: 43,
+											Line:  2,
+											Col:   9,
+										},
+									},
+								},
+							},
+							Whitespace{
+								Value: "\n",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
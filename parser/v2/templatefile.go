@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,12 +13,39 @@ import (
 	"github.com/a-h/parse"
 )
 
-func Parse(fileName string) (TemplateFile, error) {
-	fc, err := os.ReadFile(fileName)
+// Parse reads a template file from r and parses it.
+func Parse(r io.Reader) (TemplateFile, error) {
+	return parseReader(r, 0)
+}
+
+// ParseFile reads and parses the template file at path. Errors found while parsing
+// are returned as a FileError, which includes the path in its message.
+func ParseFile(path string) (tf TemplateFile, err error) {
+	f, err := os.Open(path)
 	if err != nil {
+		return tf, err
+	}
+	defer f.Close()
+	var size int
+	if fi, err := f.Stat(); err == nil {
+		size = int(fi.Size())
+	}
+	if tf, err = parseReader(f, size); err != nil {
+		return tf, FileError{FileName: path, Err: err}
+	}
+	return tf, nil
+}
+
+// parseReader reads r into a buffer of the expected size, so that the file is held in
+// memory once, and the parser uses the buffer directly without copying it.
+func parseReader(r io.Reader, size int) (TemplateFile, error) {
+	var sb strings.Builder
+	// Allow for a trailing read to detect the end of the file without growing the buffer.
+	sb.Grow(size + bytes.MinRead)
+	if _, err := io.Copy(&sb, r); err != nil {
 		return TemplateFile{}, err
 	}
-	return ParseString(string(fc))
+	return ParseString(sb.String())
 }
 
 func getDefaultPackageName(fileName string) (pkg string) {
@@ -63,7 +93,13 @@ func NewTemplateFileParser(pkg string) TemplateFileParser {
 var ErrLegacyFileFormat = errors.New("Legacy file format - run templ migrate")
 var ErrTemplateNotFound = errors.New("Template not found")
 
+// ErrMissingPackage is returned when a templ file doesn't start with a package declaration.
+// It's positioned at the start of the file, so that editors show it on the first line.
+var ErrMissingPackage = parse.Error(`missing package declaration, templ files must start with a package, e.g. "package main"`, parse.Position{})
+
 type TemplateFileParser struct {
+	// Deprecated: templ files must start with a package declaration, so the default
+	// package isn't used.
 	DefaultPackage string
 }
 
@@ -79,30 +115,38 @@ func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, er
 
 	// Required package.
 	// package name
-	from := pi.Position()
 	tf.Package, ok, err = pkg.Parse(pi)
 	if err != nil {
 		return
 	}
 	if !ok {
-		tf.Package = Package{
-			Expression: NewExpression("package "+p.DefaultPackage, from, pi.Position()),
-		}
+		return tf, false, ErrMissingPackage
 	}
 
 	// Optional whitespace.
 	_, _, _ = parse.OptionalWhitespace.Parse(pi)
 
+	var errs ParseErrors
+	var start int
+	// The comments directly above the next declaration, which are split from the Go code.
+	var doc string
 outer:
 	for {
 		// Optional templates, CSS, and script templates.
 		// templ Name(p Parameter)
 		var tn HTMLTemplate
+		start = pi.Index()
 		tn, ok, err = template.Parse(pi)
 		if err != nil {
-			return tf, false, err
+			if !errs.add(err) {
+				return tf, false, err
+			}
+			skipToNextDeclaration(pi, start)
+			doc = ""
+			continue
 		}
 		if ok {
+			tn.Doc, doc = doc, ""
 			tf.Nodes = append(tf.Nodes, tn)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
 			continue
@@ -110,11 +154,18 @@ outer:
 
 		// css Name()
 		var cn CSSTemplate
+		start = pi.Index()
 		cn, ok, err = cssParser.Parse(pi)
 		if err != nil {
-			return tf, false, err
+			if !errs.add(err) {
+				return tf, false, err
+			}
+			skipToNextDeclaration(pi, start)
+			doc = ""
+			continue
 		}
 		if ok {
+			cn.Doc, doc = doc, ""
 			tf.Nodes = append(tf.Nodes, cn)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
 			continue
@@ -122,50 +173,63 @@ outer:
 
 		// script Name()
 		var sn ScriptTemplate
+		start = pi.Index()
 		sn, ok, err = scriptTemplateParser.Parse(pi)
 		if err != nil {
-			return tf, false, err
+			if !errs.add(err) {
+				return tf, false, err
+			}
+			skipToNextDeclaration(pi, start)
+			doc = ""
+			continue
 		}
 		if ok {
+			sn.Doc, doc = doc, ""
 			tf.Nodes = append(tf.Nodes, sn)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
 			continue
 		}
 
 		// Anything that isn't template content is Go code.
-		var code strings.Builder
+		var lines []codeLine
 		from := pi.Position()
 	inner:
 		for {
 			// Check to see if this line isn't Go code.
 			last := pi.Index()
+			lineFrom := pi.Position()
 			var l string
 			if l, ok, err = parse.StringUntil(parse.Or(parse.NewLine, parse.EOF[string]())).Parse(pi); err != nil {
 				return
 			}
-			hasTemplatePrefix := strings.HasPrefix(l, "templ ") || strings.HasPrefix(l, "css ") || strings.HasPrefix(l, "script ")
-			if hasTemplatePrefix && strings.HasSuffix(l, "{") {
+			if isDeclarationStart(l) {
 				// Unread the line.
 				pi.Seek(last)
+				// The comments directly above the declaration are its doc comments.
+				to := pi.Position()
+				i := docStart(lines)
+				if i < len(lines) {
+					doc = docComments(lines[i:])
+					to = lines[i].from
+				}
 				// Take the code so far.
-				if code.Len() > 0 {
-					expr := NewExpression(strings.TrimSpace(code.String()), from, pi.Position())
+				if code := joinLines(lines[:i]); strings.TrimSpace(code) != "" {
+					expr := NewExpression(strings.TrimSpace(code), from, to)
 					tf.Nodes = append(tf.Nodes, GoExpression{Expression: expr})
 				}
 				// Carry on parsing.
 				break inner
 			}
-			code.WriteString(l)
 
 			// Eat the newline or EOF that we read until.
 			var newLine string
 			if newLine, ok, err = parse.NewLine.Parse(pi); err != nil {
 				return
 			}
-			code.WriteString(newLine)
+			lines = append(lines, codeLine{text: l + newLine, from: lineFrom})
 			if _, isEOF, _ := parse.EOF[string]().Parse(pi); isEOF {
-				if code.Len() > 0 {
-					expr := NewExpression(strings.TrimSpace(code.String()), from, pi.Position())
+				if code := joinLines(lines); code != "" {
+					expr := NewExpression(strings.TrimSpace(code), from, pi.Position())
 					tf.Nodes = append(tf.Nodes, GoExpression{Expression: expr})
 				}
 				// Stop parsing.
@@ -174,5 +238,163 @@ outer:
 		}
 	}
 
+	if len(errs) > 0 {
+		return tf, false, errs
+	}
 	return tf, true, nil
 }
+
+// codeLine is a line of the Go code between declarations, including its line break.
+type codeLine struct {
+	text string
+	from parse.Position
+}
+
+// docStart returns the index of the first of the comment lines at the end of the lines, or
+// len(lines) if the last line isn't a comment, e.g. because it's blank.
+func docStart(lines []codeLine) int {
+	i := len(lines)
+	for i > 0 && isCommentLine(lines[i-1].text) {
+		i--
+	}
+	return i
+}
+
+// isCommentLine returns true if the line is a // comment, or a /* comment */ that ends on
+// the same line.
+func isCommentLine(l string) bool {
+	l = strings.TrimSpace(l)
+	if strings.HasPrefix(l, "//") {
+		return true
+	}
+	return strings.HasPrefix(l, "/*") && strings.HasSuffix(l, "*/") && !strings.Contains(l[2:len(l)-2], "*/")
+}
+
+func joinLines(lines []codeLine) string {
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line.text)
+	}
+	return sb.String()
+}
+
+// docComments returns the comments, one per line, without surrounding whitespace.
+func docComments(lines []codeLine) string {
+	comments := make([]string, len(lines))
+	for i, line := range lines {
+		comments[i] = strings.TrimSpace(line.text)
+	}
+	return strings.Join(comments, "\n")
+}
+
+// ParseErrors are the errors found while parsing a template file. After an error, parsing
+// continues from the next templ, css or script declaration, so that all of the errors in
+// the file are reported.
+type ParseErrors []ParseError
+
+// ParseError is an error found while parsing a template file. It covers the input from Pos
+// to To, e.g. the whole of an invalid element name. Most errors are found at a position,
+// rather than within a range, and To is the same as Pos.
+type ParseError struct {
+	parse.ParseError
+	To parse.Position
+}
+
+func newRangeError(msg string, from, to parse.Position) ParseError {
+	return ParseError{ParseError: parse.Error(msg, from), To: to}
+}
+
+// Unwrap returns the parse.ParseError, so that errors.As can be used to find it.
+func (e ParseError) Unwrap() error {
+	return e.ParseError
+}
+
+func (pe ParseErrors) Error() string {
+	msgs := make([]string, len(pe))
+	for i, e := range pe {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual errors, so that errors.As can be used to find a parse.ParseError.
+func (pe ParseErrors) Unwrap() []error {
+	errs := make([]error, len(pe))
+	for i, e := range pe {
+		errs[i] = e
+	}
+	return errs
+}
+
+// FileError is an error found while parsing a named file. Parse errors are formatted as
+// path:line:col: message, with 1-based line and column numbers, one per line.
+type FileError struct {
+	FileName string
+	Err      error
+}
+
+func (fe FileError) Error() string {
+	var errs ParseErrors
+	if errors.As(fe.Err, &errs) {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = fe.format(e.ParseError)
+		}
+		return strings.Join(msgs, "\n")
+	}
+	var pe parse.ParseError
+	if errors.As(fe.Err, &pe) {
+		return fe.format(pe)
+	}
+	return fe.FileName + ": " + fe.Err.Error()
+}
+
+func (fe FileError) format(pe parse.ParseError) string {
+	return fmt.Sprintf("%s:%d:%d: %s", fe.FileName, pe.Pos.Line+1, pe.Pos.Col+1, pe.Msg)
+}
+
+func (fe FileError) Unwrap() error {
+	return fe.Err
+}
+
+// add the error to the list if it's a parse error that can be recovered from.
+func (pe *ParseErrors) add(err error) (ok bool) {
+	var rangeError ParseError
+	if errors.As(err, &rangeError) {
+		*pe = append(*pe, rangeError)
+		return true
+	}
+	var parseError parse.ParseError
+	if !errors.As(err, &parseError) {
+		return false
+	}
+	*pe = append(*pe, ParseError{ParseError: parseError, To: parseError.Pos})
+	return true
+}
+
+// skipToNextDeclaration moves the input past a declaration that failed to parse, to the start
+// of the next templ, css or script declaration, or the end of the file.
+func skipToNextDeclaration(pi *parse.Input, start int) {
+	pi.Seek(start)
+	// Skip the line containing the start of the failed declaration.
+	_, _, _ = parse.StringUntil(parse.Or(parse.NewLine, parse.EOF[string]())).Parse(pi)
+	_, _, _ = parse.NewLine.Parse(pi)
+	for {
+		if _, isEOF, _ := parse.EOF[string]().Parse(pi); isEOF {
+			return
+		}
+		lineStart := pi.Index()
+		l, _, _ := parse.StringUntil(parse.Or(parse.NewLine, parse.EOF[string]())).Parse(pi)
+		if isDeclarationStart(l) {
+			pi.Seek(lineStart)
+			return
+		}
+		_, _, _ = parse.NewLine.Parse(pi)
+	}
+}
+
+// isDeclarationStart returns true if the line starts a templ, css or script declaration.
+func isDeclarationStart(l string) bool {
+	hasTemplatePrefix := strings.HasPrefix(l, "templ ") || strings.HasPrefix(l, "css ") || strings.HasPrefix(l, "script ")
+	return hasTemplatePrefix && strings.HasSuffix(l, "{")
+}
@@ -1,8 +1,16 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestTemplateFileParser(t *testing.T) {
@@ -17,16 +25,35 @@ func TestTemplateFileParser(t *testing.T) {
 			t.Errorf("expected ErrLegacyFileFormat, got %v", err)
 		}
 	})
-	t.Run("does not require a package expression", func(t *testing.T) {
+	t.Run("requires a package expression", func(t *testing.T) {
 		input := `templ Hello() {
 Hello
 }`
-		tf, err := ParseString(input)
-		if err != nil {
-			t.Fatalf("failed to parse template, with t.Fatalf(parser %v", err)
+		_, err := ParseString(input)
+		if err != ErrMissingPackage {
+			t.Errorf("expected ErrMissingPackage, got %v", err)
 		}
-		if len(tf.Nodes) != 1 {
-			t.Errorf("expected 1 node, got %d nodes with content %+v", len(tf.Nodes), tf.Nodes)
+	})
+	t.Run("does not require any declarations", func(t *testing.T) {
+		inputs := []string{
+			"package goof",
+			"package goof\n",
+			"package goof\n\nimport \"fmt\"\n\nvar x = fmt.Sprint(1)\n",
+			"package goof\n\n// templ Hello() {\n// }\n",
+		}
+		for _, input := range inputs {
+			tf, err := ParseString(input)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", input, err)
+			}
+			if tf.Package.Expression.Value != "package goof" {
+				t.Errorf("expected \"package goof\", got %q", tf.Package.Expression.Value)
+			}
+			for _, n := range tf.Nodes {
+				if _, ok := n.(GoExpression); !ok {
+					t.Errorf("expected only Go expressions in %q, got %#v", input, n)
+				}
+			}
 		}
 	})
 	t.Run("but can accept a package expression, if one is provided", func(t *testing.T) {
@@ -119,6 +146,60 @@ const y = ` + "`456`"
 			t.Errorf("2: unexpected expression: %q", expr.Expression.Value)
 		}
 	})
+	t.Run("comments directly above declarations are their doc comments", func(t *testing.T) {
+		input := `package goof
+
+const x = "123"
+// Hello says hello.
+//
+// It's a template.
+templ Hello() {
+	Hello
+}
+
+// Not attached.
+
+/* red is a class. */
+css red() {
+	color: red;
+}
+// alert is a script.
+script alert() {
+	alert("hello");
+}
+`
+		tf, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if len(tf.Nodes) != 5 {
+			t.Fatalf("expected 5 nodes, got %d", len(tf.Nodes))
+		}
+		expected := GoExpression{
+			Expression: Expression{
+				Value: `const x = "123"`,
+				Range: Range{
+					From: Position{Index: 14, Line: 2, Col: 0},
+					To:   Position{Index: 30, Line: 3, Col: 0},
+				},
+			},
+		}
+		if diff := cmp.Diff(expected, tf.Nodes[0]); diff != "" {
+			t.Error(diff)
+		}
+		if doc := tf.Nodes[1].(HTMLTemplate).Doc; doc != "// Hello says hello.\n//\n// It's a template." {
+			t.Errorf("unexpected templ doc: %q", doc)
+		}
+		if expr := tf.Nodes[2].(GoExpression).Expression.Value; expr != "// Not attached." {
+			t.Errorf("expected the comment that's separated by a blank line to be Go code, got %q", expr)
+		}
+		if doc := tf.Nodes[3].(CSSTemplate).Doc; doc != "/* red is a class. */" {
+			t.Errorf("unexpected css doc: %q", doc)
+		}
+		if doc := tf.Nodes[4].(ScriptTemplate).Doc; doc != "// alert is a script." {
+			t.Errorf("unexpected script doc: %q", doc)
+		}
+	})
 }
 
 func TestDefaultPackageName(t *testing.T) {
@@ -157,3 +238,209 @@ func TestDefaultPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateFileParserReportsMultipleErrors(t *testing.T) {
+	input := `package main
+
+templ A() {
+	<div>
+}
+
+templ B() {
+	<span>ok</span>
+}
+
+css c() {
+	color: red
+}
+
+templ D() {
+	<a></b>
+}
+`
+	tf, err := ParseString(input)
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ParseErrors, got %v", err)
+	}
+	expected := []parse.ParseError{
+		parse.Error("<div>: expected end tag not present or invalid tag contents", parse.Position{Index: 33, Line: 4, Col: 0}),
+		parse.Error("missing expected semicolon (;)", parse.Position{Index: 86, Line: 11, Col: 8}),
+		parse.Error("<a>: mismatched end tag, expected '</a>', got '</b>'", parse.Position{Index: 109, Line: 15, Col: 4}),
+	}
+	var actual []parse.ParseError
+	for _, e := range errs {
+		if e.To != e.Pos {
+			t.Errorf("expected the error to be at a single position, got %v to %v", e.Pos, e.To)
+		}
+		actual = append(actual, e.ParseError)
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	var pe parse.ParseError
+	if !errors.As(err, &pe) || pe != expected[0] {
+		t.Errorf("expected errors.As to find the first parse error, got %v", pe)
+	}
+	// The declarations between the errors are still parsed.
+	if len(tf.Nodes) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(tf.Nodes))
+	}
+	if name := tf.Nodes[0].(HTMLTemplate).Expression.Value; name != "B()" {
+		t.Errorf("expected templ B to be parsed, got %q", name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "package main\n\ntempl Hello(name string) {\n\t<div>Hello, { name }</div>\n}\n"
+	expected, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse string: %v", err)
+	}
+	t.Run("from a reader", func(t *testing.T) {
+		actual, err := Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("from a file", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "hello.templ")
+		if err := os.WriteFile(fileName, []byte(input), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		actual, err := ParseFile(fileName)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestParseFileErrorsIncludeTheFileName(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "errors.templ")
+	input := "package main\n\ntempl A() {\n\t<div>\n}\n\ntempl D() {\n\t<a></b>\n}\n"
+	if err := os.WriteFile(fileName, []byte(input), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	_, err := ParseFile(fileName)
+	var fe FileError
+	if !errors.As(err, &fe) {
+		t.Fatalf("expected a FileError, got %v", err)
+	}
+	expected := fileName + ":5:1: <div>: expected end tag not present or invalid tag contents\n" +
+		fileName + ":8:5: <a>: mismatched end tag, expected '</a>', got '</b>'"
+	if diff := cmp.Diff(expected, err.Error()); diff != "" {
+		t.Error(diff)
+	}
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("expected the ParseErrors to be wrapped, got %v", err)
+	}
+	t.Run("a missing package is on the first line", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "nopackage.templ")
+		if err := os.WriteFile(fileName, []byte("templ A() {\n}\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		_, err := ParseFile(fileName)
+		expected := fileName + `:1:1: missing package declaration, templ files must start with a package, e.g. "package main"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected %q, got %v", expected, err)
+		}
+	})
+	t.Run("other errors include the file name", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "legacy.templ")
+		if err := os.WriteFile(fileName, []byte("{% package templates %}\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		_, err := ParseFile(fileName)
+		if !errors.Is(err, ErrLegacyFileFormat) {
+			t.Fatalf("expected ErrLegacyFileFormat, got %v", err)
+		}
+		if expected := fileName + ": " + ErrLegacyFileFormat.Error(); err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	})
+}
+
+// benchmarkTemplate returns a template file containing n copies of a typical templ.
+func benchmarkTemplate(n int) string {
+	var sb strings.Builder
+	sb.WriteString("package main\n\nimport \"fmt\"\n\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, `templ Person%d(p Person) {
+	<div class="person">
+		<h1>{ p.Name }</h1>
+		if p.Email != "" {
+			<a href={ templ.URL("mailto:" + p.Email) }>{ p.Email }</a>
+		}
+		<ul>
+			for _, address := range p.Addresses {
+				<li>{ address }</li>
+			}
+		</ul>
+	</div>
+}
+
+`, i)
+	}
+	return sb.String()
+}
+
+// benchmarkSizes are the number of templates in the small, medium and large files that are
+// parsed by the benchmarks, so that regressions that only affect large files are visible.
+var benchmarkSizes = []struct {
+	name string
+	n    int
+}{
+	{"small", 1},
+	{"medium", 100},
+	{"large", 3000},
+}
+
+func BenchmarkParseString(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		input := benchmarkTemplate(size.n)
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseString(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	input := benchmarkTemplate(10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(strings.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseFile(b *testing.B) {
+	for _, n := range []int{10, 30000} {
+		fileName := filepath.Join(b.TempDir(), "template.templ")
+		input := benchmarkTemplate(n)
+		if err := os.WriteFile(fileName, []byte(input), 0644); err != nil {
+			b.Fatalf("failed to write file: %v", err)
+		}
+		b.Run(fmt.Sprintf("%dKB", len(input)/1024), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseFile(fileName); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -76,6 +76,30 @@ func (p templateNodeParser[T]) Parse(pi *parse.Input) (op []Node, ok bool, err e
 		}
 
 		// Try for valid nodes.
+		// Try for an HTML comment.
+		// <!-- comment -->
+		var htmlCommentNode HTMLComment
+		htmlCommentNode, ok, err = htmlComment.Parse(pi)
+		if err != nil {
+			return
+		}
+		if ok {
+			op = append(op, htmlCommentNode)
+			continue
+		}
+
+		// Try for a templ comment.
+		// // comment, or /* comment */
+		var goCommentNode GoComment
+		goCommentNode, ok, err = goComment.Parse(pi)
+		if err != nil {
+			return
+		}
+		if ok {
+			op = append(op, goCommentNode)
+			continue
+		}
+
 		// Try for a doctype.
 		// <!DOCTYPE html>
 		var docTypeNode DocType
@@ -206,8 +230,19 @@ func (p templateNodeParser[T]) Parse(pi *parse.Input) (op []Node, ok bool, err e
 			continue
 		}
 
+		// Try for a character reference.
+		// &nbsp; &#169; &#xA9;
+		var characterReference CharacterReference
+		if characterReference, ok, err = characterReferenceParser.Parse(pi); err != nil {
+			return
+		}
+		if ok {
+			op = append(op, characterReference)
+			continue
+		}
+
 		// Try for text.
-		// anything &amp; everything accepted...
+		// anything & everything accepted...
 		var text Text
 		if text, ok, err = textParser.Parse(pi); err != nil {
 			return
@@ -58,6 +58,28 @@ func TestTemplateParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "template: with type parameters",
+			input: `templ list[T fmt.Stringer, K comparable](items map[K]T) {
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "list[T fmt.Stringer, K comparable](items map[K]T)",
+					Range: Range{
+						From: Position{
+							Index: 6,
+							Line:  0,
+							Col:   6,
+						},
+						To: Position{
+							Index: 55,
+							Line:  0,
+							Col:   55,
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "template: no spaces",
 			input: `templ Name(){
@@ -126,6 +148,18 @@ func TestTemplateParser(t *testing.T) {
 				Children: []Node{
 					Element{
 						Name: "span",
+						NameRange: Range{
+							From: Position{
+								Index: 27,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 31,
+								Line:  1,
+								Col:   5,
+							},
+						},
 						Children: []Node{
 							StringExpression{
 								Expression: Expression{
@@ -181,6 +215,18 @@ func TestTemplateParser(t *testing.T) {
 				Children: []Node{
 					Element{
 						Name: "div",
+						NameRange: Range{
+							From: Position{
+								Index: 27,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 30,
+								Line:  1,
+								Col:   4,
+							},
+						},
 						Children: []Node{
 							Whitespace{Value: "\n  "},
 							StringExpression{
@@ -203,6 +249,18 @@ func TestTemplateParser(t *testing.T) {
 							Whitespace{Value: "\n  "},
 							Element{
 								Name: "span",
+								NameRange: Range{
+									From: Position{
+										Index: 55,
+										Line:  3,
+										Col:   3,
+									},
+									To: Position{
+										Index: 59,
+										Line:  3,
+										Col:   7,
+									},
+								},
 								Children: []Node{
 									Whitespace{Value: "\n\t"},
 									StringExpression{
@@ -279,6 +337,18 @@ func TestTemplateParser(t *testing.T) {
 							Whitespace{Value: "\t\t"},
 							Element{
 								Name: "span",
+								NameRange: Range{
+									From: Position{
+										Index: 42,
+										Line:  2,
+										Col:   3,
+									},
+									To: Position{
+										Index: 46,
+										Line:  2,
+										Col:   7,
+									},
+								},
 								Children: []Node{
 									Whitespace{"\n\t\t\t"},
 									StringExpression{
@@ -305,6 +375,18 @@ func TestTemplateParser(t *testing.T) {
 								Value: "\n\t",
 							},
 						},
+						ThenRange: Range{
+							From: Position{
+								Index: 39,
+								Line:  2,
+								Col:   0,
+							},
+							To: Position{
+								Index: 81,
+								Line:  5,
+								Col:   1,
+							},
+						},
 					},
 					Whitespace{
 						Value: "\n",
@@ -338,6 +420,18 @@ func TestTemplateParser(t *testing.T) {
 					Whitespace{Value: "\t"},
 					Element{
 						Name: "input",
+						NameRange: Range{
+							From: Position{
+								Index: 28,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 33,
+								Line:  1,
+								Col:   7,
+							},
+						},
 						Attributes: []Attribute{
 							ConstantAttribute{Name: "type", Value: "text"},
 							ConstantAttribute{Name: "value", Value: "a"},
@@ -346,6 +440,18 @@ func TestTemplateParser(t *testing.T) {
 					Whitespace{Value: "\n\t"},
 					Element{
 						Name: "input",
+						NameRange: Range{
+							From: Position{
+								Index: 61,
+								Line:  2,
+								Col:   2,
+							},
+							To: Position{
+								Index: 66,
+								Line:  2,
+								Col:   7,
+							},
+						},
 						Attributes: []Attribute{
 							ConstantAttribute{Name: "type", Value: "text"},
 							ConstantAttribute{Name: "value", Value: "b"},
@@ -421,6 +527,18 @@ func TestTemplateParser(t *testing.T) {
 					},
 					Element{
 						Name: "a",
+						NameRange: Range{
+							From: Position{
+								Index: 14,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 15,
+								Line:  1,
+								Col:   3,
+							},
+						},
 						Attributes: []Attribute{
 							ConstantAttribute{
 								Name:  "href",
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"go/token"
 	"unicode"
 
 	"github.com/a-h/parse"
@@ -9,9 +10,7 @@ import (
 
 var templElementStartExpressionParams = parse.StringFrom(
 	parse.String("("),
-	parse.StringFrom[Expression](functionArgsParser{
-		startBracketCount: 1,
-	}),
+	parse.StringFrom[Expression](goExpressionParser{closer: token.RPAREN}),
 	parse.String(")"),
 )
 
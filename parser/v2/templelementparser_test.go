@@ -106,9 +106,24 @@ func TestTemplElementExpressionParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\n\t\t\t"},
-					Element{Name: "a", Attributes: []Attribute{
-						ConstantAttribute{"href", "someurl"},
-					}},
+					Element{
+						Name: "a",
+						NameRange: Range{
+							From: Position{
+								Index: 20,
+								Line:  1,
+								Col:   4,
+							},
+							To: Position{
+								Index: 21,
+								Line:  1,
+								Col:   5,
+							},
+						},
+						Attributes: []Attribute{
+							ConstantAttribute{"href", "someurl"},
+						},
+					},
 					Whitespace{Value: "\n\t\t"},
 				},
 			},
@@ -1,18 +1,35 @@
 package parser
 
 import (
+	"html"
+	"strings"
+
 	"github.com/a-h/parse"
 )
 
-var tagTemplOrNewLine = parse.Any(parse.Rune('<'), parse.Rune('{'), parse.Rune('}'), parse.Rune('\n'))
+var tagTemplNewLineOrAmpersand = parse.Any(parse.Rune('<'), parse.Rune('{'), parse.Rune('}'), parse.Rune('\n'), parse.Rune('&'))
 
 var textParser = parse.Func(func(pi *parse.Input) (t Text, ok bool, err error) {
 	from := pi.Position()
 
-	// Read until a tag or templ expression opens.
-	if t.Value, ok, err = parse.StringUntil(tagTemplOrNewLine).Parse(pi); err != nil || !ok {
-		return
+	// Read until a tag, templ expression or character reference opens.
+	var sb strings.Builder
+	for {
+		var s string
+		if s, ok, err = parse.StringUntil(tagTemplNewLineOrAmpersand).Parse(pi); err != nil || !ok {
+			pi.Seek(int(from.Index))
+			return
+		}
+		sb.WriteString(s)
+		// An ampersand that doesn't start a character reference is part of the text.
+		remaining, _ := pi.Peek(-1)
+		if !strings.HasPrefix(remaining, "&") || characterReferenceLength(remaining) > 0 {
+			break
+		}
+		sb.WriteString("&")
+		pi.Take(1)
 	}
+	t.Value = sb.String()
 	if _, ok = pi.Peek(1); !ok {
 		err = parse.Error("textParser: unterminated text, expected tag open, templ expression open, or newline", from)
 		return
@@ -20,3 +37,92 @@ var textParser = parse.Func(func(pi *parse.Input) (t Text, ok bool, err error) {
 
 	return t, true, nil
 })
+
+// characterReferenceParser parses named, decimal and hexadecimal character references,
+// e.g. &nbsp;, &#169; and &#xA9;.
+var characterReferenceParser = parse.Func(func(pi *parse.Input) (cr CharacterReference, ok bool, err error) {
+	remaining, _ := pi.Peek(-1)
+	n := characterReferenceLength(remaining)
+	if n == 0 {
+		return cr, false, nil
+	}
+	cr.Value, _ = pi.Take(n)
+	return cr, true, nil
+})
+
+// maxCharacterReferenceLength is the length of the longest named character reference,
+// &CounterClockwiseContourIntegral;.
+const maxCharacterReferenceLength = 33
+
+// characterReferenceLength returns the length of the character reference at the start of s,
+// or zero if s doesn't start with a valid reference. References must end with a semicolon.
+func characterReferenceLength(s string) int {
+	if !strings.HasPrefix(s, "&") {
+		return 0
+	}
+	if len(s) > maxCharacterReferenceLength {
+		s = s[:maxCharacterReferenceLength]
+	}
+	end := strings.IndexByte(s, ';')
+	if end < 2 {
+		return 0
+	}
+	name := s[1:end]
+	switch {
+	case strings.HasPrefix(name, "#x") || strings.HasPrefix(name, "#X"):
+		if !isNonEmptyAndAll(name[2:], isHexDigit) {
+			return 0
+		}
+	case strings.HasPrefix(name, "#"):
+		if !isNonEmptyAndAll(name[1:], isDecimalDigit) {
+			return 0
+		}
+	default:
+		// Unknown names are left as-is by the unescaper.
+		if !isNonEmptyAndAll(name, isAlphanumeric) || html.UnescapeString(s[:end+1]) == s[:end+1] {
+			return 0
+		}
+	}
+	return end + 1
+}
+
+// EscapeStrayAmpersands escapes ampersands that don't start a character reference, leaving
+// valid references, e.g. &nbsp;, unchanged.
+func EscapeStrayAmpersands(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '&' && characterReferenceLength(s[i:]) == 0 {
+			sb.WriteString("&amp;")
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func isNonEmptyAndAll(s string, f func(r rune) bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !f(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDecimalDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isHexDigit(r rune) bool {
+	return isDecimalDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+func isAlphanumeric(r rune) bool {
+	return isDecimalDigit(r) || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
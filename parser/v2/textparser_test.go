@@ -35,24 +35,38 @@ func TestTextParser(t *testing.T) {
 			},
 		},
 		{
-			name:  "Text may contain named references",
+			name:  "Text ends at named references",
 			input: `abcdef&nbsp;ghijk{%= "test" %}`,
 			expected: Text{
-				Value: "abcdef&nbsp;ghijk",
+				Value: "abcdef",
 			},
 		},
 		{
-			name:  "Text may contain base 10 numeric references",
+			name:  "Text ends at base 10 numeric references",
 			input: `abcdef&#32;ghijk{%= "test" %}`,
 			expected: Text{
-				Value: "abcdef&#32;ghijk",
+				Value: "abcdef",
 			},
 		},
 		{
-			name:  "Text may contain hexadecimal numeric references",
+			name:  "Text ends at hexadecimal numeric references",
 			input: `abcdef&#x20;ghijk{%= "test" %}`,
 			expected: Text{
-				Value: "abcdef&#x20;ghijk",
+				Value: "abcdef",
+			},
+		},
+		{
+			name:  "Text may contain ampersands that don't start a reference",
+			input: `fish & chips&co &nbsp;<a>`,
+			expected: Text{
+				Value: "fish & chips&co ",
+			},
+		},
+		{
+			name:  "Text may contain unknown named references",
+			input: `a &unknown; b &#xZZ; c &#; d<a>`,
+			expected: Text{
+				Value: "a &unknown; b &#xZZ; c &#; d",
 			},
 		},
 	}
@@ -73,3 +87,60 @@ func TestTextParser(t *testing.T) {
 		})
 	}
 }
+
+func TestCharacterReferenceParser(t *testing.T) {
+	var tests = []struct {
+		input      string
+		expected   CharacterReference
+		expectedOK bool
+	}{
+		{input: `&nbsp;text`, expected: CharacterReference{Value: "&nbsp;"}, expectedOK: true},
+		{input: `&copy;`, expected: CharacterReference{Value: "&copy;"}, expectedOK: true},
+		{input: `&#169;`, expected: CharacterReference{Value: "&#169;"}, expectedOK: true},
+		{input: `&#x2019;`, expected: CharacterReference{Value: "&#x2019;"}, expectedOK: true},
+		{input: `&#X2019;`, expected: CharacterReference{Value: "&#X2019;"}, expectedOK: true},
+		{input: `&CounterClockwiseContourIntegral;`, expected: CharacterReference{Value: "&CounterClockwiseContourIntegral;"}, expectedOK: true},
+		{input: `& chips`},
+		{input: `&nbsp`},
+		{input: `&unknown;`},
+		{input: `&#;`},
+		{input: `&#x;`},
+		{input: `&#12a;`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := characterReferenceParser.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if !ok && input.Index() != 0 {
+				t.Errorf("expected no input to be consumed, but %d characters were", input.Index())
+			}
+		})
+	}
+}
+
+func TestEscapeStrayAmpersands(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{input: "no ampersands", expected: "no ampersands"},
+		{input: "fish & chips", expected: "fish &amp; chips"},
+		{input: "&nbsp;&copy;&#169;&#xA9;", expected: "&nbsp;&copy;&#169;&#xA9;"},
+		{input: "&amp; & &unknown; &nbsp", expected: "&amp; &amp; &amp;unknown; &amp;nbsp"},
+	}
+	for _, tt := range tests {
+		if actual := EscapeStrayAmpersands(tt.input); actual != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
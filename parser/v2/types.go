@@ -2,7 +2,7 @@ package parser
 
 import (
 	"fmt"
-	"html"
+	"go/format"
 	"io"
 	"strings"
 
@@ -45,6 +45,8 @@ import (
 
 // Source mapping to map from the source code of the template to the
 // in-memory representation.
+// Index is the byte offset from the start of the file, and Col is the byte offset
+// from the start of the line. Use UTF16Col or RuneCol to convert Col, given the line.
 type Position struct {
 	Index int64
 	Line  uint32
@@ -68,17 +70,22 @@ func NewPosition(index int64, line, col uint32) Position {
 func NewExpression(value string, from, to parse.Position) Expression {
 	return Expression{
 		Value: value,
-		Range: Range{
-			From: Position{
-				Index: int64(from.Index),
-				Line:  uint32(from.Line),
-				Col:   uint32(from.Col),
-			},
-			To: Position{
-				Index: int64(to.Index),
-				Line:  uint32(to.Line),
-				Col:   uint32(to.Col),
-			},
+		Range: NewRange(from, to),
+	}
+}
+
+// NewRange creates a range from the positions of the parser.
+func NewRange(from, to parse.Position) Range {
+	return Range{
+		From: Position{
+			Index: int64(from.Index),
+			Line:  uint32(from.Line),
+			Col:   uint32(from.Col),
+		},
+		To: Position{
+			Index: int64(to.Index),
+			Line:  uint32(to.Line),
+			Col:   uint32(to.Col),
 		},
 	}
 }
@@ -132,7 +139,18 @@ type GoExpression struct {
 
 func (exp GoExpression) IsTemplateFileNode() bool { return true }
 func (exp GoExpression) Write(w io.Writer, indent int) error {
-	return writeIndent(w, indent, exp.Expression.Value)
+	return writeIndent(w, indent, formatGoBlock(exp.Expression.Value))
+}
+
+// formatGoBlock formats top-level Go code with go/format. If the code isn't valid, it's
+// returned unchanged, so that gopls can report the error against the generated code.
+func formatGoBlock(src string) string {
+	const prefix = "package p\n\n"
+	formatted, err := format.Source([]byte(prefix + src))
+	if err != nil {
+		return src
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(formatted), prefix))
 }
 
 func writeIndent(w io.Writer, level int, s string) (err error) {
@@ -143,6 +161,19 @@ func writeIndent(w io.Writer, level int, s string) (err error) {
 	return
 }
 
+// writeDoc writes the comments above a declaration, one per line.
+func writeDoc(w io.Writer, indent int, doc string) error {
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			continue
+		}
+		if err := writeIndent(w, indent, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type Package struct {
 	Expression Expression
 }
@@ -159,7 +190,7 @@ type Whitespace struct {
 func (ws Whitespace) IsNode() bool { return true }
 
 func (ws Whitespace) Write(w io.Writer, indent int) error {
-	if ws.Value == "" || !strings.Contains(ws.Value, "\n") {
+	if ws.Value == "" {
 		return nil
 	}
 	// https://developer.mozilla.org/en-US/docs/Web/API/Document_Object_Model/Whitespace
@@ -169,14 +200,46 @@ func (ws Whitespace) Write(w io.Writer, indent int) error {
 	// Any space immediately following another space (even across two separate inline elements) is ignored.
 	// Sequences of spaces at the beginning and end of an element are removed.
 
-	// Notes: Since we only have whitespace in this node, we can strip anything that isn't a line break.
-	// Since any space following another space is ignored, we can collapse to a single rule.
-	// So, the rule is... if there's a newline, it becomes a single space, or it's stripped.
-	// We have to remove the start and end space elsewhere.
+	// Notes: Since any space following another space is ignored, a run of whitespace collapses
+	// to a single space. Whether the whitespace is written at all depends on the nodes either
+	// side of it, see IsSignificantWhitespace.
 	_, err := io.WriteString(w, " ")
 	return err
 }
 
+// HasBlankLine returns true if the whitespace contains a blank line, which the formatter
+// keeps between nodes. Any further blank lines are removed.
+func (ws Whitespace) HasBlankLine() bool {
+	return strings.Count(ws.Value, "\n") > 1
+}
+
+// IsSignificantWhitespace returns true if whitespace between the prev and next nodes
+// changes the rendered output, in which case it's rendered as a single space.
+//
+// Whitespace at the start or end of a list of nodes, where prev or next is nil, is not
+// significant. Whitespace within a line is significant, e.g. the space between
+// <span>{ a }</span> <span>{ b }</span>. Whitespace that contains a line break is only
+// significant if it's next to text, since line breaks between elements are used to
+// format the template.
+func IsSignificantWhitespace(prev Node, ws Whitespace, next Node) bool {
+	if prev == nil || next == nil || ws.Value == "" {
+		return false
+	}
+	if !strings.Contains(ws.Value, "\n") {
+		return true
+	}
+	return isText(prev) || isText(next)
+}
+
+// isText returns true if the node is text, or a character reference within text.
+func isText(n Node) bool {
+	switch n.(type) {
+	case Text, CharacterReference:
+		return true
+	}
+	return false
+}
+
 // CSS definition.
 //
 //	css Name() {
@@ -185,12 +248,17 @@ func (ws Whitespace) Write(w io.Writer, indent int) error {
 //	  background-image: url('./somewhere.png');
 //	}
 type CSSTemplate struct {
+	// Doc is the comments written directly above the declaration, see HTMLTemplate.Doc.
+	Doc        string
 	Name       Expression
 	Properties []CSSProperty
 }
 
 func (css CSSTemplate) IsTemplateFileNode() bool { return true }
 func (css CSSTemplate) Write(w io.Writer, indent int) error {
+	if err := writeDoc(w, indent, css.Doc); err != nil {
+		return err
+	}
 	if err := writeIndent(w, indent, "css "+css.Name.Value+"() {\n"); err != nil {
 		return err
 	}
@@ -270,6 +338,34 @@ func (dt DocType) Write(w io.Writer, indent int) error {
 	return writeIndent(w, indent, "<!DOCTYPE "+dt.Value+">")
 }
 
+// <!-- Single or multiline HTML comment -->
+type HTMLComment struct {
+	Contents string
+}
+
+func (c HTMLComment) IsNode() bool { return true }
+func (c HTMLComment) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "<!--"+c.Contents+"-->")
+}
+
+// GoComment is a templ comment, which is kept for formatting, but isn't rendered.
+//
+//	// Single line comment.
+//	/* Multiline
+//	comment */
+type GoComment struct {
+	Contents  string
+	Multiline bool
+}
+
+func (c GoComment) IsNode() bool { return true }
+func (c GoComment) Write(w io.Writer, indent int) error {
+	if c.Multiline {
+		return writeIndent(w, indent, "/*"+c.Contents+"*/")
+	}
+	return writeIndent(w, indent, "//"+c.Contents)
+}
+
 // HTMLTemplate definition.
 //
 //	templ Name(p Parameter) {
@@ -278,6 +374,9 @@ func (dt DocType) Write(w io.Writer, indent int) error {
 //	  }
 //	}
 type HTMLTemplate struct {
+	// Doc is the comments written directly above the declaration, without a blank line
+	// between them, e.g. "// Page renders the page.", one comment per line.
+	Doc        string
 	Expression Expression
 	Children   []Node
 }
@@ -285,6 +384,9 @@ type HTMLTemplate struct {
 func (t HTMLTemplate) IsTemplateFileNode() bool { return true }
 
 func (t HTMLTemplate) Write(w io.Writer, indent int) error {
+	if err := writeDoc(w, indent, t.Doc); err != nil {
+		return err
+	}
 	if err := writeIndent(w, indent, "templ "+t.Expression.Value+" {\n"); err != nil {
 		return err
 	}
@@ -306,7 +408,8 @@ type Node interface {
 
 // Text node within the document.
 type Text struct {
-	// Value is the raw HTML encoded value.
+	// Value is the raw HTML encoded value. Character references are parsed as
+	// CharacterReference nodes, so any ampersand in the value is a literal ampersand.
 	Value string
 }
 
@@ -315,9 +418,22 @@ func (t Text) Write(w io.Writer, indent int) error {
 	return writeIndent(w, indent, t.Value)
 }
 
+// CharacterReference within text, e.g. &nbsp;, &#169; or &#xA9;.
+type CharacterReference struct {
+	// Value is the reference as written, including the leading ampersand and trailing semicolon.
+	Value string
+}
+
+func (cr CharacterReference) IsNode() bool { return true }
+func (cr CharacterReference) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, cr.Value)
+}
+
 // <a .../> or <div ...>...</div>
 type Element struct {
-	Name       string
+	Name string
+	// NameRange is the position of the name within the start tag.
+	NameRange  Range
 	Attributes []Attribute
 	Children   []Node
 }
@@ -336,6 +452,18 @@ var blockElements = map[string]struct{}{
 	"address": {}, "article": {}, "aside": {}, "body": {}, "blockquote": {}, "canvas": {}, "dd": {}, "div": {}, "dl": {}, "dt": {}, "fieldset": {}, "figcaption": {}, "figure": {}, "footer": {}, "form": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}, "head": {}, "header": {}, "hr": {}, "html": {}, "li": {}, "main": {}, "meta": {}, "nav": {}, "noscript": {}, "ol": {}, "p": {}, "pre": {}, "script": {}, "section": {}, "table": {}, "tr": {}, "th": {}, "td": {}, "template": {}, "tfoot": {}, "turbo-stream": {}, "ul": {}, "video": {},
 }
 
+// preformattedElements have their contents rendered and formatted exactly as written.
+var preformattedElements = map[string]struct{}{
+	"pre": {}, "textarea": {},
+}
+
+// IsPreformatted returns true if the whitespace within the element is significant, so
+// its contents must be preserved byte-for-byte.
+func (e Element) IsPreformatted() bool {
+	_, ok := preformattedElements[e.Name]
+	return ok
+}
+
 func (e Element) isBlockElement() bool {
 	_, ok := blockElements[e.Name]
 	return ok
@@ -362,7 +490,7 @@ func (e Element) containsBlockElement() bool {
 			continue
 		case StringExpression:
 			continue
-		case Text:
+		case Text, CharacterReference:
 			continue
 		case TemplElementExpression:
 			if len(n.Children) > 0 {
@@ -378,14 +506,6 @@ func (e Element) containsBlockElement() bool {
 
 // Validate that no invalid expressions have been used.
 func (e Element) Validate() (msgs []string, ok bool) {
-	// Validate that style attributes are constant.
-	for _, attr := range e.Attributes {
-		if exprAttr, isExprAttr := attr.(ExpressionAttribute); isExprAttr {
-			if strings.EqualFold(exprAttr.Name, "style") {
-				msgs = append(msgs, "invalid style attribute: style attributes cannot be a templ expression")
-			}
-		}
-	}
 	// Validate that script and style tags don't contain expressions.
 	if strings.EqualFold(e.Name, "script") || strings.EqualFold(e.Name, "style") {
 		if containsNonTextNodes(e.Children) {
@@ -399,7 +519,7 @@ func containsNonTextNodes(nodes []Node) bool {
 	for i := 0; i < len(nodes); i++ {
 		n := nodes[i]
 		switch n.(type) {
-		case Text:
+		case Text, CharacterReference:
 			continue
 		case Whitespace:
 			continue
@@ -412,8 +532,14 @@ func containsNonTextNodes(nodes []Node) bool {
 
 func (e Element) IsNode() bool { return true }
 func (e Element) Write(w io.Writer, indent int) error {
-	if err := writeIndent(w, indent, "<"+e.Name); err != nil {
-		return err
+	return writeNode(w, indent, e)
+}
+
+// writeOpenTag writes the element name and attributes, and returns the indent of the
+// closing angle bracket.
+func (e Element) writeOpenTag(w io.Writer, indent int) (closeAngleBracketIndent int, err error) {
+	if err = writeIndent(w, indent, "<"+e.Name); err != nil {
+		return
 	}
 	var previousWasMultiline bool
 	for i := 0; i < len(e.Attributes); i++ {
@@ -423,84 +549,322 @@ func (e Element) Write(w io.Writer, indent int) error {
 		if previousWasMultiline || a.IsMultilineAttr() {
 			attrIndent = indent + 1
 		} else {
-			if _, err := w.Write([]byte(" ")); err != nil {
-				return err
+			if _, err = w.Write([]byte(" ")); err != nil {
+				return
 			}
 		}
-		if err := a.Write(w, attrIndent); err != nil {
-			return err
+		if err = a.Write(w, attrIndent); err != nil {
+			return
 		}
 		previousWasMultiline = a.IsMultilineAttr()
 	}
-	var closeAngleBracketIndent int
 	if previousWasMultiline {
 		closeAngleBracketIndent = indent + 1
 	}
-	if e.hasNonWhitespaceChildren() {
-		if e.containsBlockElement() {
-			if err := writeIndent(w, closeAngleBracketIndent, ">\n"); err != nil {
-				return err
-			}
-			if err := writeNodesBlock(w, indent+1, e.Children); err != nil {
-				return err
+	return closeAngleBracketIndent, nil
+}
+
+func writeNodesInline(w io.Writer, nodes []Node) error {
+	return writeNodes(w, 0, nodes, layoutInline)
+}
+
+func writeNodesBlock(w io.Writer, indent int, nodes []Node) error {
+	return writeNodes(w, indent, nodes, layoutBlock)
+}
+
+func writeNodes(w io.Writer, indent int, nodes []Node, l layout) error {
+	f := &formatter{w: w}
+	return f.walkNodes(l, indent, nodes)
+}
+
+// writeNode writes the node, and its children.
+func writeNode(w io.Writer, indent int, n Node) error {
+	f := &formatter{w: w}
+	f.push(layoutNode, indent, nil)
+	Walk(f, n)
+	return f.err
+}
+
+// layout is how the children of a node are written.
+type layout int
+
+const (
+	// layoutNode writes a single node.
+	layoutNode layout = iota
+	// layoutBlock writes each node on a new line, unless that would add or remove
+	// significant whitespace.
+	layoutBlock
+	// layoutInline writes the nodes on the current line.
+	layoutInline
+	// layoutPreformatted writes nodes within elements such as <pre> exactly as they were
+	// written, including their whitespace and the contents of child elements.
+	layoutPreformatted
+)
+
+// formatter writes the nodes that Walk visits. Each node with children pushes a frame, which
+// lays out the children as they're visited. The frame is popped, and the end of the node is
+// written, when Walk leaves the node with Visit(nil).
+type formatter struct {
+	w      io.Writer
+	frames []*formatterFrame
+	err    error
+}
+
+type formatterFrame struct {
+	layout layout
+	indent int
+	// prev is the last node that was written, and ws is the whitespace after it, which is
+	// only written if it changes the rendered output, once the next node is known.
+	prev Node
+	ws   *Whitespace
+	// end writes the end of the node, after its children, e.g. the closing tag of an element.
+	end func() error
+}
+
+func (f *formatter) push(l layout, indent int, end func() error) {
+	f.frames = append(f.frames, &formatterFrame{layout: l, indent: indent, end: end})
+}
+
+func (f *formatter) pop() error {
+	fr := f.frames[len(f.frames)-1]
+	f.frames = f.frames[:len(f.frames)-1]
+	if f.err != nil {
+		return f.err
+	}
+	if fr.layout == layoutBlock && fr.prev != nil {
+		if _, err := f.w.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	if fr.end != nil {
+		return fr.end()
+	}
+	return nil
+}
+
+// walkNodes writes the nodes, and their children, with the layout.
+func (f *formatter) walkNodes(l layout, indent int, nodes []Node) error {
+	f.push(l, indent, nil)
+	for _, n := range nodes {
+		Walk(f, n)
+		if f.err != nil {
+			return f.err
+		}
+	}
+	return f.pop()
+}
+
+func (f *formatter) Visit(node Node) Visitor {
+	if node == nil {
+		f.err = f.pop()
+		return nil
+	}
+	if f.err != nil {
+		return nil
+	}
+	var enter bool
+	if enter, f.err = f.writeChild(node); f.err != nil || !enter {
+		return nil
+	}
+	return f
+}
+
+// writeChild writes the node with the layout of its parent. If the node's children are
+// written by walking them, enter is true, and the node has pushed a frame for them.
+func (f *formatter) writeChild(node Node) (enter bool, err error) {
+	fr := f.frames[len(f.frames)-1]
+	switch fr.layout {
+	case layoutPreformatted:
+		return f.writePreformatted(node)
+	case layoutInline:
+		if ws, isWhitespace := node.(Whitespace); isWhitespace {
+			fr.ws = &ws
+			return false, nil
+		}
+		// Whitespace between nodes is only written if it changes the rendered output.
+		if fr.ws != nil && IsSignificantWhitespace(fr.prev, *fr.ws, node) {
+			if err = fr.ws.Write(f.w, fr.indent); err != nil {
+				return false, err
 			}
-			if err := writeIndent(w, indent, "</"+e.Name+">"); err != nil {
-				return err
+		}
+		fr.prev, fr.ws = node, nil
+	case layoutBlock:
+		if ws, isWhitespace := node.(Whitespace); isWhitespace {
+			fr.ws = &ws
+			return false, nil
+		}
+		prev, ws := fr.prev, fr.ws
+		fr.prev, fr.ws = node, nil
+		if prev == nil {
+			break
+		}
+		// In blocks, each node is written on a new line, unless that would add or remove
+		// significant whitespace, in which case the node continues the current line.
+		var continueLine bool
+		if ws == nil {
+			continueLine = isText(prev) || isText(node)
+		} else if IsSignificantWhitespace(prev, *ws, node) && !strings.Contains(ws.Value, "\n") {
+			if err = ws.Write(f.w, fr.indent); err != nil {
+				return false, err
 			}
-			return nil
+			continueLine = true
 		}
-		if err := writeIndent(w, closeAngleBracketIndent, ">"); err != nil {
-			return err
+		if continueLine {
+			return false, writeContinuation(f.w, fr.indent, node)
 		}
-		if err := writeNodesInline(w, e.Children); err != nil {
-			return err
+		if _, err = f.w.Write([]byte("\n")); err != nil {
+			return false, err
 		}
-		if _, err := w.Write([]byte("</" + e.Name + ">")); err != nil {
-			return err
+		// A blank line between nodes is kept, to separate groups of nodes.
+		if ws != nil && ws.HasBlankLine() {
+			if _, err = f.w.Write([]byte("\n")); err != nil {
+				return false, err
+			}
 		}
-		return nil
 	}
-	if e.IsVoidElement() {
-		if err := writeIndent(w, closeAngleBracketIndent, "/>"); err != nil {
-			return err
+	return f.write(fr.indent, node)
+}
+
+// write writes the node. Nodes with children push a frame, and return true, so that their
+// children are written as they're walked, or write their children themselves.
+func (f *formatter) write(indent int, node Node) (enter bool, err error) {
+	switch n := node.(type) {
+	case Element:
+		return f.writeElement(indent, n)
+	case TemplElementExpression:
+		if len(n.Children) == 0 {
+			return false, writeIndent(f.w, indent, fmt.Sprintf("@%s", n.Expression.Value))
 		}
-		return nil
-	}
-	if err := writeIndent(w, closeAngleBracketIndent, "></"+e.Name+">"); err != nil {
-		return err
+		if err = writeIndent(f.w, indent, fmt.Sprintf("@%s {\n", n.Expression.Value)); err != nil {
+			return false, err
+		}
+		f.push(layoutBlock, indent+1, func() error { return writeIndent(f.w, indent, "}") })
+		return true, nil
+	case ForExpression:
+		if err = writeIndent(f.w, indent, "for "+formatForHeader(n.Expression.Value)+" {\n"); err != nil {
+			return false, err
+		}
+		f.push(layoutBlock, indent+1, func() error { return writeIndent(f.w, indent, "}") })
+		return true, nil
+	case IfExpression:
+		return false, f.writeIf(indent, n)
+	case SwitchExpression:
+		return false, f.writeSwitch(indent, n)
 	}
-	return nil
+	return false, node.Write(f.w, indent)
 }
 
-func writeNodesInline(w io.Writer, nodes []Node) error {
-	return writeNodes(w, 0, nodes, false)
+func (f *formatter) writeElement(indent int, e Element) (enter bool, err error) {
+	closeAngleBracketIndent, err := e.writeOpenTag(f.w, indent)
+	if err != nil {
+		return false, err
+	}
+	closeTag := func() error {
+		_, err := io.WriteString(f.w, "</"+e.Name+">")
+		return err
+	}
+	if e.IsPreformatted() {
+		if err = writeIndent(f.w, closeAngleBracketIndent, ">"); err != nil {
+			return false, err
+		}
+		f.push(layoutPreformatted, 0, closeTag)
+		return true, nil
+	}
+	if e.hasNonWhitespaceChildren() {
+		if e.containsBlockElement() {
+			if err = writeIndent(f.w, closeAngleBracketIndent, ">\n"); err != nil {
+				return false, err
+			}
+			f.push(layoutBlock, indent+1, func() error { return writeIndent(f.w, indent, "</"+e.Name+">") })
+			return true, nil
+		}
+		if err = writeIndent(f.w, closeAngleBracketIndent, ">"); err != nil {
+			return false, err
+		}
+		f.push(layoutInline, 0, closeTag)
+		return true, nil
+	}
+	if e.IsVoidElement() {
+		return false, writeIndent(f.w, closeAngleBracketIndent, "/>")
+	}
+	return false, writeIndent(f.w, closeAngleBracketIndent, "></"+e.Name+">")
 }
 
-func writeNodesBlock(w io.Writer, indent int, nodes []Node) error {
-	return writeNodes(w, indent, nodes, true)
+func (f *formatter) writePreformatted(node Node) (enter bool, err error) {
+	switch n := node.(type) {
+	case Whitespace:
+		_, err = io.WriteString(f.w, n.Value)
+		return false, err
+	case Element:
+		closeAngleBracketIndent, err := n.writeOpenTag(f.w, 0)
+		if err != nil {
+			return false, err
+		}
+		if n.IsVoidElement() && len(n.Children) == 0 {
+			return false, writeIndent(f.w, closeAngleBracketIndent, "/>")
+		}
+		if err = writeIndent(f.w, closeAngleBracketIndent, ">"); err != nil {
+			return false, err
+		}
+		f.push(layoutPreformatted, 0, func() error {
+			_, err := io.WriteString(f.w, "</"+n.Name+">")
+			return err
+		})
+		return true, nil
+	}
+	return f.write(0, node)
 }
 
-func writeNodes(w io.Writer, indent int, nodes []Node, block bool) error {
-	for i := 0; i < len(nodes); i++ {
-		// Terminating and leading whitespace is stripped.
-		_, isWhitespace := nodes[i].(Whitespace)
-		if isWhitespace && (i == 0 || i == len(nodes)-1) {
-			continue
+func (f *formatter) writeIf(indent int, n IfExpression) error {
+	if err := writeIndent(f.w, indent, "if "+n.Expression.Value+" {\n"); err != nil {
+		return err
+	}
+	if err := f.walkNodes(layoutBlock, indent+1, n.Then); err != nil {
+		return err
+	}
+	for _, elseIf := range n.ElseIfs {
+		if err := writeIndent(f.w, indent, "} else if "+elseIf.Expression.Value+" {\n"); err != nil {
+			return err
 		}
-		// Whitespace is stripped from block elements.
-		if isWhitespace && block {
-			continue
+		if err := f.walkNodes(layoutBlock, indent+1, elseIf.Then); err != nil {
+			return err
 		}
-		if err := nodes[i].Write(w, indent); err != nil {
+	}
+	if len(n.Else) > 0 {
+		if err := writeIndent(f.w, indent, "} else {\n"); err != nil {
 			return err
 		}
-		if block {
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
+		if err := f.walkNodes(layoutBlock, indent+1, n.Else); err != nil {
+			return err
 		}
 	}
-	return nil
+	return writeIndent(f.w, indent, "}")
+}
+
+func (f *formatter) writeSwitch(indent int, se SwitchExpression) error {
+	if err := writeIndent(f.w, indent, "switch "+se.Expression.Value+" {\n"); err != nil {
+		return err
+	}
+	for _, c := range se.Cases {
+		if err := writeIndent(f.w, indent+1, c.Expression.Value+"\n"); err != nil {
+			return err
+		}
+		if err := f.walkNodes(layoutBlock, indent+2, c.Children); err != nil {
+			return err
+		}
+	}
+	return writeIndent(f.w, indent, "}")
+}
+
+// writeContinuation writes the node at the end of the current line, rather than
+// at the start of a new, indented, line.
+func writeContinuation(w io.Writer, indent int, n Node) error {
+	var sb strings.Builder
+	if err := n.Write(&sb, indent); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, strings.TrimLeft(sb.String(), "\t"))
+	return err
 }
 
 type RawElement struct {
@@ -561,13 +925,15 @@ func (bca BoolConstantAttribute) Write(w io.Writer, indent int) error {
 
 // href=""
 type ConstantAttribute struct {
-	Name  string
+	Name string
+	// Value is the HTML encoded value as written, which may contain character references.
 	Value string
 }
 
 func (ca ConstantAttribute) IsMultilineAttr() bool { return false }
 func (ca ConstantAttribute) String() string {
-	return ca.Name + `="` + html.EscapeString(ca.Value) + `"`
+	// Values written in single quotes may contain double quotes.
+	return ca.Name + `="` + strings.ReplaceAll(ca.Value, `"`, "&quot;") + `"`
 }
 
 func (ca ConstantAttribute) Write(w io.Writer, indent int) error {
@@ -582,7 +948,7 @@ type BoolExpressionAttribute struct {
 
 func (ea BoolExpressionAttribute) IsMultilineAttr() bool { return false }
 func (ea BoolExpressionAttribute) String() string {
-	return ea.Name + `?={ ` + ea.Expression.Value + ` }`
+	return ea.Name + `?={ ` + strings.TrimSpace(ea.Expression.Value) + ` }`
 }
 
 func (ea BoolExpressionAttribute) Write(w io.Writer, indent int) error {
@@ -597,13 +963,49 @@ type ExpressionAttribute struct {
 
 func (ea ExpressionAttribute) IsMultilineAttr() bool { return false }
 func (ea ExpressionAttribute) String() string {
-	return ea.Name + `={ ` + ea.Expression.Value + ` }`
+	return ea.Name + `={ ` + strings.TrimSpace(ea.Expression.Value) + ` }`
 }
 
 func (ea ExpressionAttribute) Write(w io.Writer, indent int) error {
 	return writeIndent(w, indent, ea.String())
 }
 
+// <div { attrs... }>
+type SpreadAttributes struct {
+	Expression Expression
+}
+
+func (sa SpreadAttributes) IsMultilineAttr() bool { return false }
+func (sa SpreadAttributes) String() string {
+	return `{ ` + strings.TrimSpace(sa.Expression.Value) + `... }`
+}
+
+func (sa SpreadAttributes) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, sa.String())
+}
+
+// CommentAttribute is a templ comment between attributes.
+//
+//	<a href="test"
+//		// The link is active.
+//		class="active"
+//	>
+type CommentAttribute struct {
+	Comment GoComment
+}
+
+func (ca CommentAttribute) IsMultilineAttr() bool { return true }
+func (ca CommentAttribute) Write(w io.Writer, indent int) error {
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+	if err := ca.Comment.Write(w, indent); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte("\n"))
+	return err
+}
+
 //	<a href="test" \
 //		if active {
 //	   class="isActive"
@@ -634,17 +1036,8 @@ func (ca ConditionalAttribute) Write(w io.Writer, indent int) error {
 	if _, err := w.Write([]byte(" {\n")); err != nil {
 		return err
 	}
-	{
-		indent++
-		for _, attr := range ca.Then {
-			if err := attr.Write(w, indent); err != nil {
-				return err
-			}
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
-		}
-		indent--
+	if err := writeConditionalAttributes(w, indent+1, ca.Then); err != nil {
+		return err
 	}
 	if err := writeIndent(w, indent, "}"); err != nil {
 		return err
@@ -659,17 +1052,8 @@ func (ca ConditionalAttribute) Write(w io.Writer, indent int) error {
 	if _, err := w.Write([]byte(" else {\n")); err != nil {
 		return err
 	}
-	{
-		indent++
-		for _, attr := range ca.Else {
-			if err := attr.Write(w, indent); err != nil {
-				return err
-			}
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
-		}
-		indent--
+	if err := writeConditionalAttributes(w, indent+1, ca.Else); err != nil {
+		return err
 	}
 	if err := writeIndent(w, indent, "}\n"); err != nil {
 		return err
@@ -677,6 +1061,26 @@ func (ca ConditionalAttribute) Write(w io.Writer, indent int) error {
 	return nil
 }
 
+// writeConditionalAttributes writes each attribute within a conditional block on its
+// own line. Multiline attributes, such as nested conditions, already start on a new
+// line, so the leading newline they write is dropped to avoid blank lines.
+func writeConditionalAttributes(w io.Writer, indent int, attrs []Attribute) error {
+	for _, attr := range attrs {
+		sb := new(strings.Builder)
+		if err := attr.Write(sb, indent); err != nil {
+			return err
+		}
+		s := strings.TrimPrefix(sb.String(), "\n")
+		if !strings.HasSuffix(s, "\n") {
+			s += "\n"
+		}
+		if _, err := io.WriteString(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Nodes.
 
 // CallTemplateExpression can be used to create and render a template using data.
@@ -690,7 +1094,7 @@ type CallTemplateExpression struct {
 
 func (cte CallTemplateExpression) IsNode() bool { return true }
 func (cte CallTemplateExpression) Write(w io.Writer, indent int) error {
-	return writeIndent(w, indent, `{! `+cte.Expression.Value+` }`)
+	return writeIndent(w, indent, `{! `+strings.TrimSpace(cte.Expression.Value)+` }`)
 }
 
 // TemplElementExpression can be used to create and render a template using data.
@@ -706,19 +1110,7 @@ type TemplElementExpression struct {
 
 func (tee TemplElementExpression) IsNode() bool { return true }
 func (tee TemplElementExpression) Write(w io.Writer, indent int) error {
-	if len(tee.Children) == 0 {
-		return writeIndent(w, indent, fmt.Sprintf("@%s", tee.Expression.Value))
-	}
-	if err := writeIndent(w, indent, fmt.Sprintf("@%s {\n", tee.Expression.Value)); err != nil {
-		return err
-	}
-	if err := writeNodesBlock(w, indent+1, tee.Children); err != nil {
-		return err
-	}
-	if err := writeIndent(w, indent, "}"); err != nil {
-		return err
-	}
-	return nil
+	return writeNode(w, indent, tee)
 }
 
 // ChildrenExpression can be used to rended the children of a templ element.
@@ -738,47 +1130,24 @@ func (ChildrenExpression) Write(w io.Writer, indent int) error {
 type IfExpression struct {
 	Expression Expression
 	Then       []Node
-	ElseIfs    []ElseIfExpression
-	Else       []Node
+	// ThenRange is the range of the Then nodes, within the braces of the block.
+	ThenRange Range
+	ElseIfs   []ElseIfExpression
+	Else      []Node
+	// ElseRange is the range of the Else nodes, if there's an else block.
+	ElseRange Range
 }
 
 type ElseIfExpression struct {
 	Expression Expression
 	Then       []Node
+	// ThenRange is the range of the Then nodes, see IfExpression.ThenRange.
+	ThenRange Range
 }
 
 func (n IfExpression) IsNode() bool { return true }
 func (n IfExpression) Write(w io.Writer, indent int) error {
-	if err := writeIndent(w, indent, "if "+n.Expression.Value+" {\n"); err != nil {
-		return err
-	}
-	indent++
-	if err := writeNodesBlock(w, indent, n.Then); err != nil {
-		return err
-	}
-	indent--
-	for _, elseIf := range n.ElseIfs {
-		if err := writeIndent(w, indent, "} else if "+elseIf.Expression.Value+" {\n"); err != nil {
-			return err
-		}
-		indent++
-		if err := writeNodesBlock(w, indent, elseIf.Then); err != nil {
-			return err
-		}
-		indent--
-	}
-	if len(n.Else) > 0 {
-		if err := writeIndent(w, indent, "} else {\n"); err != nil {
-			return err
-		}
-		if err := writeNodesBlock(w, indent+1, n.Else); err != nil {
-			return err
-		}
-	}
-	if err := writeIndent(w, indent, "}"); err != nil {
-		return err
-	}
-	return nil
+	return writeNode(w, indent, n)
 }
 
 //	switch p.Type {
@@ -791,24 +1160,7 @@ type SwitchExpression struct {
 
 func (se SwitchExpression) IsNode() bool { return true }
 func (se SwitchExpression) Write(w io.Writer, indent int) error {
-	if err := writeIndent(w, indent, "switch "+se.Expression.Value+" {\n"); err != nil {
-		return err
-	}
-	indent++
-	for i := 0; i < len(se.Cases); i++ {
-		c := se.Cases[i]
-		if err := writeIndent(w, indent, c.Expression.Value+"\n"); err != nil {
-			return err
-		}
-		if err := writeNodesBlock(w, indent+1, c.Children); err != nil {
-			return err
-		}
-	}
-	indent--
-	if err := writeIndent(w, indent, "}"); err != nil {
-		return err
-	}
-	return nil
+	return writeNode(w, indent, se)
 }
 
 // case "Something":
@@ -823,20 +1175,37 @@ type CaseExpression struct {
 type ForExpression struct {
 	Expression Expression
 	Children   []Node
+	// ChildrenRange is the range of the Children, within the braces of the block.
+	ChildrenRange Range
 }
 
 func (fe ForExpression) IsNode() bool { return true }
 func (fe ForExpression) Write(w io.Writer, indent int) error {
-	if err := writeIndent(w, indent, "for "+fe.Expression.Value+" {\n"); err != nil {
-		return err
+	return writeNode(w, indent, fe)
+}
+
+// formatForHeader normalizes the spacing of a for loop header using gofmt, e.g.
+// "i:=0;i<n;i++" becomes "i := 0; i < n; i++". Headers that aren't valid Go are
+// left as they are, apart from surrounding whitespace.
+func formatForHeader(header string) string {
+	header = strings.TrimSpace(header)
+	if strings.Contains(header, "\n") {
+		return header
 	}
-	if err := writeNodesBlock(w, indent+1, fe.Children); err != nil {
-		return err
+	src := "package p\n\nfunc _() {\n\tfor " + header + " {\n\t}\n}\n"
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return header
 	}
-	if err := writeIndent(w, indent, "}"); err != nil {
-		return err
+	lines := strings.Split(string(formatted), "\n")
+	if len(lines) < 4 {
+		return header
 	}
-	return nil
+	loop := strings.TrimPrefix(lines[3], "\tfor ")
+	if loop == lines[3] || !strings.HasSuffix(loop, " {") {
+		return header
+	}
+	return strings.TrimSuffix(loop, " {")
 }
 
 // StringExpression is used within HTML elements, and for style values.
@@ -848,11 +1217,13 @@ type StringExpression struct {
 func (se StringExpression) IsNode() bool                  { return true }
 func (se StringExpression) IsStyleDeclarationValue() bool { return true }
 func (se StringExpression) Write(w io.Writer, indent int) error {
-	return writeIndent(w, indent, `{ `+se.Expression.Value+` }`)
+	return writeIndent(w, indent, `{ `+strings.TrimSpace(se.Expression.Value)+` }`)
 }
 
 // ScriptTemplate is a script block.
 type ScriptTemplate struct {
+	// Doc is the comments written directly above the declaration, see HTMLTemplate.Doc.
+	Doc        string
 	Name       Expression
 	Parameters Expression
 	Value      string
@@ -860,6 +1231,9 @@ type ScriptTemplate struct {
 
 func (s ScriptTemplate) IsTemplateFileNode() bool { return true }
 func (s ScriptTemplate) Write(w io.Writer, indent int) error {
+	if err := writeDoc(w, indent, s.Doc); err != nil {
+		return err
+	}
 	if err := writeIndent(w, indent, "script "+s.Name.Value+"("+s.Parameters.Value+") {\n"); err != nil {
 		return err
 	}
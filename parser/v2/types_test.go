@@ -1,10 +1,12 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/txtar"
 )
 
 func TestFormatting(t *testing.T) {
@@ -22,6 +24,8 @@ templ input(value, validation string) {
 	<area></area>
 	<base></base>
 	<br></br>
+	<br>
+	<input type="text">
 	<col></col>
 	<command></command>
 	<embed></embed>
@@ -45,6 +49,8 @@ templ input(value, validation string) {
 	<area/>
 	<base/>
 	<br/>
+	<br/>
+	<input type="text"/>
 	<col/>
 	<command/>
 	<embed/>
@@ -202,6 +208,541 @@ templ input(items []string) {
 	</div>
 }
 
+`,
+		},
+		{
+			name: "else if chains are formatted",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ input(n int) {
+<div>if n == 1 {
+<span>one</span>
+}   else if n == 2 {
+<span>two</span>
+} else if   n == 3 {
+<span>three</span>
+		} else {
+<span>many</span>
+}</div>
+<div>if n == 1 {
+<span>one</span>
+} else if n == 2 {
+<span>two</span>
+}</div>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ input(n int) {
+	<div>
+		if n == 1 {
+			<span>one</span>
+		} else if n == 2 {
+			<span>two</span>
+		} else if n == 3 {
+			<span>three</span>
+		} else {
+			<span>many</span>
+		}
+	</div>
+	<div>
+		if n == 1 {
+			<span>one</span>
+		} else if n == 2 {
+			<span>two</span>
+		}
+	</div>
+}
+
+`,
+		},
+		{
+			name: "for loop headers are normalized",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ input(items []string, m map[string]int, n int) {
+	for   i,item:=range items {
+		<div>{ fmt.Sprint(i) }{ item }</div>
+	}
+	for k, v := range m{
+		<div>{ k }{ fmt.Sprint(v) }</div>
+	}
+	for i:=0;i<n;i++ {
+		<div>{ fmt.Sprint(i) }</div>
+	}
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ input(items []string, m map[string]int, n int) {
+	for i, item := range items {
+		<div>{ fmt.Sprint(i) }{ item }</div>
+	}
+	for k, v := range m {
+		<div>{ k }{ fmt.Sprint(v) }</div>
+	}
+	for i := 0; i < n; i++ {
+		<div>{ fmt.Sprint(i) }</div>
+	}
+}
+
+`,
+		},
+		{
+			name: "comments are preserved",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+// Top level comment.
+templ input(active bool) {
+<!-- HTML comment -->
+	// Templ comment.
+<a href="/"
+// Comment between attributes.
+class="link"
+>/* Multiline
+comment */<span>Link</span></a>
+}
+
+/* Between declarations. */
+templ other() {
+	<div></div>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+// Top level comment.
+templ input(active bool) {
+	<!-- HTML comment -->
+	// Templ comment.
+	<a href="/"
+		// Comment between attributes.
+		class="link">
+		/* Multiline
+comment */
+		<span>Link</span>
+	</a>
+}
+
+/* Between declarations. */
+templ other() {
+	<div></div>
+}
+
+`,
+		},
+		{
+			name: "script and style contents are not reindented",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ input() {
+<div><script type="text/javascript">
+if (a < b) {
+  console.log(` + "`${a} < ${b}`" + `);
+}
+</script><style>
+  .a { color: red; }
+</style></div>
+}
+
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ input() {
+	<div>
+		<script type="text/javascript">
+if (a < b) {
+  console.log(` + "`${a} < ${b}`" + `);
+}
+</script>
+		<style>
+  .a { color: red; }
+</style>
+	</div>
+}
+
+`,
+		},
+		{
+			name: "boolean attributes are preserved",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ input(disabled bool) {
+<select><option selected>A</option></select>
+<input type="checkbox" checked disabled?={disabled}>
+}
+
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ input(disabled bool) {
+	<select><option selected>A</option></select>
+	<input type="checkbox" checked disabled?={ disabled }/>
+}
+
+`,
+		},
+		{
+			name: "nested conditional attributes are indented without blank lines",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ input(expanded, selected bool) {
+<div class="panel"
+if expanded {
+aria-expanded="true"
+if selected {
+aria-selected="true"
+} else {
+aria-selected="false"
+}
+}
+>Panel</div>
+}
+
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ input(expanded, selected bool) {
+	<div class="panel"
+		if expanded {
+			aria-expanded="true"
+			if selected {
+				aria-selected="true"
+			} else {
+				aria-selected="false"
+			}
+		}
+		>Panel</div>
+}
+
+`,
+		},
+		{
+			name: "spread attributes are formatted",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ input(attrs templ.Attributes) {
+<div class="a" {attrs...}></div>
+}
+
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ input(attrs templ.Attributes) {
+	<div class="a" { attrs... }></div>
+}
+
+`,
+		},
+		{
+			name: "css declarations are placed on their own lines",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+css button() {
+  color:   red;   background-color :blue;
+	padding: { fmt.Sprint(1) }  ;
+  background-image: url('data:image/png;base64,AAAA') ; }
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+css button() {
+	color: red;
+	background-color: blue;
+	padding: { fmt.Sprint(1) };
+	background-image: url('data:image/png;base64,AAAA');
+}
+
+`,
+		},
+		{
+			name: "go code between templates is formatted with go/format",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+func formatDate(t time.Time) string {
+return t.Format("2006-01-02")
+}
+
+templ date(t time.Time) {
+	<span>{ formatDate(t) }</span>
+}
+
+const  (
+a = 1
+bb = 2
+)
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+templ date(t time.Time) {
+	<span>{ formatDate(t) }</span>
+}
+
+const (
+	a  = 1
+	bb = 2
+)
+
+`,
+		},
+		{
+			name: "invalid go code is left for gopls to report",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+func invalid( {
+return  1
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+func invalid( {
+return  1
+}
+
+`,
+		},
+		{
+			name: "grouped, aliased and blank imports are sorted",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+import (
+"strings"
+  h "github.com/foo/html"
+_ "embed"
+"fmt"
+)
+
+templ input() {
+	<div>{ strings.ToUpper(fmt.Sprint(h.Name)) }</div>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+import (
+	_ "embed"
+	"fmt"
+	h "github.com/foo/html"
+	"strings"
+)
+
+templ input() {
+	<div>{ strings.ToUpper(fmt.Sprint(h.Name)) }</div>
+}
+
+`,
+		},
+		{
+			name: "type parameters are preserved",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ list[T fmt.Stringer, K comparable](items map[K]T) {
+<ul>for _, item := range items {
+<li>{ item.String() }</li>
+}</ul>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ list[T fmt.Stringer, K comparable](items map[K]T) {
+	<ul>
+		for _, item := range items {
+			<li>{ item.String() }</li>
+		}
+	</ul>
+}
+
+`,
+		},
+		{
+			name: "receivers are preserved",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ (p Person) card() {
+<div>{ p.Name }</div>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ (p Person) card() {
+	<div>{ p.Name }</div>
+}
+
+`,
+		},
+		{
+			name: "spaces between inline elements on the same line are preserved",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ nav(a, b string) {
+<div><span>{ a }</span>    <span>{ b }</span></div>
+<span>{ a }</span> <span>{ b }</span>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ nav(a, b string) {
+	<div><span>{ a }</span> <span>{ b }</span></div>
+	<span>{ a }</span> <span>{ b }</span>
+}
+
+`,
+		},
+		{
+			name: "line breaks between inline elements are removed, because they aren't rendered",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ nav() {
+<p>
+<b>a</b>
+<i>b</i>
+</p>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ nav() {
+	<p><b>a</b><i>b</i></p>
+}
+
+`,
+		},
+		{
+			name: "text next to elements in a block stays on the same line",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ nav() {
+<div><div>a</div>text<b>b</b> <i>c</i></div>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ nav() {
+	<div>
+		<div>a</div>text<b>b</b> <i>c</i>
+	</div>
+}
+
+`,
+		},
+		{
+			name: "pre and textarea contents are not reformatted",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ code(s string) {
+<div>
+<pre>
+  indented   text
+	<b>tabbed</b>  { s }
+</pre>
+<textarea>
+   line
+</textarea>
+</div>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ code(s string) {
+	<div>
+		<pre>
+  indented   text
+	<b>tabbed</b>  { s }
+</pre>
+		<textarea>
+   line
+</textarea>
+	</div>
+}
+
+`,
+		},
+		{
+			name: "character references and ampersands are not changed",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ entities() {
+<p>Non&nbsp;breaking &copy; It&#x2019;s &#169; fish & chips</p>
+<a title="Tom &amp; Jerry &copy; & co" style="font-family: 'sans-serif'" data-quote='say "hi"'>Link</a>
+}
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ entities() {
+	<p>Non&nbsp;breaking &copy; It&#x2019;s &#169; fish & chips</p>
+	<a title="Tom &amp; Jerry &copy; & co" style="font-family: 'sans-serif'" data-quote="say &quot;hi&quot;">Link</a>
+}
+
+`,
+		},
+		{
+			name: "line breaks within multi-line expressions are preserved",
+			input: ` // first line removed to make indentation clear in Go code
+package test
+
+templ test(names []string) {
+<p>{
+	strings.Join(names, func() string {
+		// }
+		return ", "
+	}())
+}</p>
+<a href={ templ.URL(fmt.Sprintf("/%s",
+	names[0],
+))  }>Link</a>
+}
+
+`,
+			expected: `// first line removed to make indentation clear in Go code
+package test
+
+templ test(names []string) {
+	<p>{ strings.Join(names, func() string {
+		// }
+		return ", "
+	}()) }</p>
+	<a href={ templ.URL(fmt.Sprintf("/%s",
+	names[0],
+)) }>Link</a>
+}
+
 `,
 		},
 		{
@@ -417,6 +958,52 @@ templ conditionalAttributes(addClass bool) {
 		width="300">Content</div>
 }
 
+`,
+		},
+		{
+			name: "templ expression elements with children are formatted as blocks",
+			input: ` // first line removed to make indentation clear
+package main
+
+templ base(title string) {
+<html><body>{ children... }</body></html>
+}
+
+templ page() {
+@layout.Base("title") {
+<p>body</p>
+}
+@base("outer") {
+@base("inner") {
+<p>nested</p>
+}
+}
+@base("empty")
+}
+`,
+			expected: ` // first line removed to make indentation clear
+package main
+
+templ base(title string) {
+	<html>
+		<body>
+			{ children... }
+		</body>
+	</html>
+}
+
+templ page() {
+	@layout.Base("title") {
+		<p>body</p>
+	}
+	@base("outer") {
+		@base("inner") {
+			<p>nested</p>
+		}
+	}
+	@base("empty")
+}
+
 `,
 		},
 		{
@@ -466,3 +1053,86 @@ templ x() {
 		})
 	}
 }
+
+// TestFormattingFixtures checks that formatting the templates in testdata/formatting.txtar
+// keeps their blank lines and comments, doesn't change their nodes, and that formatting the
+// output again doesn't change it.
+func TestFormattingFixtures(t *testing.T) {
+	archive, err := txtar.ParseFile("testdata/formatting.txtar")
+	if err != nil {
+		t.Fatalf("failed to read test data: %v", err)
+	}
+	files := make(map[string]string, len(archive.Files))
+	for _, f := range archive.Files {
+		files[f.Name] = string(f.Data)
+	}
+	format := func(t *testing.T, src string) (output string, tf TemplateFile) {
+		t.Helper()
+		tf, err := ParseString(src)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		w := new(strings.Builder)
+		if err = tf.Write(w); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+		return w.String(), tf
+	}
+	for _, f := range archive.Files {
+		if !strings.HasSuffix(f.Name, ".templ") {
+			continue
+		}
+		name := strings.TrimSuffix(f.Name, ".templ")
+		t.Run(name, func(t *testing.T) {
+			expected, ok := files[name+".formatted"]
+			if !ok {
+				t.Fatalf("%s.formatted not found", name)
+			}
+			actual, input := format(t, files[name+".templ"])
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("unexpected output:\n%s", diff)
+			}
+			again, formatted := format(t, actual)
+			if diff := cmp.Diff(actual, again); diff != "" {
+				t.Errorf("formatting the output changed it:\n%s", diff)
+			}
+			if diff := cmp.Diff(outline(input), outline(formatted)); diff != "" {
+				t.Errorf("formatting changed the nodes:\n%s", diff)
+			}
+		})
+	}
+}
+
+// outline describes the declarations and nodes of the file, other than whitespace, including
+// the comments, and which declarations they're attached to.
+func outline(tf TemplateFile) (lines []string) {
+	for _, n := range tf.Nodes {
+		switch n := n.(type) {
+		case GoExpression:
+			lines = append(lines, "go: "+strings.Join(strings.Fields(n.Expression.Value), " "))
+		case CSSTemplate:
+			lines = append(lines, fmt.Sprintf("css %s: doc %q", n.Name.Value, n.Doc))
+		case ScriptTemplate:
+			lines = append(lines, fmt.Sprintf("script %s: doc %q", n.Name.Value, n.Doc))
+		case HTMLTemplate:
+			lines = append(lines, fmt.Sprintf("templ %s: doc %q", n.Expression.Value, n.Doc))
+			for _, child := range n.Children {
+				Inspect(child, func(n Node) bool {
+					switch n := n.(type) {
+					case nil, Whitespace:
+					case Text:
+						lines = append(lines, fmt.Sprintf("\ttext %q", n.Value))
+					case HTMLComment:
+						lines = append(lines, fmt.Sprintf("\thtml comment %q", n.Contents))
+					case GoComment:
+						lines = append(lines, fmt.Sprintf("\tcomment %q", n.Contents))
+					default:
+						lines = append(lines, fmt.Sprintf("\t%T", n))
+					}
+					return true
+				})
+			}
+		}
+	}
+	return lines
+}
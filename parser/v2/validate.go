@@ -0,0 +1,316 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity of an Issue.
+type Severity int
+
+const (
+	// SeverityWarning is used for markup that is likely to be a mistake.
+	SeverityWarning Severity = iota
+	// SeverityError is used for markup that browsers are required to treat as an error.
+	SeverityError
+	// SeverityHint is used for suggestions that aren't mistakes, e.g. content that's never
+	// rendered. templ lint doesn't fail if it only finds hints.
+	SeverityHint
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityHint:
+		return "hint"
+	}
+	return "warning"
+}
+
+// The checks carried out by Validate. They can be suppressed with an ignore comment.
+const (
+	CheckUnknownElement     = "unknown-element"
+	CheckDuplicateAttribute = "duplicate-attribute"
+	CheckUnknownAttribute   = "unknown-attribute"
+	CheckListItemParent     = "li-outside-list"
+	CheckDuplicateID        = "duplicate-id"
+	CheckNoAlt              = "no-alt"
+)
+
+// Checks lists the names of all of the checks carried out by Validate.
+var Checks = []string{
+	CheckUnknownElement,
+	CheckDuplicateAttribute,
+	CheckUnknownAttribute,
+	CheckListItemParent,
+	CheckDuplicateID,
+	CheckNoAlt,
+}
+
+// ignoreDirective is the prefix of a templ comment that suppresses checks, e.g.
+// "//templ:ignore no-alt". The listed checks, separated by spaces or commas, are
+// suppressed for the next node and its children. If no checks are listed, all of
+// them are suppressed.
+const ignoreDirective = "templ:ignore"
+
+// Issue is suspicious markup found by Validate.
+type Issue struct {
+	// Check that found the issue, e.g. "no-alt".
+	Check    string
+	Severity Severity
+	Message  string
+	// Range of the element that has the issue.
+	Range Range
+	// Unnecessary is true if the range is content that's never rendered. Editors show it
+	// faded out.
+	Unnecessary bool
+	// Fix resolves the issue, if there's a mechanical change to the templ file that does,
+	// e.g. adding a missing attribute. Editors offer it as a quick fix.
+	Fix *Fix
+}
+
+// Fix is a change to a templ file that resolves an Issue.
+type Fix struct {
+	// Title describes the change, e.g. `Add role="button" tabindex="0"`.
+	Title string
+	// Edits to the templ file, which don't overlap.
+	Edits []Edit
+}
+
+// Edit replaces the text within the range of a templ file with NewText. Text is inserted
+// with an empty range.
+type Edit struct {
+	Range   Range
+	NewText string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (%s)", i.Range.From.Line+1, i.Range.From.Col+1, i.Severity, i.Message, i.Check)
+}
+
+// Validate checks the HTML within the templates of the file for suspicious markup,
+// e.g. unknown elements, and images without alt text. Unlike parse errors, issues
+// don't prevent code from being generated.
+func Validate(tf TemplateFile) (issues []Issue) {
+	for _, n := range tf.Nodes {
+		t, ok := n.(HTMLTemplate)
+		if !ok {
+			continue
+		}
+		v := &validator{
+			issues: &issues,
+			ids:    make(map[string]struct{}),
+		}
+		walkNodes(v, t.Children)
+	}
+	return issues
+}
+
+// validator checks the nodes that it visits. A new validator is used for the
+// children of each node, so that the parent element and the suppressed checks
+// apply to the children.
+type validator struct {
+	issues *[]Issue
+	// ids are the constant id attribute values found in the template.
+	ids map[string]struct{}
+	// parent is the name of the nearest element, or empty if it's not known, e.g.
+	// within the children of a templ element.
+	parent string
+	// foreign is true within svg and math elements, which aren't HTML.
+	foreign bool
+	// ignored checks for the nodes visited, and ignoreNext are the checks that are
+	// also ignored for the next node, because it follows an ignore comment.
+	ignored    checkSet
+	ignoreNext checkSet
+}
+
+func (v *validator) Visit(node Node) Visitor {
+	switch n := node.(type) {
+	case nil, Whitespace:
+		return nil
+	case GoComment:
+		if checks, ok := parseIgnoreDirective(n); ok {
+			v.ignoreNext = checks
+			return nil
+		}
+	}
+	ignored := v.ignored.union(v.ignoreNext)
+	v.ignoreNext = nil
+	child := &validator{
+		issues:  v.issues,
+		ids:     v.ids,
+		parent:  v.parent,
+		foreign: v.foreign,
+		ignored: ignored,
+	}
+	switch n := node.(type) {
+	case Element:
+		name := strings.ToLower(n.Name)
+		if !v.foreign {
+			v.element(n, name, ignored)
+		}
+		child.parent = name
+		child.foreign = v.foreign || name == "svg" || name == "math"
+	case TemplElementExpression:
+		// The children are rendered by another template, so their parent isn't known.
+		child.parent = ""
+	}
+	return child
+}
+
+func (v *validator) add(ignored checkSet, check string, severity Severity, e Element, msg string) {
+	if ignored.has(check) {
+		return
+	}
+	*v.issues = append(*v.issues, Issue{
+		Check:    check,
+		Severity: severity,
+		Message:  fmt.Sprintf("<%s>: %s", e.Name, msg),
+		Range:    e.NameRange,
+	})
+}
+
+func (v *validator) element(e Element, name string, ignored checkSet) {
+	_, isKnown := htmlElements[name]
+	if !isKnown && !strings.Contains(name, "-") {
+		v.add(ignored, CheckUnknownElement, SeverityWarning, e, "unknown element")
+	}
+	if name == "li" && v.parent != "" && v.parent != "ul" && v.parent != "ol" && v.parent != "menu" {
+		v.add(ignored, CheckListItemParent, SeverityWarning, e, fmt.Sprintf("list items should be within <ul>, <ol> or <menu>, not <%s>", v.parent))
+	}
+
+	// The attributes of svg and math elements aren't HTML attributes.
+	checkAttributeNames := isKnown && name != "svg" && name != "math"
+	var hasSpread, hasAlt bool
+	var check func(attrs []Attribute, seen map[string]struct{})
+	check = func(attrs []Attribute, seen map[string]struct{}) {
+		for _, attr := range attrs {
+			var attrName string
+			switch attr := attr.(type) {
+			case BoolConstantAttribute:
+				attrName = attr.Name
+			case ConstantAttribute:
+				attrName = attr.Name
+				if strings.EqualFold(attr.Name, "id") {
+					if _, exists := v.ids[attr.Value]; exists {
+						v.add(ignored, CheckDuplicateID, SeverityWarning, e, fmt.Sprintf("the id %q is used more than once in the template", attr.Value))
+					}
+					v.ids[attr.Value] = struct{}{}
+				}
+			case BoolExpressionAttribute:
+				attrName = attr.Name
+			case ExpressionAttribute:
+				attrName = attr.Name
+			case SpreadAttributes:
+				hasSpread = true
+				continue
+			case ConditionalAttribute:
+				// Only one of the branches is rendered, so each is checked separately.
+				thenSeen, elseSeen := copySet(seen), copySet(seen)
+				check(attr.Then, thenSeen)
+				check(attr.Else, elseSeen)
+				for k := range thenSeen {
+					seen[k] = struct{}{}
+				}
+				for k := range elseSeen {
+					seen[k] = struct{}{}
+				}
+				continue
+			default:
+				continue
+			}
+			attrName = strings.ToLower(attrName)
+			if attrName == "alt" {
+				hasAlt = true
+			}
+			if _, exists := seen[attrName]; exists {
+				v.add(ignored, CheckDuplicateAttribute, SeverityError, e, fmt.Sprintf("duplicate attribute %q", attrName))
+			}
+			seen[attrName] = struct{}{}
+			if checkAttributeNames && !isKnownAttribute(name, attrName) {
+				v.add(ignored, CheckUnknownAttribute, SeverityWarning, e, fmt.Sprintf("unknown attribute %q", attrName))
+			}
+		}
+	}
+	check(e.Attributes, make(map[string]struct{}))
+
+	if name == "img" && !hasAlt && !hasSpread {
+		v.add(ignored, CheckNoAlt, SeverityWarning, e, `missing alt attribute, use alt="" for decorative images`)
+	}
+}
+
+// isKnownAttribute returns true if the attribute is valid for the element. Event
+// handlers, and attributes that contain punctuation, e.g. data-*, aria-*, hx-get
+// or x-on:click, are assumed to be valid.
+func isKnownAttribute(element, attr string) bool {
+	if strings.HasPrefix(attr, "on") || strings.IndexFunc(attr, func(r rune) bool { return r < 'a' || r > 'z' }) >= 0 {
+		return true
+	}
+	if _, ok := globalAttributes[attr]; ok {
+		return true
+	}
+	_, ok := htmlElements[element][attr]
+	return ok
+}
+
+func copySet(m map[string]struct{}) map[string]struct{} {
+	c := make(map[string]struct{}, len(m))
+	for k := range m {
+		c[k] = struct{}{}
+	}
+	return c
+}
+
+// checkSet is a set of check names. The empty name represents every check.
+type checkSet map[string]struct{}
+
+func (cs checkSet) has(check string) bool {
+	if _, ok := cs[""]; ok {
+		return true
+	}
+	_, ok := cs[check]
+	return ok
+}
+
+func (cs checkSet) union(other checkSet) checkSet {
+	if len(other) == 0 {
+		return cs
+	}
+	u := make(checkSet, len(cs)+len(other))
+	for k := range cs {
+		u[k] = struct{}{}
+	}
+	for k := range other {
+		u[k] = struct{}{}
+	}
+	return u
+}
+
+func parseIgnoreDirective(c GoComment) (checks checkSet, ok bool) {
+	names, ok := IgnoredChecks(c.Contents)
+	if !ok {
+		return nil, false
+	}
+	checks = make(checkSet)
+	for _, name := range names {
+		checks[name] = struct{}{}
+	}
+	if len(checks) == 0 {
+		checks[""] = struct{}{}
+	}
+	return checks, true
+}
+
+// IgnoredChecks returns the checks listed by an ignore directive, given the contents of
+// the comment without the leading //, e.g. "templ:ignore no-alt". It returns false if the
+// comment isn't an ignore directive. If no checks are listed, all of them are ignored, and
+// checks is empty.
+func IgnoredChecks(comment string) (checks []string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(comment), ignoreDirective)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, false
+	}
+	checks = strings.FieldsFunc(rest, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+	return checks, true
+}
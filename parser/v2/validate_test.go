@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "valid markup has no issues",
+			input: `<div id="a" class="b" data-x="1" hx-get="/" onclick="f()">
+		<img src="a.png" alt=""/>
+		<ul><li>One</li></ul>
+		<my-element any="thing"></my-element>
+		<svg viewBox="0 0 10 10"><path d="M0 0"></path></svg>
+	</div>`,
+		},
+		{
+			name:     "unknown elements",
+			input:    `<div><blink>Hello</blink></div>`,
+			expected: []string{"4:8: warning: <blink>: unknown element (unknown-element)"},
+		},
+		{
+			name:     "duplicate attributes",
+			input:    `<div class="a" CLASS="b"></div>`,
+			expected: []string{`4:3: error: <div>: duplicate attribute "class" (duplicate-attribute)`},
+		},
+		{
+			name: "attributes in either branch of a conditional attribute are not duplicates of each other",
+			input: `<div
+		if x {
+			class="a"
+		} else {
+			class="b"
+		}
+	></div>`,
+		},
+		{
+			name: "attributes in a conditional attribute can duplicate other attributes",
+			input: `<div class="a"
+		if x {
+			class="b"
+		}
+	></div>`,
+			expected: []string{`4:3: error: <div>: duplicate attribute "class" (duplicate-attribute)`},
+		},
+		{
+			name:     "unknown attributes for known elements",
+			input:    `<a hreff="/">Link</a>`,
+			expected: []string{`4:3: warning: <a>: unknown attribute "hreff" (unknown-attribute)`},
+		},
+		{
+			name:     "list items outside of lists",
+			input:    `<div><li>One</li></div>`,
+			expected: []string{"4:8: warning: <li>: list items should be within <ul>, <ol> or <menu>, not <div> (li-outside-list)"},
+		},
+		{
+			name: "list items at the root of a template, or in templ element children, can be within a list",
+			input: `<li>One</li>
+	@list() {
+		<li>Two</li>
+	}
+	<ol>
+		if x {
+			<li>Three</li>
+		}
+	</ol>`,
+		},
+		{
+			name: "duplicate ids",
+			input: `<div id="a"></div>
+	<div>
+		<span id="a"></span>
+	</div>`,
+			expected: []string{`6:4: warning: <span>: the id "a" is used more than once in the template (duplicate-id)`},
+		},
+		{
+			name:     "images without alt text",
+			input:    `<img src="a.png"/>`,
+			expected: []string{`4:3: warning: <img>: missing alt attribute, use alt="" for decorative images (no-alt)`},
+		},
+		{
+			name:  "images with spread attributes might have alt text",
+			input: `<img { attrs... }/>`,
+		},
+		{
+			name: "checks can be ignored for the next node",
+			input: `//templ:ignore no-alt
+	<img src="a.png"/>
+	<img src="b.png"/>`,
+			expected: []string{`6:3: warning: <img>: missing alt attribute, use alt="" for decorative images (no-alt)`},
+		},
+		{
+			name: "ignored checks apply to children, and other checks still apply",
+			input: `// templ:ignore unknown-element, no-alt
+	<div>
+		<blink><img src="a.png"/></blink>
+		<li>One</li>
+	</div>`,
+			expected: []string{"7:4: warning: <li>: list items should be within <ul>, <ol> or <menu>, not <div> (li-outside-list)"},
+		},
+		{
+			name: "all checks are ignored if none are listed",
+			input: `//templ:ignore
+	<blink><img src="a.png"/></blink>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString("package main\n\ntempl test() {\n\t" + tt.input + "\n}\n")
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var actual []string
+			for _, issue := range Validate(tf) {
+				actual = append(actual, issue.String())
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestValidateChecksEachTemplateSeparately(t *testing.T) {
+	tf, err := ParseString(`package main
+
+templ a() {
+	<div id="a"></div>
+}
+
+templ b() {
+	<div id="a"></div>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	if issues := Validate(tf); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
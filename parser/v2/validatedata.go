@@ -0,0 +1,138 @@
+package parser
+
+import "strings"
+
+// nameSet creates a set from a space separated list of names.
+func nameSet(names string) map[string]struct{} {
+	s := make(map[string]struct{})
+	for _, name := range strings.Fields(names) {
+		s[name] = struct{}{}
+	}
+	return s
+}
+
+// globalAttributes can be used on any HTML element.
+// https://html.spec.whatwg.org/multipage/dom.html#global-attributes
+var globalAttributes = nameSet(`accesskey autocapitalize autocorrect autofocus class contenteditable dir
+	draggable enterkeyhint hidden id inert inputmode is itemid itemprop itemref itemscope
+	itemtype lang nonce popover role slot spellcheck style tabindex title translate`)
+
+// htmlElements maps the name of each HTML element to the attributes that are specific
+// to it. Obsolete elements, e.g. center, are not included.
+// https://html.spec.whatwg.org/multipage/indices.html#elements-3
+var htmlElements = map[string]map[string]struct{}{
+	"a":          nameSet("href target download ping rel hreflang type referrerpolicy"),
+	"abbr":       nil,
+	"address":    nil,
+	"area":       nameSet("alt coords shape href target download ping rel referrerpolicy"),
+	"article":    nil,
+	"aside":      nil,
+	"audio":      nameSet("src crossorigin preload autoplay loop muted controls"),
+	"b":          nil,
+	"base":       nameSet("href target"),
+	"bdi":        nil,
+	"bdo":        nil,
+	"blockquote": nameSet("cite"),
+	"body":       nil,
+	"br":         nil,
+	"button":     nameSet("disabled form formaction formenctype formmethod formnovalidate formtarget name popovertarget popovertargetaction type value"),
+	"canvas":     nameSet("width height"),
+	"caption":    nil,
+	"cite":       nil,
+	"code":       nil,
+	"col":        nameSet("span"),
+	"colgroup":   nameSet("span"),
+	"data":       nameSet("value"),
+	"datalist":   nil,
+	"dd":         nil,
+	"del":        nameSet("cite datetime"),
+	"details":    nameSet("open name"),
+	"dfn":        nil,
+	"dialog":     nameSet("open"),
+	"div":        nil,
+	"dl":         nil,
+	"dt":         nil,
+	"em":         nil,
+	"embed":      nameSet("src type width height"),
+	"fieldset":   nameSet("disabled form name"),
+	"figcaption": nil,
+	"figure":     nil,
+	"footer":     nil,
+	"form":       nameSet("action autocomplete enctype method name novalidate rel target"),
+	"h1":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"h5":         nil,
+	"h6":         nil,
+	"head":       nil,
+	"header":     nil,
+	"hgroup":     nil,
+	"hr":         nil,
+	"html":       nameSet("xmlns"),
+	"i":          nil,
+	"iframe":     nameSet("src srcdoc name sandbox allow allowfullscreen width height referrerpolicy loading"),
+	"img":        nameSet("alt src srcset sizes crossorigin usemap ismap width height referrerpolicy decoding loading fetchpriority"),
+	"input":      nameSet("accept alt autocomplete checked dirname disabled form formaction formenctype formmethod formnovalidate formtarget height list max maxlength min minlength multiple name pattern placeholder popovertarget popovertargetaction readonly required size src step type value width"),
+	"ins":        nameSet("cite datetime"),
+	"kbd":        nil,
+	"label":      nameSet("for"),
+	"legend":     nil,
+	"li":         nameSet("value"),
+	"link":       nameSet("href crossorigin rel as media hreflang type sizes imagesrcset imagesizes referrerpolicy integrity blocking color disabled fetchpriority"),
+	"main":       nil,
+	"map":        nameSet("name"),
+	"mark":       nil,
+	"math":       nil,
+	"menu":       nil,
+	"meta":       nameSet("name content charset media"),
+	"meter":      nameSet("value min max low high optimum"),
+	"nav":        nil,
+	"noscript":   nil,
+	"object":     nameSet("data type name form width height"),
+	"ol":         nameSet("reversed start type"),
+	"optgroup":   nameSet("disabled label"),
+	"option":     nameSet("disabled label selected value"),
+	"output":     nameSet("for form name"),
+	"p":          nil,
+	"picture":    nil,
+	"pre":        nil,
+	"progress":   nameSet("value max"),
+	"q":          nameSet("cite"),
+	"rp":         nil,
+	"rt":         nil,
+	"ruby":       nil,
+	"s":          nil,
+	"samp":       nil,
+	"script":     nameSet("src type nomodule async defer crossorigin integrity referrerpolicy blocking fetchpriority"),
+	"search":     nil,
+	"section":    nil,
+	"select":     nameSet("autocomplete disabled form multiple name required size"),
+	"slot":       nameSet("name"),
+	"small":      nil,
+	"source":     nameSet("type media src srcset sizes width height"),
+	"span":       nil,
+	"strong":     nil,
+	"style":      nameSet("media blocking"),
+	"sub":        nil,
+	"summary":    nil,
+	"sup":        nil,
+	"svg":        nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         nameSet("colspan rowspan headers"),
+	"template":   nameSet("shadowrootmode shadowrootdelegatesfocus shadowrootclonable"),
+	"textarea":   nameSet("autocomplete cols dirname disabled form maxlength minlength name placeholder readonly required rows wrap"),
+	"tfoot":      nil,
+	"th":         nameSet("colspan rowspan headers scope abbr"),
+	"thead":      nil,
+	"time":       nameSet("datetime"),
+	"title":      nil,
+	"tr":         nil,
+	"track":      nameSet("default kind label src srclang"),
+	"u":          nil,
+	"ul":         nil,
+	"var":        nil,
+	"video":      nameSet("src crossorigin poster preload autoplay playsinline loop muted controls width height"),
+	"wbr":        nil,
+}
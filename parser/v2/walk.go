@@ -0,0 +1,117 @@
+package parser
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a template node tree in depth-first, document order. It starts by
+// calling v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor w for each of
+// the children of node, followed by a call of w.Visit(nil).
+//
+// The children of an IfExpression are its Then nodes, the Then nodes of each
+// ElseIfExpression, and then its Else nodes. The children of a SwitchExpression are
+// the children of each of its cases, in order.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	switch n := node.(type) {
+	case Element:
+		walkNodes(v, n.Children)
+	case TemplElementExpression:
+		walkNodes(v, n.Children)
+	case IfExpression:
+		walkNodes(v, n.Then)
+		for _, elseIf := range n.ElseIfs {
+			walkNodes(v, elseIf.Then)
+		}
+		walkNodes(v, n.Else)
+	case SwitchExpression:
+		for _, c := range n.Cases {
+			walkNodes(v, c.Children)
+		}
+	case ForExpression:
+		walkNodes(v, n.Children)
+	}
+	v.Visit(nil)
+}
+
+func walkNodes(v Visitor, nodes []Node) {
+	for _, n := range nodes {
+		Walk(v, n)
+	}
+}
+
+// WalkFile walks the children of each HTMLTemplate in the file, in document order.
+// CSS templates, script templates and Go expressions don't contain nodes, so they
+// aren't visited.
+func WalkFile(v Visitor, tf TemplateFile) {
+	for _, n := range tf.Nodes {
+		if t, ok := n.(HTMLTemplate); ok {
+			walkNodes(v, t.Children)
+		}
+	}
+}
+
+// MapChildren returns a copy of the node, where each list of its children is replaced with
+// the result of f, e.g. to remove whitespace from a tree before walking it. The lists are the
+// ones that Walk visits: the Then nodes of an IfExpression, the Then nodes of each
+// ElseIfExpression and its Else nodes are separate lists, as are the children of each case of
+// a SwitchExpression. Nodes without children are returned as they are.
+func MapChildren(node Node, f func(children []Node) []Node) Node {
+	switch n := node.(type) {
+	case Element:
+		n.Children = f(n.Children)
+		return n
+	case TemplElementExpression:
+		n.Children = f(n.Children)
+		return n
+	case IfExpression:
+		n.Then = f(n.Then)
+		elseIfs := make([]ElseIfExpression, len(n.ElseIfs))
+		for i, elseIf := range n.ElseIfs {
+			elseIf.Then = f(elseIf.Then)
+			elseIfs[i] = elseIf
+		}
+		n.ElseIfs = elseIfs
+		n.Else = f(n.Else)
+		return n
+	case SwitchExpression:
+		cases := make([]CaseExpression, len(n.Cases))
+		for i, c := range n.Cases {
+			c.Children = f(c.Children)
+			cases[i] = c
+		}
+		n.Cases = cases
+		return n
+	case ForExpression:
+		n.Children = f(n.Children)
+		return n
+	}
+	return node
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a template node tree in depth-first, document order. It starts
+// by calling f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the children of node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// InspectFile calls Inspect for the children of each HTMLTemplate in the file.
+func InspectFile(tf TemplateFile, f func(Node) bool) {
+	WalkFile(inspector(f), tf)
+}
@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const walkTestTemplate = `package main
+
+css className() {
+	color: red;
+}
+
+templ Page(items []string, children templ.Component) {
+	<!DOCTYPE html>
+	<!-- comment -->
+	// templ comment
+	<div class="a">Fish &amp; chips</div>
+	<script>var x = 1;</script>
+	{! Header() }
+	@Layout() {
+		{ children... }
+	}
+	if len(items) == 0 {
+		<p>None</p>
+	} else if len(items) == 1 {
+		<p>One</p>
+	} else {
+		<p>Many</p>
+	}
+	switch len(items) {
+		case 0:
+			<span>0</span>
+		default:
+			<span>n</span>
+	}
+	for _, item := range items {
+		<li>{ item }</li>
+	}
+}
+`
+
+// describe returns a short description of the node, so that the order in which nodes
+// are visited can be compared.
+func describe(n Node) string {
+	switch n := n.(type) {
+	case nil:
+		return "end"
+	case Element:
+		return "<" + n.Name + ">"
+	case RawElement:
+		return "raw <" + n.Name + ">"
+	case Text:
+		return "text " + n.Value
+	case CharacterReference:
+		return "ref " + n.Value
+	case StringExpression:
+		return "{ " + n.Expression.Value + " }"
+	case IfExpression:
+		return "if " + n.Expression.Value
+	case SwitchExpression:
+		return "switch " + n.Expression.Value
+	case ForExpression:
+		return "for " + n.Expression.Value
+	case TemplElementExpression:
+		return "@" + n.Expression.Value
+	case CallTemplateExpression:
+		return "{! " + n.Expression.Value + " }"
+	}
+	return reflect.TypeOf(n).Name()
+}
+
+// nodeTypes returns the names of the types in the package which implement Node.
+func nodeTypes(t *testing.T) (names []string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	pkgs, err := goparser.ParseDir(fset, ".", func(fi fs.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }, 0)
+	if err != nil {
+		t.Fatalf("failed to parse package: %v", err)
+	}
+	for _, f := range pkgs["parser"].Files {
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Name.Name != "IsNode" {
+				continue
+			}
+			if ident, ok := fn.Recv.List[0].Type.(*ast.Ident); ok {
+				names = append(names, ident.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		t.Fatal("no node types found")
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWalk(t *testing.T) {
+	tf, err := ParseString(walkTestTemplate)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("nodes are visited in document order", func(t *testing.T) {
+		var actual []string
+		var depth int
+		InspectFile(tf, func(n Node) bool {
+			if n == nil {
+				depth--
+				return false
+			}
+			depth++
+			if _, ok := n.(Whitespace); !ok {
+				actual = append(actual, strings.Repeat("  ", depth-1)+describe(n))
+			}
+			return true
+		})
+		expected := []string{
+			"DocType",
+			"HTMLComment",
+			"GoComment",
+			"<div>",
+			"  text Fish ",
+			"  ref &amp;",
+			"  text chips",
+			"raw <script>",
+			"{! Header() }",
+			"@Layout()",
+			"  ChildrenExpression",
+			"if len(items) == 0",
+			"  <p>",
+			"    text None",
+			"  <p>",
+			"    text One",
+			"  <p>",
+			"    text Many",
+			"switch len(items)",
+			"  <span>",
+			"    text 0",
+			"  <span>",
+			"    text n",
+			"for _, item := range items",
+			"  <li>",
+			"    { item }",
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+		if depth != 0 {
+			t.Errorf("expected every visited node to be followed by a nil visit, depth is %d", depth)
+		}
+	})
+	t.Run("every node type is visited exactly once", func(t *testing.T) {
+		visited := map[string]int{}
+		InspectFile(tf, func(n Node) bool {
+			if n != nil {
+				visited[reflect.TypeOf(n).Name()]++
+			}
+			return true
+		})
+		for _, name := range nodeTypes(t) {
+			if _, ok := visited[name]; !ok {
+				t.Errorf("%s was not visited: add it to the test template, and to Walk if it has children", name)
+			}
+		}
+		// Every other node in the template is unique.
+		for name, count := range visited {
+			if name == "Whitespace" || name == "Element" || name == "Text" {
+				continue
+			}
+			if count != 1 {
+				t.Errorf("expected %s to be visited once, got %d", name, count)
+			}
+		}
+	})
+	t.Run("children are skipped when the visitor returns false", func(t *testing.T) {
+		var actual []string
+		InspectFile(tf, func(n Node) bool {
+			switch n := n.(type) {
+			case IfExpression, SwitchExpression, ForExpression, TemplElementExpression, Element:
+				actual = append(actual, describe(n))
+				return false
+			}
+			return true
+		})
+		expected := []string{
+			"<div>",
+			"@Layout()",
+			"if len(items) == 0",
+			"switch len(items)",
+			"for _, item := range items",
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("MapChildren replaces each list of children", func(t *testing.T) {
+		lists := map[string]int{}
+		var mapped []Node
+		for _, n := range tf.Nodes[1].(HTMLTemplate).Children {
+			m := MapChildren(n, func(children []Node) []Node {
+				lists[describe(n)]++
+				return nil
+			})
+			mapped = append(mapped, m)
+		}
+		expected := map[string]int{
+			"<div>":                      1,
+			"@Layout()":                  1,
+			"if len(items) == 0":         3,
+			"switch len(items)":          2,
+			"for _, item := range items": 1,
+		}
+		if diff := cmp.Diff(expected, lists); diff != "" {
+			t.Error(diff)
+		}
+		// Only the mapped nodes are visited, since their children were removed.
+		var visited int
+		for _, n := range mapped {
+			Inspect(n, func(n Node) bool {
+				if n != nil {
+					visited++
+				}
+				return true
+			})
+		}
+		if visited != len(mapped) {
+			t.Errorf("expected %d nodes to be visited, got %d", len(mapped), visited)
+		}
+		// The original nodes are unchanged.
+		for _, n := range tf.Nodes[1].(HTMLTemplate).Children {
+			if ifExpr, ok := n.(IfExpression); ok && len(ifExpr.ElseIfs[0].Then) == 0 {
+				t.Error("expected the else if nodes of the original node to be unchanged")
+			}
+		}
+	})
+}
@@ -6,14 +6,19 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
 	"net/http"
+	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode"
 
 	"github.com/a-h/templ/safehtml"
 )
@@ -35,12 +40,16 @@ func (cf ComponentFunc) Render(ctx context.Context, w io.Writer) error {
 	return cf(ctx, w)
 }
 
+// WithChildren sets the children to be rendered by the next component that's
+// rendered with the context, e.g. @layout() { <p>children</p> }.
 func WithChildren(ctx context.Context, children Component) context.Context {
 	ctx, v := getContext(ctx)
 	v.children = &children
 	return ctx
 }
 
+// ClearChildren removes the children from the context, so that they're not
+// passed on to components that are rendered within a component.
 func ClearChildren(ctx context.Context) context.Context {
 	_, v := getContext(ctx)
 	v.children = nil
@@ -50,6 +59,23 @@ func ClearChildren(ctx context.Context) context.Context {
 // NopComponent is a component that doesn't render anything.
 var NopComponent = ComponentFunc(func(ctx context.Context, w io.Writer) error { return nil })
 
+// Join returns a component that renders the components one after another, e.g. to pass a
+// list of components to a layout as a single parameter. Nil components are skipped. If a
+// component returns an error, the components after it aren't rendered.
+func Join(components ...Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		for _, c := range components {
+			if c == nil {
+				continue
+			}
+			if err = c.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // GetChildren from the context.
 func GetChildren(ctx context.Context) Component {
 	_, v := getContext(ctx)
@@ -65,31 +91,109 @@ type ComponentHandler struct {
 	Status       int
 	ContentType  string
 	ErrorHandler func(r *http.Request, err error) http.Handler
+	// StreamResponse renders the component directly to the http.ResponseWriter, instead of
+	// rendering the whole component before it's written, so that the output can be sent to
+	// the browser early with templ.Flush.
+	StreamResponse bool
+	// Fragments are the names of the fragments of the component that are rendered, instead
+	// of the whole component. See RenderFragment.
+	Fragments []string
 }
 
 const componentHandlerErrorMessage = "templ: failed to render template"
 
 // ServeHTTP implements the http.Handler interface.
 func (ch ComponentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if ch.StreamResponse {
+		ch.serveStream(w, r)
+		return
+	}
+	// Render to a buffer, so that an error can be returned instead of a partial page.
+	b := GetBuffer()
+	defer ReleaseBuffer(b)
+	if err := ch.render(r.Context(), b); err != nil {
+		ch.handleError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", ch.ContentType)
 	if ch.Status != 0 {
 		w.WriteHeader(ch.Status)
 	}
-	w.Header().Add("Content-Type", ch.ContentType)
-	err := ch.Component.Render(r.Context(), w)
-	if err != nil {
-		if ch.ErrorHandler != nil {
-			ch.ErrorHandler(r, err).ServeHTTP(w, r)
-			return
-		}
-		http.Error(w, componentHandlerErrorMessage, http.StatusInternalServerError)
+	_, _ = b.WriteTo(w)
+}
+
+func (ch ComponentHandler) serveStream(w http.ResponseWriter, r *http.Request) {
+	sw := &streamingResponseWriter{w: w, contentType: ch.ContentType, status: ch.Status}
+	err := ch.render(r.Context(), sw)
+	if err == nil {
+		// Write the headers of an empty response.
+		sw.writeHeader()
+		return
 	}
+	if !sw.wroteHeader {
+		ch.handleError(w, r, err)
+		return
+	}
+	// The status code and part of the page have already been sent. Aborting the handler
+	// closes the connection, so that the browser doesn't treat the truncated page as complete.
+	panic(http.ErrAbortHandler)
 }
 
-// Handler creates a http.Handler that renders the template.
+func (ch ComponentHandler) render(ctx context.Context, w io.Writer) error {
+	if len(ch.Fragments) > 0 {
+		return RenderFragment(ctx, w, ch.Component, ch.Fragments...)
+	}
+	return ch.Component.Render(ctx, w)
+}
+
+func (ch ComponentHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ch.ErrorHandler != nil {
+		ch.ErrorHandler(r, err).ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, componentHandlerErrorMessage, http.StatusInternalServerError)
+}
+
+// streamingResponseWriter writes the headers of the response when the first output is
+// written or flushed, so that the status code can still be changed if the component
+// returns an error before then.
+type streamingResponseWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	status      int
+	wroteHeader bool
+}
+
+func (sw *streamingResponseWriter) writeHeader() {
+	if sw.wroteHeader {
+		return
+	}
+	sw.wroteHeader = true
+	sw.w.Header().Set("Content-Type", sw.contentType)
+	if sw.status != 0 {
+		sw.w.WriteHeader(sw.status)
+	}
+}
+
+func (sw *streamingResponseWriter) Write(p []byte) (n int, err error) {
+	sw.writeHeader()
+	return sw.w.Write(p)
+}
+
+func (sw *streamingResponseWriter) Flush() {
+	sw.writeHeader()
+	if f, ok := sw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Handler creates a http.Handler that renders the template. By default, the whole component
+// is rendered before it's written to the response, with a Content-Type of
+// text/html; charset=utf-8.
 func Handler(c Component, options ...func(*ComponentHandler)) *ComponentHandler {
 	ch := &ComponentHandler{
 		Component:   c,
-		ContentType: "text/html",
+		ContentType: "text/html; charset=utf-8",
 	}
 	for _, o := range options {
 		o(ch)
@@ -111,6 +215,24 @@ func WithContentType(contentType string) func(*ComponentHandler) {
 	}
 }
 
+// WithStreaming sets the ComponentHandler to write the output of the component to the
+// response as it's rendered, so that it can be sent to the browser early with templ.Flush.
+// If the component returns an error after output has been written, the connection is
+// closed, since the status code has already been sent.
+func WithStreaming() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.StreamResponse = true
+	}
+}
+
+// WithFragment sets the ComponentHandler to render only the fragments of the component with
+// the names, e.g. to respond to an htmx request with part of a page. See RenderFragment.
+func WithFragment(names ...string) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Fragments = names
+	}
+}
+
 // WithErrorHandler sets the error handler used if rendering fails.
 func WithErrorHandler(eh func(r *http.Request, err error) http.Handler) func(*ComponentHandler) {
 	return func(ch *ComponentHandler) {
@@ -123,13 +245,223 @@ func EscapeString(s string) string {
 	return html.EscapeString(s)
 }
 
+// EscapeAny converts the value of a string expression within templates to escaped HTML text.
+// Strings, booleans, numbers, and types that implement fmt.Stringer are supported. A nil
+// pointer that implements fmt.Stringer is rendered as an empty string. Other types return
+// an error.
+func EscapeAny[T any](value T) (string, error) {
+	// Strings are checked first, so that they aren't converted to an escaping interface.
+	if s, ok := any(value).(string); ok {
+		return html.EscapeString(s), nil
+	}
+	return escapeAny(value)
+}
+
+func escapeAny(value any) (string, error) {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case fmt.Stringer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "", nil
+		}
+		return html.EscapeString(v.String()), nil
+	}
+	// Types defined in terms of the basic types, e.g. type Count int.
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return html.EscapeString(rv.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("templ: cannot render a value of type %T, expected a string, bool, number or fmt.Stringer", value)
+}
+
+// Error is returned by generated code when an expression or a component within a template
+// returns an error, with the position of the expression in the templ file. The error returned
+// by the expression can be checked with errors.Is and errors.As.
+type Error struct {
+	// Err is the error returned by the expression or component.
+	Err error
+	// FileName of the templ file, relative to the root of the Go module, e.g.
+	// "views/home.templ". It's empty if the code was generated without a file name.
+	FileName string
+	// Line and Col of the expression in the templ file, starting at 1.
+	Line int
+	Col  int
+}
+
+func (e *Error) Error() string {
+	if e.FileName == "" {
+		return fmt.Sprintf("%d:%d: %v", e.Line, e.Col, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", e.FileName, e.Line, e.Col, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError is used by generated code to add the position of an expression to the error it
+// returned. Errors that already have a position, because they were returned from within a
+// nested component, are returned as they are, so that the position is where the error
+// occurred.
+func WrapError(err error, fileName string, line, col int) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Err: err, FileName: fileName, Line: line, Col: col}
+}
+
+// Raw renders the HTML as-is, without escaping it, e.g. @templ.Raw(renderedMarkdown).
+// It must only be used with trusted or sanitized HTML, since anything else can be used to
+// carry out XSS attacks. It's the only way to render unescaped HTML within templates, so
+// that its use can be found by searching for templ.Raw.
+func Raw(html string) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		_, err = io.WriteString(w, html)
+		return err
+	})
+}
+
+// Flush writes the output that has been rendered so far to the writer that the outermost
+// component is being rendered to, and flushes it, if the writer implements http.Flusher,
+// e.g. @templ.Flush(). This sends the start of a page to the browser while slower parts of
+// the page are rendered. Otherwise, it does nothing.
+func Flush() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		// Components write to a buffer, which is written to the target when the outermost
+		// component has been rendered.
+		if b, ok := w.(*bytes.Buffer); ok {
+			if t, ok := ctx.Value(flushTargetContextKey).(flushTarget); ok && t.buffer == b {
+				if _, err = b.WriteTo(t.w); err != nil {
+					return err
+				}
+				w = t.w
+			}
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return nil
+	})
+}
+
+type flushTarget struct {
+	buffer *bytes.Buffer
+	w      io.Writer
+}
+
+// WithFlushTarget is used by generated code to record that the contents of the buffer are
+// written to w, so that Flush can write them early. Writers that don't implement
+// http.Flusher aren't recorded, since there's nothing to gain from writing to them early.
+func WithFlushTarget(ctx context.Context, buffer *bytes.Buffer, w io.Writer) context.Context {
+	if _, ok := w.(http.Flusher); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, flushTargetContextKey, flushTarget{buffer: buffer, w: w})
+}
+
+// Fragment returns a component that renders its children, e.g. @templ.Fragment("list") { ... }.
+// It names a region of a template, so that RenderFragment, or a Handler created with the
+// WithFragment option, can render the region on its own, e.g. in response to an htmx request.
+func Fragment(name string) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		children := GetChildren(ctx)
+		ctx = ClearChildren(ctx)
+		t, ok := ctx.Value(fragmentTargetContextKey).(*fragmentTarget)
+		if !ok || t.within || !t.names[name] {
+			return children.Render(ctx, w)
+		}
+		// The content of the fragment is written to the target, instead of the output of
+		// the template, which is skipped. CSS, scripts and once handles are tracked
+		// separately, so that they're rendered within the fragment, even if they were
+		// used in the skipped output first.
+		ctx = context.WithValue(ctx, fragmentTargetContextKey, &fragmentTarget{within: true})
+		ctx = context.WithValue(ctx, contextKey, t.v)
+		return children.Render(ctx, t.w)
+	})
+}
+
+type fragmentTarget struct {
+	names map[string]bool
+	w     io.Writer
+	v     *contextValue
+	// within is true within a fragment that's being rendered, so that nested fragments are
+	// rendered as part of it.
+	within bool
+}
+
+// RenderFragment renders the content of the fragments of the component with the names to w,
+// in the order that they're rendered, e.g. templ.RenderFragment(ctx, w, page(), "list").
+// Nothing else is written to w. Outside the fragments, generated components skip their output
+// without evaluating its expressions, but still run their if, for and switch statements, and
+// render the components they call, to find the fragments. If no fragments match, nothing is
+// written.
+func RenderFragment(ctx context.Context, w io.Writer, c Component, names ...string) error {
+	t := &fragmentTarget{
+		names: make(map[string]bool, len(names)),
+		w:     w,
+		v:     &contextValue{},
+	}
+	for _, name := range names {
+		t.names[name] = true
+	}
+	ctx = context.WithValue(ctx, fragmentTargetContextKey, t)
+	ctx = context.WithValue(ctx, contextKey, &contextValue{skip: true})
+	return c.Render(ctx, io.Discard)
+}
+
+// IsOutputSkipped returns true if the output of the component being rendered with the context
+// is skipped, because it's outside the fragments being rendered by RenderFragment. Generated
+// components check it before they evaluate expressions and write output.
+func IsOutputSkipped(ctx context.Context) bool {
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	return ok && v.skip
+}
+
 // Bool attribute value.
 func Bool(value bool) bool {
 	return value
 }
 
-// Classes for CSS.
-// Supported types are string, ConstantCSSClass, ComponentCSSClass, map[string]bool.
+// Classes for CSS, e.g. class={ templ.Classes("button", templ.KV("is-primary", isPrimary)) }.
+// Supported types are string, []string, ConstantCSSClass, ComponentCSSClass, map[string]bool,
+// KeyValue[string, bool] and []KeyValue[string, bool], created with KV, and CSSClasses.
+// Class names are rendered in the order that they're first added, once, and are omitted if
+// the last condition for the name is false. Empty names are ignored.
 func Classes(classes ...any) CSSClasses {
 	return CSSClasses(classes)
 }
@@ -202,18 +534,21 @@ func (cp *cssProcessor) Add(item any) {
 	}
 }
 
-func (cp *cssProcessor) AddUnsanitized(className string, enabled bool) {
-	for _, className := range strings.Split(className, " ") {
-		className = strings.TrimSpace(className)
+func (cp *cssProcessor) AddUnsanitized(classNames string, enabled bool) {
+	for _, className := range strings.Fields(classNames) {
 		if isSafe := safeClassName.MatchString(className); !isSafe {
-			className = fallbackClassName
-			enabled = true // Always display the fallback classname.
+			// Always display the fallback classname.
+			cp.AddSanitized(fallbackClassName, true)
+			continue
 		}
 		cp.AddSanitized(className, enabled)
 	}
 }
 
 func (cp *cssProcessor) AddSanitized(className string, enabled bool) {
+	if className == "" {
+		return
+	}
 	cp.classNameToEnabled[className] = enabled
 	cp.orderedNames = append(cp.orderedNames, className)
 }
@@ -250,6 +585,115 @@ func KV[TKey comparable, TValue any](key TKey, value TValue) KeyValue[TKey, TVal
 	}
 }
 
+// Styles for the style attribute, e.g. style={ templ.Styles(map[string]string{"color": color}) }.
+// Supported types are strings of declarations, e.g. "color: red; width: 10px",
+// map[string]string, KeyValue[string, string] and []KeyValue[string, string] of property
+// names and values, which are sanitized, SafeCSS, which is rendered as it is, and CSSStyles.
+// Properties are rendered in the order that they're first added, with map keys in sorted
+// order, once, with the last value set for the property. Properties with empty values are
+// omitted.
+func Styles(styles ...any) CSSStyles {
+	return CSSStyles(styles)
+}
+
+// CSSStyles is a slice of CSS styles.
+type CSSStyles []any
+
+// String returns the CSS declarations of all of the styles, e.g. "color:red;width:10px;".
+func (styles CSSStyles) String() string {
+	if len(styles) == 0 {
+		return ""
+	}
+	sp := &styleProcessor{properties: make(map[string]int)}
+	for _, v := range styles {
+		sp.Add(v)
+	}
+	return sp.String()
+}
+
+type styleProcessor struct {
+	// declarations are rendered in order. Each property has a single declaration, which is
+	// updated if the property is set again.
+	declarations []string
+	// properties maps property names to the index of their declaration.
+	properties map[string]int
+}
+
+func (sp *styleProcessor) Add(item any) {
+	switch s := item.(type) {
+	case string:
+		for _, declaration := range strings.Split(s, ";") {
+			property, value, ok := strings.Cut(declaration, ":")
+			if !ok && strings.TrimSpace(declaration) != "" {
+				// Declarations that aren't property: value pairs can't be sanitized.
+				sp.AddDeclarations(safehtml.InnocuousPropertyName + ":" + safehtml.InnocuousPropertyValue)
+				continue
+			}
+			sp.AddProperty(property, value)
+		}
+	case SafeCSS:
+		sp.AddDeclarations(string(s))
+	case map[string]string:
+		// Map keys are iterated in a random order, so they're sorted to produce consistent output.
+		properties := make([]string, 0, len(s))
+		for property := range s {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			sp.AddProperty(property, s[property])
+		}
+	case KeyValue[string, string]:
+		sp.AddProperty(s.Key, s.Value)
+	case []KeyValue[string, string]:
+		for _, kv := range s {
+			sp.AddProperty(kv.Key, kv.Value)
+		}
+	case CSSStyles:
+		for _, item := range s {
+			sp.Add(item)
+		}
+	default:
+		sp.AddDeclarations(safehtml.InnocuousPropertyName + ":" + safehtml.InnocuousPropertyValue)
+	}
+}
+
+// AddDeclarations adds declarations that have already been sanitized, e.g. "color:red".
+func (sp *styleProcessor) AddDeclarations(declarations string) {
+	declarations = strings.TrimRight(strings.TrimSpace(declarations), ";")
+	if declarations == "" {
+		return
+	}
+	sp.declarations = append(sp.declarations, declarations+";")
+}
+
+// AddProperty sanitizes and adds the property, replacing any previous value.
+func (sp *styleProcessor) AddProperty(property, value string) {
+	property, value = strings.TrimSpace(property), strings.TrimSpace(value)
+	if property == "" {
+		return
+	}
+	if value == "" {
+		// The property is removed, so that an empty value can be used to unset it.
+		if i, ok := sp.properties[strings.ToLower(property)]; ok {
+			sp.declarations[i] = ""
+		}
+		return
+	}
+	declaration := string(SanitizeCSS(property, value))
+	property, _, _ = strings.Cut(declaration, ":")
+	if i, ok := sp.properties[property]; ok {
+		sp.declarations[i] = declaration
+		return
+	}
+	sp.properties[property] = len(sp.declarations)
+	sp.declarations = append(sp.declarations, declaration)
+}
+
+func (sp *styleProcessor) String() string {
+	return strings.Join(sp.declarations, "")
+}
+
 var safeClassName = regexp.MustCompile(`^-?[_a-zA-Z]+[-_a-zA-Z0-9]*$`)
 
 const fallbackClassName = "--templ-css-class-safe-name"
@@ -328,7 +772,7 @@ func (cssm CSSMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Add registered classes to the context.
 	ctx, v := getContext(r.Context())
 	for _, c := range cssm.CSSHandler.Classes {
-		v.addClass(c.ID)
+		cssClassOnceHandle(c.ID).skip(v)
 	}
 	// Serve the request. Templ components will use the updated context
 	// to know to skip rendering <style> elements for any component CSS
@@ -374,14 +818,14 @@ func RenderCSSItems(ctx context.Context, w io.Writer, classes ...any) (err error
 	if len(classes) == 0 {
 		return nil
 	}
-	_, v := getContext(ctx)
-	sb := new(strings.Builder)
+	ctx, _ = getContext(ctx)
+	sb := GetBuffer()
+	defer ReleaseBuffer(sb)
 	for _, c := range classes {
 		switch ccc := c.(type) {
 		case ComponentCSSClass:
-			if !v.hasClassBeenRendered(ccc.ID) {
-				sb.WriteString(string(ccc.Class))
-				v.addClass(ccc.ID)
+			if err = cssClassOnceHandle(ccc.ID).render(ctx, sb, Raw(string(ccc.Class))); err != nil {
+				return err
 			}
 		case CSSClasses:
 			if err = RenderCSSItems(ctx, w, ccc...); err != nil {
@@ -394,10 +838,10 @@ func RenderCSSItems(ctx context.Context, w io.Writer, classes ...any) (err error
 		}
 	}
 	if sb.Len() > 0 {
-		if _, err = io.WriteString(w, `<style type="text/css">`); err != nil {
+		if _, err = io.WriteString(w, `<style type="text/css"`+nonceAttribute(ctx)+`>`); err != nil {
 			return err
 		}
-		if _, err = io.WriteString(w, sb.String()); err != nil {
+		if _, err = sb.WriteTo(w); err != nil {
 			return err
 		}
 		if _, err = io.WriteString(w, `</style>`); err != nil {
@@ -421,20 +865,124 @@ func SanitizeCSS(property, value string) SafeCSS {
 // FailedSanitizationURL is returned if a URL fails sanitization checks.
 const FailedSanitizationURL = SafeURL("about:invalid#TemplFailedSanitizationURL")
 
-// URL sanitizes the input string s and returns a SafeURL.
+// URL sanitizes the input string s and returns a SafeURL. URLs with the http, https, mailto
+// or tel schemes, data:image/ URLs other than SVG images, and relative URLs are returned
+// unchanged. Other URLs, e.g. javascript:, vbscript: and data:text/html URLs, are replaced
+// with FailedSanitizationURL. A colon after the first /, ? or # isn't part of a scheme, e.g.
+// /search?q=a:b.
 func URL(s string) SafeURL {
-	if i := strings.IndexRune(s, ':'); i >= 0 && !strings.ContainsRune(s[:i], '/') {
-		protocol := s[:i]
-		if !strings.EqualFold(protocol, "http") && !strings.EqualFold(protocol, "https") && !strings.EqualFold(protocol, "mailto") {
-			return FailedSanitizationURL
+	// Browsers remove leading and trailing control characters and spaces, and tabs and
+	// newlines within the URL, before reading the scheme, e.g. " java	script:".
+	u := strings.TrimFunc(s, func(r rune) bool { return r <= ' ' })
+	u = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
 		}
+		return r
+	}, u)
+	i := strings.IndexRune(u, ':')
+	if i < 0 || strings.ContainsAny(u[:i], "/?#") {
+		return SafeURL(s)
 	}
-	return SafeURL(s)
+	switch strings.ToLower(u[:i]) {
+	case "http", "https", "mailto", "tel":
+		return SafeURL(s)
+	case "data":
+		// SVG images can contain scripts, which run if the URL is opened in a frame.
+		mediaType := strings.ToLower(u[i+1:])
+		if strings.HasPrefix(mediaType, "image/") && !strings.HasPrefix(mediaType, "image/svg") {
+			return SafeURL(s)
+		}
+	}
+	return FailedSanitizationURL
 }
 
 // SafeURL is a URL that has been sanitized.
 type SafeURL string
 
+// Spread attributes.
+
+// Attributes is a set of attributes that can be spread onto an element, e.g. <div { attrs... }>.
+//
+// Supported value types are string, bool and SafeURL. Boolean attributes are only
+// rendered when true. String values of URL attributes, such as href, src and action,
+// are sanitized with URL. Event handler attributes, such as onclick, are not rendered,
+// whatever the type of the value, because they would be run as JavaScript. Names that
+// aren't valid attribute names, and values of other types, are not rendered.
+type Attributes map[string]any
+
+// urlAttributeNames are sanitized when spread, since the element isn't known.
+var urlAttributeNames = map[string]struct{}{
+	"href":       {},
+	"src":        {},
+	"action":     {},
+	"formaction": {},
+	"poster":     {},
+	"cite":       {},
+}
+
+// isEventHandlerAttribute returns true if the value of the attribute is run as JavaScript.
+func isEventHandlerAttribute(name string) bool {
+	return strings.HasPrefix(name, "on") || strings.HasPrefix(name, "hx-on:")
+}
+
+// isValidAttributeName returns true if the name is a valid HTML attribute name, so that it
+// can't end the attribute, or add another one, e.g. "x onmouseover".
+func isValidAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+		switch r {
+		case '"', '\'', '>', '/', '=':
+			return false
+		}
+	}
+	return true
+}
+
+// RenderAttributes writes the attributes to w, sorted by name so that the output is stable.
+func RenderAttributes(ctx context.Context, w io.Writer, attributes Attributes) (err error) {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var value string
+		// Attribute names aren't case sensitive, e.g. HREF is the same as href.
+		lowerName := strings.ToLower(name)
+		if !isValidAttributeName(name) || isEventHandlerAttribute(lowerName) {
+			continue
+		}
+		switch v := attributes[name].(type) {
+		case string:
+			value = v
+			if _, isURL := urlAttributeNames[lowerName]; isURL {
+				value = string(URL(v))
+			}
+		case SafeURL:
+			value = string(v)
+		case bool:
+			if v {
+				if _, err = io.WriteString(w, " "+EscapeString(name)); err != nil {
+					return err
+				}
+			}
+			continue
+		default:
+			continue
+		}
+		if _, err = io.WriteString(w, " "+EscapeString(name)+`="`+EscapeString(value)+`"`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Script handling.
 
 // SafeScript encodes unknown parameters for safety.
@@ -452,43 +1000,185 @@ func SafeScript(functionName string, params ...interface{}) string {
 	return sb.String()
 }
 
+// JSONScript renders a <script type="application/json"> element with the id, containing the
+// JSON encoding of v, for client-side JavaScript to read with JSON.parse. The characters <, >
+// and & are escaped within JSON strings, so the data can't close the element. If v can't be
+// encoded, the error is returned when the component is rendered.
+func JSONScript(id string, v any) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		// json.Marshal escapes <, > and & as \u003c, \u003e and \u0026.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("templ: failed to encode JSON script %q: %w", id, err)
+		}
+		if _, err = io.WriteString(w, `<script type="application/json" id="`+EscapeString(id)+`">`); err != nil {
+			return err
+		}
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, `</script>`)
+		return err
+	})
+}
+
 type contextKeyType int
 
-const contextKey = contextKeyType(0)
+const (
+	contextKey = contextKeyType(iota)
+	nonceContextKey
+	flushTargetContextKey
+	fragmentTargetContextKey
+	tracerContextKey
+)
+
+// WithNonce sets the Content-Security-Policy nonce that's added to the <script> and <style>
+// elements rendered by templ for script templates and CSS components, e.g. in middleware
+// that sets a Content-Security-Policy header of script-src 'nonce-{nonce}'.
+func WithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, nonceContextKey, nonce)
+}
+
+// GetNonce returns the Content-Security-Policy nonce set with WithNonce, or an empty string
+// if there isn't one, e.g. <script nonce={ templ.GetNonce(ctx) }>.
+func GetNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(nonceContextKey).(string)
+	return nonce
+}
+
+// Tracer is called when a component starts to render, with the qualified name of the
+// component, e.g. "main.page", or "components.(*Button).Render" for methods. If it returns an
+// end function, end is called when the component has rendered, with the error returned by
+// the component, if any.
+type Tracer func(name string) (end func(err error))
+
+// WithTracing sets the tracer that's called by generated components as they render, e.g. to
+// record the time taken to render each component in a span.
+func WithTracing(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerContextKey, tracer)
+}
+
+// TraceRender is called by generated code at the start of each component render. It returns
+// the end function of the tracer set with WithTracing, or nil if there isn't a tracer, so
+// that components don't allocate when tracing isn't enabled.
+func TraceRender(ctx context.Context, name string) (end func(err error)) {
+	tracer, _ := ctx.Value(tracerContextKey).(Tracer)
+	if tracer == nil {
+		return nil
+	}
+	return tracer(name)
+}
+
+// WithValue returns a copy of the context with the value set for the key, so that templates
+// can read it with Value, e.g. in middleware that sets the current user or locale for a
+// request. As with context.WithValue, the key should be of an unexported type.
+func WithValue(ctx context.Context, key, value any) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+// Value returns the value set for the key, or the zero value of T if there isn't one, or it's
+// of a different type, e.g. { templ.Value[string](ctx, localeKey) }.
+func Value[T any](ctx context.Context, key any) T {
+	v, _ := ctx.Value(key).(T)
+	return v
+}
+
+// nonceAttribute returns the nonce attribute for elements rendered by templ, or an empty
+// string if a nonce hasn't been set.
+func nonceAttribute(ctx context.Context) string {
+	nonce := GetNonce(ctx)
+	if nonce == "" {
+		return ""
+	}
+	return ` nonce="` + EscapeString(nonce) + `"`
+}
 
 type contextValue struct {
 	ss       map[string]struct{}
 	children *Component
+	// skip is true if the output of components is skipped, outside the fragments being
+	// rendered by RenderFragment.
+	skip bool
 }
 
-func (v *contextValue) addScript(s string) {
+// setHasBeenRendered records that the item with the key has been rendered within the
+// render tree, e.g. the content of a OnceHandle.
+func (v *contextValue) setHasBeenRendered(key string) {
 	if v.ss == nil {
 		v.ss = map[string]struct{}{}
 	}
-	v.ss["script_"+s] = struct{}{}
+	v.ss[key] = struct{}{}
 }
 
-func (v *contextValue) hasScriptBeenRendered(s string) (ok bool) {
-	if v.ss == nil {
-		v.ss = map[string]struct{}{}
-	}
-	_, ok = v.ss["script_"+s]
+func (v *contextValue) hasBeenRendered(key string) (ok bool) {
+	_, ok = v.ss[key]
 	return
 }
 
-func (v *contextValue) addClass(s string) {
-	if v.ss == nil {
-		v.ss = map[string]struct{}{}
+var onceHandleCount uint64
+
+// OnceHandle renders its children once within a render tree, e.g. a <link rel="preload"> or
+// analytics snippet that's used by several components. The first time that Once is rendered,
+// its children are rendered, and afterwards, it renders nothing until the next time a
+// component is rendered with a new context. The CSS of each CSS class, and the function of
+// each script, are rendered once in the same way, with a handle for each class and script.
+type OnceHandle struct {
+	id string
+}
+
+// NewOnceHandle creates a OnceHandle. Each handle is independent, so create one per item, and
+// share it between the components that use the item, e.g. as a package level variable.
+//
+//	var fontPreloadHandle = templ.NewOnceHandle()
+//
+//	templ heading() {
+//		@fontPreloadHandle.Once() {
+//			<link rel="preload" href="/fonts/heading.woff2" as="font" type="font/woff2" crossorigin/>
+//		}
+//		<h1>Heading</h1>
+//	}
+func NewOnceHandle() *OnceHandle {
+	return &OnceHandle{
+		id: "once_" + strconv.FormatUint(atomic.AddUint64(&onceHandleCount, 1), 10),
 	}
-	v.ss["class_"+s] = struct{}{}
 }
 
-func (v *contextValue) hasClassBeenRendered(s string) (ok bool) {
-	if v.ss == nil {
-		v.ss = map[string]struct{}{}
+// cssClassOnceHandle returns the handle that renders the CSS of the class with the ID. It's
+// keyed by the ID, so each class is rendered once, whichever component uses it.
+func cssClassOnceHandle(id string) *OnceHandle {
+	return &OnceHandle{id: "class_" + id}
+}
+
+// scriptOnceHandle returns the handle that renders the function of the script with the name.
+func scriptOnceHandle(name string) *OnceHandle {
+	return &OnceHandle{id: "script_" + name}
+}
+
+// Once returns a component that renders its children, if the handle's children haven't
+// already been rendered within the render tree.
+func (o *OnceHandle) Once() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		ctx, _ = getContext(ctx)
+		children := GetChildren(ctx)
+		ctx = ClearChildren(ctx)
+		return o.render(ctx, w, children)
+	})
+}
+
+// render renders c, if the handle's content hasn't already been rendered within the render tree.
+func (o *OnceHandle) render(ctx context.Context, w io.Writer, c Component) (err error) {
+	ctx, v := getContext(ctx)
+	if v.hasBeenRendered(o.id) {
+		return nil
 	}
-	_, ok = v.ss["class_"+s]
-	return
+	o.skip(v)
+	return c.Render(ctx, w)
+}
+
+// skip records the handle's content as rendered within the render tree, so that it isn't
+// rendered, e.g. because it's been included in a global stylesheet.
+func (o *OnceHandle) skip(v *contextValue) {
+	v.setHasBeenRendered(o.id)
 }
 
 // InitializeContext initializes context used to store internal state used during rendering.
@@ -526,19 +1216,19 @@ func RenderScriptItems(ctx context.Context, w io.Writer, scripts ...ComponentScr
 	if len(scripts) == 0 {
 		return nil
 	}
-	_, v := getContext(ctx)
-	sb := new(strings.Builder)
+	ctx, _ = getContext(ctx)
+	sb := GetBuffer()
+	defer ReleaseBuffer(sb)
 	for _, s := range scripts {
-		if !v.hasScriptBeenRendered(s.Name) {
-			sb.WriteString(s.Function)
-			v.addScript(s.Name)
+		if err = scriptOnceHandle(s.Name).render(ctx, sb, Raw(s.Function)); err != nil {
+			return err
 		}
 	}
 	if sb.Len() > 0 {
-		if _, err = io.WriteString(w, `<script type="text/javascript">`); err != nil {
+		if _, err = io.WriteString(w, `<script type="text/javascript"`+nonceAttribute(ctx)+`>`); err != nil {
 			return err
 		}
-		if _, err = io.WriteString(w, sb.String()); err != nil {
+		if _, err = sb.WriteTo(w); err != nil {
 			return err
 		}
 		if _, err = io.WriteString(w, `</script>`); err != nil {
@@ -554,11 +1244,22 @@ var bufferPool = sync.Pool{
 	},
 }
 
+// GetBuffer returns a buffer from a pool, for rendering to. Call ReleaseBuffer when it's
+// no longer in use, including when rendering fails.
 func GetBuffer() *bytes.Buffer {
 	return bufferPool.Get().(*bytes.Buffer)
 }
 
+// maxPooledBufferSize is the capacity above which buffers aren't returned to the pool, so
+// that rendering one large page doesn't keep a large buffer in the pool for every render.
+const maxPooledBufferSize = 64 * 1024
+
+// ReleaseBuffer resets the buffer, and returns it to the pool, unless it has grown larger
+// than 64KiB. The buffer must not be used afterwards.
 func ReleaseBuffer(b *bytes.Buffer) {
 	b.Reset()
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
 	bufferPool.Put(b)
 }
@@ -3,10 +3,14 @@ package templ_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ"
@@ -192,6 +196,148 @@ func TestRenderCSS(t *testing.T) {
 	}
 }
 
+func TestNonce(t *testing.T) {
+	class := templ.ComponentCSSClass{
+		ID:    "c1",
+		Class: ".c1{color:red}",
+	}
+	script := templ.ComponentScript{
+		Name:     "s1",
+		Function: "function s1(){}",
+	}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "without a nonce, elements don't have a nonce attribute",
+			ctx:      context.Background(),
+			expected: `<style type="text/css">.c1{color:red}</style><script type="text/javascript">function s1(){}</script>`,
+		},
+		{
+			name:     "the nonce is added to style and script elements",
+			ctx:      templ.WithNonce(context.Background(), "abc123"),
+			expected: `<style type="text/css" nonce="abc123">.c1{color:red}</style><script type="text/javascript" nonce="abc123">function s1(){}</script>`,
+		},
+		{
+			name:     "the nonce is escaped",
+			ctx:      templ.WithNonce(context.Background(), `"><script>alert(1)</script>`),
+			expected: `<style type="text/css" nonce="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">.c1{color:red}</style><script type="text/javascript" nonce="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">function s1(){}</script>`,
+		},
+		{
+			name:     "the nonce is kept when the context is initialized",
+			ctx:      templ.InitializeContext(templ.WithNonce(context.Background(), "abc123")),
+			expected: `<style type="text/css" nonce="abc123">.c1{color:red}</style><script type="text/javascript" nonce="abc123">function s1(){}</script>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			if err := templ.RenderCSSItems(tt.ctx, b, class); err != nil {
+				t.Fatalf("failed to render CSS: %v", err)
+			}
+			if err := templ.RenderScriptItems(tt.ctx, b, script); err != nil {
+				t.Fatalf("failed to render script: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, b.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("GetNonce returns an empty string if there's no nonce", func(t *testing.T) {
+		if nonce := templ.GetNonce(context.Background()); nonce != "" {
+			t.Errorf("expected no nonce, got %q", nonce)
+		}
+	})
+	t.Run("GetNonce returns the nonce", func(t *testing.T) {
+		if nonce := templ.GetNonce(templ.WithNonce(context.Background(), "abc123")); nonce != "abc123" {
+			t.Errorf("expected abc123, got %q", nonce)
+		}
+	})
+}
+
+type testContextKey int
+
+const (
+	userContextKey testContextKey = iota
+	localeContextKey
+)
+
+func TestValue(t *testing.T) {
+	ctx := templ.WithValue(context.Background(), userContextKey, "Alice")
+	t.Run("the value is returned", func(t *testing.T) {
+		if user := templ.Value[string](ctx, userContextKey); user != "Alice" {
+			t.Errorf("expected Alice, got %q", user)
+		}
+	})
+	t.Run("the zero value is returned if the value isn't set", func(t *testing.T) {
+		if locale := templ.Value[string](ctx, localeContextKey); locale != "" {
+			t.Errorf("expected no locale, got %q", locale)
+		}
+	})
+	t.Run("the zero value is returned if the value is of a different type", func(t *testing.T) {
+		if user := templ.Value[int](ctx, userContextKey); user != 0 {
+			t.Errorf("expected 0, got %d", user)
+		}
+	})
+	t.Run("values are kept when the context is initialized for rendering", func(t *testing.T) {
+		if user := templ.Value[string](templ.InitializeContext(ctx), userContextKey); user != "Alice" {
+			t.Errorf("expected Alice, got %q", user)
+		}
+	})
+}
+
+func TestTraceRender(t *testing.T) {
+	t.Run("nil is returned if there isn't a tracer", func(t *testing.T) {
+		if end := templ.TraceRender(context.Background(), "main.page"); end != nil {
+			t.Error("expected no end function")
+		}
+	})
+	t.Run("components don't allocate if there isn't a tracer", func(t *testing.T) {
+		ctx := templ.InitializeContext(context.Background())
+		allocs := testing.AllocsPerRun(100, func() {
+			if end := templ.TraceRender(ctx, "main.page"); end != nil {
+				t.Fatal("expected no end function")
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("expected no allocations, got %v", allocs)
+		}
+	})
+	t.Run("the tracer is called with the name, and its end function is returned", func(t *testing.T) {
+		var events []string
+		ctx := templ.WithTracing(context.Background(), func(name string) func(err error) {
+			events = append(events, "start "+name)
+			return func(err error) {
+				events = append(events, fmt.Sprintf("end %s: %v", name, err))
+			}
+		})
+		end := templ.TraceRender(templ.InitializeContext(ctx), "main.page")
+		if end == nil {
+			t.Fatal("expected an end function")
+		}
+		end(errors.New("failed"))
+		if diff := cmp.Diff([]string{"start main.page", "end main.page: failed"}, events); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the tracer may not return an end function", func(t *testing.T) {
+		var names []string
+		ctx := templ.WithTracing(context.Background(), func(name string) func(err error) {
+			names = append(names, name)
+			return nil
+		})
+		if end := templ.TraceRender(ctx, "main.page"); end != nil {
+			t.Error("expected no end function")
+		}
+		if diff := cmp.Diff([]string{"main.page"}, names); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
 func TestClassSanitization(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -347,6 +493,37 @@ func TestClassesFunction(t *testing.T) {
 			},
 			expected: "a b d",
 		},
+		{
+			name: "empty class names and extra whitespace are ignored",
+			input: []any{
+				"",
+				"  a   b ",
+				[]string{"", "c"},
+				templ.KV("", true),
+				map[string]bool{"": true},
+			},
+			expected: "a b c",
+		},
+		{
+			name: "repeated class names are rendered once, where they're first added, using the last condition",
+			input: []any{
+				templ.KV("a", false),
+				"b",
+				"a",
+				templ.KV("b", false),
+				templ.KV("b", true),
+				templ.KV("a", false),
+				templ.KV("a", true),
+			},
+			expected: "a b",
+		},
+		{
+			name: "unsafe class names don't enable the names that follow them",
+			input: []any{
+				templ.KV("</style> a", false),
+			},
+			expected: "--templ-css-class-safe-name",
+		},
 		{
 			name: "the brackets on component CSS function calls can be elided",
 			input: []any{
@@ -370,6 +547,538 @@ func TestClassesFunction(t *testing.T) {
 	}
 }
 
+func TestStylesFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []any
+		expected string
+	}{
+		{
+			name:     "no styles render nothing",
+			input:    nil,
+			expected: "",
+		},
+		{
+			name:     "string declarations are sanitized",
+			input:    []any{"color: red; background-image: url(javascript:alert(1))"},
+			expected: "color:red;background-image:zTemplUnsafeCSSPropertyValue;",
+		},
+		{
+			name:     "strings that aren't declarations are replaced",
+			input:    []any{"</style>"},
+			expected: "zTemplUnsafeCSSPropertyName:zTemplUnsafeCSSPropertyValue;",
+		},
+		{
+			name:     "safe CSS isn't sanitized",
+			input:    []any{templ.SafeCSS("background-image: url(a.png)"), templ.SafeCSS("")},
+			expected: "background-image: url(a.png);",
+		},
+		{
+			name:     "maps are rendered in the order of their keys",
+			input:    []any{map[string]string{"width": "10px", "color": "red", "height": "5px"}},
+			expected: "color:red;height:5px;width:10px;",
+		},
+		{
+			name:     "property values are sanitized",
+			input:    []any{map[string]string{"color": `red" onclick="alert(1)`}, templ.KV("width", "expression(alert(1))")},
+			expected: "color:zTemplUnsafeCSSPropertyValue;width:zTemplUnsafeCSSPropertyValue;",
+		},
+		{
+			name:     "unsafe property names are replaced",
+			input:    []any{templ.KV("color;}", "red")},
+			expected: "zTemplUnsafeCSSPropertyName:zTemplUnsafeCSSPropertyValue;",
+		},
+		{
+			name:     "property names are lower case",
+			input:    []any{templ.KV("Color", "red"), templ.KV("COLOR", "blue")},
+			expected: "color:blue;",
+		},
+		{
+			name: "kv types set properties",
+			input: []any{
+				templ.KV("color", "red"),
+				[]templ.KeyValue[string, string]{
+					templ.KV("width", "10px"),
+					{"height", "5px"},
+				},
+			},
+			expected: "color:red;width:10px;height:5px;",
+		},
+		{
+			name: "repeated properties are rendered once, where they're first added, using the last value",
+			input: []any{
+				"color: red; width: 10px",
+				templ.KV("height", "5px"),
+				map[string]string{"color": "blue"},
+				templ.KV("width", "20px"),
+			},
+			expected: "color:blue;width:20px;height:5px;",
+		},
+		{
+			name: "empty values are omitted, and remove previous values",
+			input: []any{
+				templ.KV("color", "red"),
+				templ.KV("width", ""),
+				"height: ;",
+				map[string]string{"color": " "},
+				templ.KV("", "red"),
+			},
+			expected: "",
+		},
+		{
+			name: "nested CSSStyles are extracted",
+			input: []any{
+				templ.Styles(templ.KV("color", "red"), templ.Styles("width: 10px")),
+			},
+			expected: "color:red;width:10px;",
+		},
+		{
+			name:     "unknown types are replaced",
+			input:    []any{123, map[string]bool{"color": true}},
+			expected: "zTemplUnsafeCSSPropertyName:zTemplUnsafeCSSPropertyValue;zTemplUnsafeCSSPropertyName:zTemplUnsafeCSSPropertyValue;",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, templ.Styles(tt.input...).String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRenderAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    templ.Attributes
+		expected string
+	}{
+		{
+			name:     "nil attributes render nothing",
+			input:    nil,
+			expected: "",
+		},
+		{
+			name:     "empty attributes render nothing",
+			input:    templ.Attributes{},
+			expected: "",
+		},
+		{
+			name: "attributes are sorted by name",
+			input: templ.Attributes{
+				"id":    "a",
+				"class": "b",
+			},
+			expected: ` class="b" id="a"`,
+		},
+		{
+			name: "string values are escaped",
+			input: templ.Attributes{
+				"title": `"quoted" & <tagged>`,
+			},
+			expected: ` title="&#34;quoted&#34; &amp; &lt;tagged&gt;"`,
+		},
+		{
+			name: "boolean attributes are only rendered when true",
+			input: templ.Attributes{
+				"checked":  true,
+				"disabled": false,
+			},
+			expected: ` checked`,
+		},
+		{
+			name: "string URL attributes are sanitized",
+			input: templ.Attributes{
+				"href": "javascript:alert(1)",
+			},
+			expected: ` href="about:invalid#TemplFailedSanitizationURL"`,
+		},
+		{
+			name: "safe URLs are not sanitized",
+			input: templ.Attributes{
+				"href": templ.SafeURL("javascript:alert(1)"),
+			},
+			expected: ` href="javascript:alert(1)"`,
+		},
+		{
+			name: "unsupported types are not rendered",
+			input: templ.Attributes{
+				"data-count": 1,
+			},
+			expected: "",
+		},
+		{
+			name: "URL attribute names are not case sensitive",
+			input: templ.Attributes{
+				"HREF": "javascript:alert(1)",
+			},
+			expected: ` HREF="about:invalid#TemplFailedSanitizationURL"`,
+		},
+		{
+			name: "string formaction, poster and cite attributes are sanitized",
+			input: templ.Attributes{
+				"formaction": "javascript:alert(1)",
+				"poster":     "data:image/svg+xml,<svg onload=alert(1)>",
+				"cite":       "vbscript:msgbox(1)",
+			},
+			expected: ` cite="about:invalid#TemplFailedSanitizationURL" formaction="about:invalid#TemplFailedSanitizationURL" poster="about:invalid#TemplFailedSanitizationURL"`,
+		},
+		{
+			name: "string event handler attributes are not rendered",
+			input: templ.Attributes{
+				"onclick":      "alert(1)",
+				"ONMOUSEOVER":  "alert(1)",
+				"hx-on:click":  "alert(1)",
+				"data-onclick": "alert(1)",
+			},
+			expected: ` data-onclick="alert(1)"`,
+		},
+		{
+			name: "event handler attributes are not rendered whatever the type of the value",
+			input: templ.Attributes{
+				"onclick":     templ.SafeURL("javascript:alert(1)"),
+				"onmouseover": true,
+			},
+			expected: "",
+		},
+		{
+			name: "invalid attribute names are not rendered",
+			input: templ.Attributes{
+				"x onmouseover=alert(1)": "a",
+				"x\tonclick":             templ.SafeURL("javascript:alert(1)"),
+				"y/onload":               true,
+				`z"`:                     "a",
+				"a'":                     "a",
+				"b>":                     "a",
+				"c\x00":                  "a",
+				"":                       "a",
+				"id":                     "valid",
+			},
+			expected: ` id="valid"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if err := templ.RenderAttributes(context.Background(), w, tt.input); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected templ.SafeURL
+	}{
+		{input: "http://example.com", expected: "http://example.com"},
+		{input: "HTTPS://example.com/path?q=1#top", expected: "HTTPS://example.com/path?q=1#top"},
+		{input: "mailto:user@example.com", expected: "mailto:user@example.com"},
+		{input: "", expected: ""},
+		{input: "/path/to/page", expected: "/path/to/page"},
+		{input: "//example.com/path", expected: "//example.com/path"},
+		{input: "relative/path:with-colon", expected: "relative/path:with-colon"},
+		{input: "/search?q=a:b", expected: "/search?q=a:b"},
+		{input: "?q=a:b", expected: "?q=a:b"},
+		{input: "#section:2", expected: "#section:2"},
+		{input: "javascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "JaVaScRiPt:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: " javascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "\x01javascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "java\tscript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "java\nscript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "javascript&colon;alert(1):", expected: templ.FailedSanitizationURL},
+		{input: "data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==", expected: templ.FailedSanitizationURL},
+		{input: "DATA:text/html,<script>alert(1)</script>", expected: templ.FailedSanitizationURL},
+		{input: "vbscript:msgbox(1)", expected: templ.FailedSanitizationURL},
+		{input: "file:///etc/passwd", expected: templ.FailedSanitizationURL},
+		{input: "http:", expected: "http:"},
+		{input: "tel:+44-1234-567890", expected: "tel:+44-1234-567890"},
+		{input: "TEL:123", expected: "TEL:123"},
+		{input: "data:image/png;base64,iVBORw0KGgo=", expected: "data:image/png;base64,iVBORw0KGgo="},
+		{input: "DATA:IMAGE/GIF;base64,R0lGODlh", expected: "DATA:IMAGE/GIF;base64,R0lGODlh"},
+		{input: "data:image/svg+xml,<svg onload=alert(1)>", expected: templ.FailedSanitizationURL},
+		{input: "data:text/javascript,alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "data:,image/png", expected: templ.FailedSanitizationURL},
+		{input: "jAvAsCrIpT:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "VBSCRIPT:msgbox(1)", expected: templ.FailedSanitizationURL},
+		{input: "\x00javascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "\x1fjavascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "\t\n javascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "javascript\t:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "j\ra\nv\ta\tscript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "java\x00script:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: "\u00a0javascript:alert(1)", expected: templ.FailedSanitizationURL},
+		{input: " https://example.com ", expected: " https://example.com "},
+		{input: "\thttp://example.com", expected: "\thttp://example.com"},
+		{input: "ht\ntp://example.com", expected: "ht\ntp://example.com"},
+		{input: "//example.com:8080/path", expected: "//example.com:8080/path"},
+		{input: "/\\example.com", expected: "/\\example.com"},
+	}
+	for _, tt := range tests {
+		if actual := templ.URL(tt.input); actual != tt.expected {
+			t.Errorf("URL(%q): expected %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+// flushCounter counts the number of times it's flushed, and the body that had been written
+// when it was first flushed.
+type flushCounter struct {
+	*httptest.ResponseRecorder
+	flushes      int
+	firstFlushed string
+}
+
+func (fc *flushCounter) Flush() {
+	if fc.flushes == 0 {
+		fc.firstFlushed = fc.Body.String()
+	}
+	fc.flushes++
+	fc.ResponseRecorder.Flush()
+}
+
+func TestFlush(t *testing.T) {
+	t.Run("flushing a writer that implements http.Flusher flushes it", func(t *testing.T) {
+		w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+		if err := templ.Flush().Render(context.Background(), w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.flushes != 1 {
+			t.Errorf("expected 1 flush, got %d", w.flushes)
+		}
+	})
+	t.Run("the buffer is written to the flush target before it's flushed", func(t *testing.T) {
+		w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+		b := new(bytes.Buffer)
+		ctx := templ.WithFlushTarget(context.Background(), b, w)
+		b.WriteString("<head></head>")
+		if err := templ.Flush().Render(ctx, b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.flushes != 1 {
+			t.Errorf("expected 1 flush, got %d", w.flushes)
+		}
+		if w.firstFlushed != "<head></head>" {
+			t.Errorf("expected the buffer to be written before the flush, got %q", w.firstFlushed)
+		}
+		if b.Len() != 0 {
+			t.Errorf("expected the buffer to be empty, got %q", b.String())
+		}
+	})
+	t.Run("other buffers are not written to the flush target", func(t *testing.T) {
+		w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+		ctx := templ.WithFlushTarget(context.Background(), new(bytes.Buffer), w)
+		b := new(bytes.Buffer)
+		b.WriteString("<div>")
+		if err := templ.Flush().Render(ctx, b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.flushes != 0 {
+			t.Errorf("expected no flushes, got %d", w.flushes)
+		}
+		if b.String() != "<div>" {
+			t.Errorf("expected the buffer to be unchanged, got %q", b.String())
+		}
+	})
+	t.Run("writers that don't implement http.Flusher are not written to early", func(t *testing.T) {
+		w := new(strings.Builder)
+		b := new(bytes.Buffer)
+		ctx := templ.WithFlushTarget(context.Background(), b, w)
+		b.WriteString("<div>")
+		if err := templ.Flush().Render(ctx, b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.Len() != 0 {
+			t.Errorf("expected nothing to be written, got %q", w.String())
+		}
+		if b.String() != "<div>" {
+			t.Errorf("expected the buffer to be unchanged, got %q", b.String())
+		}
+	})
+}
+
+func TestChildren(t *testing.T) {
+	child := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "child")
+		return err
+	})
+	render := func(t *testing.T, c templ.Component, ctx context.Context) string {
+		t.Helper()
+		w := new(bytes.Buffer)
+		if err := c.Render(ctx, w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return w.String()
+	}
+	t.Run("components without children render nothing", func(t *testing.T) {
+		ctx := templ.InitializeContext(context.Background())
+		if actual := render(t, templ.GetChildren(ctx), ctx); actual != "" {
+			t.Errorf("expected no output, got %q", actual)
+		}
+	})
+	t.Run("children can be retrieved from the context", func(t *testing.T) {
+		ctx := templ.WithChildren(templ.InitializeContext(context.Background()), child)
+		if actual := render(t, templ.GetChildren(ctx), ctx); actual != "child" {
+			t.Errorf("expected %q, got %q", "child", actual)
+		}
+	})
+	t.Run("cleared children render nothing", func(t *testing.T) {
+		ctx := templ.WithChildren(templ.InitializeContext(context.Background()), child)
+		ctx = templ.ClearChildren(ctx)
+		if actual := render(t, templ.GetChildren(ctx), ctx); actual != "" {
+			t.Errorf("expected no output, got %q", actual)
+		}
+	})
+}
+
+func TestStreamingHandler(t *testing.T) {
+	t.Run("output is written to the response when it's flushed", func(t *testing.T) {
+		w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+		var flushedContentType string
+		component := templ.ComponentFunc(func(ctx context.Context, cw io.Writer) (err error) {
+			if _, err = io.WriteString(cw, "<head></head>"); err != nil {
+				return err
+			}
+			if err = templ.Flush().Render(ctx, cw); err != nil {
+				return err
+			}
+			flushedContentType = w.Header().Get("Content-Type")
+			_, err = io.WriteString(cw, "<body></body>")
+			return err
+		})
+		templ.Handler(component, templ.WithStreaming()).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if w.flushes != 1 {
+			t.Errorf("expected 1 flush, got %d", w.flushes)
+		}
+		if w.firstFlushed != "<head></head>" {
+			t.Errorf("expected the head to be flushed, got %q", w.firstFlushed)
+		}
+		if flushedContentType != "text/html; charset=utf-8" {
+			t.Errorf("expected the headers to be written before the flush, got content type %q", flushedContentType)
+		}
+		if w.Body.String() != "<head></head><body></body>" {
+			t.Errorf("unexpected body %q", w.Body.String())
+		}
+	})
+	t.Run("the connection is closed if an error is returned after output is flushed", func(t *testing.T) {
+		component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			if _, err = io.WriteString(w, "<head></head>"); err != nil {
+				return err
+			}
+			if err = templ.Flush().Render(ctx, w); err != nil {
+				return err
+			}
+			return errors.New("handler error")
+		})
+		s := httptest.NewServer(templ.Handler(component, templ.WithStreaming()))
+		defer s.Close()
+		resp, err := http.Get(s.URL)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected the status code sent before the error, got %d", resp.StatusCode)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err == nil {
+			t.Errorf("expected an error reading the truncated body, got body %q", body)
+		}
+	})
+}
+
+func TestJoin(t *testing.T) {
+	text := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+	errFailed := errors.New("failed")
+	failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return errFailed
+	})
+	tests := []struct {
+		name          string
+		input         []templ.Component
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "no components render nothing",
+			input:    nil,
+			expected: "",
+		},
+		{
+			name:     "components are rendered in order",
+			input:    []templ.Component{text("<a>"), text("<b>"), text("<c>")},
+			expected: "<a><b><c>",
+		},
+		{
+			name:     "nil components are skipped",
+			input:    []templ.Component{text("<a>"), nil, templ.NopComponent, text("<b>")},
+			expected: "<a><b>",
+		},
+		{
+			name:          "components after an error are not rendered",
+			input:         []templ.Component{text("<a>"), failing, text("<b>")},
+			expected:      "<a>",
+			expectedError: errFailed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			err := templ.Join(tt.input...).Render(context.Background(), w)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSafeScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   []any
+		expected string
+	}{
+		{
+			name:     "no parameters",
+			expected: `fn()`,
+		},
+		{
+			name:     "parameters are JSON encoded and escaped",
+			params:   []any{"a", 1, true},
+			expected: `fn(&#34;a&#34;,1,true)`,
+		},
+		{
+			name:     "strings can't close the script element",
+			params:   []any{`</script>`},
+			expected: `fn(&#34;\u003c/script\u003e&#34;)`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := templ.SafeScript("fn", tt.params...); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
 func TestHandler(t *testing.T) {
 	hello := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
 		if _, err := io.WriteString(w, "Hello"); err != nil {
@@ -380,24 +1089,40 @@ func TestHandler(t *testing.T) {
 	errorComponent := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
 		return errors.New("handler error")
 	})
+	partialErrorComponent := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, "<html><body>"); err != nil {
+			t.Fatalf("failed to write string: %v", err)
+		}
+		return errors.New("handler error")
+	})
 
 	tests := []struct {
-		name           string
-		input          *templ.ComponentHandler
-		expectedStatus int
-		expectedBody   string
+		name                string
+		input               *templ.ComponentHandler
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
 	}{
 		{
-			name:           "handlers return OK by default",
-			input:          templ.Handler(hello),
-			expectedStatus: http.StatusOK,
-			expectedBody:   "Hello",
+			name:                "handlers return OK by default",
+			input:               templ.Handler(hello),
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "Hello",
+		},
+		{
+			name:                "handlers can be configured to return an alternative content type",
+			input:               templ.Handler(hello, templ.WithContentType("text/plain")),
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain",
+			expectedBody:        "Hello",
 		},
 		{
-			name:           "handlers can be configured to return an alternative status code",
-			input:          templ.Handler(hello, templ.WithStatus(http.StatusNotFound)),
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   "Hello",
+			name:                "handlers can be configured to return an alternative status code",
+			input:               templ.Handler(hello, templ.WithStatus(http.StatusNotFound)),
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "Hello",
 		},
 		{
 			name:           "handlers that fail return a 500 error",
@@ -405,6 +1130,25 @@ func TestHandler(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "templ: failed to render template\n",
 		},
+		{
+			name:           "the output of handlers that fail is not written",
+			input:          templ.Handler(partialErrorComponent),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "templ: failed to render template\n",
+		},
+		{
+			name:                "streaming handlers write the output",
+			input:               templ.Handler(hello, templ.WithStreaming(), templ.WithStatus(http.StatusNotFound)),
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "Hello",
+		},
+		{
+			name:           "streaming handlers that fail before writing output return a 500 error",
+			input:          templ.Handler(errorComponent, templ.WithStreaming()),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "templ: failed to render template\n",
+		},
 		{
 			name: "error handling can be customised",
 			input: templ.Handler(errorComponent, templ.WithErrorHandler(func(r *http.Request, err error) http.Handler {
@@ -430,6 +1174,9 @@ func TestHandler(t *testing.T) {
 			if got := w.Result().StatusCode; tt.expectedStatus != got {
 				t.Errorf("expected status %d, got %d", tt.expectedStatus, got)
 			}
+			if got := w.Result().Header.Get("Content-Type"); tt.expectedContentType != "" && tt.expectedContentType != got {
+				t.Errorf("expected content type %q, got %q", tt.expectedContentType, got)
+			}
 			body, err := io.ReadAll(w.Result().Body)
 			if err != nil {
 				t.Errorf("failed to read body: %v", err)
@@ -440,3 +1187,459 @@ func TestHandler(t *testing.T) {
 		})
 	}
 }
+
+type celsius float64
+
+func (c celsius) String() string { return strconv.FormatFloat(float64(c), 'f', 1, 64) + "°C" }
+
+type user struct{ name string }
+
+func (u *user) String() string { return u.name }
+
+type count int
+
+func TestEscapeAny(t *testing.T) {
+	var nilUser *user
+	tests := []struct {
+		name     string
+		render   func() (string, error)
+		expected string
+	}{
+		{
+			name:     "strings are escaped",
+			render:   func() (string, error) { return templ.EscapeAny(`<a href="/">`) },
+			expected: `&lt;a href=&#34;/&#34;&gt;`,
+		},
+		{
+			name:     "single and double quotes are escaped, so that values can't end an attribute",
+			render:   func() (string, error) { return templ.EscapeAny(`" onmouseover='alert(1)'`) },
+			expected: `&#34; onmouseover=&#39;alert(1)&#39;`,
+		},
+		{
+			name:     "ints",
+			render:   func() (string, error) { return templ.EscapeAny(-42) },
+			expected: "-42",
+		},
+		{
+			name:     "unsigned ints",
+			render:   func() (string, error) { return templ.EscapeAny(uint8(255)) },
+			expected: "255",
+		},
+		{
+			name:     "floats use the shortest representation",
+			render:   func() (string, error) { return templ.EscapeAny(0.1) },
+			expected: "0.1",
+		},
+		{
+			name:     "float32 values are not widened",
+			render:   func() (string, error) { return templ.EscapeAny(float32(0.1)) },
+			expected: "0.1",
+		},
+		{
+			name:     "bools",
+			render:   func() (string, error) { return templ.EscapeAny(true) },
+			expected: "true",
+		},
+		{
+			name:     "types defined in terms of basic types",
+			render:   func() (string, error) { return templ.EscapeAny(count(3)) },
+			expected: "3",
+		},
+		{
+			name:     "stringers take precedence over the underlying type",
+			render:   func() (string, error) { return templ.EscapeAny(celsius(21.5)) },
+			expected: "21.5°C",
+		},
+		{
+			name:     "the output of stringers is escaped",
+			render:   func() (string, error) { return templ.EscapeAny(&user{name: "<b>"}) },
+			expected: "&lt;b&gt;",
+		},
+		{
+			name:     "nil pointer stringers are rendered as an empty string",
+			render:   func() (string, error) { return templ.EscapeAny(nilUser) },
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.render()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+	t.Run("unsupported types return an error", func(t *testing.T) {
+		_, err := templ.EscapeAny([]string{"a"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expected := "templ: cannot render a value of type []string, expected a string, bool, number or fmt.Stringer"
+		if err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	})
+	t.Run("strings are converted without allocating", func(t *testing.T) {
+		s := "no characters to escape"
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := templ.EscapeAny(s); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("expected no allocations, got %v", allocs)
+		}
+	})
+}
+
+func TestWrapError(t *testing.T) {
+	errFailed := errors.New("failed")
+	t.Run("the error is prefixed with its position", func(t *testing.T) {
+		err := templ.WrapError(errFailed, "views/home.templ", 12, 3)
+		if diff := cmp.Diff("views/home.templ:12:3: failed", err.Error()); diff != "" {
+			t.Error(diff)
+		}
+		err = templ.WrapError(errFailed, "", 12, 3)
+		if diff := cmp.Diff("12:3: failed", err.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the wrapped error can be checked", func(t *testing.T) {
+		err := templ.WrapError(errFailed, "views/home.templ", 12, 3)
+		if !errors.Is(err, errFailed) {
+			t.Error("expected errors.Is to find the wrapped error")
+		}
+		if errors.Unwrap(err) != errFailed {
+			t.Error("expected errors.Unwrap to return the wrapped error")
+		}
+		var e *templ.Error
+		if !errors.As(err, &e) {
+			t.Fatal("expected errors.As to find the templ.Error")
+		}
+		if e.FileName != "views/home.templ" || e.Line != 12 || e.Col != 3 {
+			t.Errorf("unexpected position %s:%d:%d", e.FileName, e.Line, e.Col)
+		}
+	})
+	t.Run("errors that have a position are not wrapped again", func(t *testing.T) {
+		inner := templ.WrapError(errFailed, "views/button.templ", 4, 5)
+		outer := templ.WrapError(fmt.Errorf("rendering button: %w", inner), "views/home.templ", 12, 3)
+		if diff := cmp.Diff("rendering button: views/button.templ:4:5: failed", outer.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestRaw(t *testing.T) {
+	for _, html := range []string{
+		"",
+		`<p class="a">Hello &amp; <b>world</b></p>`,
+		"<script>alert('&<>\"')</script>",
+		"invalid UTF-8: \xff\xfe",
+	} {
+		w := new(bytes.Buffer)
+		if err := templ.Raw(html).Render(context.Background(), w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal([]byte(html), w.Bytes()) {
+			t.Errorf("expected the output to be identical to the input %q, got %q", html, w.String())
+		}
+	}
+}
+
+func TestJSONScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		input    any
+		expected string
+	}{
+		{
+			name:     "values are encoded as JSON",
+			id:       "data",
+			input:    map[string]any{"name": "Alice", "count": 2},
+			expected: `<script type="application/json" id="data">{"count":2,"name":"Alice"}</script>`,
+		},
+		{
+			name:     "nil is encoded as null",
+			id:       "data",
+			input:    nil,
+			expected: `<script type="application/json" id="data">null</script>`,
+		},
+		{
+			name:     "closing script tags in strings are escaped",
+			id:       "data",
+			input:    "</script><script>alert(1)</script>",
+			expected: `<script type="application/json" id="data">"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"</script>`,
+		},
+		{
+			name:     "closing script tags in map keys are escaped",
+			id:       "data",
+			input:    map[string]string{"</SCRIPT>": "&amp;"},
+			expected: `<script type="application/json" id="data">{"\u003c/SCRIPT\u003e":"\u0026amp;"}</script>`,
+		},
+		{
+			name:     "HTML comments are escaped",
+			id:       "data",
+			input:    []string{"<!--", "<script>", "-->"},
+			expected: `<script type="application/json" id="data">["\u003c!--","\u003cscript\u003e","--\u003e"]</script>`,
+		},
+		{
+			name:     "JavaScript line terminators are escaped",
+			id:       "data",
+			input:    "a\u2028b\u2029c",
+			expected: `<script type="application/json" id="data">"a\u2028b\u2029c"</script>`,
+		},
+		{
+			name:     "the id is escaped",
+			id:       `data" onload="alert(1)`,
+			input:    1,
+			expected: `<script type="application/json" id="data&#34; onload=&#34;alert(1)">1</script>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := templ.JSONScript(tt.id, tt.input).Render(context.Background(), w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("the data can be decoded", func(t *testing.T) {
+		input := map[string]string{"a": "</script><!-- & -->", "b": "\u2028"}
+		w := new(strings.Builder)
+		if err := templ.JSONScript("data", input).Render(context.Background(), w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		contents := strings.TrimSuffix(strings.TrimPrefix(w.String(), `<script type="application/json" id="data">`), `</script>`)
+		var actual map[string]string
+		if err := json.Unmarshal([]byte(contents), &actual); err != nil {
+			t.Fatalf("failed to decode %q: %v", contents, err)
+		}
+		if diff := cmp.Diff(input, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("encoding errors are returned", func(t *testing.T) {
+		w := new(strings.Builder)
+		err := templ.JSONScript("data", make(chan int)).Render(context.Background(), w)
+		var jsonErr *json.UnsupportedTypeError
+		if !errors.As(err, &jsonErr) {
+			t.Fatalf("expected a *json.UnsupportedTypeError, got %v", err)
+		}
+		if w.Len() != 0 {
+			t.Errorf("expected nothing to be written, got %q", w.String())
+		}
+	})
+}
+
+func TestOnceHandle(t *testing.T) {
+	text := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+	// once renders c as the children of the handle's Once component, as @h.Once() { ... } does.
+	once := func(h *templ.OnceHandle, c templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return h.Once().Render(templ.WithChildren(ctx, c), w)
+		})
+	}
+	// render renders the component with a new context, as the outermost generated component does.
+	render := func(c templ.Component, w io.Writer) error {
+		return c.Render(templ.InitializeContext(context.Background()), w)
+	}
+	// children renders the children passed to it after s.
+	children := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			c := templ.GetChildren(ctx)
+			ctx = templ.ClearChildren(ctx)
+			if _, err := io.WriteString(w, s); err != nil {
+				return err
+			}
+			return c.Render(ctx, w)
+		})
+	}
+	t.Run("children are rendered the first time", func(t *testing.T) {
+		h := templ.NewOnceHandle()
+		w := new(strings.Builder)
+		err := render(templ.Join(once(h, text("<a>")), once(h, text("<a>")), text("<b>")), w)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<a><b>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("handles are independent", func(t *testing.T) {
+		h1, h2 := templ.NewOnceHandle(), templ.NewOnceHandle()
+		w := new(strings.Builder)
+		err := render(templ.Join(once(h1, text("<a>")), once(h2, text("<b>")), once(h1, text("<a>")), once(h2, text("<b>"))), w)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<a><b>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("each top level render is independent", func(t *testing.T) {
+		h := templ.NewOnceHandle()
+		c := templ.Join(once(h, text("<a>")), once(h, text("<a>")))
+		for i := 0; i < 2; i++ {
+			w := new(strings.Builder)
+			if err := render(c, w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff("<a>", w.String()); diff != "" {
+				t.Errorf("render %d: %s", i, diff)
+			}
+		}
+	})
+	t.Run("handles are tracked in nested components", func(t *testing.T) {
+		h := templ.NewOnceHandle()
+		nested := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			ctx = templ.WithChildren(ctx, templ.Join(once(h, text("<a>")), text("<c>")))
+			return children("<b>").Render(ctx, w)
+		})
+		w := new(strings.Builder)
+		if err := render(templ.Join(nested, once(h, text("<a>")), nested), w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<b><a><c><b><c>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("skipped children are not passed to the next component", func(t *testing.T) {
+		h := templ.NewOnceHandle()
+		ctx := templ.InitializeContext(context.Background())
+		w := new(strings.Builder)
+		for i := 0; i < 2; i++ {
+			if err := h.Once().Render(templ.WithChildren(ctx, text("<a>")), w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := children("<b>").Render(ctx, w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if diff := cmp.Diff("<a><b><b>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("CSS classes and scripts are rendered once per render tree", func(t *testing.T) {
+		class := templ.ComponentCSSClass{ID: "name", Class: templ.SafeCSS(".name{color:red;}")}
+		script := templ.ComponentScript{Name: "name", Function: "function name(){}"}
+		items := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if err := templ.RenderCSSItems(ctx, w, class); err != nil {
+				return err
+			}
+			return templ.RenderScriptItems(ctx, w, script)
+		})
+		for i := 0; i < 2; i++ {
+			w := new(strings.Builder)
+			if err := render(templ.Join(items, children("<a>"), items), w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := `<style type="text/css">.name{color:red;}</style><script type="text/javascript">function name(){}</script><a>`
+			if diff := cmp.Diff(expected, w.String()); diff != "" {
+				t.Errorf("render %d: %s", i, diff)
+			}
+		}
+	})
+}
+
+func TestRenderFragment(t *testing.T) {
+	text := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+	// fragment renders c as the children of the fragment, as @templ.Fragment(name) { ... } does.
+	fragment := func(name string, c templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return templ.Fragment(name).Render(templ.WithChildren(ctx, c), w)
+		})
+	}
+	page := templ.Join(
+		text("<h1>"),
+		fragment("a", templ.Join(text("<a>"), fragment("b", text("<b>")))),
+		fragment("c", text("<c>")),
+	)
+	t.Run("components are rendered in full without RenderFragment", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := page.Render(context.Background(), w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<h1><a><b><c>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("only the fragments are rendered", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := templ.RenderFragment(context.Background(), w, page, "c", "b"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<b><c>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("CSS rendered outside the fragments is rendered within them", func(t *testing.T) {
+		red := templ.ComponentCSSClass{ID: "red", Class: templ.SafeCSS(".red{color:red;}")}
+		css := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return templ.RenderCSSItems(ctx, w, red)
+		})
+		page := templ.Join(css, fragment("a", templ.Join(css, text("<a>"), css)))
+		w := new(strings.Builder)
+		if err := templ.RenderFragment(context.Background(), w, page, "a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<style type=\"text/css\">.red{color:red;}</style><a>", w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("errors are returned", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		page := templ.Join(templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return errFailed
+		}), fragment("a", text("<a>")))
+		if err := templ.RenderFragment(context.Background(), io.Discard, page, "a"); !errors.Is(err, errFailed) {
+			t.Errorf("expected the error to be returned, got %v", err)
+		}
+	})
+	t.Run("handlers can render fragments", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		templ.Handler(page, templ.WithFragment("a")).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if diff := cmp.Diff("<a><b>", w.Body.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestReleaseBuffer(t *testing.T) {
+	t.Run("released buffers are reset", func(t *testing.T) {
+		b := templ.GetBuffer()
+		b.WriteString("content")
+		templ.ReleaseBuffer(b)
+		if b.Len() != 0 {
+			t.Errorf("expected the buffer to be reset, got %q", b.String())
+		}
+	})
+	t.Run("large buffers are not returned to the pool", func(t *testing.T) {
+		b := templ.GetBuffer()
+		b.Grow(1024 * 1024)
+		templ.ReleaseBuffer(b)
+		for i := 0; i < 10; i++ {
+			if pooled := templ.GetBuffer(); pooled.Cap() >= 1024*1024 {
+				t.Fatalf("expected the large buffer to be dropped, got a buffer with a capacity of %d", pooled.Cap())
+			}
+		}
+	})
+}
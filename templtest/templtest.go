@@ -0,0 +1,90 @@
+// Package templtest provides helpers for testing templ components: rendering them to a
+// string, parsing the output for structural assertions, and comparing the output with
+// golden files.
+//
+//	func TestHeader(t *testing.T) {
+//		doc := templtest.RenderToDocument(t, header("Home"))
+//		if title := doc.Find("h1").Text(); title != "Home" {
+//			t.Errorf("expected the title to be rendered, got %q", title)
+//		}
+//		templtest.Golden(t, "header", header("Home"))
+//	}
+package templtest
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/a-h/htmlformat"
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+// update is set with go test -templtest.update, to write the golden files instead of
+// comparing them. The flag is namespaced, so that it doesn't clash with the -update flag
+// that many test packages define for their own golden files.
+var update = flag.Bool("templtest.update", false, "Set to true to write the golden files used by templtest.Golden, instead of comparing them.")
+
+// Render renders the component, and returns its HTML. The test fails if the component
+// returns an error.
+func Render(t testing.TB, c templ.Component) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := c.Render(context.Background(), &sb); err != nil {
+		t.Fatalf("failed to render component: %v", err)
+	}
+	return sb.String()
+}
+
+// RenderToDocument renders the component, and parses the HTML, so that its structure can
+// be checked with CSS selectors. Fragments are parsed into the body of a document.
+func RenderToDocument(t testing.TB, c templ.Component) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(Render(t, c)))
+	if err != nil {
+		t.Fatalf("failed to parse rendered HTML: %v", err)
+	}
+	return doc
+}
+
+// Golden renders the component, and compares the HTML with the golden file
+// testdata/<name>.golden.html. Whitespace that isn't significant, e.g. indentation and
+// line breaks between elements, is ignored. Run the tests with -templtest.update, or the
+// test package's own -update flag, to write the golden files.
+func Golden(t testing.TB, name string, c templ.Component) {
+	t.Helper()
+	actual := Normalize(t, Render(t, c))
+	fileName := filepath.Join("testdata", name+".golden.html")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create directory for golden file: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(actual), 0644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run the test with -templtest.update to create it: %v", err)
+	}
+	if diff := cmp.Diff(Normalize(t, string(expected)), actual); diff != "" {
+		t.Errorf("%s: the rendered HTML doesn't match, run the test with -templtest.update to update it (-want +got):\n%s", fileName, diff)
+	}
+}
+
+// Normalize formats the HTML, so that HTML that only differs in whitespace that isn't
+// significant is the same.
+func Normalize(t testing.TB, html string) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := htmlformat.Fragment(&sb, strings.NewReader(html)); err != nil {
+		t.Fatalf("failed to format HTML: %v", err)
+	}
+	return sb.String()
+}
@@ -0,0 +1,142 @@
+package templtest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// userUpdate is the -update flag that test packages often define for their own golden files.
+// Defining it checks that it doesn't clash with the flag defined by the package.
+var userUpdate = flag.Bool("update", false, "Update the golden files.")
+
+func raw(html string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, html)
+		return err
+	})
+}
+
+// recorder is a testing.TB that records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failed bool
+	fatal  bool
+	msg    string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+	r.msg = format
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failed, r.fatal = true, true
+	r.msg = format
+	// Stop the helper like t.Fatalf does, without stopping the test.
+	panic(r)
+}
+
+func run(t *testing.T, f func(tb testing.TB)) (r *recorder) {
+	r = &recorder{TB: t}
+	defer func() {
+		if p := recover(); p != nil && p != r {
+			panic(p)
+		}
+	}()
+	f(r)
+	return r
+}
+
+func TestRender(t *testing.T) {
+	if actual := Render(t, raw("<p>Hello</p>")); actual != "<p>Hello</p>" {
+		t.Errorf("unexpected output: %q", actual)
+	}
+	r := run(t, func(tb testing.TB) {
+		Render(tb, templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return errors.New("render failed")
+		}))
+	})
+	if !r.fatal {
+		t.Error("expected a render error to fail the test")
+	}
+}
+
+func TestRenderToDocument(t *testing.T) {
+	doc := RenderToDocument(t, raw(`<ul><li class="selected">A</li><li>B</li></ul>`))
+	if count := doc.Find("body > ul > li").Length(); count != 2 {
+		t.Errorf("expected 2 items, got %d", count)
+	}
+	if text := doc.Find("li.selected").Text(); text != "A" {
+		t.Errorf("expected the selected item to be A, got %q", text)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Run("whitespace between elements is ignored", func(t *testing.T) {
+		Golden(t, "list", raw("<ul><li>A</li>\n<li>B</li></ul>"))
+	})
+	t.Run("differences are reported", func(t *testing.T) {
+		r := run(t, func(tb testing.TB) {
+			Golden(tb, "list", raw("<ul><li>A</li><li>C</li></ul>"))
+		})
+		if !r.failed || r.fatal {
+			t.Errorf("expected the test to fail with a diff, got failed=%v, fatal=%v", r.failed, r.fatal)
+		}
+	})
+	t.Run("missing golden files are reported", func(t *testing.T) {
+		r := run(t, func(tb testing.TB) {
+			Golden(tb, "missing", raw("<p></p>"))
+		})
+		if !r.fatal || !strings.Contains(r.msg, "-templtest.update") {
+			t.Errorf("expected the test to fail, suggesting -templtest.update, got %q", r.msg)
+		}
+	})
+	t.Run("golden files are written with -templtest.update", func(t *testing.T) {
+		testGoldenFilesAreWritten(t, update)
+	})
+	t.Run("golden files are not written with the test package's -update flag", func(t *testing.T) {
+		*userUpdate = true
+		defer func() { *userUpdate = false }()
+		r := run(t, func(tb testing.TB) {
+			Golden(tb, "missing", raw("<p></p>"))
+		})
+		if !r.fatal {
+			t.Error("expected the missing golden file to be reported, not written")
+		}
+		if _, err := os.Stat(filepath.Join("testdata", "missing.golden.html")); err == nil {
+			t.Error("expected the golden file not to be written")
+		}
+	})
+}
+
+func testGoldenFilesAreWritten(t *testing.T, updateFlag *bool) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+	*updateFlag = true
+	defer func() { *updateFlag = false }()
+
+	Golden(t, "page", raw("<main><p>Text</p></main>"))
+
+	*updateFlag = false
+	if _, err := os.Stat(filepath.Join(dir, "testdata", "page.golden.html")); err != nil {
+		t.Fatalf("expected the golden file to be written: %v", err)
+	}
+	Golden(t, "page", raw("<main>\n<p>Text</p>\n</main>"))
+}
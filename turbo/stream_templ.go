@@ -1,4 +1,6 @@
 // Code generated by templ@(devel) DO NOT EDIT.
+// templ: version: (devel)
+// templ: source hash: 7343eb9ecdaa4d24099b358cc15a4c0a16d6152912a158b21f0345c27ffd9cd6
 
 package turbo
 
@@ -9,89 +11,117 @@ import "context"
 import "io"
 import "bytes"
 
+//line stream.templ:3
 func actionTemplate(action string, target string) templ.Component {
+//line stream_templ.go:17
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "turbo.actionTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_1 := templ.GetChildren(ctx)
 		if var_1 == nil {
 			var_1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<turbo-stream action=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(action))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" target=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(target))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"><template>")
-		if err != nil {
-			return err
+		if !templSkip {
+			_, err = templBuffer.WriteString("<turbo-stream action=\"")
+			if err != nil {
+				return err
+			}
+//line stream.templ:4
+			_, err = templBuffer.WriteString(templ.EscapeString(action))
+//line stream_templ.go:42
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" target=\"")
+			if err != nil {
+				return err
+			}
+//line stream.templ:4
+			_, err = templBuffer.WriteString(templ.EscapeString(target))
+//line stream_templ.go:52
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"><template>")
+			if err != nil {
+				return err
+			}
 		}
 		err = var_1.Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</template></turbo-stream>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("</template></turbo-stream>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})
 }
 
+//line stream.templ:11
 func removeTemplate(action string, target string) templ.Component {
+//line stream_templ.go:80
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if templTraceEnd := templ.TraceRender(ctx, "turbo.removeTemplate"); templTraceEnd != nil {
+			defer func() { templTraceEnd(err) }()
+		}
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
 			templBuffer = templ.GetBuffer()
 			defer templ.ReleaseBuffer(templBuffer)
+			ctx = templ.WithFlushTarget(ctx, templBuffer, w)
 		}
+		templSkip := templ.IsOutputSkipped(ctx)
 		ctx = templ.InitializeContext(ctx)
 		var_2 := templ.GetChildren(ctx)
 		if var_2 == nil {
 			var_2 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, err = templBuffer.WriteString("<turbo-stream action=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(action))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\" target=\"")
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString(templ.EscapeString(target))
-		if err != nil {
-			return err
-		}
-		_, err = templBuffer.WriteString("\"></turbo-stream>")
-		if err != nil {
-			return err
-		}
-		if !templIsBuffer {
-			_, err = templBuffer.WriteTo(w)
+		if !templSkip {
+			_, err = templBuffer.WriteString("<turbo-stream action=\"")
+			if err != nil {
+				return err
+			}
+//line stream.templ:12
+			_, err = templBuffer.WriteString(templ.EscapeString(action))
+//line stream_templ.go:105
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\" target=\"")
+			if err != nil {
+				return err
+			}
+//line stream.templ:12
+			_, err = templBuffer.WriteString(templ.EscapeString(target))
+//line stream_templ.go:115
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"></turbo-stream>")
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = templBuffer.WriteTo(w)
+			}
 		}
 		return err
 	})